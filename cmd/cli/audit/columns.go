@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+var eventColumns = []output.TableColumn[models.AuditEvent]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "time"},
+		Value:        func(e models.AuditEvent) string { return time.Unix(0, e.Time).UTC().Format(time.DateTime) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "principal"},
+		Value:        func(e models.AuditEvent) string { return e.Principal },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "action"},
+		Value:        func(e models.AuditEvent) string { return e.Action },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "target"},
+		Value:        func(e models.AuditEvent) string { return e.Target },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "outcome"},
+		Value: func(e models.AuditEvent) string {
+			if e.StatusCode == 0 {
+				return string(e.Outcome)
+			}
+			return string(e.Outcome) + " (" + strconv.Itoa(e.StatusCode) + ")"
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "request id"},
+		Value:        func(e models.AuditEvent) string { return e.RequestID },
+	},
+}
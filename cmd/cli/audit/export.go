@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var exportExample = templates.Examples(i18n.T(`
+		# Export the audit log of the last 30 days as JSON lines
+		bacalhau audit export --after 720h --output-file audit.jsonl`))
+
+// ExportOptions is a struct to support audit export command
+type ExportOptions struct {
+	FilterOptions
+	OutputFile string
+}
+
+func NewExportCmd() *cobra.Command {
+	o := &ExportOptions{}
+	exportCmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export the events of the audit log as JSON lines, one event per line.",
+		Example: exportExample,
+		Args:    cobra.NoArgs,
+		RunE:    o.run,
+	}
+	exportCmd.Flags().AddFlagSet(filterFlags(&o.FilterOptions))
+	exportCmd.Flags().StringVar(&o.OutputFile, "output-file", o.OutputFile,
+		"File to write the events to. Defaults to the standard output")
+	return exportCmd
+}
+
+func (o *ExportOptions) run(cmd *cobra.Command, _ []string) error {
+	request, err := o.request()
+	if err != nil {
+		return err
+	}
+	response, err := util.GetAPIClientV2(cmd).Audit().List(cmd.Context(), request)
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	var out io.Writer = cmd.OutOrStdout()
+	if o.OutputFile != "" {
+		file, err := os.Create(o.OutputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err = audit.WriteJSONLines(out, response.Events); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// FilterOptions selects the events of the audit log to query
+type FilterOptions struct {
+	Principal string
+	Action    string
+	Target    string
+	After     string
+	Before    string
+	Limit     uint32
+}
+
+func filterFlags(options *FilterOptions) *pflag.FlagSet {
+	flagset := pflag.NewFlagSet("Audit filters", pflag.ContinueOnError)
+	flagset.StringVar(&options.Principal, "principal", options.Principal,
+		"Only include events of this principal, e.g. apikey:github-actions or anonymous.")
+	flagset.StringVar(&options.Action, "action", options.Action,
+		"Only include events of this action, e.g. node.approve or \"DELETE /api/v1/orchestrator/jobs/:id\".")
+	flagset.StringVar(&options.Target, "target", options.Target,
+		"Only include events on this target, e.g. the ID of a job or a node.")
+	flagset.StringVar(&options.After, "after", options.After,
+		"Only include events recorded at or after this time. Either a date, an RFC3339 timestamp or a duration ago, e.g. 24h.")
+	flagset.StringVar(&options.Before, "before", options.Before,
+		"Only include events recorded before this time. Either a date, an RFC3339 timestamp or a duration ago, e.g. 24h.")
+	flagset.Uint32Var(&options.Limit, "limit", options.Limit, "Only include the most recent events")
+	return flagset
+}
+
+// request returns the request listing the events selected by the options
+func (o *FilterOptions) request() (*apimodels.ListAuditEventsRequest, error) {
+	after, err := util.ParseTimeFilter(o.After)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --after: %w", err)
+	}
+	before, err := util.ParseTimeFilter(o.Before)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --before: %w", err)
+	}
+	return &apimodels.ListAuditEventsRequest{
+		BaseListRequest: apimodels.BaseListRequest{Limit: o.Limit},
+		Principal:       o.Principal,
+		Action:          o.Action,
+		Target:          o.Target,
+		After:           after,
+		Before:          before,
+	}, nil
+}
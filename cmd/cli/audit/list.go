@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var listExample = templates.Examples(i18n.T(`
+		# List the most recent events of the audit log
+		bacalhau audit list --limit 20
+
+		# List who approved or rejected a node
+		bacalhau audit list --target n-e9a0b9c4 --after 2024-01-01`))
+
+// ListOptions is a struct to support audit list command
+type ListOptions struct {
+	output.OutputOptions
+	FilterOptions
+}
+
+// NewListOptions returns initialized Options
+func NewListOptions() *ListOptions {
+	return &ListOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewListCmd() *cobra.Command {
+	o := NewListOptions()
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the events of the audit log, in the order they were recorded.",
+		Example: listExample,
+		Args:    cobra.NoArgs,
+		RunE:    o.run,
+	}
+	listCmd.Flags().AddFlagSet(filterFlags(&o.FilterOptions))
+	listCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return listCmd
+}
+
+func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
+	request, err := o.request()
+	if err != nil {
+		return err
+	}
+	response, err := util.GetAPIClientV2(cmd).Audit().List(cmd.Context(), request)
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if err = output.Output(cmd, eventColumns, o.OutputOptions, response.Events); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "audit",
+		Short:              "Commands to query the audit log of mutating API requests and node actions on the requester.",
+		PersistentPreRunE:  hook.AfterParentPreRunHook(hook.RemoteCmdPreRunHooks),
+		PersistentPostRunE: hook.AfterParentPostRunHook(hook.RemoteCmdPostRunHooks),
+	}
+
+	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewExportCmd())
+	return cmd
+}
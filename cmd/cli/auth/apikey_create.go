@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// CreateAPIKeyOptions is a struct to support apikey create command
+type CreateAPIKeyOptions struct {
+	Namespaces  []string
+	Permissions []string
+	ExpiresIn   time.Duration
+}
+
+func NewCreateAPIKeyCmd() *cobra.Command {
+	o := &CreateAPIKeyOptions{
+		Namespaces:  []string{models.DefaultNamespace},
+		Permissions: []string{string(models.APIKeyPermissionRead)},
+	}
+	createCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Issue a new API key scoped to namespaces and permissions.",
+		Long: `Issue a new API key scoped to namespaces and permissions.
+The key can only grant the permissions that your own access token grants in its namespaces.
+The key is printed to stdout, and cannot be shown again as the requester only stores its hash.
+Clients authenticate with the key by setting the BACALHAU_API_KEY environment variable.`,
+		Example: `  # Issue a key that can submit and read jobs of the ci namespace for 90 days
+  bacalhau auth apikey create github-actions --namespace ci --permission read,write --expires-in 2160h
+
+  # Issue a key that can read the jobs of all namespaces
+  bacalhau auth apikey create dashboard --namespace '*'`,
+		Args: cobra.ExactArgs(1),
+		RunE: o.run,
+	}
+	createCmd.Flags().StringSliceVar(&o.Namespaces, "namespace", o.Namespaces,
+		`Namespaces the key grants access to, or '*' for all namespaces`)
+	createCmd.Flags().StringSliceVar(&o.Permissions, "permission", o.Permissions,
+		fmt.Sprintf("Permissions the key grants in its namespaces. One or more of %v", models.APIKeyPermissions()))
+	createCmd.Flags().DurationVar(&o.ExpiresIn, "expires-in", 0,
+		"How long the key is valid for. The key never expires if not set")
+	return createCmd
+}
+
+func (o *CreateAPIKeyOptions) run(cmd *cobra.Command, args []string) error {
+	request := &apimodels.CreateAPIKeyRequest{
+		Name:        args[0],
+		Namespaces:  o.Namespaces,
+		Permissions: lo.Map(o.Permissions, func(p string, _ int) models.APIKeyPermission { return models.APIKeyPermission(p) }),
+		ExpiresIn:   o.ExpiresIn,
+	}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	response, err := util.GetAPIClientV2(cmd).APIKeys().Create(cmd.Context(), request)
+	if err != nil {
+		return fmt.Errorf("could not create API key %s: %w", args[0], err)
+	}
+	cmd.PrintErrf("Created API key %s. Store it safely, as it cannot be shown again.\n", response.APIKey.Name)
+	cmd.Println(response.Key)
+	return nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// ListAPIKeysOptions is a struct to support apikey list command
+type ListAPIKeysOptions struct {
+	output.OutputOptions
+	cliflags.ListOptions
+}
+
+// NewListAPIKeysOptions returns initialized Options
+func NewListAPIKeysOptions() *ListAPIKeysOptions {
+	return &ListAPIKeysOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewListAPIKeysCmd() *cobra.Command {
+	o := NewListAPIKeysOptions()
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the API keys issued by the requester. Keys are never shown.",
+		Args:  cobra.NoArgs,
+		RunE:  o.run,
+	}
+	listCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
+	listCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return listCmd
+}
+
+func (o *ListAPIKeysOptions) run(cmd *cobra.Command, _ []string) error {
+	response, err := util.GetAPIClientV2(cmd).APIKeys().List(cmd.Context(), &apimodels.ListAPIKeysRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			Limit:     o.Limit,
+			NextToken: o.NextToken,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if err = output.Output(cmd, apiKeyColumns, o.OutputOptions, response.APIKeys); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
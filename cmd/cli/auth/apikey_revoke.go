@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+func NewRevokeAPIKeyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke [name]",
+		Short: "Revoke an API key, which is no longer accepted.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			_, err := util.GetAPIClientV2(cmd).APIKeys().Revoke(cmd.Context(), &apimodels.RevokeAPIKeyRequest{
+				Name: name,
+			})
+			if err != nil {
+				return fmt.Errorf("could not revoke API key %s: %w", name, err)
+			}
+			cmd.Printf("Revoked API key %s\n", name)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/samber/lo"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// formatTime formats a timestamp of an API key, which is zero when not set
+func formatTime(timestamp int64, unset string) string {
+	if timestamp == 0 {
+		return unset
+	}
+	return time.Unix(0, timestamp).UTC().Format(time.DateTime)
+}
+
+var apiKeyColumns = []output.TableColumn[*models.APIKey]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "name"},
+		Value:        func(k *models.APIKey) string { return k.Name },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespaces"},
+		Value:        func(k *models.APIKey) string { return strings.Join(k.Namespaces, ",") },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "permissions"},
+		Value: func(k *models.APIKey) string {
+			return strings.Join(lo.Map(k.Permissions, func(p models.APIKeyPermission, _ int) string { return string(p) }), ",")
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "created"},
+		Value:        func(k *models.APIKey) string { return formatTime(k.CreateTime, "") },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "expires"},
+		Value:        func(k *models.APIKey) string { return formatTime(k.ExpireTime, "never") },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "last used"},
+		Value:        func(k *models.APIKey) string { return formatTime(k.LastUsedTime, "never") },
+	},
+}
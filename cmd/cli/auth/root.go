@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "auth",
+		Short:              "Commands to manage authentication with the requester.",
+		PersistentPreRunE:  hook.AfterParentPreRunHook(hook.RemoteCmdPreRunHooks),
+		PersistentPostRunE: hook.AfterParentPostRunHook(hook.RemoteCmdPostRunHooks),
+	}
+
+	cmd.AddCommand(NewAPIKeyCmd())
+	return cmd
+}
+
+func NewAPIKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apikey",
+		Short: "Commands to manage the API keys of service accounts, such as CI pipelines.",
+	}
+
+	cmd.AddCommand(NewCreateAPIKeyCmd())
+	cmd.AddCommand(NewListAPIKeysCmd())
+	cmd.AddCommand(NewRevokeAPIKeyCmd())
+	return cmd
+}
@@ -101,7 +101,7 @@ func (o *DescribeOptions) run(cmd *cobra.Command, args []string) error {
 		history = response.History.History
 	}
 
-	o.printHeaderData(cmd, job)
+	o.printHeaderData(cmd, job, history)
 	o.printExecutionsSummary(cmd, executions)
 
 	jobHistory := lo.Filter(history, func(entry *models.JobHistory, _ int) bool {
@@ -111,7 +111,7 @@ func (o *DescribeOptions) run(cmd *cobra.Command, args []string) error {
 		util.Fatal(cmd, fmt.Errorf("failed to write job history: %w", err), 1)
 	}
 
-	if err = o.printExecutions(cmd, executions); err != nil {
+	if err = o.printExecutions(cmd, job, executions); err != nil {
 		return fmt.Errorf("failed to write job executions %s: %w", jobID, err)
 	}
 
@@ -129,7 +129,7 @@ func (o *DescribeOptions) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func (o *DescribeOptions) printHeaderData(cmd *cobra.Command, job *models.Job) {
+func (o *DescribeOptions) printHeaderData(cmd *cobra.Command, job *models.Job, history []*models.JobHistory) {
 	var headerData = []collections.Pair[string, any]{
 		{Left: "ID", Right: job.ID},
 		{Left: "Name", Right: job.Name},
@@ -141,6 +141,12 @@ func (o *DescribeOptions) printHeaderData(cmd *cobra.Command, job *models.Job) {
 	if job.Type == models.JobTypeBatch || job.Type == models.JobTypeService {
 		headerData = append(headerData, collections.NewPair[string, any]("Count", job.Count))
 	}
+	if job.IsArray() {
+		headerData = append(headerData, collections.NewPair[string, any]("Array Size", job.ArraySize()))
+	}
+	if job.IsScheduled() && job.Schedule != nil {
+		headerData = append(headerData, scheduleHeaderData(job, history)...)
+	}
 
 	// Additional data
 	headerData = append(headerData, []collections.Pair[string, any]{
@@ -152,6 +158,34 @@ func (o *DescribeOptions) printHeaderData(cmd *cobra.Command, job *models.Job) {
 	output.KeyValue(cmd, headerData)
 }
 
+// scheduleHeaderData returns the schedule of a scheduled job along with its last and next run times
+func scheduleHeaderData(job *models.Job, history []*models.JobHistory) []collections.Pair[string, any] {
+	lastRun := "-"
+	var lastRunTime time.Time
+	for _, entry := range history {
+		runID, ok := entry.Event.Details[models.DetailsKeyScheduledRunID]
+		if !ok || entry.Occurred().Before(lastRunTime) {
+			continue
+		}
+		lastRunTime = entry.Occurred()
+		lastRun = fmt.Sprintf("%s (%s)", lastRunTime.UTC().Format(time.DateTime), idgen.ShortUUID(runID))
+	}
+
+	nextRun := "-"
+	if !job.IsTerminal() {
+		if next, err := job.Schedule.Next(time.Now()); err == nil && !next.IsZero() {
+			nextRun = next.Format(time.DateTime)
+		}
+	}
+
+	return []collections.Pair[string, any]{
+		{Left: "Schedule", Right: job.Schedule.String()},
+		{Left: "Concurrency Policy", Right: job.Schedule.ConcurrencyPolicy},
+		{Left: "Last Run", Right: lastRun},
+		{Left: "Next Run", Right: nextRun},
+	}
+}
+
 func (o *DescribeOptions) printExecutionsSummary(cmd *cobra.Command, executions []*models.Execution) {
 	// Summary of executions
 	var summaryPairs []collections.Pair[string, any]
@@ -169,7 +203,7 @@ func (o *DescribeOptions) printExecutionsSummary(cmd *cobra.Command, executions
 	output.KeyValue(cmd, summaryPairs)
 }
 
-func (o *DescribeOptions) printExecutions(cmd *cobra.Command, executions []*models.Execution) error {
+func (o *DescribeOptions) printExecutions(cmd *cobra.Command, job *models.Job, executions []*models.Execution) error {
 	// Executions table
 	tableOptions := output.OutputOptions{
 		Format:  output.TableFormat,
@@ -185,6 +219,12 @@ func (o *DescribeOptions) printExecutions(cmd *cobra.Command, executions []*mode
 		executionColumnModifiedSince,
 		executionColumnComment,
 	}
+	if job.IsArray() {
+		executionCols = slices.Insert(executionCols, 1, executionColumnArrayIndex)
+	}
+	if job.IsLongRunning() {
+		executionCols = slices.Insert(executionCols, 1, executionColumnJobVersion)
+	}
 	output.Bold(cmd, "\nExecutions\n")
 	return output.Output(cmd, executionCols, tableOptions, executions)
 }
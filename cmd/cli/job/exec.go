@@ -0,0 +1,208 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	execShortDesc = templates.LongDesc(i18n.T(`
+		Execute a command inside a running job
+`))
+
+	execLongDesc = templates.LongDesc(i18n.T(`
+		Execute a command inside a running execution of a job, similarly to docker exec,
+		e.g. to debug a long-running service job without accessing the compute node.
+
+		The command runs in the latest running execution of the job, unless an execution is selected.
+		Only executions of engines that support it, such as docker, can execute commands.
+		Executing commands requires the exec permission on the namespace of the job.
+`))
+
+	execExample = templates.Examples(i18n.T(`
+		# List the files of the working directory of a running job
+		bacalhau job exec j-51225160-807e-48b8-88c9-28311c7899e1 -- ls -la
+
+		# Open an interactive shell inside a running job
+		bacalhau job exec -it j-51225160-807e-48b8-88c9-28311c7899e1 -- sh
+
+		# Execute a command inside a specific execution of a job
+		bacalhau job exec --execution-id e-3b2cd5e5 j-51225160 -- cat /etc/hostname
+`))
+)
+
+// execInputBufferSize is the maximum size of the input sent to the command in a single message
+const execInputBufferSize = 32 * 1024
+
+type ExecOptions struct {
+	ExecutionID string
+	Stdin       bool
+	TTY         bool
+}
+
+func NewExecCmd() *cobra.Command {
+	o := &ExecOptions{}
+
+	execCmd := &cobra.Command{
+		Use:     "exec [id] -- [command] [args...]",
+		Short:   execShortDesc,
+		Long:    execLongDesc,
+		Example: execExample,
+		Args:    cobra.MinimumNArgs(2), //nolint:gomnd
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			exitCode, err := o.run(cmd, cmdArgs)
+			if err != nil {
+				return err
+			}
+			if exitCode != 0 {
+				// the command already printed why it failed
+				util.Fatal(cmd, errors.New(""), exitCode)
+			}
+			return nil
+		},
+	}
+	// flags after the job ID belong to the command, even without the -- separator
+	execCmd.Flags().SetInterspersed(false)
+
+	execCmd.Flags().StringVarP(&o.ExecutionID, "execution-id", "e", "",
+		"Execute the command in a specific execution of the job.")
+	execCmd.Flags().BoolVarP(&o.Stdin, "stdin", "i", false,
+		"Pass the standard input to the command.")
+	execCmd.Flags().BoolVarP(&o.TTY, "tty", "t", false,
+		"Allocate a terminal for the command. Requires the standard input to be a terminal.")
+	return execCmd
+}
+
+func (o *ExecOptions) run(cmd *cobra.Command, cmdArgs []string) (int, error) {
+	jobID, command := cmdArgs[0], cmdArgs[1:]
+	// the separator is kept in the arguments as flags are not interspersed
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
+	if len(command) == 0 {
+		return 0, fmt.Errorf("missing command to execute")
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	api := util.GetAPIClientV2(cmd)
+
+	// the namespace of the job is required to be authorized to execute commands in it
+	response, err := api.Jobs().Get(ctx, &apimodels.GetJobRequest{
+		JobID: jobID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	request := &apimodels.ExecRequest{
+		BaseGetRequest: apimodels.BaseGetRequest{
+			BaseRequest: apimodels.BaseRequest{Namespace: response.Job.Namespace},
+		},
+		JobID:       response.Job.ID,
+		ExecutionID: o.ExecutionID,
+		Command:     command,
+		TTY:         o.TTY,
+		Stdin:       o.Stdin,
+	}
+
+	input := make(chan models.ExecInput)
+	if o.TTY {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return 0, fmt.Errorf("cannot allocate a terminal as the standard input is not a terminal")
+		}
+		if width, height, sizeErr := term.GetSize(fd); sizeErr == nil {
+			request.Width, request.Height = uint(width), uint(height)
+		}
+		if o.Stdin {
+			state, rawErr := term.MakeRaw(fd)
+			if rawErr != nil {
+				return 0, fmt.Errorf("failed to set the terminal in raw mode: %w", rawErr)
+			}
+			defer func() { _ = term.Restore(fd, state) }()
+		}
+		go forwardTerminalResize(ctx, fd, input)
+	}
+	if o.Stdin {
+		go forwardExecStdin(ctx, cmd.InOrStdin(), input)
+	}
+
+	outputs, err := api.Jobs().Exec(ctx, request, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute command in job %s: %w", request.JobID, err)
+	}
+	for output := range outputs {
+		if output.Err != nil {
+			return 0, output.Err
+		}
+		if output.Value.Exited {
+			return output.Value.ExitCode, nil
+		}
+		writer := cmd.OutOrStdout()
+		if output.Value.Type == models.ExecutionLogTypeSTDERR {
+			writer = cmd.ErrOrStderr()
+		}
+		if _, err = writer.Write(output.Value.Data); err != nil {
+			return 0, err
+		}
+	}
+	return 0, fmt.Errorf("connection closed before the command exited")
+}
+
+// forwardExecStdin sends the standard input to the command until it is closed
+func forwardExecStdin(ctx context.Context, stdin io.Reader, input chan<- models.ExecInput) {
+	buf := make([]byte, execInputBufferSize)
+	for {
+		n, err := stdin.Read(buf)
+		msg := models.ExecInput{Data: append([]byte(nil), buf[:n]...), EOF: err != nil}
+		if n > 0 || msg.EOF {
+			select {
+			case <-ctx.Done():
+				return
+			case input <- msg:
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// forwardTerminalResize sends the new size of the terminal to the command when it is resized
+func forwardTerminalResize(ctx context.Context, fd int, input chan<- models.ExecInput) {
+	if len(util.ResizeSignals) == 0 {
+		return
+	}
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, util.ResizeSignals...)
+	defer signal.Stop(resized)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resized:
+			width, height, err := term.GetSize(fd)
+			if err != nil {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case input <- models.ExecInput{Resize: &models.TerminalSize{Height: uint(height), Width: uint(width)}}:
+			}
+		}
+	}
+}
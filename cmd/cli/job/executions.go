@@ -96,6 +96,14 @@ var (
 		ColumnConfig: table.ColumnConfig{Name: "Rev.", WidthMax: 4, WidthMaxEnforcer: text.WrapText},
 		Value:        func(e *models.Execution) string { return strconv.FormatUint(e.Revision, 10) },
 	}
+	executionColumnArrayIndex = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "Index", WidthMax: 6, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return strconv.Itoa(e.ArrayIndex) },
+	}
+	executionColumnJobVersion = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "Version", WidthMax: 7, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return strconv.FormatUint(max(e.JobVersion, 1), 10) },
+	}
 	executionColumnState = output.TableColumn[*models.Execution]{
 		ColumnConfig: table.ColumnConfig{Name: "State", WidthMax: 17, WidthMaxEnforcer: text.WrapText},
 		Value:        func(e *models.Execution) string { return e.ComputeState.StateType.String() },
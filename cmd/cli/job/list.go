@@ -20,7 +20,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
 )
 
-var orderByFields = []string{"id", "created_at"}
+var orderByFields = []string{"id", "created_at", "modified_at"}
 
 var (
 	listShort = `List submitted jobs.`
@@ -34,7 +34,13 @@ var (
 		bacalhau job list
 
 		# List jobs and output as json
-		bacalhau job list --output json --pretty`))
+		bacalhau job list --output json --pretty
+
+		# List the failed docker jobs created in the last day
+		bacalhau job list --state failed --engine docker --created-after 24h
+
+		# List the jobs whose name starts with "nightly-" created in January 2024
+		bacalhau job list --name-prefix nightly- --created-after 2024-01-01 --created-before 2024-02-01`))
 
 	// defaultLabelFilter is the default label filter for the list command when
 	// no other labels are specified.
@@ -45,7 +51,13 @@ var (
 type ListOptions struct {
 	output.OutputOptions
 	cliflags.ListOptions
-	Labels string
+	Labels        string
+	States        []string
+	Types         []string
+	EngineTypes   []string
+	NamePrefix    string
+	CreatedAfter  string
+	CreatedBefore string
 }
 
 // NewListOptions returns initialized Options
@@ -73,6 +85,18 @@ func NewListCmd() *cobra.Command {
 
 	listCmd.Flags().StringVar(&o.Labels, "labels", o.Labels,
 		"Filter nodes by labels. See https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/ for more information.")
+	listCmd.Flags().StringSliceVar(&o.States, "state", o.States,
+		"Only list jobs in any of these states, e.g. running or failed.")
+	listCmd.Flags().StringSliceVar(&o.Types, "type", o.Types,
+		"Only list jobs of any of these types, e.g. batch or service.")
+	listCmd.Flags().StringSliceVar(&o.EngineTypes, "engine", o.EngineTypes,
+		"Only list jobs using any of these engines, e.g. docker or wasm.")
+	listCmd.Flags().StringVar(&o.NamePrefix, "name-prefix", o.NamePrefix,
+		"Only list jobs whose name starts with this prefix.")
+	listCmd.Flags().StringVar(&o.CreatedAfter, "created-after", o.CreatedAfter,
+		"Only list jobs created at or after this time. Either a date, an RFC3339 timestamp or a duration ago, e.g. 24h.")
+	listCmd.Flags().StringVar(&o.CreatedBefore, "created-before", o.CreatedBefore,
+		"Only list jobs created before this time. Either a date, an RFC3339 timestamp or a duration ago, e.g. 24h.")
 
 	listCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
 	listCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
@@ -124,6 +148,14 @@ func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
 			return fmt.Errorf("could not parse labels: %w", err)
 		}
 	}
+	createdAfter, err := util.ParseTimeFilter(o.CreatedAfter)
+	if err != nil {
+		return fmt.Errorf("invalid --created-after: %w", err)
+	}
+	createdBefore, err := util.ParseTimeFilter(o.CreatedBefore)
+	if err != nil {
+		return fmt.Errorf("invalid --created-before: %w", err)
+	}
 	response, err := util.GetAPIClientV2(cmd).Jobs().List(ctx, &apimodels.ListJobsRequest{
 		Labels: labelRequirements,
 		BaseListRequest: apimodels.BaseListRequest{
@@ -132,6 +164,12 @@ func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
 			OrderBy:   o.OrderBy,
 			Reverse:   o.Reverse,
 		},
+		States:        o.States,
+		Types:         o.Types,
+		EngineTypes:   o.EngineTypes,
+		NamePrefix:    o.NamePrefix,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
 	})
 	if err != nil {
 		return fmt.Errorf("failed request: %w", err)
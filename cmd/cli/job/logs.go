@@ -1,6 +1,8 @@
 package job
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"k8s.io/kubectl/pkg/util/i18n"
 
@@ -10,7 +12,15 @@ import (
 
 var (
 	logsShortDesc = templates.LongDesc(i18n.T(`
-		Follow logs from a currently executing job
+		Read and follow the logs of a job
+`))
+
+	logsLongDesc = templates.LongDesc(i18n.T(`
+		Read the logs of a job's execution, and optionally follow them while it runs.
+
+		Compute nodes persist the logs of executions, so the full logs of completed and failed
+		jobs can be read as long as they are retained by the compute node. If they are not
+		available, the output recorded in the execution is returned instead, which might be truncated.
 `))
 
 	logsExample = templates.Examples(i18n.T(`
@@ -22,6 +32,12 @@ var (
 
 		# Tail logs for a previously submitted job
 		bacalhau job logs j-51225160-807e-48b8-88c9-28311c7899e1 --tail
+
+		# Read the last 100 lines of the logs of a completed job
+		bacalhau job logs j-51225160-807e-48b8-88c9-28311c7899e1 --tail-lines 100
+
+		# Read the logs written in the last 10 minutes
+		bacalhau job logs j-51225160-807e-48b8-88c9-28311c7899e1 --since 10m
 `))
 )
 
@@ -29,6 +45,9 @@ type LogCommandOptions struct {
 	ExecutionID string
 	Follow      bool
 	Tail        bool
+	Offset      int
+	TailLines   int
+	Since       string
 }
 
 func NewLogCmd() *cobra.Command {
@@ -37,14 +56,22 @@ func NewLogCmd() *cobra.Command {
 	logsCmd := &cobra.Command{
 		Use:     "logs [id]",
 		Short:   logsShortDesc,
+		Long:    logsLongDesc,
 		Example: logsExample,
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			since, err := util.ParseTimeFilter(options.Since)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
 			opts := util.LogOptions{
 				JobID:       cmdArgs[0],
 				ExecutionID: options.ExecutionID,
 				Follow:      options.Follow,
 				Tail:        options.Tail,
+				Offset:      options.Offset,
+				TailLines:   options.TailLines,
+				Since:       since,
 			}
 			return util.Logs(cmd, opts)
 		},
@@ -64,5 +91,20 @@ func NewLogCmd() *cobra.Command {
 		&options.Tail, "tail", "t", false,
 		"Tail the logs from the end of the log stream.",
 	)
+
+	logsCmd.PersistentFlags().IntVar(
+		&options.Offset, "offset", 0,
+		"Skip the first N lines of the logs.",
+	)
+
+	logsCmd.PersistentFlags().IntVarP(
+		&options.TailLines, "tail-lines", "n", 0,
+		"Only read the last N lines of the logs.",
+	)
+
+	logsCmd.PersistentFlags().StringVar(
+		&options.Since, "since", "",
+		"Only read the logs written after this time, as a date, an RFC3339 timestamp or a duration ago (e.g. 10m).",
+	)
 	return logsCmd
 }
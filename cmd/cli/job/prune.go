@@ -0,0 +1,159 @@
+package job
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	pruneShort = `Remove terminal jobs that are no longer retained from the orchestrator.`
+
+	pruneLong = templates.LongDesc(i18n.T(`
+		Remove completed, failed and stopped jobs, along with their executions, evaluations
+		and history, that are no longer retained by the retention policies of the orchestrator.
+
+		By default, the retention policies configured on the orchestrator are used. A one-off
+		policy can be given instead with the --namespace, --state, --older-than and --keep-last flags.
+		Use --dry-run to list the jobs that would be removed without removing them.
+`))
+
+	pruneExample = templates.Examples(i18n.T(`
+		# List the jobs that the configured retention policies would remove
+		bacalhau job prune --dry-run
+
+		# Remove the jobs that are no longer retained by the configured retention policies
+		bacalhau job prune
+
+		# Remove the failed jobs of the default namespace that are older than a week
+		bacalhau job prune --namespace default --state failed --older-than 168h
+`))
+)
+
+// PruneOptions is a struct to support job prune command
+type PruneOptions struct {
+	output.OutputOptions
+	DryRun    bool
+	Namespace string
+	States    []string
+	OlderThan time.Duration
+	KeepLast  int
+}
+
+// NewPruneOptions returns initialized Options
+func NewPruneOptions() *PruneOptions {
+	return &PruneOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewPruneCmd() *cobra.Command {
+	o := NewPruneOptions()
+	pruneCmd := &cobra.Command{
+		Use:     "prune",
+		Short:   pruneShort,
+		Long:    pruneLong,
+		Example: pruneExample,
+		Args:    cobra.NoArgs,
+		RunE:    o.run,
+	}
+
+	pruneCmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun,
+		`List the jobs that would be removed without removing them.`)
+	pruneCmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace,
+		`Only remove jobs of this namespace. Overrides the configured retention policies.`)
+	pruneCmd.Flags().StringSliceVar(&o.States, "state", o.States,
+		`Only remove jobs in these terminal states. Overrides the configured retention policies.`)
+	pruneCmd.Flags().DurationVar(&o.OlderThan, "older-than", o.OlderThan,
+		`Only remove jobs that last changed state longer ago than this. Overrides the configured retention policies.`)
+	pruneCmd.Flags().IntVar(&o.KeepLast, "keep-last", o.KeepLast,
+		`Keep the most recent jobs of each namespace regardless of their age. Overrides the configured retention policies.`)
+	pruneCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return pruneCmd
+}
+
+var pruneColumns = []output.TableColumn[*models.PrunedJob]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Modified", WidthMax: 8, WidthMaxEnforcer: output.ShortenTime},
+		Value:        func(j *models.PrunedJob) string { return time.Unix(0, j.ModifyTime).Format(time.DateTime) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "ID", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(j *models.PrunedJob) string { return j.JobID },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Namespace", WidthMax: 20, WidthMaxEnforcer: text.WrapText},
+		Value:        func(j *models.PrunedJob) string { return j.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "State", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(j *models.PrunedJob) string { return j.State.String() },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Reason", WidthMax: 40, WidthMaxEnforcer: text.WrapText},
+		Value:        func(j *models.PrunedJob) string { return j.Reason },
+	},
+}
+
+// policy returns the one-off retention policy set by the flags, or nil if none was set
+func (o *PruneOptions) policy(cmd *cobra.Command) (*models.RetentionPolicy, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("namespace") && !flags.Changed("state") &&
+		!flags.Changed("older-than") && !flags.Changed("keep-last") {
+		return nil, nil
+	}
+	policy := &models.RetentionPolicy{
+		Namespace: o.Namespace,
+		MaxAge:    o.OlderThan,
+		KeepLast:  o.KeepLast,
+	}
+	for _, state := range o.States {
+		var stateType models.JobStateType
+		if err := stateType.UnmarshalText([]byte(state)); err != nil {
+			return nil, err
+		}
+		if stateType == models.JobStateTypeUndefined {
+			return nil, fmt.Errorf("unknown job state %q", state)
+		}
+		policy.States = append(policy.States, stateType)
+	}
+	if policy.RetainsForever() {
+		return nil, fmt.Errorf("either --older-than or --keep-last must be set")
+	}
+	return policy, nil
+}
+
+func (o *PruneOptions) run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	request := &apimodels.PruneJobsRequest{
+		DryRun: o.DryRun,
+	}
+	policy, err := o.policy(cmd)
+	if err != nil {
+		return err
+	}
+	if policy != nil {
+		request.Policies = []*models.RetentionPolicy{policy}
+	}
+
+	response, err := util.GetAPIClientV2(cmd).Jobs().Prune(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if err = output.Output(cmd, pruneColumns, o.OutputOptions, response.Jobs); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	rollbackShort = `Roll a service or daemon job back to a previous version.`
+
+	rollbackLong = templates.LongDesc(i18n.T(`
+		Roll a long-running job back to one of its previous versions.
+
+		A service or daemon job gets a new version every time it is submitted again with
+		the same name. Rolling back creates a new version of the job from the specification
+		of the given previous version, which replaces the executions of the job following
+		its update strategy. Use 'bacalhau job history' to find the versions of a job.
+`))
+
+	rollbackExample = templates.Examples(i18n.T(`
+		# Roll a job back to its second version
+		bacalhau job rollback j-51225160-807e-48b8-88c9-28311c7899e1 2
+
+		# Roll a job back to its first version, with a short ID.
+		bacalhau job rollback j-51225160 1
+`))
+)
+
+func NewRollbackCmd() *cobra.Command {
+	rollbackCmd := &cobra.Command{
+		Use:     "rollback [id] [version]",
+		Short:   rollbackShort,
+		Long:    rollbackLong,
+		Example: rollbackExample,
+		Args:    cobra.ExactArgs(2),
+		RunE:    rollback,
+	}
+	return rollbackCmd
+}
+
+func rollback(cmd *cobra.Command, cmdArgs []string) error {
+	ctx := cmd.Context()
+	version, err := strconv.ParseUint(cmdArgs[1], 10, 64)
+	if err != nil || version == 0 {
+		return fmt.Errorf("invalid job version %q: must be a positive integer", cmdArgs[1])
+	}
+
+	apiClient := util.GetAPIClientV2(cmd)
+	// resolve short job IDs to the full ID of the job
+	job, err := apiClient.Jobs().Get(ctx, &apimodels.GetJobRequest{
+		JobID: cmdArgs[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	response, err := apiClient.Jobs().Rollback(ctx, &apimodels.RollbackJobRequest{
+		JobID:   job.Job.ID,
+		Version: version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back job %s: %w", job.Job.ID, err)
+	}
+	cmd.Printf("Job %s rolled back to version %d as version %d, with evaluation ID: %s\n",
+		job.Job.ID, version, response.JobVersion, response.EvaluationID)
+	return nil
+}
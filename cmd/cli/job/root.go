@@ -14,10 +14,13 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewExecCmd())
 	cmd.AddCommand(NewExecutionCmd())
 	cmd.AddCommand(NewHistoryCmd())
 	cmd.AddCommand(NewListCmd())
 	cmd.AddCommand(NewLogCmd())
+	cmd.AddCommand(NewPruneCmd())
+	cmd.AddCommand(NewRollbackCmd())
 	cmd.AddCommand(NewRunCmd())
 	cmd.AddCommand(NewStopCmd())
 	return cmd
@@ -1,12 +1,15 @@
 package job
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/kubectl/pkg/util/i18n"
 
 	"github.com/bacalhau-project/bacalhau/cmd/util/output"
@@ -26,7 +29,9 @@ var (
 	runLong = templates.LongDesc(i18n.T(`
 		Run a job from a file or from stdin.
 
-		JSON and YAML formats are accepted.
+		JSON and YAML formats are accepted. A YAML file can hold multiple jobs
+		separated by '---', in which case the jobs are submitted in order and
+		can depend on earlier jobs in the file by name.
 	`))
 	//nolint:lll // Documentation
 	runExample = templates.Examples(i18n.T(`
@@ -35,6 +40,13 @@ var (
 
 		# Run a new job from an already executed job
 		bacalhau job describe 6e51df50 | bacalhau job run
+
+		# Run a workflow of jobs, where later jobs list earlier ones in their Dependencies
+		bacalhau job run ./workflow.yaml
+
+		# Run a job on a cron schedule, where nightly.yaml has Type 'scheduled' and a Schedule
+		# such as {Cron: "0 2 * * *", Timezone: "Europe/London", ConcurrencyPolicy: forbid}
+		bacalhau job run ./nightly.yaml
 		`))
 )
 
@@ -119,53 +131,105 @@ func (o *RunOptions) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Turns out the yaml parser supports both yaml & json (because json is a subset of yaml)
-	// so we can just use that
-	var j *models.Job
-	err = marshaller.YAMLUnmarshalWithMax(byteResult, &j)
-	if err != nil {
-		return fmt.Errorf("%s: %w", userstrings.JobSpecBad, err)
-	}
-
-	// Normalize and validate the job spec
-	j.Normalize()
-	err = j.ValidateSubmission()
+	jobs, err := parseJobs(byteResult)
 	if err != nil {
 		return fmt.Errorf("%s: %w", userstrings.JobSpecBad, err)
 	}
 
 	if o.RunTimeSettings.DryRun {
-		warnings := j.SanitizeSubmission()
-		if len(warnings) > 0 {
-			o.printWarnings(cmd, warnings)
-		}
-		outputOps := output.NonTabularOutputOptions{Format: output.YAMLFormat}
-		if err = output.OutputOneNonTabular(cmd, outputOps, j); err != nil {
-			return fmt.Errorf("failed to write job: %w", err)
+		for _, j := range jobs {
+			warnings := j.SanitizeSubmission()
+			if len(warnings) > 0 {
+				o.printWarnings(cmd, warnings)
+			}
+			outputOps := output.NonTabularOutputOptions{Format: output.YAMLFormat}
+			if err = output.OutputOneNonTabular(cmd, outputOps, j); err != nil {
+				return fmt.Errorf("failed to write job: %w", err)
+			}
 		}
 		return nil
 	}
 
-	// Submit the job
+	// Submit the jobs in order, pinning dependencies on jobs submitted earlier from
+	// the same file to their IDs, so that they don't resolve to older jobs with the same name
 	client := util.GetAPIClientV2(cmd)
-	resp, err := client.Jobs().Put(ctx, &apimodels.PutJobRequest{
-		Job: j,
-	})
-	if err != nil {
-		return fmt.Errorf("failed request: %w", err)
-	}
+	submitted := make(map[string]string, len(jobs))
+	var jobID string
+	for i, j := range jobs {
+		for _, dep := range j.Dependencies {
+			if id, ok := submitted[dep.Name]; ok && dep.JobID == "" {
+				dep.JobID = id
+			}
+		}
+
+		resp, err := client.Jobs().Put(ctx, &apimodels.PutJobRequest{
+			Job: j,
+		})
+		if err != nil {
+			return fmt.Errorf("failed request: %w", err)
+		}
+
+		if o.ShowWarnings && len(resp.Warnings) > 0 {
+			o.printWarnings(cmd, resp.Warnings)
+		}
 
-	if o.ShowWarnings && len(resp.Warnings) > 0 {
-		o.printWarnings(cmd, resp.Warnings)
+		if j.Name != "" {
+			submitted[j.Name] = resp.JobID
+		}
+		jobID = resp.JobID
+		if i < len(jobs)-1 {
+			cmd.Printf("Job %s successfully submitted. Job ID: %s\n", j.Name, resp.JobID)
+		}
 	}
 
-	if err := printer.PrintJobExecution(ctx, resp.JobID, cmd, o.RunTimeSettings, client); err != nil {
+	// Print the execution of the last job, which is the end of the workflow
+	if err := printer.PrintJobExecution(ctx, jobID, cmd, o.RunTimeSettings, client); err != nil {
 		return fmt.Errorf("failed to print job execution: %w", err)
 	}
 
 	return nil
 }
 
+// parseJobs parses one or more jobs from the input, where multiple jobs
+// are separated as YAML documents. Each job is normalized and validated.
+func parseJobs(data []byte) ([]*models.Job, error) {
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var jobs []*models.Job
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		// Turns out the yaml parser supports both yaml & json (because json is a subset of yaml)
+		// so we can just use that
+		var j *models.Job
+		if err = marshaller.YAMLUnmarshalWithMax(doc, &j); err != nil {
+			return nil, err
+		}
+		if j == nil {
+			continue
+		}
+
+		// Normalize and validate the job spec
+		j.Normalize()
+		if err = j.ValidateSubmission(); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if len(jobs) == 0 {
+		return nil, errors.New("no job found")
+	}
+	return jobs, nil
+}
+
 func (o *RunOptions) printWarnings(cmd *cobra.Command, warnings []string) {
 	cmd.Println("Warnings:")
 	for _, warning := range warnings {
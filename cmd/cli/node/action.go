@@ -2,6 +2,7 @@ package node
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/cmd/util"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
@@ -9,8 +10,9 @@ import (
 )
 
 type NodeActionCmd struct {
-	action  string
-	message string
+	action      string
+	message     string
+	gracePeriod time.Duration
 }
 
 func NewActionCmd(action apimodels.NodeAction) *cobra.Command {
@@ -27,6 +29,11 @@ func NewActionCmd(action apimodels.NodeAction) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&actionCmd.message, "message", "m", "", "Message to include with the action")
+	if action == apimodels.NodeActionDrain {
+		cmd.Flags().DurationVar(&actionCmd.gracePeriod, "grace-period", 0,
+			"How long executions of service and daemon jobs are given to move to other nodes before they are stopped. "+
+				"Defaults to the drain grace period configured on the requester")
+	}
 	return cmd
 }
 
@@ -35,11 +42,18 @@ func (n *NodeActionCmd) run(cmd *cobra.Command, args []string) error {
 
 	nodeID := args[0]
 
-	response, err := util.GetAPIClientV2(cmd).Nodes().Put(ctx, &apimodels.PutNodeRequest{
+	request := &apimodels.PutNodeRequest{
 		NodeID:  nodeID,
 		Action:  n.action,
 		Message: n.message,
-	})
+	}
+	if cmd.Flags().Changed("grace-period") {
+		// sub-second grace periods are rounded up, so that only 0 stops executions immediately
+		gracePeriod := int64((n.gracePeriod + time.Second - 1) / time.Second)
+		request.GracePeriod = &gracePeriod
+	}
+
+	response, err := util.GetAPIClientV2(cmd).Nodes().Put(ctx, request)
 	if err != nil {
 		util.Fatal(cmd, fmt.Errorf("could not %s node %s: %w", n.action, nodeID, err), 1)
 	}
@@ -26,5 +26,10 @@ func NewCmd() *cobra.Command {
 	// Reject Action
 	cmd.AddCommand(NewActionCmd(apimodels.NodeActionDelete))
 
+	// Cordon, Uncordon and Drain Actions
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionCordon))
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionUncordon))
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionDrain))
+
 	return cmd
 }
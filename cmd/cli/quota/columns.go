@@ -0,0 +1,61 @@
+package quota
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// limit formats a quota limit, where zero means there is no limit
+func limit[T comparable](value T, format func(T) string) string {
+	var zero T
+	if value == zero {
+		return "-"
+	}
+	return format(value)
+}
+
+func formatBytes(value uint64) string { return datasize.ByteSize(value).HR() }
+
+var quotaColumns = []output.TableColumn[*models.NamespaceQuota]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(q *models.NamespaceQuota) string { return q.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "jobs"},
+		Value:        func(q *models.NamespaceQuota) string { return limit(q.MaxJobs, strconv.Itoa) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "cpu"},
+		Value: func(q *models.NamespaceQuota) string {
+			return limit(q.Resources.CPU, func(v float64) string { return fmt.Sprintf("%.1f", v) })
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "memory"},
+		Value:        func(q *models.NamespaceQuota) string { return limit(q.Resources.Memory, formatBytes) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "disk"},
+		Value:        func(q *models.NamespaceQuota) string { return limit(q.Resources.Disk, formatBytes) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "gpu"},
+		Value: func(q *models.NamespaceQuota) string {
+			return limit(q.Resources.GPU, func(v uint64) string { return strconv.FormatUint(v, 10) })
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "updated"},
+		Value: func(q *models.NamespaceQuota) string {
+			return time.Unix(0, q.ModifyTime).UTC().Format(time.DateTime)
+		},
+	},
+}
@@ -0,0 +1,29 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+func NewDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [namespace]",
+		Short: "Remove the quota of a namespace, lifting all its limits.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace := args[0]
+			_, err := util.GetAPIClientV2(cmd).Quotas().Delete(cmd.Context(), &apimodels.DeleteQuotaRequest{
+				QuotaNamespace: namespace,
+			})
+			if err != nil {
+				return fmt.Errorf("could not delete quota of namespace %s: %w", namespace, err)
+			}
+			cmd.Printf("Deleted quota of namespace %s\n", namespace)
+			return nil
+		},
+	}
+}
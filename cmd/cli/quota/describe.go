@@ -0,0 +1,52 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// DescribeOptions is a struct to support quota describe command
+type DescribeOptions struct {
+	OutputOpts output.NonTabularOutputOptions
+}
+
+// NewDescribeOptions returns initialized Options
+func NewDescribeOptions() *DescribeOptions {
+	return &DescribeOptions{
+		OutputOpts: output.NonTabularOutputOptions{Format: output.YAMLFormat},
+	}
+}
+
+func NewDescribeCmd() *cobra.Command {
+	o := NewDescribeOptions()
+	quotaCmd := &cobra.Command{
+		Use:   "describe [namespace]",
+		Short: "Get the quota of a namespace and its current usage.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.run,
+	}
+	quotaCmd.Flags().AddFlagSet(cliflags.OutputNonTabularFormatFlags(&o.OutputOpts))
+	return quotaCmd
+}
+
+func (o *DescribeOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	namespace := args[0]
+	response, err := util.GetAPIClientV2(cmd).Quotas().Get(ctx, &apimodels.GetQuotaRequest{
+		QuotaNamespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("could not get quota of namespace %s: %w", namespace, err)
+	}
+
+	if err = output.OutputOneNonTabular(cmd, o.OutputOpts, response); err != nil {
+		return fmt.Errorf("failed to write quota of namespace %s: %w", namespace, err)
+	}
+	return nil
+}
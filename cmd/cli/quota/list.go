@@ -0,0 +1,56 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// ListOptions is a struct to support quota list command
+type ListOptions struct {
+	output.OutputOptions
+	cliflags.ListOptions
+}
+
+// NewListOptions returns initialized Options
+func NewListOptions() *ListOptions {
+	return &ListOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewListCmd() *cobra.Command {
+	o := NewListOptions()
+	quotaCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the quotas of all namespaces.",
+		Args:  cobra.NoArgs,
+		RunE:  o.run,
+	}
+	quotaCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
+	quotaCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return quotaCmd
+}
+
+func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	response, err := util.GetAPIClientV2(cmd).Quotas().List(ctx, &apimodels.ListQuotasRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			Limit:     o.Limit,
+			NextToken: o.NextToken,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if err = output.Output(cmd, quotaColumns, o.OutputOptions, response.Quotas); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package quota
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "quota",
+		Short:              "Commands to view and set the resource quotas of namespaces.",
+		PersistentPreRunE:  hook.AfterParentPreRunHook(hook.RemoteCmdPreRunHooks),
+		PersistentPostRunE: hook.AfterParentPostRunHook(hook.RemoteCmdPostRunHooks),
+	}
+
+	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewSetCmd())
+	cmd.AddCommand(NewDeleteCmd())
+	return cmd
+}
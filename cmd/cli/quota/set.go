@@ -0,0 +1,63 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// SetOptions is a struct to support quota set command
+type SetOptions struct {
+	MaxJobs   int
+	Resources models.ResourcesConfig
+}
+
+func NewSetCmd() *cobra.Command {
+	o := &SetOptions{}
+	quotaCmd := &cobra.Command{
+		Use:   "set [namespace]",
+		Short: "Create or replace the quota of a namespace.",
+		Long: `Create or replace the quota of a namespace.
+Limits that are not set are unlimited. Jobs that would exceed the quota are accepted,
+but kept pending until enough active jobs of the namespace have finished.`,
+		Example: `  # Allow at most 10 active jobs using up to 8 GPUs in the ml namespace
+  bacalhau quota set ml --max-jobs 10 --gpu 8`,
+		Args: cobra.ExactArgs(1),
+		RunE: o.run,
+	}
+	quotaCmd.Flags().IntVar(&o.MaxJobs, "max-jobs", 0, "Maximum number of active jobs")
+	quotaCmd.Flags().StringVar(&o.Resources.CPU, "cpu", "", "Maximum total CPU units of active jobs (e.g. 500m, 2)")
+	quotaCmd.Flags().StringVar(&o.Resources.Memory, "memory", "", "Maximum total memory of active jobs (e.g. 500Mb, 2Gb)")
+	quotaCmd.Flags().StringVar(&o.Resources.Disk, "disk", "", "Maximum total disk of active jobs (e.g. 500Mb, 2Gb)")
+	quotaCmd.Flags().StringVar(&o.Resources.GPU, "gpu", "", "Maximum total GPUs of active jobs")
+	return quotaCmd
+}
+
+func (o *SetOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	resources, err := o.Resources.ToResources()
+	if err != nil {
+		return err
+	}
+	quota := &models.NamespaceQuota{
+		Namespace: args[0],
+		MaxJobs:   o.MaxJobs,
+		Resources: *resources,
+	}
+	if err = quota.Validate(); err != nil {
+		return err
+	}
+
+	response, err := util.GetAPIClientV2(cmd).Quotas().Put(ctx, &apimodels.PutQuotaRequest{
+		Quota: quota,
+	})
+	if err != nil {
+		return fmt.Errorf("could not set quota of namespace %s: %w", quota.Namespace, err)
+	}
+	cmd.Printf("Set quota of namespace %s\n", response.Quota.Namespace)
+	return nil
+}
@@ -11,9 +11,13 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bacalhau-project/bacalhau/cmd/cli/agent"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/audit"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/auth"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/exec"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/job"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/node"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/quota"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/secret"
 
 	"github.com/bacalhau-project/bacalhau/cmd/cli/cancel"
 	configcli "github.com/bacalhau-project/bacalhau/cmd/cli/config"
@@ -159,6 +163,18 @@ func NewRootCmd() *cobra.Command {
 	// Register nodes subcommands
 	RootCmd.AddCommand(node.NewCmd())
 
+	// Register namespace quota subcommands
+	RootCmd.AddCommand(quota.NewCmd())
+
+	// Register namespace secret subcommands
+	RootCmd.AddCommand(secret.NewCmd())
+
+	// Register authentication subcommands
+	RootCmd.AddCommand(auth.NewCmd())
+
+	// Register audit log subcommands
+	RootCmd.AddCommand(audit.NewCmd())
+
 	// Register exec commands
 	RootCmd.AddCommand(exec.NewCmd())
 
@@ -0,0 +1,33 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+var secretColumns = []output.TableColumn[*models.Secret]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(s *models.Secret) string { return s.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "name"},
+		Value:        func(s *models.Secret) string { return s.Name },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "created"},
+		Value: func(s *models.Secret) string {
+			return time.Unix(0, s.CreateTime).UTC().Format(time.DateTime)
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "updated"},
+		Value: func(s *models.Secret) string {
+			return time.Unix(0, s.ModifyTime).UTC().Format(time.DateTime)
+		},
+	},
+}
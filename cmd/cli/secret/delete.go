@@ -0,0 +1,34 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+func NewDeleteCmd() *cobra.Command {
+	var namespace string
+	secretCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Remove a secret of a namespace.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			_, err := util.GetAPIClientV2(cmd).Secrets().Delete(cmd.Context(), &apimodels.DeleteSecretRequest{
+				SecretNamespace: namespace,
+				SecretName:      name,
+			})
+			if err != nil {
+				return fmt.Errorf("could not delete secret %s of namespace %s: %w", name, namespace, err)
+			}
+			cmd.Printf("Deleted secret %s of namespace %s\n", name, namespace)
+			return nil
+		},
+	}
+	secretCmd.Flags().StringVar(&namespace, "namespace", models.DefaultNamespace, "Namespace of the secret")
+	return secretCmd
+}
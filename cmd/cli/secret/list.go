@@ -0,0 +1,61 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// ListOptions is a struct to support secret list command
+type ListOptions struct {
+	output.OutputOptions
+	cliflags.ListOptions
+	Namespace string
+}
+
+// NewListOptions returns initialized Options
+func NewListOptions() *ListOptions {
+	return &ListOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewListCmd() *cobra.Command {
+	o := NewListOptions()
+	secretCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the secrets of a namespace, or of all namespaces. Values are never shown.",
+		Args:  cobra.NoArgs,
+		RunE:  o.run,
+	}
+	secretCmd.Flags().StringVar(&o.Namespace, "namespace", "", "Namespace to list the secrets of. Defaults to all namespaces")
+	secretCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
+	secretCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return secretCmd
+}
+
+func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	response, err := util.GetAPIClientV2(cmd).Secrets().List(ctx, &apimodels.ListSecretsRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			BaseGetRequest: apimodels.BaseGetRequest{
+				BaseRequest: apimodels.BaseRequest{Namespace: o.Namespace},
+			},
+			Limit:     o.Limit,
+			NextToken: o.NextToken,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if err = output.Output(cmd, secretColumns, o.OutputOptions, response.Secrets); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// SetOptions is a struct to support secret set command
+type SetOptions struct {
+	Namespace string
+	Value     string
+	FromFile  string
+}
+
+func NewSetCmd() *cobra.Command {
+	o := &SetOptions{}
+	secretCmd := &cobra.Command{
+		Use:   "set [name]",
+		Short: "Create or replace a secret of a namespace.",
+		Long: `Create or replace a secret of a namespace.
+The value is read from --value, --from-file or, if neither is set, from stdin.
+Tasks of jobs in the same namespace reference the secret by name, and receive its value
+as an environment variable or a read-only file.`,
+		Example: `  # Set the api-key secret of the default namespace from stdin
+  echo -n "s3cr3t" | bacalhau secret set api-key
+
+  # Set the tls-key secret of the ml namespace from a file
+  bacalhau secret set tls-key --namespace ml --from-file ./key.pem`,
+		Args: cobra.ExactArgs(1),
+		RunE: o.run,
+	}
+	secretCmd.Flags().StringVar(&o.Namespace, "namespace", models.DefaultNamespace, "Namespace of the secret")
+	secretCmd.Flags().StringVar(&o.Value, "value", "",
+		"Value of the secret. Prefer --from-file or stdin, as the value may be kept in your shell history")
+	secretCmd.Flags().StringVar(&o.FromFile, "from-file", "", "Path of the file to read the value of the secret from")
+	secretCmd.MarkFlagsMutuallyExclusive("value", "from-file")
+	return secretCmd
+}
+
+func (o *SetOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	value, err := o.readValue(cmd)
+	if err != nil {
+		return err
+	}
+	secret := &models.Secret{
+		Namespace: o.Namespace,
+		Name:      args[0],
+		Value:     value,
+	}
+	secret.Normalize()
+	if err = secret.Validate(); err != nil {
+		return err
+	}
+
+	response, err := util.GetAPIClientV2(cmd).Secrets().Put(ctx, &apimodels.PutSecretRequest{
+		Secret: secret,
+	})
+	if err != nil {
+		return fmt.Errorf("could not set secret %s of namespace %s: %w", secret.Name, secret.Namespace, err)
+	}
+	cmd.Printf("Set secret %s of namespace %s\n", response.Secret.Name, response.Secret.Namespace)
+	return nil
+}
+
+// readValue returns the value of the secret from the flags, or from stdin if none is set.
+// A single trailing newline is trimmed from values read from stdin.
+func (o *SetOptions) readValue(cmd *cobra.Command) (string, error) {
+	if o.Value != "" {
+		return o.Value, nil
+	}
+	if o.FromFile != "" {
+		data, err := os.ReadFile(o.FromFile)
+		if err != nil {
+			return "", fmt.Errorf("could not read secret value from %s: %w", o.FromFile, err)
+		}
+		return string(data), nil
+	}
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", fmt.Errorf("could not read secret value from stdin: %w", err)
+	}
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return "", errors.New("missing secret value: set --value, --from-file or pipe it to stdin")
+	}
+	return value, nil
+}
@@ -99,6 +99,7 @@ func GetPeers(peerConnect string) ([]multiaddr.Multiaddr, error) {
 func NewCmd() *cobra.Command {
 	serveFlags := map[string][]configflags.Definition{
 		"local_publisher":       configflags.LocalPublisherFlags,
+		"ports":                 configflags.PortsFlags,
 		"publishing":            configflags.PublishingFlags,
 		"requester-tls":         configflags.RequesterTLSFlags,
 		"server-api":            configflags.ServerAPIFlags,
@@ -113,12 +114,15 @@ func NewCmd() *cobra.Command {
 		"node-type":             configflags.NodeTypeFlags,
 		"list-local":            configflags.AllowListLocalPathsFlags,
 		"compute-store":         configflags.ComputeStorageFlags,
+		"compute-logs":          configflags.ComputeLogsFlags,
 		"requester-store":       configflags.RequesterJobStorageFlags,
 		"web-ui":                configflags.WebUIFlags,
 		"node-info-store":       configflags.NodeInfoStoreFlags,
 		"node-name":             configflags.NodeNameFlags,
 		"translations":          configflags.JobTranslationFlags,
 		"docker-cache-manifest": configflags.DockerManifestCacheFlags,
+		"docker-runtime":        configflags.DockerRuntimeFlags,
+		"process-executor":      configflags.ProcessExecutorFlags,
 	}
 
 	serveCmd := &cobra.Command{
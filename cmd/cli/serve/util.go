@@ -7,15 +7,18 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	pkgerrors "github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 	"github.com/spf13/viper"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 
@@ -46,12 +49,17 @@ func GetComputeConfig(ctx context.Context, createExecutionStore bool) (node.Comp
 
 	var err error
 	var executionStore store.ExecutionStore
+	var logStore *logstream.LogStore
 
 	if createExecutionStore {
 		executionStore, err = getExecutionStore(ctx, cfg.ExecutionStore)
 		if err != nil {
 			return node.ComputeConfig{}, pkgerrors.Wrapf(err, "failed to create execution store")
 		}
+		logStore, err = getLogStore(cfg.LogStreamConfig.Persistence)
+		if err != nil {
+			return node.ComputeConfig{}, pkgerrors.Wrapf(err, "failed to create execution log store")
+		}
 	}
 
 	return node.NewComputeConfigWith(node.ComputeConfigParams{
@@ -75,7 +83,13 @@ func GetComputeConfig(ctx context.Context, createExecutionStore bool) (node.Comp
 		LogRunningExecutionsInterval: time.Duration(cfg.Logging.LogRunningExecutionsInterval),
 		LogStreamBufferSize:          cfg.LogStreamConfig.ChannelBufferSize,
 		ExecutionStore:               executionStore,
+		LogStore:                     logStore,
+		PublishLogs:                  logStore != nil && cfg.LogStreamConfig.Persistence.Publish,
 		LocalPublisher:               cfg.LocalPublisher,
+		PortsAddress:                 cfg.PortsAddress,
+		DockerRuntime:                cfg.DockerRuntime,
+		ProcessExecutor:              cfg.ProcessExecutor,
+		EnablePreemption:             cfg.Queue.EnablePreemption,
 	})
 }
 
@@ -85,9 +99,16 @@ func GetRequesterConfig(ctx context.Context, createJobStore bool) (node.Requeste
 		return node.RequesterConfig{}, err
 	}
 
-	var err error
+	retentionPolicies, err := getRetentionPolicies(cfg.JobRetention)
+	if err != nil {
+		return node.RequesterConfig{}, err
+	}
+
 	var jobStore jobstore.Store
 	if createJobStore {
+		if cfg.JobRetention.CompactOnStartup {
+			compactJobStore(ctx, cfg.JobStore)
+		}
 		jobStore, err = getJobStore(ctx, cfg.JobStore)
 		if err != nil {
 			return node.RequesterConfig{}, pkgerrors.Wrapf(err, "failed to create job store")
@@ -121,7 +142,11 @@ func GetRequesterConfig(ctx context.Context, createJobStore bool) (node.Requeste
 		S3PreSignedURLDisabled:         cfg.StorageProvider.S3.PreSignedURLDisabled,
 		TranslationEnabled:             cfg.TranslationEnabled,
 		JobStore:                       jobStore,
+		SecretsKeyPath:                 getSecretsKeyPath(cfg.JobStore),
+		RetentionPolicies:              retentionPolicies,
+		RetentionInterval:              time.Duration(cfg.JobRetention.Interval),
 		DefaultPublisher:               cfg.DefaultPublisher,
+		DrainGracePeriod:               time.Duration(cfg.DrainGracePeriod),
 	})
 	if err != nil {
 		return node.RequesterConfig{}, err
@@ -236,6 +261,12 @@ func getNetworkConfig() (node.NetworkConfig, error) {
 		ClusterPort:              networkCfg.Cluster.Port,
 		ClusterAdvertisedAddress: networkCfg.Cluster.AdvertisedAddress,
 		ClusterPeers:             networkCfg.Cluster.Peers,
+		TLSCertFile:              networkCfg.TLS.CertFile,
+		TLSKeyFile:               networkCfg.TLS.KeyFile,
+		TLSCACertFile:            networkCfg.TLS.CACertFile,
+		TLSVerifyClients:         networkCfg.TLS.VerifyClients,
+		RequireNodeCredentials:   networkCfg.NodeCredentials.Required,
+		NodeCredentialsTTL:       networkCfg.NodeCredentials.TTL.AsTimeDuration(),
 	}, nil
 }
 
@@ -252,6 +283,26 @@ func getExecutionStore(ctx context.Context, storeCfg types.JobStoreConfig) (stor
 	}
 }
 
+// getLogStore returns the store of execution logs, or nil if log persistence is disabled
+func getLogStore(cfg types.LogPersistenceConfig) (*logstream.LogStore, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var maxFileSize uint64
+	if cfg.MaxFileSize != "" {
+		var err error
+		if maxFileSize, err = humanize.ParseBytes(cfg.MaxFileSize); err != nil {
+			return nil, fmt.Errorf("invalid max log file size %q: %w", cfg.MaxFileSize, err)
+		}
+	}
+	return logstream.NewLogStore(logstream.LogStoreParams{
+		Directory:   cfg.Directory,
+		MaxFileSize: int64(maxFileSize),
+		MaxFiles:    cfg.MaxFiles,
+		Retention:   time.Duration(cfg.Retention),
+	})
+}
+
 func getJobStore(ctx context.Context, storeCfg types.JobStoreConfig) (jobstore.Store, error) {
 	if err := storeCfg.Validate(); err != nil {
 		return nil, err
@@ -261,11 +312,63 @@ func getJobStore(ctx context.Context, storeCfg types.JobStoreConfig) (jobstore.S
 	case types.BoltDB:
 		log.Ctx(ctx).Debug().Str("Path", storeCfg.Path).Msg("creating boltdb backed jobstore")
 		return boltjobstore.NewBoltJobStore(storeCfg.Path)
+	case types.Postgres:
+		log.Ctx(ctx).Debug().Msg("creating postgres backed jobstore")
+		return sqljobstore.NewSQLJobStore(ctx, sqljobstore.DriverPostgres, storeCfg.ConnectionString)
 	default:
 		return nil, fmt.Errorf("unknown JobStore type: %s", storeCfg.Type)
 	}
 }
 
+// getSecretsKeyPath returns the file holding the key that encrypts the secrets, which is kept
+// next to the job store path even when the jobs are stored in PostgreSQL
+func getSecretsKeyPath(storeCfg types.JobStoreConfig) string {
+	if storeCfg.Path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(storeCfg.Path), node.SecretsKeyFileName)
+}
+
+// compactJobStore reclaims the space left by pruned jobs in the job store database. It is done
+// before the job store is opened, as BoltDB databases cannot be compacted while in use.
+// Failing to compact is not fatal, and the job store is used as is.
+func compactJobStore(ctx context.Context, storeCfg types.JobStoreConfig) {
+	if storeCfg.Type != types.BoltDB || storeCfg.Path == "" {
+		return
+	}
+	before, after, err := boltjobstore.CompactDatabase(storeCfg.Path)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("Path", storeCfg.Path).Msg("failed to compact jobstore")
+		return
+	}
+	log.Ctx(ctx).Debug().Str("Path", storeCfg.Path).
+		Int64("SizeBefore", before).Int64("SizeAfter", after).Msg("compacted boltdb backed jobstore")
+}
+
+func getRetentionPolicies(retentionCfg types.JobRetentionConfig) ([]models.RetentionPolicy, error) {
+	policies := make([]models.RetentionPolicy, 0, len(retentionCfg.Policies))
+	for i, policyCfg := range retentionCfg.Policies {
+		policy := models.RetentionPolicy{
+			Namespace: policyCfg.Namespace,
+			MaxAge:    time.Duration(policyCfg.MaxAge),
+			KeepLast:  policyCfg.KeepLast,
+		}
+		for _, state := range policyCfg.States {
+			var stateType models.JobStateType
+			if err := stateType.UnmarshalText([]byte(state)); err != nil {
+				return nil, fmt.Errorf("invalid job retention policy %d: %w", i, err)
+			}
+			policy.States = append(policy.States, stateType)
+		}
+		policy.Normalize()
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid job retention policy %d: %w", i, err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
 func getNodeID(ctx context.Context) (string, error) {
 	nodeName, err := config.Get[string](types.NodeName)
 	if err != nil {
@@ -18,7 +18,9 @@ func GetAPIClient(ctx context.Context) *client.APIClient {
 	legacyTLS := client.LegacyTLSSupport(config.ClientTLSConfig())
 	apiClient := client.NewAPIClient(legacyTLS, config.ClientAPIHost(), config.ClientAPIPort())
 
-	if token, err := ReadToken(config.ClientAPIBase()); err != nil {
+	if apiKey := config.ClientAPIKey(); apiKey != "" {
+		apiClient.DefaultHeaders["Authorization"] = apiKeyCredential(apiKey).String()
+	} else if token, err := ReadToken(config.ClientAPIBase()); err != nil {
 		log.Warn().Err(err).Msg("Failed to read access tokens – API calls will be without authorization")
 	} else if token != nil {
 		apiClient.DefaultHeaders["Authorization"] = token.String()
@@ -47,6 +49,17 @@ func GetAPIClientV2(cmd *cobra.Command) clientv2.API {
 		clientv2.WithHeaders(headers),
 	}
 
+	// API keys are long-lived and cannot be renewed, so there is no
+	// authentication flow to run when they are rejected
+	if apiKey := config.ClientAPIKey(); apiKey != "" {
+		return clientv2.NewAPI(
+			&clientv2.AuthenticatingClient{
+				Client:     clientv2.NewHTTPClient(base, opts...),
+				Credential: apiKeyCredential(apiKey),
+			},
+		)
+	}
+
 	existingAuthToken, err := ReadToken(base)
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to read access tokens – API calls will be without authorization")
@@ -65,3 +78,10 @@ func GetAPIClientV2(cmd *cobra.Command) clientv2.API {
 		},
 	)
 }
+
+func apiKeyCredential(apiKey string) *apimodels.HTTPCredential {
+	return &apimodels.HTTPCredential{
+		Scheme: "Bearer",
+		Value:  apiKey,
+	}
+}
@@ -23,6 +23,7 @@ func RunAuthenticationFlow(cmd *cobra.Command, auth *client.Auth) (*apimodels.HT
 	supportedMethods := map[authn.MethodType]responder{
 		authn.MethodTypeChallenge: challenge.Respond,
 		authn.MethodTypeAsk:       askResponder(cmd),
+		authn.MethodTypeOIDC:      oidcResponder(cmd),
 	}
 
 	methods, err := auth.Methods(cmd.Context(), &apimodels.ListAuthnMethodsRequest{})
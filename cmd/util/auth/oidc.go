@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authn/oidc"
+)
+
+// oidcResponder signs the user in with the identity provider required by the
+// server, printing the sign in instructions to stderr.
+func oidcResponder(cmd *cobra.Command) responder {
+	return func(request *json.RawMessage) ([]byte, error) {
+		return oidc.Respond(cmd.Context(), request, cmd.ErrOrStderr())
+	}
+}
@@ -41,6 +41,14 @@ Ignored if BACALHAU_API_PORT environment variable is set.`,
 		Description:          `Enables TLS but does not verify certificates`,
 		EnvironmentVariables: []string{"BACALHAU_API_INSECURE"},
 	},
+	{
+		FlagName:     "api-key",
+		DefaultValue: Default.Node.ClientAPI.APIKey,
+		ConfigPath:   types.NodeClientAPIAPIKey,
+		Description: `An API key issued by the requester to authenticate with, instead of an access token.
+Prefer the BACALHAU_API_KEY environment variable, as the flag may be kept in your shell history.`,
+		EnvironmentVariables: []string{"BACALHAU_API_KEY"},
+	},
 }
 
 var ServerAPIFlags = []Definition{
@@ -46,4 +46,11 @@ var CapacityFlags = []Definition{
 		DefaultValue: Default.Node.Compute.Capacity.JobResourceLimits.GPU,
 		Description:  `Job GPU limit to run all jobs (e.g. 1, 2, or 8).`,
 	},
+	{
+		FlagName:     "enable-preemption",
+		ConfigPath:   types.NodeComputeQueueEnablePreemption,
+		DefaultValue: Default.Node.Compute.Queue.EnablePreemption,
+		Description: `When set the compute node will preempt lower priority executions of preemptible jobs ` +
+			`to run higher priority executions when out of capacity.`,
+	},
 }
@@ -0,0 +1,43 @@
+package configflags
+
+import "github.com/bacalhau-project/bacalhau/pkg/config/types"
+
+var ComputeLogsFlags = []Definition{
+	{
+		FlagName:             "compute-log-persistence",
+		ConfigPath:           types.NodeComputeLogStreamConfigPersistenceEnabled,
+		DefaultValue:         Default.Node.Compute.LogStreamConfig.Persistence.Enabled,
+		Description:          "Persist the logs of executions so that they can be retrieved after they complete",
+		EnvironmentVariables: []string{"BACALHAU_COMPUTE_LOG_PERSISTENCE"},
+	},
+	{
+		FlagName:     "compute-log-directory",
+		ConfigPath:   types.NodeComputeLogStreamConfigPersistenceDirectory,
+		DefaultValue: Default.Node.Compute.LogStreamConfig.Persistence.Directory,
+		Description:  "The directory where the logs of executions are persisted",
+	},
+	{
+		FlagName:     "compute-log-max-file-size",
+		ConfigPath:   types.NodeComputeLogStreamConfigPersistenceMaxFileSize,
+		DefaultValue: Default.Node.Compute.LogStreamConfig.Persistence.MaxFileSize,
+		Description:  "The size after which the log file of an execution is rotated (e.g. 10MB)",
+	},
+	{
+		FlagName:     "compute-log-max-files",
+		ConfigPath:   types.NodeComputeLogStreamConfigPersistenceMaxFiles,
+		DefaultValue: Default.Node.Compute.LogStreamConfig.Persistence.MaxFiles,
+		Description:  "The number of rotated log files kept per execution",
+	},
+	{
+		FlagName:     "compute-log-retention",
+		ConfigPath:   types.NodeComputeLogStreamConfigPersistenceRetention,
+		DefaultValue: Default.Node.Compute.LogStreamConfig.Persistence.Retention,
+		Description:  "How long the logs of executions are kept after they were last written (0 keeps them forever)",
+	},
+	{
+		FlagName:     "compute-log-publish",
+		ConfigPath:   types.NodeComputeLogStreamConfigPersistencePublish,
+		DefaultValue: Default.Node.Compute.LogStreamConfig.Persistence.Publish,
+		Description:  "Publish the logs of executions along with their results",
+	},
+}
@@ -0,0 +1,26 @@
+package configflags
+
+import "github.com/bacalhau-project/bacalhau/pkg/config/types"
+
+var DockerRuntimeFlags = []Definition{
+	{
+		FlagName:             "docker-runtime",
+		ConfigPath:           types.NodeComputeDockerRuntimeType,
+		DefaultValue:         Default.Node.Compute.DockerRuntime.Type,
+		Description:          `How docker jobs are run: with the docker daemon (docker), or directly with an OCI runtime (oci)`,
+		EnvironmentVariables: []string{"BACALHAU_DOCKER_RUNTIME"},
+	},
+	{
+		FlagName:             "oci-runtime",
+		ConfigPath:           types.NodeComputeDockerRuntimeOCIRuntime,
+		DefaultValue:         Default.Node.Compute.DockerRuntime.OCI.Runtime,
+		Description:          `The OCI runtime binary used to run docker jobs when --docker-runtime is oci, e.g. runc or crun`,
+		EnvironmentVariables: []string{"BACALHAU_OCI_RUNTIME"},
+	},
+	{
+		FlagName:     "oci-directory",
+		ConfigPath:   types.NodeComputeDockerRuntimeOCIDirectory,
+		DefaultValue: Default.Node.Compute.DockerRuntime.OCI.Directory,
+		Description:  `The directory where the images and bundles of docker jobs run with an OCI runtime are stored`,
+	},
+}
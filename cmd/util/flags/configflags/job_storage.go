@@ -7,7 +7,7 @@ var RequesterJobStorageFlags = []Definition{
 		FlagName:             "requester-job-store-type",
 		ConfigPath:           types.NodeRequesterJobStoreType,
 		DefaultValue:         Default.Node.Requester.JobStore.Type,
-		Description:          "The type of job store used by the requester node (BoltDB)",
+		Description:          "The type of job store used by the requester node (BoltDB|Postgres)",
 		EnvironmentVariables: []string{"BACALHAU_JOB_STORE_TYPE"},
 	},
 	{
@@ -17,4 +17,25 @@ var RequesterJobStorageFlags = []Definition{
 		Description:          "The path used for the requester job store store when using BoltDB",
 		EnvironmentVariables: []string{"BACALHAU_JOB_STORE_PATH"},
 	},
+	{
+		FlagName:             "requester-job-store-connection-string",
+		ConfigPath:           types.NodeRequesterJobStoreConnectionString,
+		DefaultValue:         Default.Node.Requester.JobStore.ConnectionString,
+		Description:          "The connection string of the database used for the requester job store when using Postgres",
+		EnvironmentVariables: []string{"BACALHAU_JOB_STORE_CONNECTION_STRING"},
+	},
+	{
+		FlagName:     "requester-job-retention-interval",
+		ConfigPath:   types.NodeRequesterJobRetentionInterval,
+		DefaultValue: Default.Node.Requester.JobRetention.Interval,
+		Description: "How often terminal jobs that are no longer retained by the job retention policies " +
+			"are pruned from the requester job store. Zero disables the pruning",
+		EnvironmentVariables: []string{"BACALHAU_JOB_RETENTION_INTERVAL"},
+	},
+	{
+		FlagName:     "requester-job-store-compact-on-startup",
+		ConfigPath:   types.NodeRequesterJobRetentionCompactOnStartup,
+		DefaultValue: Default.Node.Requester.JobRetention.CompactOnStartup,
+		Description:  "Compact the requester job store when the node starts, to reclaim the space of pruned jobs when using BoltDB",
+	},
 }
@@ -57,4 +57,40 @@ var NetworkFlags = []Definition{
 		DefaultValue: Default.Node.Network.Cluster.Peers,
 		Description:  `Comma-separated list of other orchestrators to connect to to form a cluster.`,
 	},
+	{
+		FlagName:     "network-tls-cert",
+		ConfigPath:   types.NodeNetworkTLSCertFile,
+		DefaultValue: Default.Node.Network.TLS.CertFile,
+		Description:  `Path to the TLS certificate of the node for connections between nodes.`,
+	},
+	{
+		FlagName:     "network-tls-key",
+		ConfigPath:   types.NodeNetworkTLSKeyFile,
+		DefaultValue: Default.Node.Network.TLS.KeyFile,
+		Description:  `Path to the private key of the TLS certificate of the node.`,
+	},
+	{
+		FlagName:     "network-tls-ca-cert",
+		ConfigPath:   types.NodeNetworkTLSCACertFile,
+		DefaultValue: Default.Node.Network.TLS.CACertFile,
+		Description:  `Path to the CA certificate that TLS certificates of other nodes are verified against.`,
+	},
+	{
+		FlagName:     "network-tls-verify-clients",
+		ConfigPath:   types.NodeNetworkTLSVerifyClients,
+		DefaultValue: Default.Node.Network.TLS.VerifyClients,
+		Description:  `Require compute nodes to present a TLS certificate signed by the CA. Applies to orchestrator nodes.`,
+	},
+	{
+		FlagName:     "network-require-node-credentials",
+		ConfigPath:   types.NodeNetworkNodeCredentialsRequired,
+		DefaultValue: Default.Node.Network.NodeCredentials.Required,
+		Description:  `Require compute nodes to connect with the credentials issued to them once approved.`,
+	},
+	{
+		FlagName:     "network-node-credentials-ttl",
+		ConfigPath:   types.NodeNetworkNodeCredentialsTTL,
+		DefaultValue: Default.Node.Network.NodeCredentials.TTL,
+		Description:  `How long credentials issued to compute nodes are valid for. Applies to orchestrator nodes.`,
+	},
 }
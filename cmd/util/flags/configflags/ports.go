@@ -0,0 +1,12 @@
+package configflags
+
+import "github.com/bacalhau-project/bacalhau/pkg/config/types"
+
+var PortsFlags = []Definition{
+	{
+		FlagName:     "ports-address",
+		DefaultValue: Default.Node.Compute.PortsAddress,
+		ConfigPath:   types.NodeComputePortsAddress,
+		Description:  `The address at which the ports published by executions are reachable, either a host or one of public, private or local`,
+	},
+}
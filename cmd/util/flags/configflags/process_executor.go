@@ -0,0 +1,37 @@
+package configflags
+
+import "github.com/bacalhau-project/bacalhau/pkg/config/types"
+
+var ProcessExecutorFlags = []Definition{
+	{
+		FlagName:             "process-executor",
+		ConfigPath:           types.NodeComputeProcessExecutorEnabled,
+		DefaultValue:         Default.Node.Compute.ProcessExecutor.Enabled,
+		Description:          `Run jobs of the process engine directly on the host, without containers. Only enable it on trusted clusters`,
+		EnvironmentVariables: []string{"BACALHAU_PROCESS_EXECUTOR"},
+	},
+	{
+		FlagName:     "process-executor-user",
+		ConfigPath:   types.NodeComputeProcessExecutorUser,
+		DefaultValue: Default.Node.Compute.ProcessExecutor.User,
+		Description:  `The user the processes of jobs run as. Required when the node runs as root`,
+	},
+	{
+		FlagName:     "process-executor-allowed-commands",
+		ConfigPath:   types.NodeComputeProcessExecutorAllowedCommands,
+		DefaultValue: Default.Node.Compute.ProcessExecutor.AllowedCommands,
+		Description:  `Absolute paths, or glob patterns of absolute paths, of the commands that jobs of the process engine can run`,
+	},
+	{
+		FlagName:     "process-executor-directory",
+		ConfigPath:   types.NodeComputeProcessExecutorDirectory,
+		DefaultValue: Default.Node.Compute.ProcessExecutor.Directory,
+		Description:  `The directory where the sandboxes of executions of the process engine are created`,
+	},
+	{
+		FlagName:     "process-executor-max-processes",
+		ConfigPath:   types.NodeComputeProcessExecutorMaxProcesses,
+		DefaultValue: Default.Node.Compute.ProcessExecutor.MaxProcesses,
+		Description:  `The maximum number of processes and threads of each execution of the process engine, when limited by cgroups. Defaults to 1024`,
+	},
+}
@@ -22,6 +22,10 @@ type LogOptions struct {
 	ExecutionID string
 	Follow      bool
 	Tail        bool
+	Offset      int
+	TailLines   int
+	// Since only returns the logs written after this time, in unix seconds
+	Since int64
 }
 
 func Logs(cmd *cobra.Command, options LogOptions) error {
@@ -41,6 +45,9 @@ func Logs(cmd *cobra.Command, options LogOptions) error {
 		ExecutionID: options.ExecutionID,
 		Follow:      options.Follow,
 		Tail:        options.Tail,
+		Offset:      options.Offset,
+		TailLines:   options.TailLines,
+		Since:       options.Since,
 	})
 	if err != nil {
 		if errResp, ok := err.(*bacerrors.ErrorResponse); ok {
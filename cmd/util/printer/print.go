@@ -300,8 +300,8 @@ To cancel the job, run:
 			break
 		}
 
-		// If the job is long running, and it's running, we can stop the spinner
-		if resp.Job.IsLongRunning() && resp.Job.State.StateType == models.JobStateTypeRunning {
+		// If the job is long running or scheduled, and it's running, we can stop the spinner
+		if (resp.Job.IsLongRunning() || resp.Job.IsScheduled()) && resp.Job.State.StateType == models.JobStateTypeRunning {
 			spinner.Done(StopSuccess)
 			cmdShuttingDown = true
 			break
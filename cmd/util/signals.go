@@ -7,3 +7,6 @@ import "os"
 var ShutdownSignals = []os.Signal{
 	os.Interrupt,
 }
+
+// ResizeSignals are the signals that notify that the terminal was resized
+var ResizeSignals = []os.Signal{}
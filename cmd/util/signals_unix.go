@@ -11,3 +11,8 @@ var ShutdownSignals = []os.Signal{
 	os.Interrupt,
 	syscall.SIGTERM,
 }
+
+// ResizeSignals are the signals that notify that the terminal was resized
+var ResizeSignals = []os.Signal{
+	syscall.SIGWINCH,
+}
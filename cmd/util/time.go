@@ -0,0 +1,23 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseTimeFilter parses a date, an RFC3339 timestamp or a duration ago into a unix time
+// in seconds. An empty value returns zero, which means no filter.
+func ParseTimeFilter(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d).Unix(), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a date, an RFC3339 timestamp or a duration", value)
+}
@@ -22,6 +22,11 @@ allow if {
     token_valid
 }
 
+# Executing commands in running jobs must be allowed explicitly
+allow_exec if {
+    token_valid
+}
+
 # The list of namespaces from the verified access token
 token_namespaces := ns if {
     authHeader := input.http.headers["Authorization"][0]
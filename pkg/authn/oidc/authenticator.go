@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authn"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
+)
+
+//go:embed oidc_ns_groups.rego
+var policies embed.FS
+
+const (
+	// The clock skew allowed between the identity provider and the node when
+	// checking the expiry of ID tokens.
+	acceptableSkew = time.Minute
+
+	// How often the signing keys of the identity provider are refreshed at
+	// most, e.g. when an ID token is signed with an unknown key.
+	minKeysRefreshInterval = 5 * time.Minute
+)
+
+// Config configures the identity provider that users sign in with.
+type Config struct {
+	// Issuer is the URL of the identity provider, which must serve its OpenID
+	// configuration under /.well-known/openid-configuration.
+	Issuer string
+	// ClientID is the ID of the client registered with the identity provider
+	// for Bacalhau, which ID tokens must be issued to.
+	ClientID string
+	// Scopes are the scopes requested when signing in. The "openid" scope is
+	// always requested.
+	Scopes []string
+	// JWKSURL overrides the URL of the signing keys of the identity provider
+	// found in its OpenID configuration.
+	JWKSURL string
+}
+
+// The data that will be passed to the authn policy, once the ID token of the
+// user has been verified.
+type policyData struct {
+	SigningKey jwk.Key        `json:"signingKey"`
+	NodeID     string         `json:"nodeId"`
+	Claims     map[string]any `json:"claims"`
+}
+
+// The data that the user will supply to us to try and authenticate.
+type response struct {
+	IDToken string `json:"IDToken"`
+}
+
+// The data that we will send to the user to allow them to sign in with the
+// identity provider.
+type request struct {
+	Issuer   string   `json:"Issuer"`
+	ClientID string   `json:"ClientID"`
+	Scopes   []string `json:"Scopes"`
+}
+
+type oidcAuthenticator struct {
+	authnPolicy *policy.Policy
+	key         jwk.Key
+	nodeID      string
+	config      Config
+	client      *http.Client
+	clock       clock.Clock
+
+	// the issuer and signing keys of the identity provider, which are
+	// discovered on first use so that nodes can start while it is unreachable
+	mu          sync.Mutex
+	issuer      string
+	jwksURL     string
+	keys        *jwk.AutoRefresh
+	lastRefresh time.Time
+
+	validate policy.Query[policyData, string]
+}
+
+func NewAuthenticator(
+	ctx context.Context,
+	p *policy.Policy,
+	key *rsa.PrivateKey,
+	nodeID string,
+	config Config,
+) authn.Authenticator {
+	return &oidcAuthenticator{
+		authnPolicy: p,
+		key:         lo.Must(jwk.New(key)),
+		nodeID:      nodeID,
+		config:      config,
+		client:      http.DefaultClient,
+		clock:       clock.New(),
+		keys:        jwk.NewAutoRefresh(ctx),
+		validate:    policy.AddQuery[policyData, string](p, authn.PolicyTokenRule),
+	}
+}
+
+// Authenticate implements authn.Authenticator.
+func (authenticator *oidcAuthenticator) Authenticate(ctx context.Context, req []byte) (authn.Authentication, error) {
+	var userInput response
+	err := json.Unmarshal(req, &userInput)
+	if err != nil {
+		return authn.Error(errors.Wrap(err, "invalid authentication data"))
+	}
+	if userInput.IDToken == "" {
+		return authn.Failed("missing ID token"), nil
+	}
+
+	issuer, jwksURL, err := authenticator.discover(ctx)
+	if err != nil {
+		return authn.Error(err)
+	}
+
+	claims, err := authenticator.verify(ctx, userInput.IDToken, issuer, jwksURL)
+	if err != nil {
+		// Don't return an error here because this is likely a bad user request.
+		return authn.Failed(err.Error()), nil
+	}
+
+	data := policyData{
+		SigningKey: authenticator.key,
+		NodeID:     authenticator.nodeID,
+		Claims:     claims,
+	}
+
+	token, err := authenticator.validate(ctx, data)
+	if errors.Is(err, policy.ErrNoResult) {
+		return authn.Failed("ID token verified but user credentials rejected"), nil
+	} else if err != nil {
+		return authn.Error(err)
+	}
+
+	return authn.Authentication{Success: true, Token: token}, nil
+}
+
+// discover returns the issuer and the URL of the signing keys of the identity
+// provider, and fetches its OpenID configuration if they are not known yet.
+func (authenticator *oidcAuthenticator) discover(ctx context.Context) (string, string, error) {
+	authenticator.mu.Lock()
+	defer authenticator.mu.Unlock()
+
+	if authenticator.jwksURL == "" {
+		metadata, err := discover(ctx, authenticator.client, authenticator.config.Issuer)
+		if err != nil {
+			return "", "", err
+		}
+
+		authenticator.issuer = metadata.Issuer
+		authenticator.jwksURL = lo.Ternary(authenticator.config.JWKSURL != "", authenticator.config.JWKSURL, metadata.JWKSURI)
+		authenticator.keys.Configure(authenticator.jwksURL,
+			jwk.WithHTTPClient(authenticator.client),
+			jwk.WithMinRefreshInterval(minKeysRefreshInterval),
+		)
+	}
+	return authenticator.issuer, authenticator.jwksURL, nil
+}
+
+// verify checks that the ID token is signed by the identity provider, issued
+// to the client and not expired, and returns its claims.
+func (authenticator *oidcAuthenticator) verify(ctx context.Context, idToken, issuer, jwksURL string) (map[string]any, error) {
+	keys, err := authenticator.keys.Fetch(ctx, jwksURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch identity provider keys")
+	}
+
+	message, err := jws.ParseString(idToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+	if len(message.Signatures()) != 1 {
+		return nil, errors.New("invalid ID token: expected a single signature")
+	}
+
+	// the identity provider may have rotated its keys since we last fetched them
+	keyID := message.Signatures()[0].ProtectedHeaders().KeyID()
+	if _, ok := keys.LookupKeyID(keyID); !ok && keyID != "" {
+		if refreshed, ok := authenticator.refreshKeys(ctx, jwksURL); ok {
+			keys = refreshed
+		}
+	}
+
+	token, err := jwt.Parse([]byte(idToken),
+		jwt.WithKeySet(keys),
+		jwt.InferAlgorithmFromKey(true),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(authenticator.config.ClientID),
+		jwt.WithRequiredClaim(jwt.ExpirationKey),
+		jwt.WithClock(jwt.ClockFunc(authenticator.clock.Now)),
+		jwt.WithAcceptableSkew(acceptableSkew),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+
+	return token.AsMap(ctx)
+}
+
+// refreshKeys fetches the signing keys of the identity provider again, unless
+// they were refreshed recently so that tokens signed with unknown keys cannot
+// be used to flood the identity provider with requests.
+func (authenticator *oidcAuthenticator) refreshKeys(ctx context.Context, jwksURL string) (jwk.Set, bool) {
+	authenticator.mu.Lock()
+	now := authenticator.clock.Now()
+	if now.Sub(authenticator.lastRefresh) < minKeysRefreshInterval {
+		authenticator.mu.Unlock()
+		return nil, false
+	}
+	authenticator.lastRefresh = now
+	authenticator.mu.Unlock()
+
+	keys, err := authenticator.keys.Refresh(ctx, jwksURL)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to refresh identity provider keys")
+		return nil, false
+	}
+	return keys, true
+}
+
+// IsInstalled implements authn.Authenticator.
+func (authenticator *oidcAuthenticator) IsInstalled(context.Context) (bool, error) {
+	return true, nil
+}
+
+// Requirement implements authn.Authenticator.
+func (authenticator *oidcAuthenticator) Requirement() authn.Requirement {
+	req := request{
+		Issuer:   authenticator.config.Issuer,
+		ClientID: authenticator.config.ClientID,
+		Scopes:   authenticator.scopes(),
+	}
+
+	params := json.RawMessage(lo.Must(json.Marshal(req)))
+	return authn.Requirement{
+		Type:   authn.MethodTypeOIDC,
+		Params: &params,
+	}
+}
+
+// scopes returns the configured scopes, including the "openid" scope that
+// makes the identity provider issue an ID token.
+func (authenticator *oidcAuthenticator) scopes() []string {
+	return lo.Uniq(append([]string{"openid"}, authenticator.config.Scopes...))
+}
+
+// GroupsPolicy grants read-only access to all namespaces, and the access to
+// namespaces that the policy maps the groups of the user to.
+var GroupsPolicy *policy.Policy = lo.Must(policy.FromFS(policies, "oidc_ns_groups.rego"))
@@ -0,0 +1,184 @@
+//go:build unit || !integration
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authn"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+)
+
+func setup(t *testing.T) (*testIssuer, authn.Authenticator) {
+	logger.ConfigureTestLogging(t)
+
+	authPolicy, err := policy.FromFS(os.DirFS("testdata"), "oidc_ns_test_groups.rego")
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := newTestIssuer(t)
+	return issuer, NewAuthenticator(context.Background(), authPolicy, rsaKey, "node", Config{
+		Issuer:   issuer.URL(),
+		ClientID: testClientID,
+		Scopes:   []string{"groups", "openid"},
+	})
+}
+
+func try(t *testing.T, authenticator authn.Authenticator, idToken string) authn.Authentication {
+	req, err := json.Marshal(response{IDToken: idToken})
+	require.NoError(t, err)
+
+	auth, err := authenticator.Authenticate(context.Background(), req)
+	require.NoError(t, err)
+	return auth
+}
+
+// namespaces returns the namespace permissions granted by the token
+func namespaces(t *testing.T, token string) map[string]any {
+	parsed, err := jwt.Parse([]byte(token))
+	require.NoError(t, err)
+	ns, ok := parsed.Get("ns")
+	require.True(t, ok)
+	return ns.(map[string]any)
+}
+
+func TestRequirement(t *testing.T) {
+	issuer, authenticator := setup(t)
+
+	requirement := authenticator.Requirement()
+	require.Equal(t, authn.MethodTypeOIDC, requirement.Type)
+
+	var params request
+	require.NoError(t, json.Unmarshal(*requirement.Params, &params))
+	require.Equal(t, issuer.URL(), params.Issuer)
+	require.Equal(t, testClientID, params.ClientID)
+	require.Equal(t, []string{"openid", "groups"}, params.Scopes)
+}
+
+func TestValidToken(t *testing.T) {
+	issuer, authenticator := setup(t)
+
+	auth := try(t, authenticator, issuer.idToken())
+	require.True(t, auth.Success, auth.Reason)
+	require.NotEmpty(t, auth.Token)
+
+	parsed, err := jwt.Parse([]byte(auth.Token))
+	require.NoError(t, err)
+	require.Equal(t, "alice", parsed.Subject())
+	require.Equal(t, map[string]any{"*": 5.0}, namespaces(t, auth.Token))
+}
+
+func TestGroupsMappedToNamespaces(t *testing.T) {
+	issuer, authenticator := setup(t)
+	issuer.groups = []string{"analysts", "data-engineering", "unknown"}
+
+	auth := try(t, authenticator, issuer.idToken())
+	require.True(t, auth.Success, auth.Reason)
+	require.Equal(t, map[string]any{
+		"*":       5.0,
+		"data":    31.0,
+		"reports": 31.0,
+	}, namespaces(t, auth.Token))
+}
+
+func TestRotatedSigningKey(t *testing.T) {
+	issuer, authenticator := setup(t)
+	require.True(t, try(t, authenticator, issuer.idToken()).Success)
+
+	issuer.rotateKey("key-2")
+	auth := try(t, authenticator, issuer.idToken())
+	require.True(t, auth.Success, auth.Reason)
+}
+
+func TestUnknownSigningKeyRefreshIsRateLimited(t *testing.T) {
+	issuer, authenticator := setup(t)
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	authenticator.(*oidcAuthenticator).clock = clk
+	require.True(t, try(t, authenticator, issuer.idToken()).Success)
+	require.Equal(t, 1, issuer.keyRequests())
+
+	// tokens signed with a known key but an invalid signature do not refresh the keys
+	otherIssuer := newTestIssuer(t)
+	forged := otherIssuer.idToken(func(token jwt.Token) {
+		require.NoError(t, token.Set(jwt.IssuerKey, issuer.URL()))
+	})
+	require.False(t, try(t, authenticator, forged).Success)
+	require.Equal(t, 1, issuer.keyRequests())
+
+	// tokens signed with an unknown key refresh the keys at most once per interval
+	otherIssuer.rotateKey("unknown")
+	unknown := otherIssuer.idToken(func(token jwt.Token) {
+		require.NoError(t, token.Set(jwt.IssuerKey, issuer.URL()))
+	})
+	for i := 0; i < 3; i++ {
+		require.False(t, try(t, authenticator, unknown).Success)
+	}
+	require.Equal(t, 2, issuer.keyRequests())
+
+	// a key rotated by the issuer is picked up once the interval is over
+	issuer.rotateKey("key-2")
+	require.False(t, try(t, authenticator, issuer.idToken()).Success)
+	require.Equal(t, 2, issuer.keyRequests())
+
+	clk.Add(minKeysRefreshInterval)
+	auth := try(t, authenticator, issuer.idToken())
+	require.True(t, auth.Success, auth.Reason)
+	require.Equal(t, 3, issuer.keyRequests())
+}
+
+func TestInvalidTokens(t *testing.T) {
+	issuer, authenticator := setup(t)
+	otherIssuer := newTestIssuer(t)
+
+	for name, idToken := range map[string]string{
+		"missing":   "",
+		"malformed": "not-a-token",
+		"wrong issuer": issuer.idToken(func(token jwt.Token) {
+			require.NoError(t, token.Set(jwt.IssuerKey, "https://example.com"))
+		}),
+		"wrong audience": issuer.idToken(func(token jwt.Token) {
+			require.NoError(t, token.Set(jwt.AudienceKey, []string{"other-client"}))
+		}),
+		"expired": issuer.idToken(func(token jwt.Token) {
+			require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(-time.Hour)))
+		}),
+		"missing expiry": issuer.idToken(func(token jwt.Token) {
+			require.NoError(t, token.Remove(jwt.ExpirationKey))
+		}),
+		"unknown key": otherIssuer.idToken(func(token jwt.Token) {
+			require.NoError(t, token.Set(jwt.IssuerKey, issuer.URL()))
+		}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			auth := try(t, authenticator, idToken)
+			require.False(t, auth.Success)
+			require.NotEmpty(t, auth.Reason)
+			require.Empty(t, auth.Token)
+		})
+	}
+}
+
+func TestUnreachableIssuer(t *testing.T) {
+	issuer, authenticator := setup(t)
+	idToken := issuer.idToken()
+	issuer.server.Close()
+
+	req, err := json.Marshal(response{IDToken: idToken})
+	require.NoError(t, err)
+	_, err = authenticator.Authenticate(context.Background(), req)
+	require.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// How long the user has to sign in with the identity provider
+	signInTimeout = 5 * time.Minute
+
+	callbackPath = "/callback"
+)
+
+// Respond signs the user in with the identity provider described by the input,
+// and returns the ID token it issues. The device authorization flow is used if
+// the identity provider supports it, so that users can sign in from another
+// device than the one running the CLI. Otherwise, the authorization code flow
+// with PKCE is used, which redirects the browser of the user to the CLI.
+// Instructions for the user are written to out.
+func Respond(ctx context.Context, input *json.RawMessage, out io.Writer) ([]byte, error) {
+	return (&signIn{client: http.DefaultClient, out: out}).respond(ctx, input)
+}
+
+type signIn struct {
+	client *http.Client
+	out    io.Writer
+	// visit is called with the URL that the user has to visit to sign in,
+	// in addition to printing it. Only used by tests to act as the browser.
+	visit func(url string)
+}
+
+func (s *signIn) respond(ctx context.Context, input *json.RawMessage) ([]byte, error) {
+	var req request
+	if input == nil {
+		return nil, errors.New("missing OpenID Connect parameters")
+	}
+	if err := json.Unmarshal(*input, &req); err != nil {
+		return nil, err
+	}
+	if req.Issuer == "" || req.ClientID == "" {
+		return nil, errors.New("missing OpenID Connect issuer or client ID")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, signInTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, s.client)
+
+	metadata, err := discover(ctx, s.client, req.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &oauth2.Config{
+		ClientID: req.ClientID,
+		Scopes:   req.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       metadata.AuthorizationEndpoint,
+			TokenURL:      metadata.TokenEndpoint,
+			DeviceAuthURL: metadata.DeviceAuthorizationEndpoint,
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+	}
+
+	var token *oauth2.Token
+	if config.Endpoint.DeviceAuthURL != "" {
+		token, err = s.deviceFlow(ctx, config)
+	} else {
+		token, err = s.authorizationCodeFlow(ctx, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return nil, errors.New("identity provider did not issue an ID token")
+	}
+	return json.Marshal(response{IDToken: idToken})
+}
+
+// deviceFlow signs the user in with the device authorization flow (RFC 8628)
+func (s *signIn) deviceFlow(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	deviceAuth, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	if deviceAuth.VerificationURIComplete != "" {
+		fmt.Fprintf(s.out, "To sign in, visit %s and check that it shows the code %s\n",
+			deviceAuth.VerificationURIComplete, deviceAuth.UserCode)
+		s.open(deviceAuth.VerificationURIComplete)
+	} else {
+		fmt.Fprintf(s.out, "To sign in, visit %s and enter the code %s\n",
+			deviceAuth.VerificationURI, deviceAuth.UserCode)
+		s.open(deviceAuth.VerificationURI)
+	}
+
+	token, err := config.DeviceAccessToken(ctx, deviceAuth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign in: %w", err)
+	}
+	return token, nil
+}
+
+// authorizationCodeFlow signs the user in with the authorization code flow
+// with PKCE (RFC 7636), and receives the code on a local address that the
+// identity provider redirects the browser of the user to.
+func (s *signIn) authorizationCodeFlow(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the sign in redirect: %w", err)
+	}
+	config.RedirectURL = "http://" + listener.Addr().String() + callbackPath
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "Invalid sign in state.", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			http.Error(w, "Sign in failed.", http.StatusUnauthorized)
+			errs <- fmt.Errorf("failed to sign in: %s %s", query.Get("error"), query.Get("error_description"))
+		default:
+			fmt.Fprintln(w, "Signed in to Bacalhau. You can close this window.")
+			codes <- query.Get("code")
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: time.Minute}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	authURL := config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	fmt.Fprintf(s.out, "To sign in, open %s\n", authURL)
+	s.open(authURL)
+
+	select {
+	case code := <-codes:
+		token, err := config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign in: %w", err)
+		}
+		return token, nil
+	case err := <-errs:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *signIn) open(url string) {
+	if s.visit != nil {
+		go s.visit(url)
+	}
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,70 @@
+//go:build unit || !integration
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signInAs returns a sign in that visits the URLs it is given like a browser
+func signInAs(out io.Writer) *signIn {
+	return &signIn{
+		client: http.DefaultClient,
+		out:    out,
+		visit: func(url string) {
+			res, err := http.Get(url)
+			if err == nil {
+				res.Body.Close()
+			}
+		},
+	}
+}
+
+func signInRequest(t *testing.T, issuer *testIssuer) *json.RawMessage {
+	params, err := json.Marshal(request{Issuer: issuer.URL(), ClientID: testClientID, Scopes: []string{"openid"}})
+	require.NoError(t, err)
+	raw := json.RawMessage(params)
+	return &raw
+}
+
+func TestDeviceFlow(t *testing.T) {
+	issuer, authenticator := setup(t)
+	var out strings.Builder
+
+	res, err := signInAs(&out).respond(context.Background(), signInRequest(t, issuer))
+	require.NoError(t, err)
+	require.Contains(t, out.String(), testUserCode)
+
+	auth, err := authenticator.Authenticate(context.Background(), res)
+	require.NoError(t, err)
+	require.True(t, auth.Success, auth.Reason)
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	issuer, authenticator := setup(t)
+	issuer.deviceFlow = false
+	var out strings.Builder
+
+	res, err := signInAs(&out).respond(context.Background(), signInRequest(t, issuer))
+	require.NoError(t, err)
+	require.Contains(t, out.String(), issuer.URL()+"/authorize")
+
+	auth, err := authenticator.Authenticate(context.Background(), res)
+	require.NoError(t, err)
+	require.True(t, auth.Success, auth.Reason)
+}
+
+func TestSignInWithWrongClient(t *testing.T) {
+	issuer := newTestIssuer(t)
+	params := json.RawMessage(`{"Issuer": "` + issuer.URL() + `", "ClientID": "other-client"}`)
+
+	_, err := signInAs(io.Discard).respond(context.Background(), &params)
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// providerMetadata is the subset of the OpenID Connect discovery document of an
+// identity provider that is needed to sign users in and to verify their tokens.
+type providerMetadata struct {
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+}
+
+// discover fetches the discovery document of the issuer, and checks that it
+// is the document of the issuer.
+func discover(ctx context.Context, client *http.Client, issuer string) (*providerMetadata, error) {
+	url := strings.TrimSuffix(issuer, "/") + discoveryPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OpenID configuration of %s: %w", issuer, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch OpenID configuration of %s: %s", issuer, res.Status)
+	}
+
+	var metadata providerMetadata
+	if err := json.NewDecoder(res.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("invalid OpenID configuration of %s: %w", issuer, err)
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("OpenID configuration of %s is for another issuer %s", issuer, metadata.Issuer)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,225 @@
+//go:build unit || !integration
+
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID   = "bacalhau"
+	testDeviceCode = "device-code"
+	testUserCode   = "ABCD-EFGH"
+	testAuthCode   = "auth-code"
+)
+
+// testIssuer is a local stand-in for an OpenID Connect identity provider. It
+// signs users in immediately as the configured subject and groups.
+type testIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    jwk.Key
+
+	// Whether to advertise the device authorization endpoint
+	deviceFlow bool
+	subject    string
+	groups     []string
+
+	mu             sync.Mutex
+	jwksRequests   int
+	deviceApproved bool
+	challenge      string
+	redirectURI    string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{t: t, subject: "alice", deviceFlow: true}
+	issuer.key = newSigningKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/device", issuer.device)
+	mux.HandleFunc("/device/verify", issuer.verifyDevice)
+	mux.HandleFunc("/authorize", issuer.authorize)
+	mux.HandleFunc("/token", issuer.token)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func newSigningKey(t *testing.T, keyID string) jwk.Key {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := jwk.New(rsaKey)
+	require.NoError(t, err)
+	require.NoError(t, key.Set(jwk.KeyIDKey, keyID))
+	return key
+}
+
+func (i *testIssuer) URL() string {
+	return i.server.URL
+}
+
+// rotateKey makes the issuer sign tokens with a new key
+func (i *testIssuer) rotateKey(keyID string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.key = newSigningKey(i.t, keyID)
+}
+
+// keyRequests returns how many times the signing keys of the issuer were fetched
+func (i *testIssuer) keyRequests() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.jwksRequests
+}
+
+// idToken returns an ID token signed by the issuer, modified by the options
+func (i *testIssuer) idToken(options ...func(jwt.Token)) string {
+	token := jwt.New()
+	require.NoError(i.t, token.Set(jwt.IssuerKey, i.URL()))
+	require.NoError(i.t, token.Set(jwt.SubjectKey, i.subject))
+	require.NoError(i.t, token.Set(jwt.AudienceKey, []string{testClientID}))
+	require.NoError(i.t, token.Set(jwt.IssuedAtKey, time.Now()))
+	require.NoError(i.t, token.Set(jwt.ExpirationKey, time.Now().Add(time.Hour)))
+	if i.groups != nil {
+		require.NoError(i.t, token.Set("groups", i.groups))
+	}
+	for _, option := range options {
+		option(token)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	signed, err := jwt.Sign(token, jwa.RS256, i.key)
+	require.NoError(i.t, err)
+	return string(signed)
+}
+
+func (i *testIssuer) discovery(w http.ResponseWriter, _ *http.Request) {
+	metadata := providerMetadata{
+		Issuer:                i.URL(),
+		JWKSURI:               i.URL() + "/jwks",
+		AuthorizationEndpoint: i.URL() + "/authorize",
+		TokenEndpoint:         i.URL() + "/token",
+	}
+	if i.deviceFlow {
+		metadata.DeviceAuthorizationEndpoint = i.URL() + "/device"
+	}
+	writeJSON(w, http.StatusOK, metadata)
+}
+
+func (i *testIssuer) jwks(w http.ResponseWriter, _ *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.jwksRequests++
+	key, err := jwk.PublicKeyOf(i.key)
+	require.NoError(i.t, err)
+	set := jwk.NewSet()
+	set.Add(key)
+	writeJSON(w, http.StatusOK, set)
+}
+
+func (i *testIssuer) device(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("client_id") != testClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"device_code":      testDeviceCode,
+		"user_code":        testUserCode,
+		"verification_uri": i.URL() + "/device/verify",
+		"expires_in":       60,
+		"interval":         1,
+	})
+}
+
+// verifyDevice is visited by the user to approve the device
+func (i *testIssuer) verifyDevice(w http.ResponseWriter, r *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.deviceApproved = true
+	w.WriteHeader(http.StatusOK)
+}
+
+// authorize is visited by the user, who is redirected back to the client
+func (i *testIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != testClientID || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	i.mu.Lock()
+	i.challenge = query.Get("code_challenge")
+	i.redirectURI = query.Get("redirect_uri")
+	i.mu.Unlock()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	require.NoError(i.t, err)
+	redirect.RawQuery = url.Values{"code": {testAuthCode}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (i *testIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("client_id") != testClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	i.mu.Lock()
+	approved, challenge, redirectURI := i.deviceApproved, i.challenge, i.redirectURI
+	i.mu.Unlock()
+
+	switch r.FormValue("grant_type") {
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		if r.FormValue("device_code") != testDeviceCode {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		} else if !approved {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+			return
+		}
+	case "authorization_code":
+		verifier := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if r.FormValue("code") != testAuthCode ||
+			r.FormValue("redirect_uri") != redirectURI ||
+			base64.RawURLEncoding.EncodeToString(verifier[:]) != challenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     i.idToken(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
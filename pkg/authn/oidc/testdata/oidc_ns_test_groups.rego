@@ -0,0 +1,69 @@
+package bacalhau.authn
+
+import rego.v1
+
+# Implements a policy where users that sign in with the identity provider are
+# permitted access. Anonymous users are not permitted.
+#
+# Modify `group_namespaces` to control what namespaces the groups of the
+# identity provider can access. The groups of a user are read from the `groups`
+# claim of their ID token, which some identity providers only include when a
+# `groups` scope is requested.
+
+now := time.now_ns() / 1000
+
+one_day := time.add_date(time.now_ns(), 0, 0, 1) / 1000
+
+# group_namespaces should be a map of group names to the namespaces their
+# members can access and the access they have, e.g.
+#   "data-engineering": {"data": full_access},
+#   "admins": {"*": full_access},
+group_namespaces := {
+	"data-engineering": {"data": full_access},
+	"analysts": {"data": read_only, "reports": full_access},
+}
+
+user_groups := object.get(input.claims, "groups", [])
+
+permission_bits := [namespace_read, namespace_write, namespace_download, namespace_cancel, namespace_exec]
+
+# The access to each namespace granted by the groups of the user, combining the
+# access of all of their groups
+group_access[ns] := sum({bit |
+	some group in user_groups
+	some bit in permission_bits
+	bits.and(object.get(group_namespaces[group], ns, 0), bit) != 0
+}) if {
+	some group in user_groups
+	some ns, _ in group_namespaces[group]
+}
+
+token := io.jwt.encode_sign(
+	{
+		"typ": "JWT",
+		"alg": "RS256",
+	},
+	{
+		"iss": input.nodeId,
+		"sub": input.claims.sub,
+		"aud": [input.nodeId],
+		"iat": now,
+		"exp": one_day,
+		"ns": object.union(
+			# Read-only access to all namespaces
+			{"*": read_only},
+			# Access granted by the groups of the user
+			group_access,
+		),
+	},
+	input.signingKey,
+)
+
+namespace_read     := 1
+namespace_write    := 2
+namespace_download := 4
+namespace_cancel   := 8
+namespace_exec     := 16
+
+read_only := bits.or(namespace_read, namespace_download)
+full_access := bits.or(bits.or(bits.or(namespace_write, namespace_cancel), namespace_exec), read_only)
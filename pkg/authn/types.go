@@ -43,6 +43,10 @@ const (
 
 	// An authentication method that asks the user to supply some credentials.
 	MethodTypeAsk MethodType = "ask"
+
+	// An authentication method that asks the user to sign in with an OpenID
+	// Connect identity provider and supply the ID token it issues.
+	MethodTypeOIDC MethodType = "oidc"
 )
 
 // Requirement represents information about how to authenticate using a
@@ -4,6 +4,8 @@ import rego.v1
 default allow = false
 
 job_endpoint := ["api", "v1", "orchestrator", "jobs"]
+apikey_endpoint := ["api", "v1", "orchestrator", "apikeys"]
+audit_endpoint := ["api", "v1", "orchestrator", "audit"]
 
 # https://developer.mozilla.org/en-US/docs/Glossary/Safe/HTTP
 http_safe_methods := ["GET", "HEAD", "OPTIONS"]
@@ -14,6 +16,23 @@ is_legacy_api if {
     input.http.path[2] == "requester"
 }
 
+# Executing commands in running jobs, e.g. /api/v1/orchestrator/jobs/<id>/exec
+is_exec_endpoint if {
+    count(input.http.path) == 6
+    array.slice(input.http.path, 0, 4) == job_endpoint
+    input.http.path[5] == "exec"
+}
+
+# Managing API keys, e.g. /api/v1/orchestrator/apikeys/<name>
+is_apikey_endpoint if {
+    array.slice(input.http.path, 0, 4) == apikey_endpoint
+}
+
+# Reading the audit log, e.g. /api/v1/orchestrator/audit
+is_audit_endpoint if {
+    array.slice(input.http.path, 0, 4) == audit_endpoint
+}
+
 # Allow writing jobs if the access token has namespace write access
 allow if {
     input.http.path == job_endpoint
@@ -30,10 +49,44 @@ allow if {
     namespace_readable(job_namespace_perms)
 }
 
+# Allow executing commands in running jobs if the access token has namespace exec access
+allow if {
+    is_exec_endpoint
+    input.http.method in http_safe_methods
+
+    namespace_executable(exec_namespace_perms)
+}
+
+# Executing commands in running jobs must be permitted explicitly, as exec
+# requests are made with safe methods
+default allow_exec := false
+allow_exec if {
+    is_exec_endpoint
+    namespace_executable(exec_namespace_perms)
+}
+
+# Allow managing API keys if the access token is an administrator's, as keys
+# grant access to the namespaces that they are scoped to
+allow if {
+    is_apikey_endpoint
+    token_admin
+}
+
+# Allow reading the audit log if the access token is an administrator's, as it
+# records the actions of all principals
+allow if {
+    is_audit_endpoint
+    input.http.method in http_safe_methods
+    token_admin
+}
+
 # Allow reading all other endpoints, inclduing by users who don't have a token
 allow if {
     input.http.path != job_endpoint
     not is_legacy_api
+    not is_exec_endpoint
+    not is_apikey_endpoint
+    not is_audit_endpoint
     input.http.method in http_safe_methods
 }
 
@@ -70,6 +123,11 @@ token_valid if {
     not input.http.headers["Authorization"]
 }
 
+# Administrators have full access to all namespaces
+token_admin if {
+    bits.and(token_namespaces["*"], admin_access) == admin_access
+}
+
 # The permissions the access token grants on the job namespace
 job_namespace_perms := bits.or(token_namespaces[job_namespace], token_namespaces["*"]) if {
     token_namespaces[job_namespace]
@@ -91,6 +149,21 @@ job_namespace := ns if {
     ns := jobRequest["namespace"]
 }
 
+# The namespace of the job that commands are executed in, which the
+# orchestrator checks against the namespace of the job
+default exec_namespace := "default"
+exec_namespace := input.http.query.namespace[0]
+
+# The permissions the access token grants on the namespace of the job that commands are executed in
+exec_namespace_perms := bits.or(token_namespaces[exec_namespace], token_namespaces["*"]) if {
+    token_namespaces[exec_namespace]
+    token_namespaces["*"]
+} else := token_namespaces[exec_namespace] if {
+    token_namespaces[exec_namespace]
+} else := token_namespaces["*"] if {
+    token_namespaces["*"]
+}
+
 # The list of namespaces from the verified access token
 token_namespaces := ns if {
     authHeader := input.http.headers["Authorization"][0]
@@ -106,3 +179,7 @@ namespace_readable(namespace)     if { bits.and(namespace, 1) != 0 }
 namespace_writable(namespace)     if { bits.and(namespace, 2) != 0 }
 namespace_downloadable(namespace) if { bits.and(namespace, 4) != 0 }
 namespace_cancelable(namespace)   if { bits.and(namespace, 8) != 0 }
+namespace_executable(namespace)   if { bits.and(namespace, 16) != 0 }
+
+# Reading, writing, downloading and cancelling
+admin_access := 15
@@ -3,3 +3,4 @@ import rego.v1
 
 allow := true
 token_valid := true
+allow_exec := true
@@ -13,7 +13,9 @@ import (
 	"strings"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
+	"github.com/lestrrat-go/jwx/jwa"
 	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jwt"
 	"github.com/samber/lo"
 )
 
@@ -22,19 +24,25 @@ import (
 // `bacalhau.authz` and then by defining a rule `allow`. See
 // `policy_test_allow.rego` for a minimal example.
 //
+// Executing commands in running jobs additionally requires the `allow_exec`
+// rule to be `true`, so that policies only permit it explicitly.
+//
 //nolint:gosec  // not hardcoded creds
 const (
 	AuthzAllowRule      = "bacalhau.authz.allow"
 	AuthzTokenValidRule = "bacalhau.authz.token_valid"
+	AuthzExecRule       = "bacalhau.authz.allow_exec"
 )
 
 type policyAuthorizer struct {
 	policy *policy.Policy
+	key    *rsa.PublicKey
 	keyset string
 	nodeID string
 
 	allowQuery      policy.Query[authzData, bool]
 	tokenValidQuery policy.Query[authzData, bool]
+	execQuery       policy.Query[authzData, bool]
 }
 
 type httpData struct {
@@ -65,9 +73,11 @@ var policies embed.FS
 func NewPolicyAuthorizer(authzPolicy *policy.Policy, key *rsa.PublicKey, nodeID string) Authorizer {
 	p := &policyAuthorizer{
 		policy:          authzPolicy,
+		key:             key,
 		nodeID:          nodeID,
 		allowQuery:      policy.AddQuery[authzData, bool](authzPolicy, AuthzAllowRule),
 		tokenValidQuery: policy.AddQuery[authzData, bool](authzPolicy, AuthzTokenValidRule),
+		execQuery:       policy.AddQuery[authzData, bool](authzPolicy, AuthzExecRule),
 	}
 
 	if key != nil {
@@ -125,7 +135,59 @@ func (authorizer *policyAuthorizer) Authorize(req *http.Request) (Authorization,
 
 	approved, aErr := authorizer.allowQuery(req.Context(), in)
 	tokenValid, tvErr := authorizer.tokenValidQuery(req.Context(), in)
-	return Authorization{Approved: approved, TokenValid: tokenValid}, errors.Join(aErr, tvErr)
+	exec, eErr := authorizer.execQuery(req.Context(), in)
+	// policies that do not define the exec rule do not permit executing commands
+	if errors.Is(eErr, policy.ErrNoResult) {
+		eErr = nil
+	}
+	result := Authorization{
+		Approved:   approved,
+		TokenValid: tokenValid,
+		Exec:       exec,
+	}
+	if token := authorizer.token(req); token != nil {
+		result.Principal = token.Subject()
+		result.Namespaces = namespaces(token)
+	}
+	return result, errors.Join(aErr, tvErr, eErr)
+}
+
+// token returns the bearer token of the request if it was signed by this node
+// and has not expired, so that actions can be attributed to it regardless of
+// what the policy makes of the token.
+func (authorizer *policyAuthorizer) token(req *http.Request) jwt.Token {
+	if authorizer.key == nil {
+		return nil
+	}
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return nil
+	}
+	parsed, err := jwt.ParseString(token, jwt.WithVerify(jwa.RS256, authorizer.key), jwt.WithValidate(true))
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+// namespaces returns the namespace permissions of the "ns" claim of the token,
+// ignoring any that are not numbers.
+func namespaces(token jwt.Token) map[string]int {
+	claim, ok := token.Get("ns")
+	if !ok {
+		return nil
+	}
+	values, ok := claim.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]int, len(values))
+	for namespace, value := range values {
+		if permissions, ok := value.(float64); ok {
+			result[namespace] = int(permissions)
+		}
+	}
+	return result
 }
 
 // AlwaysAllowPolicy is a policy that will always permit access, irrespective of
@@ -8,6 +8,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
@@ -24,6 +25,7 @@ const (
 	NamespaceWritable     uint8 = 0b0010
 	NamespaceDownloadable uint8 = 0b0100
 	NamespaceCancellable  uint8 = 0b1000
+	NamespaceExecutable   uint8 = 0b10000
 )
 
 func getJWTWithNamespace(t *testing.T, signingKey crypto.PrivateKey, namespace string, perms uint8) string {
@@ -73,6 +75,30 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 			"other", "other", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/nodes", sameKey, require.True},
 		{"deny writing other APIs",
 			"other", "other", "test", NamespaceNoPermission, http.MethodDelete, "/api/v1/orchestrator/nodes", sameKey, require.False},
+		{"allow exec with namespace exec access",
+			"test", "test", "test", NamespaceExecutable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=test", sameKey, require.True},
+		{"deny exec without namespace exec access",
+			"test", "test", "test", NamespaceReadable | NamespaceWritable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=test", sameKey, require.False},
+		{"deny exec in alternative namespace",
+			"other", "other", "test", NamespaceExecutable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=other", sameKey, require.False},
+		{"deny exec without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec", sameKey, require.False},
+		{"allow managing API keys as administrator",
+			"test", "test", "*", NamespaceReadable | NamespaceWritable | NamespaceDownloadable | NamespaceCancellable, http.MethodPost, "/api/v1/orchestrator/apikeys", sameKey, require.True},
+		{"allow revoking API keys as administrator",
+			"test", "test", "*", NamespaceReadable | NamespaceWritable | NamespaceDownloadable | NamespaceCancellable, http.MethodDelete, "/api/v1/orchestrator/apikeys/ci", sameKey, require.True},
+		{"deny managing API keys with access to a single namespace",
+			"test", "test", "test", NamespaceReadable | NamespaceWritable | NamespaceDownloadable | NamespaceCancellable, http.MethodPost, "/api/v1/orchestrator/apikeys", sameKey, require.False},
+		{"deny managing API keys with read-only access",
+			"test", "test", "*", NamespaceReadable | NamespaceDownloadable, http.MethodPost, "/api/v1/orchestrator/apikeys", sameKey, require.False},
+		{"deny listing API keys without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/apikeys", sameKey, require.False},
+		{"allow reading the audit log as administrator",
+			"test", "test", "*", NamespaceReadable | NamespaceWritable | NamespaceDownloadable | NamespaceCancellable, http.MethodGet, "/api/v1/orchestrator/audit", sameKey, require.True},
+		{"deny reading the audit log with access to a single namespace",
+			"test", "test", "test", NamespaceReadable | NamespaceWritable | NamespaceDownloadable | NamespaceCancellable, http.MethodGet, "/api/v1/orchestrator/audit", sameKey, require.False},
+		{"deny reading the audit log without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/audit", sameKey, require.False},
 		{"deny signed by wrong key",
 			"test", "test", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/jobs", newKey, require.False},
 	}
@@ -109,6 +135,10 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 			result, err := authorizer.Authorize(request)
 			require.NoError(t, err)
 			testcase.checker(t, result.Approved)
+			// executing commands is also permitted explicitly
+			if strings.Contains(testcase.path, "/exec") {
+				testcase.checker(t, result.Exec)
+			}
 		})
 	}
 }
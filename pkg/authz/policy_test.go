@@ -3,10 +3,15 @@
 package authz
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"net/http"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -92,3 +97,54 @@ func TestPolicyEvaluatedAgainstDeniedHTTPRequest(t *testing.T) {
 	require.False(t, badResult.Approved)
 	require.True(t, badResult.TokenValid)
 }
+
+func TestExecRequiresExplicitPermission(t *testing.T) {
+	// a policy that allows all requests made with GET, which exec requests are
+	readOnly, err := policy.FromFS(fstest.MapFS{"policy.rego": {Data: []byte(`package bacalhau.authz
+import rego.v1
+
+default allow := false
+allow if input.http.method == "GET"
+token_valid := true
+`)}}, "policy.rego")
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec", nil)
+	require.NoError(t, err)
+
+	result, err := NewPolicyAuthorizer(readOnly, nil, "").Authorize(request)
+	require.NoError(t, err)
+	require.True(t, result.Approved)
+	require.False(t, result.Exec)
+
+	result, err = AlwaysAllow.Authorize(request)
+	require.NoError(t, err)
+	require.True(t, result.Exec)
+}
+
+func TestPrincipalIsSubjectOfTokensSignedByNode(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authorizer := NewPolicyAuthorizer(AlwaysAllowPolicy, &signingKey.PublicKey, "test-node")
+	principal := func(key *rsa.PrivateKey, claims jwt.MapClaims) string {
+		request, err := http.NewRequest(http.MethodPut, "/api/v1/orchestrator/jobs", nil)
+		require.NoError(t, err)
+		if key != nil {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		result, err := authorizer.Authorize(request)
+		require.NoError(t, err)
+		require.True(t, result.Approved)
+		return result.Principal
+	}
+
+	require.Equal(t, "alice", principal(signingKey, jwt.MapClaims{"sub": "alice"}))
+	require.Empty(t, principal(nil, nil))
+	require.Empty(t, principal(otherKey, jwt.MapClaims{"sub": "alice"}))
+	require.Empty(t, principal(signingKey, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Hour).Unix()}))
+}
@@ -1,6 +1,7 @@
 package authz
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -8,8 +9,33 @@ type Authorization struct {
 	Approved   bool   `json:"approved"`
 	TokenValid bool   `json:"tokenValid"`
 	Reason     string `json:"reason"`
+	// Principal is the subject of the access token of the request, if the token
+	// was signed by this node and has not expired. It is empty otherwise.
+	Principal string `json:"principal,omitempty"`
+	// Namespaces are the namespace permissions that the access token of the
+	// request grants, keyed by namespace as in its "ns" claim. They are only set
+	// for the same tokens as the principal.
+	Namespaces map[string]int `json:"namespaces,omitempty"`
+	// Exec is whether the policy explicitly permits executing commands in running
+	// jobs. It is required on top of the approval of exec requests, which are made
+	// with safe methods that policies may allow without further checks.
+	Exec bool `json:"exec,omitempty"`
 }
 
 type Authorizer interface {
 	Authorize(req *http.Request) (Authorization, error)
 }
+
+type authorizationContextKey struct{}
+
+// ContextWithAuthorization returns a context carrying the authorization of a
+// request, so that handlers can check what the access token of the request grants.
+func ContextWithAuthorization(ctx context.Context, authorization Authorization) context.Context {
+	return context.WithValue(ctx, authorizationContextKey{}, authorization)
+}
+
+// AuthorizationFromContext returns the authorization carried by the context, if any.
+func AuthorizationFromContext(ctx context.Context) (Authorization, bool) {
+	authorization, ok := ctx.Value(authorizationContextKey{}).(Authorization)
+	return authorization, ok
+}
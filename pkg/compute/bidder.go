@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/logger"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 
@@ -27,6 +28,8 @@ type BidderParams struct {
 	Executor         Executor
 	Callback         Callback
 	GetApproveURL    func() *url.URL
+	// Secrets holds the values of the secrets of the executions started without approval
+	Secrets *secrets.Cache
 }
 
 type Bidder struct {
@@ -36,6 +39,7 @@ type Bidder struct {
 	executor        Executor
 	callback        Callback
 	getApproveURL   func() *url.URL
+	secrets         *secrets.Cache
 
 	semanticStrategy []bidstrategy.SemanticBidStrategy
 	resourceStrategy []bidstrategy.ResourceBidStrategy
@@ -49,6 +53,7 @@ func NewBidder(params BidderParams) Bidder {
 		getApproveURL:    params.GetApproveURL,
 		executor:         params.Executor,
 		callback:         params.Callback,
+		secrets:          params.Secrets,
 		semanticStrategy: params.SemanticStrategy,
 		resourceStrategy: params.ResourceStrategy,
 	}
@@ -85,6 +90,10 @@ type BidderRequest struct {
 
 	// ResourceUsage specifies the requested resources for this execution
 	ResourceUsage *models.Resources
+
+	// Secrets holds the values of the secrets referenced by the task, if the execution is
+	// started without waiting for approval
+	Secrets SecretValues
 }
 
 // TODO: evaluate the need for async bidding and marking bids as waiting
@@ -112,7 +121,8 @@ func (b Bidder) RunBidding(ctx context.Context, bidRequest *BidderRequest) {
 		})
 		return
 	}
-	b.handleBidResult(ctx, bidResult, bidRequest.SourcePeerID, bidRequest.WaitForApproval, bidRequest.Execution)
+	b.handleBidResult(ctx, bidResult, bidRequest.SourcePeerID, bidRequest.WaitForApproval, bidRequest.Execution,
+		bidRequest.Secrets)
 }
 
 type bidStrategyResponse struct {
@@ -128,6 +138,7 @@ func (b Bidder) handleBidResult(
 	targetPeer string,
 	waitForApproval bool,
 	execution *models.Execution,
+	secretValues SecretValues,
 ) {
 	var (
 		routingMetadata = RoutingMetadata{
@@ -180,6 +191,7 @@ func (b Bidder) handleBidResult(
 			handleComputeFailure(ctx, err, "failed to create execution state")
 			return
 		}
+		b.secrets.Put(execution.ID, secretValues)
 		if err := b.executor.Run(ctx, *localExecution); err != nil {
 			// no need to check for run errors as they are already handled by the executor.
 			log.Ctx(ctx).Error().Err(err).Msg("failed to run execution")
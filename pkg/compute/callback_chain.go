@@ -41,5 +41,23 @@ func (c ChainedCallback) OnComputeFailure(ctx context.Context, err ComputeError)
 	}
 }
 
+func (c ChainedCallback) OnCheckpoint(ctx context.Context, result CheckpointResult) {
+	for _, callback := range c.callbacks {
+		callback.OnCheckpoint(ctx, result)
+	}
+}
+
+func (c ChainedCallback) OnHealthCheck(ctx context.Context, result HealthCheckResult) {
+	for _, callback := range c.callbacks {
+		callback.OnHealthCheck(ctx, result)
+	}
+}
+
+func (c ChainedCallback) OnPortsPublished(ctx context.Context, result PortsResult) {
+	for _, callback := range c.callbacks {
+		callback.OnPortsPublished(ctx, result)
+	}
+}
+
 // compile-time interface check
 var _ Callback = &ChainedCallback{}
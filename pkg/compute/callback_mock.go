@@ -7,6 +7,9 @@ type CallbackMock struct {
 	OnCancelCompleteHandler func(ctx context.Context, result CancelResult)
 	OnComputeFailureHandler func(ctx context.Context, err ComputeError)
 	OnRunCompleteHandler    func(ctx context.Context, result RunResult)
+	OnCheckpointHandler     func(ctx context.Context, result CheckpointResult)
+	OnHealthCheckHandler    func(ctx context.Context, result HealthCheckResult)
+	OnPortsPublishedHandler func(ctx context.Context, result PortsResult)
 }
 
 // OnBidComplete implements Callback
@@ -37,4 +40,25 @@ func (c CallbackMock) OnRunComplete(ctx context.Context, result RunResult) {
 	}
 }
 
+// OnCheckpoint implements Callback
+func (c CallbackMock) OnCheckpoint(ctx context.Context, result CheckpointResult) {
+	if c.OnCheckpointHandler != nil {
+		c.OnCheckpointHandler(ctx, result)
+	}
+}
+
+// OnHealthCheck implements Callback
+func (c CallbackMock) OnHealthCheck(ctx context.Context, result HealthCheckResult) {
+	if c.OnHealthCheckHandler != nil {
+		c.OnHealthCheckHandler(ctx, result)
+	}
+}
+
+// OnPortsPublished implements Callback
+func (c CallbackMock) OnPortsPublished(ctx context.Context, result PortsResult) {
+	if c.OnPortsPublishedHandler != nil {
+		c.OnPortsPublishedHandler(ctx, result)
+	}
+}
+
 var _ Callback = CallbackMock{}
@@ -0,0 +1,245 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/util/targzip"
+)
+
+// splitCheckpointInput separates the input source an execution resumes its checkpoint from,
+// if any, from the other input sources of its task.
+func splitCheckpointInput(inputSources []*models.InputSource) ([]*models.InputSource, *models.InputSource) {
+	var checkpoint *models.InputSource
+	inputs := make([]*models.InputSource, 0, len(inputSources))
+	for _, input := range inputSources {
+		if input.Alias == models.CheckpointInputAlias {
+			checkpoint = input
+			continue
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, checkpoint
+}
+
+// restoreCheckpoint downloads the checkpoint an execution resumes from and restores it into
+// the checkpoint directory of its results, which the executor mounts at the checkpoint path
+// of the task. A checkpoint that was already restored, e.g. before the compute node
+// restarted, is kept as the execution may have updated it since.
+func restoreCheckpoint(
+	ctx context.Context,
+	strgprovider storage.StorageProvider,
+	storageDirectory string,
+	input *models.InputSource,
+	resultsDir string,
+	maxFileSize datasize.ByteSize,
+) error {
+	target := filepath.Join(resultsDir, models.CheckpointResultName)
+	if _, err := os.Lstat(target); err == nil {
+		return nil
+	}
+
+	volumes, err := storage.ParallelPrepareStorage(ctx, strgprovider, storageDirectory, input)
+	if err != nil {
+		return fmt.Errorf("downloading checkpoint: %w", err)
+	}
+	defer func() {
+		if err := storage.ParallelCleanStorage(ctx, strgprovider, volumes); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to clean up downloaded checkpoint")
+		}
+	}()
+	if err = restoreCheckpointFrom(volumes[0].Volume.Source, target, maxFileSize); err != nil {
+		_ = os.RemoveAll(target)
+		return fmt.Errorf("restoring checkpoint: %w", err)
+	}
+	return nil
+}
+
+// restoreCheckpointFrom restores a downloaded checkpoint into the target directory. Publishers
+// that upload archives, such as S3 and local, produce a single tar.gz file that is extracted,
+// whereas publishers of directories, such as IPFS, produce the checkpoint directory itself.
+func restoreCheckpointFrom(source string, target string, maxFileSize datasize.ByteSize) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	archive := source
+	if info.IsDir() {
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			return err
+		}
+		archive = ""
+		if len(entries) == 1 && entries[0].Type().IsRegular() {
+			archive = filepath.Join(source, entries[0].Name())
+		}
+	}
+	if archive != "" && isTarGzip(archive) {
+		file, err := os.Open(archive)
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+		if maxFileSize == 0 {
+			maxFileSize = datasize.ByteSize(math.MaxInt64)
+		}
+		return targzip.DecompressWithMaxBytes(file, target, maxFileSize)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("checkpoint %s is neither a directory nor a tar.gz archive", source)
+	}
+	return copyCheckpoint(source, target)
+}
+
+func isTarGzip(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close() //nolint:errcheck
+	_, err = targzip.UncompressedSize(file)
+	return err == nil
+}
+
+// copyCheckpoint copies a checkpoint directory, without following the links it contains as
+// the checkpoint is written by the job.
+func copyCheckpoint(source, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(target, rel)
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(dst, models.DownloadFolderPerm)
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, dst)
+		case entry.Type().IsRegular():
+			return copyCheckpointFile(path, dst)
+		default:
+			// devices, sockets and pipes are not part of checkpoints
+			return nil
+		}
+	})
+}
+
+func copyCheckpointFile(source, target string) error {
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// publishCheckpoints periodically publishes the checkpoint of the execution in the background,
+// if its task has checkpoints enabled. The returned function stops publishing checkpoints.
+func (e *BaseExecutor) publishCheckpoints(ctx context.Context, state store.LocalExecutionState) func() {
+	task := state.Execution.Job.Task()
+	if task.Checkpoint == nil || task.Publisher.IsEmpty() {
+		return func() {}
+	}
+	publishCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(task.Checkpoint.GetInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-publishCtx.Done():
+				return
+			case <-ticker.C:
+				if err := e.publishCheckpoint(publishCtx, state); err != nil {
+					log.Ctx(ctx).Warn().Err(err).Msg("failed to publish checkpoint")
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// publishCheckpoint publishes a snapshot of the checkpoint directory of the execution and
+// notifies the requester, so that the execution can be resumed from it on another node.
+// Nothing is published while the checkpoint directory is empty.
+func (e *BaseExecutor) publishCheckpoint(ctx context.Context, state store.LocalExecutionState) error {
+	execution := state.Execution
+	dir := filepath.Join(e.resultsPath.getResultsDir(execution.ID), models.CheckpointResultName)
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+
+	// the checkpoint is copied so that the job can keep updating it while it is published
+	createTime := time.Now().UTC()
+	snapshot, err := os.MkdirTemp(e.storageDirectory, "checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(snapshot); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to remove checkpoint snapshot at %s", snapshot)
+		}
+	}()
+	if err = copyCheckpoint(dir, snapshot); err != nil {
+		return fmt.Errorf("failed to snapshot checkpoint: %w", err)
+	}
+
+	jobPublisher, err := e.publishers.Get(ctx, execution.Job.Task().Publisher.Type)
+	if err != nil {
+		return fmt.Errorf("failed to get publisher %s: %w", execution.Job.Task().Publisher.Type, err)
+	}
+	source, err := jobPublisher.PublishResult(ctx, execution, snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to publish checkpoint: %w", err)
+	}
+	log.Ctx(ctx).Debug().Msg("Checkpoint published")
+
+	e.callback.OnCheckpoint(ctx, CheckpointResult{
+		ExecutionMetadata: NewExecutionMetadata(execution),
+		RoutingMetadata: RoutingMetadata{
+			SourcePeerID: e.ID,
+			TargetPeerID: state.RequesterNodeID,
+		},
+		Checkpoint: &models.ExecutionCheckpoint{
+			Source:     &source,
+			CreateTime: createTime.UnixNano(),
+		},
+	})
+	return nil
+}
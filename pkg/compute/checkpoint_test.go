@@ -0,0 +1,93 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/targzip"
+)
+
+type CheckpointTestSuite struct {
+	suite.Suite
+	checkpoint string
+}
+
+func TestCheckpointTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckpointTestSuite))
+}
+
+func (s *CheckpointTestSuite) SetupTest() {
+	s.checkpoint = s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.checkpoint, "nested"), 0755))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.checkpoint, "nested", "state"), []byte("step 1"), 0600))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.checkpoint, "epoch"), []byte("1"), 0600))
+}
+
+func (s *CheckpointTestSuite) requireRestored(target string) {
+	data, err := os.ReadFile(filepath.Join(target, "nested", "state"))
+	s.Require().NoError(err)
+	s.Equal("step 1", string(data))
+	data, err = os.ReadFile(filepath.Join(target, "epoch"))
+	s.Require().NoError(err)
+	s.Equal("1", string(data))
+}
+
+func (s *CheckpointTestSuite) archive(dir string) {
+	var buf bytes.Buffer
+	s.Require().NoError(targzip.CompressWithoutPath(context.Background(), s.checkpoint, &buf))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "checkpoint.tgz"), buf.Bytes(), 0600))
+}
+
+func (s *CheckpointTestSuite) TestRestoreFromDirectory() {
+	target := filepath.Join(s.T().TempDir(), models.CheckpointResultName)
+	s.Require().NoError(restoreCheckpointFrom(s.checkpoint, target, 0))
+	s.requireRestored(target)
+}
+
+func (s *CheckpointTestSuite) TestRestoreFromArchive() {
+	downloaded := s.T().TempDir()
+	s.archive(downloaded)
+
+	// storage sources download archives either as a file or within a directory
+	target := filepath.Join(s.T().TempDir(), models.CheckpointResultName)
+	s.Require().NoError(restoreCheckpointFrom(filepath.Join(downloaded, "checkpoint.tgz"), target, 0))
+	s.requireRestored(target)
+
+	target = filepath.Join(s.T().TempDir(), models.CheckpointResultName)
+	s.Require().NoError(restoreCheckpointFrom(downloaded, target, 0))
+	s.requireRestored(target)
+}
+
+func (s *CheckpointTestSuite) TestRestoreLimitsFileSize() {
+	downloaded := s.T().TempDir()
+	s.archive(downloaded)
+	target := filepath.Join(s.T().TempDir(), models.CheckpointResultName)
+	s.Error(restoreCheckpointFrom(downloaded, target, 2))
+}
+
+func (s *CheckpointTestSuite) TestCopyDoesNotFollowLinks() {
+	s.Require().NoError(os.Symlink("/etc", filepath.Join(s.checkpoint, "host")))
+	target := filepath.Join(s.T().TempDir(), "snapshot")
+	s.Require().NoError(copyCheckpoint(s.checkpoint, target))
+	s.requireRestored(target)
+
+	info, err := os.Lstat(filepath.Join(target, "host"))
+	s.Require().NoError(err)
+	s.Equal(os.ModeSymlink, info.Mode().Type())
+}
+
+func (s *CheckpointTestSuite) TestSplitCheckpointInput() {
+	input := &models.InputSource{Alias: "data", Target: "/inputs"}
+	checkpoint := &models.InputSource{Alias: models.CheckpointInputAlias, Target: "/checkpoint"}
+	inputs, found := splitCheckpointInput([]*models.InputSource{input, checkpoint})
+	s.Equal([]*models.InputSource{input}, inputs)
+	s.Equal(checkpoint, found)
+}
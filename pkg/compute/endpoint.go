@@ -13,6 +13,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
@@ -23,7 +24,10 @@ type BaseEndpointParams struct {
 	UsageCalculator capacity.UsageCalculator
 	Bidder          Bidder
 	Executor        Executor
+	Executors       executor.ExecutorProvider
 	LogServer       *logstream.Server
+	// Secrets holds the values of the secrets delivered with the executions until they are done
+	Secrets *secrets.Cache
 }
 
 // Base implementation of Endpoint
@@ -33,7 +37,9 @@ type BaseEndpoint struct {
 	usageCalculator capacity.UsageCalculator
 	bidder          Bidder
 	executor        Executor
+	executors       executor.ExecutorProvider
 	logServer       *logstream.Server
+	secrets         *secrets.Cache
 }
 
 func NewBaseEndpoint(params BaseEndpointParams) BaseEndpoint {
@@ -43,7 +49,9 @@ func NewBaseEndpoint(params BaseEndpointParams) BaseEndpoint {
 		usageCalculator: params.UsageCalculator,
 		bidder:          params.Bidder,
 		executor:        params.Executor,
+		executors:       params.Executors,
 		logServer:       params.LogServer,
+		secrets:         params.Secrets,
 	}
 }
 
@@ -73,6 +81,7 @@ func (s BaseEndpoint) AskForBid(ctx context.Context, request AskForBidRequest) (
 		Execution:       request.Execution,
 		WaitForApproval: request.WaitForApproval,
 		ResourceUsage:   parsedUsage,
+		Secrets:         request.Secrets,
 	})
 
 	return AskForBidResponse{ExecutionMetadata: ExecutionMetadata{
@@ -100,6 +109,8 @@ func (s BaseEndpoint) BidAccepted(ctx context.Context, request BidAcceptedReques
 	// Increment the number of jobs accepted by this compute node:
 	jobsAccepted.Add(ctx, 1)
 
+	s.secrets.Put(request.ExecutionID, request.Secrets)
+
 	err = s.executor.Run(ctx, localExecutionState)
 	if err != nil {
 		return BidAcceptedResponse{}, err
@@ -146,6 +157,7 @@ func (s BaseEndpoint) CancelExecution(ctx context.Context, request CancelExecuti
 			return CancelExecutionResponse{}, err
 		}
 	}
+	s.secrets.Remove(request.ExecutionID)
 
 	err = s.executionStore.UpdateExecutionState(ctx, store.UpdateExecutionStateRequest{
 		ExecutionID: request.ExecutionID,
@@ -166,8 +178,38 @@ func (s BaseEndpoint) ExecutionLogs(ctx context.Context, request ExecutionLogsRe
 		ExecutionID: request.ExecutionID,
 		Tail:        request.Tail,
 		Follow:      request.Follow,
+		Offset:      request.Offset,
+		TailLines:   request.TailLines,
+		Since:       request.Since,
 	})
 }
 
+func (s BaseEndpoint) Exec(ctx context.Context, request ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if len(request.Command) == 0 {
+		return nil, fmt.Errorf("no command to execute in execution %s", request.ExecutionID)
+	}
+	localExecutionState, err := s.executionStore.GetExecution(ctx, request.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	if localExecutionState.State != store.ExecutionStateRunning {
+		return nil, fmt.Errorf("cannot exec in execution %s in state %s",
+			request.ExecutionID, localExecutionState.State)
+	}
+
+	engineType := localExecutionState.Execution.Job.Task().Engine.Type
+	exec, err := s.executors.Get(ctx, engineType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find executor for engine: %s. %w", engineType, err)
+	}
+	execer, ok := exec.(executor.Execer)
+	if !ok {
+		return nil, fmt.Errorf("engine %s does not support exec", engineType)
+	}
+	log.Ctx(ctx).Info().Msgf("executing %v in execution %s", request.Command, request.ExecutionID)
+	return runExec(ctx, execer, request), nil
+}
+
 // Compile-time interface check:
 var _ Endpoint = (*BaseEndpoint)(nil)
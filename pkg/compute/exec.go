@@ -0,0 +1,108 @@
+package compute
+
+import (
+	"context"
+	"io"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// execOutputBuffer is the number of outputs of an executed command that are buffered
+// before the command is blocked on writing more.
+const execOutputBuffer = 16
+
+// runExec runs the command of the request with the executor, forwarding the input of the
+// request to the command and streaming its output. The last result is either the exit code
+// of the command or the error that stopped it.
+func runExec(ctx context.Context, execer executor.Execer, request ExecRequest) <-chan *concurrency.AsyncResult[models.ExecOutput] {
+	ch := make(chan *concurrency.AsyncResult[models.ExecOutput], execOutputBuffer)
+	ctx, cancel := context.WithCancel(ctx)
+
+	stdinReader, stdinWriter := io.Pipe()
+	resize := make(chan models.TerminalSize)
+	execRequest := executor.ExecRequest{
+		ExecutionID: request.ExecutionID,
+		Command:     request.Command,
+		TTY:         request.TTY,
+		Size:        request.Size,
+		Resize:      resize,
+		Stdout:      &execOutputWriter{ctx: ctx, ch: ch, outputType: models.ExecutionLogTypeSTDOUT},
+		Stderr:      &execOutputWriter{ctx: ctx, ch: ch, outputType: models.ExecutionLogTypeSTDERR},
+	}
+	if request.Stdin {
+		execRequest.Stdin = stdinReader
+	}
+	go forwardExecInput(ctx, request, stdinWriter, resize)
+
+	go func() {
+		defer close(ch)
+		defer cancel()
+		// unblocks the executor if it is still reading the input of the command
+		defer stdinReader.Close()
+
+		exitCode, err := execer.Exec(ctx, execRequest)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msgf("exec in execution %s failed", request.ExecutionID)
+		}
+		result := concurrency.NewAsyncResult(models.ExecOutput{Exited: true, ExitCode: exitCode}, err)
+		select {
+		case <-ctx.Done():
+		case ch <- result:
+		}
+	}()
+	return ch
+}
+
+// forwardExecInput writes the input of the request to the standard input of the command and resizes
+// its terminal, until the input is closed or the command exits.
+func forwardExecInput(ctx context.Context, request ExecRequest, stdin *io.PipeWriter, resize chan<- models.TerminalSize) {
+	defer close(resize)
+	defer stdin.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case input, ok := <-request.Input:
+			if !ok {
+				return
+			}
+			if request.Stdin && len(input.Data) > 0 {
+				// errors mean that the input was closed, in which case the data is dropped
+				_, _ = stdin.Write(input.Data)
+			}
+			if input.EOF {
+				_ = stdin.Close()
+			}
+			if request.TTY && input.Resize != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case resize <- *input.Resize:
+				}
+			}
+		}
+	}
+}
+
+// execOutputWriter sends what is written to it as outputs of an executed command
+type execOutputWriter struct {
+	ctx        context.Context
+	ch         chan<- *concurrency.AsyncResult[models.ExecOutput]
+	outputType models.ExecutionLogType
+}
+
+func (w *execOutputWriter) Write(p []byte) (int, error) {
+	// the buffer is owned by the caller, and is copied as the output is sent asynchronously
+	data := make([]byte, len(p))
+	copy(data, p)
+	select {
+	case <-w.ctx.Done():
+		return 0, w.ctx.Err()
+	case w.ch <- concurrency.NewAsyncValue(models.ExecOutput{Type: w.outputType, Data: data}):
+		return len(p), nil
+	}
+}
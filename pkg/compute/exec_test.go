@@ -0,0 +1,156 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// catExecer echoes the standard input of the command to its output, and reports the
+// terminal sizes it received on stderr
+type catExecer struct {
+	err error
+}
+
+func (e *catExecer) Exec(ctx context.Context, request executor.ExecRequest) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	if request.TTY {
+		size := <-request.Resize
+		_, _ = fmt.Fprintf(request.Stderr, "%dx%d", size.Width, size.Height)
+	}
+	if request.Stdin != nil {
+		if _, err := io.Copy(request.Stdout, request.Stdin); err != nil {
+			return 0, err
+		}
+	}
+	return len(request.Command), nil
+}
+
+type ExecTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestExecTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecTestSuite))
+}
+
+func (s *ExecTestSuite) SetupTest() {
+	s.ctx = context.Background()
+}
+
+// collect reads the outputs until the command exits, and returns them with the exit code
+func (s *ExecTestSuite) collect(outputs <-chan *concurrency.AsyncResult[models.ExecOutput]) ([]models.ExecOutput, int) {
+	var collected []models.ExecOutput
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case output, ok := <-outputs:
+			s.Require().True(ok, "the outputs were closed before the command exited")
+			s.Require().NoError(output.Err)
+			if output.Value.Exited {
+				_, more := <-outputs
+				s.False(more)
+				return collected, output.Value.ExitCode
+			}
+			collected = append(collected, output.Value)
+		case <-timeout:
+			s.FailNow("timed out waiting for the command to exit")
+		}
+	}
+}
+
+func (s *ExecTestSuite) TestStdin() {
+	input := make(chan models.ExecInput, 3)
+	input <- models.ExecInput{Data: []byte("hello ")}
+	input <- models.ExecInput{Data: []byte("world")}
+	input <- models.ExecInput{EOF: true}
+
+	outputs := runExec(s.ctx, &catExecer{}, ExecRequest{
+		ExecutionID: "e-1",
+		Command:     []string{"cat"},
+		Stdin:       true,
+		Input:       input,
+	})
+	collected, exitCode := s.collect(outputs)
+	s.Equal(1, exitCode)
+
+	var stdout string
+	for _, output := range collected {
+		s.Equal(models.ExecutionLogTypeSTDOUT, output.Type)
+		stdout += string(output.Data)
+	}
+	s.Equal("hello world", stdout)
+}
+
+func (s *ExecTestSuite) TestInputIgnoredWithoutStdin() {
+	input := make(chan models.ExecInput, 1)
+	input <- models.ExecInput{Data: []byte("ignored")}
+
+	outputs := runExec(s.ctx, &catExecer{}, ExecRequest{
+		ExecutionID: "e-1",
+		Command:     []string{"sh", "-c", "true"},
+		Input:       input,
+	})
+	collected, exitCode := s.collect(outputs)
+	s.Equal(3, exitCode)
+	s.Empty(collected)
+}
+
+func (s *ExecTestSuite) TestResize() {
+	input := make(chan models.ExecInput, 1)
+	input <- models.ExecInput{Resize: &models.TerminalSize{Height: 24, Width: 80}}
+
+	outputs := runExec(s.ctx, &catExecer{}, ExecRequest{
+		ExecutionID: "e-1",
+		Command:     []string{"sh"},
+		TTY:         true,
+		Input:       input,
+	})
+	collected, _ := s.collect(outputs)
+	s.Require().Len(collected, 1)
+	s.Equal(models.ExecutionLogTypeSTDERR, collected[0].Type)
+	s.Equal("80x24", string(collected[0].Data))
+}
+
+func (s *ExecTestSuite) TestError() {
+	outputs := runExec(s.ctx, &catExecer{err: errors.New("no such container")}, ExecRequest{
+		ExecutionID: "e-1",
+		Command:     []string{"sh"},
+	})
+	output := <-outputs
+	s.Require().NotNil(output)
+	s.ErrorContains(output.Err, "no such container")
+	_, more := <-outputs
+	s.False(more)
+}
+
+func (s *ExecTestSuite) TestCancel() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	// the command blocks reading its input, which is never closed
+	outputs := runExec(ctx, &catExecer{}, ExecRequest{
+		ExecutionID: "e-1",
+		Command:     []string{"cat"},
+		Stdin:       true,
+		Input:       make(chan models.ExecInput),
+	})
+	cancel()
+	select {
+	case <-outputs:
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for the command to stop")
+	}
+}
@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/c2h5oh/datasize"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
@@ -23,6 +29,9 @@ import (
 
 const StorageDirectoryPerms = 0755
 
+// logCaptureTimeout is how long to wait for the logs of a finished execution to be persisted
+const logCaptureTimeout = 10 * time.Second
+
 type BaseExecutorParams struct {
 	ID                     string
 	Callback               Callback
@@ -33,6 +42,14 @@ type BaseExecutorParams struct {
 	ResultsPath            ResultsPath
 	Publishers             publisher.PublisherProvider
 	FailureInjectionConfig model.FailureInjectionComputeConfig
+	// LogStore persists the logs of executions. Optional.
+	LogStore *logstream.LogStore
+	// PublishLogs publishes the persisted logs of executions along with their results
+	PublishLogs bool
+	// PortsAddress is the host at which the ports published by executions are reachable
+	PortsAddress string
+	// Secrets holds the values of the secrets delivered with the executions
+	Secrets *secrets.Cache
 }
 
 // BaseExecutor is the base implementation for backend service.
@@ -47,6 +64,10 @@ type BaseExecutor struct {
 	publishers       publisher.PublisherProvider
 	resultsPath      ResultsPath
 	failureInjection model.FailureInjectionComputeConfig
+	logStore         *logstream.LogStore
+	publishLogs      bool
+	portsAddress     string
+	secrets          *secrets.Cache
 }
 
 func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
@@ -60,6 +81,10 @@ func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
 		publishers:       params.Publishers,
 		failureInjection: params.FailureInjectionConfig,
 		resultsPath:      params.ResultsPath,
+		logStore:         params.LogStore,
+		publishLogs:      params.PublishLogs,
+		portsAddress:     params.PortsAddress,
+		secrets:          params.Secrets,
 	}
 }
 
@@ -131,7 +156,22 @@ func PrepareRunArguments(
 ) (*executor.RunCommandRequest, InputCleanupFn, error) {
 	var cleanupFuncs []func(context.Context) error
 
-	inputVolumes, inputCleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, execution.Job.Task().InputSources...)
+	// the checkpoint the execution resumes from is restored into its output, rather than mounted as an input
+	inputSources := execution.Job.Task().InputSources
+	outputs := execution.Job.Task().ResultPaths
+	if checkpoint := execution.Job.Task().Checkpoint; checkpoint != nil {
+		var checkpointInput *models.InputSource
+		inputSources, checkpointInput = splitCheckpointInput(inputSources)
+		outputs = append(slices.Clone(outputs), checkpoint.ResultPath())
+		if checkpointInput != nil {
+			maxFileSize := datasize.ByteSize(execution.TotalAllocatedResources().Disk)
+			if err := restoreCheckpoint(ctx, strgprovider, storageDirectory, checkpointInput, resultsDir, maxFileSize); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	inputVolumes, inputCleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, inputSources...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -176,31 +216,32 @@ func PrepareRunArguments(
 	}
 
 	return &executor.RunCommandRequest{
-			JobID:        execution.Job.ID,
-			ExecutionID:  execution.ID,
-			Resources:    execution.TotalAllocatedResources(),
-			Network:      execution.Job.Task().Network,
-			Outputs:      execution.Job.Task().ResultPaths,
-			Inputs:       inputVolumes,
-			ResultsDir:   resultsDir,
-			EngineParams: engineArgs,
-			OutputLimits: executor.OutputLimits{
-				MaxStdoutFileLength:   system.MaxStdoutFileLength,
-				MaxStdoutReturnLength: system.MaxStdoutReturnLength,
-				MaxStderrFileLength:   system.MaxStderrFileLength,
-				MaxStderrReturnLength: system.MaxStderrReturnLength,
-			},
-		}, func(ctx context.Context) error {
-			log.Ctx(ctx).Info().Str("execution", execution.ID).Msg("cleaning up execution")
-			var cleanupErr error
-			for _, cleanupFunc := range cleanupFuncs {
-				if err := cleanupFunc(ctx); err != nil {
-					log.Ctx(ctx).Error().Err(err).Str("execution", execution.ID).Msg("cleaning up execution")
-					cleanupErr = errors.Join(cleanupErr, err)
-				}
+		JobID:        execution.Job.ID,
+		ExecutionID:  execution.ID,
+		Resources:    execution.TotalAllocatedResources(),
+		Network:      execution.Job.Task().Network,
+		Outputs:      outputs,
+		Inputs:       inputVolumes,
+		ResultsDir:   resultsDir,
+		EngineParams: engineArgs,
+		Env:          execution.Job.Task().Env,
+		OutputLimits: executor.OutputLimits{
+			MaxStdoutFileLength:   system.MaxStdoutFileLength,
+			MaxStdoutReturnLength: system.MaxStdoutReturnLength,
+			MaxStderrFileLength:   system.MaxStderrFileLength,
+			MaxStderrReturnLength: system.MaxStderrReturnLength,
+		},
+	}, func(ctx context.Context) error {
+		log.Ctx(ctx).Info().Str("execution", execution.ID).Msg("cleaning up execution")
+		var cleanupErr error
+		for _, cleanupFunc := range cleanupFuncs {
+			if err := cleanupFunc(ctx); err != nil {
+				log.Ctx(ctx).Error().Err(err).Str("execution", execution.ID).Msg("cleaning up execution")
+				cleanupErr = errors.Join(cleanupErr, err)
 			}
-			return cleanupErr
-		}, nil
+		}
+		return cleanupErr
+	}, nil
 }
 
 type StartResult struct {
@@ -241,6 +282,10 @@ func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *
 		result.Err = fmt.Errorf("preparing arguments: %w", err)
 		return result
 	}
+	if err = e.injectSecrets(execution, args); err != nil {
+		result.Err = err
+		return result
+	}
 
 	if err := e.store.UpdateExecutionState(ctx, store.UpdateExecutionStateRequest{
 		ExecutionID: execution.ID,
@@ -300,6 +345,8 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 
 	stopwatch := telemetry.Timer(ctx, jobDurationMilliseconds, state.Execution.Job.MetricAttributes()...)
 	topic := EventTopicExecutionRunning
+	// the secrets are no longer needed once the execution is done
+	defer e.secrets.Remove(execution.ID)
 	defer func() {
 		if err != nil {
 			e.handleFailure(ctx, state, err, topic)
@@ -334,7 +381,16 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		}
 	}
 
+	waitForLogs := e.captureLogs(ctx, execution, res.Err != nil)
+	defer waitForLogs()
+
+	stopPorts := e.publishPorts(ctx, state)
+	stopCheckpoints := e.publishCheckpoints(ctx, state)
+	stopHealthChecks := e.checkHealth(ctx, state)
 	result, err := e.Wait(ctx, state)
+	stopHealthChecks()
+	stopCheckpoints()
+	stopPorts()
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			// TODO(forrest) [correctness]:
@@ -356,8 +412,23 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 			log.Ctx(ctx).Info().Msg("execution timeout exceeded canceling execution")
 			return nil
 		}
+		if cause := context.Cause(ctx); ctx.Err() != nil && cause != ctx.Err() {
+			// The ExecutorBuffer cancels the context with a cause when it preempts the execution to make room
+			// for a higher priority one. Stop the execution and report the cause as the failure, using a context
+			// that is no longer canceled so that the failure can still be recorded and sent to the requester.
+			ctx = context.WithoutCancel(ctx)
+			if exe, getErr := e.executors.Get(ctx, execution.Job.Task().Engine.Type); getErr == nil {
+				if cancelErr := exe.Cancel(ctx, execution.ID); cancelErr != nil {
+					log.Ctx(ctx).Error().Err(cancelErr).Msg("failed to stop preempted execution")
+				}
+			}
+			return cause
+		}
 		return err
 	}
+	result.STDOUT = e.secrets.Redact(execution.ID, result.STDOUT)
+	result.STDERR = e.secrets.Redact(execution.ID, result.STDERR)
+	result.ErrorMsg = e.secrets.Redact(execution.ID, result.ErrorMsg)
 	if result.ErrorMsg != "" {
 		return fmt.Errorf("execution error: %s", result.ErrorMsg)
 	}
@@ -393,6 +464,24 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 			}
 		}()
 
+		// the checkpoint is only needed to resume the execution, and is not part of its results
+		if execution.Job.Task().Checkpoint != nil {
+			if err = os.RemoveAll(filepath.Join(resultsDir, models.CheckpointResultName)); err != nil {
+				return err
+			}
+		}
+
+		if err = e.redactResults(execution.ID, resultsDir); err != nil {
+			return err
+		}
+
+		if e.publishLogs {
+			waitForLogs()
+			if err = e.writeLogs(execution.ID, resultsDir); err != nil {
+				return err
+			}
+		}
+
 		publishedResult, err = e.publish(ctx, state, resultsDir)
 		if err != nil {
 			return err
@@ -421,6 +510,103 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 	return err
 }
 
+// captureLogs persists the logs of the execution in the background. The returned function waits
+// for the execution to stop writing logs, and stops the capture if it takes longer than logCaptureTimeout.
+func (e *BaseExecutor) captureLogs(ctx context.Context, execution *models.Execution, resumed bool) func() {
+	if e.logStore == nil {
+		return func() {}
+	}
+	// the capture outlives the run's context, so that logs written while the execution
+	// is being stopped are persisted too
+	captureCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jobExecutor, err := e.executors.Get(captureCtx, execution.Job.Task().Engine.Type)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to get executor to capture execution logs")
+			return
+		}
+		reader, err := jobExecutor.GetLogStream(captureCtx, executor.LogStreamRequest{
+			JobID:       execution.JobID,
+			ExecutionID: execution.ID,
+			// only capture new logs if the execution was already running, e.g. after a restart
+			Tail:   resumed && e.logStore.Has(execution.ID),
+			Follow: true,
+		})
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to open log stream to capture execution logs")
+			return
+		}
+		defer reader.Close() //nolint:errcheck
+		redacted := logstream.NewRedactingReader(reader, func(data string) string {
+			return e.secrets.Redact(execution.ID, data)
+		})
+		if err = e.logStore.Capture(captureCtx, execution.ID, redacted); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to capture execution logs")
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			defer cancel()
+			select {
+			case <-done:
+			case <-time.After(logCaptureTimeout):
+				log.Ctx(ctx).Warn().Msg("timed out waiting for execution logs to be persisted")
+			}
+		})
+	}
+}
+
+// writeLogs writes the persisted logs of the execution to the results directory, to be published with the results
+func (e *BaseExecutor) writeLogs(executionID string, resultsDir string) error {
+	if !e.logStore.Has(executionID) {
+		return nil
+	}
+	logsDir := filepath.Join(resultsDir, models.DownloadLogsFolderName)
+	if err := os.MkdirAll(logsDir, models.DownloadFolderPerm); err != nil {
+		return fmt.Errorf("failed to create logs folder: %w", err)
+	}
+	file, err := os.OpenFile(
+		filepath.Join(logsDir, models.DownloadFilenameLogs), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, models.DownloadFilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create logs file: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+	if err = e.logStore.Export(executionID, file); err != nil {
+		return fmt.Errorf("failed to write execution logs: %w", err)
+	}
+	return nil
+}
+
+// redactResults replaces the values of the secrets of the execution in the stdout and stderr
+// written to its results, so that they are not published. The logs are already redacted when
+// they are captured.
+func (e *BaseExecutor) redactResults(executionID string, resultsDir string) error {
+	if _, ok := e.secrets.Get(executionID); !ok {
+		return nil
+	}
+	for _, name := range []string{models.DownloadFilenameStdout, models.DownloadFilenameStderr} {
+		path := filepath.Join(resultsDir, name)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to read %s to redact secrets: %w", name, err)
+		}
+		redacted := e.secrets.Redact(executionID, string(data))
+		if redacted == string(data) {
+			continue
+		}
+		if err = os.WriteFile(path, []byte(redacted), models.DownloadFilePerm); err != nil {
+			return fmt.Errorf("failed to redact secrets from %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // Publish the result of an execution after it has been verified.
 func (e *BaseExecutor) publish(ctx context.Context, localExecutionState store.LocalExecutionState,
 	resultFolder string) (publishedResult models.SpecConfig, err error) {
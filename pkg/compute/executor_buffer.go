@@ -2,6 +2,8 @@ package compute
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,6 +19,10 @@ import (
 type bufferTask struct {
 	localExecutionState store.LocalExecutionState
 	enqueuedAt          time.Time
+	// cancel stops a running task, with the cause reported back to the delegate executor
+	cancel context.CancelCauseFunc
+	// preempted is set once the running task has been chosen to make room for a higher priority execution
+	preempted bool
 }
 
 func newBufferTask(execution store.LocalExecutionState) *bufferTask {
@@ -26,6 +32,15 @@ func newBufferTask(execution store.LocalExecutionState) *bufferTask {
 	}
 }
 
+// newExecutionPreemptedError returns the error reported for an execution that was stopped to make room for a
+// higher priority execution. It is retryable and flagged as preempted so that the orchestrator reschedules the
+// execution instead of counting it as a failed attempt.
+func newExecutionPreemptedError(preemptedBy string) error {
+	return models.NewBaseError("execution preempted by higher priority execution %s", preemptedBy).
+		WithRetryable().
+		WithDetails(map[string]string{models.DetailsKeyPreempted: "true"})
+}
+
 type ExecutorBufferParams struct {
 	ID                         string
 	DelegateExecutor           Executor
@@ -33,14 +48,22 @@ type ExecutorBufferParams struct {
 	RunningCapacityTracker     capacity.Tracker
 	EnqueuedCapacityTracker    capacity.Tracker
 	DefaultJobExecutionTimeout time.Duration
+	// EnablePreemption allows the buffer to stop running executions of preemptible jobs
+	// to make room for queued executions with a higher priority.
+	EnablePreemption bool
 }
 
 // ExecutorBuffer is a backend.Executor implementation that buffers executions locally until enough capacity is
 // available to be able to run them. The buffer accepts a delegate backend.Executor that will be used to run the jobs.
-// The buffer is implemented as a FIFO queue, where the order of the executions is determined by the order in which
-// they were enqueued. However, an execution with high resource usage requirements might be skipped if there are newer
-// jobs with lower resource usage requirements that can be executed immediately. This is done to improve utilization
-// of compute nodes, though it might result in starvation and should be re-evaluated in the future.
+// The buffer is implemented as a priority queue, where executions of higher priority jobs run first, and executions
+// with the same priority run in the order in which they were enqueued. However, an execution with high resource usage
+// requirements might be skipped if there are newer jobs with lower resource usage requirements that can be executed
+// immediately. This is done to improve utilization of compute nodes, though it might result in starvation and should
+// be re-evaluated in the future.
+//
+// When preemption is enabled and there is not enough capacity for the highest priority queued execution, running
+// executions of preemptible jobs with a lower priority are stopped to make room for it. Preempted executions are
+// reported as failed with a retryable error, so that the orchestrator can reschedule them elsewhere.
 type ExecutorBuffer struct {
 	ID                         string
 	runningCapacity            capacity.Tracker
@@ -50,6 +73,7 @@ type ExecutorBuffer struct {
 	running                    map[string]*bufferTask
 	queuedTasks                *collections.HashedPriorityQueue[string, *bufferTask]
 	defaultJobExecutionTimeout time.Duration
+	enablePreemption           bool
 	mu                         sync.Mutex
 }
 
@@ -66,6 +90,7 @@ func NewExecutorBuffer(params ExecutorBufferParams) *ExecutorBuffer {
 		callback:                   params.Callback,
 		running:                    make(map[string]*bufferTask),
 		defaultJobExecutionTimeout: params.DefaultJobExecutionTimeout,
+		enablePreemption:           params.EnablePreemption,
 		queuedTasks:                collections.NewHashedPriorityQueue[string, *bufferTask](indexer),
 	}
 
@@ -151,6 +176,13 @@ func (s *ExecutorBuffer) doRun(ctx context.Context, task *bufferTask) {
 
 	select {
 	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// the execution was preempted. The delegate executor stops it and reports the failure,
+			// so we only need to wait for it to return before freeing up its capacity.
+			log.Ctx(ctx).Info().Str("ID", task.localExecutionState.Execution.ID).Msg("Execution preempted")
+			<-ch
+			break
+		}
 		log.Ctx(ctx).Info().Str("ID", task.localExecutionState.Execution.ID).Dur("Timeout", timeout).Msg("Execution timed out")
 		s.callback.OnCancelComplete(ctx, CancelResult{
 			ExecutionMetadata: NewExecutionMetadata(task.localExecutionState.Execution),
@@ -213,7 +245,73 @@ func (s *ExecutorBuffer) deque() {
 		execID := task.localExecutionState.Execution.ID
 		s.running[execID] = task
 
-		go s.doRun(logger.ContextWithNodeIDLogger(context.Background(), s.ID), task)
+		ctx, cancel := context.WithCancelCause(logger.ContextWithNodeIDLogger(context.Background(), s.ID))
+		task.cancel = cancel
+		go s.doRun(ctx, task)
+	}
+
+	if s.enablePreemption {
+		s.preempt(ctx)
+	}
+}
+
+// preempt stops running executions of preemptible jobs with a lower priority than the next queued execution,
+// if the next execution cannot run otherwise. Executions with the lowest priority, and then the most recently
+// started ones, are preempted first. Nothing is preempted if stopping all candidates would still not free up
+// enough capacity. It is called by deque, where a lock is already held.
+func (s *ExecutorBuffer) preempt(ctx context.Context) {
+	next := s.queuedTasks.Peek()
+	if next == nil {
+		return
+	}
+	required := *next.Value.localExecutionState.Execution.TotalAllocatedResources()
+
+	// capacity of executions that were already preempted will be freed once they stop
+	available := s.runningCapacity.GetAvailableCapacity(ctx)
+	candidates := make([]*bufferTask, 0, len(s.running))
+	for _, task := range s.running {
+		job := task.localExecutionState.Execution.Job
+		if task.preempted {
+			available = *available.Add(*task.localExecutionState.Execution.TotalAllocatedResources())
+			continue
+		}
+		if job.Preemptible && int64(job.Priority) < next.Priority && task.cancel != nil {
+			candidates = append(candidates, task)
+		}
+	}
+	if required.LessThanEq(available) || len(candidates) == 0 {
+		return
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		pi := candidates[i].localExecutionState.Execution.Job.Priority
+		pj := candidates[j].localExecutionState.Execution.Job.Priority
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[i].enqueuedAt.After(candidates[j].enqueuedAt)
+	})
+
+	var victims []*bufferTask
+	for _, task := range candidates {
+		victims = append(victims, task)
+		available = *available.Add(*task.localExecutionState.Execution.TotalAllocatedResources())
+		if required.LessThanEq(available) {
+			break
+		}
+	}
+	if !required.LessThanEq(available) {
+		return
+	}
+
+	nextID := next.Value.localExecutionState.Execution.ID
+	for _, task := range victims {
+		log.Ctx(ctx).Info().
+			Str("ID", task.localExecutionState.Execution.ID).
+			Str("PreemptedBy", nextID).
+			Msg("Preempting execution to make room for a higher priority execution")
+		task.preempted = true
+		task.cancel(newExecutionPreemptedError(nextID))
 	}
 }
 
@@ -0,0 +1,170 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ExecutorBufferPreemptTestSuite struct {
+	suite.Suite
+	buffer    *ExecutorBuffer
+	preempted map[string]error
+	now       time.Time
+}
+
+func TestExecutorBufferPreemptTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorBufferPreemptTestSuite))
+}
+
+func (s *ExecutorBufferPreemptTestSuite) SetupTest() {
+	s.buffer = NewExecutorBuffer(ExecutorBufferParams{
+		ID:                      "node",
+		RunningCapacityTracker:  capacity.NewLocalTracker(capacity.LocalTrackerParams{MaxCapacity: models.Resources{CPU: 4}}),
+		EnqueuedCapacityTracker: capacity.NewLocalTracker(capacity.LocalTrackerParams{MaxCapacity: models.Resources{CPU: 4}}),
+		EnablePreemption:        true,
+	})
+	s.preempted = make(map[string]error)
+	s.now = time.Now()
+}
+
+// newTask returns a buffer task of a job with the given priority that requires the given CPU
+func (s *ExecutorBufferPreemptTestSuite) newTask(priority int, preemptible bool, cpu float64) *bufferTask {
+	job := mock.Job()
+	job.Priority = priority
+	job.Preemptible = preemptible
+	execution := mock.ExecutionForJob(job)
+	execution.AllocateResources(job.Task().Name, models.Resources{CPU: cpu})
+	return newBufferTask(*store.NewLocalExecutionState(execution, "requester"))
+}
+
+// run adds the task to the running executions as if it was started at the given offset from now
+func (s *ExecutorBufferPreemptTestSuite) run(task *bufferTask, startedAfter time.Duration) string {
+	ctx := context.Background()
+	id := task.localExecutionState.Execution.ID
+	task.enqueuedAt = s.now.Add(startedAfter)
+	task.cancel = func(cause error) {
+		s.preempted[id] = cause
+	}
+	s.Require().NotNil(s.buffer.runningCapacity.AddIfHasCapacity(ctx, *task.localExecutionState.Execution.TotalAllocatedResources()))
+	s.buffer.running[id] = task
+	return id
+}
+
+// enqueue queues the task without running it
+func (s *ExecutorBufferPreemptTestSuite) enqueue(task *bufferTask) string {
+	s.buffer.queuedTasks.Enqueue(task, int64(task.localExecutionState.Execution.Job.Priority))
+	return task.localExecutionState.Execution.ID
+}
+
+func (s *ExecutorBufferPreemptTestSuite) preemptedIDs() []string {
+	ids := make([]string, 0, len(s.preempted))
+	for id := range s.preempted {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestPreemptsLowestPriorityFirst() {
+	medium := s.run(s.newTask(20, true, 2), 0)
+	low := s.run(s.newTask(10, true, 2), 0)
+	next := s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.ElementsMatch([]string{low}, s.preemptedIDs())
+	s.NotContains(s.preempted, medium)
+
+	var preemptedErr *models.BaseError
+	s.Require().ErrorAs(s.preempted[low], &preemptedErr)
+	s.True(preemptedErr.Retryable())
+	s.Equal("true", preemptedErr.Details()[models.DetailsKeyPreempted])
+	s.Contains(preemptedErr.Error(), next)
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestPreemptsMostRecentlyStartedFirstWithinPriority() {
+	older := s.run(s.newTask(10, true, 2), -time.Minute)
+	newer := s.run(s.newTask(10, true, 2), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.ElementsMatch([]string{newer}, s.preemptedIDs())
+	s.NotContains(s.preempted, older)
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestDoesNotPreemptEqualOrHigherPriority() {
+	s.run(s.newTask(50, true, 2), 0)
+	s.run(s.newTask(60, true, 2), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.Empty(s.preempted)
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestDoesNotPreemptNonPreemptibleJobs() {
+	s.run(s.newTask(10, false, 2), 0)
+	s.run(s.newTask(10, false, 2), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.Empty(s.preempted)
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestFreesExactlyEnoughResources() {
+	// one CPU is free, so preempting the lowest priority execution is enough to run the next one
+	first := s.run(s.newTask(10, true, 1), 0)
+	s.run(s.newTask(20, true, 1), 0)
+	s.run(s.newTask(30, true, 1), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.ElementsMatch([]string{first}, s.preemptedIDs())
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestPreemptsSeveralExecutionsWhenNeeded() {
+	first := s.run(s.newTask(10, true, 1), 0)
+	second := s.run(s.newTask(20, true, 1), 0)
+	s.run(s.newTask(30, true, 2), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.ElementsMatch([]string{first, second}, s.preemptedIDs())
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestDoesNotPreemptIfNotEnoughCanBeFreed() {
+	s.run(s.newTask(10, true, 1), 0)
+	s.run(s.newTask(60, false, 3), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.Empty(s.preempted)
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestCountsAlreadyPreemptedExecutionsAsFreed() {
+	first := s.run(s.newTask(10, true, 2), 0)
+	s.run(s.newTask(20, true, 2), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.ElementsMatch([]string{first}, s.preemptedIDs())
+
+	// the first execution has not stopped yet, but its capacity is already accounted for
+	s.buffer.preempt(context.Background())
+	s.ElementsMatch([]string{first}, s.preemptedIDs())
+}
+
+func (s *ExecutorBufferPreemptTestSuite) TestDoesNotPreemptIfNextExecutionFits() {
+	s.run(s.newTask(10, true, 2), 0)
+	s.enqueue(s.newTask(50, false, 2))
+
+	s.buffer.preempt(context.Background())
+	s.Empty(s.preempted)
+}
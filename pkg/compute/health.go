@@ -0,0 +1,209 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// healthProbe runs a health check once against a running execution, and returns an error if it fails
+type healthProbe func(ctx context.Context) error
+
+// newHealthProbe returns a probe running the health check against the execution, or an error
+// if the executor running the execution does not support the type of the health check.
+func newHealthProbe(jobExecutor executor.Executor, executionID string, check *models.HealthCheck) (healthProbe, error) {
+	switch check.Type {
+	case models.HealthCheckHTTP, models.HealthCheckTCP:
+		addresser, ok := jobExecutor.(executor.Addresser)
+		if !ok {
+			return nil, fmt.Errorf("executor does not support %s health checks", check.Type)
+		}
+		address := func(ctx context.Context) (string, error) {
+			host, err := addresser.Address(ctx, executionID)
+			if err != nil {
+				return "", err
+			}
+			return net.JoinHostPort(host, strconv.Itoa(check.Port)), nil
+		}
+		if check.Type == models.HealthCheckTCP {
+			return func(ctx context.Context) error {
+				addr, err := address(ctx)
+				if err != nil {
+					return err
+				}
+				var dialer net.Dialer
+				conn, err := dialer.DialContext(ctx, "tcp", addr)
+				if err != nil {
+					return err
+				}
+				return conn.Close()
+			}, nil
+		}
+		// the check targets the execution directly, without the proxy of the compute node, and
+		// redirects are successful responses rather than followed
+		client := &http.Client{
+			Transport: &http.Transport{DisableKeepAlives: true},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		return func(ctx context.Context) error {
+			addr, err := address(ctx)
+			if err != nil {
+				return err
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+check.Path, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close() //nolint:errcheck
+			if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+				return fmt.Errorf("health check returned status %d", resp.StatusCode)
+			}
+			return nil
+		}, nil
+	case models.HealthCheckCommand:
+		execer, ok := jobExecutor.(executor.Execer)
+		if !ok {
+			return nil, fmt.Errorf("executor does not support %s health checks", check.Type)
+		}
+		return func(ctx context.Context) error {
+			exitCode, err := execer.Exec(ctx, executor.ExecRequest{
+				ExecutionID: executionID,
+				Command:     check.Command,
+				Stdout:      discard{},
+				Stderr:      discard{},
+			})
+			if err != nil {
+				return err
+			}
+			if exitCode != 0 {
+				return fmt.Errorf("health check command exited with code %d", exitCode)
+			}
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown health check type %q", check.Type)
+	}
+}
+
+// discard is a writer discarding the output of health check commands
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// healthTracker tracks the results of the health checks of an execution, and returns the new
+// health of the execution every time it changes. An execution becomes healthy as soon as a check
+// passes, and unhealthy once the failure threshold of consecutive checks failed.
+type healthTracker struct {
+	failureThreshold int
+	failures         int
+	status           models.ExecutionHealthStatus
+}
+
+func newHealthTracker(failureThreshold int) *healthTracker {
+	return &healthTracker{failureThreshold: failureThreshold}
+}
+
+// record records the result of a health check, and returns the new health of the execution if
+// it changed, or nil otherwise
+func (t *healthTracker) record(err error, checkTime time.Time) *models.ExecutionHealth {
+	if err == nil {
+		t.failures = 0
+		if t.status == models.ExecutionHealthy {
+			return nil
+		}
+		t.status = models.ExecutionHealthy
+		return &models.ExecutionHealth{
+			Status:    models.ExecutionHealthy,
+			Message:   "health check passed",
+			CheckTime: checkTime.UnixNano(),
+		}
+	}
+
+	t.failures++
+	if t.failures < t.failureThreshold || t.status == models.ExecutionUnhealthy {
+		return nil
+	}
+	t.status = models.ExecutionUnhealthy
+	return &models.ExecutionHealth{
+		Status:    models.ExecutionUnhealthy,
+		Message:   fmt.Sprintf("health check failed %d times in a row: %s", t.failures, err),
+		CheckTime: checkTime.UnixNano(),
+	}
+}
+
+// checkHealth periodically runs the health check of the execution's task in the background, if
+// it has one, and notifies the requester every time the execution becomes healthy or unhealthy.
+// The returned function stops the health checks.
+func (e *BaseExecutor) checkHealth(ctx context.Context, state store.LocalExecutionState) func() {
+	execution := state.Execution
+	check := execution.Job.Task().HealthCheck
+	if check == nil {
+		return func() {}
+	}
+	jobExecutor, err := e.executors.Get(ctx, execution.Job.Task().Engine.Type)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to get executor to check the health of the execution")
+		return func() {}
+	}
+	probe, err := newHealthProbe(jobExecutor, execution.ID, check)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("health of the execution is not checked")
+		return func() {}
+	}
+
+	checkCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracker := newHealthTracker(check.FailureThreshold)
+		ticker := time.NewTicker(check.GetInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-checkCtx.Done():
+				return
+			case <-ticker.C:
+				probeCtx, cancelProbe := context.WithTimeout(checkCtx, check.GetTimeout())
+				err := probe(probeCtx)
+				cancelProbe()
+				if checkCtx.Err() != nil {
+					return
+				}
+				health := tracker.record(err, time.Now().UTC())
+				if health == nil {
+					continue
+				}
+				log.Ctx(ctx).Debug().Msgf("Execution is %s: %s", health.Status, health.Message)
+				e.callback.OnHealthCheck(ctx, HealthCheckResult{
+					ExecutionMetadata: NewExecutionMetadata(execution),
+					RoutingMetadata: RoutingMetadata{
+						SourcePeerID: e.ID,
+						TargetPeerID: state.RequesterNodeID,
+					},
+					Health: health,
+				})
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
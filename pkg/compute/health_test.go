@@ -0,0 +1,123 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// addressExecutor is an executor that only resolves the address of executions
+type addressExecutor struct {
+	executor.Executor
+	host string
+}
+
+func (e addressExecutor) Address(context.Context, string) (string, error) {
+	return e.host, nil
+}
+
+type HealthCheckTestSuite struct {
+	suite.Suite
+	executor addressExecutor
+}
+
+func TestHealthCheckTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthCheckTestSuite))
+}
+
+func (s *HealthCheckTestSuite) SetupTest() {
+	s.executor = addressExecutor{host: "127.0.0.1"}
+}
+
+func (s *HealthCheckTestSuite) serverPort(server *httptest.Server) int {
+	serverURL, err := url.Parse(server.URL)
+	s.Require().NoError(err)
+	port, err := strconv.Atoi(serverURL.Port())
+	s.Require().NoError(err)
+	return port
+}
+
+func (s *HealthCheckTestSuite) TestHTTPProbe() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	check := &models.HealthCheck{Type: models.HealthCheckHTTP, Port: s.serverPort(server), Path: "/health"}
+	probe, err := newHealthProbe(s.executor, "e-1", check)
+	s.Require().NoError(err)
+	s.NoError(probe(context.Background()))
+
+	check.Path = "/broken"
+	probe, err = newHealthProbe(s.executor, "e-1", check)
+	s.Require().NoError(err)
+	s.Error(probe(context.Background()))
+}
+
+func (s *HealthCheckTestSuite) TestTCPProbe() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	probe, err := newHealthProbe(s.executor, "e-1", &models.HealthCheck{Type: models.HealthCheckTCP, Port: port})
+	s.Require().NoError(err)
+	s.NoError(probe(context.Background()))
+
+	s.Require().NoError(listener.Close())
+	s.Error(probe(context.Background()))
+}
+
+func (s *HealthCheckTestSuite) TestUnsupportedProbe() {
+	// the executor cannot run commands inside executions
+	_, err := newHealthProbe(s.executor, "e-1", &models.HealthCheck{Type: models.HealthCheckCommand, Command: []string{"true"}})
+	s.Error(err)
+}
+
+func (s *HealthCheckTestSuite) TestTrackerReportsStatusChanges() {
+	tracker := newHealthTracker(2)
+	now := time.Now()
+	failure := errors.New("connection refused")
+
+	health := tracker.record(nil, now)
+	s.Require().NotNil(health)
+	s.Equal(models.ExecutionHealthy, health.Status)
+	s.Equal(now.UnixNano(), health.CheckTime)
+	s.Nil(tracker.record(nil, now), "unchanged health is not reported")
+
+	s.Nil(tracker.record(failure, now), "failures below the threshold are not reported")
+	health = tracker.record(failure, now)
+	s.Require().NotNil(health)
+	s.Equal(models.ExecutionUnhealthy, health.Status)
+	s.Contains(health.Message, "connection refused")
+	s.Nil(tracker.record(failure, now))
+
+	health = tracker.record(nil, now)
+	s.Require().NotNil(health)
+	s.Equal(models.ExecutionHealthy, health.Status)
+}
+
+func (s *HealthCheckTestSuite) TestTrackerResetsFailuresOnSuccess() {
+	tracker := newHealthTracker(2)
+	now := time.Now()
+	failure := errors.New("timeout")
+
+	s.NotNil(tracker.record(nil, now))
+	s.Nil(tracker.record(failure, now))
+	s.Nil(tracker.record(nil, now))
+	s.Nil(tracker.record(failure, now), "failures are counted consecutively")
+}
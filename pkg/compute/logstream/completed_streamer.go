@@ -12,17 +12,25 @@ import (
 
 type CompletedStreamerParams struct {
 	Execution *models.Execution
+	// Offset is the number of lines to skip from the start of the output
+	Offset int
+	// TailLines limits the output to the last N lines. Zero returns all of them.
+	TailLines int
 }
 
 // CompletedStreamer is a streamer for completed executions that streams the
 // output from the execution's RunOutput field to the channel.
 type CompletedStreamer struct {
 	execution *models.Execution
+	offset    int
+	tailLines int
 }
 
 func NewCompletedStreamer(params CompletedStreamerParams) *CompletedStreamer {
 	return &CompletedStreamer{
 		execution: params.Execution,
+		offset:    params.Offset,
+		tailLines: params.TailLines,
 	}
 }
 
@@ -31,17 +39,41 @@ func (s *CompletedStreamer) Stream(ctx context.Context) chan *concurrency.AsyncR
 	go func() {
 		defer close(ch)
 		if s.execution.RunOutput != nil {
-			s.process(ctx, ch, s.execution.RunOutput.STDOUT, models.ExecutionLogTypeSTDOUT)
-			s.process(ctx, ch, s.execution.RunOutput.STDERR, models.ExecutionLogTypeSTDERR)
+			skip := s.linesToSkip()
+			s.process(ctx, ch, s.execution.RunOutput.STDOUT, models.ExecutionLogTypeSTDOUT, &skip)
+			s.process(ctx, ch, s.execution.RunOutput.STDERR, models.ExecutionLogTypeSTDERR, &skip)
 		}
 	}()
 	return ch
 }
 
-func (s *CompletedStreamer) process(
-	ctx context.Context, ch chan *concurrency.AsyncResult[models.ExecutionLog], output string, typ models.ExecutionLogType) {
+// linesToSkip returns the number of lines to skip from the start of the output to honour the offset and tail
+func (s *CompletedStreamer) linesToSkip() int {
+	skip := s.offset
+	if s.tailLines > 0 {
+		total := countLines(s.execution.RunOutput.STDOUT) + countLines(s.execution.RunOutput.STDERR)
+		skip = max(skip, total-s.tailLines)
+	}
+	return skip
+}
+
+func countLines(output string) int {
+	count := 0
 	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
+		count++
+	}
+	return count
+}
+
+func (s *CompletedStreamer) process(ctx context.Context, ch chan *concurrency.AsyncResult[models.ExecutionLog],
+	output string, typ models.ExecutionLogType, skip *int) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if *skip > 0 {
+			*skip--
+			continue
+		}
 		select {
 		case <-ctx.Done():
 			// Context has been cancelled, stop processing
@@ -98,6 +98,30 @@ func (suite *CompletedStreamerSuite) TestSTDOUTAndSTDERR() {
 	suite.False(stdoutFirst, "STDOUT should be processed first")
 }
 
+// TestOffsetAndTailLines tests skipping lines from the start of the output and limiting it to the last lines
+func (suite *CompletedStreamerSuite) TestOffsetAndTailLines() {
+	execution := &models.Execution{
+		RunOutput: &models.RunCommandResult{
+			STDOUT: "stdout line 1\nstdout line 2\nstdout line 3",
+			STDERR: "stderr line 1\nstderr line 2",
+		},
+	}
+
+	read := func(params CompletedStreamerParams) []string {
+		params.Execution = execution
+		lines := make([]string, 0)
+		for log := range NewCompletedStreamer(params).Stream(context.Background()) {
+			suite.Nil(log.Err)
+			lines = append(lines, log.Value.Line)
+		}
+		return lines
+	}
+
+	suite.Equal([]string{"stdout line 3\n", "stderr line 1\n", "stderr line 2\n"}, read(CompletedStreamerParams{Offset: 2}))
+	suite.Equal([]string{"stderr line 1\n", "stderr line 2\n"}, read(CompletedStreamerParams{TailLines: 2}))
+	suite.Equal([]string{"stderr line 2\n"}, read(CompletedStreamerParams{Offset: 4, TailLines: 3}))
+}
+
 // TestContextCancellation tests the streamer's response to a cancelled context
 func (suite *CompletedStreamerSuite) TestContextCancellation() {
 	execution := &models.Execution{
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"strings"
 	"testing"
 	"time"
 
@@ -128,3 +129,29 @@ func (suite *LogStreamTestSuite) TestLogStream_CancelContext() {
 		suite.Fail("Timeout waiting for LiveStreamer channel to close after context cancellation")
 	}
 }
+
+func (suite *LogStreamTestSuite) TestLogStream_Redacted() {
+	data := suite.simulateLogStream([]simulatedLogEntry{
+		{Type: models.ExecutionLogTypeSTDOUT, Line: "token=s3cr3t"},
+		{Type: models.ExecutionLogTypeSTDERR, Line: "no secret here"},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logStream := NewLiveStreamer(LiveStreamerParams{
+		Reader: NewRedactingReader(bytes.NewReader(data), func(line string) string {
+			return strings.ReplaceAll(line, "s3cr3t", "[REDACTED]")
+		}),
+		Buffer: 10,
+	})
+
+	ch := logStream.Stream(ctx)
+	result := <-ch
+	suite.Require().NoError(result.Err)
+	suite.Equal(models.ExecutionLogTypeSTDOUT, result.Value.Type)
+	suite.Equal("token=[REDACTED]", result.Value.Line)
+	result = <-ch
+	suite.Require().NoError(result.Err)
+	suite.Equal(models.ExecutionLogTypeSTDERR, result.Value.Type)
+	suite.Equal("no secret here", result.Value.Line)
+}
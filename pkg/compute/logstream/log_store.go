@@ -0,0 +1,432 @@
+package logstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	DefaultMaxLogFileSize = 10 * 1024 * 1024
+	DefaultMaxLogFiles    = 5
+
+	logFileSuffix     = ".log"
+	logDirPerm        = 0755
+	logFilePerm       = 0644
+	logPruneInterval  = time.Hour
+	logSegmentIDWidth = 6
+)
+
+type LogStoreParams struct {
+	// Directory is where the logs are persisted, in a sub directory per execution
+	Directory string
+	// MaxFileSize is the size in bytes after which the log file of an execution is rotated
+	MaxFileSize int64
+	// MaxFiles is the number of log files kept per execution. The oldest files are removed first.
+	MaxFiles int
+	// Retention is how long the logs of an execution are kept after they were last written.
+	// Zero keeps them forever.
+	Retention time.Duration
+	Clock     clock.Clock
+}
+
+// LogStore persists the stdout and stderr of executions on the compute node, so that
+// their logs can still be served once the executions are completed. The logs of each
+// execution are written to a sequence of numbered files, each holding one JSON encoded
+// entry per line, and the oldest file is removed when the maximum number of files is reached.
+type LogStore struct {
+	directory   string
+	maxFileSize int64
+	maxFiles    int
+	retention   time.Duration
+	clock       clock.Clock
+
+	// captures holds the executions whose logs are being captured, and is used by
+	// readers to know whether more logs are expected
+	captures map[string]chan struct{}
+	mu       sync.Mutex
+
+	stopChannel chan struct{}
+	stopOnce    sync.Once
+}
+
+// logEntry is a single line of output as persisted in the log files
+type logEntry struct {
+	Time int64                   `json:"t"`
+	Type models.ExecutionLogType `json:"s"`
+	Line string                  `json:"l"`
+}
+
+func NewLogStore(params LogStoreParams) (*LogStore, error) {
+	if params.Directory == "" {
+		return nil, errors.New("log store directory is required")
+	}
+	if err := os.MkdirAll(params.Directory, logDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create log store directory %s: %w", params.Directory, err)
+	}
+	maxFileSize := params.MaxFileSize
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxLogFileSize
+	}
+	maxFiles := params.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxLogFiles
+	}
+	c := params.Clock
+	if c == nil {
+		c = clock.New()
+	}
+	return &LogStore{
+		directory:   params.Directory,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+		retention:   params.Retention,
+		clock:       c,
+		captures:    make(map[string]chan struct{}),
+		stopChannel: make(chan struct{}),
+	}, nil
+}
+
+// Capture persists the log frames read from the reader until the reader is exhausted or the
+// context is canceled. It blocks until the capture is done, and fails if the logs of the
+// execution are already being captured.
+func (s *LogStore) Capture(ctx context.Context, executionID string, reader io.Reader) error {
+	s.mu.Lock()
+	if _, ok := s.captures[executionID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("logs of execution %s are already being captured", executionID)
+	}
+	done := make(chan struct{})
+	s.captures[executionID] = done
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.captures, executionID)
+		close(done)
+		s.mu.Unlock()
+	}()
+
+	writer, err := s.newLogWriter(executionID)
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+
+	for {
+		df, err := logger.NewDataFrameFromReader(reader)
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read logs of execution %s: %w", executionID, err)
+		}
+		logType := models.ExecutionLogTypeSTDERR
+		if df.Tag == logger.StdoutStreamTag {
+			logType = models.ExecutionLogTypeSTDOUT
+		}
+		now := s.clock.Now().UnixNano()
+		for _, line := range splitLines(string(df.Data)) {
+			if err = writer.write(logEntry{Time: now, Type: logType, Line: line}); err != nil {
+				return fmt.Errorf("failed to persist logs of execution %s: %w", executionID, err)
+			}
+		}
+	}
+}
+
+// Capturing returns true if the logs of the execution are being captured
+func (s *LogStore) Capturing(executionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.captures[executionID]
+	return ok
+}
+
+// Has returns true if logs were persisted for the execution
+func (s *LogStore) Has(executionID string) bool {
+	segments, err := s.segments(executionID)
+	return err == nil && len(segments) > 0
+}
+
+// Remove deletes the persisted logs of the execution
+func (s *LogStore) Remove(executionID string) error {
+	return os.RemoveAll(s.executionDir(executionID))
+}
+
+// Export writes the persisted logs of the execution to the writer as plain text,
+// with each line prefixed by the time it was captured and the stream it was written to.
+func (s *LogStore) Export(executionID string, w io.Writer) error {
+	segments, err := s.segments(executionID)
+	if err != nil {
+		return err
+	}
+	buffered := bufio.NewWriter(w)
+	for _, segment := range segments {
+		if err = s.exportSegment(executionID, segment, buffered); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+func (s *LogStore) exportSegment(executionID string, segment int, w io.Writer) error {
+	file, err := os.Open(s.segmentPath(executionID, segment))
+	if errors.Is(err, os.ErrNotExist) {
+		// the segment was rotated away in the meantime
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, int(s.maxFileSize)+bufio.MaxScanTokenSize)
+	for scanner.Scan() {
+		var entry logEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		stream := "stderr"
+		if entry.Type == models.ExecutionLogTypeSTDOUT {
+			stream = "stdout"
+		}
+		timestamp := time.Unix(0, entry.Time).UTC().Format(time.RFC3339Nano)
+		if _, err = fmt.Fprintf(w, "%s %s %s", timestamp, stream, entry.Line); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(entry.Line, "\n") {
+			if _, err = io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// Start removes the logs that are older than the retention period in the background,
+// until Stop is called. It does nothing if no retention period is set.
+func (s *LogStore) Start(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+	go func() {
+		s.prune(ctx)
+		ticker := s.clock.Ticker(logPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.prune(ctx)
+			case <-s.stopChannel:
+				log.Ctx(ctx).Debug().Msg("stopped execution log store pruning")
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *LogStore) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChannel)
+	})
+}
+
+// prune removes the logs of the executions that were not written to within the retention period
+func (s *LogStore) prune(ctx context.Context) {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("failed to list persisted execution logs")
+		return
+	}
+	cutoff := s.clock.Now().Add(-s.retention)
+	for _, entry := range entries {
+		if !entry.IsDir() || s.Capturing(entry.Name()) {
+			continue
+		}
+		lastWrite, err := s.lastWrite(entry.Name())
+		if err != nil || lastWrite.After(cutoff) {
+			continue
+		}
+		if err = s.Remove(entry.Name()); err != nil {
+			log.Ctx(ctx).Err(err).Msgf("failed to remove persisted logs of execution %s", entry.Name())
+		}
+	}
+}
+
+// lastWrite returns the time the logs of the execution were last written to
+func (s *LogStore) lastWrite(executionID string) (time.Time, error) {
+	entries, err := os.ReadDir(s.executionDir(executionID))
+	if err != nil {
+		return time.Time{}, err
+	}
+	var latest time.Time
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// waitCapture returns a channel that is closed once the capture of the execution's logs is done,
+// or nil if they are not being captured
+func (s *LogStore) waitCapture(executionID string) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.captures[executionID]
+}
+
+func (s *LogStore) executionDir(executionID string) string {
+	return filepath.Join(s.directory, filepath.Base(executionID))
+}
+
+func (s *LogStore) segmentPath(executionID string, segment int) string {
+	return filepath.Join(s.executionDir(executionID), fmt.Sprintf("%0*d%s", logSegmentIDWidth, segment, logFileSuffix))
+}
+
+// segments returns the sequence numbers of the execution's log files, oldest first
+func (s *LogStore) segments(executionID string) ([]int, error) {
+	entries, err := os.ReadDir(s.executionDir(executionID))
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, logFileSuffix) {
+			continue
+		}
+		segment, err := strconv.Atoi(strings.TrimSuffix(name, logFileSuffix))
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	sort.Ints(segments)
+	return segments, nil
+}
+
+// logWriter appends entries to the latest log file of an execution, and rotates it
+// once it reaches the maximum file size
+type logWriter struct {
+	store       *LogStore
+	executionID string
+	segment     int
+	file        *os.File
+	size        int64
+}
+
+func (s *LogStore) newLogWriter(executionID string) (*logWriter, error) {
+	if err := os.MkdirAll(s.executionDir(executionID), logDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create log directory of execution %s: %w", executionID, err)
+	}
+	segments, err := s.segments(executionID)
+	if err != nil {
+		return nil, err
+	}
+	w := &logWriter{store: s, executionID: executionID}
+	// continue writing to the latest file, e.g. if the execution is resumed after a restart
+	if len(segments) > 0 {
+		w.segment = segments[len(segments)-1]
+	}
+	if err = w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *logWriter) open() error {
+	file, err := os.OpenFile(
+		w.store.segmentPath(w.executionID, w.segment), os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+func (w *logWriter) write(entry logEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if w.size > 0 && w.size+int64(len(data)) > w.store.maxFileSize {
+		if err = w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return err
+}
+
+// rotate starts a new log file, and removes the oldest files beyond the maximum number of files
+func (w *logWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.segment++
+	if err := w.open(); err != nil {
+		return err
+	}
+	segments, err := w.store.segments(w.executionID)
+	if err != nil {
+		return err
+	}
+	for len(segments) > w.store.maxFiles {
+		if err = os.Remove(w.store.segmentPath(w.executionID, segments[0])); err != nil {
+			return err
+		}
+		segments = segments[1:]
+	}
+	return nil
+}
+
+func (w *logWriter) close() {
+	_ = w.file.Close()
+}
+
+// splitLines splits the data in lines, keeping the line breaks
+func splitLines(data string) []string {
+	lines := make([]string, 0, 1)
+	for len(data) > 0 {
+		i := strings.IndexByte(data, '\n')
+		if i < 0 {
+			lines = append(lines, data)
+			break
+		}
+		lines = append(lines, data[:i+1])
+		data = data[i+1:]
+	}
+	return lines
+}
@@ -0,0 +1,236 @@
+//go:build unit || !integration
+
+package logstream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const testExecutionID = "e-test"
+
+type LogStoreTestSuite struct {
+	suite.Suite
+	ctx   context.Context
+	clock *clock.Mock
+	store *LogStore
+}
+
+func TestLogStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(LogStoreTestSuite))
+}
+
+func (s *LogStoreTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	var err error
+	s.store, err = NewLogStore(LogStoreParams{
+		Directory:   s.T().TempDir(),
+		MaxFileSize: 1024,
+		MaxFiles:    3,
+		Retention:   24 * time.Hour,
+		Clock:       s.clock,
+	})
+	s.Require().NoError(err)
+}
+
+// frames encodes the lines as the log frames written by executors, alternating stdout and stderr
+func frames(lines ...string) io.Reader {
+	var buf bytes.Buffer
+	for i, line := range lines {
+		tag := logger.StdoutStreamTag
+		if i%2 == 1 {
+			tag = logger.StderrStreamTag
+		}
+		buf.Write(logger.NewDataFrameFromData(tag, []byte(line)).ToBytes())
+	}
+	return &buf
+}
+
+func numberedLines(from, to int) []string {
+	lines := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		lines = append(lines, fmt.Sprintf("line %d\n", i))
+	}
+	return lines
+}
+
+func (s *LogStoreTestSuite) capture(lines ...string) {
+	s.Require().NoError(s.store.Capture(s.ctx, testExecutionID, frames(lines...)))
+}
+
+func (s *LogStoreTestSuite) read(params PersistedStreamerParams) []string {
+	params.Store = s.store
+	params.ExecutionID = testExecutionID
+	lines := make([]string, 0)
+	for result := range NewPersistedStreamer(params).Stream(s.ctx) {
+		s.Require().NoError(result.Err)
+		lines = append(lines, result.Value.Line)
+	}
+	return lines
+}
+
+func (s *LogStoreTestSuite) TestCapture() {
+	s.False(s.store.Has(testExecutionID))
+	s.capture("first\nsecond\n", "error\n", "partial")
+	s.True(s.store.Has(testExecutionID))
+	s.False(s.store.Capturing(testExecutionID))
+
+	results := NewPersistedStreamer(PersistedStreamerParams{
+		Store:       s.store,
+		ExecutionID: testExecutionID,
+	}).Stream(s.ctx)
+	expected := []models.ExecutionLog{
+		{Type: models.ExecutionLogTypeSTDOUT, Line: "first\n"},
+		{Type: models.ExecutionLogTypeSTDOUT, Line: "second\n"},
+		{Type: models.ExecutionLogTypeSTDERR, Line: "error\n"},
+		{Type: models.ExecutionLogTypeSTDOUT, Line: "partial"},
+	}
+	for _, log := range expected {
+		result := <-results
+		s.Require().NotNil(result)
+		s.Require().NoError(result.Err)
+		s.Equal(log, result.Value)
+	}
+	_, more := <-results
+	s.False(more)
+}
+
+func (s *LogStoreTestSuite) TestRotation() {
+	// each entry takes about 40 bytes, so that the logs are rotated multiple times
+	s.capture(numberedLines(0, 200)...)
+
+	segments, err := s.store.segments(testExecutionID)
+	s.Require().NoError(err)
+	s.Len(segments, 3)
+
+	// the oldest lines were rotated away, and the remaining ones are read in order across files
+	lines := s.read(PersistedStreamerParams{})
+	s.Require().NotEmpty(lines)
+	s.Less(len(lines), 200)
+	s.Equal(numberedLines(200-len(lines), 200), lines)
+}
+
+func (s *LogStoreTestSuite) TestRead() {
+	s.capture(numberedLines(0, 5)...)
+	s.clock.Add(time.Minute)
+	s.capture(numberedLines(5, 10)...)
+
+	testCases := []struct {
+		name     string
+		params   PersistedStreamerParams
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: numberedLines(0, 10),
+		},
+		{
+			name:     "offset",
+			params:   PersistedStreamerParams{Offset: 7},
+			expected: numberedLines(7, 10),
+		},
+		{
+			name:     "tail lines",
+			params:   PersistedStreamerParams{TailLines: 3},
+			expected: numberedLines(7, 10),
+		},
+		{
+			name:     "since",
+			params:   PersistedStreamerParams{Since: s.clock.Now()},
+			expected: numberedLines(5, 10),
+		},
+		{
+			name:     "offset and tail lines",
+			params:   PersistedStreamerParams{Offset: 8, TailLines: 5},
+			expected: numberedLines(8, 10),
+		},
+		{
+			name:     "tail skips existing logs",
+			params:   PersistedStreamerParams{Tail: true},
+			expected: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, s.read(tc.params))
+		})
+	}
+}
+
+func (s *LogStoreTestSuite) TestFollow() {
+	reader, writer := io.Pipe()
+	captured := make(chan error, 1)
+	go func() {
+		captured <- s.store.Capture(s.ctx, testExecutionID, reader)
+	}()
+
+	_, err := io.Copy(writer, frames(numberedLines(0, 2)...))
+	s.Require().NoError(err)
+	s.Eventually(func() bool { return s.store.Has(testExecutionID) }, time.Second, 10*time.Millisecond)
+
+	results := NewPersistedStreamer(PersistedStreamerParams{
+		Store:       s.store,
+		ExecutionID: testExecutionID,
+		Follow:      true,
+	}).Stream(s.ctx)
+
+	// more logs are written while following them
+	_, err = io.Copy(writer, frames(numberedLines(2, 4)...))
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+	s.Require().NoError(<-captured)
+
+	lines := make([]string, 0)
+	for result := range results {
+		s.Require().NoError(result.Err)
+		lines = append(lines, result.Value.Line)
+	}
+	s.Equal(numberedLines(0, 4), lines)
+}
+
+func (s *LogStoreTestSuite) TestCaptureTwice() {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	go func() {
+		_ = s.store.Capture(s.ctx, testExecutionID, reader)
+	}()
+	s.Eventually(func() bool { return s.store.Capturing(testExecutionID) }, time.Second, 10*time.Millisecond)
+	s.Error(s.store.Capture(s.ctx, testExecutionID, frames("line\n")))
+}
+
+func (s *LogStoreTestSuite) TestExport() {
+	s.capture("out\n", "err")
+
+	var buf strings.Builder
+	s.Require().NoError(s.store.Export(testExecutionID, &buf))
+	s.Equal("2024-01-10T00:00:00Z stdout out\n2024-01-10T00:00:00Z stderr err\n", buf.String())
+}
+
+func (s *LogStoreTestSuite) TestPrune() {
+	recentExecutionID := "e-recent"
+	s.capture("line\n")
+	s.Require().NoError(s.store.Capture(s.ctx, recentExecutionID, frames("line\n")))
+
+	old := s.clock.Now().Add(-48 * time.Hour)
+	recent := s.clock.Now().Add(-time.Hour)
+	s.Require().NoError(os.Chtimes(s.store.segmentPath(testExecutionID, 0), old, old))
+	s.Require().NoError(os.Chtimes(s.store.segmentPath(recentExecutionID, 0), recent, recent))
+
+	s.store.prune(s.ctx)
+	s.False(s.store.Has(testExecutionID))
+	s.True(s.store.Has(recentExecutionID))
+}
@@ -0,0 +1,278 @@
+package logstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// logFollowInterval is how often the persisted logs are checked for new entries when following them
+const logFollowInterval = 100 * time.Millisecond
+
+type PersistedStreamerParams struct {
+	Store       *LogStore
+	ExecutionID string
+	// Offset is the number of lines to skip from the start of the persisted logs
+	Offset int
+	// TailLines limits the existing logs to the last N lines. Zero returns all of them.
+	TailLines int
+	// Since skips the lines that were written before this time
+	Since time.Time
+	// Tail skips all the existing logs and only streams new ones
+	Tail bool
+	// Follow keeps streaming new logs until the execution stops writing them
+	Follow bool
+	Buffer int
+}
+
+// PersistedStreamer streams the logs of an execution that were persisted in the log store,
+// whether the execution is still running or is completed.
+type PersistedStreamer struct {
+	store       *LogStore
+	executionID string
+	offset      int
+	tailLines   int
+	since       time.Time
+	tail        bool
+	follow      bool
+	buffer      int
+}
+
+func NewPersistedStreamer(params PersistedStreamerParams) *PersistedStreamer {
+	return &PersistedStreamer{
+		store:       params.Store,
+		executionID: params.ExecutionID,
+		offset:      params.Offset,
+		tailLines:   params.TailLines,
+		since:       params.Since,
+		tail:        params.Tail,
+		follow:      params.Follow,
+		buffer:      params.Buffer,
+	}
+}
+
+func (s *PersistedStreamer) Stream(ctx context.Context) chan *concurrency.AsyncResult[models.ExecutionLog] {
+	ch := make(chan *concurrency.AsyncResult[models.ExecutionLog], s.buffer)
+	go func() {
+		defer close(ch)
+		reader := &segmentReader{store: s.store, executionID: s.executionID, segment: -1}
+		defer reader.close()
+		if err := s.stream(ctx, reader, ch); err != nil && ctx.Err() == nil {
+			// return one last log entry with the error message before closing the channel
+			ch <- &concurrency.AsyncResult[models.ExecutionLog]{Err: err}
+		}
+	}()
+	return ch
+}
+
+func (s *PersistedStreamer) stream(
+	ctx context.Context, reader *segmentReader, ch chan *concurrency.AsyncResult[models.ExecutionLog]) error {
+	index := 0
+	// existing logs, keeping the last lines aside when only the tail is requested
+	var tail []logEntry
+	for {
+		entry, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		index++
+		if s.tail || !s.matches(index, entry) {
+			continue
+		}
+		if s.tailLines > 0 {
+			tail = append(tail, entry)
+			if len(tail) > s.tailLines {
+				tail = tail[1:]
+			}
+			continue
+		}
+		if !send(ctx, ch, entry) {
+			return nil
+		}
+	}
+	for _, entry := range tail {
+		if !send(ctx, ch, entry) {
+			return nil
+		}
+	}
+	if !s.follow {
+		return nil
+	}
+
+	// new logs, until the execution's logs are no longer being captured
+	for {
+		done := s.store.waitCapture(s.executionID)
+		entry, err := reader.next()
+		if err == io.EOF {
+			if done == nil {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-done:
+			case <-time.After(logFollowInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		index++
+		if s.matches(index, entry) && !send(ctx, ch, entry) {
+			return nil
+		}
+	}
+}
+
+// matches returns true if the entry at the given position, starting from 1, is not filtered out
+func (s *PersistedStreamer) matches(index int, entry logEntry) bool {
+	if index <= s.offset {
+		return false
+	}
+	return s.since.IsZero() || !time.Unix(0, entry.Time).Before(s.since)
+}
+
+func send(ctx context.Context, ch chan *concurrency.AsyncResult[models.ExecutionLog], entry logEntry) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- &concurrency.AsyncResult[models.ExecutionLog]{
+		Value: models.ExecutionLog{Type: entry.Type, Line: entry.Line},
+	}:
+		return true
+	}
+}
+
+// segmentReader reads the entries of an execution's log files in order, moving on to the
+// next file when the current one has been rotated.
+type segmentReader struct {
+	store       *LogStore
+	executionID string
+	segment     int
+	file        *os.File
+	reader      *bufio.Reader
+	// partial holds an entry that is still being written
+	partial []byte
+}
+
+// next returns the next entry, or io.EOF if there are no more entries for now
+func (r *segmentReader) next() (logEntry, error) {
+	for {
+		if r.file == nil {
+			opened, err := r.openNext()
+			if err != nil {
+				return logEntry{}, err
+			}
+			if !opened {
+				return logEntry{}, io.EOF
+			}
+		}
+		entry, ok, err := r.readEntry()
+		if err != nil || ok {
+			return entry, err
+		}
+
+		// the end of the file was reached, move on to the next one if the file was rotated
+		rotated, err := r.hasNext()
+		if err != nil || !rotated {
+			return logEntry{}, io.EOF
+		}
+		// read whatever was written to the file before it was rotated
+		entry, ok, err = r.readEntry()
+		if err != nil || ok {
+			return entry, err
+		}
+		r.close()
+	}
+}
+
+// readEntry reads the next complete entry of the current file, and returns false
+// when the end of the file is reached
+func (r *segmentReader) readEntry() (logEntry, bool, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		r.partial = append(r.partial, line...)
+		if err == io.EOF {
+			return logEntry{}, false, nil
+		}
+		if err != nil {
+			return logEntry{}, false, err
+		}
+		var entry logEntry
+		err = json.Unmarshal(r.partial, &entry)
+		r.partial = r.partial[:0]
+		if err != nil {
+			// skip corrupted entries, e.g. if the node crashed while writing them
+			continue
+		}
+		return entry, true, nil
+	}
+}
+
+// openNext opens the oldest file that comes after the current one, and returns false if there is none
+func (r *segmentReader) openNext() (bool, error) {
+	for {
+		segments, err := r.store.segments(r.executionID)
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		next := -1
+		for _, segment := range segments {
+			if segment > r.segment {
+				next = segment
+				break
+			}
+		}
+		if next < 0 {
+			return false, nil
+		}
+		file, err := os.Open(r.store.segmentPath(r.executionID, next))
+		if errors.Is(err, os.ErrNotExist) {
+			// the file was rotated away before it could be opened, try the next one
+			r.segment = next
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		r.segment = next
+		r.file = file
+		r.reader = bufio.NewReader(file)
+		r.partial = r.partial[:0]
+		return true, nil
+	}
+}
+
+// hasNext returns true if there is a newer file than the current one
+func (r *segmentReader) hasNext() (bool, error) {
+	segments, err := r.store.segments(r.executionID)
+	if err != nil {
+		return false, err
+	}
+	return len(segments) > 0 && segments[len(segments)-1] > r.segment, nil
+}
+
+func (r *segmentReader) close() {
+	if r.file != nil {
+		_ = r.file.Close()
+		r.file = nil
+		r.reader = nil
+	}
+}
+
+// compile-time check that PersistedStreamer implements Streamer
+var _ Streamer = (*PersistedStreamer)(nil)
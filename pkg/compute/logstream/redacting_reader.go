@@ -0,0 +1,47 @@
+package logstream
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+)
+
+// RedactingReader reads the log frames of an execution, and redacts their data before
+// passing them on, so that sensitive values are not persisted nor streamed.
+type RedactingReader struct {
+	reader io.Reader
+	redact func(string) string
+	buffer bytes.Buffer
+}
+
+// NewRedactingReader creates a reader of the log frames read from the reader, whose data is
+// replaced by the result of the redact function.
+func NewRedactingReader(reader io.Reader, redact func(string) string) *RedactingReader {
+	return &RedactingReader{
+		reader: reader,
+		redact: redact,
+	}
+}
+
+func (r *RedactingReader) Read(p []byte) (int, error) {
+	if r.buffer.Len() == 0 {
+		df, err := logger.NewDataFrameFromReader(r.reader)
+		if err != nil {
+			return 0, err
+		}
+		redacted := logger.NewDataFrameFromData(df.Tag, []byte(r.redact(string(df.Data))))
+		r.buffer.Write(redacted.ToBytes())
+	}
+	return r.buffer.Read(p)
+}
+
+// redactingReadCloser is a RedactingReader that closes the underlying reader
+type redactingReadCloser struct {
+	*RedactingReader
+	closer io.Closer
+}
+
+func (r *redactingReadCloser) Close() error {
+	return r.closer.Close()
+}
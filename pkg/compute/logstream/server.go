@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
@@ -13,12 +14,19 @@ import (
 type ServerParams struct {
 	ExecutionStore store.ExecutionStore
 	Executors      executor.ExecutorProvider
-	Buffer         int
+	// LogStore holds the persisted logs of executions. Optional, and if not set
+	// logs can only be streamed while executions are running.
+	LogStore *LogStore
+	// Secrets holds the values of the secrets of executions, which are redacted from their logs
+	Secrets *secrets.Cache
+	Buffer  int
 }
 
 type Server struct {
 	executionStore store.ExecutionStore
 	executors      executor.ExecutorProvider
+	logStore       *LogStore
+	secrets        *secrets.Cache
 	buffer         int
 }
 
@@ -27,11 +35,15 @@ func NewServer(params ServerParams) *Server {
 	return &Server{
 		executionStore: params.ExecutionStore,
 		executors:      params.Executors,
+		logStore:       params.LogStore,
+		secrets:        params.Secrets,
 		buffer:         params.Buffer,
 	}
 }
 
-// GetLogStream returns a stream of logs for a given execution
+// GetLogStream returns a stream of logs for a given execution. Persisted logs are served
+// when available, including for completed executions, and otherwise the logs are streamed
+// from the running execution.
 func (s *Server) GetLogStream(ctx context.Context, request executor.LogStreamRequest) (
 	<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	localExecutionState, err := s.executionStore.GetExecution(ctx, request.ExecutionID)
@@ -39,8 +51,22 @@ func (s *Server) GetLogStream(ctx context.Context, request executor.LogStreamReq
 		return nil, err
 	}
 
+	if s.logStore != nil && s.logStore.Has(request.ExecutionID) {
+		streamer := NewPersistedStreamer(PersistedStreamerParams{
+			Store:       s.logStore,
+			ExecutionID: request.ExecutionID,
+			Offset:      request.Offset,
+			TailLines:   request.TailLines,
+			Since:       request.Since,
+			Tail:        request.Tail,
+			Follow:      request.Follow,
+			Buffer:      s.buffer,
+		})
+		return streamer.Stream(ctx), nil
+	}
+
 	if localExecutionState.State.IsTerminal() {
-		return nil, fmt.Errorf("can't stream logs for completed execution: %s", request.ExecutionID)
+		return nil, fmt.Errorf("no persisted logs for completed execution: %s", request.ExecutionID)
 	}
 	engineType := localExecutionState.Execution.Job.Task().Engine.Type
 	exec, err := s.executors.Get(ctx, engineType)
@@ -53,7 +79,12 @@ func (s *Server) GetLogStream(ctx context.Context, request executor.LogStreamReq
 		return nil, fmt.Errorf("failed to get log stream for execution: %s. %w", request.ExecutionID, err)
 	}
 	streamer := NewLiveStreamer(LiveStreamerParams{
-		Reader: reader,
+		Reader: &redactingReadCloser{
+			RedactingReader: NewRedactingReader(reader, func(data string) string {
+				return s.secrets.Redact(request.ExecutionID, data)
+			}),
+			closer: reader,
+		},
 		Buffer: s.buffer,
 	})
 
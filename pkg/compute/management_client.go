@@ -24,6 +24,17 @@ type ManagementClientParams struct {
 	RegistrationFilePath string
 	HeartbeatClient      *heartbeat.HeartbeatClient
 	ControlPlaneSettings types.ComputeControlPlaneConfig
+	// Credentials is optional, and only set when the node has to connect with
+	// credentials issued by the requester nodes.
+	Credentials NodeCredentialsStore
+}
+
+// NodeCredentialsStore holds the key of the compute node and the credentials
+// issued for it by the requester nodes.
+type NodeCredentialsStore interface {
+	PublicKey() string
+	HasCredentials() bool
+	SetCredentials(credentials string) error
 }
 
 // ManagementClient is used to call management functions with
@@ -40,6 +51,7 @@ type ManagementClient struct {
 	registrationFile  *RegistrationFile
 	heartbeatClient   *heartbeat.HeartbeatClient
 	settings          types.ComputeControlPlaneConfig
+	credentials       NodeCredentialsStore
 }
 
 func NewManagementClient(params *ManagementClientParams) *ManagementClient {
@@ -53,6 +65,22 @@ func NewManagementClient(params *ManagementClientParams) *ManagementClient {
 		resourceTracker:   params.ResourceTracker,
 		heartbeatClient:   params.HeartbeatClient,
 		settings:          params.ControlPlaneSettings,
+		credentials:       params.Credentials,
+	}
+}
+
+// needsCredentials returns true if the node has to connect with credentials it does not hold yet
+func (m *ManagementClient) needsCredentials() bool {
+	return m.credentials != nil && !m.credentials.HasCredentials()
+}
+
+// setCredentials stores the credentials issued by the requester node, if any
+func (m *ManagementClient) setCredentials(ctx context.Context, credentials string) {
+	if m.credentials == nil || credentials == "" {
+		return
+	}
+	if err := m.credentials.SetCredentials(credentials); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to store credentials issued by requester node")
 	}
 }
 
@@ -69,22 +97,27 @@ func (m *ManagementClient) getNodeInfo(ctx context.Context) models.NodeInfo {
 // register, a sentinel file is created to indicate that we are registered. If present
 // the requester node will know it is already registered.  If not present, it will
 // attempt to register again, expecting the requester node to gracefully handle any
-// previous registrations.
+// previous registrations. Nodes that need credentials register until they are issued
+// credentials, which happens once they are approved.
 func (m *ManagementClient) RegisterNode(ctx context.Context) error {
-	if m.registrationFile.Exists() {
+	if m.registrationFile.Exists() && !m.needsCredentials() {
 		log.Ctx(ctx).Debug().Msg("not registering with requester, already registered")
 		return nil
 	}
 
-	nodeInfo := m.getNodeInfo(ctx)
-	response, err := m.managementProxy.Register(ctx, requests.RegisterRequest{
-		Info: nodeInfo,
-	})
+	request := requests.RegisterRequest{
+		Info: m.getNodeInfo(ctx),
+	}
+	if m.credentials != nil {
+		request.PublicKey = m.credentials.PublicKey()
+	}
+	response, err := m.managementProxy.Register(ctx, request)
 	if err != nil {
 		return errors.New("failed to register with requester node")
 	}
 
 	if response.Accepted {
+		m.setCredentials(ctx, response.Credentials)
 		if err := m.registrationFile.Set(); err != nil {
 			return errors.Wrap(err, "failed to record local registration status")
 		}
@@ -114,6 +147,7 @@ func (m *ManagementClient) deliverInfo(ctx context.Context) {
 	}
 
 	if response.Accepted {
+		m.setCredentials(ctx, response.Credentials)
 		log.Ctx(ctx).Debug().Msg("update info accepted")
 	} else {
 		log.Ctx(ctx).Error().Msgf("update info rejected: %s", response.Reason)
@@ -170,6 +204,14 @@ func (m *ManagementClient) Start(ctx context.Context) {
 		case <-m.done:
 			return
 		case <-infoTicker.C:
+			if m.needsCredentials() {
+				// Nodes without credentials can only register, which issues
+				// them credentials once they are approved
+				if err := m.RegisterNode(ctx); err != nil {
+					log.Ctx(ctx).Debug().Err(err).Msg("failed to register node for credentials")
+				}
+				continue
+			}
 			// Send the latest node info to the requester node
 			m.deliverInfo(ctx)
 		case <-resourceTicker.C:
@@ -0,0 +1,102 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/requests"
+)
+
+// credentialsEndpoint is a management endpoint that issues credentials once approved
+type credentialsEndpoint struct {
+	ManagementEndpoint
+	approved      bool
+	registrations []requests.RegisterRequest
+}
+
+func (e *credentialsEndpoint) Register(
+	_ context.Context, request requests.RegisterRequest) (*requests.RegisterResponse, error) {
+	e.registrations = append(e.registrations, request)
+	response := &requests.RegisterResponse{Accepted: true}
+	if e.approved {
+		response.Credentials = "credentials-of-" + request.PublicKey
+	}
+	return response, nil
+}
+
+type testCredentialsStore struct {
+	credentials string
+}
+
+func (s *testCredentialsStore) PublicKey() string {
+	return "node-key"
+}
+
+func (s *testCredentialsStore) HasCredentials() bool {
+	return s.credentials != ""
+}
+
+func (s *testCredentialsStore) SetCredentials(credentials string) error {
+	s.credentials = credentials
+	return nil
+}
+
+type ManagementClientSuite struct {
+	suite.Suite
+	endpoint    *credentialsEndpoint
+	credentials *testCredentialsStore
+	client      *ManagementClient
+}
+
+func (s *ManagementClientSuite) SetupTest() {
+	s.endpoint = &credentialsEndpoint{}
+	s.credentials = &testCredentialsStore{}
+	s.client = NewManagementClient(&ManagementClientParams{
+		NodeID:               "node1",
+		LabelsProvider:       models.MergeLabelsInOrder(),
+		ManagementProxy:      s.endpoint,
+		NodeInfoDecorator:    models.NoopNodeInfoDecorator{},
+		RegistrationFilePath: filepath.Join(s.T().TempDir(), "node1.registration.lock"),
+		Credentials:          s.credentials,
+	})
+}
+
+func (s *ManagementClientSuite) TestRegisterUntilIssuedCredentials() {
+	ctx := context.Background()
+
+	// the node is registered, but not approved yet
+	s.Require().NoError(s.client.RegisterNode(ctx))
+	s.Require().Len(s.endpoint.registrations, 1)
+	s.Equal("node-key", s.endpoint.registrations[0].PublicKey)
+	s.False(s.credentials.HasCredentials())
+
+	// the node registers again as it has no credentials yet
+	s.endpoint.approved = true
+	s.Require().NoError(s.client.RegisterNode(ctx))
+	s.Len(s.endpoint.registrations, 2)
+	s.Equal("credentials-of-node-key", s.credentials.credentials)
+
+	// and no longer once it holds credentials
+	s.Require().NoError(s.client.RegisterNode(ctx))
+	s.Len(s.endpoint.registrations, 2)
+}
+
+func (s *ManagementClientSuite) TestRegisterOnceWithoutCredentials() {
+	ctx := context.Background()
+	s.client.credentials = nil
+
+	s.Require().NoError(s.client.RegisterNode(ctx))
+	s.Require().NoError(s.client.RegisterNode(ctx))
+	s.Require().Len(s.endpoint.registrations, 1)
+	s.Empty(s.endpoint.registrations[0].PublicKey)
+}
+
+func TestManagementClientSuite(t *testing.T) {
+	suite.Run(t, new(ManagementClientSuite))
+}
@@ -103,6 +103,21 @@ func (mr *MockEndpointMockRecorder) CancelExecution(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelExecution", reflect.TypeOf((*MockEndpoint)(nil).CancelExecution), arg0, arg1)
 }
 
+// Exec mocks base method.
+func (m *MockEndpoint) Exec(ctx context.Context, request ExecRequest) (<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exec", ctx, request)
+	ret0, _ := ret[0].(<-chan *concurrency.AsyncResult[models.ExecOutput])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec.
+func (mr *MockEndpointMockRecorder) Exec(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockEndpoint)(nil).Exec), ctx, request)
+}
+
 // ExecutionLogs mocks base method.
 func (m *MockEndpoint) ExecutionLogs(ctx context.Context, request ExecutionLogsRequest) (<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	m.ctrl.T.Helper()
@@ -216,6 +231,42 @@ func (mr *MockCallbackMockRecorder) OnCancelComplete(ctx, result any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCancelComplete", reflect.TypeOf((*MockCallback)(nil).OnCancelComplete), ctx, result)
 }
 
+// OnCheckpoint mocks base method.
+func (m *MockCallback) OnCheckpoint(ctx context.Context, result CheckpointResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnCheckpoint", ctx, result)
+}
+
+// OnCheckpoint indicates an expected call of OnCheckpoint.
+func (mr *MockCallbackMockRecorder) OnCheckpoint(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCheckpoint", reflect.TypeOf((*MockCallback)(nil).OnCheckpoint), ctx, result)
+}
+
+// OnHealthCheck mocks base method.
+func (m *MockCallback) OnHealthCheck(ctx context.Context, result HealthCheckResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnHealthCheck", ctx, result)
+}
+
+// OnHealthCheck indicates an expected call of OnHealthCheck.
+func (mr *MockCallbackMockRecorder) OnHealthCheck(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnHealthCheck", reflect.TypeOf((*MockCallback)(nil).OnHealthCheck), ctx, result)
+}
+
+// OnPortsPublished mocks base method.
+func (m *MockCallback) OnPortsPublished(ctx context.Context, result PortsResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnPortsPublished", ctx, result)
+}
+
+// OnPortsPublished indicates an expected call of OnPortsPublished.
+func (mr *MockCallbackMockRecorder) OnPortsPublished(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPortsPublished", reflect.TypeOf((*MockCallback)(nil).OnPortsPublished), ctx, result)
+}
+
 // OnComputeFailure mocks base method.
 func (m *MockCallback) OnComputeFailure(ctx context.Context, err ComputeError) {
 	m.ctrl.T.Helper()
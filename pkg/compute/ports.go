@@ -0,0 +1,72 @@
+package compute
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// publishPorts notifies the requester of the ports published by the execution once it started,
+// if its task has ports, so that they can be discovered by other jobs and clients.
+// The returned function stops waiting for the execution to start.
+func (e *BaseExecutor) publishPorts(ctx context.Context, state store.LocalExecutionState) func() {
+	execution := state.Execution
+	network := execution.Job.Task().Network
+	if network == nil || len(network.Ports) == 0 {
+		return func() {}
+	}
+	jobExecutor, err := e.executors.Get(ctx, execution.Job.Task().Engine.Type)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to get executor to publish the ports of the execution")
+		return func() {}
+	}
+	publisher, ok := jobExecutor.(executor.PortPublisher)
+	if !ok {
+		// executors that do not publish ports reject tasks with ports when bidding
+		log.Ctx(ctx).Warn().Msgf("executor %s does not publish the ports of executions", execution.Job.Task().Engine.Type)
+		return func() {}
+	}
+
+	publishCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		published, err := publisher.PublishedPorts(publishCtx, execution.ID)
+		if err != nil {
+			if publishCtx.Err() == nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("failed to get the ports published by the execution")
+			}
+			return
+		}
+		ports := make([]*models.ExecutionPort, 0, len(network.Ports))
+		for _, port := range network.Ports {
+			hostPort, found := published[port.Target]
+			if !found {
+				log.Ctx(ctx).Warn().Msgf("port %s of the execution was not published", port.Name)
+				continue
+			}
+			ports = append(ports, &models.ExecutionPort{
+				Name:   port.Name,
+				Target: port.Target,
+				Host:   e.portsAddress,
+				Port:   hostPort,
+			})
+		}
+		e.callback.OnPortsPublished(ctx, PortsResult{
+			ExecutionMetadata: NewExecutionMetadata(execution),
+			RoutingMetadata: RoutingMetadata{
+				SourcePeerID: e.ID,
+				TargetPeerID: state.RequesterNodeID,
+			},
+			Ports: ports,
+		})
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,92 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// portsExecutor is an executor that only returns the ports published by executions
+type portsExecutor struct {
+	executor.Executor
+	published map[int]int
+}
+
+func (e portsExecutor) IsInstalled(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (e portsExecutor) PublishedPorts(context.Context, string) (map[int]int, error) {
+	return e.published, nil
+}
+
+type PortsTestSuite struct {
+	suite.Suite
+	results chan PortsResult
+}
+
+func TestPortsTestSuite(t *testing.T) {
+	suite.Run(t, new(PortsTestSuite))
+}
+
+func (s *PortsTestSuite) SetupTest() {
+	s.results = make(chan PortsResult, 1)
+}
+
+func (s *PortsTestSuite) newExecutor(jobExecutor executor.Executor) *BaseExecutor {
+	return NewBaseExecutor(BaseExecutorParams{
+		ID: "compute",
+		Callback: CallbackMock{
+			OnPortsPublishedHandler: func(ctx context.Context, result PortsResult) {
+				s.results <- result
+			},
+		},
+		Executors:    provider.NewMappedProvider(map[string]executor.Executor{"noop": jobExecutor}),
+		PortsAddress: "10.0.0.1",
+	})
+}
+
+func (s *PortsTestSuite) TestPublishesPortsOfExecution() {
+	job := mock.Job()
+	job.Task().Network = &models.NetworkConfig{
+		Type: models.NetworkFull,
+		Ports: []*models.Port{
+			{Name: "http", Target: 8080},
+			{Name: "metrics", Target: 9090, Static: 9090},
+		},
+	}
+	execution := mock.ExecutionForJob(job)
+	baseExecutor := s.newExecutor(portsExecutor{published: map[int]int{8080: 32768, 9090: 9090}})
+
+	stop := baseExecutor.publishPorts(context.Background(), *store.NewLocalExecutionState(execution, "requester"))
+	stop()
+
+	s.Require().Len(s.results, 1)
+	result := <-s.results
+	s.Equal(execution.ID, result.ExecutionID)
+	s.Equal("requester", result.TargetPeerID)
+	s.Equal([]*models.ExecutionPort{
+		{Name: "http", Target: 8080, Host: "10.0.0.1", Port: 32768},
+		{Name: "metrics", Target: 9090, Host: "10.0.0.1", Port: 9090},
+	}, result.Ports)
+	s.Equal("10.0.0.1:32768", result.Ports[0].Address())
+}
+
+func (s *PortsTestSuite) TestSkipsTasksWithoutPorts() {
+	execution := mock.Execution()
+	baseExecutor := s.newExecutor(portsExecutor{})
+
+	stop := baseExecutor.publishPorts(context.Background(), *store.NewLocalExecutionState(execution, "requester"))
+	stop()
+
+	s.Empty(s.results)
+}
@@ -0,0 +1,44 @@
+package compute
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// injectSecrets adds the secrets referenced by the task to the arguments of the execution,
+// either as environment variables or as files, using the values delivered by the requester.
+// The values are not available if the compute node restarted since the execution was started,
+// in which case the execution fails and is rescheduled by the requester.
+func (e *BaseExecutor) injectSecrets(execution *models.Execution, args *executor.RunCommandRequest) error {
+	references := execution.Job.Task().Secrets
+	if len(references) == 0 {
+		return nil
+	}
+	values, ok := e.secrets.Get(execution.ID)
+	if !ok {
+		return fmt.Errorf("secrets of execution %s are not available on this node", execution.ID)
+	}
+
+	env := make(map[string]string, len(args.Env)+len(references))
+	maps.Copy(env, args.Env)
+	files := make(map[string][]byte)
+	for _, reference := range references {
+		value, ok := values[reference.Name]
+		if !ok {
+			return fmt.Errorf("secret %s of execution %s is not available on this node", reference.Name, execution.ID)
+		}
+		if reference.Env != "" {
+			env[reference.Env] = value
+		} else {
+			files[reference.Path] = []byte(value)
+		}
+	}
+	args.Env = env
+	if len(files) > 0 {
+		args.SecretFiles = files
+	}
+	return nil
+}
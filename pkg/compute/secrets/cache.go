@@ -0,0 +1,99 @@
+// Package secrets holds the values of the secrets of the executions running on a compute node.
+package secrets
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the values of secrets in the logs and outputs of executions
+const Redacted = "[REDACTED]"
+
+// Cache holds the values of the secrets referenced by the executions running on the compute
+// node, as delivered by the requester when the executions are started. The values are only
+// kept in memory, so they are never persisted on the compute node, and are removed once the
+// executions are done.
+type Cache struct {
+	// values is keyed by execution ID and then by secret name
+	values    map[string]map[string]string
+	replacers map[string]*strings.Replacer
+	mu        sync.RWMutex
+}
+
+func NewCache() *Cache {
+	return &Cache{
+		values:    make(map[string]map[string]string),
+		replacers: make(map[string]*strings.Replacer),
+	}
+}
+
+// Put sets the values of the secrets of an execution, keyed by secret name.
+func (c *Cache) Put(executionID string, values map[string]string) {
+	if c == nil || len(values) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[executionID] = values
+	c.replacers[executionID] = newRedactor(values)
+}
+
+// Get returns the values of the secrets of an execution, and whether they are known.
+func (c *Cache) Get(executionID string) (map[string]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	values, ok := c.values[executionID]
+	return values, ok
+}
+
+// Remove forgets the values of the secrets of an execution.
+func (c *Cache) Remove(executionID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, executionID)
+	delete(c.replacers, executionID)
+}
+
+// Redact replaces the values of the secrets of an execution found in the text.
+func (c *Cache) Redact(executionID string, text string) string {
+	if c == nil {
+		return text
+	}
+	c.mu.RLock()
+	replacer, ok := c.replacers[executionID]
+	c.mu.RUnlock()
+	if !ok {
+		return text
+	}
+	return replacer.Replace(text)
+}
+
+// newRedactor returns a replacer of the values, and of each line of multi-line values as
+// outputs are often processed line by line. Longer values are matched first so that a
+// value containing another one is fully redacted.
+func newRedactor(values map[string]string) *strings.Replacer {
+	seen := make(map[string]bool)
+	var patterns []string
+	for _, value := range values {
+		for _, pattern := range append([]string{value}, strings.Split(value, "\n")...) {
+			pattern = strings.TrimSpace(pattern)
+			if pattern != "" && !seen[pattern] {
+				seen[pattern] = true
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool { return len(patterns[i]) > len(patterns[j]) })
+	oldnew := make([]string, 0, 2*len(patterns))
+	for _, pattern := range patterns {
+		oldnew = append(oldnew, pattern, Redacted)
+	}
+	return strings.NewReplacer(oldnew...)
+}
@@ -0,0 +1,37 @@
+//go:build unit || !integration
+
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache()
+	cache.Put("e1", map[string]string{"token": "s3cr3t", "key": "line-one\nline-two\n"})
+
+	values, ok := cache.Get("e1")
+	assert.True(t, ok)
+	assert.Equal(t, "s3cr3t", values["token"])
+	_, ok = cache.Get("e2")
+	assert.False(t, ok)
+
+	assert.Equal(t, "token=[REDACTED]", cache.Redact("e1", "token=s3cr3t"))
+	assert.Equal(t, "[REDACTED]\n", cache.Redact("e1", "line-two\n"))
+	assert.Equal(t, "token=s3cr3t", cache.Redact("e2", "token=s3cr3t"), "other executions are not redacted")
+
+	cache.Remove("e1")
+	_, ok = cache.Get("e1")
+	assert.False(t, ok)
+	assert.Equal(t, "token=s3cr3t", cache.Redact("e1", "token=s3cr3t"))
+}
+
+func TestCache_Nil(t *testing.T) {
+	var cache *Cache
+	cache.Put("e1", map[string]string{"token": "s3cr3t"})
+	_, ok := cache.Get("e1")
+	assert.False(t, ok)
+	assert.Equal(t, "s3cr3t", cache.Redact("e1", "s3cr3t"))
+}
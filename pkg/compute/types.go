@@ -3,7 +3,11 @@ package compute
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -25,6 +29,8 @@ type Endpoint interface {
 	CancelExecution(context.Context, CancelExecutionRequest) (CancelExecutionResponse, error)
 	// ExecutionLogs returns the address of a suitable log server
 	ExecutionLogs(ctx context.Context, request ExecutionLogsRequest) (<-chan *concurrency.AsyncResult[models.ExecutionLog], error)
+	// Exec runs a command inside a running execution, and streams its output until it exits.
+	Exec(ctx context.Context, request ExecRequest) (<-chan *concurrency.AsyncResult[models.ExecOutput], error)
 }
 
 // Executor Backend service that is responsible for running and publishing executions.
@@ -42,6 +48,9 @@ type Callback interface {
 	OnRunComplete(ctx context.Context, result RunResult)
 	OnCancelComplete(ctx context.Context, result CancelResult)
 	OnComputeFailure(ctx context.Context, err ComputeError)
+	OnCheckpoint(ctx context.Context, result CheckpointResult)
+	OnHealthCheck(ctx context.Context, result HealthCheckResult)
+	OnPortsPublished(ctx context.Context, result PortsResult)
 }
 
 // ManagementEndpoint is the transport-based interface for compute nodes to
@@ -64,6 +73,11 @@ type RoutingMetadata struct {
 	TargetPeerID string
 }
 
+// SourceNodeID returns the ID of the node that sent the message
+func (m RoutingMetadata) SourceNodeID() string {
+	return m.SourcePeerID
+}
+
 type ExecutionMetadata struct {
 	ExecutionID string
 	JobID       string
@@ -84,17 +98,39 @@ type AskForBidRequest struct {
 	// if set to true, the compute node will not start the execution until the requester approves the bid.
 	// If set to false, the compute node will automatically start the execution after bidding and when resources are available.
 	WaitForApproval bool
+	// Secrets holds the values of the secrets referenced by the task, keyed by name.
+	// It is only set if the execution is started without waiting for approval.
+	Secrets SecretValues `json:",omitempty"`
 }
 
 type AskForBidResponse struct {
 	ExecutionMetadata
 }
 
+// SecretValues holds the values of secrets keyed by name. The values are redacted when
+// formatted, so that the requests carrying them can be logged.
+type SecretValues map[string]string
+
+func (v SecretValues) String() string {
+	entries := make([]string, 0, len(v))
+	for name := range v {
+		entries = append(entries, name+":"+secrets.Redacted)
+	}
+	sort.Strings(entries)
+	return "map[" + strings.Join(entries, " ") + "]"
+}
+
+func (v SecretValues) GoString() string {
+	return v.String()
+}
+
 type BidAcceptedRequest struct {
 	RoutingMetadata
 	ExecutionID   string
 	Accepted      bool
 	Justification string
+	// Secrets holds the values of the secrets referenced by the task, keyed by name
+	Secrets SecretValues `json:",omitempty"`
 }
 
 type BidAcceptedResponse struct {
@@ -126,6 +162,23 @@ type ExecutionLogsRequest struct {
 	ExecutionID string
 	Tail        bool
 	Follow      bool
+	Offset      int
+	TailLines   int
+	Since       time.Time
+}
+
+type ExecRequest struct {
+	RoutingMetadata
+	ExecutionID string
+	Command     []string
+	TTY         bool
+	// Stdin attaches the standard input of the command to Input
+	Stdin bool
+	// Size is the initial size of the terminal, if TTY is set
+	Size *models.TerminalSize
+	// Input receives the input of the command until it is closed. It is not serialized,
+	// as transports forward the input separately from the request.
+	Input <-chan models.ExecInput `json:"-"`
 }
 
 type ExecutionLogsResponse struct {
@@ -155,6 +208,27 @@ type RunResult struct {
 	RunCommandResult *models.RunCommandResult
 }
 
+// CheckpointResult is a checkpoint published by a running execution that is returned to the caller through a Callback.
+type CheckpointResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Checkpoint *models.ExecutionCheckpoint
+}
+
+// HealthCheckResult is a change in the health of a running execution that is returned to the caller through a Callback.
+type HealthCheckResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Health *models.ExecutionHealth
+}
+
+// PortsResult are the ports published by a running execution that are returned to the caller through a Callback.
+type PortsResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Ports []*models.ExecutionPort
+}
+
 // CancelResult Result of a job cancel that is returned to the caller through a Callback.
 type CancelResult struct {
 	RoutingMetadata
@@ -39,6 +39,9 @@ const (
 
 var (
 	ComputeExecutionsStorePath = filepath.Join(ComputeStorePath, "executions.db")
+	ComputeExecutionLogsPath   = filepath.Join(ComputeStorePath, "logs")
+	ComputeOCIPath             = filepath.Join(ComputeStorePath, "oci")
+	ComputeProcessPath         = filepath.Join(ComputeStorePath, "process")
 	OrchestratorJobStorePath   = filepath.Join(OrchestratorStorePath, "jobs.db")
 )
 
@@ -76,6 +79,9 @@ func getDefaultConfig(path string) types.BacalhauConfig {
 	defaultConfig.Node.ExecutorPluginPath = filepath.Join(path, PluginsPath)
 	defaultConfig.Node.ComputeStoragePath = filepath.Join(path, ComputeStoragesPath)
 	defaultConfig.Node.Compute.ExecutionStore.Path = filepath.Join(path, ComputeExecutionsStorePath)
+	defaultConfig.Node.Compute.LogStreamConfig.Persistence.Directory = filepath.Join(path, ComputeExecutionLogsPath)
+	defaultConfig.Node.Compute.DockerRuntime.OCI.Directory = filepath.Join(path, ComputeOCIPath)
+	defaultConfig.Node.Compute.ProcessExecutor.Directory = filepath.Join(path, ComputeProcessPath)
 	defaultConfig.Node.Requester.JobStore.Path = filepath.Join(path, OrchestratorJobStorePath)
 	defaultConfig.Update.CheckStatePath = filepath.Join(path, UpdateCheckStatePath)
 	defaultConfig.Auth.TokensPath = filepath.Join(path, TokensPath)
@@ -152,13 +152,26 @@ var ProductionComputeConfig = types.ComputeConfig{
 		Duration:  types.Duration(1 * time.Hour),
 		Frequency: types.Duration(1 * time.Hour),
 	},
+	DockerRuntime: types.DockerRuntimeConfig{
+		Type: types.DockerRuntimeDocker,
+		OCI: types.OCIRuntimeConfig{
+			Runtime: "runc",
+		},
+	},
 	LogStreamConfig: types.LogStreamConfig{
 		ChannelBufferSize: 10,
+		Persistence: types.LogPersistenceConfig{
+			Enabled:     true,
+			MaxFileSize: "10MB",
+			MaxFiles:    5,
+			Retention:   types.Duration(7 * 24 * time.Hour),
+		},
 	},
 	LocalPublisher: types.LocalPublisherConfig{
 		Address: "public",
 		Port:    6001,
 	},
+	PortsAddress: "public",
 	ControlPlaneSettings: types.ComputeControlPlaneConfig{
 		InfoUpdateFrequency:     types.Duration(60 * time.Second),
 		ResourceUpdateFrequency: types.Duration(30 * time.Second),
@@ -180,6 +193,9 @@ var ProductionRequesterConfig = types.RequesterConfig{
 		Type: types.BoltDB,
 		Path: "",
 	},
+	JobRetention: types.JobRetentionConfig{
+		Interval: types.Duration(time.Hour),
+	},
 	HousekeepingBackgroundTaskInterval: types.Duration(30 * time.Second),
 	NodeRankRandomnessRange:            5,
 	OverAskForBidsFactor:               3,
@@ -206,6 +222,7 @@ var ProductionRequesterConfig = types.RequesterConfig{
 			PreSignedURLExpiration: types.Duration(30 * time.Minute),
 		},
 	},
+	DrainGracePeriod: types.Duration(5 * time.Minute),
 	ControlPlaneSettings: types.RequesterControlPlaneConfig{
 		HeartbeatCheckFrequency: types.Duration(30 * time.Second),
 		HeartbeatTopic:          "heartbeat",
@@ -55,6 +55,11 @@ func ClientTLSConfig() types.ClientTLSConfig {
 	return cfg
 }
 
+// ClientAPIKey returns the API key the client authenticates with, if any
+func ClientAPIKey() string {
+	return viper.GetString(types.NodeClientAPIAPIKey)
+}
+
 func ClientAPIBase() string {
 	scheme := "http"
 	if ClientTLSConfig().UseTLS {
@@ -10,6 +10,25 @@ import "github.com/bacalhau-project/bacalhau/pkg/authn"
 type AuthenticatorConfig struct {
 	Type       authn.MethodType `yaml:"Type"`
 	PolicyPath string           `yaml:"PolicyPath,omitempty"`
+	// OIDC configures the identity provider of authenticators of type "oidc".
+	OIDC OIDCConfig `yaml:"OIDC,omitempty"`
+}
+
+// OIDCConfig is config for an OpenID Connect identity provider that users sign
+// in with to authenticate.
+type OIDCConfig struct {
+	// Issuer is the URL of the identity provider.
+	Issuer string `yaml:"Issuer,omitempty"`
+	// ClientID is the ID of the client registered with the identity provider
+	// for Bacalhau. The client must be a public client that supports either the
+	// device authorization grant or the authorization code grant with PKCE and
+	// a loopback redirect URI.
+	ClientID string `yaml:"ClientID,omitempty"`
+	// Scopes are additional scopes to request when signing in, for example to
+	// include the groups of the user in the ID token.
+	Scopes []string `yaml:"Scopes,omitempty"`
+	// JWKSURL overrides the URL of the signing keys of the identity provider.
+	JWKSURL string `yaml:"JWKSURL,omitempty"`
 }
 
 // AuthConfig is config that controls user authentication and authorization.
@@ -13,8 +13,11 @@ type ComputeConfig struct {
 	Queue                QueueConfig               `yaml:"Queue"`
 	Logging              LoggingConfig             `yaml:"Logging"`
 	ManifestCache        DockerCacheConfig         `yaml:"ManifestCache"`
+	DockerRuntime        DockerRuntimeConfig       `yaml:"DockerRuntime"`
+	ProcessExecutor      ProcessExecutorConfig     `yaml:"ProcessExecutor"`
 	LogStreamConfig      LogStreamConfig           `yaml:"LogStream"`
 	LocalPublisher       LocalPublisherConfig      `yaml:"LocalPublisher"`
+	PortsAddress         string                    `yaml:"PortsAddress"`
 	ControlPlaneSettings ComputeControlPlaneConfig `yaml:"ClusterTimeouts"`
 }
 
@@ -46,6 +49,9 @@ type JobTimeoutConfig struct {
 }
 
 type QueueConfig struct {
+	// EnablePreemption allows higher priority executions to preempt lower priority running executions of
+	// preemptible jobs when the node is out of capacity. Preempted executions are rescheduled by the requester.
+	EnablePreemption bool `yaml:"EnablePreemption"`
 }
 
 type LoggingConfig struct {
@@ -56,6 +62,63 @@ type LoggingConfig struct {
 type LogStreamConfig struct {
 	// How many messages to buffer in the log stream channel, per stream
 	ChannelBufferSize int `yaml:"ChannelBufferSize"`
+	// Persistence of the logs of executions, so that they can be retrieved after executions complete
+	Persistence LogPersistenceConfig `yaml:"Persistence"`
+}
+
+type LogPersistenceConfig struct {
+	Enabled bool `yaml:"Enabled"`
+	// Directory where the logs of executions are persisted
+	Directory string `yaml:"Directory"`
+	// MaxFileSize is the size after which the log file of an execution is rotated, e.g. 10MB
+	MaxFileSize string `yaml:"MaxFileSize"`
+	// MaxFiles is the number of log files kept per execution, the oldest being removed first
+	MaxFiles int `yaml:"MaxFiles"`
+	// Retention is how long the logs of an execution are kept after they were last written.
+	// Zero keeps them forever.
+	Retention Duration `yaml:"Retention"`
+	// Publish publishes the logs of executions along with their results
+	Publish bool `yaml:"Publish"`
+}
+
+// DockerRuntime values select how the compute node runs the jobs of the docker engine
+const (
+	// DockerRuntimeDocker runs docker jobs with the docker daemon
+	DockerRuntimeDocker = "docker"
+	// DockerRuntimeOCI runs docker jobs directly with an OCI runtime, without a docker daemon
+	DockerRuntimeOCI = "oci"
+)
+
+type DockerRuntimeConfig struct {
+	// Type is the runtime of docker jobs, either docker or oci
+	Type string `yaml:"Type"`
+	// OCI configures running docker jobs with an OCI runtime
+	OCI OCIRuntimeConfig `yaml:"OCI"`
+}
+
+type OCIRuntimeConfig struct {
+	// Runtime is the OCI runtime binary, e.g. runc or crun
+	Runtime string `yaml:"Runtime"`
+	// Directory stores the images pulled by the compute node, and the bundles of executions
+	Directory string `yaml:"Directory"`
+}
+
+type ProcessExecutorConfig struct {
+	// Enabled allows the node to run jobs of the process engine, which run directly on the host
+	// without any isolation other than the user, cgroup and rlimits of their process.
+	// It should only be enabled on nodes of trusted clusters.
+	Enabled bool `yaml:"Enabled"`
+	// User is the user the processes of jobs run as. It is required when the node runs as root,
+	// and can't be root.
+	User string `yaml:"User"`
+	// AllowedCommands are the commands jobs can run, as absolute paths or glob patterns of absolute paths.
+	// No command is allowed if it is empty.
+	AllowedCommands []string `yaml:"AllowedCommands"`
+	// Directory stores the sandbox directories of executions
+	Directory string `yaml:"Directory"`
+	// MaxProcesses is the maximum number of processes and threads of each execution, enforced by
+	// its cgroup. The executor's default limit is used if it is zero.
+	MaxProcesses int `yaml:"MaxProcesses"`
 }
 
 type LocalPublisherConfig struct {
@@ -12,6 +12,7 @@ const NodeClientAPIClientTLS = "Node.ClientAPI.ClientTLS"
 const NodeClientAPIClientTLSUseTLS = "Node.ClientAPI.ClientTLS.UseTLS"
 const NodeClientAPIClientTLSCACert = "Node.ClientAPI.ClientTLS.CACert"
 const NodeClientAPIClientTLSInsecure = "Node.ClientAPI.ClientTLS.Insecure"
+const NodeClientAPIAPIKey = "Node.ClientAPI.APIKey"
 const NodeClientAPITLS = "Node.ClientAPI.TLS"
 const NodeClientAPITLSAutoCert = "Node.ClientAPI.TLS.AutoCert"
 const NodeClientAPITLSAutoCertCachePath = "Node.ClientAPI.TLS.AutoCertCachePath"
@@ -25,6 +26,7 @@ const NodeServerAPIClientTLS = "Node.ServerAPI.ClientTLS"
 const NodeServerAPIClientTLSUseTLS = "Node.ServerAPI.ClientTLS.UseTLS"
 const NodeServerAPIClientTLSCACert = "Node.ServerAPI.ClientTLS.CACert"
 const NodeServerAPIClientTLSInsecure = "Node.ServerAPI.ClientTLS.Insecure"
+const NodeServerAPIAPIKey = "Node.ServerAPI.APIKey"
 const NodeServerAPITLS = "Node.ServerAPI.TLS"
 const NodeServerAPITLSAutoCert = "Node.ServerAPI.TLS.AutoCert"
 const NodeServerAPITLSAutoCertCachePath = "Node.ServerAPI.TLS.AutoCertCachePath"
@@ -70,6 +72,7 @@ const NodeComputeCapacityQueueResourceLimitsGPU = "Node.Compute.Capacity.QueueRe
 const NodeComputeExecutionStore = "Node.Compute.ExecutionStore"
 const NodeComputeExecutionStoreType = "Node.Compute.ExecutionStore.Type"
 const NodeComputeExecutionStorePath = "Node.Compute.ExecutionStore.Path"
+const NodeComputeExecutionStoreConnectionString = "Node.Compute.ExecutionStore.ConnectionString"
 const NodeComputeJobTimeouts = "Node.Compute.JobTimeouts"
 const NodeComputeJobTimeoutsJobExecutionTimeoutClientIDBypassList = "Node.Compute.JobTimeouts.JobExecutionTimeoutClientIDBypassList"
 const NodeComputeJobTimeoutsJobNegotiationTimeout = "Node.Compute.JobTimeouts.JobNegotiationTimeout"
@@ -83,18 +86,38 @@ const NodeComputeJobSelectionAcceptNetworkedJobs = "Node.Compute.JobSelection.Ac
 const NodeComputeJobSelectionProbeHTTP = "Node.Compute.JobSelection.ProbeHTTP"
 const NodeComputeJobSelectionProbeExec = "Node.Compute.JobSelection.ProbeExec"
 const NodeComputeQueue = "Node.Compute.Queue"
+const NodeComputeQueueEnablePreemption = "Node.Compute.Queue.EnablePreemption"
 const NodeComputeLogging = "Node.Compute.Logging"
 const NodeComputeLoggingLogRunningExecutionsInterval = "Node.Compute.Logging.LogRunningExecutionsInterval"
 const NodeComputeManifestCache = "Node.Compute.ManifestCache"
 const NodeComputeManifestCacheSize = "Node.Compute.ManifestCache.Size"
 const NodeComputeManifestCacheDuration = "Node.Compute.ManifestCache.Duration"
 const NodeComputeManifestCacheFrequency = "Node.Compute.ManifestCache.Frequency"
+const NodeComputeDockerRuntime = "Node.Compute.DockerRuntime"
+const NodeComputeDockerRuntimeType = "Node.Compute.DockerRuntime.Type"
+const NodeComputeDockerRuntimeOCI = "Node.Compute.DockerRuntime.OCI"
+const NodeComputeDockerRuntimeOCIRuntime = "Node.Compute.DockerRuntime.OCI.Runtime"
+const NodeComputeDockerRuntimeOCIDirectory = "Node.Compute.DockerRuntime.OCI.Directory"
+const NodeComputeProcessExecutor = "Node.Compute.ProcessExecutor"
+const NodeComputeProcessExecutorEnabled = "Node.Compute.ProcessExecutor.Enabled"
+const NodeComputeProcessExecutorUser = "Node.Compute.ProcessExecutor.User"
+const NodeComputeProcessExecutorAllowedCommands = "Node.Compute.ProcessExecutor.AllowedCommands"
+const NodeComputeProcessExecutorDirectory = "Node.Compute.ProcessExecutor.Directory"
+const NodeComputeProcessExecutorMaxProcesses = "Node.Compute.ProcessExecutor.MaxProcesses"
 const NodeComputeLogStreamConfig = "Node.Compute.LogStreamConfig"
 const NodeComputeLogStreamConfigChannelBufferSize = "Node.Compute.LogStreamConfig.ChannelBufferSize"
+const NodeComputeLogStreamConfigPersistence = "Node.Compute.LogStreamConfig.Persistence"
+const NodeComputeLogStreamConfigPersistenceEnabled = "Node.Compute.LogStreamConfig.Persistence.Enabled"
+const NodeComputeLogStreamConfigPersistenceDirectory = "Node.Compute.LogStreamConfig.Persistence.Directory"
+const NodeComputeLogStreamConfigPersistenceMaxFileSize = "Node.Compute.LogStreamConfig.Persistence.MaxFileSize"
+const NodeComputeLogStreamConfigPersistenceMaxFiles = "Node.Compute.LogStreamConfig.Persistence.MaxFiles"
+const NodeComputeLogStreamConfigPersistenceRetention = "Node.Compute.LogStreamConfig.Persistence.Retention"
+const NodeComputeLogStreamConfigPersistencePublish = "Node.Compute.LogStreamConfig.Persistence.Publish"
 const NodeComputeLocalPublisher = "Node.Compute.LocalPublisher"
 const NodeComputeLocalPublisherAddress = "Node.Compute.LocalPublisher.Address"
 const NodeComputeLocalPublisherPort = "Node.Compute.LocalPublisher.Port"
 const NodeComputeLocalPublisherDirectory = "Node.Compute.LocalPublisher.Directory"
+const NodeComputePortsAddress = "Node.Compute.PortsAddress"
 const NodeComputeControlPlaneSettings = "Node.Compute.ControlPlaneSettings"
 const NodeComputeControlPlaneSettingsInfoUpdateFrequency = "Node.Compute.ControlPlaneSettings.InfoUpdateFrequency"
 const NodeComputeControlPlaneSettingsResourceUpdateFrequency = "Node.Compute.ControlPlaneSettings.ResourceUpdateFrequency"
@@ -113,6 +136,11 @@ const NodeRequesterJobSelectionPolicyProbeExec = "Node.Requester.JobSelectionPol
 const NodeRequesterJobStore = "Node.Requester.JobStore"
 const NodeRequesterJobStoreType = "Node.Requester.JobStore.Type"
 const NodeRequesterJobStorePath = "Node.Requester.JobStore.Path"
+const NodeRequesterJobStoreConnectionString = "Node.Requester.JobStore.ConnectionString"
+const NodeRequesterJobRetention = "Node.Requester.JobRetention"
+const NodeRequesterJobRetentionInterval = "Node.Requester.JobRetention.Interval"
+const NodeRequesterJobRetentionPolicies = "Node.Requester.JobRetention.Policies"
+const NodeRequesterJobRetentionCompactOnStartup = "Node.Requester.JobRetention.CompactOnStartup"
 const NodeRequesterHousekeepingBackgroundTaskInterval = "Node.Requester.HousekeepingBackgroundTaskInterval"
 const NodeRequesterNodeRankRandomnessRange = "Node.Requester.NodeRankRandomnessRange"
 const NodeRequesterOverAskForBidsFactor = "Node.Requester.OverAskForBidsFactor"
@@ -143,6 +171,7 @@ const NodeRequesterControlPlaneSettingsHeartbeatCheckFrequency = "Node.Requester
 const NodeRequesterControlPlaneSettingsHeartbeatTopic = "Node.Requester.ControlPlaneSettings.HeartbeatTopic"
 const NodeRequesterControlPlaneSettingsNodeDisconnectedAfter = "Node.Requester.ControlPlaneSettings.NodeDisconnectedAfter"
 const NodeRequesterManualNodeApproval = "Node.Requester.ManualNodeApproval"
+const NodeRequesterDrainGracePeriod = "Node.Requester.DrainGracePeriod"
 const NodeBootstrapAddresses = "Node.BootstrapAddresses"
 const NodeDownloadURLRequestRetries = "Node.DownloadURLRequestRetries"
 const NodeDownloadURLRequestTimeout = "Node.DownloadURLRequestTimeout"
@@ -173,6 +202,14 @@ const NodeNetworkClusterName = "Node.Network.Cluster.Name"
 const NodeNetworkClusterPort = "Node.Network.Cluster.Port"
 const NodeNetworkClusterAdvertisedAddress = "Node.Network.Cluster.AdvertisedAddress"
 const NodeNetworkClusterPeers = "Node.Network.Cluster.Peers"
+const NodeNetworkTLS = "Node.Network.TLS"
+const NodeNetworkTLSCertFile = "Node.Network.TLS.CertFile"
+const NodeNetworkTLSKeyFile = "Node.Network.TLS.KeyFile"
+const NodeNetworkTLSCACertFile = "Node.Network.TLS.CACertFile"
+const NodeNetworkTLSVerifyClients = "Node.Network.TLS.VerifyClients"
+const NodeNetworkNodeCredentials = "Node.Network.NodeCredentials"
+const NodeNetworkNodeCredentialsRequired = "Node.Network.NodeCredentials.Required"
+const NodeNetworkNodeCredentialsTTL = "Node.Network.NodeCredentials.TTL"
 const NodeStrictVersionMatch = "Node.StrictVersionMatch"
 const User = "User"
 const UserKeyPath = "User.KeyPath"
@@ -34,6 +34,7 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeClientAPIClientTLSUseTLS, cfg.Node.ClientAPI.ClientTLS.UseTLS)
 	p.Viper.SetDefault(NodeClientAPIClientTLSCACert, cfg.Node.ClientAPI.ClientTLS.CACert)
 	p.Viper.SetDefault(NodeClientAPIClientTLSInsecure, cfg.Node.ClientAPI.ClientTLS.Insecure)
+	p.Viper.SetDefault(NodeClientAPIAPIKey, cfg.Node.ClientAPI.APIKey)
 	p.Viper.SetDefault(NodeClientAPITLS, cfg.Node.ClientAPI.TLS)
 	p.Viper.SetDefault(NodeClientAPITLSAutoCert, cfg.Node.ClientAPI.TLS.AutoCert)
 	p.Viper.SetDefault(NodeClientAPITLSAutoCertCachePath, cfg.Node.ClientAPI.TLS.AutoCertCachePath)
@@ -47,6 +48,7 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeServerAPIClientTLSUseTLS, cfg.Node.ServerAPI.ClientTLS.UseTLS)
 	p.Viper.SetDefault(NodeServerAPIClientTLSCACert, cfg.Node.ServerAPI.ClientTLS.CACert)
 	p.Viper.SetDefault(NodeServerAPIClientTLSInsecure, cfg.Node.ServerAPI.ClientTLS.Insecure)
+	p.Viper.SetDefault(NodeServerAPIAPIKey, cfg.Node.ServerAPI.APIKey)
 	p.Viper.SetDefault(NodeServerAPITLS, cfg.Node.ServerAPI.TLS)
 	p.Viper.SetDefault(NodeServerAPITLSAutoCert, cfg.Node.ServerAPI.TLS.AutoCert)
 	p.Viper.SetDefault(NodeServerAPITLSAutoCertCachePath, cfg.Node.ServerAPI.TLS.AutoCertCachePath)
@@ -92,6 +94,7 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeComputeExecutionStore, cfg.Node.Compute.ExecutionStore)
 	p.Viper.SetDefault(NodeComputeExecutionStoreType, cfg.Node.Compute.ExecutionStore.Type)
 	p.Viper.SetDefault(NodeComputeExecutionStorePath, cfg.Node.Compute.ExecutionStore.Path)
+	p.Viper.SetDefault(NodeComputeExecutionStoreConnectionString, cfg.Node.Compute.ExecutionStore.ConnectionString)
 	p.Viper.SetDefault(NodeComputeJobTimeouts, cfg.Node.Compute.JobTimeouts)
 	p.Viper.SetDefault(NodeComputeJobTimeoutsJobExecutionTimeoutClientIDBypassList, cfg.Node.Compute.JobTimeouts.JobExecutionTimeoutClientIDBypassList)
 	p.Viper.SetDefault(NodeComputeJobTimeoutsJobNegotiationTimeout, cfg.Node.Compute.JobTimeouts.JobNegotiationTimeout.AsTimeDuration())
@@ -105,18 +108,38 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeComputeJobSelectionProbeHTTP, cfg.Node.Compute.JobSelection.ProbeHTTP)
 	p.Viper.SetDefault(NodeComputeJobSelectionProbeExec, cfg.Node.Compute.JobSelection.ProbeExec)
 	p.Viper.SetDefault(NodeComputeQueue, cfg.Node.Compute.Queue)
+	p.Viper.SetDefault(NodeComputeQueueEnablePreemption, cfg.Node.Compute.Queue.EnablePreemption)
 	p.Viper.SetDefault(NodeComputeLogging, cfg.Node.Compute.Logging)
 	p.Viper.SetDefault(NodeComputeLoggingLogRunningExecutionsInterval, cfg.Node.Compute.Logging.LogRunningExecutionsInterval.AsTimeDuration())
 	p.Viper.SetDefault(NodeComputeManifestCache, cfg.Node.Compute.ManifestCache)
 	p.Viper.SetDefault(NodeComputeManifestCacheSize, cfg.Node.Compute.ManifestCache.Size)
 	p.Viper.SetDefault(NodeComputeManifestCacheDuration, cfg.Node.Compute.ManifestCache.Duration.AsTimeDuration())
 	p.Viper.SetDefault(NodeComputeManifestCacheFrequency, cfg.Node.Compute.ManifestCache.Frequency.AsTimeDuration())
+	p.Viper.SetDefault(NodeComputeDockerRuntime, cfg.Node.Compute.DockerRuntime)
+	p.Viper.SetDefault(NodeComputeDockerRuntimeType, cfg.Node.Compute.DockerRuntime.Type)
+	p.Viper.SetDefault(NodeComputeDockerRuntimeOCI, cfg.Node.Compute.DockerRuntime.OCI)
+	p.Viper.SetDefault(NodeComputeDockerRuntimeOCIRuntime, cfg.Node.Compute.DockerRuntime.OCI.Runtime)
+	p.Viper.SetDefault(NodeComputeDockerRuntimeOCIDirectory, cfg.Node.Compute.DockerRuntime.OCI.Directory)
+	p.Viper.SetDefault(NodeComputeProcessExecutor, cfg.Node.Compute.ProcessExecutor)
+	p.Viper.SetDefault(NodeComputeProcessExecutorEnabled, cfg.Node.Compute.ProcessExecutor.Enabled)
+	p.Viper.SetDefault(NodeComputeProcessExecutorUser, cfg.Node.Compute.ProcessExecutor.User)
+	p.Viper.SetDefault(NodeComputeProcessExecutorAllowedCommands, cfg.Node.Compute.ProcessExecutor.AllowedCommands)
+	p.Viper.SetDefault(NodeComputeProcessExecutorDirectory, cfg.Node.Compute.ProcessExecutor.Directory)
+	p.Viper.SetDefault(NodeComputeProcessExecutorMaxProcesses, cfg.Node.Compute.ProcessExecutor.MaxProcesses)
 	p.Viper.SetDefault(NodeComputeLogStreamConfig, cfg.Node.Compute.LogStreamConfig)
 	p.Viper.SetDefault(NodeComputeLogStreamConfigChannelBufferSize, cfg.Node.Compute.LogStreamConfig.ChannelBufferSize)
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistence, cfg.Node.Compute.LogStreamConfig.Persistence)
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistenceEnabled, cfg.Node.Compute.LogStreamConfig.Persistence.Enabled)
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistenceDirectory, cfg.Node.Compute.LogStreamConfig.Persistence.Directory)
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistenceMaxFileSize, cfg.Node.Compute.LogStreamConfig.Persistence.MaxFileSize)
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistenceMaxFiles, cfg.Node.Compute.LogStreamConfig.Persistence.MaxFiles)
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistenceRetention, cfg.Node.Compute.LogStreamConfig.Persistence.Retention.AsTimeDuration())
+	p.Viper.SetDefault(NodeComputeLogStreamConfigPersistencePublish, cfg.Node.Compute.LogStreamConfig.Persistence.Publish)
 	p.Viper.SetDefault(NodeComputeLocalPublisher, cfg.Node.Compute.LocalPublisher)
 	p.Viper.SetDefault(NodeComputeLocalPublisherAddress, cfg.Node.Compute.LocalPublisher.Address)
 	p.Viper.SetDefault(NodeComputeLocalPublisherPort, cfg.Node.Compute.LocalPublisher.Port)
 	p.Viper.SetDefault(NodeComputeLocalPublisherDirectory, cfg.Node.Compute.LocalPublisher.Directory)
+	p.Viper.SetDefault(NodeComputePortsAddress, cfg.Node.Compute.PortsAddress)
 	p.Viper.SetDefault(NodeComputeControlPlaneSettings, cfg.Node.Compute.ControlPlaneSettings)
 	p.Viper.SetDefault(NodeComputeControlPlaneSettingsInfoUpdateFrequency, cfg.Node.Compute.ControlPlaneSettings.InfoUpdateFrequency.AsTimeDuration())
 	p.Viper.SetDefault(NodeComputeControlPlaneSettingsResourceUpdateFrequency, cfg.Node.Compute.ControlPlaneSettings.ResourceUpdateFrequency.AsTimeDuration())
@@ -135,6 +158,11 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeRequesterJobStore, cfg.Node.Requester.JobStore)
 	p.Viper.SetDefault(NodeRequesterJobStoreType, cfg.Node.Requester.JobStore.Type)
 	p.Viper.SetDefault(NodeRequesterJobStorePath, cfg.Node.Requester.JobStore.Path)
+	p.Viper.SetDefault(NodeRequesterJobStoreConnectionString, cfg.Node.Requester.JobStore.ConnectionString)
+	p.Viper.SetDefault(NodeRequesterJobRetention, cfg.Node.Requester.JobRetention)
+	p.Viper.SetDefault(NodeRequesterJobRetentionInterval, cfg.Node.Requester.JobRetention.Interval.AsTimeDuration())
+	p.Viper.SetDefault(NodeRequesterJobRetentionPolicies, cfg.Node.Requester.JobRetention.Policies)
+	p.Viper.SetDefault(NodeRequesterJobRetentionCompactOnStartup, cfg.Node.Requester.JobRetention.CompactOnStartup)
 	p.Viper.SetDefault(NodeRequesterHousekeepingBackgroundTaskInterval, cfg.Node.Requester.HousekeepingBackgroundTaskInterval.AsTimeDuration())
 	p.Viper.SetDefault(NodeRequesterNodeRankRandomnessRange, cfg.Node.Requester.NodeRankRandomnessRange)
 	p.Viper.SetDefault(NodeRequesterOverAskForBidsFactor, cfg.Node.Requester.OverAskForBidsFactor)
@@ -165,6 +193,7 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeRequesterControlPlaneSettingsHeartbeatTopic, cfg.Node.Requester.ControlPlaneSettings.HeartbeatTopic)
 	p.Viper.SetDefault(NodeRequesterControlPlaneSettingsNodeDisconnectedAfter, cfg.Node.Requester.ControlPlaneSettings.NodeDisconnectedAfter.AsTimeDuration())
 	p.Viper.SetDefault(NodeRequesterManualNodeApproval, cfg.Node.Requester.ManualNodeApproval)
+	p.Viper.SetDefault(NodeRequesterDrainGracePeriod, cfg.Node.Requester.DrainGracePeriod.AsTimeDuration())
 	p.Viper.SetDefault(NodeBootstrapAddresses, cfg.Node.BootstrapAddresses)
 	p.Viper.SetDefault(NodeDownloadURLRequestRetries, cfg.Node.DownloadURLRequestRetries)
 	p.Viper.SetDefault(NodeDownloadURLRequestTimeout, cfg.Node.DownloadURLRequestTimeout.AsTimeDuration())
@@ -195,6 +224,14 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeNetworkClusterPort, cfg.Node.Network.Cluster.Port)
 	p.Viper.SetDefault(NodeNetworkClusterAdvertisedAddress, cfg.Node.Network.Cluster.AdvertisedAddress)
 	p.Viper.SetDefault(NodeNetworkClusterPeers, cfg.Node.Network.Cluster.Peers)
+	p.Viper.SetDefault(NodeNetworkTLS, cfg.Node.Network.TLS)
+	p.Viper.SetDefault(NodeNetworkTLSCertFile, cfg.Node.Network.TLS.CertFile)
+	p.Viper.SetDefault(NodeNetworkTLSKeyFile, cfg.Node.Network.TLS.KeyFile)
+	p.Viper.SetDefault(NodeNetworkTLSCACertFile, cfg.Node.Network.TLS.CACertFile)
+	p.Viper.SetDefault(NodeNetworkTLSVerifyClients, cfg.Node.Network.TLS.VerifyClients)
+	p.Viper.SetDefault(NodeNetworkNodeCredentials, cfg.Node.Network.NodeCredentials)
+	p.Viper.SetDefault(NodeNetworkNodeCredentialsRequired, cfg.Node.Network.NodeCredentials.Required)
+	p.Viper.SetDefault(NodeNetworkNodeCredentialsTTL, cfg.Node.Network.NodeCredentials.TTL.AsTimeDuration())
 	p.Viper.SetDefault(NodeStrictVersionMatch, cfg.Node.StrictVersionMatch)
 	p.Viper.SetDefault(User, cfg.User)
 	p.Viper.SetDefault(UserKeyPath, cfg.User.KeyPath)
@@ -232,6 +269,7 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeClientAPIClientTLSUseTLS, cfg.Node.ClientAPI.ClientTLS.UseTLS)
 	p.Viper.Set(NodeClientAPIClientTLSCACert, cfg.Node.ClientAPI.ClientTLS.CACert)
 	p.Viper.Set(NodeClientAPIClientTLSInsecure, cfg.Node.ClientAPI.ClientTLS.Insecure)
+	p.Viper.Set(NodeClientAPIAPIKey, cfg.Node.ClientAPI.APIKey)
 	p.Viper.Set(NodeClientAPITLS, cfg.Node.ClientAPI.TLS)
 	p.Viper.Set(NodeClientAPITLSAutoCert, cfg.Node.ClientAPI.TLS.AutoCert)
 	p.Viper.Set(NodeClientAPITLSAutoCertCachePath, cfg.Node.ClientAPI.TLS.AutoCertCachePath)
@@ -245,6 +283,7 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeServerAPIClientTLSUseTLS, cfg.Node.ServerAPI.ClientTLS.UseTLS)
 	p.Viper.Set(NodeServerAPIClientTLSCACert, cfg.Node.ServerAPI.ClientTLS.CACert)
 	p.Viper.Set(NodeServerAPIClientTLSInsecure, cfg.Node.ServerAPI.ClientTLS.Insecure)
+	p.Viper.Set(NodeServerAPIAPIKey, cfg.Node.ServerAPI.APIKey)
 	p.Viper.Set(NodeServerAPITLS, cfg.Node.ServerAPI.TLS)
 	p.Viper.Set(NodeServerAPITLSAutoCert, cfg.Node.ServerAPI.TLS.AutoCert)
 	p.Viper.Set(NodeServerAPITLSAutoCertCachePath, cfg.Node.ServerAPI.TLS.AutoCertCachePath)
@@ -290,6 +329,7 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeComputeExecutionStore, cfg.Node.Compute.ExecutionStore)
 	p.Viper.Set(NodeComputeExecutionStoreType, cfg.Node.Compute.ExecutionStore.Type)
 	p.Viper.Set(NodeComputeExecutionStorePath, cfg.Node.Compute.ExecutionStore.Path)
+	p.Viper.Set(NodeComputeExecutionStoreConnectionString, cfg.Node.Compute.ExecutionStore.ConnectionString)
 	p.Viper.Set(NodeComputeJobTimeouts, cfg.Node.Compute.JobTimeouts)
 	p.Viper.Set(NodeComputeJobTimeoutsJobExecutionTimeoutClientIDBypassList, cfg.Node.Compute.JobTimeouts.JobExecutionTimeoutClientIDBypassList)
 	p.Viper.Set(NodeComputeJobTimeoutsJobNegotiationTimeout, cfg.Node.Compute.JobTimeouts.JobNegotiationTimeout.AsTimeDuration())
@@ -303,18 +343,38 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeComputeJobSelectionProbeHTTP, cfg.Node.Compute.JobSelection.ProbeHTTP)
 	p.Viper.Set(NodeComputeJobSelectionProbeExec, cfg.Node.Compute.JobSelection.ProbeExec)
 	p.Viper.Set(NodeComputeQueue, cfg.Node.Compute.Queue)
+	p.Viper.Set(NodeComputeQueueEnablePreemption, cfg.Node.Compute.Queue.EnablePreemption)
 	p.Viper.Set(NodeComputeLogging, cfg.Node.Compute.Logging)
 	p.Viper.Set(NodeComputeLoggingLogRunningExecutionsInterval, cfg.Node.Compute.Logging.LogRunningExecutionsInterval.AsTimeDuration())
 	p.Viper.Set(NodeComputeManifestCache, cfg.Node.Compute.ManifestCache)
 	p.Viper.Set(NodeComputeManifestCacheSize, cfg.Node.Compute.ManifestCache.Size)
 	p.Viper.Set(NodeComputeManifestCacheDuration, cfg.Node.Compute.ManifestCache.Duration.AsTimeDuration())
 	p.Viper.Set(NodeComputeManifestCacheFrequency, cfg.Node.Compute.ManifestCache.Frequency.AsTimeDuration())
+	p.Viper.Set(NodeComputeDockerRuntime, cfg.Node.Compute.DockerRuntime)
+	p.Viper.Set(NodeComputeDockerRuntimeType, cfg.Node.Compute.DockerRuntime.Type)
+	p.Viper.Set(NodeComputeDockerRuntimeOCI, cfg.Node.Compute.DockerRuntime.OCI)
+	p.Viper.Set(NodeComputeDockerRuntimeOCIRuntime, cfg.Node.Compute.DockerRuntime.OCI.Runtime)
+	p.Viper.Set(NodeComputeDockerRuntimeOCIDirectory, cfg.Node.Compute.DockerRuntime.OCI.Directory)
+	p.Viper.Set(NodeComputeProcessExecutor, cfg.Node.Compute.ProcessExecutor)
+	p.Viper.Set(NodeComputeProcessExecutorEnabled, cfg.Node.Compute.ProcessExecutor.Enabled)
+	p.Viper.Set(NodeComputeProcessExecutorUser, cfg.Node.Compute.ProcessExecutor.User)
+	p.Viper.Set(NodeComputeProcessExecutorAllowedCommands, cfg.Node.Compute.ProcessExecutor.AllowedCommands)
+	p.Viper.Set(NodeComputeProcessExecutorDirectory, cfg.Node.Compute.ProcessExecutor.Directory)
+	p.Viper.Set(NodeComputeProcessExecutorMaxProcesses, cfg.Node.Compute.ProcessExecutor.MaxProcesses)
 	p.Viper.Set(NodeComputeLogStreamConfig, cfg.Node.Compute.LogStreamConfig)
 	p.Viper.Set(NodeComputeLogStreamConfigChannelBufferSize, cfg.Node.Compute.LogStreamConfig.ChannelBufferSize)
+	p.Viper.Set(NodeComputeLogStreamConfigPersistence, cfg.Node.Compute.LogStreamConfig.Persistence)
+	p.Viper.Set(NodeComputeLogStreamConfigPersistenceEnabled, cfg.Node.Compute.LogStreamConfig.Persistence.Enabled)
+	p.Viper.Set(NodeComputeLogStreamConfigPersistenceDirectory, cfg.Node.Compute.LogStreamConfig.Persistence.Directory)
+	p.Viper.Set(NodeComputeLogStreamConfigPersistenceMaxFileSize, cfg.Node.Compute.LogStreamConfig.Persistence.MaxFileSize)
+	p.Viper.Set(NodeComputeLogStreamConfigPersistenceMaxFiles, cfg.Node.Compute.LogStreamConfig.Persistence.MaxFiles)
+	p.Viper.Set(NodeComputeLogStreamConfigPersistenceRetention, cfg.Node.Compute.LogStreamConfig.Persistence.Retention.AsTimeDuration())
+	p.Viper.Set(NodeComputeLogStreamConfigPersistencePublish, cfg.Node.Compute.LogStreamConfig.Persistence.Publish)
 	p.Viper.Set(NodeComputeLocalPublisher, cfg.Node.Compute.LocalPublisher)
 	p.Viper.Set(NodeComputeLocalPublisherAddress, cfg.Node.Compute.LocalPublisher.Address)
 	p.Viper.Set(NodeComputeLocalPublisherPort, cfg.Node.Compute.LocalPublisher.Port)
 	p.Viper.Set(NodeComputeLocalPublisherDirectory, cfg.Node.Compute.LocalPublisher.Directory)
+	p.Viper.Set(NodeComputePortsAddress, cfg.Node.Compute.PortsAddress)
 	p.Viper.Set(NodeComputeControlPlaneSettings, cfg.Node.Compute.ControlPlaneSettings)
 	p.Viper.Set(NodeComputeControlPlaneSettingsInfoUpdateFrequency, cfg.Node.Compute.ControlPlaneSettings.InfoUpdateFrequency.AsTimeDuration())
 	p.Viper.Set(NodeComputeControlPlaneSettingsResourceUpdateFrequency, cfg.Node.Compute.ControlPlaneSettings.ResourceUpdateFrequency.AsTimeDuration())
@@ -333,6 +393,11 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeRequesterJobStore, cfg.Node.Requester.JobStore)
 	p.Viper.Set(NodeRequesterJobStoreType, cfg.Node.Requester.JobStore.Type)
 	p.Viper.Set(NodeRequesterJobStorePath, cfg.Node.Requester.JobStore.Path)
+	p.Viper.Set(NodeRequesterJobStoreConnectionString, cfg.Node.Requester.JobStore.ConnectionString)
+	p.Viper.Set(NodeRequesterJobRetention, cfg.Node.Requester.JobRetention)
+	p.Viper.Set(NodeRequesterJobRetentionInterval, cfg.Node.Requester.JobRetention.Interval.AsTimeDuration())
+	p.Viper.Set(NodeRequesterJobRetentionPolicies, cfg.Node.Requester.JobRetention.Policies)
+	p.Viper.Set(NodeRequesterJobRetentionCompactOnStartup, cfg.Node.Requester.JobRetention.CompactOnStartup)
 	p.Viper.Set(NodeRequesterHousekeepingBackgroundTaskInterval, cfg.Node.Requester.HousekeepingBackgroundTaskInterval.AsTimeDuration())
 	p.Viper.Set(NodeRequesterNodeRankRandomnessRange, cfg.Node.Requester.NodeRankRandomnessRange)
 	p.Viper.Set(NodeRequesterOverAskForBidsFactor, cfg.Node.Requester.OverAskForBidsFactor)
@@ -363,6 +428,7 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeRequesterControlPlaneSettingsHeartbeatTopic, cfg.Node.Requester.ControlPlaneSettings.HeartbeatTopic)
 	p.Viper.Set(NodeRequesterControlPlaneSettingsNodeDisconnectedAfter, cfg.Node.Requester.ControlPlaneSettings.NodeDisconnectedAfter.AsTimeDuration())
 	p.Viper.Set(NodeRequesterManualNodeApproval, cfg.Node.Requester.ManualNodeApproval)
+	p.Viper.Set(NodeRequesterDrainGracePeriod, cfg.Node.Requester.DrainGracePeriod.AsTimeDuration())
 	p.Viper.Set(NodeBootstrapAddresses, cfg.Node.BootstrapAddresses)
 	p.Viper.Set(NodeDownloadURLRequestRetries, cfg.Node.DownloadURLRequestRetries)
 	p.Viper.Set(NodeDownloadURLRequestTimeout, cfg.Node.DownloadURLRequestTimeout.AsTimeDuration())
@@ -393,6 +459,14 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeNetworkClusterPort, cfg.Node.Network.Cluster.Port)
 	p.Viper.Set(NodeNetworkClusterAdvertisedAddress, cfg.Node.Network.Cluster.AdvertisedAddress)
 	p.Viper.Set(NodeNetworkClusterPeers, cfg.Node.Network.Cluster.Peers)
+	p.Viper.Set(NodeNetworkTLS, cfg.Node.Network.TLS)
+	p.Viper.Set(NodeNetworkTLSCertFile, cfg.Node.Network.TLS.CertFile)
+	p.Viper.Set(NodeNetworkTLSKeyFile, cfg.Node.Network.TLS.KeyFile)
+	p.Viper.Set(NodeNetworkTLSCACertFile, cfg.Node.Network.TLS.CACertFile)
+	p.Viper.Set(NodeNetworkTLSVerifyClients, cfg.Node.Network.TLS.VerifyClients)
+	p.Viper.Set(NodeNetworkNodeCredentials, cfg.Node.Network.NodeCredentials)
+	p.Viper.Set(NodeNetworkNodeCredentialsRequired, cfg.Node.Network.NodeCredentials.Required)
+	p.Viper.Set(NodeNetworkNodeCredentialsTTL, cfg.Node.Network.NodeCredentials.TTL.AsTimeDuration())
 	p.Viper.Set(NodeStrictVersionMatch, cfg.Node.StrictVersionMatch)
 	p.Viper.Set(User, cfg.User)
 	p.Viper.Set(UserKeyPath, cfg.User.KeyPath)
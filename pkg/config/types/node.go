@@ -59,6 +59,11 @@ type APIConfig struct {
 	// API.
 	ClientTLS ClientTLSConfig `yaml:"ClientTLS"`
 
+	// APIKey is used for NodeConfig.ClientAPI, and is an API key issued by the
+	// requester that the client authenticates with instead of running an
+	// interactive authentication flow.
+	APIKey string `yaml:"APIKey,omitempty"`
+
 	// TLS returns information about how TLS is configured for the public server.
 	// This is only used in APIConfig for NodeConfig.ServerAPI
 	TLS TLSConfiguration `yaml:"TLS"`
@@ -161,6 +166,23 @@ type NetworkConfig struct {
 	Orchestrators     []string             `yaml:"Orchestrators"`
 	StoreDir          string               `yaml:"StoreDir"`
 	Cluster           NetworkClusterConfig `yaml:"Cluster"`
+	TLS               NetworkTLSConfig     `yaml:"TLS"`
+	// NodeCredentials configures the credentials that orchestrators issue to approved compute nodes
+	NodeCredentials NetworkNodeCredentialsConfig `yaml:"NodeCredentials"`
+}
+
+type NetworkTLSConfig struct {
+	CertFile      string `yaml:"CertFile"`
+	KeyFile       string `yaml:"KeyFile"`
+	CACertFile    string `yaml:"CACertFile"`
+	VerifyClients bool   `yaml:"VerifyClients"`
+}
+
+type NetworkNodeCredentialsConfig struct {
+	// Required requires compute nodes to connect with their issued credentials once approved
+	Required bool `yaml:"Required"`
+	// TTL is how long issued credentials are valid for. Defaults to one hour.
+	TTL Duration `yaml:"TTL"`
 }
 
 type NetworkClusterConfig struct {
@@ -11,6 +11,7 @@ type RequesterConfig struct {
 	// How the node decides what jobs to run.
 	JobSelectionPolicy model.JobSelectionPolicy `yaml:"JobSelectionPolicy"`
 	JobStore           JobStoreConfig           `yaml:"JobStore"`
+	JobRetention       JobRetentionConfig       `yaml:"JobRetention"`
 
 	HousekeepingBackgroundTaskInterval Duration                              `yaml:"HousekeepingBackgroundTaskInterval"`
 	NodeRankRandomnessRange            int                                   `yaml:"NodeRankRandomnessRange"`
@@ -32,6 +33,35 @@ type RequesterConfig struct {
 	// By default, nodes are auto-approved to simplify upgrades, by setting this property to
 	// true, nodes will need to be manually approved before they are included in node selection.
 	ManualNodeApproval bool `yaml:"ManualNodeApproval"`
+
+	// DrainGracePeriod is how long running executions of long-running jobs are given to be
+	// replaced on other nodes when draining a node, if the drain request does not set one.
+	DrainGracePeriod Duration `yaml:"DrainGracePeriod"`
+}
+
+// JobRetentionConfig configures how long terminal jobs are kept in the job store.
+type JobRetentionConfig struct {
+	// Interval is how often terminal jobs are pruned from the job store. Zero disables the pruning.
+	Interval Duration `yaml:"Interval"`
+	// Policies are the retention policies, in order of precedence. A terminal job is retained
+	// by the first policy that matches it, and jobs that match no policy are kept forever.
+	Policies []JobRetentionPolicy `yaml:"Policies"`
+	// CompactOnStartup rewrites the BoltDB job store when the requester starts, to give back
+	// to the file system the space freed by the pruned jobs.
+	CompactOnStartup bool `yaml:"CompactOnStartup"`
+}
+
+type JobRetentionPolicy struct {
+	// Namespace is the namespace the policy applies to. Empty means all namespaces.
+	Namespace string `yaml:"Namespace"`
+	// States are the terminal job states the policy applies to, e.g. Completed, Failed
+	// or Stopped. Empty means all terminal states.
+	States []string `yaml:"States"`
+	// MaxAge is how long jobs are kept after they last changed state. Zero means no age limit.
+	MaxAge Duration `yaml:"MaxAge"`
+	// KeepLast is the number of most recent jobs matching the policy that are kept in
+	// each namespace, regardless of their age.
+	KeepLast int `yaml:"KeepLast"`
 }
 
 type EvaluationBrokerConfig struct {
@@ -11,16 +11,26 @@ import (
 type JobStoreConfig struct {
 	Type StorageType `yaml:"Type"`
 	Path string      `yaml:"Path"`
+	// ConnectionString is the connection string of the database when using Postgres,
+	// e.g. postgres://user@localhost/bacalhau?sslmode=disable
+	ConnectionString string `yaml:"ConnectionString"`
 }
 
 func (cfg JobStoreConfig) Validate() error {
 	var err error
-	if cfg.Type <= UnknownStorage || cfg.Type > BoltDB {
+	if cfg.Type <= UnknownStorage || cfg.Type > Postgres {
 		err = errors.Join(err, fmt.Errorf("unknown execution store type: %q", cfg.Type.String()))
 	}
 
-	if cfg.Path == "" {
-		err = errors.Join(err, fmt.Errorf("execution store path is missing"))
+	switch cfg.Type {
+	case Postgres:
+		if cfg.ConnectionString == "" {
+			err = errors.Join(err, fmt.Errorf("execution store connection string is missing"))
+		}
+	default:
+		if cfg.Path == "" {
+			err = errors.Join(err, fmt.Errorf("execution store path is missing"))
+		}
 	}
 
 	return err
@@ -32,6 +42,7 @@ type StorageType int64
 const (
 	UnknownStorage StorageType = 0
 	BoltDB         StorageType = 1
+	Postgres       StorageType = 2
 )
 
 func (j *StorageType) UnmarshalText(text []byte) error {
@@ -57,13 +68,13 @@ func (j *StorageType) UnmarshalYAML(value *yaml.Node) error {
 }
 
 func ParseStorageType(s string) (ret StorageType, err error) {
-	for typ := UnknownStorage; typ <= BoltDB; typ++ {
+	for typ := UnknownStorage; typ <= Postgres; typ++ {
 		if equal(typ.String(), s) {
 			return typ, nil
 		}
 	}
 
-	return UnknownStorage, fmt.Errorf("StorageType: unknown type '%s' (valid types: %q)", s, []StorageType{BoltDB})
+	return UnknownStorage, fmt.Errorf("StorageType: unknown type '%s' (valid types: %q)", s, []StorageType{BoltDB, Postgres})
 }
 
 func equal(a, b string) bool {
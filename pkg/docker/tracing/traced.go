@@ -111,6 +111,34 @@ func (c TracedClient) ContainerWait(
 	return telemetry.RecordErrorOnSpanTwoChannels[container.WaitResponse](span)(c.client.ContainerWait(ctx, containerID, condition))
 }
 
+func (c TracedClient) ContainerExecCreate(ctx context.Context, containerID string, config types.ExecConfig) (types.IDResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.create")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.IDResponse](span)(c.client.ContainerExecCreate(ctx, containerID, config))
+}
+
+func (c TracedClient) ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.attach")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.HijackedResponse](span)(c.client.ContainerExecAttach(ctx, execID, config))
+}
+
+func (c TracedClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	ctx, span := c.span(ctx, "container.exec.inspect")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.ContainerExecInspect](span)(c.client.ContainerExecInspect(ctx, execID))
+}
+
+func (c TracedClient) ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error {
+	ctx, span := c.span(ctx, "container.exec.resize")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.ContainerExecResize(ctx, execID, options))
+}
+
 func (c TracedClient) CopyFromContainer(ctx context.Context, containerID, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
 	ctx, span := c.span(ctx, "container.cp")
 	// span ends when the io.ReadCloser is closed
@@ -120,6 +148,14 @@ func (c TracedClient) CopyFromContainer(ctx context.Context, containerID, srcPat
 	)
 }
 
+func (c TracedClient) CopyToContainer(
+	ctx context.Context, containerID, dstPath string, content io.Reader, options types.CopyToContainerOptions) error {
+	ctx, span := c.span(ctx, "container.cp")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.CopyToContainer(ctx, containerID, dstPath, content, options))
+}
+
 func (c TracedClient) ImageInspectWithRaw(ctx context.Context, imageID string) (types.ImageInspect, []byte, error) {
 	ctx, span := c.span(ctx, "image.inspect")
 	defer span.End()
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -142,6 +144,8 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 			Inputs:        request.Inputs,
 			Outputs:       request.Outputs,
 			ResultsDir:    request.ResultsDir,
+			Env:           request.Env,
+			SecretFiles:   request.SecretFiles,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create docker job container: %w", err)
@@ -279,6 +283,36 @@ func (e *Executor) GetLogStream(ctx context.Context, request executor.LogStreamR
 	return nil, fmt.Errorf("getting outputs for execution (%s): %w", request.ExecutionID, executor.ErrNotFound)
 }
 
+// Exec runs a command inside the container of a running execution, similarly to `docker exec`.
+// It returns an error if the execution is not found or is no longer running.
+func (e *Executor) Exec(ctx context.Context, request executor.ExecRequest) (int, error) {
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found {
+		return 0, fmt.Errorf("exec in execution (%s): %w", request.ExecutionID, executor.ErrNotFound)
+	}
+	return handler.exec(ctx, request)
+}
+
+// Address returns the host at which the container of a running execution can be reached from
+// the compute node.
+func (e *Executor) Address(ctx context.Context, executionID string) (string, error) {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return "", fmt.Errorf("address of execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	return handler.address(ctx)
+}
+
+// PublishedPorts returns the ports of the compute node on which the ports of the container of a
+// running execution are published.
+func (e *Executor) PublishedPorts(ctx context.Context, executionID string) (map[int]int, error) {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return nil, fmt.Errorf("ports of execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	return handler.publishedPorts(ctx)
+}
+
 // Run initiates and waits for the completion of an execution in one call.
 // This method serves as a higher-level convenience function that
 // internally calls Start and Wait methods.
@@ -311,6 +345,8 @@ type dockerJobContainerParams struct {
 	Inputs        []storage.PreparedStorage
 	Outputs       []*models.ResultPath
 	ResultsDir    string
+	Env           map[string]string
+	SecretFiles   map[string][]byte
 }
 
 // newDockerJobContainer is an internal method called by Start to set up a new Docker container
@@ -327,7 +363,7 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 	containerConfig := &container.Config{
 		Image:      dockerArgs.Image,
 		Tty:        false,
-		Env:        dockerArgs.EnvironmentVariables,
+		Env:        containerEnv(dockerArgs.EnvironmentVariables, params.Env),
 		Entrypoint: dockerArgs.Entrypoint,
 		Cmd:        dockerArgs.Parameters,
 		Labels:     e.containerLabels(params.ExecutionID, params.JobID),
@@ -382,6 +418,15 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 	if err != nil {
 		return container.CreateResponse{}, fmt.Errorf("creating container: %w", err)
 	}
+
+	// secret files are copied into the writable layer of the container before it starts, so
+	// they are stored on the disk of the compute node until the container is removed
+	if err = e.copySecretFiles(ctx, jobContainer.ID, params.SecretFiles); err != nil {
+		if removeErr := e.client.RemoveContainer(ctx, jobContainer.ID); removeErr != nil {
+			log.Ctx(ctx).Warn().Err(removeErr).Msg("failed to remove container after failing to copy secrets")
+		}
+		return container.CreateResponse{}, fmt.Errorf("copying secrets to container: %w", err)
+	}
 	return jobContainer, nil
 }
 
@@ -468,7 +513,8 @@ func makeContainerMounts(
 		}
 
 		srcd := filepath.Join(resultsDir, output.Name)
-		if err := os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W); err != nil {
+		// the output already exists if the compute node restored a checkpoint into it
+		if err := os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W); err != nil && !errors.Is(err, fs.ErrExist) {
 			return nil, fmt.Errorf("failed to create results dir for execution: %w", err)
 		}
 
@@ -498,6 +544,19 @@ func (e *Executor) containerName(executionID string, jobID string) string {
 	return e.dockerObjectName(executionID, jobID, "executor")
 }
 
+// containerEnv returns the environment variables of the container, where the variables
+// of the task are appended to the ones of the engine so that they take precedence.
+func containerEnv(engineEnv []string, taskEnv map[string]string) []string {
+	env := make([]string, 0, len(engineEnv)+len(taskEnv))
+	env = append(env, engineEnv...)
+	keys := lo.Keys(taskEnv)
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+taskEnv[key])
+	}
+	return env
+}
+
 func (e *Executor) containerLabels(executionID, jobID string) map[string]string {
 	return map[string]string{
 		labelExecutorName: e.ID,
@@ -516,6 +575,9 @@ func labelExecutionValue(executorID string, executionID string) string {
 
 // Compile-time interface check:
 var _ executor.Executor = (*Executor)(nil)
+var _ executor.Execer = (*Executor)(nil)
+var _ executor.Addresser = (*Executor)(nil)
+var _ executor.PortPublisher = (*Executor)(nil)
 
 // FindRunningContainer, not part of the Executor interface, is a utility function that
 // helps locate a container durin a restart check.
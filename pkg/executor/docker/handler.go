@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -213,6 +215,161 @@ func (h *executionHandler) outputStream(ctx context.Context, request executor.Lo
 	return h.client.GetOutputStream(ctx, h.containerID, since, request.Follow)
 }
 
+// address returns the host at which the container can be reached from the compute node, which is
+// the compute node itself for containers using the host network, or the address of the container
+// in its network otherwise.
+func (h *executionHandler) address(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-h.activeCh:
+	}
+	if !h.active() {
+		return "", fmt.Errorf("address of execution (%s): %w", h.executionID, executor.ErrAlreadyComplete)
+	}
+
+	containerJSON, err := h.client.ContainerInspect(ctx, h.containerID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to inspect docker container")
+	}
+	if containerJSON.HostConfig != nil && containerJSON.HostConfig.NetworkMode.IsHost() {
+		return "127.0.0.1", nil
+	}
+	if containerJSON.NetworkSettings != nil {
+		for _, endpoint := range containerJSON.NetworkSettings.Networks {
+			if endpoint != nil && endpoint.IPAddress != "" {
+				return endpoint.IPAddress, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("execution (%s) has no network address", h.executionID)
+}
+
+// publishedPorts returns the ports of the host on which the ports of the container are
+// published, keyed by the port of the container.
+func (h *executionHandler) publishedPorts(ctx context.Context) (map[int]int, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-h.activeCh:
+	}
+	if !h.active() {
+		return nil, fmt.Errorf("ports of execution (%s): %w", h.executionID, executor.ErrAlreadyComplete)
+	}
+
+	containerJSON, err := h.client.ContainerInspect(ctx, h.containerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to inspect docker container")
+	}
+	ports := make(map[int]int)
+	if containerJSON.NetworkSettings == nil {
+		return ports, nil
+	}
+	for containerPort, bindings := range containerJSON.NetworkSettings.Ports {
+		for _, binding := range bindings {
+			hostPort, err := strconv.Atoi(binding.HostPort)
+			if err != nil {
+				continue
+			}
+			ports[containerPort.Int()] = hostPort
+			break
+		}
+	}
+	return ports, nil
+}
+
+// exec runs a command inside the container, attaching the streams of the request, and returns
+// the exit code of the command once it exits.
+func (h *executionHandler) exec(ctx context.Context, request executor.ExecRequest) (int, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	// We have to wait until the container is started before running commands in it.
+	case <-h.activeCh:
+	}
+	if !h.active() {
+		return 0, fmt.Errorf("exec in execution (%s): %w", h.executionID, executor.ErrAlreadyComplete)
+	}
+
+	var consoleSize *[2]uint
+	if request.TTY && request.Size != nil {
+		consoleSize = &[2]uint{request.Size.Height, request.Size.Width}
+	}
+	created, err := h.client.ContainerExecCreate(ctx, h.containerID, types.ExecConfig{
+		Cmd:          request.Command,
+		Tty:          request.TTY,
+		ConsoleSize:  consoleSize,
+		AttachStdin:  request.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create exec instance")
+	}
+	attached, err := h.client.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{
+		Tty:         request.TTY,
+		ConsoleSize: consoleSize,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to attach to exec instance")
+	}
+	defer attached.Close()
+	// the hijacked connection does not honour the context, so it is closed when the context is done
+	stop := context.AfterFunc(ctx, attached.Close)
+	defer stop()
+
+	if request.Stdin != nil {
+		go func() {
+			if _, copyErr := io.Copy(attached.Conn, request.Stdin); copyErr != nil {
+				h.logger.Debug().Err(copyErr).Msg("failed to write exec input")
+			}
+			_ = attached.CloseWrite()
+		}()
+	}
+	if request.TTY && request.Resize != nil {
+		go h.resizeExec(ctx, created.ID, request.Resize)
+	}
+
+	if request.TTY {
+		_, err = io.Copy(request.Stdout, attached.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(request.Stdout, request.Stderr, attached.Reader)
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read exec output")
+	}
+
+	inspect, err := h.client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to inspect exec instance")
+	}
+	return inspect.ExitCode, nil
+}
+
+// resizeExec resizes the terminal of an exec instance until the resize channel is closed
+func (h *executionHandler) resizeExec(ctx context.Context, execID string, resize <-chan models.TerminalSize) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case size, ok := <-resize:
+			if !ok {
+				return
+			}
+			err := h.client.ContainerExecResize(ctx, execID, container.ResizeOptions{
+				Height: size.Height,
+				Width:  size.Width,
+			})
+			if err != nil {
+				h.logger.Debug().Err(err).Msg("failed to resize exec terminal")
+			}
+		}
+	}
+}
+
 func (h *executionHandler) active() bool {
 	return h.running.Load()
 }
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
 	pkgerrors "github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
@@ -76,6 +78,14 @@ func (e *Executor) setupNetworkForJob(
 	case models.NetworkFull:
 		hostConfig.NetworkMode = dockerNetworkHost
 		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, dockerHostAddCommand)
+		if len(network.Ports) > 0 {
+			// ports can't be mapped on the host network, so containers publishing ports run on
+			// the default bridge network instead
+			log.Ctx(ctx).Debug().Str("execution", executionID).
+				Msg("running container on the bridge network instead of the host network to publish its ports")
+			hostConfig.NetworkMode = dockerNetworkBridge
+			containerConfig.ExposedPorts, hostConfig.PortBindings = portBindings(network.Ports)
+		}
 	case models.NetworkHTTP:
 		var internalNetwork *types.NetworkResource
 		var proxyAddr *net.TCPAddr
@@ -94,6 +104,23 @@ func (e *Executor) setupNetworkForJob(
 	return
 }
 
+// portBindings returns the ports exposed by a container and their bindings on the host. Ports
+// without a static port are bound to a random free port of the host.
+func portBindings(ports []*models.Port) (nat.PortSet, nat.PortMap) {
+	exposed := make(nat.PortSet, len(ports))
+	bindings := make(nat.PortMap, len(ports))
+	for _, port := range ports {
+		containerPort := nat.Port(fmt.Sprintf("%d/tcp", port.Target))
+		hostPort := ""
+		if port.Static != 0 {
+			hostPort = strconv.Itoa(port.Static)
+		}
+		exposed[containerPort] = struct{}{}
+		bindings[containerPort] = append(bindings[containerPort], nat.PortBinding{HostPort: hostPort})
+	}
+	return exposed, bindings
+}
+
 //nolint:funlen,gocyclo
 func (e *Executor) createHTTPGateway(
 	ctx context.Context,
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// secretFileMode lets any user of the container read the secret files, as the user the
+// container runs as is defined by its image. Root users of the container can still
+// modify them.
+const secretFileMode = 0444
+
+// copySecretFiles copies the secret files into the created container, at their absolute path.
+// The directories of the files are created if they do not exist in the image.
+func (e *Executor) copySecretFiles(ctx context.Context, containerID string, files map[string][]byte) error {
+	if len(files) == 0 {
+		return nil
+	}
+	archive, err := secretsArchive(files)
+	if err != nil {
+		return err
+	}
+	return e.client.CopyToContainer(ctx, containerID, "/", archive, types.CopyToContainerOptions{})
+}
+
+// secretsArchive returns a tar archive of the files relative to the root directory, as
+// expected by docker when copying files into a container.
+func secretsArchive(files map[string][]byte) (*bytes.Buffer, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	buf := new(bytes.Buffer)
+	writer := tar.NewWriter(buf)
+	for _, path := range paths {
+		content := files[path]
+		err := writer.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(path, "/"),
+			Mode:     secretFileMode,
+			Size:     int64(len(content)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = writer.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
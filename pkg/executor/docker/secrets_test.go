@@ -0,0 +1,37 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"archive/tar"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretsArchive(t *testing.T) {
+	archive, err := secretsArchive(map[string][]byte{
+		"/run/secrets/token": []byte("s3cr3t"),
+		"/etc/app/key.pem":   []byte("-----BEGIN KEY-----"),
+	})
+	require.NoError(t, err)
+
+	reader := tar.NewReader(archive)
+	var names []string
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, int64(secretFileMode), header.Mode)
+		if header.Name == "run/secrets/token" {
+			require.Equal(t, "s3cr3t", string(content))
+		}
+		names = append(names, header.Name)
+	}
+	require.Equal(t, []string{"etc/app/key.pem", "run/secrets/token"}, names)
+}
@@ -0,0 +1,426 @@
+package oci
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/rs/zerolog/log"
+
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+)
+
+const (
+	// cpuPeriod is the CFS period, in microseconds, over which the CPU quota of executions is enforced
+	cpuPeriod = 100000
+
+	defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+	bundleConfigFile = "config.json"
+	bundleRootfsDir  = "rootfs"
+	bundleFilePerm   = 0600
+)
+
+// defaultCapabilities are the capabilities granted to the processes of executions,
+// which are the same as the default capabilities of docker containers.
+var defaultCapabilities = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_FSETID",
+	"CAP_FOWNER",
+	"CAP_MKNOD",
+	"CAP_NET_RAW",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETFCAP",
+	"CAP_SETPCAP",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_SYS_CHROOT",
+	"CAP_KILL",
+	"CAP_AUDIT_WRITE",
+}
+
+type runtimeSpecParams struct {
+	Hostname string
+	// Image is the configuration of the image, which provides the defaults of the process
+	Image ocispec.ImageConfig
+	// Engine is the docker engine spec of the task, which overrides the defaults of the image
+	Engine dockermodels.EngineSpec
+	// Env is the environment of the task, set on top of the engine's own
+	Env       map[string]string
+	User      specs.User
+	Resources *models.Resources
+	Network   *models.NetworkConfig
+	// CgroupsPath is the cgroup of the execution, in which its resource limits are enforced
+	CgroupsPath string
+	// Mounts are mounted on top of the default mounts of the container
+	Mounts []specs.Mount
+}
+
+// newRuntimeSpec returns the runtime configuration of the bundle of an execution, running the
+// process defined by the docker engine spec of the task in the same way docker would.
+func newRuntimeSpec(params runtimeSpecParams) (*specs.Spec, error) {
+	args := processArgs(params.Image, params.Engine)
+	if len(args) == 0 {
+		return nil, errors.New("no command specified by the image or the task")
+	}
+	cwd := params.Engine.WorkingDirectory
+	if cwd == "" {
+		cwd = params.Image.WorkingDir
+	}
+	if cwd == "" {
+		cwd = "/"
+	}
+
+	hostNetwork := params.Network != nil && params.Network.Type == models.NetworkFull
+	namespaces := []specs.LinuxNamespace{
+		{Type: specs.PIDNamespace},
+		{Type: specs.IPCNamespace},
+		{Type: specs.UTSNamespace},
+		{Type: specs.MountNamespace},
+		{Type: specs.CgroupNamespace},
+	}
+	if !hostNetwork {
+		namespaces = append(namespaces, specs.LinuxNamespace{Type: specs.NetworkNamespace})
+	}
+
+	mounts := defaultMounts(hostNetwork)
+	if hostNetwork {
+		mounts = append(mounts, hostNetworkMounts()...)
+	}
+	mounts = append(mounts, params.Mounts...)
+
+	spec := &specs.Spec{
+		Version:  specs.Version,
+		Hostname: params.Hostname,
+		Root: &specs.Root{
+			Path: bundleRootfsDir,
+		},
+		Process: &specs.Process{
+			User: params.User,
+			Args: args,
+			Env:  processEnv(params.Image.Env, params.Engine.EnvironmentVariables, params.Env),
+			Cwd:  cwd,
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding:  defaultCapabilities,
+				Effective: defaultCapabilities,
+				Permitted: defaultCapabilities,
+			},
+		},
+		Mounts: mounts,
+		Linux: &specs.Linux{
+			Namespaces:  namespaces,
+			Resources:   linuxResources(params.Resources),
+			CgroupsPath: params.CgroupsPath,
+			MaskedPaths: []string{
+				"/proc/acpi",
+				"/proc/asound",
+				"/proc/kcore",
+				"/proc/keys",
+				"/proc/latency_stats",
+				"/proc/timer_list",
+				"/proc/timer_stats",
+				"/proc/sched_debug",
+				"/proc/scsi",
+				"/sys/firmware",
+				"/sys/devices/virtual/powercap",
+			},
+			ReadonlyPaths: []string{
+				"/proc/bus",
+				"/proc/fs",
+				"/proc/irq",
+				"/proc/sys",
+				"/proc/sysrq-trigger",
+			},
+		},
+	}
+	return spec, nil
+}
+
+// processArgs returns the command of the process, where the entrypoint and parameters of the
+// task override the ones of the image. Like docker, overriding the entrypoint discards the
+// default command of the image.
+func processArgs(image ocispec.ImageConfig, engine dockermodels.EngineSpec) []string {
+	entrypoint, cmd := image.Entrypoint, image.Cmd
+	if len(engine.Entrypoint) > 0 {
+		entrypoint, cmd = engine.Entrypoint, nil
+	}
+	if len(engine.Parameters) > 0 {
+		cmd = engine.Parameters
+	}
+	args := make([]string, 0, len(entrypoint)+len(cmd))
+	args = append(args, entrypoint...)
+	return append(args, cmd...)
+}
+
+// processEnv returns the environment of the process, where the variables of the image are
+// overridden by the ones of the engine, which are overridden by the ones of the task.
+func processEnv(imageEnv []string, engineEnv []string, taskEnv map[string]string) []string {
+	var env []string
+	index := make(map[string]int)
+	set := func(variable string) {
+		key, _, _ := strings.Cut(variable, "=")
+		if i, found := index[key]; found {
+			env[i] = variable
+			return
+		}
+		index[key] = len(env)
+		env = append(env, variable)
+	}
+
+	for _, variable := range imageEnv {
+		set(variable)
+	}
+	for _, variable := range engineEnv {
+		set(variable)
+	}
+	keys := make([]string, 0, len(taskEnv))
+	for key := range taskEnv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		set(key + "=" + taskEnv[key])
+	}
+	if _, found := index["PATH"]; !found {
+		set("PATH=" + defaultPath)
+	}
+	return env
+}
+
+// linuxResources returns the cgroup limits of the execution. Access to devices is denied, as
+// the runtime allows the default devices of containers anyway.
+func linuxResources(resources *models.Resources) *specs.LinuxResources {
+	linuxResources := &specs.LinuxResources{
+		Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+	}
+	if resources == nil {
+		return linuxResources
+	}
+	if resources.Memory > 0 {
+		limit := int64(resources.Memory)
+		linuxResources.Memory = &specs.LinuxMemory{Limit: &limit}
+	}
+	if resources.CPU > 0 {
+		quota := int64(resources.CPU * cpuPeriod)
+		period := uint64(cpuPeriod)
+		linuxResources.CPU = &specs.LinuxCPU{Quota: &quota, Period: &period}
+	}
+	return linuxResources
+}
+
+// defaultMounts returns the filesystems mounted in all containers. Sysfs can only be mounted
+// in a new network namespace, so the one of the host is bound instead when sharing its network.
+func defaultMounts(hostNetwork bool) []specs.Mount {
+	sysfs := specs.Mount{
+		Destination: "/sys",
+		Type:        "sysfs",
+		Source:      "sysfs",
+		Options:     []string{"nosuid", "noexec", "nodev", "ro"},
+	}
+	if hostNetwork {
+		sysfs = specs.Mount{
+			Destination: "/sys",
+			Type:        "bind",
+			Source:      "/sys",
+			Options:     []string{"rbind", "nosuid", "noexec", "nodev", "ro"},
+		}
+	}
+	return []specs.Mount{
+		{
+			Destination: "/proc",
+			Type:        "proc",
+			Source:      "proc",
+		},
+		{
+			Destination: "/dev",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"nosuid", "strictatime", "mode=755", "size=65536k"},
+		},
+		{
+			Destination: "/dev/pts",
+			Type:        "devpts",
+			Source:      "devpts",
+			Options:     []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"},
+		},
+		{
+			Destination: "/dev/shm",
+			Type:        "tmpfs",
+			Source:      "shm",
+			Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"},
+		},
+		{
+			Destination: "/dev/mqueue",
+			Type:        "mqueue",
+			Source:      "mqueue",
+			Options:     []string{"nosuid", "noexec", "nodev"},
+		},
+		sysfs,
+		{
+			Destination: "/sys/fs/cgroup",
+			Type:        "cgroup",
+			Source:      "cgroup",
+			Options:     []string{"nosuid", "noexec", "nodev", "relatime", "ro"},
+		},
+	}
+}
+
+// hostNetworkMounts binds the name resolution files of the host, so that containers sharing
+// its network resolve names the same way.
+func hostNetworkMounts() []specs.Mount {
+	var mounts []specs.Mount
+	for _, file := range []string{"/etc/resolv.conf", "/etc/hosts"} {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		mounts = append(mounts, specs.Mount{
+			Destination: file,
+			Type:        "bind",
+			Source:      file,
+			Options:     []string{"rbind", "ro"},
+		})
+	}
+	return mounts
+}
+
+// makeBundleMounts returns the mounts of the inputs and outputs of an execution, in the
+// same way as the docker executor does for containers.
+func makeBundleMounts(
+	ctx context.Context, inputs []storage.PreparedStorage, outputs []*models.ResultPath, resultsDir string) ([]specs.Mount, error) {
+	var mounts []specs.Mount
+	for _, input := range inputs {
+		if input.Volume.Type != storage.StorageVolumeConnectorBind {
+			return nil, fmt.Errorf("unknown storage volume type: %s", input.Volume.Type)
+		}
+		log.Ctx(ctx).Trace().Msgf("Input Volume: %+v %+v", input.InputSource, input.Volume)
+		mounts = append(mounts, bindMount(input.Volume.Source, input.Volume.Target, input.Volume.ReadOnly))
+	}
+
+	for _, output := range outputs {
+		if output.Name == "" {
+			return nil, fmt.Errorf("output volume has no name: %+v", output)
+		}
+		if output.Path == "" {
+			return nil, fmt.Errorf("output volume has no Location: %+v", output)
+		}
+
+		srcd := filepath.Join(resultsDir, output.Name)
+		// the output already exists if the compute node restored a checkpoint into it
+		if err := os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W); err != nil && !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to create results dir for execution: %w", err)
+		}
+		log.Ctx(ctx).Trace().Msgf("Output Volume: %+v", output)
+		mounts = append(mounts, bindMount(srcd, output.Path, false))
+	}
+	return mounts, nil
+}
+
+func bindMount(source, destination string, readOnly bool) specs.Mount {
+	options := []string{"rbind", "rw"}
+	if readOnly {
+		options = []string{"rbind", "ro"}
+	}
+	return specs.Mount{
+		Destination: destination,
+		Type:        "bind",
+		Source:      source,
+		Options:     options,
+	}
+}
+
+// resolveUser resolves the user of the image, in the user[:group] format where both can be
+// names or IDs, using the passwd and group files of the root filesystem of the image.
+func resolveUser(rootfs string, user string) (specs.User, error) {
+	if user == "" {
+		return specs.User{}, nil
+	}
+	userPart, groupPart, hasGroup := strings.Cut(user, ":")
+
+	var resolved specs.User
+	if uid, err := strconv.ParseUint(userPart, 10, 32); err == nil {
+		resolved.UID = uint32(uid)
+		// the primary group of the user, if it is known
+		if entry, found, _ := lookupEntry(rootfs, "/etc/passwd", 2, userPart); found {
+			if gid, err := strconv.ParseUint(entry[3], 10, 32); err == nil {
+				resolved.GID = uint32(gid)
+			}
+		}
+	} else {
+		entry, found, err := lookupEntry(rootfs, "/etc/passwd", 0, userPart)
+		if err != nil {
+			return specs.User{}, err
+		}
+		if !found {
+			return specs.User{}, fmt.Errorf("unable to find user %s in the image", userPart)
+		}
+		uid, uidErr := strconv.ParseUint(entry[2], 10, 32)
+		gid, gidErr := strconv.ParseUint(entry[3], 10, 32)
+		if err = errors.Join(uidErr, gidErr); err != nil {
+			return specs.User{}, fmt.Errorf("invalid passwd entry for user %s: %w", userPart, err)
+		}
+		resolved.UID, resolved.GID = uint32(uid), uint32(gid)
+	}
+
+	if !hasGroup {
+		return resolved, nil
+	}
+	if gid, err := strconv.ParseUint(groupPart, 10, 32); err == nil {
+		resolved.GID = uint32(gid)
+		return resolved, nil
+	}
+	entry, found, err := lookupEntry(rootfs, "/etc/group", 0, groupPart)
+	if err != nil {
+		return specs.User{}, err
+	}
+	if !found {
+		return specs.User{}, fmt.Errorf("unable to find group %s in the image", groupPart)
+	}
+	gid, err := strconv.ParseUint(entry[2], 10, 32)
+	if err != nil {
+		return specs.User{}, fmt.Errorf("invalid group entry for group %s: %w", groupPart, err)
+	}
+	resolved.GID = uint32(gid)
+	return resolved, nil
+}
+
+// lookupEntry returns the first entry of a colon separated database file of the root filesystem,
+// e.g. /etc/passwd, with the given value in the given field.
+func lookupEntry(rootfs, file string, field int, value string) ([]string, bool, error) {
+	path, err := securejoin.SecureJoin(rootfs, file)
+	if err != nil {
+		return nil, false, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entry := strings.Split(scanner.Text(), ":")
+		// passwd entries have 7 fields, and group entries 4
+		if len(entry) < 4 || entry[field] != value {
+			continue
+		}
+		return entry, true, nil
+	}
+	return nil, false, scanner.Err()
+}
@@ -0,0 +1,167 @@
+//go:build unit || !integration
+
+package oci
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/suite"
+
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type BundleTestSuite struct {
+	suite.Suite
+}
+
+func TestBundleTestSuite(t *testing.T) {
+	suite.Run(t, new(BundleTestSuite))
+}
+
+func (s *BundleTestSuite) TestProcessArgs() {
+	image := ocispec.ImageConfig{Entrypoint: []string{"/entrypoint"}, Cmd: []string{"default"}}
+	for _, tc := range []struct {
+		name     string
+		engine   dockermodels.EngineSpec
+		expected []string
+	}{
+		{
+			name:     "image defaults",
+			expected: []string{"/entrypoint", "default"},
+		},
+		{
+			name:     "parameters override the command",
+			engine:   dockermodels.EngineSpec{Parameters: []string{"a", "b"}},
+			expected: []string{"/entrypoint", "a", "b"},
+		},
+		{
+			name:     "entrypoint discards the command",
+			engine:   dockermodels.EngineSpec{Entrypoint: []string{"/bin/sh"}},
+			expected: []string{"/bin/sh"},
+		},
+		{
+			name:     "entrypoint and parameters",
+			engine:   dockermodels.EngineSpec{Entrypoint: []string{"/bin/sh", "-c"}, Parameters: []string{"echo"}},
+			expected: []string{"/bin/sh", "-c", "echo"},
+		},
+	} {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, processArgs(image, tc.engine))
+		})
+	}
+}
+
+func (s *BundleTestSuite) TestProcessEnv() {
+	env := processEnv(
+		[]string{"PATH=/image/bin", "IMAGE=image", "SHARED=image"},
+		[]string{"SHARED=engine", "ENGINE=engine"},
+		map[string]string{"TASK": "task", "IMAGE": "task"},
+	)
+	s.Equal([]string{"PATH=/image/bin", "IMAGE=task", "SHARED=engine", "ENGINE=engine", "TASK=task"}, env)
+
+	s.Equal([]string{"PATH=" + defaultPath}, processEnv(nil, nil, nil))
+}
+
+func (s *BundleTestSuite) TestLinuxResources() {
+	resources := linuxResources(&models.Resources{CPU: 1.5, Memory: 1024})
+	s.Require().NotNil(resources.Memory)
+	s.Equal(int64(1024), *resources.Memory.Limit)
+	s.Require().NotNil(resources.CPU)
+	s.Equal(int64(150000), *resources.CPU.Quota)
+	s.Equal(uint64(100000), *resources.CPU.Period)
+	s.Equal([]specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}}, resources.Devices)
+
+	resources = linuxResources(nil)
+	s.Nil(resources.Memory)
+	s.Nil(resources.CPU)
+}
+
+func (s *BundleTestSuite) TestRuntimeSpec() {
+	params := runtimeSpecParams{
+		Hostname:    "execution",
+		Image:       ocispec.ImageConfig{Cmd: []string{"/app"}, WorkingDir: "/work"},
+		CgroupsPath: "/bacalhau/execution",
+		Mounts:      []specs.Mount{bindMount("/inputs", "/data", true)},
+	}
+	spec, err := newRuntimeSpec(params)
+	s.Require().NoError(err)
+	s.Equal([]string{"/app"}, spec.Process.Args)
+	s.Equal("/work", spec.Process.Cwd)
+	s.Equal("/bacalhau/execution", spec.Linux.CgroupsPath)
+	s.Contains(spec.Linux.Namespaces, specs.LinuxNamespace{Type: specs.NetworkNamespace})
+	s.Equal(bindMount("/inputs", "/data", true), spec.Mounts[len(spec.Mounts)-1])
+	s.Equal("sysfs", s.mount(spec, "/sys").Type)
+
+	params.Engine.WorkingDirectory = "/engine"
+	params.Network = &models.NetworkConfig{Type: models.NetworkFull}
+	spec, err = newRuntimeSpec(params)
+	s.Require().NoError(err)
+	s.Equal("/engine", spec.Process.Cwd)
+	s.NotContains(spec.Linux.Namespaces, specs.LinuxNamespace{Type: specs.NetworkNamespace})
+	s.Equal("bind", s.mount(spec, "/sys").Type)
+	s.Equal("/etc/resolv.conf", s.mount(spec, "/etc/resolv.conf").Source)
+
+	_, err = newRuntimeSpec(runtimeSpecParams{})
+	s.ErrorContains(err, "no command specified")
+}
+
+func (s *BundleTestSuite) mount(spec *specs.Spec, destination string) specs.Mount {
+	for _, mount := range spec.Mounts {
+		if mount.Destination == destination {
+			return mount
+		}
+	}
+	s.FailNow("mount not found", destination)
+	return specs.Mount{}
+}
+
+func (s *BundleTestSuite) TestResolveUser() {
+	rootfs := s.T().TempDir()
+	s.Require().NoError(os.Mkdir(filepath.Join(rootfs, "etc"), rootfsDirPerm))
+	s.Require().NoError(os.WriteFile(filepath.Join(rootfs, "etc", "passwd"), []byte(
+		"root:x:0:0:root:/root:/bin/sh\n"+
+			"app:x:1000:1001:app:/home/app:/bin/sh\n"), 0600))
+	s.Require().NoError(os.WriteFile(filepath.Join(rootfs, "etc", "group"), []byte(
+		"root:x:0:\n"+
+			"staff:x:50:app\n"), 0600))
+
+	for _, tc := range []struct {
+		user     string
+		expected specs.User
+	}{
+		{user: "", expected: specs.User{}},
+		{user: "app", expected: specs.User{UID: 1000, GID: 1001}},
+		{user: "1000", expected: specs.User{UID: 1000, GID: 1001}},
+		{user: "2000", expected: specs.User{UID: 2000}},
+		{user: "app:staff", expected: specs.User{UID: 1000, GID: 50}},
+		{user: "app:60", expected: specs.User{UID: 1000, GID: 60}},
+	} {
+		s.Run(tc.user, func() {
+			user, err := resolveUser(rootfs, tc.user)
+			s.Require().NoError(err)
+			s.Equal(tc.expected, user)
+		})
+	}
+
+	_, err := resolveUser(rootfs, "unknown")
+	s.ErrorContains(err, "unable to find user unknown")
+	_, err = resolveUser(rootfs, "app:unknown")
+	s.ErrorContains(err, "unable to find group unknown")
+}
+
+func (s *BundleTestSuite) TestMakeBundleMounts() {
+	_, err := makeBundleMounts(context.Background(), nil, []*models.ResultPath{{Path: "/outputs"}}, s.T().TempDir())
+	s.ErrorContains(err, "output volume has no name")
+
+	resultsDir := s.T().TempDir()
+	mounts, err := makeBundleMounts(context.Background(), nil, []*models.ResultPath{{Name: "outputs", Path: "/outputs"}}, resultsDir)
+	s.Require().NoError(err)
+	s.Equal([]specs.Mount{bindMount(filepath.Join(resultsDir, "outputs"), "/outputs", false)}, mounts)
+	s.DirExists(filepath.Join(resultsDir, "outputs"))
+}
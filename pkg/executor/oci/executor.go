@@ -0,0 +1,416 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/atomic"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	wasmlogs "github.com/bacalhau-project/bacalhau/pkg/logger/wasm"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/util/generic"
+)
+
+const (
+	// DefaultRuntime is the OCI runtime used when none is configured
+	DefaultRuntime = "runc"
+
+	imagesDir   = "images"
+	bundlesDir  = "bundles"
+	runtimeDir  = "state"
+	cgroupsRoot = "/bacalhau"
+
+	// cgroupControllersFile only exists on hosts using the unified cgroups v2 hierarchy
+	cgroupControllersFile = "/sys/fs/cgroup/cgroup.controllers"
+)
+
+type ExecutorParams struct {
+	// ID is used to allow multiple executors to run against the same OCI runtime
+	ID string
+	// Runtime is the OCI runtime binary, e.g. runc or crun. It is looked up in the PATH if it is not a path.
+	Runtime string
+	// Directory stores the images pulled by the executor, and the bundles of the executions
+	Directory string
+}
+
+// Executor runs the jobs of the docker engine directly with an OCI runtime, such as runc or crun,
+// for nodes that can't run a docker daemon. Images are pulled from their registries and unpacked
+// into the bundles of the executions, and the resource limits of the executions are enforced
+// with cgroups v2.
+type Executor struct {
+	// used to allow multiple executors to run against the same OCI runtime
+	ID string
+
+	// handlers is a map of executionID to its handler.
+	handlers generic.SyncMap[string, *executionHandler]
+
+	runtime    *ociRuntime
+	images     *imageStore
+	bundlesDir string
+}
+
+func NewExecutor(_ context.Context, params ExecutorParams) (*Executor, error) {
+	if params.Directory == "" {
+		return nil, errors.New("the directory of the OCI executor is required")
+	}
+	runtimePath := params.Runtime
+	if runtimePath == "" {
+		runtimePath = DefaultRuntime
+	}
+
+	images, err := newImageStore(
+		filepath.Join(params.Directory, imagesDir),
+		newRegistryClient(&http.Client{}, config.GetDockerCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{bundlesDir, runtimeDir} {
+		if err = os.MkdirAll(filepath.Join(params.Directory, dir), storeDirPerm); err != nil {
+			return nil, fmt.Errorf("creating OCI executor directory: %w", err)
+		}
+	}
+
+	return &Executor{
+		ID: params.ID,
+		runtime: &ociRuntime{
+			path: runtimePath,
+			root: filepath.Join(params.Directory, runtimeDir),
+		},
+		images:     images,
+		bundlesDir: filepath.Join(params.Directory, bundlesDir),
+	}, nil
+}
+
+// IsInstalled checks if the OCI runtime is installed, and if the host uses cgroups v2
+// which are required to enforce the resource limits of executions.
+func (e *Executor) IsInstalled(context.Context) (bool, error) {
+	if _, err := exec.LookPath(e.runtime.path); err != nil {
+		return false, nil
+	}
+	if _, err := os.Stat(cgroupControllersFile); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (e *Executor) ShouldBid(
+	ctx context.Context,
+	request bidstrategy.BidStrategyRequest,
+) (bidstrategy.BidStrategyResponse, error) {
+	task := request.Job.Task()
+	if task.Network != nil && task.Network.Type == models.NetworkHTTP {
+		return bidstrategy.NewBidResponse(false, "support HTTP networking with the OCI runtime"), nil
+	}
+	if task.HasSecretFiles() {
+		return bidstrategy.NewBidResponse(false, "support secret files with the OCI runtime"), nil
+	}
+	if task.HasPorts() {
+		return bidstrategy.NewBidResponse(false, "publish ports with the OCI runtime"), nil
+	}
+	if task.ResourcesConfig != nil && task.ResourcesConfig.GPU != "" {
+		resources, err := task.ResourcesConfig.ToResources()
+		if err != nil {
+			return bidstrategy.BidStrategyResponse{}, err
+		}
+		if resources.GPU > 0 {
+			return bidstrategy.NewBidResponse(false, "support GPUs with the OCI runtime"), nil
+		}
+	}
+	return bidstrategy.NewBidResponse(true, "support the networking and resources of the job with the OCI runtime"), nil
+}
+
+func (e *Executor) ShouldBidBasedOnUsage(
+	ctx context.Context,
+	request bidstrategy.BidStrategyRequest,
+	usage models.Resources,
+) (bidstrategy.BidStrategyResponse, error) {
+	return bidstrategy.NewBidResponse(true, "not place additional requirements on Docker jobs"), nil
+}
+
+// Start initiates an execution based on the provided RunCommandRequest.
+func (e *Executor) Start(ctx context.Context, request *executor.RunCommandRequest) error {
+	log.Ctx(ctx).Info().
+		Str("executionID", request.ExecutionID).
+		Str("jobID", request.JobID).
+		Msg("starting execution")
+
+	if handler, found := e.handlers.Get(request.ExecutionID); found {
+		if handler.active() {
+			return fmt.Errorf("starting execution (%s): %w", request.ExecutionID, executor.ErrAlreadyStarted)
+		} else {
+			return fmt.Errorf("starting execution (%s): %w", request.ExecutionID, executor.ErrAlreadyComplete)
+		}
+	}
+
+	containerID := e.containerID(request.ExecutionID)
+	bundleDir := filepath.Join(e.bundlesDir, containerID)
+	// remove the container of a previous run of the execution, e.g. before the compute
+	// node restarted, as its output can't be recovered.
+	e.removeContainer(ctx, containerID, bundleDir)
+
+	if err := e.createBundle(ctx, &bundleParams{
+		ContainerID: containerID,
+		BundleDir:   bundleDir,
+		EngineSpec:  request.EngineParams,
+		Network:     request.Network,
+		Resources:   request.Resources,
+		Inputs:      request.Inputs,
+		Outputs:     request.Outputs,
+		ResultsDir:  request.ResultsDir,
+		Env:         request.Env,
+	}); err != nil {
+		_ = os.RemoveAll(bundleDir)
+		return fmt.Errorf("failed to create OCI bundle: %w", err)
+	}
+
+	logManager, err := wasmlogs.NewLogManager(ctx, request.ExecutionID)
+	if err != nil {
+		return err
+	}
+
+	handler := &executionHandler{
+		runtime: e.runtime,
+		logger: log.With().
+			Str("container", containerID).
+			Str("execution", request.ExecutionID).
+			Str("job", request.JobID).
+			Logger(),
+		ID:          e.ID,
+		executionID: request.ExecutionID,
+		containerID: containerID,
+		bundleDir:   bundleDir,
+		resultsDir:  request.ResultsDir,
+		limits:      request.OutputLimits,
+		keepStack:   config.ShouldKeepStack(),
+		logManager:  logManager,
+		waitCh:      make(chan bool),
+		activeCh:    make(chan bool),
+		running:     atomic.NewBool(false),
+	}
+
+	// register the handler for this executionID
+	e.handlers.Put(request.ExecutionID, handler)
+	// run the container.
+	go handler.run(ctx)
+	return nil
+}
+
+// Wait initiates a wait for the completion of a specific execution using its
+// executionID. The function returns two channels: one for the result and another
+// for any potential error. If the executionID is not found, an error is immediately
+// sent to the error channel. Otherwise, an internal goroutine (doWait) is spawned
+// to handle the asynchronous waiting.
+func (e *Executor) Wait(ctx context.Context, executionID string) (<-chan *models.RunCommandResult, <-chan error) {
+	handler, found := e.handlers.Get(executionID)
+	resultCh := make(chan *models.RunCommandResult, 1)
+	errCh := make(chan error, 1)
+
+	if !found {
+		errCh <- fmt.Errorf("waiting on execution (%s): %w", executionID, executor.ErrNotFound)
+		return resultCh, errCh
+	}
+
+	go e.doWait(ctx, resultCh, errCh, handler)
+	return resultCh, errCh
+}
+
+// doWait is a helper function that actively waits for an execution to finish. It
+// listens on the executionHandler's wait channel for completion signals. Once the
+// signal is received, the result is sent to the provided output channel. If there's
+// a cancellation request (context is done) before completion, an error is relayed to
+// the error channel.
+func (e *Executor) doWait(ctx context.Context, out chan *models.RunCommandResult, errCh chan error, handle *executionHandler) {
+	log.Info().Str("executionID", handle.executionID).Msg("waiting on execution")
+	defer close(out)
+	defer close(errCh)
+
+	select {
+	case <-ctx.Done():
+		errCh <- ctx.Err() // Send the cancellation error to the error channel
+		return
+	case <-handle.waitCh:
+		if handle.result != nil {
+			log.Info().Str("executionID", handle.executionID).Msg("received results from execution")
+			out <- handle.result
+		} else {
+			errCh <- fmt.Errorf("execution (%s) result is nil", handle.executionID)
+		}
+	}
+}
+
+// Cancel tries to cancel a specific execution by its executionID.
+// It returns an error if the execution is not found.
+func (e *Executor) Cancel(ctx context.Context, executionID string) error {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return fmt.Errorf("canceling execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	return handler.kill(ctx)
+}
+
+// GetLogStream provides a stream of output logs for a specific execution.
+// It returns an error if the execution is not found.
+func (e *Executor) GetLogStream(ctx context.Context, request executor.LogStreamRequest) (io.ReadCloser, error) {
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found {
+		return nil, fmt.Errorf("getting outputs for execution (%s): %w", request.ExecutionID, executor.ErrNotFound)
+	}
+	return handler.outputStream(ctx, request)
+}
+
+// Run initiates and waits for the completion of an execution in one call.
+// This method serves as a higher-level convenience function that
+// internally calls Start and Wait methods.
+// It returns the result of the execution or an error if either starting
+// or waiting fails, or if the context is canceled.
+func (e *Executor) Run(
+	ctx context.Context,
+	request *executor.RunCommandRequest,
+) (*models.RunCommandResult, error) {
+	if err := e.Start(ctx, request); err != nil {
+		return nil, err
+	}
+	resCh, errCh := e.Wait(ctx, request.ExecutionID)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case out := <-resCh:
+		return out, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+// Shutdown removes the containers and bundles of the executor that are still running.
+func (e *Executor) Shutdown(ctx context.Context) error {
+	if config.ShouldKeepStack() {
+		return nil
+	}
+	ids, err := e.runtime.list(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to list OCI containers")
+		return nil
+	}
+	prefix := e.containerID("")
+	for _, id := range ids {
+		if strings.HasPrefix(id, prefix) {
+			e.removeContainer(ctx, id, filepath.Join(e.bundlesDir, id))
+		}
+	}
+	return nil
+}
+
+type bundleParams struct {
+	ContainerID string
+	BundleDir   string
+	EngineSpec  *models.SpecConfig
+	Network     *models.NetworkConfig
+	Resources   *models.Resources
+	Inputs      []storage.PreparedStorage
+	Outputs     []*models.ResultPath
+	ResultsDir  string
+	Env         map[string]string
+}
+
+// createBundle is an internal method called by Start to create the OCI bundle of an execution,
+// made of the root filesystem unpacked from the image of the docker engine spec, and of the
+// runtime configuration of the container.
+func (e *Executor) createBundle(ctx context.Context, params *bundleParams) error {
+	// decode the request arguments, bail if they are invalid.
+	dockerArgs, err := dockermodels.DecodeSpec(params.EngineSpec)
+	if err != nil {
+		return fmt.Errorf("decoding engine spec: %w", err)
+	}
+	if params.Resources != nil && len(params.Resources.GPUs) > 0 {
+		return errors.New("GPUs are not supported by the OCI executor")
+	}
+	if params.Network != nil && params.Network.Type == models.NetworkHTTP {
+		return fmt.Errorf("network type %s is not supported by the OCI executor", params.Network.Type)
+	}
+
+	img, err := e.images.pull(ctx, dockerArgs.Image)
+	if err != nil {
+		return fmt.Errorf("pulling image %s: %w", dockerArgs.Image, err)
+	}
+
+	rootfs := filepath.Join(params.BundleDir, bundleRootfsDir)
+	if err = os.MkdirAll(rootfs, rootfsDirPerm); err != nil {
+		return err
+	}
+	for _, layer := range img.layers {
+		if err = e.unpackLayer(layer, rootfs); err != nil {
+			return fmt.Errorf("unpacking layer %s of image %s: %w", layer.Digest, img.name, err)
+		}
+	}
+
+	mounts, err := makeBundleMounts(ctx, params.Inputs, params.Outputs, params.ResultsDir)
+	if err != nil {
+		return fmt.Errorf("creating container mounts: %w", err)
+	}
+	user, err := resolveUser(rootfs, img.config.Config.User)
+	if err != nil {
+		return err
+	}
+
+	spec, err := newRuntimeSpec(runtimeSpecParams{
+		Hostname:    params.ContainerID,
+		Image:       img.config.Config,
+		Engine:      dockerArgs,
+		Env:         params.Env,
+		User:        user,
+		Resources:   params.Resources,
+		Network:     params.Network,
+		CgroupsPath: filepath.Join(cgroupsRoot, params.ContainerID),
+		Mounts:      mounts,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("encoding runtime spec: %w", err)
+	}
+	return os.WriteFile(filepath.Join(params.BundleDir, bundleConfigFile), data, bundleFilePerm)
+}
+
+func (e *Executor) unpackLayer(layer ocispec.Descriptor, rootfs string) error {
+	blob, err := e.images.openBlob(layer.Digest)
+	if err != nil {
+		return err
+	}
+	defer blob.Close() //nolint:errcheck
+	return unpackLayer(blob, layer.MediaType, rootfs)
+}
+
+// removeContainer deletes a container and its bundle, if they exist
+func (e *Executor) removeContainer(ctx context.Context, containerID string, bundleDir string) {
+	if err := e.runtime.delete(ctx, containerID); err == nil {
+		log.Ctx(ctx).Debug().Str("container", containerID).Msg("deleted existing container")
+	}
+	if err := os.RemoveAll(bundleDir); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("bundle", bundleDir).Msg("failed to remove bundle")
+	}
+}
+
+func (e *Executor) containerID(executionID string) string {
+	return strings.Join([]string{"bacalhau", e.ID, executionID}, "-")
+}
+
+// Compile-time check that Executor implements the Executor interface.
+var _ executor.Executor = (*Executor)(nil)
@@ -0,0 +1,61 @@
+//go:build unit || !integration
+
+package oci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ExecutorTestSuite struct {
+	suite.Suite
+}
+
+func TestExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorTestSuite))
+}
+
+func (s *ExecutorTestSuite) TestShouldBid() {
+	for _, tc := range []struct {
+		name      string
+		task      models.Task
+		shouldBid bool
+	}{
+		{
+			name:      "full network",
+			task:      models.Task{Network: &models.NetworkConfig{Type: models.NetworkFull}},
+			shouldBid: true,
+		},
+		{
+			name:      "HTTP network",
+			task:      models.Task{Network: &models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"example.com"}}},
+			shouldBid: false,
+		},
+		{
+			name:      "secret files",
+			task:      models.Task{Secrets: []*models.SecretReference{{Name: "api-key", Path: "/run/secrets/api-key"}}},
+			shouldBid: false,
+		},
+		{
+			name: "ports",
+			task: models.Task{Network: &models.NetworkConfig{
+				Type:  models.NetworkFull,
+				Ports: []*models.Port{{Name: "http", Target: 8080}},
+			}},
+			shouldBid: false,
+		},
+	} {
+		s.Run(tc.name, func() {
+			tc.task.Name = "task"
+			job := models.Job{Tasks: []*models.Task{&tc.task}}
+			response, err := (&Executor{}).ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{Job: job})
+			s.Require().NoError(err)
+			s.Equal(tc.shouldBid, response.ShouldBid, response.Reason)
+		})
+	}
+}
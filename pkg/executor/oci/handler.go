@@ -0,0 +1,143 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/atomic"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	wasmlogs "github.com/bacalhau-project/bacalhau/pkg/logger/wasm"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const destroyTimeout = 10 * time.Second
+
+type executionHandler struct {
+	//
+	// provided by the executor
+	runtime *ociRuntime
+	logger  zerolog.Logger
+	// meta data about the executor
+	ID string
+
+	//
+	// meta data about the task
+	executionID string
+	containerID string
+	bundleDir   string
+	resultsDir  string
+	limits      executor.OutputLimits
+	keepStack   bool
+
+	// output of the container
+	logManager *wasmlogs.LogManager
+
+	//
+	// synchronization
+	// blocks until the container starts
+	activeCh chan bool
+	// blocks until the run method returns
+	waitCh chan bool
+	// true until the run method returns
+	running *atomic.Bool
+
+	//
+	// results
+	result *models.RunCommandResult
+}
+
+func (h *executionHandler) run(ctx context.Context) {
+	ActiveExecutions.Inc(ctx, attribute.String("executor_id", h.ID))
+	h.running.Store(true)
+	defer func() {
+		if err := h.destroy(destroyTimeout); err != nil {
+			h.logger.Warn().Err(err).Msg("failed to cleanup container")
+		}
+		h.running.Store(false)
+		close(h.waitCh)
+		ActiveExecutions.Dec(ctx, attribute.String("executor_id", h.ID))
+	}()
+
+	h.logger.Info().Msg("starting container execution")
+	stdout, stderr := h.logManager.GetWriters()
+	cmd, err := h.runtime.run(h.containerID, h.bundleDir, stdout, stderr)
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("failed to start container")
+		h.result = executor.NewFailedResult(fmt.Sprintf("failed to start container: %s", err))
+		return
+	}
+	// The container is now active
+	close(h.activeCh)
+
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	var waitErr error
+	select {
+	case <-ctx.Done():
+		// failure case, the context has been canceled. We are aborting this execution
+		reason := fmt.Errorf("context canceled while waiting on container status: %w", ctx.Err())
+		h.logger.Err(reason).Msg("cancel waiting on container status")
+		h.result = executor.NewFailedResult(reason.Error())
+		return
+	case waitErr = <-waitCh:
+	}
+
+	// the idea here is even if the container errors
+	// we want to capture stdout, stderr and feed it back to the user
+	exitCode := 0
+	var containerError error
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if waitErr != nil {
+		containerError = waitErr
+		exitCode = 1
+	}
+	h.logger.Info().Int("status", exitCode).Err(containerError).Msg("container execution ended")
+
+	// the container has exited, so there is nothing else to read from
+	h.logManager.Drain()
+	stdoutReader, stderrReader := h.logManager.GetDefaultReaders(false)
+	h.result = executor.WriteJobResults(h.resultsDir, stdoutReader, stderrReader, exitCode, containerError, h.limits)
+}
+
+func (h *executionHandler) active() bool {
+	return h.running.Load()
+}
+
+func (h *executionHandler) kill(ctx context.Context) error {
+	h.logger.Info().Msg("killing the container")
+	// NB: killing the container will cause the run method to perform cleanup if still active
+	return h.runtime.kill(ctx, h.containerID, "KILL")
+}
+
+func (h *executionHandler) destroy(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	h.logger.Info().Msg("destroying the container")
+
+	// the runtime deletes the container once its process exits, so the container
+	// only remains if the execution was canceled while it was running
+	if err := h.runtime.delete(ctx, h.containerID); err != nil {
+		h.logger.Debug().Err(err).Msg("container already deleted")
+	}
+
+	if !h.keepStack {
+		h.logger.Info().Msg("removing bundle")
+		return os.RemoveAll(h.bundleDir)
+	}
+	return nil
+}
+
+func (h *executionHandler) outputStream(ctx context.Context, request executor.LogStreamRequest) (io.ReadCloser, error) {
+	return h.logManager.GetMuxedReader(request.Follow), nil
+}
@@ -0,0 +1,197 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	blobsDir = "blobs"
+	refsDir  = "refs"
+
+	storeDirPerm  = 0700
+	storeFilePerm = 0600
+)
+
+// image is an image pulled into the image store
+type image struct {
+	name   string
+	config ocispec.Image
+	layers []ocispec.Descriptor
+}
+
+// imageStore pulls images from registries, and stores their manifests, configs and layers
+// on disk addressed by their digest, so that they are only downloaded once.
+type imageStore struct {
+	dir      string
+	registry *registryClient
+}
+
+func newImageStore(dir string, registry *registryClient) (*imageStore, error) {
+	for _, d := range []string{filepath.Join(dir, blobsDir), filepath.Join(dir, refsDir)} {
+		if err := os.MkdirAll(d, storeDirPerm); err != nil {
+			return nil, fmt.Errorf("creating image store directory %s: %w", d, err)
+		}
+	}
+	return &imageStore{dir: dir, registry: registry}, nil
+}
+
+// pull resolves the image for the platform of the node and downloads the blobs that are
+// not in the store yet. If the registry can't be reached, the image is resolved from the
+// last manifest pulled for the same reference, if any.
+func (s *imageStore) pull(ctx context.Context, name string) (*image, error) {
+	ref, err := parseImageReference(name)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, manifestDigest, err := s.registry.resolve(ctx, ref)
+	if err != nil {
+		var cacheErr error
+		manifest, manifestDigest, cacheErr = s.cachedManifest(ref)
+		if cacheErr != nil {
+			return nil, err
+		}
+		log.Ctx(ctx).Warn().Err(err).Str("image", ref.name).Msg("failed to resolve image, using the last pulled manifest")
+	} else if err = s.saveManifest(ref, manifest, manifestDigest); err != nil {
+		return nil, err
+	}
+
+	if err = s.ensureBlob(ctx, ref, manifest.Config); err != nil {
+		return nil, err
+	}
+	for _, layer := range manifest.Layers {
+		if err = s.ensureBlob(ctx, ref, layer); err != nil {
+			return nil, err
+		}
+	}
+
+	configData, err := os.ReadFile(s.blobPath(manifest.Config.Digest))
+	if err != nil {
+		return nil, fmt.Errorf("reading config of image %s: %w", ref.name, err)
+	}
+	img := &image{name: ref.name, layers: manifest.Layers}
+	if err = json.Unmarshal(configData, &img.config); err != nil {
+		return nil, fmt.Errorf("decoding config of image %s: %w", ref.name, err)
+	}
+	return img, nil
+}
+
+// openBlob opens a blob of the store for reading
+func (s *imageStore) openBlob(dgst digest.Digest) (*os.File, error) {
+	return os.Open(s.blobPath(dgst))
+}
+
+func (s *imageStore) blobPath(dgst digest.Digest) string {
+	return filepath.Join(s.dir, blobsDir, dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// refPath returns the file recording the manifest last pulled for a reference
+func (s *imageStore) refPath(ref imageReference) string {
+	return filepath.Join(s.dir, refsDir, digest.FromString(ref.name).Encoded())
+}
+
+func (s *imageStore) saveManifest(ref imageReference, manifest ocispec.Manifest, dgst digest.Digest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err = writeFileAtomic(s.blobPath(dgst), data); err != nil {
+		return fmt.Errorf("storing manifest of image %s: %w", ref.name, err)
+	}
+	if err = writeFileAtomic(s.refPath(ref), []byte(dgst.String())); err != nil {
+		return fmt.Errorf("storing reference of image %s: %w", ref.name, err)
+	}
+	return nil
+}
+
+func (s *imageStore) cachedManifest(ref imageReference) (ocispec.Manifest, digest.Digest, error) {
+	data, err := os.ReadFile(s.refPath(ref))
+	if err != nil {
+		return ocispec.Manifest{}, "", err
+	}
+	dgst, err := digest.Parse(string(data))
+	if err != nil {
+		return ocispec.Manifest{}, "", err
+	}
+	data, err = os.ReadFile(s.blobPath(dgst))
+	if err != nil {
+		return ocispec.Manifest{}, "", err
+	}
+	var manifest ocispec.Manifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return ocispec.Manifest{}, "", err
+	}
+	return manifest, dgst, nil
+}
+
+// ensureBlob downloads a blob into the store if it is not there yet, verifying its digest
+func (s *imageStore) ensureBlob(ctx context.Context, ref imageReference, descriptor ocispec.Descriptor) error {
+	if err := descriptor.Digest.Validate(); err != nil {
+		return fmt.Errorf("invalid digest %q in image %s: %w", descriptor.Digest, ref.name, err)
+	}
+	path := s.blobPath(descriptor.Digest)
+	if stat, err := os.Stat(path); err == nil && stat.Size() == descriptor.Size {
+		return nil
+	}
+
+	blob, err := s.registry.fetchBlob(ctx, ref, descriptor.Digest)
+	if err != nil {
+		return err
+	}
+	defer blob.Close() //nolint:errcheck
+
+	if err = os.MkdirAll(filepath.Dir(path), storeDirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	verifier := descriptor.Digest.Verifier()
+	written, err := io.Copy(io.MultiWriter(tmp, verifier), blob)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("downloading blob %s of image %s: %w", descriptor.Digest, ref.name, err)
+	}
+	if written != descriptor.Size || !verifier.Verified() {
+		return fmt.Errorf("blob %s of image %s does not match its digest", descriptor.Digest, ref.name)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// writeFileAtomic writes a file by renaming a temporary file, so that readers never see partial content
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), storeDirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Chmod(storeFilePerm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
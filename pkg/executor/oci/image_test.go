@@ -0,0 +1,234 @@
+//go:build unit || !integration
+
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+)
+
+const testToken = "test-token"
+
+// testRegistry serves a single multi-platform image, requiring a bearer token
+type testRegistry struct {
+	server      *httptest.Server
+	manifests   map[string][]byte
+	mediaTypes  map[string]string
+	blobs       map[digest.Digest][]byte
+	blobFetches atomic.Int32
+}
+
+func newTestRegistry(platform ocispec.Platform, layers ...[]byte) *testRegistry {
+	r := &testRegistry{
+		manifests:  make(map[string][]byte),
+		mediaTypes: make(map[string]string),
+		blobs:      make(map[digest.Digest][]byte),
+	}
+
+	imageConfig, _ := json.Marshal(ocispec.Image{
+		Platform: platform,
+		Config: ocispec.ImageConfig{
+			Env:        []string{"PATH=/bin", "IMAGE=test"},
+			Entrypoint: []string{"/bin/app"},
+		},
+	})
+	manifest := ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.addBlob(ocispec.MediaTypeImageConfig, imageConfig),
+	}
+	manifest.SchemaVersion = 2
+	for _, layer := range layers {
+		manifest.Layers = append(manifest.Layers, r.addBlob(ocispec.MediaTypeImageLayerGzip, layer))
+	}
+	manifestData, _ := json.Marshal(manifest)
+	manifestDigest := digest.FromBytes(manifestData)
+	r.manifests[manifestDigest.String()] = manifestData
+	r.mediaTypes[manifestDigest.String()] = ocispec.MediaTypeImageManifest
+
+	index := ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{
+			{
+				MediaType: ocispec.MediaTypeImageManifest,
+				Digest:    digest.FromString("other platform"),
+				Size:      1,
+				Platform:  &ocispec.Platform{OS: "windows", Architecture: runtime.GOARCH},
+			},
+			{
+				MediaType: ocispec.MediaTypeImageManifest,
+				Digest:    manifestDigest,
+				Size:      int64(len(manifestData)),
+				Platform:  &platform,
+			},
+		},
+	}
+	index.SchemaVersion = 2
+	indexData, _ := json.Marshal(index)
+	r.manifests["latest"] = indexData
+	r.mediaTypes["latest"] = ocispec.MediaTypeImageIndex
+
+	r.server = httptest.NewServer(http.HandlerFunc(r.handle))
+	return r
+}
+
+func (r *testRegistry) addBlob(mediaType string, data []byte) ocispec.Descriptor {
+	dgst := digest.FromBytes(data)
+	r.blobs[dgst] = data
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(data))}
+}
+
+func (r *testRegistry) image() string {
+	return strings.TrimPrefix(r.server.URL, "http://") + "/library/app:latest"
+}
+
+func (r *testRegistry) handle(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if req.URL.Query().Get("scope") != "repository:library/app:pull" {
+			http.Error(w, "invalid scope", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer "+testToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if ref, found := strings.CutPrefix(req.URL.Path, "/v2/library/app/manifests/"); found {
+		data, ok := r.manifests[ref]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", r.mediaTypes[ref])
+		_, _ = w.Write(data)
+		return
+	}
+	if dgst, found := strings.CutPrefix(req.URL.Path, "/v2/library/app/blobs/"); found {
+		data, ok := r.blobs[digest.Digest(dgst)]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		r.blobFetches.Add(1)
+		_, _ = w.Write(data)
+		return
+	}
+	http.NotFound(w, req)
+}
+
+type ImageStoreTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	layer    []byte
+	registry *testRegistry
+	store    *imageStore
+}
+
+func TestImageStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(ImageStoreTestSuite))
+}
+
+func (s *ImageStoreTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.layer = makeLayer(file("bin/app", "binary"))
+	s.registry = newTestRegistry(ocispec.Platform{OS: "linux", Architecture: runtime.GOARCH}, s.layer)
+	s.T().Cleanup(s.registry.server.Close)
+
+	var err error
+	s.store, err = newImageStore(s.T().TempDir(), newRegistryClient(s.registry.server.Client(), config.DockerCredentials{}))
+	s.Require().NoError(err)
+}
+
+func (s *ImageStoreTestSuite) TestPull() {
+	img, err := s.store.pull(s.ctx, s.registry.image())
+	s.Require().NoError(err)
+	s.Equal(s.registry.image(), img.name)
+	s.Equal([]string{"/bin/app"}, img.config.Config.Entrypoint)
+	s.Require().Len(img.layers, 1)
+	s.Equal(digest.FromBytes(s.layer), img.layers[0].Digest)
+	s.FileExists(s.store.blobPath(img.layers[0].Digest))
+	s.Equal(int32(2), s.registry.blobFetches.Load())
+
+	// the blobs are only downloaded once
+	_, err = s.store.pull(s.ctx, s.registry.image())
+	s.Require().NoError(err)
+	s.Equal(int32(2), s.registry.blobFetches.Load())
+}
+
+func (s *ImageStoreTestSuite) TestPullUsesLastManifestWhenOffline() {
+	_, err := s.store.pull(s.ctx, s.registry.image())
+	s.Require().NoError(err)
+	s.registry.server.Close()
+
+	img, err := s.store.pull(s.ctx, s.registry.image())
+	s.Require().NoError(err)
+	s.Equal([]string{"/bin/app"}, img.config.Config.Entrypoint)
+}
+
+func (s *ImageStoreTestSuite) TestPullVerifiesDigests() {
+	layerDigest := digest.FromBytes(s.layer)
+	s.registry.blobs[layerDigest] = makeLayer(file("bin/app", "tampered"))
+
+	_, err := s.store.pull(s.ctx, s.registry.image())
+	s.ErrorContains(err, "does not match its digest")
+	s.NoFileExists(s.store.blobPath(layerDigest))
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(s.store.blobPath(layerDigest)), "*"))
+	s.Require().NoError(err)
+	for _, match := range matches {
+		s.NotContains(filepath.Base(match), layerDigest.Encoded(), "no partial download should be left")
+	}
+}
+
+func (s *ImageStoreTestSuite) TestPullUnavailablePlatform() {
+	registry := newTestRegistry(ocispec.Platform{OS: "linux", Architecture: "unknown"}, s.layer)
+	defer registry.server.Close()
+	store, err := newImageStore(s.T().TempDir(), newRegistryClient(registry.server.Client(), config.DockerCredentials{}))
+	s.Require().NoError(err)
+
+	_, err = store.pull(s.ctx, registry.image())
+	s.ErrorContains(err, "is not available for")
+}
+
+func (s *ImageStoreTestSuite) TestParseImageReference() {
+	ref, err := parseImageReference("ubuntu")
+	s.Require().NoError(err)
+	s.Equal("docker.io/library/ubuntu:latest", ref.name)
+	s.Equal("docker.io", ref.domain)
+	s.Equal("library/ubuntu", ref.repository)
+	s.Equal("latest", ref.reference)
+	s.Equal("https://registry-1.docker.io", registryURL(ref.domain))
+
+	ref, err = parseImageReference("ghcr.io/bacalhau-project/app@sha256:" + strings.Repeat("a", 64))
+	s.Require().NoError(err)
+	s.Equal("sha256:"+strings.Repeat("a", 64), ref.reference)
+	s.Equal("https://ghcr.io", registryURL(ref.domain))
+
+	s.Equal("http://localhost:5000", registryURL("localhost:5000"))
+}
+
+func (s *ImageStoreTestSuite) TestParseChallenge() {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:a:pull"`)
+	s.Equal("Bearer", scheme)
+	s.Equal(map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:a:pull",
+	}, params)
+}
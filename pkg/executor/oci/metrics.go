@@ -0,0 +1,20 @@
+package oci
+
+import (
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+var (
+	ociExecutorMeter = otel.GetMeterProvider().Meter("oci-executor")
+)
+
+var (
+	ActiveExecutions = lo.Must(telemetry.NewGauge(
+		ociExecutorMeter,
+		"oci_active_executions",
+		"Number of active OCI runtime executions",
+	))
+)
@@ -0,0 +1,343 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+)
+
+const (
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerLayerGzip    = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+
+	// maxManifestSize limits the size of the manifests read from registries
+	maxManifestSize = 4 << 20
+)
+
+var manifestMediaTypes = []string{
+	ocispec.MediaTypeImageIndex,
+	ocispec.MediaTypeImageManifest,
+	mediaTypeDockerManifestList,
+	mediaTypeDockerManifest,
+}
+
+// imageReference is a parsed image reference, e.g. ubuntu:22.04
+type imageReference struct {
+	// name is the normalized name of the image, e.g. docker.io/library/ubuntu:22.04
+	name       string
+	domain     string
+	repository string
+	// reference is the tag or digest of the image
+	reference string
+}
+
+func parseImageReference(image string) (imageReference, error) {
+	named, err := reference.ParseDockerRef(image)
+	if err != nil {
+		return imageReference{}, fmt.Errorf("invalid image reference %q: %w", image, err)
+	}
+	ref := imageReference{
+		name:       named.String(),
+		domain:     reference.Domain(named),
+		repository: reference.Path(named),
+	}
+	switch r := named.(type) {
+	case reference.Digested:
+		ref.reference = r.Digest().String()
+	case reference.Tagged:
+		ref.reference = r.Tag()
+	default:
+		return imageReference{}, fmt.Errorf("image reference %q has no tag or digest", image)
+	}
+	return ref, nil
+}
+
+// registryClient pulls manifests and blobs from container registries using the
+// distribution API, authenticating with the docker credentials of the node if set.
+type registryClient struct {
+	client      *http.Client
+	credentials config.DockerCredentials
+
+	// tokens caches the bearer tokens per repository
+	tokens   map[string]string
+	tokensMu sync.Mutex
+}
+
+func newRegistryClient(client *http.Client, credentials config.DockerCredentials) *registryClient {
+	return &registryClient{
+		client:      client,
+		credentials: credentials,
+		tokens:      make(map[string]string),
+	}
+}
+
+// resolve returns the manifest of the image for the platform of the node, following the image indexes.
+func (c *registryClient) resolve(ctx context.Context, ref imageReference) (ocispec.Manifest, digest.Digest, error) {
+	data, mediaType, err := c.fetchManifest(ctx, ref, ref.reference)
+	if err != nil {
+		return ocispec.Manifest{}, "", err
+	}
+
+	if mediaType == ocispec.MediaTypeImageIndex || mediaType == mediaTypeDockerManifestList {
+		var index ocispec.Index
+		if err = json.Unmarshal(data, &index); err != nil {
+			return ocispec.Manifest{}, "", fmt.Errorf("decoding index of image %s: %w", ref.name, err)
+		}
+		descriptor, found := matchPlatform(index.Manifests)
+		if !found {
+			return ocispec.Manifest{}, "", fmt.Errorf("image %s is not available for %s/%s", ref.name, runtime.GOOS, runtime.GOARCH)
+		}
+		data, mediaType, err = c.fetchManifest(ctx, ref, descriptor.Digest.String())
+		if err != nil {
+			return ocispec.Manifest{}, "", err
+		}
+	}
+
+	if mediaType != ocispec.MediaTypeImageManifest && mediaType != mediaTypeDockerManifest {
+		return ocispec.Manifest{}, "", fmt.Errorf("unsupported manifest type %q for image %s", mediaType, ref.name)
+	}
+	var manifest ocispec.Manifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return ocispec.Manifest{}, "", fmt.Errorf("decoding manifest of image %s: %w", ref.name, err)
+	}
+	return manifest, digest.FromBytes(data), nil
+}
+
+// matchPlatform returns the manifest of the index matching the platform of the node.
+// Images are always run as linux images, as OCI runtimes only run linux containers.
+func matchPlatform(manifests []ocispec.Descriptor) (ocispec.Descriptor, bool) {
+	for _, descriptor := range manifests {
+		if descriptor.Platform == nil {
+			continue
+		}
+		if descriptor.Platform.OS == "linux" && descriptor.Platform.Architecture == runtime.GOARCH {
+			return descriptor, true
+		}
+	}
+	return ocispec.Descriptor{}, false
+}
+
+func (c *registryClient) fetchManifest(ctx context.Context, ref imageReference, manifestRef string) ([]byte, string, error) {
+	response, err := c.get(ctx, ref, "manifests/"+manifestRef, manifestMediaTypes)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching manifest of image %s: %w", ref.name, err)
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxManifestSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("reading manifest of image %s: %w", ref.name, err)
+	}
+	if dgst, err := digest.Parse(manifestRef); err == nil {
+		if err = dgst.Validate(); err == nil && dgst != dgst.Algorithm().FromBytes(data) {
+			return nil, "", fmt.Errorf("manifest of image %s does not match digest %s", ref.name, dgst)
+		}
+	}
+
+	mediaType, _, _ := strings.Cut(response.Header.Get("Content-Type"), ";")
+	if mediaType == "" || mediaType == "application/json" {
+		// fall back to the media type embedded in the manifest
+		var versioned struct {
+			MediaType string `json:"mediaType"`
+		}
+		_ = json.Unmarshal(data, &versioned)
+		mediaType = versioned.MediaType
+	}
+	return data, mediaType, nil
+}
+
+// fetchBlob returns the content of a blob of the image. The caller is responsible for
+// verifying the digest of the content, and closing it.
+func (c *registryClient) fetchBlob(ctx context.Context, ref imageReference, dgst digest.Digest) (io.ReadCloser, error) {
+	response, err := c.get(ctx, ref, "blobs/"+dgst.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching blob %s of image %s: %w", dgst, ref.name, err)
+	}
+	return response.Body, nil
+}
+
+// get sends a request to the registry of the image, authenticating if the registry requires it.
+func (c *registryClient) get(ctx context.Context, ref imageReference, path string, accept []string) (*http.Response, error) {
+	endpoint := fmt.Sprintf("%s/v2/%s/%s", registryURL(ref.domain), ref.repository, path)
+	newRequest := func() (*http.Request, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, mediaType := range accept {
+			request.Header.Add("Accept", mediaType)
+		}
+		return request, nil
+	}
+
+	request, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	c.authorize(request, ref)
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		_ = response.Body.Close()
+		if err = c.authenticate(ctx, ref, challenge); err != nil {
+			return nil, err
+		}
+		if request, err = newRequest(); err != nil {
+			return nil, err
+		}
+		c.authorize(request, ref)
+		if response, err = c.client.Do(request); err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s from registry %s", response.Status, ref.domain)
+	}
+	return response, nil
+}
+
+// authorize adds the credentials of the repository to the request, if any
+func (c *registryClient) authorize(request *http.Request, ref imageReference) {
+	c.tokensMu.Lock()
+	token, found := c.tokens[ref.domain+"/"+ref.repository]
+	c.tokensMu.Unlock()
+	if found {
+		request.Header.Set("Authorization", token)
+	}
+}
+
+// authenticate answers the authentication challenge of a registry, and caches the
+// resulting credentials for the repository.
+func (c *registryClient) authenticate(ctx context.Context, ref imageReference, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	var authorization string
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !c.credentials.IsValid() {
+			return fmt.Errorf("registry %s requires credentials", ref.domain)
+		}
+		request := &http.Request{Header: make(http.Header)}
+		request.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+		authorization = request.Header.Get("Authorization")
+	case "bearer":
+		token, err := c.fetchToken(ctx, ref, params)
+		if err != nil {
+			return err
+		}
+		authorization = "Bearer " + token
+	default:
+		return fmt.Errorf("unsupported authentication challenge %q from registry %s", challenge, ref.domain)
+	}
+
+	c.tokensMu.Lock()
+	c.tokens[ref.domain+"/"+ref.repository] = authorization
+	c.tokensMu.Unlock()
+	return nil
+}
+
+// fetchToken requests a pull token for the repository from the token server of the registry
+func (c *registryClient) fetchToken(ctx context.Context, ref imageReference, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q from registry %s", params["realm"], ref.domain)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.repository))
+	realm.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.credentials.IsValid() {
+		request.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("requesting token from %s: %w", realm.Host, err)
+	}
+	defer response.Body.Close() //nolint:errcheck
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s requesting token from %s", response.Status, realm.Host)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("decoding token from %s: %w", realm.Host, err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("no token returned by %s", realm.Host)
+}
+
+// parseChallenge parses a WWW-Authenticate header, e.g.
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return scheme, params
+}
+
+// registryURL returns the base URL of the registry of a domain. Like docker, registries
+// on loopback addresses are accessed over plain HTTP.
+func registryURL(domain string) string {
+	if domain == dockerHubDomain {
+		domain = dockerHubRegistry
+	}
+	host := domain
+	if h, _, err := net.SplitHostPort(domain); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return "http://" + domain
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return "http://" + domain
+	}
+	return "https://" + domain
+}
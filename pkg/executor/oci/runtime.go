@@ -0,0 +1,77 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ociRuntime runs containers with the command line interface of OCI runtimes, which is
+// the same for runc and crun.
+type ociRuntime struct {
+	// path of the runtime binary
+	path string
+	// root is the directory where the runtime stores the state of the containers
+	root string
+}
+
+// run starts a container from a bundle, with the output of its process written to the writers.
+// The returned command exits with the exit code of the process once it exits, and the runtime
+// deletes the container.
+func (r *ociRuntime) run(id string, bundle string, stdout, stderr io.Writer) (*exec.Cmd, error) {
+	// the command is not bound to a context, as killing the runtime would leave the
+	// container running. Containers are stopped with kill instead.
+	cmd := exec.Command(r.path, "--root", r.root, "run", "--bundle", bundle, id) //nolint:gosec
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", r.path, err)
+	}
+	return cmd, nil
+}
+
+// kill sends a signal to the process of a container, e.g. KILL
+func (r *ociRuntime) kill(ctx context.Context, id string, signal string) error {
+	_, err := r.exec(ctx, "kill", id, signal)
+	return err
+}
+
+// delete deletes a container, killing its process if it is still running
+func (r *ociRuntime) delete(ctx context.Context, id string) error {
+	_, err := r.exec(ctx, "delete", "--force", id)
+	return err
+}
+
+// list returns the IDs of the containers of the runtime
+func (r *ociRuntime) list(ctx context.Context) ([]string, error) {
+	output, err := r.exec(ctx, "list", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	var containers []struct {
+		ID string `json:"id"`
+	}
+	if err = json.Unmarshal(output, &containers); err != nil {
+		return nil, fmt.Errorf("decoding containers listed by %s: %w", r.path, err)
+	}
+	ids := make([]string, 0, len(containers))
+	for _, container := range containers {
+		ids = append(ids, container.ID)
+	}
+	return ids, nil
+}
+
+func (r *ociRuntime) exec(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.path, append([]string{"--root", r.root}, args...)...) //nolint:gosec
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", r.path, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
@@ -0,0 +1,183 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/klauspost/compress/zstd"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+
+	rootfsDirPerm = 0755
+)
+
+// decompressLayer returns the tar stream of a layer based on its media type
+func decompressLayer(layer io.Reader, mediaType string) (io.ReadCloser, error) {
+	switch mediaType {
+	case ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerNonDistributable: //nolint:staticcheck
+		return io.NopCloser(layer), nil
+	case ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerNonDistributableGzip, //nolint:staticcheck
+		mediaTypeDockerLayerGzip:
+		return gzip.NewReader(layer)
+	case ocispec.MediaTypeImageLayerZstd, ocispec.MediaTypeImageLayerNonDistributableZstd: //nolint:staticcheck
+		decoder, err := zstd.NewReader(layer)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported layer media type %q", mediaType)
+	}
+}
+
+// unpackLayer applies a layer on top of a root filesystem, following the changeset rules of the
+// OCI image specification: whiteout files remove the entries of the lower layers, and the entries
+// of the layer replace existing ones. Entries can't be written outside of the root filesystem,
+// even through symbolic links.
+//
+//nolint:gocyclo
+func unpackLayer(layer io.Reader, mediaType string, root string) error {
+	stream, err := decompressLayer(layer, mediaType)
+	if err != nil {
+		return err
+	}
+	defer stream.Close() //nolint:errcheck
+
+	isRoot := os.Geteuid() == 0
+	// the entries unpacked from this layer, which opaque whiteouts must not remove
+	unpacked := make(map[string]bool)
+	reader := tar.NewReader(stream)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading layer: %w", err)
+		}
+
+		name := path.Clean("/" + header.Name)
+		if name == "/" {
+			continue
+		}
+		dir, base := path.Split(name)
+		parent, err := securejoin.SecureJoin(root, dir)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", dir, err)
+		}
+
+		if base == whiteoutOpaque {
+			if err = removeChildren(parent, func(child string) bool { return unpacked[path.Join(dir, child)] }); err != nil {
+				return fmt.Errorf("applying opaque whiteout %s: %w", name, err)
+			}
+			continue
+		}
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			if err = os.RemoveAll(filepath.Join(parent, strings.TrimPrefix(base, whiteoutPrefix))); err != nil {
+				return fmt.Errorf("applying whiteout %s: %w", name, err)
+			}
+			continue
+		}
+
+		if err = os.MkdirAll(parent, rootfsDirPerm); err != nil {
+			return err
+		}
+		target := filepath.Join(parent, base)
+		// the entry replaces any existing one, except directories which are merged
+		if existing, statErr := os.Lstat(target); statErr == nil {
+			if !(existing.IsDir() && header.Typeflag == tar.TypeDir) {
+				if err = os.RemoveAll(target); err != nil {
+					return err
+				}
+			}
+		}
+
+		mode := header.FileInfo().Mode()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, rootfsDirPerm); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA: //nolint:staticcheck
+			if err = writeLayerFile(target, reader, mode.Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err = os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			source, joinErr := securejoin.SecureJoin(root, path.Clean("/"+header.Linkname))
+			if joinErr != nil {
+				return fmt.Errorf("resolving link %s: %w", header.Linkname, joinErr)
+			}
+			if err = os.Link(source, target); err != nil {
+				return err
+			}
+		default:
+			// devices and fifos are not unpacked, as the runtime creates the devices of the container
+			continue
+		}
+		unpacked[name] = true
+
+		if isRoot {
+			if err = os.Lchown(target, header.Uid, header.Gid); err != nil {
+				return err
+			}
+		}
+		if header.Typeflag == tar.TypeSymlink || header.Typeflag == tar.TypeLink {
+			continue
+		}
+		// chmod after chown, as changing the owner clears the setuid and setgid bits
+		if err = os.Chmod(target, mode&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)); err != nil {
+			return err
+		}
+		if err = os.Chtimes(target, header.ModTime, header.ModTime); err != nil {
+			return err
+		}
+	}
+}
+
+func writeLayerFile(target string, content io.Reader, perm os.FileMode) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(file, content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// removeChildren removes the entries of a directory, except the ones to keep
+func removeChildren(dir string, keep func(name string) bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if keep(entry.Name()) {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build unit || !integration
+
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+)
+
+type tarEntry struct {
+	name     string
+	content  string
+	typeflag byte
+	linkname string
+	mode     int64
+}
+
+func file(name, content string) tarEntry {
+	return tarEntry{name: name, content: content, typeflag: tar.TypeReg, mode: 0644}
+}
+
+func dir(name string) tarEntry {
+	return tarEntry{name: name, typeflag: tar.TypeDir, mode: 0755}
+}
+
+func symlink(name, target string) tarEntry {
+	return tarEntry{name: name, typeflag: tar.TypeSymlink, linkname: target, mode: 0777}
+}
+
+func hardlink(name, target string) tarEntry {
+	return tarEntry{name: name, typeflag: tar.TypeLink, linkname: target, mode: 0644}
+}
+
+// makeLayer returns a gzip compressed layer with the given entries
+func makeLayer(entries ...tarEntry) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Mode:     entry.mode,
+			Size:     int64(len(entry.content)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			panic(err)
+		}
+		if _, err := tw.Write([]byte(entry.content)); err != nil {
+			panic(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		panic(err)
+	}
+	if err := gz.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+type UnpackTestSuite struct {
+	suite.Suite
+	root string
+}
+
+func TestUnpackTestSuite(t *testing.T) {
+	suite.Run(t, new(UnpackTestSuite))
+}
+
+func (s *UnpackTestSuite) SetupTest() {
+	s.root = s.T().TempDir()
+}
+
+func (s *UnpackTestSuite) unpack(entries ...tarEntry) {
+	s.Require().NoError(unpackLayer(bytes.NewReader(makeLayer(entries...)), ocispec.MediaTypeImageLayerGzip, s.root))
+}
+
+func (s *UnpackTestSuite) readFile(name string) string {
+	data, err := os.ReadFile(filepath.Join(s.root, name))
+	s.Require().NoError(err)
+	return string(data)
+}
+
+func (s *UnpackTestSuite) TestFiles() {
+	s.unpack(
+		dir("etc/"),
+		file("etc/hostname", "bacalhau"),
+		file("usr/bin/app", "binary"),
+		symlink("bin", "usr/bin"),
+		hardlink("etc/hostname.bak", "etc/hostname"),
+	)
+	s.Equal("bacalhau", s.readFile("etc/hostname"))
+	s.Equal("bacalhau", s.readFile("etc/hostname.bak"))
+	s.Equal("binary", s.readFile("bin/app"))
+
+	target, err := os.Readlink(filepath.Join(s.root, "bin"))
+	s.Require().NoError(err)
+	s.Equal("usr/bin", target)
+}
+
+func (s *UnpackTestSuite) TestLayersReplaceEntries() {
+	s.unpack(file("etc/config", "lower"), file("data", "file"))
+	s.unpack(file("etc/config", "upper"), dir("data/"))
+
+	s.Equal("upper", s.readFile("etc/config"))
+	stat, err := os.Stat(filepath.Join(s.root, "data"))
+	s.Require().NoError(err)
+	s.True(stat.IsDir())
+}
+
+func (s *UnpackTestSuite) TestWhiteout() {
+	s.unpack(file("etc/keep", "keep"), file("etc/remove", "remove"), file("tmp/cache/file", "cache"))
+	s.unpack(file("etc/.wh.remove", ""), file("tmp/.wh.cache", ""))
+
+	s.Equal("keep", s.readFile("etc/keep"))
+	s.NoFileExists(filepath.Join(s.root, "etc/remove"))
+	s.NoFileExists(filepath.Join(s.root, "etc/.wh.remove"))
+	s.NoDirExists(filepath.Join(s.root, "tmp/cache"))
+}
+
+func (s *UnpackTestSuite) TestOpaqueWhiteout() {
+	s.unpack(file("app/old", "old"), file("app/lib/old", "old"))
+	// the entries of the same layer are kept, regardless of their order
+	s.unpack(dir("app/"), file("app/new", "new"), file("app/.wh..wh..opq", ""))
+
+	s.Equal("new", s.readFile("app/new"))
+	s.NoFileExists(filepath.Join(s.root, "app/old"))
+	s.NoDirExists(filepath.Join(s.root, "app/lib"))
+}
+
+func (s *UnpackTestSuite) TestEntriesStayInRoot() {
+	outside := s.T().TempDir()
+	s.unpack(
+		file("../../escape", "escape"),
+		symlink("link", outside),
+		file("link/escape", "escape"),
+		symlink("parent", "../.."),
+		file("parent/escape2", "escape"),
+	)
+
+	entries, err := os.ReadDir(outside)
+	s.Require().NoError(err)
+	s.Empty(entries)
+	// the entries are written inside the root, following the symlinks as if the root was /
+	s.Equal("escape", s.readFile("escape"))
+	s.Equal("escape", s.readFile(filepath.Join(outside, "escape")))
+	s.Equal("escape", s.readFile("escape2"))
+}
+
+func (s *UnpackTestSuite) TestUnsupportedMediaType() {
+	err := unpackLayer(bytes.NewReader(nil), "application/vnd.unknown", s.root)
+	s.ErrorContains(err, "unsupported layer media type")
+}
@@ -0,0 +1,65 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// credential is the user the processes of executions run as
+type credential struct {
+	uid    uint32
+	gid    uint32
+	groups []uint32
+}
+
+// lookupCredential returns the credential of a user, given as a name or an ID. It returns nil
+// when the processes run as the user of the node, which is only allowed when it isn't root.
+func lookupCredential(name string) (*credential, error) {
+	root := os.Geteuid() == 0
+	if name == "" {
+		if root {
+			return nil, errors.New("the user of the process executor is required when running as root")
+		}
+		return nil, nil
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		var unknownUser user.UnknownUserError
+		if !errors.As(err, &unknownUser) {
+			return nil, err
+		}
+		if u, err = user.LookupId(name); err != nil {
+			return nil, fmt.Errorf("looking up user %s of the process executor: %w", name, err)
+		}
+	}
+	uid, uidErr := strconv.ParseUint(u.Uid, 10, 32)
+	gid, gidErr := strconv.ParseUint(u.Gid, 10, 32)
+	if err = errors.Join(uidErr, gidErr); err != nil {
+		return nil, fmt.Errorf("invalid IDs of user %s: %w", name, err)
+	}
+	if uid == 0 {
+		return nil, errors.New("the processes of the process executor can't run as root")
+	}
+	if !root {
+		if int(uid) == os.Geteuid() {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("running processes as user %s requires running the compute node as root", name)
+	}
+
+	cred := &credential{uid: uint32(uid), gid: uint32(gid)}
+	groupIDs, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("looking up the groups of user %s: %w", name, err)
+	}
+	for _, groupID := range groupIDs {
+		if id, err := strconv.ParseUint(groupID, 10, 32); err == nil {
+			cred.groups = append(cred.groups, uint32(id))
+		}
+	}
+	return cred, nil
+}
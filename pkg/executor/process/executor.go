@@ -0,0 +1,408 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.uber.org/atomic"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	processmodels "github.com/bacalhau-project/bacalhau/pkg/executor/process/models"
+	wasmlogs "github.com/bacalhau-project/bacalhau/pkg/logger/wasm"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/generic"
+)
+
+type ExecutorParams struct {
+	// ID is used to tell apart the sandboxes and cgroups of multiple executors on the same host
+	ID string
+	// User is the user, as a name or an ID, the processes of executions run as.
+	// It is required when the node runs as root, in which case it can't be root.
+	User string
+	// AllowedCommands are the absolute paths, or glob patterns of absolute paths, of the
+	// commands that executions can run
+	AllowedCommands []string
+	// Directory stores the sandbox directories of executions
+	Directory string
+	// MaxProcesses is the maximum number of processes and threads of each execution, when
+	// their resources are limited by cgroups. Defaults to defaultMaxProcesses.
+	MaxProcesses int
+}
+
+// defaultMaxProcesses is the default maximum number of processes and threads of an execution,
+// which stops fork bombs from exhausting the processes of the host
+const defaultMaxProcesses = 1024
+
+// Executor runs the jobs of the process engine as plain processes on the host, for trusted clusters.
+// Processes run as a dedicated user in the sandbox directory of their execution, where their inputs
+// are linked and their outputs are written, with their resources limited by a cgroup when the node
+// can manage cgroups, and by rlimits.
+type Executor struct {
+	// used to tell apart the sandboxes and cgroups of multiple executors
+	ID string
+
+	// handlers is a map of executionID to its handler.
+	handlers generic.SyncMap[string, *executionHandler]
+
+	allowedCommands []string
+	sandboxesDir    string
+	credential      *credential
+	cgroups         *cgroupManager
+}
+
+func NewExecutor(ctx context.Context, params ExecutorParams) (*Executor, error) {
+	if params.Directory == "" {
+		return nil, errors.New("the directory of the process executor is required")
+	}
+	if params.MaxProcesses < 0 {
+		return nil, errors.New("the maximum number of processes of the process executor must not be negative")
+	}
+	for _, pattern := range params.AllowedCommands {
+		if !filepath.IsAbs(pattern) {
+			return nil, fmt.Errorf("allowed command %s must be an absolute path", pattern)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid allowed command pattern %s: %w", pattern, err)
+		}
+	}
+	cred, err := lookupCredential(params.User)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(params.Directory, sandboxDirPerm); err != nil {
+		return nil, fmt.Errorf("creating process executor directory: %w", err)
+	}
+
+	if params.MaxProcesses == 0 {
+		params.MaxProcesses = defaultMaxProcesses
+	}
+	cgroups, err := newCgroupManager(params.MaxProcesses)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).
+			Msg("unable to manage cgroups, the resources of processes are only limited with rlimits")
+	}
+
+	return &Executor{
+		ID:              params.ID,
+		allowedCommands: params.AllowedCommands,
+		sandboxesDir:    params.Directory,
+		credential:      cred,
+		cgroups:         cgroups,
+	}, nil
+}
+
+// IsInstalled checks if processes can be run by the executor, which is only supported on Linux.
+func (e *Executor) IsInstalled(context.Context) (bool, error) {
+	return supported, nil
+}
+
+func (e *Executor) ShouldBid(
+	ctx context.Context,
+	request bidstrategy.BidStrategyRequest,
+) (bidstrategy.BidStrategyResponse, error) {
+	spec, err := processmodels.DecodeSpec(request.Job.Task().Engine)
+	if err != nil {
+		return bidstrategy.BidStrategyResponse{}, err
+	}
+	if !e.isAllowed(spec.Command) {
+		return bidstrategy.NewBidResponse(false, "allow running %s", spec.Command), nil
+	}
+	if request.Job.Task().HasSecretFiles() {
+		return bidstrategy.NewBidResponse(false, "support secret files with the process executor"), nil
+	}
+	return bidstrategy.NewBidResponse(true, "allow running %s", spec.Command), nil
+}
+
+func (e *Executor) ShouldBidBasedOnUsage(
+	ctx context.Context,
+	request bidstrategy.BidStrategyRequest,
+	usage models.Resources,
+) (bidstrategy.BidStrategyResponse, error) {
+	return bidstrategy.NewBidResponse(true, "not place additional requirements on process jobs"), nil
+}
+
+// isAllowed returns true if the command matches one of the allowed commands of the executor
+func (e *Executor) isAllowed(command string) bool {
+	if !filepath.IsAbs(command) {
+		return false
+	}
+	command = filepath.Clean(command)
+	for _, pattern := range e.allowedCommands {
+		if matched, _ := filepath.Match(pattern, command); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Start initiates an execution based on the provided RunCommandRequest.
+func (e *Executor) Start(ctx context.Context, request *executor.RunCommandRequest) error {
+	log.Ctx(ctx).Info().
+		Str("executionID", request.ExecutionID).
+		Str("jobID", request.JobID).
+		Msg("starting execution")
+
+	if handler, found := e.handlers.Get(request.ExecutionID); found {
+		if handler.active() {
+			return fmt.Errorf("starting execution (%s): %w", request.ExecutionID, executor.ErrAlreadyStarted)
+		} else {
+			return fmt.Errorf("starting execution (%s): %w", request.ExecutionID, executor.ErrAlreadyComplete)
+		}
+	}
+
+	spec, err := processmodels.DecodeSpec(request.EngineParams)
+	if err != nil {
+		return fmt.Errorf("decoding engine spec: %w", err)
+	}
+	if !e.isAllowed(spec.Command) {
+		return fmt.Errorf("command %s is not allowed by the process executor", spec.Command)
+	}
+	// processes share the filesystem of the compute node, where secrets are never written
+	if len(request.SecretFiles) > 0 {
+		return errors.New("secret files are not supported by the process executor, use env vars instead")
+	}
+
+	name := e.sandboxName(request.ExecutionID)
+	sandboxDir := filepath.Join(e.sandboxesDir, name)
+	// remove the sandbox and cgroup of a previous run of the execution, e.g. before the compute
+	// node restarted, as its output can't be recovered.
+	e.removeSandbox(ctx, name)
+
+	sandbox, err := createSandbox(sandboxParams{
+		Dir:        sandboxDir,
+		Inputs:     request.Inputs,
+		Outputs:    request.Outputs,
+		ResultsDir: request.ResultsDir,
+		Credential: e.credential,
+	})
+	if err != nil {
+		_ = os.RemoveAll(sandboxDir)
+		return fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	workingDir, err := sandbox.workingDir(spec.WorkingDirectory)
+	if err != nil {
+		_ = os.RemoveAll(sandboxDir)
+		return err
+	}
+
+	logManager, err := wasmlogs.NewLogManager(ctx, request.ExecutionID)
+	if err != nil {
+		_ = os.RemoveAll(sandboxDir)
+		return err
+	}
+
+	handler := &executionHandler{
+		logger: log.With().
+			Str("sandbox", sandboxDir).
+			Str("execution", request.ExecutionID).
+			Str("job", request.JobID).
+			Logger(),
+		ID:          e.ID,
+		executionID: request.ExecutionID,
+		name:        name,
+		process: processParams{
+			Command:    spec.Command,
+			Arguments:  spec.Arguments,
+			Env:        processEnv(sandboxDir, spec.EnvironmentVariables, request.Env),
+			WorkingDir: workingDir,
+			Limits:     newRlimits(request.Resources, e.cgroups != nil),
+			Credential: e.credential,
+		},
+		resources:  request.Resources,
+		network:    request.Network,
+		cgroups:    e.cgroups,
+		sandbox:    sandbox,
+		resultsDir: request.ResultsDir,
+		limits:     request.OutputLimits,
+		keepStack:  config.ShouldKeepStack(),
+		logManager: logManager,
+		waitCh:     make(chan bool),
+		activeCh:   make(chan bool),
+		running:    atomic.NewBool(false),
+		pid:        atomic.NewInt32(0),
+	}
+
+	// register the handler for this executionID
+	e.handlers.Put(request.ExecutionID, handler)
+	// run the process.
+	go handler.run(ctx)
+	return nil
+}
+
+// Wait initiates a wait for the completion of a specific execution using its
+// executionID. The function returns two channels: one for the result and another
+// for any potential error. If the executionID is not found, an error is immediately
+// sent to the error channel. Otherwise, an internal goroutine (doWait) is spawned
+// to handle the asynchronous waiting.
+func (e *Executor) Wait(ctx context.Context, executionID string) (<-chan *models.RunCommandResult, <-chan error) {
+	handler, found := e.handlers.Get(executionID)
+	resultCh := make(chan *models.RunCommandResult, 1)
+	errCh := make(chan error, 1)
+
+	if !found {
+		errCh <- fmt.Errorf("waiting on execution (%s): %w", executionID, executor.ErrNotFound)
+		return resultCh, errCh
+	}
+
+	go e.doWait(ctx, resultCh, errCh, handler)
+	return resultCh, errCh
+}
+
+// doWait is a helper function that actively waits for an execution to finish. It
+// listens on the executionHandler's wait channel for completion signals. Once the
+// signal is received, the result is sent to the provided output channel. If there's
+// a cancellation request (context is done) before completion, an error is relayed to
+// the error channel.
+func (e *Executor) doWait(ctx context.Context, out chan *models.RunCommandResult, errCh chan error, handle *executionHandler) {
+	log.Info().Str("executionID", handle.executionID).Msg("waiting on execution")
+	defer close(out)
+	defer close(errCh)
+
+	select {
+	case <-ctx.Done():
+		errCh <- ctx.Err() // Send the cancellation error to the error channel
+		return
+	case <-handle.waitCh:
+		if handle.result != nil {
+			log.Info().Str("executionID", handle.executionID).Msg("received results from execution")
+			out <- handle.result
+		} else {
+			errCh <- fmt.Errorf("execution (%s) result is nil", handle.executionID)
+		}
+	}
+}
+
+// Cancel tries to cancel a specific execution by its executionID.
+// It returns an error if the execution is not found.
+func (e *Executor) Cancel(ctx context.Context, executionID string) error {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return fmt.Errorf("canceling execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	return handler.kill(ctx)
+}
+
+// GetLogStream provides a stream of output logs for a specific execution.
+// It returns an error if the execution is not found.
+func (e *Executor) GetLogStream(ctx context.Context, request executor.LogStreamRequest) (io.ReadCloser, error) {
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found {
+		return nil, fmt.Errorf("getting outputs for execution (%s): %w", request.ExecutionID, executor.ErrNotFound)
+	}
+	return handler.outputStream(ctx, request)
+}
+
+// Address returns the host at which a running execution can be reached from the compute node.
+// Processes share the network of the compute node, so they are reached on the loopback address.
+func (e *Executor) Address(ctx context.Context, executionID string) (string, error) {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return "", fmt.Errorf("address of execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-handler.activeCh:
+	}
+	if !handler.active() {
+		return "", fmt.Errorf("address of execution (%s): %w", executionID, executor.ErrAlreadyComplete)
+	}
+	return "127.0.0.1", nil
+}
+
+// PublishedPorts returns the ports of the compute node on which the ports of a running execution
+// are published. Processes share the network of the compute node, so their ports are published
+// on the ports they listen on.
+func (e *Executor) PublishedPorts(ctx context.Context, executionID string) (map[int]int, error) {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return nil, fmt.Errorf("ports of execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-handler.activeCh:
+	}
+	if !handler.active() {
+		return nil, fmt.Errorf("ports of execution (%s): %w", executionID, executor.ErrAlreadyComplete)
+	}
+	ports := make(map[int]int)
+	if handler.network != nil {
+		for _, port := range handler.network.Ports {
+			ports[port.Target] = port.Target
+		}
+	}
+	return ports, nil
+}
+
+// Run initiates and waits for the completion of an execution in one call.
+// This method serves as a higher-level convenience function that
+// internally calls Start and Wait methods.
+// It returns the result of the execution or an error if either starting
+// or waiting fails, or if the context is canceled.
+func (e *Executor) Run(
+	ctx context.Context,
+	request *executor.RunCommandRequest,
+) (*models.RunCommandResult, error) {
+	if err := e.Start(ctx, request); err != nil {
+		return nil, err
+	}
+	resCh, errCh := e.Wait(ctx, request.ExecutionID)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case out := <-resCh:
+		return out, nil
+	case err := <-errCh:
+		return nil, err
+	}
+}
+
+// Shutdown kills the processes of the executor that are still running, and removes their sandboxes.
+func (e *Executor) Shutdown(ctx context.Context) error {
+	if config.ShouldKeepStack() {
+		return nil
+	}
+	entries, err := os.ReadDir(e.sandboxesDir)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to list process sandboxes")
+		return nil
+	}
+	prefix := e.sandboxName("")
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix) {
+			e.removeSandbox(ctx, entry.Name())
+		}
+	}
+	return nil
+}
+
+// removeSandbox kills the processes left in the cgroup of an execution, and removes its
+// cgroup and sandbox, if they exist
+func (e *Executor) removeSandbox(ctx context.Context, name string) {
+	if e.cgroups != nil {
+		if err := e.cgroups.remove(name); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("cgroup", name).Msg("failed to remove cgroup")
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(e.sandboxesDir, name)); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("sandbox", name).Msg("failed to remove sandbox")
+	}
+}
+
+func (e *Executor) sandboxName(executionID string) string {
+	return strings.Join([]string{"bacalhau", e.ID, executionID}, "-")
+}
+
+// Compile-time check that Executor implements the Executor interface.
+var _ executor.Executor = (*Executor)(nil)
+var _ executor.Addresser = (*Executor)(nil)
+var _ executor.PortPublisher = (*Executor)(nil)
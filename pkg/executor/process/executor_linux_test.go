@@ -0,0 +1,129 @@
+//go:build (unit || !integration) && linux
+
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	processmodels "github.com/bacalhau-project/bacalhau/pkg/executor/process/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+type RunTestSuite struct {
+	suite.Suite
+	executor *Executor
+	input    string
+}
+
+func TestRunTestSuite(t *testing.T) {
+	suite.Run(t, new(RunTestSuite))
+}
+
+func (s *RunTestSuite) SetupTest() {
+	user := ""
+	if os.Geteuid() == 0 {
+		user = "nobody"
+	}
+	cred, err := lookupCredential(user)
+	if err != nil {
+		s.T().Skipf("no user to run processes as: %s", err)
+	}
+
+	// the user of the processes has to reach its sandbox, and read its inputs
+	sandboxesDir := s.accessibleDir()
+	s.input = s.accessibleDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(s.input, "data"), []byte("input"), 0644))
+
+	// the executor is created without cgroups, which would change the cgroups of the host
+	s.executor = &Executor{
+		ID:              "test",
+		allowedCommands: []string{"/bin/sh"},
+		sandboxesDir:    sandboxesDir,
+		credential:      cred,
+	}
+}
+
+func (s *RunTestSuite) accessibleDir() string {
+	dir := s.T().TempDir()
+	s.Require().NoError(os.Chmod(filepath.Dir(dir), 0755))
+	s.Require().NoError(os.Chmod(dir, 0755))
+	return dir
+}
+
+func (s *RunTestSuite) request(executionID string, script string) *executor.RunCommandRequest {
+	return &executor.RunCommandRequest{
+		JobID:       "job",
+		ExecutionID: executionID,
+		EngineParams: processmodels.NewProcessEngineBuilder("/bin/sh").
+			WithArguments("-c", script).
+			WithEnvironmentVariables("GREETING=hello").
+			Build(),
+		Inputs: []storage.PreparedStorage{{
+			Volume: storage.StorageVolume{Type: storage.StorageVolumeConnectorBind, Source: s.input, Target: "/inputs"},
+		}},
+		Outputs:    []*models.ResultPath{{Name: "outputs", Path: "/outputs"}},
+		ResultsDir: s.T().TempDir(),
+		Env:        map[string]string{"TASK": "task"},
+		OutputLimits: executor.OutputLimits{
+			MaxStdoutFileLength:   1024,
+			MaxStdoutReturnLength: 1024,
+			MaxStderrFileLength:   1024,
+			MaxStderrReturnLength: 1024,
+		},
+	}
+}
+
+func (s *RunTestSuite) TestRun() {
+	request := s.request("run",
+		`echo "$GREETING $TASK"; cat inputs/data > outputs/result; echo oops >&2; exit 3`)
+	result, err := s.executor.Run(context.Background(), request)
+	s.Require().NoError(err)
+
+	s.Equal(3, result.ExitCode)
+	s.Empty(result.ErrorMsg)
+	s.Equal("hello task\n", result.STDOUT)
+	s.Equal("oops\n", result.STDERR)
+
+	data, err := os.ReadFile(filepath.Join(request.ResultsDir, "outputs", "result"))
+	s.Require().NoError(err)
+	s.Equal("input", string(data))
+	s.NoDirExists(filepath.Join(s.executor.sandboxesDir, s.executor.sandboxName("run")))
+}
+
+func (s *RunTestSuite) TestCancel() {
+	request := s.request("cancel", `sleep 60 & sleep 60`)
+	s.Require().NoError(s.executor.Start(context.Background(), request))
+
+	handler, found := s.executor.handlers.Get("cancel")
+	s.Require().True(found)
+	<-handler.activeCh
+	s.Require().NoError(s.executor.Cancel(context.Background(), "cancel"))
+
+	resultCh, errCh := s.executor.Wait(context.Background(), "cancel")
+	select {
+	case result := <-resultCh:
+		s.NotEqual(0, result.ExitCode)
+	case err := <-errCh:
+		s.Require().NoError(err)
+	case <-time.After(10 * time.Second):
+		s.FailNow("the process was not killed")
+	}
+	s.False(handler.active())
+}
+
+func (s *RunTestSuite) TestStartTwice() {
+	request := s.request("twice", `true`)
+	_, err := s.executor.Run(context.Background(), request)
+	s.Require().NoError(err)
+
+	err = s.executor.Start(context.Background(), request)
+	s.ErrorIs(err, executor.ErrAlreadyComplete)
+}
@@ -0,0 +1,102 @@
+//go:build unit || !integration
+
+package process
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	processmodels "github.com/bacalhau-project/bacalhau/pkg/executor/process/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ExecutorTestSuite struct {
+	suite.Suite
+	executor *Executor
+}
+
+func TestExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorTestSuite))
+}
+
+func (s *ExecutorTestSuite) SetupTest() {
+	s.executor = &Executor{
+		ID:              "test",
+		allowedCommands: []string{"/bin/sh", "/opt/tools/*"},
+		sandboxesDir:    s.T().TempDir(),
+	}
+}
+
+func (s *ExecutorTestSuite) TestIsAllowed() {
+	s.True(s.executor.isAllowed("/bin/sh"))
+	s.True(s.executor.isAllowed("/opt/tools/convert"))
+	s.True(s.executor.isAllowed("/opt/tools/../tools/convert"))
+	s.False(s.executor.isAllowed("/opt/tools/bin/convert"))
+	s.False(s.executor.isAllowed("/bin/bash"))
+	s.False(s.executor.isAllowed("sh"))
+	s.False((&Executor{}).isAllowed("/bin/sh"), "no command is allowed by default")
+}
+
+func (s *ExecutorTestSuite) TestShouldBid() {
+	for _, tc := range []struct {
+		command   string
+		shouldBid bool
+	}{
+		{command: "/bin/sh", shouldBid: true},
+		{command: "/usr/bin/python3", shouldBid: false},
+	} {
+		s.Run(tc.command, func() {
+			job := &models.Job{Tasks: []*models.Task{{
+				Name:   "task",
+				Engine: processmodels.NewProcessEngineBuilder(tc.command).Build(),
+			}}}
+			response, err := s.executor.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{Job: *job})
+			s.Require().NoError(err)
+			s.Equal(tc.shouldBid, response.ShouldBid, response.Reason)
+		})
+	}
+}
+
+func (s *ExecutorTestSuite) TestShouldNotBidOnSecretFiles() {
+	job := &models.Job{Tasks: []*models.Task{{
+		Name:    "task",
+		Engine:  processmodels.NewProcessEngineBuilder("/bin/sh").Build(),
+		Secrets: []*models.SecretReference{{Name: "api-key", Path: "/run/secrets/api-key"}},
+	}}}
+	response, err := s.executor.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{Job: *job})
+	s.Require().NoError(err)
+	s.False(response.ShouldBid, response.Reason)
+}
+
+func (s *ExecutorTestSuite) TestNewExecutorValidatesAllowedCommands() {
+	_, err := NewExecutor(context.Background(), ExecutorParams{
+		ID:              "test",
+		User:            "nobody",
+		AllowedCommands: []string{"python3"},
+		Directory:       s.T().TempDir(),
+	})
+	s.ErrorContains(err, "must be an absolute path")
+}
+
+func (s *ExecutorTestSuite) TestNewExecutorValidatesMaxProcesses() {
+	_, err := NewExecutor(context.Background(), ExecutorParams{
+		ID:           "test",
+		User:         "nobody",
+		Directory:    s.T().TempDir(),
+		MaxProcesses: -1,
+	})
+	s.ErrorContains(err, "must not be negative")
+}
+
+func (s *ExecutorTestSuite) TestStartRejectsCommand() {
+	err := s.executor.Start(context.Background(), &executor.RunCommandRequest{
+		JobID:        "job",
+		ExecutionID:  "execution",
+		EngineParams: processmodels.NewProcessEngineBuilder("/usr/bin/python3").Build(),
+	})
+	s.ErrorContains(err, "is not allowed")
+}
@@ -0,0 +1,192 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/atomic"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	wasmlogs "github.com/bacalhau-project/bacalhau/pkg/logger/wasm"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// processParams define the process of an execution
+type processParams struct {
+	Command    string
+	Arguments  []string
+	Env        []string
+	WorkingDir string
+	Limits     rlimits
+	// Credential is the user the process runs as, or nil to run it as the user of the node
+	Credential *credential
+}
+
+type executionHandler struct {
+	//
+	// provided by the executor
+	logger zerolog.Logger
+	// meta data about the executor
+	ID string
+
+	//
+	// meta data about the task
+	executionID string
+	// name of the sandbox and cgroup of the execution
+	name       string
+	process    processParams
+	resources  *models.Resources
+	network    *models.NetworkConfig
+	cgroups    *cgroupManager
+	sandbox    *sandbox
+	resultsDir string
+	limits     executor.OutputLimits
+	keepStack  bool
+
+	// output of the process
+	logManager *wasmlogs.LogManager
+
+	//
+	// synchronization
+	// blocks until the process starts
+	activeCh chan bool
+	// blocks until the run method returns
+	waitCh chan bool
+	// true until the run method returns
+	running *atomic.Bool
+	// pid of the process once it started
+	pid *atomic.Int32
+
+	//
+	// results
+	result *models.RunCommandResult
+}
+
+func (h *executionHandler) run(ctx context.Context) {
+	ActiveExecutions.Inc(ctx, attribute.String("executor_id", h.ID))
+	h.running.Store(true)
+	defer func() {
+		if err := h.destroy(); err != nil {
+			h.logger.Warn().Err(err).Msg("failed to cleanup process")
+		}
+		h.running.Store(false)
+		close(h.waitCh)
+		ActiveExecutions.Dec(ctx, attribute.String("executor_id", h.ID))
+	}()
+
+	var cgroup *os.File
+	if h.cgroups != nil {
+		var err error
+		if cgroup, err = h.cgroups.create(h.name, h.resources); err != nil {
+			h.logger.Warn().Err(err).Msg("failed to create cgroup")
+			h.result = executor.NewFailedResult(fmt.Sprintf("failed to create cgroup: %s", err))
+			return
+		}
+	}
+
+	h.logger.Info().Msg("starting process execution")
+	stdout, stderr := h.logManager.GetWriters()
+	cmd, err := startProcess(h.process, stdout, stderr, cgroup)
+	if cgroup != nil {
+		// the process was moved to the cgroup as it started
+		_ = cgroup.Close()
+	}
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("failed to start process")
+		h.result = executor.NewFailedResult(fmt.Sprintf("failed to start process: %s", err))
+		return
+	}
+	h.pid.Store(int32(cmd.Process.Pid))
+	// The process is now active
+	close(h.activeCh)
+
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	var waitErr error
+	select {
+	case <-ctx.Done():
+		// failure case, the context has been canceled. We are aborting this execution
+		reason := fmt.Errorf("context canceled while waiting on process status: %w", ctx.Err())
+		h.logger.Err(reason).Msg("cancel waiting on process status")
+		h.result = executor.NewFailedResult(reason.Error())
+		return
+	case waitErr = <-waitCh:
+	}
+
+	// the idea here is even if the process errors
+	// we want to capture stdout, stderr and feed it back to the user
+	exitCode := 0
+	var processError error
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if waitErr != nil {
+		processError = waitErr
+		exitCode = 1
+	}
+	h.logger.Info().Int("status", exitCode).Err(processError).Msg("process execution ended")
+
+	// the children of the process could still be writing to the outputs
+	h.killProcesses()
+	if err = h.sandbox.collectOutputs(h.resultsDir); err != nil {
+		h.logger.Warn().Err(err).Msg("failed to collect outputs")
+		processError = errors.Join(processError, err)
+	}
+
+	// the process has exited, so there is nothing else to read from
+	h.logManager.Drain()
+	stdoutReader, stderrReader := h.logManager.GetDefaultReaders(false)
+	h.result = executor.WriteJobResults(h.resultsDir, stdoutReader, stderrReader, exitCode, processError, h.limits)
+}
+
+func (h *executionHandler) active() bool {
+	return h.running.Load()
+}
+
+func (h *executionHandler) kill(ctx context.Context) error {
+	h.logger.Info().Msg("killing the process")
+	// NB: killing the process will cause the run method to perform cleanup if still active
+	h.killProcesses()
+	return nil
+}
+
+// killProcesses kills the process of the execution, and all the processes it started
+func (h *executionHandler) killProcesses() {
+	if pid := h.pid.Load(); pid > 0 {
+		if err := killProcessGroup(int(pid)); err != nil {
+			h.logger.Debug().Err(err).Msg("process group already exited")
+		}
+	}
+	// processes can leave their process group, but not their cgroup
+	if h.cgroups != nil {
+		if err := h.cgroups.kill(h.name); err != nil {
+			h.logger.Debug().Err(err).Msg("failed to kill the processes of the cgroup")
+		}
+	}
+}
+
+func (h *executionHandler) destroy() error {
+	h.logger.Info().Msg("destroying the process sandbox")
+	h.killProcesses()
+
+	var err error
+	if h.cgroups != nil {
+		err = h.cgroups.remove(h.name)
+	}
+	if !h.keepStack {
+		h.logger.Info().Msg("removing sandbox")
+		err = errors.Join(err, os.RemoveAll(h.sandbox.dir))
+	}
+	return err
+}
+
+func (h *executionHandler) outputStream(ctx context.Context, request executor.LogStreamRequest) (io.ReadCloser, error) {
+	return h.logManager.GetMuxedReader(request.Follow), nil
+}
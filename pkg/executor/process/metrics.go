@@ -0,0 +1,20 @@
+package process
+
+import (
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+var (
+	processExecutorMeter = otel.GetMeterProvider().Meter("process-executor")
+)
+
+var (
+	ActiveExecutions = lo.Must(telemetry.NewGauge(
+		processExecutorMeter,
+		"process_active_executions",
+		"Number of active process executions",
+	))
+)
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/structs"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	EngineKeyCommandProcess              = "Command"
+	EngineKeyArgumentsProcess            = "Arguments"
+	EngineKeyEnvironmentVariablesProcess = "EnvironmentVariables"
+	EngineKeyWorkingDirectoryProcess     = "WorkingDirectory"
+)
+
+// EngineSpec contains necessary parameters to execute a process job.
+type EngineSpec struct {
+	// Command is the absolute path of the program to run, which must be allowed by the compute node
+	Command string `json:"Command,omitempty"`
+	// Arguments holds the commandline arguments of the program
+	Arguments []string `json:"Arguments,omitempty"`
+	// EnvironmentVariables is a slice of env to run the process with, in the KEY=VALUE format
+	EnvironmentVariables []string `json:"EnvironmentVariables,omitempty"`
+	// WorkingDirectory of the process, relative to the sandbox directory of the execution
+	WorkingDirectory string `json:"WorkingDirectory,omitempty"`
+}
+
+func (c EngineSpec) Validate() error {
+	if validate.IsBlank(c.Command) {
+		return errors.New("invalid process engine params: command cannot be empty")
+	}
+	if !filepath.IsAbs(c.Command) {
+		return fmt.Errorf("invalid process engine params: command %s must be an absolute path", c.Command)
+	}
+	return nil
+}
+
+func (c EngineSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+func DecodeSpec(spec *models.SpecConfig) (EngineSpec, error) {
+	if !spec.IsType(models.EngineProcess) {
+		return EngineSpec{}, errors.New("invalid process engine type. expected " + models.EngineProcess + ", but received: " + spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return EngineSpec{}, errors.New("invalid process engine params. cannot be nil")
+	}
+
+	paramsBytes, err := json.Marshal(inputParams)
+	if err != nil {
+		return EngineSpec{}, fmt.Errorf("failed to encode process engine specs. %w", err)
+	}
+
+	var c *EngineSpec
+	err = json.Unmarshal(paramsBytes, &c)
+	if err != nil {
+		return EngineSpec{}, fmt.Errorf("failed to decode process engine specs. %w", err)
+	}
+	return *c, c.Validate()
+}
+
+// ProcessEngineBuilder is a struct that is used for constructing an EngineSpec object
+// specifically for process engines using the Builder pattern.
+type ProcessEngineBuilder struct {
+	eb *models.SpecConfig
+}
+
+// NewProcessEngineBuilder function initializes a new ProcessEngineBuilder instance.
+// It sets the engine type to models.EngineProcess and command as per the input argument.
+func NewProcessEngineBuilder(command string) *ProcessEngineBuilder {
+	eb := models.NewSpecConfig(models.EngineProcess)
+	eb.WithParam(EngineKeyCommandProcess, command)
+	return &ProcessEngineBuilder{eb: eb}
+}
+
+// WithArguments is a builder method that sets the process engine's arguments.
+// It returns the ProcessEngineBuilder for further chaining of builder methods.
+func (b *ProcessEngineBuilder) WithArguments(e ...string) *ProcessEngineBuilder {
+	b.eb.WithParam(EngineKeyArgumentsProcess, e)
+	return b
+}
+
+// WithEnvironmentVariables is a builder method that sets the process engine's environment variables.
+// It returns the ProcessEngineBuilder for further chaining of builder methods.
+func (b *ProcessEngineBuilder) WithEnvironmentVariables(e ...string) *ProcessEngineBuilder {
+	b.eb.WithParam(EngineKeyEnvironmentVariablesProcess, e)
+	return b
+}
+
+// WithWorkingDirectory is a builder method that sets the process engine's working directory.
+// It returns the ProcessEngineBuilder for further chaining of builder methods.
+func (b *ProcessEngineBuilder) WithWorkingDirectory(e string) *ProcessEngineBuilder {
+	b.eb.WithParam(EngineKeyWorkingDirectoryProcess, e)
+	return b
+}
+
+// Build method constructs the final SpecConfig object by calling the embedded EngineBuilder's Build method.
+func (b *ProcessEngineBuilder) Build() *models.SpecConfig {
+	return b.eb
+}
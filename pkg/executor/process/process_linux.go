@@ -0,0 +1,190 @@
+//go:build linux
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// supported is true as processes can be run on Linux
+const supported = true
+
+const (
+	cgroupMountpoint = "/sys/fs/cgroup"
+	// cgroupsRoot is the cgroup the cgroups of executions are created in
+	cgroupsRoot = "bacalhau"
+	// cgroupRemoveTimeout is how long the processes of a cgroup are waited for to exit once killed
+	cgroupRemoveTimeout = 5 * time.Second
+	cgroupRemoveBackoff = 100 * time.Millisecond
+	// cpuPeriod is the period of the CPU quota of cgroups, in microseconds
+	cpuPeriod = 100000
+)
+
+// cgroupControllers are the controllers enforcing the resource limits of executions
+var cgroupControllers = []string{"cpu", "memory", "pids"}
+
+// startProcess starts the process of an execution in its own process group, as its user, and in
+// its cgroup if not nil.
+func startProcess(params processParams, stdout, stderr io.Writer, cgroup *os.File) (*exec.Cmd, error) {
+	args := params.Limits.wrap(params.Command, params.Arguments...)
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	cmd.Dir = params.WorkingDir
+	cmd.Env = params.Env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if params.Credential != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{
+			Uid:    params.Credential.uid,
+			Gid:    params.Credential.gid,
+			Groups: params.Credential.groups,
+		}
+	}
+	if cgroup != nil {
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = int(cgroup.Fd())
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", params.Command, err)
+	}
+	return cmd, nil
+}
+
+// killProcessGroup kills the processes of the process group of a process
+func killProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// cgroupManager manages the cgroups v2 of executions, enforcing their CPU, memory and process limits
+type cgroupManager struct {
+	root string
+	// maxProcesses is the maximum number of processes and threads of each execution
+	maxProcesses int
+}
+
+// newCgroupManager returns a manager of the cgroups of executions, or an error if the node can't
+// manage them, e.g. if it doesn't use cgroups v2 or isn't allowed to create cgroups.
+func newCgroupManager(maxProcesses int) (*cgroupManager, error) {
+	if _, err := os.Stat(filepath.Join(cgroupMountpoint, "cgroup.controllers")); err != nil {
+		return nil, errors.New("cgroups v2 are not available")
+	}
+	root := filepath.Join(cgroupMountpoint, cgroupsRoot)
+	if err := os.MkdirAll(root, sandboxDirPerm); err != nil {
+		return nil, fmt.Errorf("creating cgroup %s: %w", root, err)
+	}
+	for _, dir := range []string{cgroupMountpoint, root} {
+		if err := enableControllers(dir); err != nil {
+			return nil, err
+		}
+	}
+	return &cgroupManager{root: root, maxProcesses: maxProcesses}, nil
+}
+
+// enableControllers enables the controllers of executions in the children of a cgroup
+func enableControllers(dir string) error {
+	available, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return err
+	}
+	var enable []string
+	for _, controller := range cgroupControllers {
+		if !strings.Contains(" "+strings.TrimSpace(string(available))+" ", " "+controller+" ") {
+			return fmt.Errorf("cgroup controller %s is not available in %s", controller, dir)
+		}
+		enable = append(enable, "+"+controller)
+	}
+	return writeCgroupFile(dir, "cgroup.subtree_control", strings.Join(enable, " "))
+}
+
+// create creates the cgroup of an execution with its resource limits, and returns it opened
+// so that its process can be started in it
+func (m *cgroupManager) create(name string, resources *models.Resources) (*os.File, error) {
+	dir := filepath.Join(m.root, name)
+	if err := os.Mkdir(dir, sandboxDirPerm); err != nil {
+		return nil, err
+	}
+	if m.maxProcesses > 0 {
+		if err := writeCgroupFile(dir, "pids.max", strconv.Itoa(m.maxProcesses)); err != nil {
+			return nil, err
+		}
+	}
+	if resources != nil && resources.Memory > 0 {
+		if err := writeCgroupFile(dir, "memory.max", strconv.FormatUint(resources.Memory, 10)); err != nil {
+			return nil, err
+		}
+		// swapping would let processes use more memory than their limit
+		if err := writeCgroupFile(dir, "memory.swap.max", "0"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if resources != nil && resources.CPU > 0 {
+		quota := fmt.Sprintf("%d %d", int64(resources.CPU*cpuPeriod), cpuPeriod)
+		if err := writeCgroupFile(dir, "cpu.max", quota); err != nil {
+			return nil, err
+		}
+	}
+	return os.Open(dir)
+}
+
+// kill kills the processes of the cgroup of an execution
+func (m *cgroupManager) kill(name string) error {
+	dir := filepath.Join(m.root, name)
+	err := writeCgroupFile(dir, "cgroup.kill", "1")
+	if err == nil || errors.Is(err, fs.ErrNotExist) && !exists(dir) {
+		return nil
+	}
+	// cgroup.kill is only available since Linux 5.14
+	procs, readErr := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if readErr != nil {
+		return errors.Join(err, readErr)
+	}
+	for _, field := range strings.Fields(string(procs)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+	return nil
+}
+
+// remove kills the processes of the cgroup of an execution, and removes it once they exited
+func (m *cgroupManager) remove(name string) error {
+	dir := filepath.Join(m.root, name)
+	if !exists(dir) {
+		return nil
+	}
+	if err := m.kill(name); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(cgroupRemoveTimeout)
+	for {
+		err := syscall.Rmdir(dir)
+		if err == nil || errors.Is(err, syscall.ENOENT) {
+			return nil
+		}
+		if !errors.Is(err, syscall.EBUSY) || time.Now().After(deadline) {
+			return fmt.Errorf("removing cgroup %s: %w", dir, err)
+		}
+		time.Sleep(cgroupRemoveBackoff)
+	}
+}
+
+func writeCgroupFile(dir, file, value string) error {
+	return os.WriteFile(filepath.Join(dir, file), []byte(value), 0)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
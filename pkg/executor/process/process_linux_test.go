@@ -0,0 +1,50 @@
+//go:build (unit || !integration) && linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// readCgroupFile returns the value written to a file of a cgroup created in a regular directory
+func readCgroupFile(t *testing.T, dir, file string) string {
+	path := filepath.Join(dir, file)
+	// the files of real cgroups already exist, so they are written without permissions
+	require.NoError(t, os.Chmod(path, 0o600))
+	value, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(value)
+}
+
+func TestCgroupManagerCreateLimits(t *testing.T) {
+	manager := &cgroupManager{root: t.TempDir(), maxProcesses: 100}
+
+	cgroup, err := manager.create("execution", &models.Resources{CPU: 0.5, Memory: 64 * 1024 * 1024})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cgroup.Close() })
+
+	dir := filepath.Join(manager.root, "execution")
+	require.Equal(t, "100", readCgroupFile(t, dir, "pids.max"))
+	require.Equal(t, "67108864", readCgroupFile(t, dir, "memory.max"))
+	require.Equal(t, "0", readCgroupFile(t, dir, "memory.swap.max"))
+	require.Equal(t, "50000 100000", readCgroupFile(t, dir, "cpu.max"))
+}
+
+func TestCgroupManagerCreateLimitsProcessesWithoutResources(t *testing.T) {
+	manager := &cgroupManager{root: t.TempDir(), maxProcesses: defaultMaxProcesses}
+
+	cgroup, err := manager.create("execution", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cgroup.Close() })
+
+	dir := filepath.Join(manager.root, "execution")
+	require.Equal(t, "1024", readCgroupFile(t, dir, "pids.max"))
+	require.NoFileExists(t, filepath.Join(dir, "memory.max"))
+	require.NoFileExists(t, filepath.Join(dir, "cpu.max"))
+}
@@ -0,0 +1,43 @@
+//go:build !linux
+
+package process
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// supported is false as processes can only be run on Linux
+const supported = false
+
+var errUnsupported = errors.New("the process executor is only supported on Linux")
+
+func startProcess(params processParams, stdout, stderr io.Writer, cgroup *os.File) (*exec.Cmd, error) {
+	return nil, errUnsupported
+}
+
+func killProcessGroup(pid int) error {
+	return errUnsupported
+}
+
+type cgroupManager struct{}
+
+func newCgroupManager(maxProcesses int) (*cgroupManager, error) {
+	return nil, errUnsupported
+}
+
+func (m *cgroupManager) create(name string, resources *models.Resources) (*os.File, error) {
+	return nil, errUnsupported
+}
+
+func (m *cgroupManager) kill(name string) error {
+	return errUnsupported
+}
+
+func (m *cgroupManager) remove(name string) error {
+	return errUnsupported
+}
@@ -0,0 +1,57 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// shell sets the rlimits of processes before executing them, as they can't be set on
+	// child processes from go
+	shell = "/bin/sh"
+	// fileSizeBlock is the unit of the file size limit of ulimit
+	fileSizeBlock = 512
+	// addressSpaceBlock is the unit of the address space limit of ulimit
+	addressSpaceBlock = 1024
+)
+
+// rlimits are the resource limits of a process, on top of the ones of its cgroup
+type rlimits struct {
+	// fileSize is the maximum size of the files written by the process, in bytes
+	fileSize uint64
+	// addressSpace is the maximum size of the virtual memory of the process, in bytes
+	addressSpace uint64
+}
+
+// newRlimits returns the rlimits of a process. The disk of a process limits the size of the files
+// it writes. Its memory is limited by its cgroup if there is one, or by its virtual memory otherwise,
+// which is stricter than its actual usage.
+func newRlimits(resources *models.Resources, withCgroup bool) rlimits {
+	if resources == nil {
+		return rlimits{}
+	}
+	limits := rlimits{fileSize: resources.Disk}
+	if !withCgroup {
+		limits.addressSpace = resources.Memory
+	}
+	return limits
+}
+
+// wrap returns the arguments running a command with the rlimits applied. Core dumps are always disabled.
+func (l rlimits) wrap(command string, arguments ...string) []string {
+	commands := []string{"ulimit -c 0"}
+	if l.fileSize > 0 {
+		commands = append(commands, fmt.Sprintf("ulimit -f %d", ceilDiv(l.fileSize, fileSizeBlock)))
+	}
+	if l.addressSpace > 0 {
+		commands = append(commands, fmt.Sprintf("ulimit -v %d", ceilDiv(l.addressSpace, addressSpaceBlock)))
+	}
+	commands = append(commands, `exec "$@"`)
+	return append([]string{shell, "-c", strings.Join(commands, " && "), command, command}, arguments...)
+}
+
+func ceilDiv(value, unit uint64) uint64 {
+	return (value + unit - 1) / unit
+}
@@ -0,0 +1,285 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+const (
+	// sandboxDirPerm allows the users of processes to traverse the directory of the executor
+	// to reach their sandboxes, without listing them
+	sandboxDirPerm = 0711
+	// executionDirPerm restricts the sandbox of an execution to the user of its process
+	executionDirPerm = 0700
+	// sandboxTmpDir is the temporary directory of processes, inside their sandbox
+	sandboxTmpDir = "tmp"
+
+	defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+type sandboxParams struct {
+	Dir     string
+	Inputs  []storage.PreparedStorage
+	Outputs []*models.ResultPath
+	// ResultsDir is the results directory of the execution, where outputs restored from a
+	// checkpoint are found
+	ResultsDir string
+	// Credential is the user owning the sandbox, or nil to keep the user of the node
+	Credential *credential
+}
+
+// sandbox is the directory an execution's process runs in. The paths of the inputs and
+// outputs of the execution are relative to it: inputs are linked at their target path, and
+// outputs are written to their path before being moved to the results of the execution.
+type sandbox struct {
+	dir        string
+	outputs    []*models.ResultPath
+	credential *credential
+}
+
+func createSandbox(params sandboxParams) (*sandbox, error) {
+	s := &sandbox{
+		dir:        params.Dir,
+		outputs:    params.Outputs,
+		credential: params.Credential,
+	}
+	if err := os.Mkdir(s.dir, executionDirPerm); err != nil {
+		return nil, err
+	}
+	if err := s.chown(s.dir); err != nil {
+		return nil, err
+	}
+	if _, err := s.mkdirAll(sandboxTmpDir); err != nil {
+		return nil, err
+	}
+
+	for _, input := range params.Inputs {
+		if input.Volume.Type != storage.StorageVolumeConnectorBind {
+			return nil, fmt.Errorf("unknown storage volume type: %s", input.Volume.Type)
+		}
+		target, err := securejoin.SecureJoin(s.dir, input.Volume.Target)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = s.mkdirAll(filepath.Dir(strings.TrimPrefix(target, s.dir))); err != nil {
+			return nil, err
+		}
+		if err = os.Symlink(input.Volume.Source, target); err != nil {
+			return nil, fmt.Errorf("linking input %s: %w", input.Volume.Target, err)
+		}
+	}
+
+	for _, output := range params.Outputs {
+		if output.Name == "" {
+			return nil, fmt.Errorf("output volume has no name: %+v", output)
+		}
+		if output.Path == "" {
+			return nil, fmt.Errorf("output volume has no Location: %+v", output)
+		}
+		target, err := s.mkdirAll(output.Path)
+		if err != nil {
+			return nil, fmt.Errorf("creating output %s: %w", output.Name, err)
+		}
+		if params.ResultsDir == "" {
+			continue
+		}
+		if err = s.restoreOutput(filepath.Join(params.ResultsDir, output.Name), target); err != nil {
+			return nil, fmt.Errorf("restoring output %s: %w", output.Name, err)
+		}
+	}
+	return s, nil
+}
+
+// restoreOutput moves an output the compute node restored from a checkpoint into the
+// sandbox, so that the process starts from it and it is collected again when it exits
+func (s *sandbox) restoreOutput(source, target string) error {
+	if _, err := os.Lstat(source); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := copyDir(source, target); err != nil {
+		return err
+	}
+	if s.credential != nil {
+		if err := filepath.WalkDir(target, func(path string, _ fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return s.chown(path)
+		}); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(source)
+}
+
+// workingDir returns the working directory of the process, which is the sandbox unless the
+// engine spec sets one inside it
+func (s *sandbox) workingDir(dir string) (string, error) {
+	if dir == "" {
+		return s.dir, nil
+	}
+	path, err := s.mkdirAll(dir)
+	if err != nil {
+		return "", fmt.Errorf("creating working directory %s: %w", dir, err)
+	}
+	return path, nil
+}
+
+// mkdirAll creates a directory inside the sandbox along with its parents, all owned by the
+// user of the sandbox, and returns its path
+func (s *sandbox) mkdirAll(dir string) (string, error) {
+	path := s.dir
+	for _, part := range strings.Split(filepath.Clean("/"+dir), string(filepath.Separator)) {
+		if part == "" {
+			continue
+		}
+		next, err := securejoin.SecureJoin(path, part)
+		if err != nil {
+			return "", err
+		}
+		err = os.Mkdir(next, executionDirPerm)
+		if errors.Is(err, fs.ErrExist) {
+			path = next
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if err = s.chown(next); err != nil {
+			return "", err
+		}
+		path = next
+	}
+	return path, nil
+}
+
+func (s *sandbox) chown(path string) error {
+	if s.credential == nil {
+		return nil
+	}
+	return os.Lchown(path, int(s.credential.uid), int(s.credential.gid))
+}
+
+// collectOutputs moves the outputs written by the process to the results directory
+// of the execution, where they are published from
+func (s *sandbox) collectOutputs(resultsDir string) error {
+	for _, output := range s.outputs {
+		parent, err := securejoin.SecureJoin(s.dir, filepath.Dir(filepath.Clean("/"+output.Path)))
+		if err != nil {
+			return err
+		}
+		// the process could have replaced its output by a link to anywhere on the host
+		source := filepath.Join(parent, filepath.Base(output.Path))
+		info, err := os.Lstat(source)
+		if err != nil {
+			return fmt.Errorf("reading output %s: %w", output.Name, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("output %s at %s is not a directory", output.Name, output.Path)
+		}
+		target := filepath.Join(resultsDir, output.Name)
+		if err = os.Rename(source, target); err == nil {
+			continue
+		}
+		// the sandbox and the results are on different filesystems
+		if err = copyDir(source, target); err != nil {
+			return fmt.Errorf("copying output %s: %w", output.Name, err)
+		}
+	}
+	return nil
+}
+
+// copyDir copies a directory tree, without following the links it contains
+func copyDir(source, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(target, rel)
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(dst, executionDirPerm)
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, dst)
+		case entry.Type().IsRegular():
+			return copyFile(path, dst)
+		default:
+			// devices, sockets and pipes are not results
+			return nil
+		}
+	})
+}
+
+func copyFile(source, target string) error {
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// processEnv returns the environment of the process. It does not inherit the environment of the
+// node: it starts from a default environment pointing at the sandbox, overridden by the variables
+// of the engine, which are overridden by the ones of the task.
+func processEnv(sandboxDir string, engineEnv []string, taskEnv map[string]string) []string {
+	var env []string
+	index := make(map[string]int)
+	set := func(variable string) {
+		key, _, _ := strings.Cut(variable, "=")
+		if i, found := index[key]; found {
+			env[i] = variable
+			return
+		}
+		index[key] = len(env)
+		env = append(env, variable)
+	}
+
+	set("PATH=" + defaultPath)
+	set("HOME=" + sandboxDir)
+	set("TMPDIR=" + filepath.Join(sandboxDir, sandboxTmpDir))
+	set("BACALHAU_SANDBOX=" + sandboxDir)
+	for _, variable := range engineEnv {
+		set(variable)
+	}
+	keys := make([]string, 0, len(taskEnv))
+	for key := range taskEnv {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		set(key + "=" + taskEnv[key])
+	}
+	return env
+}
@@ -0,0 +1,146 @@
+//go:build unit || !integration
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+type SandboxTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestSandboxTestSuite(t *testing.T) {
+	suite.Run(t, new(SandboxTestSuite))
+}
+
+func (s *SandboxTestSuite) SetupTest() {
+	s.dir = filepath.Join(s.T().TempDir(), "sandbox")
+}
+
+func (s *SandboxTestSuite) TestInputsAndOutputs() {
+	input := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(input, "data"), []byte("input"), 0600))
+
+	sb, err := createSandbox(sandboxParams{
+		Dir: s.dir,
+		Inputs: []storage.PreparedStorage{{
+			Volume: storage.StorageVolume{Type: storage.StorageVolumeConnectorBind, Source: input, Target: "/inputs/data"},
+		}},
+		Outputs: []*models.ResultPath{{Name: "outputs", Path: "/outputs"}},
+	})
+	s.Require().NoError(err)
+
+	data, err := os.ReadFile(filepath.Join(s.dir, "inputs", "data", "data"))
+	s.Require().NoError(err)
+	s.Equal("input", string(data))
+	s.DirExists(filepath.Join(s.dir, sandboxTmpDir))
+
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "outputs", "result"), []byte("output"), 0600))
+	resultsDir := s.T().TempDir()
+	s.Require().NoError(sb.collectOutputs(resultsDir))
+	data, err = os.ReadFile(filepath.Join(resultsDir, "outputs", "result"))
+	s.Require().NoError(err)
+	s.Equal("output", string(data))
+}
+
+func (s *SandboxTestSuite) TestRestoredOutputs() {
+	// the compute node restored a checkpoint into the results of the execution
+	resultsDir := s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(resultsDir, "checkpoint", "nested"), 0700))
+	s.Require().NoError(os.WriteFile(filepath.Join(resultsDir, "checkpoint", "nested", "state"), []byte("step 1"), 0600))
+
+	sb, err := createSandbox(sandboxParams{
+		Dir:        s.dir,
+		Outputs:    []*models.ResultPath{{Name: "checkpoint", Path: "/checkpoint"}, {Name: "outputs", Path: "/outputs"}},
+		ResultsDir: resultsDir,
+	})
+	s.Require().NoError(err)
+	data, err := os.ReadFile(filepath.Join(s.dir, "checkpoint", "nested", "state"))
+	s.Require().NoError(err)
+	s.Equal("step 1", string(data))
+	s.NoDirExists(filepath.Join(resultsDir, "checkpoint"))
+
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "checkpoint", "nested", "state"), []byte("step 2"), 0600))
+	s.Require().NoError(sb.collectOutputs(resultsDir))
+	data, err = os.ReadFile(filepath.Join(resultsDir, "checkpoint", "nested", "state"))
+	s.Require().NoError(err)
+	s.Equal("step 2", string(data))
+}
+
+func (s *SandboxTestSuite) TestOutputsMustStayDirectories() {
+	sb, err := createSandbox(sandboxParams{
+		Dir:     s.dir,
+		Outputs: []*models.ResultPath{{Name: "outputs", Path: "/outputs"}},
+	})
+	s.Require().NoError(err)
+
+	// the process replaced its output with a link to the host
+	s.Require().NoError(os.Remove(filepath.Join(s.dir, "outputs")))
+	s.Require().NoError(os.Symlink("/etc", filepath.Join(s.dir, "outputs")))
+	s.ErrorContains(sb.collectOutputs(s.T().TempDir()), "is not a directory")
+}
+
+func (s *SandboxTestSuite) TestWorkingDirStaysInSandbox() {
+	sb, err := createSandbox(sandboxParams{Dir: s.dir})
+	s.Require().NoError(err)
+
+	dir, err := sb.workingDir("")
+	s.Require().NoError(err)
+	s.Equal(s.dir, dir)
+
+	dir, err = sb.workingDir("/work/../../../project")
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(s.dir, "project"), dir)
+	s.DirExists(dir)
+}
+
+func (s *SandboxTestSuite) TestCopyDir() {
+	source := s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(source, "nested"), 0700))
+	s.Require().NoError(os.WriteFile(filepath.Join(source, "nested", "file"), []byte("content"), 0640))
+	s.Require().NoError(os.Symlink("/etc/passwd", filepath.Join(source, "link")))
+
+	target := filepath.Join(s.T().TempDir(), "copy")
+	s.Require().NoError(copyDir(source, target))
+
+	data, err := os.ReadFile(filepath.Join(target, "nested", "file"))
+	s.Require().NoError(err)
+	s.Equal("content", string(data))
+	link, err := os.Readlink(filepath.Join(target, "link"))
+	s.Require().NoError(err)
+	s.Equal("/etc/passwd", link)
+}
+
+func (s *SandboxTestSuite) TestProcessEnv() {
+	env := processEnv("/sandbox", []string{"HOME=/home/app", "ENGINE=engine", "SHARED=engine"},
+		map[string]string{"SHARED": "task", "TASK": "task"})
+	s.Equal([]string{
+		"PATH=" + defaultPath,
+		"HOME=/home/app",
+		"TMPDIR=/sandbox/tmp",
+		"BACALHAU_SANDBOX=/sandbox",
+		"ENGINE=engine",
+		"SHARED=task",
+		"TASK=task",
+	}, env)
+}
+
+func (s *SandboxTestSuite) TestRlimits() {
+	s.Equal([]string{shell, "-c", `ulimit -c 0 && exec "$@"`, "/bin/app", "/bin/app", "arg"},
+		newRlimits(nil, false).wrap("/bin/app", "arg"))
+
+	limits := newRlimits(&models.Resources{Disk: 1000, Memory: 2048}, false)
+	s.Equal(`ulimit -c 0 && ulimit -f 2 && ulimit -v 2 && exec "$@"`, limits.wrap("/bin/app")[2])
+
+	limits = newRlimits(&models.Resources{Disk: 1000, Memory: 2048}, true)
+	s.Equal(`ulimit -c 0 && ulimit -f 2 && exec "$@"`, limits.wrap("/bin/app")[2], "the cgroup limits the memory")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
@@ -52,12 +53,61 @@ type Executor interface {
 	GetLogStream(ctx context.Context, request LogStreamRequest) (io.ReadCloser, error)
 }
 
+// Execer is implemented by executors that can run commands inside their running executions,
+// e.g. to debug a long-running service.
+type Execer interface {
+	// Exec runs a command inside the running execution identified by its executionID, attaching the
+	// streams of the request, and returns the exit code of the command once it exits.
+	// It returns an error if the execution does not exist or is no longer running.
+	Exec(ctx context.Context, request ExecRequest) (int, error)
+}
+
+// Addresser is implemented by executors whose running executions can be reached over the network
+// from the compute node, e.g. to check the health of a long-running service.
+type Addresser interface {
+	// Address returns the host at which the running execution identified by its executionID can be
+	// reached from the compute node. It returns an error if the execution does not exist, is no
+	// longer running, or cannot be reached.
+	Address(ctx context.Context, executionID string) (string, error)
+}
+
+// PortPublisher is implemented by executors that publish the ports of tasks on the compute node,
+// so that the running executions can be reached from other nodes.
+type PortPublisher interface {
+	// PublishedPorts returns the ports of the compute node on which the ports of the running
+	// execution identified by its executionID are published, keyed by the port the task listens on.
+	// It returns an error if the execution does not exist or is no longer running.
+	PublishedPorts(ctx context.Context, executionID string) (map[int]int, error)
+}
+
+// ExecRequest encapsulates the parameters required to run a command inside a running execution.
+type ExecRequest struct {
+	ExecutionID string
+	Command     []string
+	// TTY allocates a terminal for the command, in which case the output is only written to Stdout
+	TTY bool
+	// Size is the initial size of the terminal, if TTY is set
+	Size *models.TerminalSize
+	// Resize receives the new sizes of the terminal, if TTY is set. Optional.
+	Resize <-chan models.TerminalSize
+	// Stdin is attached to the standard input of the command. Optional.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
 // LogStreamRequest encapsulates the parameters required to retrieve a log stream.
 type LogStreamRequest struct {
 	JobID       string
 	ExecutionID string
 	Tail        bool
 	Follow      bool
+	// Offset is the number of lines to skip from the start of the logs
+	Offset int
+	// TailLines limits the existing logs to the last N lines. Zero returns all of them.
+	TailLines int
+	// Since skips the lines that were written before this time
+	Since time.Time
 }
 
 // RunCommandRequest encapsulates the parameters required to initiate a job execution.
@@ -71,6 +121,8 @@ type RunCommandRequest struct {
 	Inputs       []storage.PreparedStorage // Prepared storage elements that are used as inputs.
 	ResultsDir   string                    // Directory where results should be stored.
 	EngineParams *models.SpecConfig        // Engine-specific configuration parameters.
+	Env          map[string]string         // Environment variables of the task, set on top of the engine's own.
+	SecretFiles  map[string][]byte         // Contents of the secret files to inject in the execution, keyed by absolute path.
 	OutputLimits OutputLimits              // Output size limits for the execution.
 }
 
@@ -39,14 +39,36 @@ func (p *bidStrategyFromExecutor) ShouldBid(
 	ctx context.Context,
 	request bidstrategy.BidStrategyRequest,
 ) (bidstrategy.BidStrategyResponse, error) {
-	e, err := p.provider.Get(ctx, request.Job.Task().Engine.Type)
+	task := request.Job.Task()
+	e, err := p.provider.Get(ctx, task.Engine.Type)
 	if err != nil {
 		return bidstrategy.BidStrategyResponse{}, err
 	}
 
+	// the health check runs against the execution through the executor, so an executor that
+	// cannot run it would leave the execution without health checks
+	if task.HealthCheck != nil && !supportsHealthCheck(e, task.HealthCheck.Type) {
+		return bidstrategy.NewBidResponse(false, "run %s health checks with the %s engine",
+			task.HealthCheck.Type, task.Engine.Type), nil
+	}
+
 	return e.ShouldBid(ctx, request)
 }
 
+// supportsHealthCheck returns true if the executor can run health checks of the type
+func supportsHealthCheck(e executor.Executor, checkType string) bool {
+	switch checkType {
+	case models.HealthCheckHTTP, models.HealthCheckTCP:
+		_, ok := e.(executor.Addresser)
+		return ok
+	case models.HealthCheckCommand:
+		_, ok := e.(executor.Execer)
+		return ok
+	default:
+		return false
+	}
+}
+
 // ShouldBidBasedOnUsage implements bidstrategy.BidStrategy
 func (p *bidStrategyFromExecutor) ShouldBidBasedOnUsage(
 	ctx context.Context,
@@ -73,3 +73,31 @@ func TestExecutorsBidStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutorsBidStrategyUnsupportedHealthCheck(t *testing.T) {
+	// the noop executor can neither be reached over the network nor run commands
+	for _, checkType := range []string{models.HealthCheckHTTP, models.HealthCheckTCP, models.HealthCheckCommand} {
+		t.Run(checkType, func(t *testing.T) {
+			noop_provider := NewNoopExecutors(noop.ExecutorConfig{
+				ExternalHooks: noop.ExecutorConfigExternalHooks{
+					GetBidStrategy: func(ctx context.Context) (bidstrategy.BidStrategy, error) {
+						return &returnTrue, nil
+					},
+				},
+			})
+			strategy := NewExecutorSpecificBidStrategy(noop_provider)
+			job := mock.Job()
+			job.Task().HealthCheck = &models.HealthCheck{
+				Type:    checkType,
+				Port:    8080,
+				Command: []string{"true"},
+			}
+			result, err := strategy.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{
+				Job: *job,
+			})
+			require.NoError(t, err)
+			require.False(t, result.ShouldBid)
+			require.Contains(t, result.Reason, checkType+" health checks")
+		})
+	}
+}
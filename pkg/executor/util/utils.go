@@ -2,10 +2,14 @@ package util
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/docker"
 	noop_executor "github.com/bacalhau-project/bacalhau/pkg/executor/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/oci"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/process"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
@@ -31,6 +35,11 @@ type StandardStorageProviderOptions struct {
 
 type StandardExecutorOptions struct {
 	DockerID string
+	// DockerRuntime selects how the jobs of the docker engine are run
+	DockerRuntime types.DockerRuntimeConfig
+	// ProcessExecutor configures the executor of the process engine, which is only
+	// provided when it is enabled
+	ProcessExecutor types.ProcessExecutorConfig
 }
 
 func NewStandardStorageProvider(
@@ -108,7 +117,7 @@ func NewStandardExecutorProvider(
 	cm *system.CleanupManager,
 	executorOptions StandardExecutorOptions,
 ) (executor.ExecutorProvider, error) {
-	dockerExecutor, err := docker.NewExecutor(ctx, executorOptions.DockerID)
+	dockerExecutor, err := newDockerExecutor(ctx, executorOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -118,10 +127,44 @@ func NewStandardExecutorProvider(
 		return nil, err
 	}
 
-	return provider.NewMappedProvider(map[string]executor.Executor{
+	executors := map[string]executor.Executor{
 		models.EngineDocker: dockerExecutor,
 		models.EngineWasm:   wasmExecutor,
-	}), nil
+	}
+
+	if executorOptions.ProcessExecutor.Enabled {
+		processExecutor, err := process.NewExecutor(ctx, process.ExecutorParams{
+			ID:              executorOptions.DockerID,
+			User:            executorOptions.ProcessExecutor.User,
+			AllowedCommands: executorOptions.ProcessExecutor.AllowedCommands,
+			Directory:       executorOptions.ProcessExecutor.Directory,
+			MaxProcesses:    executorOptions.ProcessExecutor.MaxProcesses,
+		})
+		if err != nil {
+			return nil, err
+		}
+		executors[models.EngineProcess] = processExecutor
+	}
+
+	return provider.NewMappedProvider(executors), nil
+}
+
+// newDockerExecutor returns the executor of the docker engine, which runs the jobs with the docker
+// daemon or directly with an OCI runtime depending on the configured runtime
+func newDockerExecutor(ctx context.Context, executorOptions StandardExecutorOptions) (executor.Executor, error) {
+	switch executorOptions.DockerRuntime.Type {
+	case "", types.DockerRuntimeDocker:
+		return docker.NewExecutor(ctx, executorOptions.DockerID)
+	case types.DockerRuntimeOCI:
+		return oci.NewExecutor(ctx, oci.ExecutorParams{
+			ID:        executorOptions.DockerID,
+			Runtime:   executorOptions.DockerRuntime.OCI.Runtime,
+			Directory: executorOptions.DockerRuntime.OCI.Directory,
+		})
+	default:
+		return nil, fmt.Errorf("unknown docker runtime %q, expected %s or %s",
+			executorOptions.DockerRuntime.Type, types.DockerRuntimeDocker, types.DockerRuntimeOCI)
+	}
 }
 
 // return noop executors for all engines
@@ -2,9 +2,11 @@ package wasm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 
@@ -44,6 +46,15 @@ func (e *Executor) IsInstalled(context.Context) (bool, error) {
 }
 
 func (*Executor) ShouldBid(ctx context.Context, request bidstrategy.BidStrategyRequest) (bidstrategy.BidStrategyResponse, error) {
+	task := request.Job.Task()
+	if task.HasPorts() {
+		return bidstrategy.NewBidResponse(false, "publish ports with WASM jobs"), nil
+	}
+	// modules only have access to WASI preview 1, which has no sockets, so the network
+	// of the task could not be provided nor its allowed domains enforced
+	if network := task.Network; network != nil && !network.Disabled() {
+		return bidstrategy.NewBidResponse(false, "support networking with WASM jobs"), nil
+	}
 	return bidstrategy.NewBidResponse(true, "not place additional requirements on WASM jobs"), nil
 }
 
@@ -95,11 +106,24 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 	if err != nil {
 		return fmt.Errorf("decoding wasm arguments: %w", err)
 	}
+	if len(request.Env) > 0 {
+		// variables of the task take precedence over the ones of the engine
+		env := make(map[string]string, len(engineParams.EnvironmentVariables)+len(request.Env))
+		maps.Copy(env, engineParams.EnvironmentVariables)
+		maps.Copy(env, request.Env)
+		engineParams.EnvironmentVariables = env
+	}
 
 	rootFs, err := e.makeFsFromStorage(ctx, request.ResultsDir, request.Inputs, request.Outputs)
 	if err != nil {
 		return err
 	}
+	// secret files are served from memory, so that they are never written to the disk of the compute node
+	for path, content := range request.SecretFiles {
+		if err = rootFs.Mount(path, filefs.NewFromData(path, content)); err != nil {
+			return fmt.Errorf("mounting secret file %s: %w", path, err)
+		}
+	}
 
 	// Create a new log manager and obtain some writers that we can pass to the wasm
 	// configuration
@@ -236,7 +260,7 @@ func (e *Executor) makeFsFromStorage(
 	ctx context.Context,
 	jobResultsDir string,
 	volumes []storage.PreparedStorage,
-	outputs []*models.ResultPath) (fs.FS, error) {
+	outputs []*models.ResultPath) (*mountfs.MountDir, error) {
 	var err error
 	rootFs := mountfs.New()
 
@@ -280,8 +304,9 @@ func (e *Executor) makeFsFromStorage(
 			Str("dir", srcd).
 			Msg("Collecting output")
 
+		// the output already exists if the compute node restored a checkpoint into it
 		err = os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W)
-		if err != nil {
+		if err != nil && !errors.Is(err, fs.ErrExist) {
 			return nil, err
 		}
 
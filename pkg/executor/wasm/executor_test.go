@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -51,3 +52,27 @@ func (s *ExecutorTestSuite) TestFailingRequestedMemGreaterThan4GB() {
 
 	assert.Contains(s.T(), err.Error(), "requested memory exceeds the wasm limit")
 }
+
+func (s *ExecutorTestSuite) TestShouldBid() {
+	e, err := NewExecutor()
+	s.Require().NoError(err)
+
+	for _, tc := range []struct {
+		network   *models.NetworkConfig
+		shouldBid bool
+	}{
+		{network: nil, shouldBid: true},
+		{network: &models.NetworkConfig{Type: models.NetworkNone}, shouldBid: true},
+		{network: &models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"example.com"}}, shouldBid: false},
+		{network: &models.NetworkConfig{Type: models.NetworkFull}, shouldBid: false},
+		{
+			network:   &models.NetworkConfig{Type: models.NetworkFull, Ports: []*models.Port{{Name: "http", Target: 8080}}},
+			shouldBid: false,
+		},
+	} {
+		job := models.Job{Tasks: []*models.Task{{Name: "task", Network: tc.network}}}
+		response, err := e.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{Job: job})
+		s.Require().NoError(err)
+		s.Equal(tc.shouldBid, response.ShouldBid, response.Reason)
+	}
+}
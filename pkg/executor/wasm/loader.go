@@ -1,7 +1,9 @@
 package wasm
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -51,12 +53,15 @@ func (loader *ModuleLoader) Load(ctx context.Context, path string) (wazero.Compi
 	defer span.End()
 
 	log.Ctx(ctx).Debug().Str("Path", path).Msg("Loading WASM module")
-	bytes, err := os.ReadFile(path)
+	binary, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	if isComponent(binary) {
+		return nil, fmt.Errorf("%s: %w", path, ErrComponentNotSupported)
+	}
 
-	module, err := loader.runtime.CompileModule(ctx, bytes)
+	module, err := loader.runtime.CompileModule(ctx, binary)
 	if err != nil {
 		return nil, err
 	}
@@ -185,3 +190,23 @@ func (loader *ModuleLoader) loadModuleByName(ctx context.Context, moduleName str
 
 	return nil, fmt.Errorf(unknownModuleErrStr, moduleName, wasi_snapshot_preview1.ModuleName)
 }
+
+// ErrComponentNotSupported is returned when loading a WebAssembly component,
+// e.g. compiled for wasm32-wasip2, as the runtime only runs core modules.
+var ErrComponentNotSupported = errors.New("WebAssembly components are not supported, " +
+	"only core modules are: compile the program for a WASI preview 1 target such as wasm32-wasip1")
+
+// A WebAssembly binary starts with the magic bytes followed by its version and
+// layer. Core modules are layer 0, whereas components are layer 1.
+var (
+	wasmMagic      = []byte{0x00, 0x61, 0x73, 0x6d}
+	componentLayer = []byte{0x01, 0x00}
+)
+
+// isComponent returns whether the passed binary is a WebAssembly component
+// rather than a core module.
+func isComponent(binary []byte) bool {
+	return len(binary) >= 8 &&
+		bytes.Equal(binary[:4], wasmMagic) &&
+		bytes.Equal(binary[6:8], componentLayer)
+}
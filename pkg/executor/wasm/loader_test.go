@@ -31,6 +31,9 @@ func prepareModule(t *testing.T, alias string, program []byte) storage.PreparedS
 	}
 }
 
+// componentProgram is the preamble of an empty component.
+var componentProgram = []byte{0x00, 0x61, 0x73, 0x6d, 0x0d, 0x00, 0x01, 0x00}
+
 func TestModuleLoading(t *testing.T) {
 	logger.ConfigureTestLogging(t)
 
@@ -76,6 +79,15 @@ func TestModuleLoading(t *testing.T) {
 			errorChecker:  require.NoError,
 			moduleChecker: require.NotNil,
 		},
+		{
+			name:          "fails to load a component",
+			entryModule:   prepareModule(t, "", componentProgram),
+			importModules: []storage.PreparedStorage{},
+			errorChecker: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorIs(t, err, ErrComponentNotSupported)
+			},
+			moduleChecker: require.Nil,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -3,6 +3,7 @@ package boltjobstore
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -14,6 +15,10 @@ const (
 	DefaultDatabasePermissions   = 0600
 	DefaultBucketSearchSliceSize = 16
 	BucketPathDelimiter          = "/"
+
+	// compactTxMaxSize is the maximum size of the transactions used to copy the
+	// data while compacting a database
+	compactTxMaxSize = 64 * 1024 * 1024
 )
 
 func GetDatabase(path string) (*bolt.DB, error) {
@@ -25,6 +30,56 @@ func GetDatabase(path string) (*bolt.DB, error) {
 	return database, nil
 }
 
+// CompactDatabase rewrites the database at path into a new file that only holds the
+// data currently in use, and replaces the database with it. Bolt never shrinks its file,
+// so this is how the space freed by deleted data is given back to the file system.
+// The database must not be open. It returns the sizes of the file before and after
+// compaction, and does nothing if the database does not exist.
+func CompactDatabase(path string) (int64, int64, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+
+	src, err := GetDatabase(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+
+	compactPath := path + ".compact"
+	_ = os.Remove(compactPath)
+	dst, err := bolt.Open(compactPath, info.Mode().Perm(), &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to create compacted database")
+	}
+
+	if err = bolt.Compact(dst, src, compactTxMaxSize); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(compactPath)
+		return 0, 0, errors.Wrap(err, "failed to compact database")
+	}
+	if err = dst.Close(); err != nil {
+		_ = os.Remove(compactPath)
+		return 0, 0, err
+	}
+	if err = src.Close(); err != nil {
+		_ = os.Remove(compactPath)
+		return 0, 0, err
+	}
+
+	compactInfo, err := os.Stat(compactPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = os.Rename(compactPath, path); err != nil {
+		return 0, 0, errors.Wrap(err, "failed to replace database with compacted copy")
+	}
+	return info.Size(), compactInfo.Size(), nil
+}
+
 // GetBucketsByPrefix will search through the provided bucket to find other buckets with
 // a name that starts with the partialname that is provided.
 func GetBucketsByPrefix(tx *bolt.Tx, bucket *bolt.Bucket, partialName []byte) ([][]byte, error) {
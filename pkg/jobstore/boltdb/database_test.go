@@ -4,12 +4,17 @@ package boltjobstore
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
 	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 )
 
 type DatabaseTestSuite struct {
@@ -132,3 +137,38 @@ func (s *DatabaseTestSuite) TestBucketCreationError() {
 	})
 	s.NoError(err)
 }
+
+func (s *DatabaseTestSuite) TestCompactDatabase() {
+	// fill the database with data that is then mostly deleted
+	for i := 0; i < 100; i++ {
+		job := mock.Job()
+		job.ID = fmt.Sprintf("job-%03d", i)
+		job.Meta["padding"] = strings.Repeat("x", 10*1024)
+		s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	}
+	for i := 1; i < 100; i++ {
+		s.Require().NoError(s.store.DeleteJob(s.ctx, fmt.Sprintf("job-%03d", i)))
+	}
+	s.Require().NoError(s.store.Close(s.ctx))
+
+	before, after, err := CompactDatabase(s.dbFile)
+	s.Require().NoError(err)
+	s.Less(after, before)
+
+	info, err := os.Stat(s.dbFile)
+	s.Require().NoError(err)
+	s.Equal(after, info.Size())
+
+	// the data still in use is kept
+	s.store, err = NewBoltJobStore(s.dbFile)
+	s.Require().NoError(err)
+	_, err = s.store.GetJob(s.ctx, "job-000")
+	s.Require().NoError(err)
+}
+
+func (s *DatabaseTestSuite) TestCompactDatabaseMissing() {
+	before, after, err := CompactDatabase(filepath.Join(s.T().TempDir(), "missing.db"))
+	s.Require().NoError(err)
+	s.Zero(before)
+	s.Zero(after)
+}
@@ -3,6 +3,7 @@ package boltjobstore
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -21,7 +22,6 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 	bolt "go.etcd.io/bbolt"
-	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -30,16 +30,24 @@ const (
 	BucketJobEvaluations   = "evaluations"
 	BucketJobHistory       = "job_history"
 	BucketExecutionHistory = "execution_history"
+	BucketJobVersions      = "job_versions"
 
 	BucketTagsIndex        = "idx_tags"        // tag -> Job id
 	BucketProgressIndex    = "idx_inprogress"  // job-id -> {}
 	BucketNamespacesIndex  = "idx_namespaces"  // namespace -> Job id
 	BucketExecutionsIndex  = "idx_executions"  // execution-id -> Job id
 	BucketEvaluationsIndex = "idx_evaluations" // evaluation-id -> Job id
+	BucketStatesIndex      = "idx_states"      // job state -> Job id
+	BucketTypesIndex       = "idx_types"       // job type -> Job id
+	BucketCreateTimeIndex  = "idx_createtime"  // create-time + Job id -> {}
+	BucketDependsOnIndex   = "idx_dependson"   // upstream Job id -> Job id
 )
 
 var SpecKey = []byte("spec")
 
+// createTimeKeyLength is the length of the create time prefix of the create time index keys
+const createTimeKeyLength = 8
+
 type BoltJobStore struct {
 	database    *bolt.DB
 	clock       clock.Clock
@@ -52,6 +60,10 @@ type BoltJobStore struct {
 	tagsIndex        *Index
 	executionsIndex  *Index
 	evaluationsIndex *Index
+	statesIndex      *Index
+	typesIndex       *Index
+	createTimeIndex  *Index
+	dependsOnIndex   *Index
 }
 
 type Option func(store *BoltJobStore)
@@ -83,6 +95,10 @@ func WithClock(clock clock.Clock) Option {
 //	NamespacesIndex  = namespace -> Job id
 //	ExecutionsIndex  = execution-id -> Job id
 //	EvaluationsIndex = evaluation-id -> Job id
+//	StatesIndex      = job state -> Job id
+//	TypesIndex       = job type -> Job id
+//	CreateTimeIndex  = create-time + job-id -> {}, ordered by create time
+//	DependsOnIndex   = upstream job id -> Job id
 func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 	db, err := GetDatabase(dbPath)
 	if err != nil {
@@ -100,9 +116,22 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 		opt(store)
 	}
 
+	store.inProgressIndex = NewIndex(BucketProgressIndex)
+	store.namespacesIndex = NewIndex(BucketNamespacesIndex)
+	store.tagsIndex = NewIndex(BucketTagsIndex)
+	store.executionsIndex = NewIndex(BucketExecutionsIndex)
+	store.evaluationsIndex = NewIndex(BucketEvaluationsIndex)
+	store.statesIndex = NewIndex(BucketStatesIndex)
+	store.typesIndex = NewIndex(BucketTypesIndex)
+	store.createTimeIndex = NewIndex(BucketCreateTimeIndex)
+	store.dependsOnIndex = NewIndex(BucketDependsOnIndex)
+
 	// Create the top level buckets ready for use as they
 	// will definitely be required
 	err = db.Update(func(tx *bolt.Tx) (err error) {
+		// Databases created before the listing indexes existed need their jobs indexed
+		reindex := tx.Bucket([]byte(BucketCreateTimeIndex)) == nil || tx.Bucket([]byte(BucketDependsOnIndex)) == nil
+
 		// Create the top level jobs bucket, and the
 		_, err = tx.CreateBucketIfNotExists([]byte(BucketJobs))
 		if err != nil {
@@ -115,6 +144,10 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 			BucketNamespacesIndex,
 			BucketExecutionsIndex,
 			BucketEvaluationsIndex,
+			BucketStatesIndex,
+			BucketTypesIndex,
+			BucketCreateTimeIndex,
+			BucketDependsOnIndex,
 		}
 		for _, ib := range indexBuckets {
 			_, err = tx.CreateBucketIfNotExists([]byte(ib))
@@ -123,18 +156,101 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 			}
 		}
 
+		if reindex {
+			return store.reindexJobs(tx)
+		}
 		return nil
 	})
 
-	store.inProgressIndex = NewIndex(BucketProgressIndex)
-	store.namespacesIndex = NewIndex(BucketNamespacesIndex)
-	store.tagsIndex = NewIndex(BucketTagsIndex)
-	store.executionsIndex = NewIndex(BucketExecutionsIndex)
-	store.evaluationsIndex = NewIndex(BucketEvaluationsIndex)
-
 	return store, err
 }
 
+// reindexJobs adds all the jobs to the listing indexes
+func (b *BoltJobStore) reindexJobs(tx *bolt.Tx) error {
+	bkt, err := NewBucketPath(BucketJobs).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return bkt.ForEachBucket(func(k []byte) error {
+		job, err := b.getJob(tx, string(k))
+		if err != nil {
+			return err
+		}
+		return b.addJobToListingIndexes(tx, &job)
+	})
+}
+
+// addJobToListingIndexes adds the job to the indexes used to filter and sort jobs when listing them
+func (b *BoltJobStore) addJobToListingIndexes(tx *bolt.Tx, job *models.Job) error {
+	jobIDKey := []byte(job.ID)
+	if err := b.statesIndex.Add(tx, jobIDKey, []byte(job.State.StateType.String())); err != nil {
+		return err
+	}
+	if err := b.typesIndex.Add(tx, jobIDKey, []byte(job.Type)); err != nil {
+		return err
+	}
+	if err := b.addJobDependencies(tx, job); err != nil {
+		return err
+	}
+	return b.createTimeIndex.Add(tx, createTimeIndexKey(job.CreateTime, job.ID))
+}
+
+// removeJobFromListingIndexes removes the job from the indexes used when listing jobs
+func (b *BoltJobStore) removeJobFromListingIndexes(tx *bolt.Tx, job *models.Job) error {
+	jobIDKey := []byte(job.ID)
+	if err := b.statesIndex.Remove(tx, jobIDKey, []byte(job.State.StateType.String())); err != nil {
+		return err
+	}
+	if err := b.typesIndex.Remove(tx, jobIDKey, []byte(job.Type)); err != nil {
+		return err
+	}
+	if err := b.removeJobDependencies(tx, job); err != nil {
+		return err
+	}
+	return b.createTimeIndex.Remove(tx, createTimeIndexKey(job.CreateTime, job.ID))
+}
+
+// addJobDependencies indexes the job under each of the upstream jobs it depends on
+func (b *BoltJobStore) addJobDependencies(tx *bolt.Tx, job *models.Job) error {
+	for _, dep := range job.Dependencies {
+		if dep == nil || dep.JobID == "" {
+			continue
+		}
+		if err := b.dependsOnIndex.Add(tx, []byte(job.ID), []byte(dep.JobID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeJobDependencies removes the job from the index of the upstream jobs it depends on
+func (b *BoltJobStore) removeJobDependencies(tx *bolt.Tx, job *models.Job) error {
+	for _, dep := range job.Dependencies {
+		if dep == nil || dep.JobID == "" {
+			continue
+		}
+		if err := b.dependsOnIndex.Remove(tx, []byte(job.ID), []byte(dep.JobID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTimeIndexKey returns the key of the job in the create time index. Keys start with the
+// big endian create time so that bolt keeps them ordered by create time, and then by job ID.
+func createTimeIndexKey(createTime int64, jobID string) []byte {
+	key := make([]byte, createTimeKeyLength+len(jobID))
+	binary.BigEndian.PutUint64(key, uint64(createTime))
+	copy(key[createTimeKeyLength:], jobID)
+	return key
+}
+
+// Database returns the underlying bolt database, allowing other components of the
+// orchestrator to persist their state in the same file as the jobs.
+func (b *BoltJobStore) Database() *bolt.DB {
+	return b.database
+}
+
 func (b *BoltJobStore) Watch(ctx context.Context,
 	types jobstore.StoreWatcherType,
 	events jobstore.StoreEventType) chan jobstore.WatchEvent {
@@ -310,60 +426,36 @@ func (b *BoltJobStore) GetJobs(ctx context.Context, query jobstore.JobQuery) (*j
 }
 
 func (b *BoltJobStore) getJobs(tx *bolt.Tx, query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
-	jobSet, err := b.getJobsInitialSet(tx, query)
-	if err != nil {
-		return nil, err
-	}
-
-	jobSet, err = b.getJobsIncludeTags(tx, jobSet, query.IncludeTags)
+	candidates, err := b.getJobsCandidates(tx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	jobSet, err = b.getJobsExcludeTags(tx, jobSet, query.ExcludeTags)
+	excluded, err := b.getJobsExcludeTags(tx, query.ExcludeTags)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := b.getJobsBuildList(tx, jobSet, query)
-	if err != nil {
-		return nil, err
-	}
-
-	// Sort the jobs according to the query.SortBy and query.SortOrder
-	listSorter := func(i, j int) bool {
-		switch query.SortBy {
-		case "modified_at":
-			if query.SortReverse {
-				return result[i].ModifyTime > result[j].ModifyTime
-			} else {
-				return result[i].ModifyTime < result[j].ModifyTime
-			}
-		default:
-			// We apply created_at as a default sort so that we can use it for pagination.
-			// Without a known default we won't have a stable sort that makes sense for
-			// offsets/limits.
-			if query.SortReverse {
-				return result[i].CreateTime > result[j].CreateTime
-			} else {
-				return result[i].CreateTime < result[j].CreateTime
+	isCandidate := func(jobID string) bool {
+		if candidates != nil {
+			if _, ok := candidates[jobID]; !ok {
+				return false
 			}
 		}
+		_, isExcluded := excluded[jobID]
+		return !isExcluded
 	}
-	sort.Slice(result, listSorter)
 
-	// If we have a selector, filter the results to only those that match
-	if query.Selector != nil {
-		var filtered []models.Job
-		for _, job := range result {
-			if query.Selector.Matches(labels.Set(job.Labels)) {
-				filtered = append(filtered, job)
-			}
-		}
-		result = filtered
+	var jobs []models.Job
+	var more bool
+	if query.SortField() == jobstore.SortByCreatedAt {
+		jobs, more, err = b.getJobsByCreateTime(tx, query, isCandidate)
+	} else {
+		jobs, more, err = b.getJobsSorted(tx, query, isCandidate)
+	}
+	if err != nil {
+		return nil, err
 	}
-
-	jobs, more := b.getJobsWithinLimit(result, query)
 
 	response := &jobstore.JobQueryResponse{
 		Jobs:   jobs,
@@ -374,49 +466,69 @@ func (b *BoltJobStore) getJobs(tx *bolt.Tx, query jobstore.JobQuery) (*jobstore.
 	// If we don't have 'limit' jobs, then there definitely aren't any more
 	if more {
 		response.NextOffset = query.Offset + query.Limit
+		response.NextCursor = query.NewJobCursor(&jobs[len(jobs)-1])
 	}
 
 	return response, nil
 }
 
-// getJobsWithinLimit returns the initial set of jobs to be considered for GetJobs response.
-// It either returns all jobs, or jobs for a specific client if specified in the query.
-func (b *BoltJobStore) getJobsInitialSet(tx *bolt.Tx, query jobstore.JobQuery) (map[string]struct{}, error) {
-	jobSet := make(map[string]struct{})
+// getJobsCandidates returns the IDs of the jobs that match the namespace, states, types, included
+// tags and upstream job of the query using the indexes, or nil if the query does not filter on any of them.
+func (b *BoltJobStore) getJobsCandidates(tx *bolt.Tx, query jobstore.JobQuery) (map[string]struct{}, error) {
+	var candidates map[string]struct{}
 
-	if query.ReturnAll || query.Namespace == "" {
-		bkt, err := NewBucketPath(BucketJobs).Get(tx, false)
-		if err != nil {
-			return nil, err
+	// keep the jobs that are both in the candidates and in ANY of the labels of the index
+	intersect := func(index *Index, values []string) error {
+		matches := make(map[string]struct{})
+		for _, value := range values {
+			ids, err := index.List(tx, []byte(value))
+			if err != nil {
+				return err
+			}
+			for _, k := range ids {
+				if _, ok := candidates[string(k)]; candidates == nil || ok {
+					matches[string(k)] = struct{}{}
+				}
+			}
 		}
+		candidates = matches
+		return nil
+	}
 
-		err = bkt.ForEachBucket(func(k []byte) error {
-			jobSet[string(k)] = struct{}{}
-			return nil
-		})
-		if err != nil {
+	if !query.ReturnAll && query.Namespace != "" {
+		if err := intersect(b.namespacesIndex, []string{query.Namespace}); err != nil {
 			return nil, err
 		}
-	} else {
-		ids, err := b.namespacesIndex.List(tx, []byte(query.Namespace))
-		if err != nil {
+	}
+	if len(query.States) > 0 {
+		states := lo.Map(query.States, func(s models.JobStateType, _ int) string { return s.String() })
+		if err := intersect(b.statesIndex, states); err != nil {
 			return nil, err
 		}
-
-		for _, k := range ids {
-			jobSet[string(k)] = struct{}{}
+	}
+	if len(query.Types) > 0 {
+		if err := intersect(b.typesIndex, query.Types); err != nil {
+			return nil, err
+		}
+	}
+	if len(query.IncludeTags) > 0 {
+		tags := lo.Map(query.IncludeTags, func(t string, _ int) string { return strings.ToLower(t) })
+		if err := intersect(b.tagsIndex, tags); err != nil {
+			return nil, err
+		}
+	}
+	if query.DependsOn != "" {
+		if err := intersect(b.dependsOnIndex, []string{query.DependsOn}); err != nil {
+			return nil, err
 		}
 	}
 
-	return jobSet, nil
+	return candidates, nil
 }
 
-// getJobsIncludeTags filters out jobs that don't have ANY of the tags specified in the query.
-func (b *BoltJobStore) getJobsIncludeTags(tx *bolt.Tx, jobSet map[string]struct{}, tags []string) (map[string]struct{}, error) {
-	if len(tags) == 0 {
-		return jobSet, nil
-	}
-	tagSet := make(map[string]struct{})
+// getJobsExcludeTags returns the IDs of the jobs that have ANY of the tags specified in the query.
+func (b *BoltJobStore) getJobsExcludeTags(tx *bolt.Tx, tags []string) (map[string]struct{}, error) {
+	excluded := make(map[string]struct{})
 	for _, tag := range tags {
 		tagLabel := []byte(strings.ToLower(tag))
 		ids, err := b.tagsIndex.List(tx, tagLabel)
@@ -425,60 +537,128 @@ func (b *BoltJobStore) getJobsIncludeTags(tx *bolt.Tx, jobSet map[string]struct{
 		}
 
 		for _, k := range ids {
-			tagSet[string(k)] = struct{}{}
+			excluded[string(k)] = struct{}{}
 		}
 	}
+	return excluded, nil
+}
 
-	// remove jobs that are not in the tag set
-	for k := range jobSet {
-		if _, ok := tagSet[k]; !ok {
-			delete(jobSet, k)
-		}
+// getJobsByCreateTime walks the create time index in the sort order of the query, starting
+// after the cursor and within the create time range of the query, and only loads the jobs
+// needed to fill the page.
+func (b *BoltJobStore) getJobsByCreateTime(
+	tx *bolt.Tx, query jobstore.JobQuery, isCandidate func(string) bool) ([]models.Job, bool, error) {
+	bkt, err := b.createTimeIndex.rootBucketPath.Get(tx, false)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return jobSet, nil
-}
+	// lower is inclusive and upper is exclusive
+	var lower, upper []byte
+	if !query.CreatedAfter.IsZero() {
+		lower = createTimeIndexKey(query.CreatedAfter.UnixNano(), "")
+	}
+	if !query.CreatedBefore.IsZero() {
+		upper = createTimeIndexKey(query.CreatedBefore.UnixNano(), "")
+	}
 
-// getJobsExcludeTags filters out jobs that have ANY of the tags specified in the query.
-func (b *BoltJobStore) getJobsExcludeTags(tx *bolt.Tx, jobSet map[string]struct{}, tags []string) (map[string]struct{}, error) {
-	if len(tags) == 0 {
-		return jobSet, nil
+	c := bkt.Cursor()
+	var k []byte
+	var next func() ([]byte, []byte)
+	if query.SortReverse {
+		// the cursor is an exclusive upper bound when walking backwards
+		if query.Cursor != nil {
+			cursorKey := createTimeIndexKey(query.Cursor.SortValue, query.Cursor.ID)
+			if upper == nil || bytes.Compare(cursorKey, upper) < 0 {
+				upper = cursorKey
+			}
+		}
+		if upper == nil {
+			k, _ = c.Last()
+		} else if k, _ = c.Seek(upper); k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+		next = c.Prev
+	} else {
+		start := lower
+		if query.Cursor != nil {
+			cursorKey := createTimeIndexKey(query.Cursor.SortValue, query.Cursor.ID)
+			if start == nil || bytes.Compare(cursorKey, start) >= 0 {
+				start = cursorKey
+			}
+		}
+		if start == nil {
+			k, _ = c.First()
+		} else {
+			k, _ = c.Seek(start)
+		}
+		next = c.Next
 	}
 
-	for _, tag := range tags {
-		tagLabel := []byte(strings.ToLower(tag))
-		ids, err := b.tagsIndex.List(tx, tagLabel)
+	jobs := make([]models.Job, 0)
+	skipped := uint32(0)
+	for ; k != nil; k, _ = next() {
+		if (lower != nil && bytes.Compare(k, lower) < 0) || (upper != nil && bytes.Compare(k, upper) >= 0) {
+			break
+		}
+		jobID := string(k[createTimeKeyLength:])
+		if !isCandidate(jobID) {
+			continue
+		}
+		job, err := b.getJob(tx, jobID)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-
-		for _, k := range ids {
-			delete(jobSet, string(k))
+		if !query.Matches(&job) || !query.IsAfterCursor(&job) {
+			continue
 		}
+		if skipped < query.Offset {
+			skipped++
+			continue
+		}
+		if query.Limit > 0 && uint32(len(jobs)) == query.Limit {
+			return jobs, true, nil
+		}
+		jobs = append(jobs, job)
 	}
-
-	return jobSet, nil
+	return jobs, false, nil
 }
 
-func (b *BoltJobStore) getJobsBuildList(tx *bolt.Tx, jobSet map[string]struct{}, query jobstore.JobQuery) ([]models.Job, error) {
-	var result []models.Job
+// getJobsSorted loads all the candidate jobs and sorts them in memory, for the sort fields
+// that are not indexed.
+func (b *BoltJobStore) getJobsSorted(
+	tx *bolt.Tx, query jobstore.JobQuery, isCandidate func(string) bool) ([]models.Job, bool, error) {
+	bkt, err := NewBucketPath(BucketJobs).Get(tx, false)
+	if err != nil {
+		return nil, false, err
+	}
 
-	for key := range jobSet {
+	var result []models.Job
+	err = bkt.ForEachBucket(func(k []byte) error {
+		if !isCandidate(string(k)) {
+			return nil
+		}
 		var job models.Job
-
-		path := NewBucketPath(BucketJobs, key)
-		data := GetBucketData(tx, path, SpecKey)
-		err := b.marshaller.Unmarshal(data, &job)
-		if err != nil {
-			return nil, err
+		if err := b.marshaller.Unmarshal(GetBucketData(tx, NewBucketPath(BucketJobs, string(k)), SpecKey), &job); err != nil {
+			return err
 		}
-		result = append(result, job)
+		if query.Matches(&job) && query.IsAfterCursor(&job) {
+			result = append(result, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
-	listSorter := b.getListSorter(result, query)
-	sort.Slice(result, listSorter)
+	sort.Slice(result, func(i, j int) bool {
+		return query.Less(&result[i], &result[j])
+	})
 
-	return result, nil
+	jobs, more := b.getJobsWithinLimit(result, query)
+	return jobs, more, nil
 }
 
 func (b *BoltJobStore) getJobsWithinLimit(jobs []models.Job, query jobstore.JobQuery) ([]models.Job, bool) {
@@ -499,28 +679,6 @@ func (b *BoltJobStore) getJobsWithinLimit(jobs []models.Job, query jobstore.JobQ
 	return jobsFiltered, filteredLength > query.Limit
 }
 
-func (b *BoltJobStore) getListSorter(jobs []models.Job, query jobstore.JobQuery) func(i, j int) bool {
-	return func(i, j int) bool {
-		switch query.SortBy {
-		case "id":
-			if query.SortReverse {
-				// what does it mean to sort by ID?
-				return jobs[i].ID > jobs[j].ID
-			} else {
-				return jobs[i].ID < jobs[j].ID
-			}
-		case "created_at":
-			if query.SortReverse {
-				return jobs[i].CreateTime > jobs[j].CreateTime
-			} else {
-				return jobs[i].CreateTime < jobs[j].CreateTime
-			}
-		default:
-			return false
-		}
-	}
-}
-
 // GetExecutions returns the current job state for the provided job id
 func (b *BoltJobStore) GetExecutions(ctx context.Context, options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
 	var state []models.Execution
@@ -683,6 +841,7 @@ func (b *BoltJobStore) getJobHistory(tx *bolt.Tx, jobID string,
 func (b *BoltJobStore) CreateJob(ctx context.Context, job models.Job, event models.Event) error {
 	job.State = models.NewJobState(models.JobStateTypePending)
 	job.Revision = 1
+	job.Version = 1
 	job.CreateTime = b.clock.Now().UTC().UnixNano()
 	job.ModifyTime = b.clock.Now().UTC().UnixNano()
 	job.Normalize()
@@ -756,9 +915,132 @@ func (b *BoltJobStore) createJob(tx *bolt.Tx, job models.Job, event models.Event
 		}
 	}
 
+	if err = b.addJobToListingIndexes(tx, &job); err != nil {
+		return err
+	}
+
 	return b.appendJobHistory(tx, job, models.JobStateTypePending, event)
 }
 
+// UpdateJob updates the specification of an existing job as a new version of the job,
+// keeping the previous specification as a previous version
+func (b *BoltJobStore) UpdateJob(ctx context.Context, job models.Job, event models.Event) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		return b.updateJob(tx, job, event)
+	})
+}
+
+func (b *BoltJobStore) updateJob(tx *bolt.Tx, job models.Job, event models.Event) error {
+	existing, err := b.getJob(tx, job.ID)
+	if err != nil {
+		return err
+	}
+	if existing.IsTerminal() {
+		return jobstore.NewErrInvalidJobState(existing.ID, existing.State.StateType, models.JobStateTypeUndefined)
+	}
+	if job.Type != existing.Type {
+		return fmt.Errorf("cannot change the type of job %s from %s to %s", existing.ID, existing.Type, job.Type)
+	}
+
+	// jobs created before versions were tracked are at their first version
+	if existing.Version == 0 {
+		existing.Version = 1
+	}
+
+	job.ID = existing.ID
+	job.Namespace = existing.Namespace
+	job.State = existing.State
+	job.Version = existing.Version + 1
+	job.Revision = existing.Revision + 1
+	job.CreateTime = existing.CreateTime
+	job.ModifyTime = b.clock.Now().UTC().UnixNano()
+	job.Normalize()
+	if err = job.Validate(); err != nil {
+		return err
+	}
+
+	tx.OnCommit(func() {
+		b.triggerEvent(jobstore.JobWatcher, jobstore.UpdateEvent, job)
+	})
+
+	// keep the previous specification as a previous version
+	existingData, err := b.marshaller.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	if bkt, err := NewBucketPath(BucketJobs, job.ID, BucketJobVersions).Get(tx, true); err != nil {
+		return err
+	} else if err = bkt.Put(jobVersionKey(existing.Version), existingData); err != nil {
+		return err
+	}
+
+	jobData, err := b.marshaller.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if bkt, err := NewBucketPath(BucketJobs, job.ID).Get(tx, false); err != nil {
+		return err
+	} else if err = bkt.Put(SpecKey, jobData); err != nil {
+		return err
+	}
+
+	// Re-write sentinels keys for specific tags
+	jobIDKey := []byte(job.ID)
+	for tag := range existing.Labels {
+		if err = b.tagsIndex.Remove(tx, jobIDKey, []byte(strings.ToLower(tag))); err != nil {
+			return err
+		}
+	}
+	for tag := range job.Labels {
+		if err = b.tagsIndex.Add(tx, jobIDKey, []byte(strings.ToLower(tag))); err != nil {
+			return err
+		}
+	}
+
+	// Re-index the dependencies, which may change with the specification
+	if err = b.removeJobDependencies(tx, &existing); err != nil {
+		return err
+	}
+	if err = b.addJobDependencies(tx, &job); err != nil {
+		return err
+	}
+
+	return b.appendJobHistory(tx, job, existing.State.StateType, event)
+}
+
+// GetJobVersion returns the specification of a job at the given version
+func (b *BoltJobStore) GetJobVersion(ctx context.Context, id string, version uint64) (models.Job, error) {
+	var job models.Job
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		job, err = b.getJobVersion(tx, id, version)
+		return
+	})
+	return job, err
+}
+
+func (b *BoltJobStore) getJobVersion(tx *bolt.Tx, id string, version uint64) (models.Job, error) {
+	job, err := b.getJob(tx, id)
+	if err != nil {
+		return job, err
+	}
+	if job.Version == version || (job.Version == 0 && version == 1) {
+		return job, nil
+	}
+
+	data := GetBucketData(tx, NewBucketPath(BucketJobs, job.ID, BucketJobVersions), jobVersionKey(version))
+	if data == nil {
+		return models.Job{}, jobstore.NewErrJobVersionNotFound(job.ID, version)
+	}
+	var previous models.Job
+	err = b.marshaller.Unmarshal(data, &previous)
+	return previous, err
+}
+
+// jobVersionKey returns the key of a version of a job, padded so that bolt keeps versions in order
+func jobVersionKey(version uint64) []byte {
+	return []byte(fmt.Sprintf("%016d", version))
+}
+
 // DeleteJob removes the specified job from the system entirely
 func (b *BoltJobStore) DeleteJob(ctx context.Context, jobID string) error {
 	return b.database.Update(func(tx *bolt.Tx) (err error) {
@@ -808,7 +1090,7 @@ func (b *BoltJobStore) deleteJob(tx *bolt.Tx, jobID string) error {
 		}
 	}
 
-	return nil
+	return b.removeJobFromListingIndexes(tx, &job)
 }
 
 // UpdateJobState updates the current state for a single Job, appending an entry to
@@ -846,6 +1128,12 @@ func (b *BoltJobStore) updateJobState(tx *bolt.Tx, request jobstore.UpdateJobSta
 
 	// update the job state
 	previousState := job.State.StateType
+	if err = b.statesIndex.Remove(tx, []byte(job.ID), []byte(previousState.String())); err != nil {
+		return err
+	}
+	if err = b.statesIndex.Add(tx, []byte(job.ID), []byte(request.NewState.String())); err != nil {
+		return err
+	}
 	job.State.StateType = request.NewState
 	job.State.Message = request.Event.Message
 	job.Revision++
@@ -65,6 +65,20 @@ func (e ErrInvalidJobVersion) Error() string {
 	return fmt.Sprintf("job %s has version %d but expected %d", e.JobID, e.Actual, e.Expected)
 }
 
+// ErrJobVersionNotFound is returned when a version of a job is not found
+type ErrJobVersionNotFound struct {
+	JobID   string
+	Version uint64
+}
+
+func NewErrJobVersionNotFound(id string, version uint64) ErrJobVersionNotFound {
+	return ErrJobVersionNotFound{JobID: id, Version: version}
+}
+
+func (e ErrJobVersionNotFound) Error() string {
+	return fmt.Sprintf("version %d of job %s not found", e.Version, e.JobID)
+}
+
 // ErrJobAlreadyTerminal is returned when an job is already in terminal state and cannot be updated.
 type ErrJobAlreadyTerminal struct {
 	JobID    string
@@ -199,6 +199,21 @@ func (mr *MockStoreMockRecorder) GetJobHistory(ctx, jobID, options any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobHistory", reflect.TypeOf((*MockStore)(nil).GetJobHistory), ctx, jobID, options)
 }
 
+// GetJobVersion mocks base method.
+func (m *MockStore) GetJobVersion(ctx context.Context, id string, version uint64) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobVersion", ctx, id, version)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobVersion indicates an expected call of GetJobVersion.
+func (mr *MockStoreMockRecorder) GetJobVersion(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobVersion", reflect.TypeOf((*MockStore)(nil).GetJobVersion), ctx, id, version)
+}
+
 // GetJobs mocks base method.
 func (m *MockStore) GetJobs(ctx context.Context, query JobQuery) (*JobQueryResponse, error) {
 	m.ctrl.T.Helper()
@@ -228,6 +243,20 @@ func (mr *MockStoreMockRecorder) UpdateExecution(ctx, request any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExecution", reflect.TypeOf((*MockStore)(nil).UpdateExecution), ctx, request)
 }
 
+// UpdateJob mocks base method.
+func (m *MockStore) UpdateJob(ctx context.Context, j models.Job, event models.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, j, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockStoreMockRecorder) UpdateJob(ctx, j, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockStore)(nil).UpdateJob), ctx, j, event)
+}
+
 // UpdateJobState mocks base method.
 func (m *MockStore) UpdateJobState(ctx context.Context, request UpdateJobStateRequest) error {
 	m.ctrl.T.Helper()
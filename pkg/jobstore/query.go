@@ -0,0 +1,129 @@
+package jobstore
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	SortByID         = "id"
+	SortByCreatedAt  = "created_at"
+	SortByModifiedAt = "modified_at"
+)
+
+// JobCursor is the position of a job in the sort order of a JobQuery. Jobs are sorted by
+// the sort field of the query, and then by their ID so that the order is stable.
+type JobCursor struct {
+	// SortValue is the create or modify time of the job, depending on the sort field,
+	// and is not used when sorting by ID.
+	SortValue int64
+	ID        string
+}
+
+// NewJobCursor returns the position of the job in the sort order of the query
+func (q JobQuery) NewJobCursor(job *models.Job) *JobCursor {
+	return &JobCursor{SortValue: q.sortValue(job), ID: job.ID}
+}
+
+// SortField returns the sort field of the query. Jobs are sorted by create time by default,
+// as without a known default we won't have a stable sort that makes sense for pagination.
+func (q JobQuery) SortField() string {
+	switch q.SortBy {
+	case SortByID, SortByModifiedAt:
+		return q.SortBy
+	default:
+		return SortByCreatedAt
+	}
+}
+
+func (q JobQuery) sortValue(job *models.Job) int64 {
+	switch q.SortField() {
+	case SortByModifiedAt:
+		return job.ModifyTime
+	case SortByID:
+		return 0
+	default:
+		return job.CreateTime
+	}
+}
+
+// Less returns true if job a comes before job b in the sort order of the query
+func (q JobQuery) Less(a, b *models.Job) bool {
+	return q.compare(q.NewJobCursor(a), q.NewJobCursor(b)) < 0
+}
+
+// IsAfterCursor returns true if there is no cursor, or if the job comes after it
+// in the sort order of the query
+func (q JobQuery) IsAfterCursor(job *models.Job) bool {
+	return q.Cursor == nil || q.compare(q.NewJobCursor(job), q.Cursor) > 0
+}
+
+func (q JobQuery) compare(a, b *JobCursor) int {
+	c := 0
+	switch {
+	case a.SortValue < b.SortValue:
+		c = -1
+	case a.SortValue > b.SortValue:
+		c = 1
+	default:
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if q.SortReverse {
+		return -c
+	}
+	return c
+}
+
+// Matches returns true if the job passes all the filters of the query, excluding tags
+// which are not part of the job
+func (q JobQuery) Matches(job *models.Job) bool {
+	if !q.ReturnAll && q.Namespace != "" && job.Namespace != q.Namespace {
+		return false
+	}
+	if len(q.States) > 0 && !lo.Contains(q.States, job.State.StateType) {
+		return false
+	}
+	if len(q.Types) > 0 && !lo.Contains(q.Types, job.Type) {
+		return false
+	}
+	if q.DependsOn != "" && !job.DependsOn(q.DependsOn) {
+		return false
+	}
+	if !q.MatchesUnindexed(job) {
+		return false
+	}
+	if !q.CreatedAfter.IsZero() && job.CreateTime < q.CreatedAfter.UnixNano() {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && job.CreateTime >= q.CreatedBefore.UnixNano() {
+		return false
+	}
+	return true
+}
+
+// MatchesUnindexed returns true if the job passes the filters of the query that stores
+// cannot serve from an index: the label selector, engine types and name prefix.
+func (q JobQuery) MatchesUnindexed(job *models.Job) bool {
+	if q.Selector != nil && !q.Selector.Matches(labels.Set(job.Labels)) {
+		return false
+	}
+	if len(q.EngineTypes) > 0 {
+		if len(job.Tasks) == 0 || job.Task().Engine == nil ||
+			!lo.ContainsBy(q.EngineTypes, func(t string) bool { return strings.EqualFold(t, job.Task().Engine.Type) }) {
+			return false
+		}
+	}
+	if q.NamePrefix != "" && !strings.HasPrefix(job.Name, q.NamePrefix) {
+		return false
+	}
+	return true
+}
+
+// HasUnindexedFilters returns true if the query has filters that are matched by MatchesUnindexed
+func (q JobQuery) HasUnindexedFilters() bool {
+	return q.Selector != nil || len(q.EngineTypes) > 0 || q.NamePrefix != ""
+}
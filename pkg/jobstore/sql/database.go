@@ -0,0 +1,81 @@
+package sqljobstore
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Database is the database of a SQLJobStore, which the other stores of the requester use
+// to persist their state next to the jobs. Queries are written with ? placeholders, which
+// are rewritten into the placeholders of the database. The stores create their own tables,
+// which are not part of the versioned schema of the jobstore.
+type Database struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// Database returns the database of the jobstore
+func (s *SQLJobStore) Database() *Database {
+	return &Database{db: s.database, dialect: s.dialect}
+}
+
+// JSONType returns the column type used to store JSON documents
+func (d *Database) JSONType() string {
+	return d.dialect.jsonType
+}
+
+// SerialKey returns the column definition of an auto-incremented primary key
+func (d *Database) SerialKey() string {
+	return d.dialect.serialKey
+}
+
+func (d *Database) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.db.ExecContext(ctx, d.dialect.rebind(query), args...)
+}
+
+func (d *Database) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return d.db.QueryContext(ctx, d.dialect.rebind(query), args...)
+}
+
+func (d *Database) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return d.db.QueryRowContext(ctx, d.dialect.rebind(query), args...)
+}
+
+// Update runs f in a transaction, which is committed if f succeeds and rolled back otherwise.
+// The database must not be used outside of the transaction until f returns, as SQLite
+// databases only have a single connection.
+func (d *Database) Update(ctx context.Context, f func(tx *Tx) error) error {
+	sqlTx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = f(&Tx{tx: sqlTx, dialect: d.dialect}); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+	return sqlTx.Commit()
+}
+
+// Tx is a transaction of a Database
+type Tx struct {
+	tx      *sql.Tx
+	dialect dialect
+}
+
+func (t *Tx) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, t.dialect.rebind(query), args...)
+}
+
+func (t *Tx) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, t.dialect.rebind(query), args...)
+}
+
+func (t *Tx) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, t.dialect.rebind(query), args...)
+}
+
+// ForUpdate returns the query with the locking clause of the database appended, so that
+// the rows it reads cannot be changed by other transactions until this one completes.
+func (t *Tx) ForUpdate(query string) string {
+	return query + t.dialect.forUpdate
+}
@@ -0,0 +1,82 @@
+package sqljobstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DriverPostgres is the name of the PostgreSQL driver
+	DriverPostgres = "postgres"
+	// DriverSQLite is the name of the pure Go SQLite driver. The driver is not registered
+	// by this package, and callers need to import modernc.org/sqlite to use it.
+	DriverSQLite = "sqlite"
+)
+
+// dialect captures the differences between the SQL databases supported by the store
+type dialect struct {
+	// driver is the name of the database/sql driver
+	driver string
+	// numberedPlaceholders is true if the database uses $1, $2, ... instead of ?
+	numberedPlaceholders bool
+	// jsonType is the column type used to store JSON documents
+	jsonType string
+	// serialKey is the column definition of an auto-incremented primary key
+	serialKey string
+	// forUpdate is appended to queries that read rows that are about to be updated
+	forUpdate string
+	// maxOpenConns limits the number of open connections, zero means no limit
+	maxOpenConns int
+}
+
+var dialects = map[string]dialect{
+	DriverPostgres: {
+		driver:               DriverPostgres,
+		numberedPlaceholders: true,
+		jsonType:             "JSONB",
+		serialKey:            "BIGSERIAL PRIMARY KEY",
+		forUpdate:            " FOR UPDATE",
+	},
+	DriverSQLite: {
+		driver:    DriverSQLite,
+		jsonType:  "TEXT",
+		serialKey: "INTEGER PRIMARY KEY AUTOINCREMENT",
+		// SQLite only supports a single writer, and in-memory databases are private
+		// to the connection that created them.
+		maxOpenConns: 1,
+	},
+}
+
+func getDialect(driver string) (dialect, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return dialect{}, fmt.Errorf("unsupported jobstore SQL driver: %q", driver)
+	}
+	return d, nil
+}
+
+// rebind rewrites the ? placeholders of the query into the placeholders of the dialect
+func (d dialect) rebind(query string) string {
+	if !d.numberedPlaceholders {
+		return query
+	}
+	var sb strings.Builder
+	sb.Grow(len(query) + 10) //nolint:gomnd
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// placeholders returns a comma separated list of n placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,166 @@
+package sqljobstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is a versioned change of the schema. Migrations are applied in order,
+// and each one is applied exactly once in its own transaction.
+type migration struct {
+	version    int
+	statements func(d dialect) []string
+}
+
+// migrations must only ever be appended to, as released versions are recorded in
+// the schema_migrations table of existing databases.
+var migrations = []migration{
+	{
+		version: 1,
+		statements: func(d dialect) []string {
+			return []string{
+				`CREATE TABLE jobs (
+					id          TEXT PRIMARY KEY,
+					name        TEXT NOT NULL,
+					namespace   TEXT NOT NULL,
+					type        TEXT NOT NULL,
+					state       TEXT NOT NULL,
+					revision    BIGINT NOT NULL,
+					in_progress BOOLEAN NOT NULL,
+					create_time BIGINT NOT NULL,
+					modify_time BIGINT NOT NULL,
+					data        ` + d.jsonType + ` NOT NULL
+				)`,
+				`CREATE INDEX idx_jobs_namespace ON jobs (namespace)`,
+				`CREATE INDEX idx_jobs_in_progress ON jobs (in_progress, type)`,
+				`CREATE TABLE job_tags (
+					job_id TEXT NOT NULL REFERENCES jobs (id),
+					tag    TEXT NOT NULL,
+					PRIMARY KEY (job_id, tag)
+				)`,
+				`CREATE INDEX idx_job_tags_tag ON job_tags (tag)`,
+				`CREATE TABLE executions (
+					id            TEXT PRIMARY KEY,
+					job_id        TEXT NOT NULL REFERENCES jobs (id),
+					node_id       TEXT NOT NULL,
+					compute_state TEXT NOT NULL,
+					desired_state TEXT NOT NULL,
+					revision      BIGINT NOT NULL,
+					create_time   BIGINT NOT NULL,
+					modify_time   BIGINT NOT NULL,
+					data          ` + d.jsonType + ` NOT NULL
+				)`,
+				`CREATE INDEX idx_executions_job_id ON executions (job_id)`,
+				`CREATE TABLE job_history (
+					id     ` + d.serialKey + `,
+					job_id TEXT NOT NULL REFERENCES jobs (id),
+					time   BIGINT NOT NULL,
+					data   ` + d.jsonType + ` NOT NULL
+				)`,
+				`CREATE INDEX idx_job_history_job_id ON job_history (job_id)`,
+				`CREATE TABLE execution_history (
+					id           ` + d.serialKey + `,
+					job_id       TEXT NOT NULL REFERENCES jobs (id),
+					execution_id TEXT NOT NULL,
+					node_id      TEXT NOT NULL,
+					time         BIGINT NOT NULL,
+					data         ` + d.jsonType + ` NOT NULL
+				)`,
+				`CREATE INDEX idx_execution_history_job_id ON execution_history (job_id)`,
+				`CREATE TABLE evaluations (
+					id     TEXT PRIMARY KEY,
+					job_id TEXT NOT NULL REFERENCES jobs (id),
+					data   ` + d.jsonType + ` NOT NULL
+				)`,
+				`CREATE INDEX idx_evaluations_job_id ON evaluations (job_id)`,
+			}
+		},
+	},
+	{
+		version: 2,
+		statements: func(d dialect) []string {
+			return []string{
+				`CREATE INDEX idx_jobs_state ON jobs (state)`,
+				`CREATE INDEX idx_jobs_type ON jobs (type)`,
+				`CREATE INDEX idx_jobs_create_time ON jobs (create_time, id)`,
+				`CREATE INDEX idx_jobs_modify_time ON jobs (modify_time, id)`,
+			}
+		},
+	},
+	{
+		version: 3,
+		statements: func(d dialect) []string {
+			return []string{
+				`CREATE TABLE job_versions (
+					job_id  TEXT NOT NULL REFERENCES jobs (id),
+					version BIGINT NOT NULL,
+					data    ` + d.jsonType + ` NOT NULL,
+					PRIMARY KEY (job_id, version)
+				)`,
+			}
+		},
+	},
+	{
+		version: 4,
+		statements: func(d dialect) []string {
+			return []string{
+				`CREATE TABLE job_dependencies (
+					job_id      TEXT NOT NULL REFERENCES jobs (id),
+					upstream_id TEXT NOT NULL,
+					PRIMARY KEY (job_id, upstream_id)
+				)`,
+				`CREATE INDEX idx_job_dependencies_upstream_id ON job_dependencies (upstream_id)`,
+			}
+		},
+	},
+}
+
+// migrate creates the schema_migrations table if needed, and applies the migrations
+// that have not been applied to the database yet.
+func migrate(ctx context.Context, db *sql.DB, d dialect) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current int
+	row := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`)
+	if err = row.Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err = applyMigration(ctx, db, d, m); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, d dialect, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range m.statements(d) {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, d.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`),
+		m.version, time.Now().UTC().UnixNano())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
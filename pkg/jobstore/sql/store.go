@@ -0,0 +1,1130 @@
+package sqljobstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+	"github.com/benbjohnson/clock"
+	"github.com/imdario/mergo"
+	_ "github.com/lib/pq" // registers the postgres driver
+	"github.com/rs/zerolog/log"
+)
+
+type SQLJobStore struct {
+	database    *sql.DB
+	dialect     dialect
+	clock       clock.Clock
+	marshaller  marshaller.Marshaller
+	watchers    []*jobstore.Watcher
+	watcherLock sync.Mutex
+}
+
+type Option func(store *SQLJobStore)
+
+func WithClock(clock clock.Clock) Option {
+	return func(store *SQLJobStore) {
+		store.clock = clock
+	}
+}
+
+// NewSQLJobStore creates a new job store backed by a SQL database, and migrates the
+// schema of the database to the latest version. The driver is either DriverPostgres,
+// or DriverSQLite which is mostly useful for tests. The data is structured as follows:
+//
+//	jobs              -> one row per job, with its spec and state as JSON in data
+//	job_tags          -> (job_id, tag) for each lowercased label key of a job
+//	job_dependencies  -> (job_id, upstream_id) for each upstream job a job depends on
+//	executions        -> one row per execution, with the execution as JSON in data
+//	job_history       -> job level history entries, ordered by id
+//	execution_history -> execution level history entries, ordered by id
+//	evaluations       -> one row per evaluation, with the evaluation as JSON in data
+//
+// The columns next to the JSON documents duplicate the fields that are used for
+// filtering, so that the tables can also be queried directly.
+func NewSQLJobStore(ctx context.Context, driver string, dsn string, options ...Option) (*SQLJobStore, error) {
+	d, err := getDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if d.maxOpenConns > 0 {
+		db.SetMaxOpenConns(d.maxOpenConns)
+	}
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to the jobstore database: %w", err)
+	}
+	if err = migrate(ctx, db, d); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	store := &SQLJobStore{
+		database:   db,
+		dialect:    d,
+		clock:      clock.New(),
+		marshaller: marshaller.NewJSONMarshaller(),
+		watchers:   make([]*jobstore.Watcher, 0),
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+	return store, nil
+}
+
+// txn is a database transaction that rebinds the placeholders of its queries to
+// the dialect of the store, and runs callbacks after a successful commit.
+type txn struct {
+	tx       *sql.Tx
+	dialect  dialect
+	onCommit []func()
+}
+
+func (t *txn) OnCommit(f func()) {
+	t.onCommit = append(t.onCommit, f)
+}
+
+func (t *txn) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := t.tx.ExecContext(ctx, t.dialect.rebind(query), args...)
+	return err
+}
+
+func (t *txn) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, t.dialect.rebind(query), args...)
+}
+
+func (t *txn) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, t.dialect.rebind(query), args...)
+}
+
+// forUpdate returns the query with the locking clause of the dialect appended, so that
+// the rows it reads cannot be changed by other transactions until this one completes.
+func (t *txn) forUpdate(query string) string {
+	return query + t.dialect.forUpdate
+}
+
+// inTx runs f in a transaction, which is committed if f succeeds and rolled back otherwise
+func (s *SQLJobStore) inTx(ctx context.Context, f func(tx *txn) error) error {
+	sqlTx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	tx := &txn{tx: sqlTx, dialect: s.dialect}
+	if err = f(tx); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+	if err = sqlTx.Commit(); err != nil {
+		return err
+	}
+	for _, callback := range tx.onCommit {
+		callback()
+	}
+	return nil
+}
+
+func (s *SQLJobStore) Watch(ctx context.Context,
+	types jobstore.StoreWatcherType,
+	events jobstore.StoreEventType) chan jobstore.WatchEvent {
+	w := jobstore.NewWatcher(types, events)
+
+	s.watcherLock.Lock() // keep the watchers lock as narrow as possible
+	s.watchers = append(s.watchers, w)
+	s.watcherLock.Unlock()
+
+	return w.Channel()
+}
+
+func (s *SQLJobStore) triggerEvent(t jobstore.StoreWatcherType, e jobstore.StoreEventType, object interface{}) {
+	data, _ := json.Marshal(object)
+
+	s.watcherLock.Lock()
+	defer s.watcherLock.Unlock()
+	for _, w := range s.watchers {
+		if !w.IsWatchingEvent(e) || !w.IsWatchingType(t) {
+			continue
+		}
+
+		_ = w.WriteEvent(t, e, data, false) // Do not block
+	}
+}
+
+// GetJob retrieves the Job identified by the id string. If the job isn't found it will
+// return an indicating the error.
+func (s *SQLJobStore) GetJob(ctx context.Context, id string) (models.Job, error) {
+	var job models.Job
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		job, err = s.getJob(ctx, tx, id, false)
+		return
+	})
+	return job, err
+}
+
+func (s *SQLJobStore) getJob(ctx context.Context, tx *txn, jobID string, lock bool) (models.Job, error) {
+	var job models.Job
+
+	jobID, err := s.reifyJobID(ctx, tx, jobID)
+	if err != nil {
+		return job, err
+	}
+
+	query := `SELECT data FROM jobs WHERE id = ?`
+	if lock {
+		query = tx.forUpdate(query)
+	}
+	var data []byte
+	err = tx.queryRow(ctx, query, jobID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, bacerrors.NewJobNotFound(jobID)
+	} else if err != nil {
+		return job, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &job)
+	return job, err
+}
+
+// reifyJobID ensures the provided job ID is a full-length ID. This is either through
+// returning the ID, or resolving the short ID to a single job id.
+func (s *SQLJobStore) reifyJobID(ctx context.Context, tx *txn, jobID string) (string, error) {
+	if idgen.ShortUUID(jobID) != jobID {
+		// Return what we were given
+		return jobID, nil
+	}
+
+	rows, err := tx.query(ctx, `SELECT id FROM jobs WHERE SUBSTR(id, 1, ?) = ? ORDER BY id`, len(jobID), jobID)
+	if err != nil {
+		return "", err
+	}
+	found, err := scanStrings(rows)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		return "", bacerrors.NewJobNotFound(jobID)
+	case 1:
+		return found[0], nil
+	default:
+		return "", bacerrors.NewMultipleJobsFound(jobID, found)
+	}
+}
+
+func (s *SQLJobStore) jobExists(ctx context.Context, tx *txn, jobID string) (bool, error) {
+	var count int
+	err := tx.queryRow(ctx, `SELECT COUNT(*) FROM jobs WHERE id = ?`, jobID).Scan(&count)
+	return count > 0, err
+}
+
+// GetJobs returns all Jobs that match the provided query
+func (s *SQLJobStore) GetJobs(ctx context.Context, query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
+	var response *jobstore.JobQueryResponse
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		response, err = s.getJobs(ctx, tx, query)
+		return
+	})
+	return response, err
+}
+
+func (s *SQLJobStore) getJobs(ctx context.Context, tx *txn, query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
+	var conditions []string
+	var args []interface{}
+
+	if !query.ReturnAll && query.Namespace != "" {
+		conditions = append(conditions, "namespace = ?")
+		args = append(args, query.Namespace)
+	}
+	// Jobs must have ANY of the included tags, and NONE of the excluded tags
+	if len(query.IncludeTags) > 0 {
+		conditions = append(conditions,
+			fmt.Sprintf("id IN (SELECT job_id FROM job_tags WHERE tag IN (%s))", placeholders(len(query.IncludeTags))))
+		args = append(args, lowerTags(query.IncludeTags)...)
+	}
+	if len(query.ExcludeTags) > 0 {
+		conditions = append(conditions,
+			fmt.Sprintf("id NOT IN (SELECT job_id FROM job_tags WHERE tag IN (%s))", placeholders(len(query.ExcludeTags))))
+		args = append(args, lowerTags(query.ExcludeTags)...)
+	}
+	if len(query.States) > 0 {
+		conditions = append(conditions, fmt.Sprintf("state IN (%s)", placeholders(len(query.States))))
+		for _, state := range query.States {
+			args = append(args, state.String())
+		}
+	}
+	if len(query.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("type IN (%s)", placeholders(len(query.Types))))
+		for _, typ := range query.Types {
+			args = append(args, typ)
+		}
+	}
+	if query.DependsOn != "" {
+		conditions = append(conditions, "id IN (SELECT job_id FROM job_dependencies WHERE upstream_id = ?)")
+		args = append(args, query.DependsOn)
+	}
+	if query.NamePrefix != "" {
+		conditions = append(conditions, `name LIKE ? ESCAPE '\'`)
+		args = append(args, likeEscaper.Replace(query.NamePrefix)+"%")
+	}
+	if !query.CreatedAfter.IsZero() {
+		conditions = append(conditions, "create_time >= ?")
+		args = append(args, query.CreatedAfter.UnixNano())
+	}
+	if !query.CreatedBefore.IsZero() {
+		conditions = append(conditions, "create_time < ?")
+		args = append(args, query.CreatedBefore.UnixNano())
+	}
+
+	// We apply created_at as a default sort so that we can use it for pagination.
+	// Without a known default we won't have a stable sort that makes sense for
+	// offsets/limits.
+	var orderBy string
+	switch query.SortField() {
+	case jobstore.SortByID:
+		orderBy = "id"
+	case jobstore.SortByModifiedAt:
+		orderBy = "modify_time"
+	default:
+		orderBy = "create_time"
+	}
+	direction, after := "ASC", ">"
+	if query.SortReverse {
+		direction, after = "DESC", "<"
+	}
+
+	// Jobs after the cursor in the sort order, which is the sort field and then the job ID
+	if query.Cursor != nil {
+		if orderBy == "id" {
+			conditions = append(conditions, "id "+after+" ?")
+			args = append(args, query.Cursor.ID)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", orderBy, after, orderBy, after))
+			args = append(args, query.Cursor.SortValue, query.Cursor.SortValue, query.Cursor.ID)
+		}
+	}
+
+	statement := "SELECT data FROM jobs"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if orderBy == "id" {
+		statement += " ORDER BY id " + direction
+	} else {
+		statement += fmt.Sprintf(" ORDER BY %s %s, id %s", orderBy, direction, direction)
+	}
+
+	// Without a selector or engine types the pagination can be done by the database. With
+	// them, the jobs have to be matched before the offset and limit can be applied.
+	paginated := query.Selector == nil && len(query.EngineTypes) == 0 && query.Limit > 0
+	if paginated {
+		// Fetch one more job than needed to find out if there are more
+		statement += " LIMIT ? OFFSET ?"
+		args = append(args, query.Limit+1, query.Offset)
+	}
+
+	result, err := s.queryJobs(ctx, tx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// If we have a selector or engine types, filter the results to only those that match
+	if query.HasUnindexedFilters() {
+		var filtered []models.Job
+		for i := range result {
+			if query.MatchesUnindexed(&result[i]) {
+				filtered = append(filtered, result[i])
+			}
+		}
+		result = filtered
+	}
+
+	var jobs []models.Job
+	var more bool
+	if paginated {
+		jobs, more = result, uint32(len(result)) > query.Limit
+		if more {
+			jobs = result[:query.Limit]
+		}
+		if jobs == nil {
+			jobs = []models.Job{}
+		}
+	} else {
+		jobs, more = getJobsWithinLimit(result, query)
+	}
+
+	response := &jobstore.JobQueryResponse{
+		Jobs:   jobs,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	// If we don't have 'limit' jobs, then there definitely aren't any more
+	if more {
+		response.NextOffset = query.Offset + query.Limit
+		response.NextCursor = query.NewJobCursor(&jobs[len(jobs)-1])
+	}
+
+	return response, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func getJobsWithinLimit(jobs []models.Job, query jobstore.JobQuery) ([]models.Job, bool) {
+	if query.Offset >= uint32(len(jobs)) {
+		return []models.Job{}, false
+	}
+
+	jobsFiltered := jobs[query.Offset:]
+	if query.Limit == 0 || uint32(len(jobsFiltered)) <= query.Limit {
+		return jobsFiltered, false
+	}
+	return jobsFiltered[:query.Limit], true
+}
+
+func (s *SQLJobStore) queryJobs(ctx context.Context, tx *txn, query string, args ...interface{}) ([]models.Job, error) {
+	rows, err := tx.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var job models.Job
+		if err = s.marshaller.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// GetExecutions returns the current job state for the provided job id
+func (s *SQLJobStore) GetExecutions(ctx context.Context, options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	var state []models.Execution
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		state, err = s.getExecutions(ctx, tx, options)
+		return
+	})
+	return state, err
+}
+
+func (s *SQLJobStore) getExecutions(ctx context.Context, tx *txn, options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	// load latest job state, which also makes sure the job exists
+	j, err := s.getJob(ctx, tx, options.JobID, false)
+	if err != nil {
+		return nil, err
+	}
+	var job *models.Job
+	if options.IncludeJob {
+		job = &j
+	}
+
+	rows, err := tx.query(ctx, `SELECT data FROM executions WHERE job_id = ? ORDER BY id`, j.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var execs []models.Execution
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var es models.Execution
+		if err = s.marshaller.Unmarshal(data, &es); err != nil {
+			return nil, err
+		}
+		es.Job = job
+		execs = append(execs, es)
+	}
+	return execs, rows.Err()
+}
+
+func (s *SQLJobStore) getExecution(ctx context.Context, tx *txn, id string, lock bool) (models.Execution, error) {
+	var exec models.Execution
+
+	query := `SELECT data FROM executions WHERE id = ?`
+	if lock {
+		query = tx.forUpdate(query)
+	}
+	var data []byte
+	err := tx.queryRow(ctx, query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return exec, jobstore.NewErrExecutionNotFound(id)
+	} else if err != nil {
+		return exec, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &exec)
+	return exec, err
+}
+
+// GetInProgressJobs gets a list of the currently in-progress jobs, if a job type is supplied then
+// only jobs of that type will be retrieved
+func (s *SQLJobStore) GetInProgressJobs(ctx context.Context, jobType string) ([]models.Job, error) {
+	var infos []models.Job
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		query := `SELECT data FROM jobs WHERE in_progress = ?`
+		args := []interface{}{true}
+		if jobType != "" {
+			query += ` AND type = ?`
+			args = append(args, jobType)
+		}
+		infos, err = s.queryJobs(ctx, tx, query+` ORDER BY type, id`, args...)
+		return
+	})
+	return infos, err
+}
+
+// GetJobHistory returns the job (and execution) history for the provided options
+func (s *SQLJobStore) GetJobHistory(ctx context.Context,
+	jobID string,
+	options jobstore.JobHistoryFilterOptions) ([]models.JobHistory, error) {
+	var history []models.JobHistory
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		history, err = s.getJobHistory(ctx, tx, jobID, options)
+		return
+	})
+	return history, err
+}
+
+func (s *SQLJobStore) getJobHistory(ctx context.Context, tx *txn, jobID string,
+	options jobstore.JobHistoryFilterOptions) ([]models.JobHistory, error) {
+	var history []models.JobHistory
+
+	jobID, err := s.reifyJobID(ctx, tx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter out anything before the specified Since time
+	since := time.Unix(options.Since, 0).UnixNano()
+
+	// Job level entries have no execution or node, so they are excluded when
+	// filtering on either of them
+	if !options.ExcludeJobLevel && options.ExecutionID == "" && options.NodeID == "" {
+		items, err := s.queryHistory(ctx, tx,
+			`SELECT data FROM job_history WHERE job_id = ? AND time >= ? ORDER BY id`, jobID, since)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, items...)
+	}
+
+	if !options.ExcludeExecutionLevel {
+		query := `SELECT data FROM execution_history WHERE job_id = ? AND time >= ?`
+		args := []interface{}{jobID, since}
+		if options.ExecutionID != "" {
+			query += ` AND SUBSTR(execution_id, 1, ?) = ?`
+			args = append(args, len(options.ExecutionID), options.ExecutionID)
+		}
+		if options.NodeID != "" {
+			query += ` AND SUBSTR(node_id, 1, ?) = ?`
+			args = append(args, len(options.NodeID), options.NodeID)
+		}
+		items, err := s.queryHistory(ctx, tx, query+` ORDER BY id`, args...)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, items...)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.UTC().Before(history[j].Time.UTC()) })
+
+	return history, nil
+}
+
+func (s *SQLJobStore) queryHistory(ctx context.Context, tx *txn, query string, args ...interface{}) ([]models.JobHistory, error) {
+	rows, err := tx.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []models.JobHistory
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var item models.JobHistory
+		if err = s.marshaller.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		history = append(history, item)
+	}
+	return history, rows.Err()
+}
+
+// CreateJob creates a new record of a job in the data store
+func (s *SQLJobStore) CreateJob(ctx context.Context, job models.Job, event models.Event) error {
+	job.State = models.NewJobState(models.JobStateTypePending)
+	job.Revision = 1
+	job.Version = 1
+	job.CreateTime = s.clock.Now().UTC().UnixNano()
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+	job.Normalize()
+	err := job.Validate()
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.createJob(ctx, tx, job, event)
+	})
+}
+
+func (s *SQLJobStore) createJob(ctx context.Context, tx *txn, job models.Job, event models.Event) error {
+	exists, err := s.jobExists(ctx, tx, job.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return jobstore.NewErrJobAlreadyExists(job.ID)
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.CreateEvent, job)
+	})
+
+	jobData, err := s.marshaller.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	err = tx.exec(ctx, `INSERT INTO jobs
+		(id, name, namespace, type, state, revision, in_progress, create_time, modify_time, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Name, job.Namespace, job.Type, job.State.StateType.String(), job.Revision,
+		!job.IsTerminal(), job.CreateTime, job.ModifyTime, string(jobData))
+	if err != nil {
+		return err
+	}
+
+	// Write rows for specific tags, several labels can share the same lowercased key
+	tags := make(map[string]struct{}, len(job.Labels))
+	for tag := range job.Labels {
+		tags[strings.ToLower(tag)] = struct{}{}
+	}
+	for tag := range tags {
+		if err = tx.exec(ctx, `INSERT INTO job_tags (job_id, tag) VALUES (?, ?)`, job.ID, tag); err != nil {
+			return err
+		}
+	}
+	if err = s.insertJobDependencies(ctx, tx, job); err != nil {
+		return err
+	}
+
+	return s.appendJobHistory(ctx, tx, job, models.JobStateTypePending, event)
+}
+
+// UpdateJob updates the specification of an existing job as a new version of the job,
+// keeping the previous specification as a previous version
+func (s *SQLJobStore) UpdateJob(ctx context.Context, job models.Job, event models.Event) error {
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.updateJob(ctx, tx, job, event)
+	})
+}
+
+func (s *SQLJobStore) updateJob(ctx context.Context, tx *txn, job models.Job, event models.Event) error {
+	existing, err := s.getJob(ctx, tx, job.ID, true)
+	if err != nil {
+		return err
+	}
+	if existing.IsTerminal() {
+		return jobstore.NewErrInvalidJobState(existing.ID, existing.State.StateType, models.JobStateTypeUndefined)
+	}
+	if job.Type != existing.Type {
+		return fmt.Errorf("cannot change the type of job %s from %s to %s", existing.ID, existing.Type, job.Type)
+	}
+
+	// jobs created before versions were tracked are at their first version
+	if existing.Version == 0 {
+		existing.Version = 1
+	}
+
+	job.ID = existing.ID
+	job.Namespace = existing.Namespace
+	job.State = existing.State
+	job.Version = existing.Version + 1
+	job.Revision = existing.Revision + 1
+	job.CreateTime = existing.CreateTime
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+	job.Normalize()
+	if err = job.Validate(); err != nil {
+		return err
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.UpdateEvent, job)
+	})
+
+	// keep the previous specification as a previous version
+	existingData, err := s.marshaller.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	err = tx.exec(ctx, `INSERT INTO job_versions (job_id, version, data) VALUES (?, ?, ?)`,
+		existing.ID, existing.Version, string(existingData))
+	if err != nil {
+		return err
+	}
+
+	jobData, err := s.marshaller.Marshal(job)
+	if err != nil {
+		return err
+	}
+	err = tx.exec(ctx, `UPDATE jobs SET name = ?, revision = ?, modify_time = ?, data = ? WHERE id = ?`,
+		job.Name, job.Revision, job.ModifyTime, string(jobData), job.ID)
+	if err != nil {
+		return err
+	}
+
+	// Re-write rows for specific tags
+	if err = tx.exec(ctx, `DELETE FROM job_tags WHERE job_id = ?`, job.ID); err != nil {
+		return err
+	}
+	tags := make(map[string]struct{}, len(job.Labels))
+	for tag := range job.Labels {
+		tags[strings.ToLower(tag)] = struct{}{}
+	}
+	for tag := range tags {
+		if err = tx.exec(ctx, `INSERT INTO job_tags (job_id, tag) VALUES (?, ?)`, job.ID, tag); err != nil {
+			return err
+		}
+	}
+
+	// Re-write rows for the dependencies, which may change with the specification
+	if err = tx.exec(ctx, `DELETE FROM job_dependencies WHERE job_id = ?`, job.ID); err != nil {
+		return err
+	}
+	if err = s.insertJobDependencies(ctx, tx, job); err != nil {
+		return err
+	}
+
+	return s.appendJobHistory(ctx, tx, job, existing.State.StateType, event)
+}
+
+// insertJobDependencies writes a row for each upstream job the job depends on
+func (s *SQLJobStore) insertJobDependencies(ctx context.Context, tx *txn, job models.Job) error {
+	upstreams := make(map[string]struct{}, len(job.Dependencies))
+	for _, dep := range job.Dependencies {
+		if dep != nil && dep.JobID != "" {
+			upstreams[dep.JobID] = struct{}{}
+		}
+	}
+	for upstreamID := range upstreams {
+		err := tx.exec(ctx, `INSERT INTO job_dependencies (job_id, upstream_id) VALUES (?, ?)`, job.ID, upstreamID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetJobVersion returns the specification of a job at the given version
+func (s *SQLJobStore) GetJobVersion(ctx context.Context, id string, version uint64) (models.Job, error) {
+	var job models.Job
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		job, err = s.getJobVersion(ctx, tx, id, version)
+		return
+	})
+	return job, err
+}
+
+func (s *SQLJobStore) getJobVersion(ctx context.Context, tx *txn, id string, version uint64) (models.Job, error) {
+	job, err := s.getJob(ctx, tx, id, false)
+	if err != nil {
+		return job, err
+	}
+	if job.Version == version || (job.Version == 0 && version == 1) {
+		return job, nil
+	}
+
+	var data []byte
+	err = tx.queryRow(ctx, `SELECT data FROM job_versions WHERE job_id = ? AND version = ?`, job.ID, version).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Job{}, jobstore.NewErrJobVersionNotFound(job.ID, version)
+	} else if err != nil {
+		return models.Job{}, err
+	}
+	var previous models.Job
+	err = s.marshaller.Unmarshal(data, &previous)
+	return previous, err
+}
+
+// DeleteJob removes the specified job from the system entirely
+func (s *SQLJobStore) DeleteJob(ctx context.Context, jobID string) error {
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.deleteJob(ctx, tx, jobID)
+	})
+}
+
+func (s *SQLJobStore) deleteJob(ctx context.Context, tx *txn, jobID string) error {
+	job, err := s.getJob(ctx, tx, jobID, true)
+	if err != nil {
+		return bacerrors.NewJobNotFound(jobID)
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.DeleteEvent, job)
+	})
+
+	// Delete everything that references the job before the job itself
+	tables := []string{"job_tags", "job_dependencies", "job_versions", "executions", "job_history", "execution_history", "evaluations"}
+	for _, table := range tables {
+		if err = tx.exec(ctx, `DELETE FROM `+table+` WHERE job_id = ?`, job.ID); err != nil {
+			return err
+		}
+	}
+	return tx.exec(ctx, `DELETE FROM jobs WHERE id = ?`, job.ID)
+}
+
+// UpdateJobState updates the current state for a single Job, appending an entry to
+// the history at the same time
+func (s *SQLJobStore) UpdateJobState(ctx context.Context, request jobstore.UpdateJobStateRequest) error {
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.updateJobState(ctx, tx, request)
+	})
+}
+
+func (s *SQLJobStore) updateJobState(ctx context.Context, tx *txn, request jobstore.UpdateJobStateRequest) error {
+	job, err := s.getJob(ctx, tx, request.JobID, true)
+	if err != nil {
+		return err
+	}
+
+	// check the expected state
+	if err = request.Condition.Validate(job); err != nil {
+		return err
+	}
+
+	if job.IsTerminal() {
+		return jobstore.NewErrJobAlreadyTerminal(request.JobID, job.State.StateType, request.NewState)
+	}
+
+	// update the job state
+	previousState := job.State.StateType
+	job.State.StateType = request.NewState
+	job.State.Message = request.Event.Message
+	job.Revision++
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+
+	// Setup an oncommit handler after the obvious errors/checks
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.UpdateEvent, job)
+	})
+
+	jobStateData, err := s.marshaller.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	// Once terminal, the job is no longer in progress
+	err = tx.exec(ctx, `UPDATE jobs
+		SET state = ?, revision = ?, in_progress = ?, modify_time = ?, data = ?
+		WHERE id = ?`,
+		job.State.StateType.String(), job.Revision, !job.IsTerminal(), job.ModifyTime, string(jobStateData), job.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.appendJobHistory(ctx, tx, job, previousState, request.Event)
+}
+
+func (s *SQLJobStore) appendJobHistory(ctx context.Context, tx *txn, updateJob models.Job,
+	previousState models.JobStateType, event models.Event) error {
+	historyEntry := models.JobHistory{
+		Type:  models.JobHistoryTypeJobLevel,
+		JobID: updateJob.ID,
+		JobState: &models.StateChange[models.JobStateType]{
+			Previous: previousState,
+			New:      updateJob.State.StateType,
+		},
+		NewRevision: updateJob.Revision,
+		Comment:     event.Message,
+		Event:       event,
+		Time:        time.Unix(0, updateJob.ModifyTime),
+	}
+	data, err := s.marshaller.Marshal(historyEntry)
+	if err != nil {
+		return err
+	}
+
+	return tx.exec(ctx, `INSERT INTO job_history (job_id, time, data) VALUES (?, ?, ?)`,
+		updateJob.ID, updateJob.ModifyTime, string(data))
+}
+
+// CreateExecution creates a record of a new execution
+func (s *SQLJobStore) CreateExecution(ctx context.Context, execution models.Execution, event models.Event) error {
+	if execution.CreateTime == 0 {
+		execution.CreateTime = s.clock.Now().UTC().UnixNano()
+	}
+	if execution.ModifyTime == 0 {
+		execution.ModifyTime = execution.CreateTime
+	}
+	if execution.Revision == 0 {
+		execution.Revision = 1
+	}
+	// Ensure the job is not included in the execution when persisting it
+	execution.Job = nil
+	execution.Normalize()
+	err := execution.Validate()
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.createExecution(ctx, tx, execution, event)
+	})
+}
+
+func (s *SQLJobStore) createExecution(ctx context.Context, tx *txn, execution models.Execution, event models.Event) error {
+	exists, err := s.jobExists(ctx, tx, execution.JobID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return jobstore.NewErrJobNotFound(execution.JobID)
+	}
+
+	if _, err = s.getExecution(ctx, tx, execution.ID, false); err == nil {
+		return jobstore.NewErrExecutionAlreadyExists(execution.ID)
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.ExecutionWatcher, jobstore.CreateEvent, execution)
+	})
+
+	data, err := s.marshaller.Marshal(execution)
+	if err != nil {
+		return err
+	}
+
+	err = tx.exec(ctx, `INSERT INTO executions
+		(id, job_id, node_id, compute_state, desired_state, revision, create_time, modify_time, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		execution.ID, execution.JobID, execution.NodeID, execution.ComputeState.StateType.String(),
+		execution.DesiredState.StateType.String(), execution.Revision, execution.CreateTime, execution.ModifyTime,
+		string(data))
+	if err != nil {
+		return err
+	}
+
+	return s.appendExecutionHistory(ctx, tx, execution, models.ExecutionStateNew, event)
+}
+
+// UpdateExecution updates the state of a single execution by loading from storage,
+// updating and then writing back in a single transaction
+func (s *SQLJobStore) UpdateExecution(ctx context.Context, request jobstore.UpdateExecutionRequest) error {
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.updateExecution(ctx, tx, request)
+	})
+}
+
+func (s *SQLJobStore) updateExecution(ctx context.Context, tx *txn, request jobstore.UpdateExecutionRequest) error {
+	existingExecution, err := s.getExecution(ctx, tx, request.ExecutionID, true)
+	if err != nil {
+		return jobstore.NewErrExecutionNotFound(request.ExecutionID)
+	}
+
+	// check the expected state
+	if err = request.Condition.Validate(existingExecution); err != nil {
+		return err
+	}
+	if existingExecution.IsTerminalComputeState() {
+		return jobstore.NewErrExecutionAlreadyTerminal(
+			request.ExecutionID, existingExecution.ComputeState.StateType, request.NewValues.ComputeState.StateType)
+	}
+
+	// populate default values, maintain existing execution createTime
+	newExecution := request.NewValues
+	newExecution.CreateTime = existingExecution.CreateTime
+	if newExecution.ModifyTime == 0 {
+		newExecution.ModifyTime = s.clock.Now().UTC().UnixNano()
+	}
+	if newExecution.Revision == 0 {
+		newExecution.Revision = existingExecution.Revision + 1
+	}
+	newExecution.Normalize()
+
+	err = mergo.Merge(&newExecution, existingExecution)
+	if err != nil {
+		return err
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.ExecutionWatcher, jobstore.UpdateEvent, newExecution)
+	})
+
+	data, err := s.marshaller.Marshal(newExecution)
+	if err != nil {
+		return err
+	}
+
+	err = tx.exec(ctx, `UPDATE executions
+		SET node_id = ?, compute_state = ?, desired_state = ?, revision = ?, modify_time = ?, data = ?
+		WHERE id = ?`,
+		newExecution.NodeID, newExecution.ComputeState.StateType.String(), newExecution.DesiredState.StateType.String(),
+		newExecution.Revision, newExecution.ModifyTime, string(data), existingExecution.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.appendExecutionHistory(ctx, tx, newExecution, existingExecution.ComputeState.StateType, request.Event)
+}
+
+func (s *SQLJobStore) appendExecutionHistory(ctx context.Context, tx *txn, updated models.Execution,
+	previous models.ExecutionStateType, event models.Event) error {
+	historyEntry := models.JobHistory{
+		Type:        models.JobHistoryTypeExecutionLevel,
+		JobID:       updated.JobID,
+		NodeID:      updated.NodeID,
+		ExecutionID: updated.ID,
+		ExecutionState: &models.StateChange[models.ExecutionStateType]{
+			Previous: previous,
+			New:      updated.ComputeState.StateType,
+		},
+		NewRevision: updated.Revision,
+		Comment:     event.Message,
+		Event:       event,
+		Time:        time.Unix(0, updated.ModifyTime),
+	}
+
+	data, err := s.marshaller.Marshal(historyEntry)
+	if err != nil {
+		return err
+	}
+
+	return tx.exec(ctx, `INSERT INTO execution_history
+		(job_id, execution_id, node_id, time, data) VALUES (?, ?, ?, ?, ?)`,
+		updated.JobID, updated.ID, updated.NodeID, updated.ModifyTime, string(data))
+}
+
+// CreateEvaluation creates a new evaluation
+func (s *SQLJobStore) CreateEvaluation(ctx context.Context, eval models.Evaluation) error {
+	return s.inTx(ctx, func(tx *txn) error {
+		return s.createEvaluation(ctx, tx, eval)
+	})
+}
+
+func (s *SQLJobStore) createEvaluation(ctx context.Context, tx *txn, eval models.Evaluation) error {
+	job, err := s.getJob(ctx, tx, eval.JobID, false)
+	if err != nil {
+		return err
+	}
+
+	// If there is no error getting an eval with this ID, then it already exists
+	if _, err = s.getEvaluation(ctx, tx, eval.ID); err == nil {
+		return bacerrors.NewAlreadyExists(eval.ID, "Evaluation")
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.EvaluationWatcher, jobstore.CreateEvent, eval)
+	})
+
+	data, err := s.marshaller.Marshal(eval)
+	if err != nil {
+		return err
+	}
+
+	return tx.exec(ctx, `INSERT INTO evaluations (id, job_id, data) VALUES (?, ?, ?)`, eval.ID, job.ID, string(data))
+}
+
+// GetEvaluation retrieves the specified evaluation
+func (s *SQLJobStore) GetEvaluation(ctx context.Context, id string) (models.Evaluation, error) {
+	var eval models.Evaluation
+	err := s.inTx(ctx, func(tx *txn) (err error) {
+		eval, err = s.getEvaluation(ctx, tx, id)
+		return
+	})
+	return eval, err
+}
+
+func (s *SQLJobStore) getEvaluation(ctx context.Context, tx *txn, id string) (models.Evaluation, error) {
+	var eval models.Evaluation
+
+	var data []byte
+	err := tx.queryRow(ctx, `SELECT data FROM evaluations WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return eval, bacerrors.NewEvaluationNotFound(id)
+	} else if err != nil {
+		return eval, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &eval)
+	return eval, err
+}
+
+// DeleteEvaluation deletes the specified evaluation
+func (s *SQLJobStore) DeleteEvaluation(ctx context.Context, id string) error {
+	return s.inTx(ctx, func(tx *txn) error {
+		eval, err := s.getEvaluation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		tx.OnCommit(func() {
+			s.triggerEvent(jobstore.EvaluationWatcher, jobstore.DeleteEvent, eval)
+		})
+
+		return tx.exec(ctx, `DELETE FROM evaluations WHERE id = ?`, id)
+	})
+}
+
+func (s *SQLJobStore) Close(ctx context.Context) error {
+	s.watcherLock.Lock()
+	for _, w := range s.watchers {
+		w.Close()
+	}
+	s.watcherLock.Unlock()
+
+	log.Ctx(ctx).Debug().Str("Driver", s.dialect.driver).Msg("closing sql-backed job store")
+	return s.database.Close()
+}
+
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func lowerTags(tags []string) []interface{} {
+	lowered := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		lowered[i] = strings.ToLower(tag)
+	}
+	return lowered
+}
+
+// Static check to ensure that SQLJobStore implements jobstore.Store
+var _ jobstore.Store = (*SQLJobStore)(nil)
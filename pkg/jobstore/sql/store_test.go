@@ -0,0 +1,64 @@
+//go:build unit || !integration
+
+package sqljobstore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	jobstoretest "github.com/bacalhau-project/bacalhau/pkg/jobstore/test"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+	_ "modernc.org/sqlite"
+)
+
+// postgresDSNEnv is the environment variable holding the connection string of a
+// PostgreSQL database to run the suite against. The database must be empty, as all
+// its jobstore tables are dropped after each test.
+const postgresDSNEnv = "BACALHAU_TEST_POSTGRES_DSN"
+
+type SQLiteJobstoreTestSuite struct {
+	jobstoretest.StoreSuite
+}
+
+func TestSQLiteJobstoreTestSuite(t *testing.T) {
+	s := new(SQLiteJobstoreTestSuite)
+	s.NewStore = func(clock clock.Clock) (jobstore.Store, error) {
+		return NewSQLJobStore(context.Background(), DriverSQLite, ":memory:", WithClock(clock))
+	}
+	suite.Run(t, s)
+}
+
+type PostgresJobstoreTestSuite struct {
+	jobstoretest.StoreSuite
+	dsn string
+}
+
+func TestPostgresJobstoreTestSuite(t *testing.T) {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+	s := &PostgresJobstoreTestSuite{dsn: dsn}
+	s.NewStore = func(clock clock.Clock) (jobstore.Store, error) {
+		return NewSQLJobStore(context.Background(), DriverPostgres, dsn, WithClock(clock))
+	}
+	suite.Run(t, s)
+}
+
+func (s *PostgresJobstoreTestSuite) TearDownTest() {
+	s.StoreSuite.TearDownTest()
+
+	store, err := NewSQLJobStore(s.Ctx, DriverPostgres, s.dsn)
+	s.Require().NoError(err)
+	defer store.Close(s.Ctx)
+	for _, table := range []string{
+		"job_tags", "job_dependencies", "job_versions", "executions", "job_history", "execution_history", "evaluations", "jobs",
+		"schema_migrations",
+	} {
+		_, err = store.database.ExecContext(s.Ctx, "DROP TABLE "+table)
+		s.Require().NoError(err)
+	}
+}
@@ -0,0 +1,929 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+	"github.com/benbjohnson/clock"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// StoreSuite is the test suite that every jobstore.Store implementation is expected to
+// pass. Implementations run it by setting NewStore before running the suite.
+type StoreSuite struct {
+	suite.Suite
+	// NewStore creates an empty store that uses the given clock. It is called
+	// before every test, and the store is closed after the test.
+	NewStore func(clock clock.Clock) (jobstore.Store, error)
+
+	Store jobstore.Store
+	Ctx   context.Context
+	Clock *clock.Mock
+}
+
+func (s *StoreSuite) SetupTest() {
+	s.Clock = clock.NewMock()
+	s.Ctx = context.Background()
+
+	var err error
+	s.Store, err = s.NewStore(s.Clock)
+	s.Require().NoError(err)
+
+	jobFixtures := []struct {
+		id              string
+		jobType         string
+		client          string
+		tags            map[string]string
+		jobStates       []models.JobStateType
+		executionStates []models.ExecutionStateType
+	}{
+		{
+			id:              "110",
+			client:          "client1",
+			jobType:         "batch",
+			tags:            map[string]string{"gpu": "true", "fast": "true"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning, models.JobStateTypeStopped},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted, models.ExecutionStateCancelled},
+		},
+		{
+			id:              "120",
+			client:          "client2",
+			jobType:         "batch",
+			tags:            map[string]string{},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning, models.JobStateTypeStopped},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted, models.ExecutionStateCancelled},
+		},
+		{
+			id:              "130",
+			client:          "client3",
+			jobType:         "batch",
+			tags:            map[string]string{"slow": "true", "max": "10"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
+		},
+		{
+			id:              "140",
+			client:          "client4",
+			jobType:         "batch",
+			tags:            map[string]string{"max": "10"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
+		},
+		{
+			id:              "150",
+			client:          "client5",
+			jobType:         "daemon",
+			tags:            map[string]string{"max": "10"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
+		},
+	}
+
+	for _, fixture := range jobFixtures {
+		s.Clock.Add(1 * time.Second)
+		job := makeDockerEngineJob(
+			[]string{"bash", "-c", "echo hello"})
+
+		job.ID = fixture.id
+		job.Name = "job-" + fixture.id
+		job.Type = fixture.jobType
+		job.Labels = fixture.tags
+		job.Namespace = fixture.client
+		err := s.Store.CreateJob(s.Ctx, *job, models.Event{})
+		s.Require().NoError(err)
+
+		s.Clock.Add(1 * time.Second)
+		execution := mock.ExecutionForJob(job)
+		execution.ComputeState.StateType = models.ExecutionStateNew
+		err = s.Store.CreateExecution(s.Ctx, *execution, models.Event{})
+		s.Require().NoError(err)
+
+		for i, state := range fixture.jobStates {
+			s.Clock.Add(1 * time.Second)
+
+			oldState := models.JobStateTypePending
+			if i > 0 {
+				oldState = fixture.jobStates[i-1]
+			}
+
+			request := jobstore.UpdateJobStateRequest{
+				JobID:    fixture.id,
+				NewState: state,
+				Condition: jobstore.UpdateJobCondition{
+					ExpectedState:    oldState,
+					ExpectedRevision: uint64(i + 1),
+				},
+				Event: models.Event{},
+			}
+			err = s.Store.UpdateJobState(s.Ctx, request)
+			s.Require().NoError(err)
+		}
+
+		for i, state := range fixture.executionStates {
+			s.Clock.Add(1 * time.Second)
+
+			oldState := models.ExecutionStateNew
+			if i > 0 {
+				oldState = fixture.executionStates[i-1]
+			}
+
+			// We are pretending this is a new execution struct
+			execution.ComputeState.StateType = state
+			execution.ModifyTime = s.Clock.Now().UTC().UnixNano()
+
+			request := jobstore.UpdateExecutionRequest{
+				ExecutionID: execution.ID,
+				Condition: jobstore.UpdateExecutionCondition{
+					ExpectedStates:   []models.ExecutionStateType{oldState},
+					ExpectedRevision: uint64(i + 1),
+				},
+				NewValues: *execution,
+				Event:     models.Event{},
+			}
+
+			err = s.Store.UpdateExecution(s.Ctx, request)
+			s.Require().NoError(err)
+		}
+
+	}
+}
+
+func (s *StoreSuite) TearDownTest() {
+	s.Require().NoError(s.Store.Close(s.Ctx))
+}
+
+func (s *StoreSuite) TestUnfilteredJobHistory() {
+	history, err := s.Store.GetJobHistory(s.Ctx, "110", jobstore.JobHistoryFilterOptions{})
+	s.Require().NoError(err, "failed to get job history")
+	s.Require().Equal(8, len(history))
+
+	history, err = s.Store.GetJobHistory(s.Ctx, "11", jobstore.JobHistoryFilterOptions{})
+	s.Require().NoError(err)
+	s.NotEmpty(history)
+	s.Require().Equal("110", history[0].JobID)
+
+	history, err = s.Store.GetJobHistory(s.Ctx, "1", jobstore.JobHistoryFilterOptions{})
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.MultipleJobsFound{})
+	s.Require().Nil(history)
+}
+
+func (s *StoreSuite) TestJobHistoryOrdering() {
+	history, err := s.Store.GetJobHistory(s.Ctx, "110", jobstore.JobHistoryFilterOptions{})
+	require.NoError(s.T(), err, "failed to get job history")
+
+	// There are 6 history entries that we created directly, and 2 created by
+	// CreateJob and CreateExecution
+	require.Equal(s.T(), 8, len(history))
+
+	// Make sure they come back in order
+	values := make([]int64, len(history))
+	for i, h := range history {
+		values[i] = h.Time.Unix()
+	}
+
+	require.Equal(s.T(), []int64{1, 2, 3, 4, 5, 6, 7, 8}, values)
+}
+
+func (s *StoreSuite) TestTimeFilteredJobHistory() {
+	options := jobstore.JobHistoryFilterOptions{
+		Since: 5,
+	}
+
+	history, err := s.Store.GetJobHistory(s.Ctx, "110", options)
+	require.NoError(s.T(), err, "failed to get job history")
+	require.Equal(s.T(), 4, len(history))
+}
+
+func (s *StoreSuite) TestExecutionFilteredJobHistory() {
+	allHistories, err := s.Store.GetJobHistory(s.Ctx, "110", jobstore.JobHistoryFilterOptions{})
+	require.NoError(s.T(), err)
+
+	var executionID string
+	for _, h := range allHistories {
+		if h.ExecutionID != "" {
+			executionID = h.ExecutionID
+			break
+		}
+	}
+	require.NotEmpty(s.T(), executionID, "failed to find execution ID")
+
+	options := jobstore.JobHistoryFilterOptions{
+		ExecutionID: executionID,
+	}
+
+	history, err := s.Store.GetJobHistory(s.Ctx, "110", options)
+	require.NoError(s.T(), err, "failed to get job history")
+
+	for _, h := range history {
+		require.Equal(s.T(), executionID, h.ExecutionID)
+	}
+}
+
+func (s *StoreSuite) TestNodeFilteredJobHistory() {
+	allHistories, err := s.Store.GetJobHistory(s.Ctx, "110", jobstore.JobHistoryFilterOptions{})
+	require.NoError(s.T(), err)
+
+	var nodeID string
+	for _, h := range allHistories {
+		if h.NodeID != "" {
+			nodeID = h.NodeID
+			break
+		}
+	}
+	require.NotEmpty(s.T(), nodeID, "failed to find node ID")
+
+	options := jobstore.JobHistoryFilterOptions{
+		NodeID: nodeID,
+	}
+
+	history, err := s.Store.GetJobHistory(s.Ctx, "110", options)
+	require.NoError(s.T(), err, "failed to get job history")
+
+	for _, h := range history {
+		require.Equal(s.T(), nodeID, h.NodeID)
+	}
+}
+
+func (s *StoreSuite) TestLevelFilteredJobHistory() {
+	jobOptions := jobstore.JobHistoryFilterOptions{
+		ExcludeExecutionLevel: true,
+	}
+	execOptions := jobstore.JobHistoryFilterOptions{
+		ExcludeJobLevel: true,
+	}
+
+	history, err := s.Store.GetJobHistory(s.Ctx, "110", jobOptions)
+	s.Require().NoError(err, "failed to get job history")
+	s.Require().Equal(4, len(history))
+	s.Require().Equal(models.JobStateTypePending, history[1].JobState.New)
+
+	count := lo.Reduce(history, func(agg int, item models.JobHistory, _ int) int {
+		if item.Type == models.JobHistoryTypeJobLevel {
+			return agg + 1
+		}
+		return agg
+	}, 0)
+	s.Require().Equal(count, 4)
+
+	history, err = s.Store.GetJobHistory(s.Ctx, "110", execOptions)
+	s.Require().NoError(err, "failed to get job history")
+	s.Require().Equal(4, len(history))
+	s.Require().Equal(models.ExecutionStateAskForBid, history[1].ExecutionState.New)
+
+	count = lo.Reduce(history, func(agg int, item models.JobHistory, _ int) int {
+		if item.Type == models.JobHistoryTypeExecutionLevel {
+			return agg + 1
+		}
+		return agg
+	}, 0)
+	s.Require().Equal(count, 4)
+}
+
+func (s *StoreSuite) TestSearchJobs() {
+	s.T().Run("by client ID and included tags", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			Namespace:   "client1",
+			IncludeTags: []string{"fast", "slow"},
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 1, len(jobs))
+		require.Equal(t, "client1", jobs[0].Namespace)
+		require.Contains(t, jobs[0].Labels, "fast")
+		require.NotContains(t, jobs[0].Labels, "slow")
+	})
+
+	s.T().Run("basic selectors", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			Namespace: "client1",
+			Selector:  s.parseLabels("gpu=true,fast=true"),
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 1, len(jobs))
+		require.Equal(t, "client1", jobs[0].Namespace)
+	})
+
+	s.T().Run("all records with selectors and paging", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			SortBy:   "created_at",
+			Selector: s.parseLabels("max>1"),
+			Limit:    2,
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 2, len(jobs))
+
+		// Having skipped the first two s.ids because of non-matching selectors,
+		// we expect the next two to match
+		require.Equal(t, "130", jobs[0].ID)
+		require.Equal(t, "140", jobs[1].ID)
+
+		response, err = s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			SortBy:   "created_at",
+			Selector: s.parseLabels("max>1"),
+			Limit:    2,
+			Offset:   2,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.Jobs))
+	})
+
+	s.T().Run("everything sorted by created_at", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll: true,
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 5, len(jobs))
+		ids := lo.Map(jobs, func(item models.Job, _ int) string {
+			return item.ID
+		})
+		require.EqualValues(t, []string{"110", "120", "130", "140", "150"}, ids)
+
+		response, err = s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll:   true,
+			SortReverse: true,
+		})
+		require.NoError(t, err)
+		jobs = response.Jobs
+		require.Equal(t, 5, len(jobs))
+		ids = lo.Map(jobs, func(item models.Job, _ int) string {
+			return item.ID
+		})
+		require.EqualValues(t, []string{"150", "140", "130", "120", "110"}, ids)
+	})
+
+	s.T().Run("everything", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, len(response.Jobs))
+	})
+
+	s.T().Run("everything offset", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll: true,
+			Offset:    1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 4, len(response.Jobs))
+		require.Equal(t, uint32(1), response.Offset)
+	})
+
+	s.T().Run("everything limit", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll: true,
+			Limit:     2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(response.Jobs))
+	})
+
+	s.T().Run("everything offset/limit", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll: true,
+			Offset:    1,
+			Limit:     1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.Jobs))
+	})
+
+	s.T().Run("include tags", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			IncludeTags: []string{"gpu"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.Jobs))
+		require.Equal(t, "110", response.Jobs[0].ID)
+	})
+
+	s.T().Run("all but exclude tags", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			ReturnAll:   true,
+			ExcludeTags: []string{"fast"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 4, len(response.Jobs))
+	})
+
+	s.T().Run("include/exclude same tag", func(t *testing.T) {
+		response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{
+			IncludeTags: []string{"gpu"},
+			ExcludeTags: []string{"fast"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, len(response.Jobs))
+	})
+}
+
+func (s *StoreSuite) TestSearchJobsFilters() {
+	ids := func(response *jobstore.JobQueryResponse) []string {
+		return lo.Map(response.Jobs, func(item models.Job, _ int) string { return item.ID })
+	}
+	all, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{ReturnAll: true})
+	s.Require().NoError(err)
+	s.Require().Len(all.Jobs, 5)
+
+	testCases := []struct {
+		name     string
+		query    jobstore.JobQuery
+		expected []string
+	}{
+		{
+			name:     "states",
+			query:    jobstore.JobQuery{ReturnAll: true, States: []models.JobStateType{models.JobStateTypeStopped}},
+			expected: []string{"110", "120"},
+		},
+		{
+			name: "states and namespace",
+			query: jobstore.JobQuery{
+				Namespace: "client2", States: []models.JobStateType{models.JobStateTypeStopped, models.JobStateTypeRunning}},
+			expected: []string{"120"},
+		},
+		{
+			name:     "types",
+			query:    jobstore.JobQuery{ReturnAll: true, Types: []string{"daemon"}},
+			expected: []string{"150"},
+		},
+		{
+			name:     "types and states",
+			query:    jobstore.JobQuery{ReturnAll: true, Types: []string{"batch"}, States: []models.JobStateType{models.JobStateTypeRunning}},
+			expected: []string{"130", "140"},
+		},
+		{
+			name:     "engine types",
+			query:    jobstore.JobQuery{ReturnAll: true, EngineTypes: []string{models.EngineDocker}},
+			expected: []string{"110", "120", "130", "140", "150"},
+		},
+		{
+			name:     "other engine types",
+			query:    jobstore.JobQuery{ReturnAll: true, EngineTypes: []string{models.EngineWasm}},
+			expected: []string{},
+		},
+		{
+			name:     "name prefix",
+			query:    jobstore.JobQuery{ReturnAll: true, NamePrefix: "job-13"},
+			expected: []string{"130"},
+		},
+		{
+			name:     "name prefix with wildcards",
+			query:    jobstore.JobQuery{ReturnAll: true, NamePrefix: "%"},
+			expected: []string{},
+		},
+		{
+			name: "create time range",
+			query: jobstore.JobQuery{
+				ReturnAll:     true,
+				CreatedAfter:  all.Jobs[1].GetCreateTime(),
+				CreatedBefore: all.Jobs[3].GetCreateTime(),
+			},
+			expected: []string{"120", "130"},
+		},
+		{
+			name: "create time range reversed",
+			query: jobstore.JobQuery{
+				ReturnAll:     true,
+				SortReverse:   true,
+				CreatedAfter:  all.Jobs[1].GetCreateTime(),
+				CreatedBefore: all.Jobs[3].GetCreateTime(),
+			},
+			expected: []string{"130", "120"},
+		},
+		{
+			name:     "create time range sorted by id",
+			query:    jobstore.JobQuery{ReturnAll: true, SortBy: "id", SortReverse: true, CreatedAfter: all.Jobs[3].GetCreateTime()},
+			expected: []string{"150", "140"},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			response, err := s.Store.GetJobs(s.Ctx, tc.query)
+			s.Require().NoError(err)
+			s.Equal(tc.expected, ids(response))
+		})
+	}
+}
+
+func (s *StoreSuite) TestSearchJobsCursor() {
+	sorts := []struct {
+		sortBy  string
+		reverse bool
+	}{
+		{sortBy: "created_at"},
+		{sortBy: "created_at", reverse: true},
+		{sortBy: "modified_at"},
+		{sortBy: "modified_at", reverse: true},
+		{sortBy: "id"},
+		{sortBy: "id", reverse: true},
+	}
+	for _, sort := range sorts {
+		s.Run(fmt.Sprintf("%s reverse=%t", sort.sortBy, sort.reverse), func() {
+			all, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{ReturnAll: true, SortBy: sort.sortBy, SortReverse: sort.reverse})
+			s.Require().NoError(err)
+			s.Require().Len(all.Jobs, 5)
+			s.Nil(all.NextCursor)
+
+			query := jobstore.JobQuery{ReturnAll: true, SortBy: sort.sortBy, SortReverse: sort.reverse, Limit: 2}
+			var paged []models.Job
+			for page := 0; ; page++ {
+				s.Require().Less(page, 5, "too many pages")
+				response, err := s.Store.GetJobs(s.Ctx, query)
+				s.Require().NoError(err)
+				paged = append(paged, response.Jobs...)
+				if response.NextCursor == nil {
+					break
+				}
+
+				// jobs created between pages must not shift the next pages
+				job := makeDockerEngineJob([]string{"bash", "-c", "echo hello"})
+				job.ID = fmt.Sprintf("100-%d", page)
+				s.Require().NoError(s.Store.CreateJob(s.Ctx, *job, models.Event{}))
+				defer func() { s.Require().NoError(s.Store.DeleteJob(s.Ctx, job.ID)) }()
+				query.Cursor = response.NextCursor
+			}
+
+			// every job is returned once, and the jobs created while paging are only returned
+			// if they come after the cursor, so only the fixtures are compared
+			s.Len(lo.UniqBy(paged, func(j models.Job) string { return j.ID }), len(paged))
+			paged = lo.Filter(paged, func(job models.Job, _ int) bool {
+				return lo.ContainsBy(all.Jobs, func(j models.Job) bool { return j.ID == job.ID })
+			})
+			s.Equal(lo.Map(all.Jobs, func(j models.Job, _ int) string { return j.ID }),
+				lo.Map(paged, func(j models.Job, _ int) string { return j.ID }))
+		})
+	}
+}
+
+func (s *StoreSuite) TestDeleteJob() {
+	job := makeDockerEngineJob(
+		[]string{"bash", "-c", "echo hello"})
+	job.Labels = map[string]string{"tag": "value"}
+	job.ID = "deleteme"
+	job.Namespace = "client1"
+
+	err := s.Store.CreateJob(s.Ctx, *job, models.Event{})
+	s.Require().NoError(err)
+
+	err = s.Store.DeleteJob(s.Ctx, job.ID)
+	s.Require().NoError(err)
+}
+
+func (s *StoreSuite) TestGetJob() {
+	job, err := s.Store.GetJob(s.Ctx, "110")
+	s.Require().NoError(err)
+	s.NotNil(job)
+
+	_, err = s.Store.GetJob(s.Ctx, "100")
+	s.Require().Error(err)
+}
+
+func (s *StoreSuite) TestUpdateJob() {
+	existing, err := s.Store.GetJob(s.Ctx, "150")
+	s.Require().NoError(err)
+	s.Equal(uint64(1), existing.Version)
+
+	s.Clock.Add(1 * time.Second)
+	update := existing.Copy()
+	update.Labels = map[string]string{"version": "2"}
+	update.Task().Engine.Params["Image"] = "ubuntu:24.04"
+	s.Require().NoError(s.Store.UpdateJob(s.Ctx, *update, models.Event{Message: "updated"}))
+
+	job, err := s.Store.GetJob(s.Ctx, "150")
+	s.Require().NoError(err)
+	s.Equal(uint64(2), job.Version)
+	s.Equal(existing.Revision+1, job.Revision)
+	s.Equal(existing.State.StateType, job.State.StateType)
+	s.Equal(existing.CreateTime, job.CreateTime)
+	s.Equal("ubuntu:24.04", job.Task().Engine.Params["Image"])
+
+	// both the current and the previous versions are available
+	current, err := s.Store.GetJobVersion(s.Ctx, "150", 2)
+	s.Require().NoError(err)
+	s.Equal(job, current)
+	previous, err := s.Store.GetJobVersion(s.Ctx, "150", 1)
+	s.Require().NoError(err)
+	s.Equal(existing.Task().Engine.Params["Image"], previous.Task().Engine.Params["Image"])
+	_, err = s.Store.GetJobVersion(s.Ctx, "150", 3)
+	s.Require().ErrorAs(err, &jobstore.ErrJobVersionNotFound{})
+
+	// the labels of the new version are used to search jobs
+	response, err := s.Store.GetJobs(s.Ctx, jobstore.JobQuery{ReturnAll: true, IncludeTags: []string{"version"}})
+	s.Require().NoError(err)
+	s.Require().Len(response.Jobs, 1)
+	s.Equal("150", response.Jobs[0].ID)
+	response, err = s.Store.GetJobs(s.Ctx, jobstore.JobQuery{ReturnAll: true, IncludeTags: []string{"max"}})
+	s.Require().NoError(err)
+	s.NotContains(lo.Map(response.Jobs, func(j models.Job, _ int) string { return j.ID }), "150")
+
+	history, err := s.Store.GetJobHistory(s.Ctx, "150", jobstore.JobHistoryFilterOptions{ExcludeExecutionLevel: true})
+	s.Require().NoError(err)
+	s.Equal("updated", history[len(history)-1].Event.Message)
+
+	// terminal jobs and changes of type are rejected
+	update.Type = models.JobTypeService
+	s.Require().Error(s.Store.UpdateJob(s.Ctx, *update, models.Event{}))
+	stopped, err := s.Store.GetJob(s.Ctx, "110")
+	s.Require().NoError(err)
+	s.Require().Error(s.Store.UpdateJob(s.Ctx, stopped, models.Event{}))
+}
+
+func (s *StoreSuite) TestSearchJobsDependsOn() {
+	ids := func(query jobstore.JobQuery) []string {
+		response, err := s.Store.GetJobs(s.Ctx, query)
+		s.Require().NoError(err)
+		return lo.Map(response.Jobs, func(item models.Job, _ int) string { return item.ID })
+	}
+	createDependentJob := func(id string, upstreamIDs ...string) *models.Job {
+		s.Clock.Add(1 * time.Second)
+		job := makeDockerEngineJob([]string{"bash", "-c", "echo hello"})
+		job.ID = id
+		job.Type = models.JobTypeService
+		for _, upstreamID := range upstreamIDs {
+			job.Dependencies = append(job.Dependencies, &models.JobDependency{JobID: upstreamID})
+		}
+		s.Require().NoError(s.Store.CreateJob(s.Ctx, *job, models.Event{}))
+		return job
+	}
+	createDependentJob("160", "110")
+	both := createDependentJob("170", "110", "120")
+
+	s.Equal([]string{"160", "170"}, ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "110"}))
+	s.Equal([]string{"170"}, ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "120"}))
+	s.Equal([]string{"170"}, ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "110", Types: []string{models.JobTypeService}, Limit: 1, Offset: 1}))
+	s.Empty(ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "130"}))
+
+	// the dependencies of the new version are used to search jobs
+	update := both.Copy()
+	update.Dependencies = []*models.JobDependency{{JobID: "130"}}
+	s.Require().NoError(s.Store.UpdateJob(s.Ctx, *update, models.Event{}))
+	s.Equal([]string{"160"}, ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "110"}))
+	s.Empty(ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "120"}))
+	s.Equal([]string{"170"}, ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "130"}))
+
+	s.Require().NoError(s.Store.DeleteJob(s.Ctx, "160"))
+	s.Empty(ids(jobstore.JobQuery{ReturnAll: true, DependsOn: "110"}))
+}
+
+func (s *StoreSuite) TestCreateExecution() {
+	job := mock.Job()
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.Store.CreateJob(s.Ctx, *job, models.Event{}))
+	s.Require().NoError(s.Store.CreateExecution(s.Ctx, *execution, models.Event{}))
+
+	// Ensure that the execution is created
+	exec, err := s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID: job.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(exec))
+	s.Require().Nil(exec[0].Job)
+
+	// Ensure that the execution is created and the job is included
+	exec, err = s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID:      job.ID,
+		IncludeJob: true,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(exec))
+	s.Require().NotNil(exec[0].Job)
+	s.Require().Equal(job.ID, exec[0].Job.ID)
+}
+
+func (s *StoreSuite) TestGetExecutions() {
+	state, err := s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID: "110",
+	})
+	s.Require().NoError(err)
+	s.NotNil(state)
+	s.Equal(len(state), 1)
+	s.Nil(state[0].Job)
+
+	state, err = s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID:      "110",
+		IncludeJob: true,
+	})
+	s.Require().NoError(err)
+	s.NotNil(state)
+	s.Equal(len(state), 1)
+	s.NotNil(state[0].Job)
+	s.Equal("110", state[0].Job.ID)
+
+	state, err = s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID: "100",
+	})
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.JobNotFound{})
+	s.Require().Nil(state)
+
+	state, err = s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID: "11",
+	})
+	s.Require().NoError(err)
+	s.NotNil(state)
+	s.Require().Equal("110", state[0].JobID)
+
+	state, err = s.Store.GetExecutions(s.Ctx, jobstore.GetExecutionsOptions{
+		JobID: "1",
+	})
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.MultipleJobsFound{})
+	s.Require().Nil(state)
+
+}
+
+func (s *StoreSuite) TestInProgressJobs() {
+	infos, err := s.Store.GetInProgressJobs(s.Ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal(3, len(infos))
+	s.Require().Equal("130", infos[0].ID)
+
+	infos, err = s.Store.GetInProgressJobs(s.Ctx, "batch")
+	s.Require().NoError(err)
+	s.Require().Equal(2, len(infos))
+	s.Require().Equal("130", infos[0].ID)
+
+	infos, err = s.Store.GetInProgressJobs(s.Ctx, "daemon")
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(infos))
+	s.Require().Equal("150", infos[0].ID)
+}
+
+func (s *StoreSuite) TestShortIDs() {
+	uuidString := "9308d0d2-d93c-4e22-8a5b-c392e614922e"
+	uuidString2 := "9308d0d2-d93c-4e22-8a5b-c392e614922f"
+	shortString := "9308d0d2"
+
+	job := makeDockerEngineJob(
+		[]string{"bash", "-c", "echo hello"})
+	job.ID = uuidString
+	job.Namespace = "110"
+
+	// No matches
+	_, err := s.Store.GetJob(s.Ctx, shortString)
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.JobNotFound{})
+
+	// Create and fetch the single entry
+	err = s.Store.CreateJob(s.Ctx, *job, models.Event{})
+	s.Require().NoError(err)
+
+	j, err := s.Store.GetJob(s.Ctx, shortString)
+	s.Require().NoError(err)
+	s.Require().Equal(uuidString, j.ID)
+
+	// Add a record that will also match and expect an appropriate error
+	job.ID = uuidString2
+	err = s.Store.CreateJob(s.Ctx, *job, models.Event{})
+	s.Require().NoError(err)
+
+	_, err = s.Store.GetJob(s.Ctx, shortString)
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.MultipleJobsFound{})
+}
+
+func (s *StoreSuite) TestEvents() {
+	ch := s.Store.Watch(s.Ctx,
+		jobstore.JobWatcher|jobstore.ExecutionWatcher,
+		jobstore.CreateEvent|jobstore.UpdateEvent|jobstore.DeleteEvent,
+	)
+
+	job := makeDockerEngineJob(
+		[]string{"bash", "-c", "echo hello"})
+	job.ID = "10"
+	job.Namespace = "110"
+
+	var execution models.Execution
+
+	s.Run("job create event", func() {
+		err := s.Store.CreateJob(s.Ctx, *job, models.Event{})
+		s.Require().NoError(err)
+
+		// Read an event, it should be a jobcreate
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.CreateEvent)
+		s.Require().Equal(ev.Kind, jobstore.JobWatcher)
+
+		var decodedJob models.Job
+		err = json.Unmarshal(ev.Object, &decodedJob)
+		s.Require().NoError(err)
+		s.Require().Equal(decodedJob.ID, job.ID)
+	})
+
+	s.Run("execution create event", func() {
+		s.Clock.Add(1 * time.Second)
+		execution = *mock.Execution()
+		execution.JobID = "10"
+		execution.ComputeState = models.State[models.ExecutionStateType]{StateType: models.ExecutionStateNew}
+		err := s.Store.CreateExecution(s.Ctx, execution, models.Event{})
+		s.Require().NoError(err)
+
+		// Read an event, it should be a ExecutionForJob Create
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.CreateEvent)
+		s.Require().Equal(ev.Kind, jobstore.ExecutionWatcher)
+	})
+
+	s.Run("update job state event", func() {
+		request := jobstore.UpdateJobStateRequest{
+			JobID:    "10",
+			NewState: models.JobStateTypeRunning,
+			Condition: jobstore.UpdateJobCondition{
+				ExpectedState: models.JobStateTypePending,
+			},
+			Event: models.Event{Message: "event test"},
+		}
+		_ = s.Store.UpdateJobState(s.Ctx, request)
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.UpdateEvent)
+		s.Require().Equal(ev.Kind, jobstore.JobWatcher)
+	})
+
+	s.Run("update execution state event", func() {
+		execution.ComputeState.StateType = models.ExecutionStateAskForBid
+		execution.ModifyTime = s.Clock.Now().UTC().UnixNano()
+		s.Store.UpdateExecution(s.Ctx, jobstore.UpdateExecutionRequest{
+			ExecutionID: execution.ID,
+			Condition: jobstore.UpdateExecutionCondition{
+				ExpectedStates: []models.ExecutionStateType{models.ExecutionStateNew},
+			},
+			NewValues: execution,
+			Event:     models.Event{Message: "event test"},
+		})
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.UpdateEvent)
+		s.Require().Equal(ev.Kind, jobstore.ExecutionWatcher)
+
+		var decodedExecution models.Execution
+		err := json.Unmarshal(ev.Object, &decodedExecution)
+		s.Require().NoError(err)
+		s.Require().Equal(decodedExecution.ID, execution.ID)
+	})
+
+	s.Run("delete job event", func() {
+		_ = s.Store.DeleteJob(s.Ctx, job.ID)
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.DeleteEvent)
+		s.Require().Equal(ev.Kind, jobstore.JobWatcher)
+	})
+}
+
+func (s *StoreSuite) TestEvaluations() {
+
+	eval := models.Evaluation{
+		ID:    "e1",
+		JobID: "10",
+	}
+
+	// Wrong job ID means JobNotFound
+	err := s.Store.CreateEvaluation(s.Ctx, eval)
+	s.Require().Error(err)
+
+	// Correct job ID
+	eval.JobID = "110"
+	err = s.Store.CreateEvaluation(s.Ctx, eval)
+	s.Require().NoError(err)
+
+	_, err = s.Store.GetEvaluation(s.Ctx, "missing")
+	s.Require().Error(err)
+
+	e, err := s.Store.GetEvaluation(s.Ctx, eval.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(e, eval)
+
+	err = s.Store.DeleteEvaluation(s.Ctx, eval.ID)
+	s.Require().NoError(err)
+}
+
+func (s *StoreSuite) parseLabels(selector string) labels.Selector {
+	req, err := labels.ParseToRequirements(selector)
+	s.NoError(err)
+
+	return labels.NewSelector().Add(req...)
+}
+
+func makeDockerEngineJob(entrypointArray []string) *models.Job {
+	j := mock.Job()
+	j.Task().Engine = &models.SpecConfig{
+		Type: models.EngineDocker,
+		Params: map[string]interface{}{
+			"Image":      "ubuntu:latest",
+			"Entrypoint": entrypointArray,
+		},
+	}
+	return j
+}
@@ -3,6 +3,7 @@ package jobstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"k8s.io/apimachinery/pkg/labels"
@@ -21,6 +22,22 @@ type JobQuery struct {
 	SortBy      string
 	SortReverse bool
 	Selector    labels.Selector
+
+	// Cursor, if set, only returns the jobs that come after it in the sort order, and
+	// is used instead of Offset to page through jobs that are created or updated meanwhile.
+	Cursor *JobCursor
+
+	// States, Types and EngineTypes filter jobs that have ANY of the given values
+	States      []models.JobStateType
+	Types       []string
+	EngineTypes []string
+	// NamePrefix filters jobs whose name starts with the prefix
+	NamePrefix string
+	// DependsOn filters jobs that directly depend on the job with the given ID
+	DependsOn string
+	// CreatedAfter and CreatedBefore filter jobs created at or after, and strictly before, the given times
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
 }
 
 type JobQueryResponse struct {
@@ -28,6 +45,8 @@ type JobQueryResponse struct {
 	Offset     uint32 // Offset into the filtered results of the first returned record
 	Limit      uint32 // The number of records to return, 0 means all
 	NextOffset uint32 // Offset + Limit of the next page of results, 0 means no more results
+	// NextCursor is the position of the last returned job, and is only set if there are more results
+	NextCursor *JobCursor
 }
 
 // A Store will persist jobs and their state to the underlying storage.
@@ -68,6 +87,15 @@ type Store interface {
 	// CreateJob will create a new job and persist it in the store.
 	CreateJob(ctx context.Context, j models.Job, event models.Event) error
 
+	// UpdateJob updates the specification of an existing job, which becomes a new
+	// version of the job. The state of the job is kept, and the previous specification
+	// remains available as a previous version.
+	UpdateJob(ctx context.Context, j models.Job, event models.Event) error
+
+	// GetJobVersion returns the specification of a job at the given version, or an
+	// error if the version does not exist.
+	GetJobVersion(ctx context.Context, id string, version uint64) (models.Job, error)
+
 	// GetExecutions retrieves all executions for the specified job.
 	GetExecutions(ctx context.Context, options GetExecutionsOptions) ([]models.Execution, error)
 
@@ -92,6 +92,13 @@ func (s *ExponentialBackoffSuite) TestContextCancelledImmediately() {
 	s.InDelta(time.Duration(0), float64(elapsedTime), float64(s.delta))
 }
 
+func (s *ExponentialBackoffSuite) TestDuration() {
+	s.Equal(time.Duration(0), s.backoff.Duration(0))
+	s.Equal(s.baseBackoff, s.backoff.Duration(1))
+	s.Equal(8*s.baseBackoff, s.backoff.Duration(4))
+	s.Equal(s.maxBackoff, s.backoff.Duration(100))
+}
+
 func TestExponentialBackoffSuite(t *testing.T) {
 	suite.Run(t, new(ExponentialBackoffSuite))
 }
@@ -83,6 +83,15 @@ func (q *HashedPriorityQueue[K, T]) DequeueWhere(matcher MatchingFunction[T]) *Q
 	return item
 }
 
+// Peek returns the next highest priority item without removing it from
+// the queue, or nil if the queue is empty.
+func (q *HashedPriorityQueue[K, T]) Peek() *QueueItem[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.queue.Peek()
+}
+
 // Len returns the number of items currently in the queue
 func (q *HashedPriorityQueue[K, T]) Len() int {
 	return q.queue.Len()
@@ -35,6 +35,10 @@ type PriorityQueueInterface[T any] interface {
 	// extra PriorityQueue) for the dequeued items.
 	DequeueWhere(matcher MatchingFunction[T]) *QueueItem[T]
 
+	// Peek returns the next highest priority item without removing it from
+	// the queue, or nil if the queue is empty.
+	Peek() *QueueItem[T]
+
 	// Len returns the number of items currently in the queue
 	Len() int
 
@@ -162,6 +166,19 @@ func (pq *PriorityQueue[T]) DequeueWhere(matcher MatchingFunction[T]) *QueueItem
 	return result
 }
 
+// Peek returns the next highest priority item without removing it from
+// the queue, or nil if the queue is empty.
+func (pq *PriorityQueue[T]) Peek() *QueueItem[T] {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	if pq.IsEmpty() {
+		return nil
+	}
+	item, _ := pq.internalQueue[0].value.(T)
+	return &QueueItem[T]{Value: item, Priority: pq.internalQueue[0].priority}
+}
+
 // Len returns the number of items currently in the queue
 func (pq *PriorityQueue[T]) Len() int {
 	return pq.internalQueue.Len()
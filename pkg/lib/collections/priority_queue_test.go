@@ -78,6 +78,21 @@ func (s *PriorityQueueSuite) TestEmpty() {
 	s.Require().True(pq.IsEmpty())
 }
 
+func (s *PriorityQueueSuite) TestPeek() {
+	pq := collections.NewPriorityQueue[string]()
+	s.Require().Nil(pq.Peek())
+
+	pq.Enqueue("B", 2)
+	pq.Enqueue("A", 3)
+	pq.Enqueue("C", 1)
+
+	qitem := pq.Peek()
+	s.Require().NotNil(qitem)
+	s.Require().Equal("A", qitem.Value)
+	s.Require().Equal(int64(3), qitem.Priority)
+	s.Require().Equal(3, pq.Len())
+}
+
 func (s *PriorityQueueSuite) TestDequeueWhere() {
 	pq := collections.NewPriorityQueue[string]()
 	pq.Enqueue("A", 4)
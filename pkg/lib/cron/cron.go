@@ -0,0 +1,239 @@
+// Package cron parses standard five field cron expressions and computes their activation times.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search for the next activation time, so that
+// expressions that can never be satisfied, such as 30th of February, terminate.
+const maxSearchYears = 5
+
+// field describes the bounds and accepted names of a cron field
+type field struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// day of week accepts 7 as an alias for sunday
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors are the predefined schedules that can be used in place of an expression
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a parsed cron expression. Each field is represented as a bitset
+// of the values it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the day fields are unrestricted, which changes
+	// how days are matched: when both are restricted, a day matches if either matches.
+	domStar, dowStar bool
+}
+
+// Parse parses a standard cron expression with five space separated fields:
+// minute, hour, day of month, month and day of week. Each field accepts '*',
+// single values, ranges (1-5), steps (*/15, 1-30/2) and comma separated lists.
+// Months and days of week also accept their three letter english names.
+// The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are also supported.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty cron expression")
+	}
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unrecognized cron descriptor %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, found %d", spec, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, _, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, _, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, s.domStar, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, _, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, s.dowStar, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	// fold 7 into sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// parseField parses a comma separated list of ranges, and returns the bitset of
+// matching values and whether the field is unrestricted.
+func parseField(expr string, f field) (uint64, bool, error) {
+	var result uint64
+	star := false
+	for _, part := range strings.Split(expr, ",") {
+		bitset, isStar, err := parseRange(part, f)
+		if err != nil {
+			return 0, false, err
+		}
+		result |= bitset
+		star = star || isStar
+	}
+	return result, star, nil
+}
+
+// parseRange parses a single element of a cron field, such as *, 5, 1-5 or */15.
+func parseRange(expr string, f field) (uint64, bool, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(expr, "/")
+	step := uint(1)
+	if hasStep {
+		var err error
+		if step, err = parseValue(stepExpr, field{name: f.name, min: 1, max: f.max}); err != nil {
+			return 0, false, fmt.Errorf("invalid step in %s field %q: %w", f.name, expr, err)
+		}
+	}
+
+	var start, end uint
+	star := false
+	switch {
+	case rangeExpr == "*" || rangeExpr == "?":
+		start, end = f.min, f.max
+		if f.name == dowField.name {
+			// avoid matching sunday twice
+			end = 6
+		}
+		star = !hasStep
+	case strings.Contains(rangeExpr, "-"):
+		low, high, _ := strings.Cut(rangeExpr, "-")
+		var err error
+		if start, err = parseValue(low, f); err != nil {
+			return 0, false, err
+		}
+		if end, err = parseValue(high, f); err != nil {
+			return 0, false, err
+		}
+		if start > end {
+			return 0, false, fmt.Errorf("invalid range in %s field %q: start is after end", f.name, expr)
+		}
+	default:
+		var err error
+		if start, err = parseValue(rangeExpr, f); err != nil {
+			return 0, false, err
+		}
+		end = start
+		if hasStep {
+			// a single value with a step, such as 5/15, runs from the value to the maximum
+			end = f.max
+		}
+	}
+
+	var bitset uint64
+	for i := start; i <= end; i += step {
+		bitset |= 1 << i
+	}
+	return bitset, star, nil
+}
+
+// parseValue parses a single numeric or named value of a field
+func parseValue(expr string, f field) (uint, error) {
+	if value, ok := f.names[strings.ToLower(expr)]; ok {
+		return value, nil
+	}
+	value, err := strconv.ParseUint(expr, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s field", expr, f.name)
+	}
+	if uint(value) < f.min || uint(value) > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d] for %s field", value, f.min, f.max, f.name)
+	}
+	return uint(value), nil
+}
+
+// Next returns the first activation time strictly after the given time, in the
+// location of the given time. Activation times that do not exist in that location
+// because of a daylight saving time transition are skipped. It returns the zero time
+// if no activation time can be found within the next few years, such as for the 30th
+// of February.
+func (s *Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	// start from the next whole minute
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+	for t.Year() <= yearLimit {
+		if !matches(s.month, uint(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !matches(s.hour, uint(t.Hour())) {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// daylight saving time transitions can map the next hour back onto the current one
+				next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			}
+			t = next
+			continue
+		}
+		if !matches(s.minute, uint(t.Minute())) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches returns true if the day of the given time matches the day of month
+// and day of week fields. Following the standard cron behaviour, if both fields are
+// restricted the day matches when either of them matches.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := matches(s.dom, uint(t.Day()))
+	dowMatch := matches(s.dow, uint(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func matches(bitset uint64, value uint) bool {
+	return bitset&(1<<value) != 0
+}
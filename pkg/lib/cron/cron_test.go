@@ -0,0 +1,82 @@
+//go:build unit || !integration
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"foo * * * *",
+		"@every",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, "expected error for %q", spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		after    string
+		expected string
+	}{
+		{"* * * * *", "2024-01-01T10:00:30Z", "2024-01-01T10:01:00Z"},
+		{"*/15 * * * *", "2024-01-01T10:01:00Z", "2024-01-01T10:15:00Z"},
+		{"0 2 * * *", "2024-01-01T02:00:00Z", "2024-01-02T02:00:00Z"},
+		{"30 9 * * mon-fri", "2024-01-05T10:00:00Z", "2024-01-08T09:30:00Z"},
+		{"0 0 1 jan *", "2024-06-01T00:00:00Z", "2025-01-01T00:00:00Z"},
+		{"0 0 29 2 *", "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"0 0 * * 7", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		// day of month and day of week are OR'ed when both are restricted
+		{"0 0 15 * sun", "2024-01-08T00:00:00Z", "2024-01-14T00:00:00Z"},
+		{"0 0 15 * sun", "2024-01-14T00:00:00Z", "2024-01-15T00:00:00Z"},
+		{"5/20 1,3 * * *", "2024-01-01T01:45:00Z", "2024-01-01T03:05:00Z"},
+		{"@daily", "2024-01-01T12:00:00Z", "2024-01-02T00:00:00Z"},
+		{"@hourly", "2024-12-31T23:30:00Z", "2025-01-01T00:00:00Z"},
+	} {
+		schedule, err := Parse(tc.spec)
+		require.NoError(t, err, tc.spec)
+		after, err := time.Parse(time.RFC3339, tc.after)
+		require.NoError(t, err)
+		expected, err := time.Parse(time.RFC3339, tc.expected)
+		require.NoError(t, err)
+		assert.Equal(t, expected, schedule.Next(after), "%s after %s", tc.spec, tc.after)
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	schedule, err := Parse("0 2 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2024, 1, 9, 12, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2024, 1, 10, 2, 0, 0, 0, loc), next)
+
+	// 2am does not exist on the day daylight saving time starts, and is skipped
+	next = schedule.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2024, 3, 11, 2, 0, 0, 0, loc), next)
+	assert.Equal(t, loc, next.Location())
+}
+
+func TestNextNeverMatches(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
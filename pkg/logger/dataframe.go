@@ -39,21 +39,23 @@ type DataFrame struct {
 func NewDataFrameFromReader(reader io.Reader) (*DataFrame, error) {
 	header := make([]byte, HeaderLength)
 
-	n, err := reader.Read(header)
+	// the reader may return less than a full frame per read, e.g. when frames are
+	// streamed over the network, so read until the header is complete
+	n, err := io.ReadFull(reader, header)
+	if err == io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("unable to read dataframe header")
+	}
 	if err != nil {
 		return nil, err
 	}
-	if n != HeaderLength {
-		return nil, fmt.Errorf("unable to read dataframe header")
-	}
 
 	df := &DataFrame{}
 	df.Tag = StreamTag(binary.LittleEndian.Uint32(header))
 	df.Size = int(binary.BigEndian.Uint32(header[4:]))
 	df.Data = make([]byte, df.Size)
 
-	n, err = reader.Read(df.Data)
-	if err != nil {
+	n, err = io.ReadFull(reader, df.Data)
+	if err != nil && err != io.ErrUnexpectedEOF {
 		return nil, err
 	}
 	if n != df.Size {
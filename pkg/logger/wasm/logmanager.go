@@ -147,6 +147,10 @@ func (lm *LogManager) Drain() {
 		lm.processItem(m)
 	}
 
+	// Nothing else will be written, so end the streams of readers that
+	// are following the logs
+	lm.broadcaster.Close()
+
 	// Ask the file to sync to disk
 	_ = lm.file.Sync()
 
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// maxAPIKeyNameLength is the maximum length of the name of an API key
+const maxAPIKeyNameLength = 128
+
+// apiKeyNamePattern is the pattern the names of API keys must match
+var apiKeyNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// APIKeyAllNamespaces is the namespace that grants the permissions of an API key in all namespaces
+const APIKeyAllNamespaces = "*"
+
+// APIKeyPermission is an access that an API key grants in its namespaces
+type APIKeyPermission string
+
+const (
+	// APIKeyPermissionRead allows reading and downloading the results of jobs
+	APIKeyPermissionRead APIKeyPermission = "read"
+	// APIKeyPermissionWrite allows submitting and cancelling jobs
+	APIKeyPermissionWrite APIKeyPermission = "write"
+)
+
+// APIKeyPermissions returns the permissions that API keys can grant
+func APIKeyPermissions() []APIKeyPermission {
+	return []APIKeyPermission{APIKeyPermissionRead, APIKeyPermissionWrite}
+}
+
+// APIKey is a long-lived credential issued by the requester, typically to service
+// accounts such as CI pipelines that cannot run an interactive authentication flow.
+// Only a hash of the secret of the key is stored, so the key itself is only known
+// when it is issued.
+type APIKey struct {
+	// ID identifies the key, and is part of the key itself
+	ID string `json:"ID"`
+
+	// Name is the unique name the key is managed by
+	Name string `json:"Name"`
+
+	// Namespaces are the namespaces the key grants access to, or "*" for all namespaces
+	Namespaces []string `json:"Namespaces"`
+
+	// Permissions are the accesses the key grants in its namespaces
+	Permissions []APIKeyPermission `json:"Permissions"`
+
+	// Hash is the hash of the secret of the key. It is never returned by the APIs.
+	Hash string `json:"Hash,omitempty"`
+
+	CreateTime int64 `json:"CreateTime"`
+
+	// ExpireTime is when the key stops being accepted, or zero if it never expires
+	ExpireTime int64 `json:"ExpireTime,omitempty"`
+
+	// LastUsedTime is when the key was last accepted, or zero if it was never used.
+	// It is only updated about once a minute.
+	LastUsedTime int64 `json:"LastUsedTime,omitempty"`
+}
+
+// Normalize normalizes the API key
+func (k *APIKey) Normalize() {
+	if k == nil {
+		return
+	}
+	k.Name = strings.TrimSpace(k.Name)
+	for i := range k.Namespaces {
+		k.Namespaces[i] = strings.TrimSpace(k.Namespaces[i])
+	}
+	for i := range k.Permissions {
+		k.Permissions[i] = APIKeyPermission(strings.ToLower(strings.TrimSpace(string(k.Permissions[i]))))
+	}
+	slices.Sort(k.Namespaces)
+	k.Namespaces = slices.Compact(k.Namespaces)
+	slices.Sort(k.Permissions)
+	k.Permissions = slices.Compact(k.Permissions)
+}
+
+// Copy returns a copy of the API key
+func (k *APIKey) Copy() *APIKey {
+	if k == nil {
+		return nil
+	}
+	nk := new(APIKey)
+	*nk = *k
+	nk.Namespaces = slices.Clone(k.Namespaces)
+	nk.Permissions = slices.Clone(k.Permissions)
+	return nk
+}
+
+// Redacted returns a copy of the API key without its hash
+func (k *APIKey) Redacted() *APIKey {
+	if k == nil {
+		return nil
+	}
+	nk := k.Copy()
+	nk.Hash = ""
+	return nk
+}
+
+// IsExpired returns true if the key is no longer accepted at the given time
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpireTime != 0 && now.UnixNano() >= k.ExpireTime
+}
+
+// HasPermission returns true if the key grants the permission
+func (k *APIKey) HasPermission(permission APIKeyPermission) bool {
+	return slices.Contains(k.Permissions, permission)
+}
+
+// Validate returns an error if the API key is invalid
+func (k *APIKey) Validate() error {
+	if k == nil {
+		return errors.New("missing API key")
+	}
+	var mErr error
+	if validate.IsBlank(k.ID) {
+		mErr = errors.Join(mErr, errors.New("missing API key ID"))
+	}
+	mErr = errors.Join(mErr, ValidateAPIKeyName(k.Name))
+	if len(k.Namespaces) == 0 {
+		mErr = errors.Join(mErr, errors.New("API key must grant access to at least one namespace"))
+	}
+	for _, namespace := range k.Namespaces {
+		if validate.IsBlank(namespace) {
+			mErr = errors.Join(mErr, errors.New("API key namespace is blank"))
+		} else if validate.ContainsSpaces(namespace) {
+			mErr = errors.Join(mErr, fmt.Errorf("API key namespace %q contains whitespace", namespace))
+		}
+	}
+	if len(k.Permissions) == 0 {
+		mErr = errors.Join(mErr, errors.New("API key must grant at least one permission"))
+	}
+	for _, permission := range k.Permissions {
+		if !slices.Contains(APIKeyPermissions(), permission) {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid API key permission %q: must be one of %v",
+				permission, APIKeyPermissions()))
+		}
+	}
+	if k.ExpireTime != 0 && k.ExpireTime <= k.CreateTime {
+		mErr = errors.Join(mErr, errors.New("API key must expire after it is created"))
+	}
+	return mErr
+}
+
+// ValidateAPIKeyName returns an error if the name is not a valid API key name
+func ValidateAPIKeyName(name string) error {
+	if validate.IsBlank(name) {
+		return errors.New("missing API key name")
+	}
+	if len(name) > maxAPIKeyNameLength {
+		return fmt.Errorf("API key name %s is longer than %d characters", name, maxAPIKeyNameLength)
+	}
+	if !apiKeyNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid API key name %s: must start with a letter or a digit, "+
+			"and only contain letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
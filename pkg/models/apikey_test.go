@@ -0,0 +1,55 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Validate(t *testing.T) {
+	valid := func() APIKey {
+		return APIKey{
+			ID:          "0123456789abcdef",
+			Name:        "github-actions",
+			Namespaces:  []string{"ci"},
+			Permissions: []APIKeyPermission{APIKeyPermissionRead, APIKeyPermissionWrite},
+			CreateTime:  1,
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(*APIKey)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*APIKey) {}},
+		{name: "all-namespaces", modify: func(k *APIKey) { k.Namespaces = []string{APIKeyAllNamespaces} }},
+		{name: "missing-id", modify: func(k *APIKey) { k.ID = "" }, wantErr: true},
+		{name: "invalid-name", modify: func(k *APIKey) { k.Name = "ci/key" }, wantErr: true},
+		{name: "no-namespaces", modify: func(k *APIKey) { k.Namespaces = nil }, wantErr: true},
+		{name: "namespace-with-space", modify: func(k *APIKey) { k.Namespaces = []string{"c i"} }, wantErr: true},
+		{name: "no-permissions", modify: func(k *APIKey) { k.Permissions = nil }, wantErr: true},
+		{name: "unknown-permission", modify: func(k *APIKey) { k.Permissions = []APIKeyPermission{"admin"} }, wantErr: true},
+		{name: "expires-before-creation", modify: func(k *APIKey) { k.ExpireTime = 1 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := valid()
+			tt.modify(&key)
+			if tt.wantErr {
+				assert.Error(t, key.Validate())
+			} else {
+				assert.NoError(t, key.Validate())
+			}
+		})
+	}
+}
+
+func TestAPIKey_IsExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&APIKey{}).IsExpired(now))
+	assert.False(t, (&APIKey{ExpireTime: now.Add(time.Second).UnixNano()}).IsExpired(now))
+	assert.True(t, (&APIKey{ExpireTime: now.UnixNano()}).IsExpired(now))
+}
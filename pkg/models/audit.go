@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// AuditOutcome is the result of an audited action
+type AuditOutcome string
+
+const (
+	// AuditOutcomeSuccess is the outcome of actions that were carried out
+	AuditOutcomeSuccess AuditOutcome = "success"
+	// AuditOutcomeDenied is the outcome of actions that the principal was not authorized to carry out
+	AuditOutcomeDenied AuditOutcome = "denied"
+	// AuditOutcomeFailure is the outcome of actions that were authorized but failed
+	AuditOutcomeFailure AuditOutcome = "failure"
+)
+
+// AuditOutcomes returns the outcomes of audited actions
+func AuditOutcomes() []AuditOutcome {
+	return []AuditOutcome{AuditOutcomeSuccess, AuditOutcomeDenied, AuditOutcomeFailure}
+}
+
+// The principals of actions whose principal is not identified by a verified token
+const (
+	// AuditPrincipalAnonymous is the principal of requests without an access token
+	AuditPrincipalAnonymous = "anonymous"
+	// AuditPrincipalUnknown is the principal of requests with an access token that
+	// could not be verified, such as tokens signed elsewhere or invalid tokens
+	AuditPrincipalUnknown = "unknown"
+	// AuditPrincipalSystem is the principal of actions that the requester carries
+	// out on its own, rather than for an API request
+	AuditPrincipalSystem = "system"
+)
+
+// The actions of the node manager that are audited
+const (
+	AuditActionNodeApprove  = "node.approve"
+	AuditActionNodeReject   = "node.reject"
+	AuditActionNodeDelete   = "node.delete"
+	AuditActionNodeCordon   = "node.cordon"
+	AuditActionNodeDrain    = "node.drain"
+	AuditActionNodeUncordon = "node.uncordon"
+)
+
+// AuditEvent records who carried out an action on the requester, on what, and
+// with what outcome. Audit events are only ever appended to the audit log.
+type AuditEvent struct {
+	// ID is the position of the event in the audit log, assigned when it is recorded
+	ID uint64 `json:"ID"`
+
+	// Time is when the action was carried out, in unix nanoseconds
+	Time int64 `json:"Time"`
+
+	// Principal is who carried out the action, which is the subject of their
+	// access token such as a user or "apikey:<name>"
+	Principal string `json:"Principal"`
+
+	// Action is what was done, such as "PUT /api/v1/orchestrator/jobs" for API
+	// requests or "node.approve" for node actions
+	Action string `json:"Action"`
+
+	// Target is what the action was carried out on, such as the ID of a job or a
+	// node, or the path of the request if it did not act on a job
+	Target string `json:"Target"`
+
+	// RequestID is the ID of the API request that the action was part of
+	RequestID string `json:"RequestID,omitempty"`
+
+	Outcome AuditOutcome `json:"Outcome"`
+
+	// StatusCode is the HTTP status of the response to API requests
+	StatusCode int `json:"StatusCode,omitempty"`
+
+	// Reason is the reason given for the action, or why it was denied or failed
+	Reason string `json:"Reason,omitempty"`
+}
+
+// Validate returns an error if the audit event is invalid
+func (e *AuditEvent) Validate() error {
+	if e == nil {
+		return errors.New("missing audit event")
+	}
+	var mErr error
+	if e.Time <= 0 {
+		mErr = errors.Join(mErr, errors.New("missing audit event time"))
+	}
+	if validate.IsBlank(e.Principal) {
+		mErr = errors.Join(mErr, errors.New("missing audit event principal"))
+	}
+	if validate.IsBlank(e.Action) {
+		mErr = errors.Join(mErr, errors.New("missing audit event action"))
+	}
+	if !slices.Contains(AuditOutcomes(), e.Outcome) {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid audit event outcome %q: must be one of %v",
+			e.Outcome, AuditOutcomes()))
+	}
+	return mErr
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+const (
+	// DefaultCheckpointInterval is how often, in seconds, the checkpoint of a task is
+	// published if its interval is not set.
+	DefaultCheckpointInterval = int64(10 * time.Minute / time.Second)
+
+	// CheckpointResultName is the name of the result directory the checkpoint of a task
+	// is written to on the compute node.
+	CheckpointResultName = "checkpoint"
+
+	// CheckpointInputAlias is the alias of the input source an execution resumes its
+	// checkpoint from.
+	CheckpointInputAlias = "bacalhau-checkpoint"
+)
+
+// CheckpointConfig enables the checkpoints of a task. The compute node periodically
+// publishes the checkpoint directory of the task using the task's publisher, and when
+// the task is rescheduled the new execution starts from the latest published checkpoint.
+type CheckpointConfig struct {
+	// Path is the directory of the task holding its checkpoint.
+	Path string `json:"Path"`
+
+	// Interval is how often the checkpoint is published in seconds.
+	// Defaults to DefaultCheckpointInterval.
+	Interval int64 `json:"Interval,omitempty"`
+}
+
+// GetInterval returns the interval at which the checkpoint is published
+func (c *CheckpointConfig) GetInterval() time.Duration {
+	return time.Duration(c.Interval) * time.Second
+}
+
+// Normalize applies defaults to the checkpoint config
+func (c *CheckpointConfig) Normalize() {
+	if c == nil {
+		return
+	}
+	if c.Interval == 0 {
+		c.Interval = DefaultCheckpointInterval
+	}
+}
+
+// Copy returns a deep copy of the checkpoint config
+func (c *CheckpointConfig) Copy() *CheckpointConfig {
+	if c == nil {
+		return nil
+	}
+	return &CheckpointConfig{
+		Path:     c.Path,
+		Interval: c.Interval,
+	}
+}
+
+// Validate is used to check a checkpoint config for reasonable configuration
+func (c *CheckpointConfig) Validate() error {
+	if c == nil {
+		return errors.New("empty/nil checkpoint config")
+	}
+	var mErr error
+	if validate.IsBlank(c.Path) {
+		mErr = errors.Join(mErr, errors.New("checkpoint path is blank"))
+	} else if !path.IsAbs(c.Path) {
+		mErr = errors.Join(mErr, fmt.Errorf("checkpoint path %s must be absolute", c.Path))
+	}
+	if c.Interval < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid checkpoint interval value: %s", c.GetInterval()))
+	}
+	return mErr
+}
+
+// ResultPath returns the result path the checkpoint is written to
+func (c *CheckpointConfig) ResultPath() *ResultPath {
+	return &ResultPath{Name: CheckpointResultName, Path: c.Path}
+}
+
+// ExecutionCheckpoint is a checkpoint published by an execution
+type ExecutionCheckpoint struct {
+	// Source is where the checkpoint was published to
+	Source *SpecConfig `json:"Source"`
+
+	// CreateTime is the time the checkpoint was taken
+	CreateTime int64 `json:"CreateTime"`
+}
+
+// GetCreateTime returns the time the checkpoint was taken
+func (c *ExecutionCheckpoint) GetCreateTime() time.Time {
+	return time.Unix(0, c.CreateTime).UTC()
+}
+
+// Copy returns a deep copy of the checkpoint
+func (c *ExecutionCheckpoint) Copy() *ExecutionCheckpoint {
+	if c == nil {
+		return nil
+	}
+	return &ExecutionCheckpoint{
+		Source:     c.Source.Copy(),
+		CreateTime: c.CreateTime,
+	}
+}
+
+// InputSource returns the input source an execution resumes from this checkpoint with
+func (c *ExecutionCheckpoint) InputSource(config *CheckpointConfig) *InputSource {
+	return &InputSource{
+		Source: c.Source.Copy(),
+		Alias:  CheckpointInputAlias,
+		Target: config.Path,
+	}
+}
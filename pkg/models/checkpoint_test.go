@@ -0,0 +1,102 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_ValidateCheckpoint(t *testing.T) {
+	newTask := func() *Task {
+		return &Task{
+			Name:        "main",
+			Engine:      &SpecConfig{Type: EngineDocker},
+			Publisher:   &SpecConfig{Type: PublisherS3},
+			ResultPaths: []*ResultPath{{Name: "outputs", Path: "/outputs"}},
+			Checkpoint:  &CheckpointConfig{Path: "/checkpoint"},
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(task *Task)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			modify: func(task *Task) {},
+		},
+		{
+			name:    "relative-path",
+			modify:  func(task *Task) { task.Checkpoint.Path = "checkpoint" },
+			wantErr: true,
+		},
+		{
+			name:    "blank-path",
+			modify:  func(task *Task) { task.Checkpoint.Path = " " },
+			wantErr: true,
+		},
+		{
+			name:    "negative-interval",
+			modify:  func(task *Task) { task.Checkpoint.Interval = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "no-publisher",
+			modify:  func(task *Task) { task.Publisher = nil },
+			wantErr: true,
+		},
+		{
+			name:    "reserved-result-name",
+			modify:  func(task *Task) { task.ResultPaths[0].Name = CheckpointResultName },
+			wantErr: true,
+		},
+		{
+			name:    "result-at-checkpoint-path",
+			modify:  func(task *Task) { task.ResultPaths[0].Path = "/checkpoint" },
+			wantErr: true,
+		},
+		{
+			name: "reserved-input-alias",
+			modify: func(task *Task) {
+				task.InputSources = []*InputSource{{Source: &SpecConfig{Type: StorageSourceURL}, Alias: CheckpointInputAlias, Target: "/inputs"}}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask()
+			tt.modify(task)
+			err := task.ValidateSubmission()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckpointConfig_Normalize(t *testing.T) {
+	config := &CheckpointConfig{Path: "/checkpoint"}
+	config.Normalize()
+	assert.Equal(t, DefaultCheckpointInterval, config.Interval)
+
+	config = &CheckpointConfig{Path: "/checkpoint", Interval: 60}
+	config.Normalize()
+	assert.Equal(t, int64(60), config.Interval)
+}
+
+func TestExecutionCheckpoint_InputSource(t *testing.T) {
+	checkpoint := &ExecutionCheckpoint{
+		Source:     &SpecConfig{Type: StorageSourceS3, Params: map[string]interface{}{"Key": "checkpoint.tar.gz"}},
+		CreateTime: 1,
+	}
+	input := checkpoint.InputSource(&CheckpointConfig{Path: "/checkpoint"})
+	assert.Equal(t, CheckpointInputAlias, input.Alias)
+	assert.Equal(t, "/checkpoint", input.Target)
+	assert.Equal(t, checkpoint.Source, input.Source)
+	assert.NotSame(t, checkpoint.Source, input.Source)
+}
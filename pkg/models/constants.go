@@ -21,12 +21,17 @@ const (
 	// JobTypeOps represents a batch job that runs to completion on all nodes matching
 	// the specified constraints.
 	JobTypeOps = "ops"
+
+	// JobTypeScheduled represents a job that creates a batch job run each time
+	// its cron schedule is due.
+	JobTypeScheduled = "scheduled"
 )
 
 const (
-	EngineNoop   = "noop"
-	EngineDocker = "docker"
-	EngineWasm   = "wasm"
+	EngineNoop    = "noop"
+	EngineDocker  = "docker"
+	EngineWasm    = "wasm"
+	EngineProcess = "process"
 )
 
 const (
@@ -58,6 +63,8 @@ const (
 	DownloadFilenameStderr   = "stderr"
 	DownloadFilenameExitCode = "exitCode"
 	DownloadCIDsFolderName   = "raw"
+	DownloadLogsFolderName   = "logs"
+	DownloadFilenameLogs     = "execution.log"
 	DownloadFolderPerm       = 0755
 	DownloadFilePerm         = 0644
 )
@@ -71,4 +78,9 @@ const (
 	// it may have been translated from another job.
 	MetaDerivedFrom  = "bacalhau.org/derivedFrom"
 	MetaTranslatedBy = "bacalhau.org/translatedBy"
+
+	// Scheduled run metadata used to track the scheduled job that created a run,
+	// and the time the run was due.
+	MetaScheduledBy   = "bacalhau.org/scheduledBy"
+	MetaScheduledTime = "bacalhau.org/scheduledTime"
 )
@@ -1,5 +1,5 @@
 package models
 
 func IsDefaultEngineType(kind string) bool {
-	return kind == EngineDocker || kind == EngineNoop || kind == EngineWasm
+	return kind == EngineDocker || kind == EngineNoop || kind == EngineWasm || kind == EngineProcess
 }
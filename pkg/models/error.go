@@ -7,6 +7,8 @@ const (
 	DetailsKeyHint           = "Hint"
 	DetailsKeyRetryable      = "Retryable"
 	DetailsKeyFailsExecution = "FailsExecution"
+	DetailsKeyPreempted      = "Preempted"
+	DetailsKeyStopReason     = "StopReason"
 )
 
 type HasHint interface {
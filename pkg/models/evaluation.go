@@ -20,6 +20,11 @@ const (
 	EvalTriggerJobCancel       = "job-cancel"
 	EvalTriggerRetryFailedExec = "exec-failure"
 	EvalTriggerExecUpdate      = "exec-update"
+	EvalTriggerJobDependency   = "job-dependency"
+	EvalTriggerJobSchedule     = "job-schedule"
+	EvalTriggerJobQuota        = "job-quota"
+	EvalTriggerNodeUpdate      = "node-update"
+	EvalTriggerJobUpdate       = "job-update"
 )
 
 // Evaluation is just to ask the scheduler to reassess if additional job instances must be
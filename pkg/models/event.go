@@ -85,6 +85,11 @@ func (e *Event) WithFailsExecution(failsExecution bool) *Event {
 	return e
 }
 
+// StopReason returns the reason the event stops executions for, if any
+func (e Event) StopReason() ExecutionStopReason {
+	return ExecutionStopReason(e.Details[DetailsKeyStopReason])
+}
+
 // WithDetails returns a new Event with the given details and topic.
 func (e *Event) WithDetails(details map[string]string) *Event {
 	maps.Copy(e.Details, details)
@@ -57,6 +57,22 @@ const (
 	ExecutionDesiredStateStopped
 )
 
+// ExecutionStopReason is why an execution was stopped, for the reasons that schedulers
+// act on when deciding whether and how to replace stopped executions.
+type ExecutionStopReason string
+
+const (
+	// ExecutionStopReasonNodeLost is the reason of executions stopped as their node was lost
+	ExecutionStopReasonNodeLost ExecutionStopReason = "NodeLost"
+	// ExecutionStopReasonPreempted is the reason of executions stopped by their compute
+	// node to make room for higher priority executions
+	ExecutionStopReasonPreempted ExecutionStopReason = "Preempted"
+	// ExecutionStopReasonNodeDrained is the reason of executions stopped as their node was drained
+	ExecutionStopReasonNodeDrained ExecutionStopReason = "NodeDrained"
+	// ExecutionStopReasonHealthCheck is the reason of executions stopped as they failed their health check
+	ExecutionStopReasonHealthCheck ExecutionStopReason = "HealthCheck"
+)
+
 // Execution is used to allocate the placement of a task group to a node.
 type Execution struct {
 	// ID of the execution (UUID)
@@ -81,18 +97,37 @@ type Execution struct {
 	// TODO: evaluate using a copy of the job instead of a pointer
 	Job *Job `json:"Job,omitempty"`
 
+	// JobVersion is the version of the job this execution runs.
+	JobVersion uint64 `json:"JobVersion,omitempty"`
+
+	// ArrayIndex is the index of the parameter matrix run by this execution, for array jobs.
+	ArrayIndex int `json:"ArrayIndex,omitempty"`
+
 	// AllocatedResources is the total resources allocated for the execution tasks.
 	AllocatedResources *AllocatedResources `json:"AllocatedResources"`
 
 	// DesiredState of the execution on the compute node
 	DesiredState State[ExecutionDesiredStateType] `json:"DesiredState"`
 
+	// StopReason is why the execution was stopped, if it was stopped for a reason
+	// that schedulers act on
+	StopReason ExecutionStopReason `json:"StopReason,omitempty"`
+
 	// ComputeState observed state of the execution on the compute node
 	ComputeState State[ExecutionStateType] `json:"ComputeState"`
 
 	// the published results for this execution
 	PublishedResult *SpecConfig `json:"PublishedResult"`
 
+	// Checkpoint is the latest checkpoint published by this execution
+	Checkpoint *ExecutionCheckpoint `json:"Checkpoint,omitempty"`
+
+	// Health is the health of the execution, if its task has a health check
+	Health *ExecutionHealth `json:"Health,omitempty"`
+
+	// Ports are the ports of the task published by the execution
+	Ports []*ExecutionPort `json:"Ports,omitempty"`
+
 	// RunOutput is the output of the run command
 	// TODO: evaluate removing this from execution spec in favour of calling `bacalhau logs`
 	RunOutput *RunCommandResult `json:"RunOutput"`
@@ -115,6 +150,9 @@ type Execution struct {
 	CreateTime int64 `json:"CreateTime"`
 	// ModifyTime is the time the execution was last updated.
 	ModifyTime int64 `json:"ModifyTime"`
+	// StartTime is the time the execution started running on its compute node,
+	// which is when its bid was accepted.
+	StartTime int64 `json:"StartTime,omitempty"`
 }
 
 func (e *Execution) String() string {
@@ -135,6 +173,15 @@ func (e *Execution) GetModifyTime() time.Time {
 	return time.Unix(0, e.ModifyTime).UTC()
 }
 
+// GetStartTime returns the time the execution started running, or the zero time
+// if it has not started yet
+func (e *Execution) GetStartTime() time.Time {
+	if e.StartTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, e.StartTime).UTC()
+}
+
 // Normalize Allocation to ensure fields are initialized to the expectations
 // of this version of Bacalhau. Should be called when restoring persisted
 // Executions or receiving Executions from Bacalhau clients potentially on an
@@ -162,9 +209,22 @@ func (e *Execution) Copy() *Execution {
 	na.Job = na.Job.Copy()
 	na.AllocatedResources = na.AllocatedResources.Copy()
 	na.PublishedResult = na.PublishedResult.Copy()
+	na.Checkpoint = na.Checkpoint.Copy()
+	na.Health = na.Health.Copy()
+	if e.Ports != nil {
+		na.Ports = make([]*ExecutionPort, len(e.Ports))
+		for i, port := range e.Ports {
+			na.Ports[i] = port.Copy()
+		}
+	}
 	return na
 }
 
+// IsUnhealthy returns true if the execution failed its health check
+func (e *Execution) IsUnhealthy() bool {
+	return e.Health.IsUnhealthy()
+}
+
 // Validate is used to check a job for reasonable configuration
 func (e *Execution) Validate() error {
 	var mErr error
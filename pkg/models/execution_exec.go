@@ -0,0 +1,26 @@
+package models
+
+// TerminalSize is the size of the terminal of a command executed inside an execution
+type TerminalSize struct {
+	Height uint
+	Width  uint
+}
+
+// ExecInput is sent to a command executed inside a running execution
+type ExecInput struct {
+	// Data is written to the standard input of the command
+	Data []byte `json:",omitempty"`
+	// Resize changes the size of the terminal of the command
+	Resize *TerminalSize `json:",omitempty"`
+	// EOF closes the standard input of the command
+	EOF bool `json:",omitempty"`
+}
+
+// ExecOutput is produced by a command executed inside a running execution
+type ExecOutput struct {
+	Type ExecutionLogType `json:",omitempty"`
+	Data []byte           `json:",omitempty"`
+	// Exited is only set on the last output, once the command exited with ExitCode
+	Exited   bool `json:",omitempty"`
+	ExitCode int  `json:",omitempty"`
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+)
+
+const (
+	// HealthCheckHTTP checks the health of a task by requesting an HTTP path of the task,
+	// which must respond with a 2xx or 3xx status code.
+	HealthCheckHTTP = "http"
+
+	// HealthCheckTCP checks the health of a task by opening a TCP connection to a port of the task.
+	HealthCheckTCP = "tcp"
+
+	// HealthCheckCommand checks the health of a task by running a command inside the task,
+	// which must exit with a zero exit code.
+	HealthCheckCommand = "command"
+
+	// DefaultHealthCheckInterval is how often, in seconds, the health of a task is
+	// checked if the interval is not set.
+	DefaultHealthCheckInterval = int64(10)
+
+	// DefaultHealthCheckTimeout is how long, in seconds, a health check can take before it
+	// fails if the timeout is not set.
+	DefaultHealthCheckTimeout = int64(5)
+
+	// DefaultHealthCheckFailureThreshold is the number of consecutive failed health checks
+	// after which a task is unhealthy if the failure threshold is not set.
+	DefaultHealthCheckFailureThreshold = 3
+)
+
+// HealthCheck is a check periodically run by the compute node against the running task of a
+// long-running job. Executions whose task fails the check a number of times in a row are
+// considered unhealthy, and are replaced by the orchestrator.
+type HealthCheck struct {
+	// Type is the type of the check, which is one of http, tcp or command.
+	Type string `json:"Type"`
+
+	// Port is the port of the task checked by http and tcp checks.
+	Port int `json:"Port,omitempty"`
+
+	// Path is the path requested by http checks. Defaults to "/".
+	Path string `json:"Path,omitempty"`
+
+	// Command is the command run inside the task by command checks.
+	Command []string `json:"Command,omitempty"`
+
+	// Interval is how often the check runs in seconds. Defaults to DefaultHealthCheckInterval.
+	Interval int64 `json:"Interval,omitempty"`
+
+	// Timeout is how long the check can take in seconds. Defaults to DefaultHealthCheckTimeout.
+	Timeout int64 `json:"Timeout,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed checks after which the task is
+	// unhealthy. Defaults to DefaultHealthCheckFailureThreshold.
+	FailureThreshold int `json:"FailureThreshold,omitempty"`
+}
+
+// GetInterval returns how often the check runs
+func (h *HealthCheck) GetInterval() time.Duration {
+	return time.Duration(h.Interval) * time.Second
+}
+
+// GetTimeout returns how long the check can take
+func (h *HealthCheck) GetTimeout() time.Duration {
+	return time.Duration(h.Timeout) * time.Second
+}
+
+// Normalize applies defaults to the health check
+func (h *HealthCheck) Normalize() {
+	if h == nil {
+		return
+	}
+	if h.Type == HealthCheckHTTP && h.Path == "" {
+		h.Path = "/"
+	}
+	if h.Interval == 0 {
+		h.Interval = DefaultHealthCheckInterval
+	}
+	if h.Timeout == 0 {
+		h.Timeout = DefaultHealthCheckTimeout
+	}
+	if h.FailureThreshold == 0 {
+		h.FailureThreshold = DefaultHealthCheckFailureThreshold
+	}
+}
+
+// Copy returns a deep copy of the health check
+func (h *HealthCheck) Copy() *HealthCheck {
+	if h == nil {
+		return nil
+	}
+	nh := new(HealthCheck)
+	*nh = *h
+	nh.Command = append([]string(nil), h.Command...)
+	return nh
+}
+
+// Validate is used to check a health check for reasonable configuration
+func (h *HealthCheck) Validate() error {
+	if h == nil {
+		return errors.New("empty/nil health check")
+	}
+	var mErr error
+	switch h.Type {
+	case HealthCheckHTTP, HealthCheckTCP:
+		if h.Port <= 0 || h.Port > 65535 {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid health check port: %d", h.Port))
+		}
+		if h.Type == HealthCheckHTTP && h.Path != "" && !path.IsAbs(h.Path) {
+			mErr = errors.Join(mErr, fmt.Errorf("health check path %s must be absolute", h.Path))
+		}
+	case HealthCheckCommand:
+		if len(h.Command) == 0 {
+			mErr = errors.Join(mErr, errors.New("health check command is empty"))
+		}
+	case "":
+		mErr = errors.Join(mErr, errors.New("missing health check type"))
+	default:
+		mErr = errors.Join(mErr, fmt.Errorf("invalid health check type: %q", h.Type))
+	}
+	if h.Interval < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid health check interval value: %s", h.GetInterval()))
+	}
+	if h.Timeout < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid health check timeout value: %s", h.GetTimeout()))
+	}
+	if h.FailureThreshold < 0 {
+		mErr = errors.Join(mErr, errors.New("health check failure threshold must be >= 0"))
+	}
+	return mErr
+}
+
+// ExecutionHealthStatus is the health of a running execution, as reported by its health check
+type ExecutionHealthStatus string
+
+const (
+	ExecutionHealthy   ExecutionHealthStatus = "healthy"
+	ExecutionUnhealthy ExecutionHealthStatus = "unhealthy"
+)
+
+// ExecutionHealth is the health of an execution, reported by the compute node running its
+// health check every time the execution becomes healthy or unhealthy.
+type ExecutionHealth struct {
+	// Status is whether the execution is healthy
+	Status ExecutionHealthStatus `json:"Status"`
+
+	// Message describes the result of the latest health check
+	Message string `json:"Message"`
+
+	// CheckTime is the time of the health check that changed the status
+	CheckTime int64 `json:"CheckTime"`
+}
+
+// IsUnhealthy returns true if the execution failed its health check
+func (h *ExecutionHealth) IsUnhealthy() bool {
+	return h != nil && h.Status == ExecutionUnhealthy
+}
+
+// GetCheckTime returns the time of the health check that changed the status
+func (h *ExecutionHealth) GetCheckTime() time.Time {
+	return time.Unix(0, h.CheckTime).UTC()
+}
+
+// Copy returns a deep copy of the execution health
+func (h *ExecutionHealth) Copy() *ExecutionHealth {
+	if h == nil {
+		return nil
+	}
+	nh := new(ExecutionHealth)
+	*nh = *h
+	return nh
+}
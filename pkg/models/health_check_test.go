@@ -0,0 +1,117 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_ValidateHealthCheck(t *testing.T) {
+	newTask := func() *Task {
+		return &Task{
+			Name:        "main",
+			Engine:      &SpecConfig{Type: EngineDocker},
+			Publisher:   &SpecConfig{Type: PublisherLocal},
+			Network:     &NetworkConfig{Type: NetworkFull},
+			HealthCheck: &HealthCheck{Type: HealthCheckHTTP, Port: 8080, Path: "/health"},
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(task *Task)
+		wantErr bool
+	}{
+		{
+			name:   "valid-http",
+			modify: func(task *Task) {},
+		},
+		{
+			name:   "valid-tcp",
+			modify: func(task *Task) { task.HealthCheck = &HealthCheck{Type: HealthCheckTCP, Port: 5432} },
+		},
+		{
+			name: "valid-command-without-network",
+			modify: func(task *Task) {
+				task.Network = &NetworkConfig{Type: NetworkNone}
+				task.HealthCheck = &HealthCheck{Type: HealthCheckCommand, Command: []string{"pg_isready"}}
+			},
+		},
+		{
+			name:    "missing-type",
+			modify:  func(task *Task) { task.HealthCheck.Type = "" },
+			wantErr: true,
+		},
+		{
+			name:    "unknown-type",
+			modify:  func(task *Task) { task.HealthCheck.Type = "grpc" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid-port",
+			modify:  func(task *Task) { task.HealthCheck.Port = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "relative-path",
+			modify:  func(task *Task) { task.HealthCheck.Path = "health" },
+			wantErr: true,
+		},
+		{
+			name:    "empty-command",
+			modify:  func(task *Task) { task.HealthCheck = &HealthCheck{Type: HealthCheckCommand} },
+			wantErr: true,
+		},
+		{
+			name:    "negative-interval",
+			modify:  func(task *Task) { task.HealthCheck.Interval = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative-failure-threshold",
+			modify:  func(task *Task) { task.HealthCheck.FailureThreshold = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "no-network",
+			modify:  func(task *Task) { task.Network = &NetworkConfig{Type: NetworkNone} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask()
+			tt.modify(task)
+			err := task.ValidateSubmission()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHealthCheck_Normalize(t *testing.T) {
+	check := &HealthCheck{Type: HealthCheckHTTP, Port: 8080}
+	check.Normalize()
+	assert.Equal(t, "/", check.Path)
+	assert.Equal(t, DefaultHealthCheckInterval, check.Interval)
+	assert.Equal(t, DefaultHealthCheckTimeout, check.Timeout)
+	assert.Equal(t, DefaultHealthCheckFailureThreshold, check.FailureThreshold)
+
+	check = &HealthCheck{Type: HealthCheckTCP, Port: 8080, Interval: 30, Timeout: 1, FailureThreshold: 5}
+	check.Normalize()
+	assert.Empty(t, check.Path)
+	assert.Equal(t, int64(30), check.Interval)
+	assert.Equal(t, int64(1), check.Timeout)
+	assert.Equal(t, 5, check.FailureThreshold)
+}
+
+func TestExecutionHealth_IsUnhealthy(t *testing.T) {
+	var health *ExecutionHealth
+	assert.False(t, health.IsUnhealthy())
+	assert.False(t, (&ExecutionHealth{Status: ExecutionHealthy}).IsUnhealthy())
+	assert.True(t, (&ExecutionHealth{Status: ExecutionUnhealthy}).IsUnhealthy())
+}
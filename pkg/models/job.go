@@ -42,6 +42,16 @@ func (s JobStateType) IsUndefined() bool {
 	return s == JobStateTypeUndefined
 }
 
+// IsTerminal returns true if the job state is terminal
+func (s JobStateType) IsTerminal() bool {
+	switch s {
+	case JobStateTypeCompleted, JobStateTypeFailed, JobStateTypeStopped:
+		return true
+	default:
+		return false
+	}
+}
+
 func JobStateTypes() []JobStateType {
 	var res []JobStateType
 	for typ := JobStateTypePending; typ <= JobStateTypeStopped; typ++ {
@@ -85,6 +95,10 @@ type Job struct {
 	// Priority defines the scheduling priority of this job.
 	Priority int `json:"Priority"`
 
+	// Preemptible allows the job's running executions to be preempted by executions of higher
+	// priority jobs on compute nodes that are out of capacity. Preempted executions are rescheduled.
+	Preemptible bool `json:"Preemptible,omitempty"`
+
 	// Count is the number of replicas that should be scheduled.
 	Count int `json:"Count"`
 
@@ -100,6 +114,26 @@ type Job struct {
 
 	Tasks []*Task `json:"Tasks"`
 
+	// Dependencies is a list of upstream jobs that must complete before this job
+	// is scheduled. Their published results are mounted as inputs of this job's task.
+	Dependencies []*JobDependency `json:"Dependencies,omitempty"`
+
+	// Array fans out a batch job into one execution per index of a parameter matrix.
+	// Only valid for batch jobs, in which case Count is ignored.
+	Array *JobArray `json:"Array,omitempty"`
+
+	// Schedule defines when the runs of a scheduled job are created.
+	// Only valid for scheduled jobs.
+	Schedule *JobSchedule `json:"Schedule,omitempty"`
+
+	// RetryPolicy defines how failed executions are retried. Failed executions
+	// are retried immediately and without limit if not set.
+	RetryPolicy *RetryPolicy `json:"RetryPolicy,omitempty"`
+
+	// Update defines how the executions of a service or daemon job are replaced when the
+	// job is updated. All executions are replaced at once if not set.
+	Update *UpdateStrategy `json:"Update,omitempty"`
+
 	// State is the current state of the job.
 	State State[JobStateType] `json:"State"`
 
@@ -157,6 +191,10 @@ func (j *Job) Normalize() {
 		j.Tasks = make([]*Task, 0)
 	}
 
+	if j.Dependencies == nil {
+		j.Dependencies = make([]*JobDependency, 0)
+	}
+
 	// Ensure the job is in a namespace.
 	if j.Namespace == "" {
 		j.Namespace = DefaultNamespace
@@ -169,6 +207,11 @@ func (j *Job) Normalize() {
 	for _, task := range j.Tasks {
 		task.Normalize()
 	}
+	NormalizeSlice(j.Dependencies)
+	j.Array.Normalize()
+	j.Schedule.Normalize()
+	j.RetryPolicy.Normalize()
+	j.Update.Normalize()
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -189,6 +232,14 @@ func (j *Job) Copy() *Job {
 		nj.Tasks = tasks
 	}
 
+	if j.Dependencies != nil {
+		nj.Dependencies = CopySlice[*JobDependency](nj.Dependencies)
+	}
+	nj.Array = j.Array.Copy()
+	nj.Schedule = j.Schedule.Copy()
+	nj.RetryPolicy = j.RetryPolicy.Copy()
+	nj.Update = j.Update.Copy()
+
 	nj.Meta = maps.Clone(nj.Meta)
 	return nj
 }
@@ -239,7 +290,7 @@ func (j *Job) ValidateSubmission() error {
 
 	var mErr error
 	switch j.Type {
-	case JobTypeService, JobTypeBatch, JobTypeDaemon, JobTypeOps:
+	case JobTypeService, JobTypeBatch, JobTypeDaemon, JobTypeOps, JobTypeScheduled:
 	case "":
 		mErr = errors.Join(mErr, errors.New("missing job type"))
 	default:
@@ -258,6 +309,60 @@ func (j *Job) ValidateSubmission() error {
 			mErr = errors.Join(mErr, outer)
 		}
 	}
+	for idx, dep := range j.Dependencies {
+		if err := dep.Validate(); err != nil {
+			outer := fmt.Errorf("dependency %d validation failed: %s", idx+1, err)
+			mErr = errors.Join(mErr, outer)
+		}
+	}
+	if j.IsArray() {
+		if j.Type != JobTypeBatch && j.Type != JobTypeScheduled {
+			mErr = errors.Join(mErr, fmt.Errorf("job of type %s cannot be an array job", j.Type))
+		}
+		var task *Task
+		if len(j.Tasks) > 0 {
+			task = j.Task()
+		}
+		if err := j.Array.Validate(task); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
+	if j.Type == JobTypeScheduled {
+		if j.Schedule == nil {
+			mErr = errors.Join(mErr, errors.New("scheduled job must have a schedule"))
+		} else if err := j.Schedule.Validate(); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+		if j.HasDependencies() {
+			mErr = errors.Join(mErr, errors.New("scheduled job cannot have dependencies"))
+		}
+	} else if j.Schedule != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("job of type %s cannot have a schedule", j.Type))
+	}
+
+	if j.Update != nil {
+		if !j.IsLongRunning() {
+			mErr = errors.Join(mErr, fmt.Errorf("job of type %s cannot have an update strategy", j.Type))
+		}
+		if err := j.Update.Validate(); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
+
+	for _, task := range j.Tasks {
+		if task.HealthCheck != nil && !j.IsLongRunning() {
+			mErr = errors.Join(mErr, fmt.Errorf("job of type %s cannot have health checks", j.Type))
+		}
+	}
+
+	if j.RetryPolicy != nil {
+		if j.Type == JobTypeDaemon || j.Type == JobTypeOps {
+			mErr = errors.Join(mErr, fmt.Errorf("job of type %s cannot have a retry policy", j.Type))
+		}
+		if err := j.RetryPolicy.Validate(); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
 
 	// Validate the task group
 	for _, task := range j.Tasks {
@@ -292,9 +397,9 @@ func (j *Job) SanitizeSubmission() (warnings []string) {
 		warnings = append(warnings, "job modify time is ignored when submitting a job")
 		j.ModifyTime = 0
 	}
-	if j.Type == JobTypeBatch || j.Type == JobTypeOps {
+	if j.Type == JobTypeBatch || j.Type == JobTypeOps || j.Type == JobTypeScheduled {
 		if j.ID != "" {
-			warnings = append(warnings, "job ID is ignored when submitting a batch job")
+			warnings = append(warnings, fmt.Sprintf("job ID is ignored when submitting a %s job", j.Type))
 			j.ID = ""
 		}
 	}
@@ -314,12 +419,7 @@ func (j *Job) SanitizeSubmission() (warnings []string) {
 
 // IsTerminal returns true if the job is in a terminal state
 func (j *Job) IsTerminal() bool {
-	switch j.State.StateType {
-	case JobStateTypeCompleted, JobStateTypeFailed, JobStateTypeStopped:
-		return true
-	default:
-		return false
-	}
+	return j.State.StateType.IsTerminal()
 }
 
 // Task returns the job task
@@ -353,6 +453,26 @@ func (j *Job) AllStorageTypes() []string {
 	return storageTypes
 }
 
+// HasDependencies returns true if the job has to wait for upstream jobs
+func (j *Job) HasDependencies() bool {
+	return len(j.Dependencies) > 0
+}
+
+// DependsOn returns true if the job directly depends on the upstream job with the given ID
+func (j *Job) DependsOn(jobID string) bool {
+	for _, dep := range j.Dependencies {
+		if dep != nil && dep.JobID == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsScheduled returns true if the job creates runs on a schedule
+func (j *Job) IsScheduled() bool {
+	return j.Type == JobTypeScheduled
+}
+
 // IsLongRunning returns true if the job is long running
 func (j *Job) IsLongRunning() bool {
 	return j.Type == JobTypeService || j.Type == JobTypeDaemon
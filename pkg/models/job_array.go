@@ -0,0 +1,284 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvArrayIndex is the environment variable holding the index of an array job's execution.
+	EnvArrayIndex = "BACALHAU_ARRAY_INDEX"
+
+	// EnvArraySize is the environment variable holding the number of indices of an array job.
+	EnvArraySize = "BACALHAU_ARRAY_SIZE"
+
+	// MaxArraySize is the maximum number of indices of an array job.
+	MaxArraySize = 10000
+)
+
+// envVarNamePattern matches valid environment variable names
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// JobArray turns a batch job into an array job, which runs one execution per index of a
+// parameter matrix. The indices are the cartesian product of the parameters' values,
+// with the last parameter varying the fastest. Each execution gets its index and the values
+// of the parameters at that index as environment variables of its task.
+// Failed indices are retried on their own, and the job completes once every index has completed.
+type JobArray struct {
+	Parameters []*ArrayParameter `json:"Parameters"`
+}
+
+// ArrayParameter is a dimension of the parameter matrix of an array job.
+// Exactly one of Values, Range or InputSources must be set.
+type ArrayParameter struct {
+	// Name is the environment variable holding the parameter's value.
+	Name string `json:"Name"`
+
+	// Values is an explicit list of values.
+	Values []string `json:"Values,omitempty"`
+
+	// Range is a range of integer values.
+	Range *ArrayRange `json:"Range,omitempty"`
+
+	// InputSources creates one value per input source of the task, which is the path the input
+	// source is mounted at. Each execution only gets the input source of its index mounted.
+	InputSources bool `json:"InputSources,omitempty"`
+}
+
+// ArrayRange is a range of integer values from Start to End, both inclusive.
+type ArrayRange struct {
+	Start int `json:"Start"`
+	End   int `json:"End"`
+	// Step is the increment between values. Defaults to 1.
+	Step int `json:"Step,omitempty"`
+}
+
+// Normalize trims the parameters and applies defaults
+func (a *JobArray) Normalize() {
+	if a == nil {
+		return
+	}
+	if a.Parameters == nil {
+		a.Parameters = make([]*ArrayParameter, 0)
+	}
+	for _, p := range a.Parameters {
+		if p == nil {
+			continue
+		}
+		p.Name = strings.TrimSpace(p.Name)
+		if p.Range != nil && p.Range.Step == 0 {
+			p.Range.Step = 1
+		}
+	}
+}
+
+// Copy returns a deep copy of the array
+func (a *JobArray) Copy() *JobArray {
+	if a == nil {
+		return nil
+	}
+	na := &JobArray{Parameters: make([]*ArrayParameter, len(a.Parameters))}
+	for i, p := range a.Parameters {
+		if p == nil {
+			continue
+		}
+		np := *p
+		np.Values = slices.Clone(p.Values)
+		if p.Range != nil {
+			r := *p.Range
+			np.Range = &r
+		}
+		na.Parameters[i] = &np
+	}
+	return na
+}
+
+// Validate is used to check the array for reasonable configuration against the task it fans out
+func (a *JobArray) Validate(task *Task) error {
+	if a == nil {
+		return errors.New("empty/nil job array")
+	}
+	if len(a.Parameters) == 0 {
+		return errors.New("job array must have at least one parameter")
+	}
+	var mErr error
+	names := make(map[string]struct{})
+	inputParams := 0
+	for idx, p := range a.Parameters {
+		if p == nil {
+			mErr = errors.Join(mErr, fmt.Errorf("array parameter %d is empty", idx+1))
+			continue
+		}
+		if err := p.validate(task); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("array parameter %d validation failed: %w", idx+1, err))
+		}
+		if _, ok := names[p.Name]; ok {
+			mErr = errors.Join(mErr, fmt.Errorf("duplicate array parameter %s", p.Name))
+		}
+		names[p.Name] = struct{}{}
+		if p.InputSources {
+			inputParams++
+		}
+	}
+	if inputParams > 1 {
+		mErr = errors.Join(mErr, errors.New("only one array parameter can be created from input sources"))
+	}
+	if mErr != nil {
+		return mErr
+	}
+	if size := a.Size(task); size < 1 {
+		return errors.New("job array must have at least one index")
+	} else if size > MaxArraySize {
+		return fmt.Errorf("job array has more than the maximum of %d indices", MaxArraySize)
+	}
+	return nil
+}
+
+func (p *ArrayParameter) validate(task *Task) error {
+	var mErr error
+	if !envVarNamePattern.MatchString(p.Name) {
+		mErr = errors.Join(mErr, fmt.Errorf("name %q must be a valid environment variable name", p.Name))
+	} else if strings.HasPrefix(strings.ToUpper(p.Name), "BACALHAU_") {
+		mErr = errors.Join(mErr, fmt.Errorf("name %q uses the reserved BACALHAU_ prefix", p.Name))
+	}
+
+	sources := 0
+	if len(p.Values) > 0 {
+		sources++
+	}
+	if p.Range != nil {
+		sources++
+		if p.Range.Step <= 0 {
+			mErr = errors.Join(mErr, errors.New("range step must be > 0"))
+		}
+		if p.Range.End < p.Range.Start {
+			mErr = errors.Join(mErr, errors.New("range end must be >= start"))
+		}
+	}
+	if p.InputSources {
+		sources++
+		if task == nil || len(task.InputSources) == 0 {
+			mErr = errors.Join(mErr, errors.New("parameter created from input sources requires the task to have input sources"))
+		}
+	}
+	if sources != 1 {
+		mErr = errors.Join(mErr, errors.New("exactly one of values, range or input sources must be set"))
+	}
+	return mErr
+}
+
+// size returns the number of values of the parameter
+func (p *ArrayParameter) size(task *Task) int {
+	switch {
+	case len(p.Values) > 0:
+		return len(p.Values)
+	case p.Range != nil:
+		return p.Range.size()
+	case p.InputSources && task != nil:
+		return len(task.InputSources)
+	default:
+		return 0
+	}
+}
+
+// value returns the value of the parameter at the given offset, which for input sources
+// is the path the input source is mounted at
+func (p *ArrayParameter) value(task *Task, offset int) string {
+	switch {
+	case len(p.Values) > 0:
+		return p.Values[offset]
+	case p.Range != nil:
+		return strconv.Itoa(p.Range.Start + offset*p.Range.step())
+	default:
+		return task.InputSources[offset].Target
+	}
+}
+
+// size returns the number of values of the range, capped at MaxArraySize+1 as ranges
+// with more values than the maximum array size are rejected anyway
+func (r *ArrayRange) size() int {
+	if r.End < r.Start {
+		return 0
+	}
+	// the distance between the bounds can overflow an int, but not an uint64
+	distance := uint64(r.End) - uint64(r.Start)
+	steps := distance / uint64(r.step())
+	if steps >= MaxArraySize {
+		return MaxArraySize + 1
+	}
+	return int(steps) + 1
+}
+
+func (r *ArrayRange) step() int {
+	if r.Step <= 0 {
+		return 1
+	}
+	return r.Step
+}
+
+// Size returns the number of indices of the array for the given task
+func (a *JobArray) Size(task *Task) int {
+	if a == nil || len(a.Parameters) == 0 {
+		return 0
+	}
+	size := 1
+	for _, p := range a.Parameters {
+		size *= p.size(task)
+		if size > MaxArraySize {
+			// avoid overflowing with large matrices, which are rejected anyway
+			return size
+		}
+	}
+	return size
+}
+
+// offsets returns the offset of each parameter's value at the given index
+func (a *JobArray) offsets(task *Task, index int) []int {
+	offsets := make([]int, len(a.Parameters))
+	for i := len(a.Parameters) - 1; i >= 0; i-- {
+		n := a.Parameters[i].size(task)
+		offsets[i] = index % n
+		index /= n
+	}
+	return offsets
+}
+
+// ForArrayIndex returns a copy of the job to be run by the execution of the given array index.
+// The index, the array size and the parameter values at that index are added to the task's
+// environment, and only the input source of the index is kept if a parameter is created from
+// input sources.
+func (j *Job) ForArrayIndex(index int) *Job {
+	nj := j.Copy()
+	task := nj.Task()
+	if task.Env == nil {
+		task.Env = make(map[string]string)
+	}
+	task.Env[EnvArrayIndex] = strconv.Itoa(index)
+	task.Env[EnvArraySize] = strconv.Itoa(j.Array.Size(j.Task()))
+
+	offsets := j.Array.offsets(j.Task(), index)
+	for i, p := range j.Array.Parameters {
+		task.Env[p.Name] = p.value(j.Task(), offsets[i])
+		if p.InputSources {
+			task.InputSources = []*InputSource{task.InputSources[offsets[i]]}
+		}
+	}
+	return nj
+}
+
+// IsArray returns true if the job fans out one execution per index of a parameter matrix
+func (j *Job) IsArray() bool {
+	return j.Array != nil
+}
+
+// ArraySize returns the number of indices of an array job, or 0 if the job is not an array job
+func (j *Job) ArraySize() int {
+	if !j.IsArray() || len(j.Tasks) == 0 {
+		return 0
+	}
+	return j.Array.Size(j.Task())
+}
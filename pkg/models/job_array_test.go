@@ -0,0 +1,156 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobArray_Validate(t *testing.T) {
+	task := &Task{InputSources: []*InputSource{{Target: "/a"}, {Target: "/b"}}}
+	tests := []struct {
+		name    string
+		params  []*ArrayParameter
+		wantErr bool
+	}{
+		{
+			name:   "values",
+			params: []*ArrayParameter{{Name: "MODEL", Values: []string{"a", "b"}}},
+		},
+		{
+			name:   "range-and-input-sources",
+			params: []*ArrayParameter{{Name: "SEED", Range: &ArrayRange{End: 9, Step: 1}}, {Name: "INPUT", InputSources: true}},
+		},
+		{
+			name:    "no-parameters",
+			wantErr: true,
+		},
+		{
+			name:    "invalid-name",
+			params:  []*ArrayParameter{{Name: "1-MODEL", Values: []string{"a"}}},
+			wantErr: true,
+		},
+		{
+			name:    "reserved-name",
+			params:  []*ArrayParameter{{Name: "BACALHAU_MODEL", Values: []string{"a"}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate-name",
+			params:  []*ArrayParameter{{Name: "A", Values: []string{"a"}}, {Name: "A", Values: []string{"b"}}},
+			wantErr: true,
+		},
+		{
+			name:    "multiple-sources",
+			params:  []*ArrayParameter{{Name: "A", Values: []string{"a"}, Range: &ArrayRange{End: 1, Step: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "reversed-range",
+			params:  []*ArrayParameter{{Name: "A", Range: &ArrayRange{Start: 2, End: 1, Step: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "too-large",
+			params:  []*ArrayParameter{{Name: "A", Range: &ArrayRange{End: 1000, Step: 1}}, {Name: "B", Range: &ArrayRange{End: 1000, Step: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "overflowing-range",
+			params:  []*ArrayParameter{{Name: "A", Range: &ArrayRange{Start: math.MinInt, End: math.MaxInt, Step: 1}}},
+			wantErr: true,
+		},
+		{
+			name:   "full-range-with-large-step",
+			params: []*ArrayParameter{{Name: "A", Range: &ArrayRange{Start: math.MinInt, End: math.MaxInt, Step: math.MaxInt}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&JobArray{Parameters: tt.params}).Validate(task)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJobArray_Size(t *testing.T) {
+	assert.Equal(t, 3, (&JobArray{Parameters: []*ArrayParameter{
+		{Name: "A", Range: &ArrayRange{Start: math.MinInt, End: math.MaxInt, Step: math.MaxInt}},
+	}}).Size(nil))
+
+	// sizes beyond the maximum are capped instead of overflowing
+	for _, array := range []*JobArray{
+		{Parameters: []*ArrayParameter{{Name: "A", Range: &ArrayRange{Start: math.MinInt, End: math.MaxInt, Step: 1}}}},
+		{Parameters: []*ArrayParameter{
+			{Name: "A", Range: &ArrayRange{End: math.MaxInt, Step: 1}},
+			{Name: "B", Range: &ArrayRange{End: math.MaxInt, Step: 1}},
+			{Name: "C", Range: &ArrayRange{End: math.MaxInt, Step: 1}},
+		}},
+	} {
+		size := array.Size(nil)
+		assert.Greater(t, size, MaxArraySize)
+		assert.Error(t, array.Validate(nil))
+	}
+}
+
+func TestJob_ValidateSubmission_ArrayJobType(t *testing.T) {
+	newJob := func(jobType string) *Job {
+		return &Job{
+			Type:  jobType,
+			Count: 1,
+			Tasks: []*Task{{
+				Name:      "main",
+				Engine:    &SpecConfig{Type: EngineNoop},
+				Publisher: &SpecConfig{Type: PublisherNoop},
+			}},
+			Array: &JobArray{Parameters: []*ArrayParameter{{Name: "SEED", Values: []string{"1", "2"}}}},
+		}
+	}
+	assert.NoError(t, newJob(JobTypeBatch).ValidateSubmission())
+
+	// updates of service jobs are rolled out one execution at a time, which array jobs do not support
+	service := newJob(JobTypeService)
+	service.Update = &UpdateStrategy{MaxParallel: 1}
+	assert.ErrorContains(t, service.ValidateSubmission(), "cannot be an array job")
+	assert.ErrorContains(t, newJob(JobTypeDaemon).ValidateSubmission(), "cannot be an array job")
+}
+
+func TestJob_ForArrayIndex(t *testing.T) {
+	job := &Job{
+		Type: JobTypeBatch,
+		Tasks: []*Task{{
+			Name:         "main",
+			Env:          map[string]string{"FOO": "bar"},
+			InputSources: []*InputSource{{Target: "/inputs/a"}, {Target: "/inputs/b"}},
+		}},
+		Array: &JobArray{Parameters: []*ArrayParameter{
+			{Name: "INPUT", InputSources: true},
+			{Name: "SEED", Range: &ArrayRange{Start: 10, End: 30, Step: 10}},
+		}},
+	}
+	require.Equal(t, 6, job.ArraySize())
+
+	indexed := job.ForArrayIndex(4)
+	task := indexed.Task()
+	assert.Equal(t, map[string]string{
+		"FOO":         "bar",
+		EnvArrayIndex: "4",
+		EnvArraySize:  "6",
+		"INPUT":       "/inputs/b",
+		"SEED":        "20",
+	}, task.Env)
+	require.Len(t, task.InputSources, 1)
+	assert.Equal(t, "/inputs/b", task.InputSources[0].Target)
+
+	// the original job is left untouched
+	assert.Len(t, job.Task().Env, 1)
+	assert.Len(t, job.Task().InputSources, 2)
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// DefaultDependencyTargetRoot is the directory under which the results of upstream
+// jobs are mounted when a dependency does not specify its own target.
+const DefaultDependencyTargetRoot = "/inputs"
+
+// JobDependency declares that a job must wait for another job in the same
+// namespace to complete before it is scheduled. The published results of the
+// upstream job are mounted into the downstream job's task as input sources.
+type JobDependency struct {
+	// JobID is the ID of the upstream job.
+	// Either JobID or Name must be set. If only Name is set, the orchestrator
+	// resolves it to the ID of the most recent job with that name on submission.
+	JobID string `json:"JobID,omitempty"`
+
+	// Name is the name of the upstream job.
+	Name string `json:"Name,omitempty"`
+
+	// Target is the path where the upstream job's published results are mounted.
+	// Defaults to /inputs/<upstream name or id>.
+	Target string `json:"Target,omitempty"`
+}
+
+// Normalize trims the dependency's fields
+func (d *JobDependency) Normalize() {
+	if d == nil {
+		return
+	}
+	d.JobID = strings.TrimSpace(d.JobID)
+	d.Name = strings.TrimSpace(d.Name)
+	d.Target = strings.TrimSpace(d.Target)
+}
+
+// Copy returns a deep copy of the dependency
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+// Reference returns the identifier the dependency was declared with,
+// preferring the job ID if set.
+func (d *JobDependency) Reference() string {
+	if d.JobID != "" {
+		return d.JobID
+	}
+	return d.Name
+}
+
+// TargetOrDefault returns the path where the upstream results should be mounted
+func (d *JobDependency) TargetOrDefault() string {
+	if d.Target != "" {
+		return d.Target
+	}
+	if d.Name != "" {
+		return path.Join(DefaultDependencyTargetRoot, d.Name)
+	}
+	return path.Join(DefaultDependencyTargetRoot, d.JobID)
+}
+
+// Validate is used to check a dependency for reasonable configuration
+func (d *JobDependency) Validate() error {
+	if d == nil {
+		return errors.New("empty/nil job dependency")
+	}
+	var mErr error
+	if validate.IsBlank(d.JobID) && validate.IsBlank(d.Name) {
+		mErr = errors.Join(mErr, errors.New("job dependency must have either a job ID or a name"))
+	}
+	if validate.ContainsSpaces(d.JobID) {
+		mErr = errors.Join(mErr, errors.New("job dependency ID contains a space"))
+	}
+	if d.Target != "" && !path.IsAbs(d.Target) {
+		mErr = errors.Join(mErr, fmt.Errorf("job dependency target %q must be an absolute path", d.Target))
+	}
+	return mErr
+}
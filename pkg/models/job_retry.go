@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// RetryOnFailed retries executions that failed on the compute node.
+	RetryOnFailed = "failed"
+
+	// RetryOnNodeLost retries executions that were running on a node that was lost.
+	RetryOnNodeLost = "node-lost"
+)
+
+// RetryPolicy defines how the failed executions of a job are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of executions attempted for the job, or for each
+	// index of an array job, including the first one. Zero means no limit.
+	MaxAttempts int `json:"MaxAttempts,omitempty"`
+
+	// BackoffBase is the time to wait in seconds before the first retry. The wait doubles
+	// on each following retry. Zero means retrying immediately.
+	BackoffBase int64 `json:"BackoffBase,omitempty"`
+
+	// BackoffMax is the maximum time to wait in seconds between retries.
+	// Defaults to BackoffBase doubled ten times.
+	BackoffMax int64 `json:"BackoffMax,omitempty"`
+
+	// RetryOn is the list of failure classes that are retried, among failed and node-lost.
+	// Defaults to all failure classes.
+	RetryOn []string `json:"RetryOn,omitempty"`
+}
+
+// Normalize applies defaults to the retry policy
+func (r *RetryPolicy) Normalize() {
+	if r == nil {
+		return
+	}
+	if r.BackoffMax == 0 {
+		r.BackoffMax = r.BackoffBase << 10
+	}
+	if len(r.RetryOn) == 0 {
+		r.RetryOn = []string{RetryOnFailed, RetryOnNodeLost}
+	}
+	for i, class := range r.RetryOn {
+		r.RetryOn[i] = strings.ToLower(strings.TrimSpace(class))
+	}
+}
+
+// Copy returns a deep copy of the retry policy
+func (r *RetryPolicy) Copy() *RetryPolicy {
+	if r == nil {
+		return nil
+	}
+	nr := new(RetryPolicy)
+	*nr = *r
+	nr.RetryOn = slices.Clone(r.RetryOn)
+	return nr
+}
+
+// Validate is used to check a retry policy for reasonable configuration
+func (r *RetryPolicy) Validate() error {
+	if r == nil {
+		return errors.New("empty/nil retry policy")
+	}
+	var mErr error
+	if r.MaxAttempts < 0 {
+		mErr = errors.Join(mErr, errors.New("retry policy max attempts must be >= 0"))
+	}
+	if r.BackoffBase < 0 {
+		mErr = errors.Join(mErr, errors.New("retry policy backoff base must be >= 0"))
+	}
+	if r.BackoffMax < 0 {
+		mErr = errors.Join(mErr, errors.New("retry policy backoff max must be >= 0"))
+	} else if r.BackoffMax > 0 && r.BackoffMax < r.BackoffBase {
+		mErr = errors.Join(mErr, errors.New("retry policy backoff max must be >= backoff base"))
+	}
+	for _, class := range r.RetryOn {
+		switch class {
+		case RetryOnFailed, RetryOnNodeLost:
+		default:
+			mErr = errors.Join(mErr, fmt.Errorf("invalid retry failure class %q, must be one of %s or %s",
+				class, RetryOnFailed, RetryOnNodeLost))
+		}
+	}
+	return mErr
+}
+
+// GetBackoffBase returns the time to wait before the first retry
+func (r *RetryPolicy) GetBackoffBase() time.Duration {
+	return time.Duration(r.BackoffBase) * time.Second
+}
+
+// GetBackoffMax returns the maximum time to wait between retries
+func (r *RetryPolicy) GetBackoffMax() time.Duration {
+	return time.Duration(r.BackoffMax) * time.Second
+}
+
+// RetriesOn returns true if executions failing with the given failure class are retried
+func (r *RetryPolicy) RetriesOn(class string) bool {
+	if len(r.RetryOn) == 0 {
+		return true
+	}
+	return slices.Contains(r.RetryOn, class)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/cron"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+const (
+	// ConcurrencyPolicyAllow allows scheduled runs to overlap with previous runs that are still active.
+	ConcurrencyPolicyAllow = "allow"
+
+	// ConcurrencyPolicyForbid skips a scheduled run if a previous run is still active.
+	ConcurrencyPolicyForbid = "forbid"
+
+	// ConcurrencyPolicyReplace stops the previous runs that are still active before starting a new run.
+	ConcurrencyPolicyReplace = "replace"
+)
+
+const (
+	// DetailsKeyScheduledTime is the event detail holding the time a scheduled run was due, in RFC3339.
+	DetailsKeyScheduledTime = "ScheduledTime"
+
+	// DetailsKeyScheduledRunID is the event detail holding the ID of the job created for a scheduled run.
+	DetailsKeyScheduledRunID = "RunJobID"
+)
+
+// JobSchedule defines when the runs of a scheduled job are created.
+type JobSchedule struct {
+	// Cron is a standard five field cron expression, such as "0 2 * * *",
+	// or one of the @yearly, @monthly, @weekly, @daily and @hourly descriptors.
+	Cron string `json:"Cron"`
+
+	// Timezone is the IANA time zone the cron expression is evaluated in. Defaults to UTC.
+	Timezone string `json:"Timezone,omitempty"`
+
+	// ConcurrencyPolicy defines what happens when a run is due while previous runs
+	// are still active. One of allow, forbid or replace. Defaults to allow.
+	ConcurrencyPolicy string `json:"ConcurrencyPolicy,omitempty"`
+}
+
+// Normalize trims the schedule's fields and applies defaults
+func (s *JobSchedule) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Cron = strings.TrimSpace(s.Cron)
+	s.Timezone = strings.TrimSpace(s.Timezone)
+	s.ConcurrencyPolicy = strings.ToLower(strings.TrimSpace(s.ConcurrencyPolicy))
+	if s.ConcurrencyPolicy == "" {
+		s.ConcurrencyPolicy = ConcurrencyPolicyAllow
+	}
+}
+
+// Copy returns a deep copy of the schedule
+func (s *JobSchedule) Copy() *JobSchedule {
+	if s == nil {
+		return nil
+	}
+	ns := new(JobSchedule)
+	*ns = *s
+	return ns
+}
+
+// Validate is used to check a schedule for reasonable configuration
+func (s *JobSchedule) Validate() error {
+	if s == nil {
+		return errors.New("empty/nil job schedule")
+	}
+	var mErr error
+	if validate.IsBlank(s.Cron) {
+		mErr = errors.Join(mErr, errors.New("job schedule must have a cron expression"))
+	} else if _, err := cron.Parse(s.Cron); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid job schedule: %w", err))
+	}
+	if _, err := s.Location(); err != nil {
+		mErr = errors.Join(mErr, err)
+	}
+	switch s.ConcurrencyPolicy {
+	case "", ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace:
+	default:
+		mErr = errors.Join(mErr, fmt.Errorf("invalid concurrency policy %q, must be one of %s, %s or %s",
+			s.ConcurrencyPolicy, ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace))
+	}
+	return mErr
+}
+
+// Location returns the time zone the schedule is evaluated in
+func (s *JobSchedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job schedule timezone %q: %w", s.Timezone, err)
+	}
+	return loc, nil
+}
+
+// Next returns the first time a run is due strictly after the given time, in UTC.
+// It returns the zero time if the schedule never activates again.
+func (s *JobSchedule) Next(after time.Time) (time.Time, error) {
+	schedule, err := cron.Parse(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := schedule.Next(after.In(loc))
+	if next.IsZero() {
+		return next, nil
+	}
+	return next.UTC(), nil
+}
+
+// String returns the cron expression along with its time zone
+func (s *JobSchedule) String() string {
+	if s.Timezone == "" {
+		return s.Cron
+	}
+	return fmt.Sprintf("%s (%s)", s.Cron, s.Timezone)
+}
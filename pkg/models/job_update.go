@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+const (
+	// DefaultUpdateMaxParallel is the number of executions replaced at a time during an
+	// update if the max parallel of the update strategy is not set.
+	DefaultUpdateMaxParallel = 1
+
+	// DefaultUpdateMinHealthyTime is how long, in seconds, a new execution must be running
+	// during an update before it is considered healthy if the min healthy time is not set.
+	DefaultUpdateMinHealthyTime = int64(10)
+)
+
+// UpdateStrategy defines how the executions of a long-running job are replaced when the job
+// is updated. The executions of the previous versions of the job are replaced gradually, and
+// only once the executions of the new version are healthy.
+type UpdateStrategy struct {
+	// MaxParallel is the number of executions replaced at a time.
+	// Defaults to DefaultUpdateMaxParallel.
+	MaxParallel int `json:"MaxParallel,omitempty"`
+
+	// MinHealthyTime is how long in seconds an execution of the new version must be running
+	// before it is considered healthy. Defaults to DefaultUpdateMinHealthyTime.
+	MinHealthyTime int64 `json:"MinHealthyTime,omitempty"`
+
+	// AutoRevert reverts the job to the version it is updated from if an execution of the
+	// new version fails during the update.
+	AutoRevert bool `json:"AutoRevert,omitempty"`
+
+	// Canary is the number of executions of the new version that must be healthy before
+	// the executions of the previous versions are replaced. Zero means no canaries.
+	Canary int `json:"Canary,omitempty"`
+}
+
+// Normalize applies defaults to the update strategy
+func (u *UpdateStrategy) Normalize() {
+	if u == nil {
+		return
+	}
+	if u.MaxParallel == 0 {
+		u.MaxParallel = DefaultUpdateMaxParallel
+	}
+	if u.MinHealthyTime == 0 {
+		u.MinHealthyTime = DefaultUpdateMinHealthyTime
+	}
+}
+
+// Copy returns a deep copy of the update strategy
+func (u *UpdateStrategy) Copy() *UpdateStrategy {
+	if u == nil {
+		return nil
+	}
+	nu := new(UpdateStrategy)
+	*nu = *u
+	return nu
+}
+
+// Validate is used to check an update strategy for reasonable configuration
+func (u *UpdateStrategy) Validate() error {
+	if u == nil {
+		return errors.New("empty/nil update strategy")
+	}
+	var mErr error
+	if u.MaxParallel < 0 {
+		mErr = errors.Join(mErr, errors.New("update strategy max parallel must be >= 0"))
+	}
+	if u.MinHealthyTime < 0 {
+		mErr = errors.Join(mErr, errors.New("update strategy min healthy time must be >= 0"))
+	}
+	if u.Canary < 0 {
+		mErr = errors.Join(mErr, errors.New("update strategy canary must be >= 0"))
+	}
+	return mErr
+}
+
+// GetMinHealthyTime returns how long an execution must be running before it is considered healthy
+func (u *UpdateStrategy) GetMinHealthyTime() time.Duration {
+	return time.Duration(u.MinHealthyTime) * time.Second
+}
+
+// RequiresReplacement returns true if the executions of the given previous version of the
+// job must be replaced to run this version, which is the case when their tasks or constraints
+// differ. Executions of versions that only differ otherwise, such as by their count, keep running.
+func (j *Job) RequiresReplacement(previous *Job) bool {
+	// compare copies so that nil and empty fields are equal
+	current, previous := j.Copy(), previous.Copy()
+	return !reflect.DeepEqual(current.Tasks, previous.Tasks) ||
+		!reflect.DeepEqual(current.Constraints, previous.Constraints)
+}
@@ -0,0 +1,69 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateStrategy_Normalize(t *testing.T) {
+	strategy := &UpdateStrategy{Canary: 1}
+	strategy.Normalize()
+	assert.Equal(t, DefaultUpdateMaxParallel, strategy.MaxParallel)
+	assert.Equal(t, DefaultUpdateMinHealthyTime, strategy.MinHealthyTime)
+	assert.Equal(t, 1, strategy.Canary)
+}
+
+func TestUpdateStrategy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy UpdateStrategy
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			strategy: UpdateStrategy{MaxParallel: 2, MinHealthyTime: 30, AutoRevert: true, Canary: 1},
+		},
+		{
+			name:     "negative-max-parallel",
+			strategy: UpdateStrategy{MaxParallel: -1},
+			wantErr:  true,
+		},
+		{
+			name:     "negative-min-healthy-time",
+			strategy: UpdateStrategy{MinHealthyTime: -1},
+			wantErr:  true,
+		},
+		{
+			name:     "negative-canary",
+			strategy: UpdateStrategy{Canary: -1},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.strategy.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestJob_RequiresReplacement(t *testing.T) {
+	previous := &Job{
+		Count: 1,
+		Tasks: []*Task{{Name: "main", Engine: &SpecConfig{Type: EngineDocker}}},
+	}
+
+	job := previous.Copy()
+	job.Count = 3
+	assert.False(t, job.RequiresReplacement(previous))
+
+	job.Tasks[0].Env = map[string]string{"VERSION": "2"}
+	assert.True(t, job.RequiresReplacement(previous))
+}
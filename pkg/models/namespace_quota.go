@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// NamespaceQuota limits the jobs of a namespace that can be active at the same time,
+// so that a namespace cannot take all the capacity of a shared cluster. Jobs over quota
+// are kept pending until enough active jobs of the namespace have finished.
+type NamespaceQuota struct {
+	// Namespace is the namespace the quota applies to
+	Namespace string `json:"Namespace"`
+
+	// MaxJobs is the maximum number of active jobs in the namespace. Zero means no limit.
+	MaxJobs int `json:"MaxJobs,omitempty"`
+
+	// Resources is the maximum total resources requested by the active jobs in the namespace.
+	// A zero value for a resource means no limit for that resource.
+	Resources Resources `json:"Resources"`
+
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// NamespaceUsage is the number of active jobs in a namespace and their requested resources.
+type NamespaceUsage struct {
+	Jobs      int       `json:"Jobs"`
+	Resources Resources `json:"Resources"`
+}
+
+// Add returns the sum of the usages
+func (u NamespaceUsage) Add(other NamespaceUsage) NamespaceUsage {
+	return NamespaceUsage{
+		Jobs:      u.Jobs + other.Jobs,
+		Resources: *u.Resources.Add(other.Resources),
+	}
+}
+
+// Normalize normalizes the quota
+func (q *NamespaceQuota) Normalize() {
+	if q == nil {
+		return
+	}
+	q.Namespace = strings.TrimSpace(q.Namespace)
+	q.Resources.GPUs = nil
+}
+
+// Copy returns a deep copy of the quota
+func (q *NamespaceQuota) Copy() *NamespaceQuota {
+	if q == nil {
+		return nil
+	}
+	nq := new(NamespaceQuota)
+	*nq = *q
+	nq.Resources = *q.Resources.Copy()
+	return nq
+}
+
+// Validate returns an error if the quota is invalid
+func (q *NamespaceQuota) Validate() error {
+	if q == nil {
+		return errors.New("missing namespace quota")
+	}
+	var mErr error
+	if validate.IsBlank(q.Namespace) {
+		mErr = errors.Join(mErr, errors.New("missing namespace"))
+	} else if validate.ContainsSpaces(q.Namespace) {
+		mErr = errors.Join(mErr, errors.New("namespace contains whitespace"))
+	}
+	if q.MaxJobs < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid max jobs value: %d", q.MaxJobs))
+	}
+	if err := q.Resources.Validate(); err != nil {
+		mErr = errors.Join(mErr, err)
+	}
+	return mErr
+}
+
+// Exceeded returns the reasons why the usage exceeds the quota, or nil if it is within the quota.
+func (q *NamespaceQuota) Exceeded(usage NamespaceUsage) []string {
+	var reasons []string
+	if q.MaxJobs > 0 && usage.Jobs > q.MaxJobs {
+		reasons = append(reasons, fmt.Sprintf("jobs %d > %d", usage.Jobs, q.MaxJobs))
+	}
+	if q.Resources.CPU > 0 && usage.Resources.CPU > q.Resources.CPU {
+		reasons = append(reasons, fmt.Sprintf("cpu %g > %g", usage.Resources.CPU, q.Resources.CPU))
+	}
+	if q.Resources.Memory > 0 && usage.Resources.Memory > q.Resources.Memory {
+		reasons = append(reasons, fmt.Sprintf("memory %d > %d", usage.Resources.Memory, q.Resources.Memory))
+	}
+	if q.Resources.Disk > 0 && usage.Resources.Disk > q.Resources.Disk {
+		reasons = append(reasons, fmt.Sprintf("disk %d > %d", usage.Resources.Disk, q.Resources.Disk))
+	}
+	if q.Resources.GPU > 0 && usage.Resources.GPU > q.Resources.GPU {
+		reasons = append(reasons, fmt.Sprintf("gpu %d > %d", usage.Resources.GPU, q.Resources.GPU))
+	}
+	return reasons
+}
+
+// ResourceUsage returns the number of jobs and the total resources requested by the job
+// across all its desired executions.
+func (j *Job) ResourceUsage() (NamespaceUsage, error) {
+	executions := j.Count
+	if j.IsArray() {
+		executions = j.ArraySize()
+	}
+	if executions < 1 {
+		// daemon and ops jobs run on every matching node, so only one execution
+		// is known to be needed when the job is submitted
+		executions = 1
+	}
+
+	usage := NamespaceUsage{Jobs: 1}
+	for _, task := range j.Tasks {
+		if task.ResourcesConfig == nil {
+			continue
+		}
+		resources, err := task.ResourcesConfig.ToResources()
+		if err != nil {
+			return NamespaceUsage{}, err
+		}
+		for i := 0; i < executions; i++ {
+			usage.Resources = *usage.Resources.Add(*resources)
+		}
+	}
+	usage.Resources.GPUs = nil
+	return usage, nil
+}
@@ -78,6 +78,10 @@ func (n *Network) UnmarshalText(text []byte) (err error) {
 type NetworkConfig struct {
 	Type    Network  `json:"Type"`
 	Domains []string `json:"Domains,omitempty"`
+	// Ports are the ports of the task published on the compute node. They require full networking,
+	// and Docker tasks with ports run on the bridge network rather than the network of the host,
+	// as ports can't be mapped on the host network.
+	Ports []*Port `json:"Ports,omitempty"`
 }
 
 // Disabled returns whether network connections should be completely disabled according
@@ -105,9 +109,14 @@ func (n *NetworkConfig) Copy() *NetworkConfig {
 	if n == nil {
 		return nil
 	}
+	var ports []*Port
+	for _, port := range n.Ports {
+		ports = append(ports, port.Copy())
+	}
 	return &NetworkConfig{
 		Type:    n.Type,
 		Domains: slices.Clone(n.Domains),
+		Ports:   ports,
 	}
 }
 
@@ -128,6 +137,26 @@ func (n *NetworkConfig) Validate() (err error) {
 		err = errors.Join(err, fmt.Errorf("invalid domain %q", domain))
 	}
 
+	if len(n.Ports) > 0 && n.Type != NetworkFull {
+		err = errors.Join(err, errors.New("ports can only be published by tasks with full networking"))
+	}
+	names := make(map[string]bool)
+	staticPorts := make(map[int]bool)
+	for _, port := range n.Ports {
+		if portErr := port.Validate(); portErr != nil {
+			err = errors.Join(err, portErr)
+			continue
+		}
+		if names[port.Name] {
+			err = errors.Join(err, fmt.Errorf("port %s is defined more than once", port.Name))
+		}
+		if port.Static != 0 && staticPorts[port.Static] {
+			err = errors.Join(err, fmt.Errorf("static port %d is used by more than one port", port.Static))
+		}
+		names[port.Name] = true
+		staticPorts[port.Static] = true
+	}
+
 	return
 }
 
@@ -62,6 +62,64 @@ func TestNetworkConfig_IsValid(t *testing.T) {
 	}
 }
 
+func TestNetworkConfig_ValidatePorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		networkType Network
+		ports       []*Port
+		wantErr     bool
+	}{
+		{
+			name:        "valid",
+			networkType: NetworkFull,
+			ports:       []*Port{{Name: "http", Target: 8080}, {Name: "metrics", Target: 9090, Static: 9090}},
+		},
+		{
+			name:        "requires-full-network",
+			networkType: NetworkHTTP,
+			ports:       []*Port{{Name: "http", Target: 8080}},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid-name",
+			networkType: NetworkFull,
+			ports:       []*Port{{Name: "HTTP", Target: 8080}},
+			wantErr:     true,
+		},
+		{
+			name:        "duplicate-name",
+			networkType: NetworkFull,
+			ports:       []*Port{{Name: "http", Target: 8080}, {Name: "http", Target: 8081}},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid-target",
+			networkType: NetworkFull,
+			ports:       []*Port{{Name: "http", Target: 0}},
+			wantErr:     true,
+		},
+		{
+			name:        "duplicate-static-port",
+			networkType: NetworkFull,
+			ports:       []*Port{{Name: "http", Target: 8080, Static: 80}, {Name: "web", Target: 8081, Static: 80}},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NetworkConfig{
+				Type:  tt.networkType,
+				Ports: tt.ports,
+			}
+			if err := n.Validate(); tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestDomainSet(t *testing.T) {
 	tests := []struct {
 		input, output []string
@@ -1,9 +1,54 @@
 package models
 
+import "time"
+
 // NodeState contains metadata about the state of a node on the network. Requester nodes maintain a NodeState for
 // each node they are aware of. The NodeState represents a Requester nodes view of another node on the network.
 type NodeState struct {
 	Info       NodeInfo            `json:"Info"`
 	Membership NodeMembershipState `json:"Membership"`
 	Connection NodeConnectionState `json:"Connection"`
+	// Cordon is set while the node is taken out of rotation, e.g. for maintenance
+	Cordon *NodeCordon `json:"Cordon,omitempty"`
+	// PublicKey is the key the node registered with, which its credentials are issued for
+	PublicKey string `json:"PublicKey,omitempty"`
+}
+
+// IsCordoned returns true if no new executions can be scheduled on the node
+func (s NodeState) IsCordoned() bool {
+	return s.Cordon != nil
+}
+
+// NodeCordon describes why and how a node was taken out of rotation. No new executions
+// are scheduled on a cordoned node. If the node is also draining, the executions of its
+// long-running jobs are moved to other nodes, while its batch executions are allowed to finish.
+type NodeCordon struct {
+	// Message is the reason the node was cordoned
+	Message string `json:"Message,omitempty"`
+	// CreateTime is when the node was cordoned
+	CreateTime int64 `json:"CreateTime"`
+	// Drain is true if the long-running executions of the node are moved to other nodes
+	Drain bool `json:"Drain,omitempty"`
+	// DrainDeadline is when the long-running executions of a draining node are stopped.
+	// Their replacements are scheduled on other nodes right away.
+	DrainDeadline int64 `json:"DrainDeadline,omitempty"`
+}
+
+// GetCreateTime returns the time the node was cordoned
+func (c *NodeCordon) GetCreateTime() time.Time {
+	return time.Unix(0, c.CreateTime).UTC()
+}
+
+// GetDrainDeadline returns the time the long-running executions of a draining node are stopped
+func (c *NodeCordon) GetDrainDeadline() time.Time {
+	return time.Unix(0, c.DrainDeadline).UTC()
+}
+
+// Copy returns a deep copy of the cordon
+func (c *NodeCordon) Copy() *NodeCordon {
+	if c == nil {
+		return nil
+	}
+	cpy := *c
+	return &cpy
 }
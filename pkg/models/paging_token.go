@@ -10,8 +10,11 @@ import (
 )
 
 const (
-	delimiter         = ":"
-	expectedPartCount = 4
+	delimiter = ":"
+	// offsetPartCount is the number of parts of tokens that page using an offset
+	offsetPartCount = 4
+	// cursorPartCount is the number of parts of tokens that page using a cursor
+	cursorPartCount = 6
 )
 
 type PagingTokenParams struct {
@@ -19,21 +22,29 @@ type PagingTokenParams struct {
 	SortReverse bool
 	Limit       uint32
 	Offset      uint32
+	// CursorID and CursorSortValue identify the last item of the previous page, so that the
+	// next page starts right after it. They are not set when paging using an offset.
+	CursorID        string
+	CursorSortValue int64
 }
 
 type PagingToken struct {
-	SortBy      string
-	SortReverse bool
-	Limit       uint32
-	Offset      uint32
+	SortBy          string
+	SortReverse     bool
+	Limit           uint32
+	Offset          uint32
+	CursorID        string
+	CursorSortValue int64
 }
 
 func NewPagingToken(params *PagingTokenParams) *PagingToken {
 	return &PagingToken{
-		SortBy:      params.SortBy,
-		SortReverse: params.SortReverse,
-		Limit:       params.Limit,
-		Offset:      params.Offset,
+		SortBy:          params.SortBy,
+		SortReverse:     params.SortReverse,
+		Limit:           params.Limit,
+		Offset:          params.Offset,
+		CursorID:        params.CursorID,
+		CursorSortValue: params.CursorSortValue,
 	}
 }
 
@@ -46,7 +57,7 @@ func NewPagingTokenFromString(s string) (*PagingToken, error) {
 	}
 
 	parts := strings.Split(string(decodedBytes), delimiter)
-	if len(parts) != expectedPartCount {
+	if len(parts) != offsetPartCount && len(parts) != cursorPartCount {
 		return nil, NewErrInvalidPagingToken(s, "invalid number of parts")
 	}
 
@@ -67,21 +78,45 @@ func NewPagingTokenFromString(s string) (*PagingToken, error) {
 		token.Offset = uint32(offset)
 	}
 
+	if len(parts) == cursorPartCount {
+		if parts[5] == "" {
+			return nil, NewErrInvalidPagingToken(s, "malformed token")
+		}
+		if value, err := strconv.ParseInt(parts[4], 10, 64); err != nil {
+			return nil, NewErrInvalidPagingToken(s, "malformed token")
+		} else {
+			token.CursorSortValue = value
+		}
+		token.CursorID = parts[5]
+	}
+
 	return token, nil
 }
 
+// HasCursor returns true if the token pages using a cursor rather than an offset
+func (pagingToken *PagingToken) HasCursor() bool {
+	return pagingToken.CursorID != ""
+}
+
 func (pagingToken *PagingToken) RawString() string {
 	reverse := "N"
 	if pagingToken.SortReverse {
 		reverse = "Y"
 	}
 
-	return strings.Join([]string{
+	parts := []string{
 		pagingToken.SortBy,
 		reverse,
 		strconv.FormatUint(uint64(pagingToken.Limit), 10),
 		strconv.FormatUint(uint64(pagingToken.Offset), 10),
-	}, delimiter)
+	}
+	if pagingToken.HasCursor() {
+		parts = append(parts,
+			strconv.FormatInt(pagingToken.CursorSortValue, 10),
+			pagingToken.CursorID,
+		)
+	}
+	return strings.Join(parts, delimiter)
 }
 
 // String returns the token as a base 64 encoded string where each field is
@@ -62,6 +62,25 @@ func (s *PagingTokenTestSuite) TestNew() {
 			decoded:   "created_at:Y:10:10",
 			expectErr: false,
 		},
+		{
+			name: "valid with cursor",
+			params: &models.PagingTokenParams{
+				SortBy:          "created_at",
+				SortReverse:     true,
+				Limit:           10,
+				CursorID:        "j-1",
+				CursorSortValue: 1700000000,
+			},
+			token:     "Y3JlYXRlZF9hdDpZOjEwOjA6MTcwMDAwMDAwMDpqLTE",
+			decoded:   "created_at:Y:10:0:1700000000:j-1",
+			expectErr: false,
+		},
+		{
+			name:      "invalid cursor",
+			params:    &models.PagingTokenParams{},
+			token:     "Y3JlYXRlZF9hdDpZOjEwOjA6YWJjOmotMQ",
+			expectErr: true,
+		},
 		{
 			name:      "invalid token",
 			params:    &models.PagingTokenParams{},
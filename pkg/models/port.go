@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+)
+
+var portNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Port is a named port of a task, published on the compute node running the task so that other
+// jobs and clients can reach it.
+type Port struct {
+	// Name identifies the port in the published ports of executions, e.g. http.
+	Name string `json:"Name"`
+
+	// Target is the port the task listens on.
+	Target int `json:"Target"`
+
+	// Static is the port of the compute node the port is published on.
+	// A random free port of the node is used if it is not set.
+	Static int `json:"Static,omitempty"`
+}
+
+// Copy returns a deep copy of the port
+func (p *Port) Copy() *Port {
+	if p == nil {
+		return nil
+	}
+	np := new(Port)
+	*np = *p
+	return np
+}
+
+// Validate is used to check a port for reasonable configuration
+func (p *Port) Validate() error {
+	if p == nil {
+		return errors.New("empty/nil port")
+	}
+	var mErr error
+	if !portNameRegex.MatchString(p.Name) {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid port name %q: must be lowercase alphanumeric "+
+			"characters or dashes, starting with a letter", p.Name))
+	}
+	if p.Target <= 0 || p.Target > 65535 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid target of port %s: %d", p.Name, p.Target))
+	}
+	if p.Static < 0 || p.Static > 65535 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid static port of port %s: %d", p.Name, p.Static))
+	}
+	return mErr
+}
+
+// ExecutionPort is a port of a task published by an execution, along with the address at which
+// the port is reachable.
+type ExecutionPort struct {
+	// Name is the name of the port in the task
+	Name string `json:"Name"`
+
+	// Target is the port the task listens on
+	Target int `json:"Target"`
+
+	// Host is the host of the compute node running the execution
+	Host string `json:"Host"`
+
+	// Port is the port of the compute node the port is published on
+	Port int `json:"Port"`
+}
+
+// Address returns the host:port at which the port is reachable
+func (p *ExecutionPort) Address() string {
+	return net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+}
+
+// Copy returns a deep copy of the execution port
+func (p *ExecutionPort) Copy() *ExecutionPort {
+	if p == nil {
+		return nil
+	}
+	np := new(ExecutionPort)
+	*np = *p
+	return np
+}
@@ -4,10 +4,14 @@ import "github.com/bacalhau-project/bacalhau/pkg/models"
 
 type RegisterRequest struct {
 	Info models.NodeInfo
+	// PublicKey is the key of the node that credentials are issued for, if the node uses credentials
+	PublicKey string `json:",omitempty"`
 }
 type RegisterResponse struct {
 	Accepted bool
 	Reason   string
+	// Credentials are issued to nodes that registered a public key once they are approved
+	Credentials string `json:",omitempty"`
 }
 
 type UpdateInfoRequest struct {
@@ -17,6 +21,8 @@ type UpdateInfoRequest struct {
 type UpdateInfoResponse struct {
 	Accepted bool
 	Reason   string
+	// Credentials are renewed every time an approved node updates its info
+	Credentials string `json:",omitempty"`
 }
 
 type UpdateResourcesRequest struct {
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// RetentionPolicy decides how long terminal jobs, along with their executions, evaluations
+// and history, are kept in the job store before being pruned. A job is retained by the first
+// policy that matches its namespace and state.
+type RetentionPolicy struct {
+	// Namespace is the namespace the policy applies to. Empty means all namespaces.
+	Namespace string `json:"Namespace,omitempty"`
+
+	// States are the terminal states the policy applies to. Empty means all terminal states.
+	States []JobStateType `json:"States,omitempty"`
+
+	// MaxAge is how long jobs are kept after they last changed state. Zero means no age limit.
+	MaxAge time.Duration `json:"MaxAge,omitempty"`
+
+	// KeepLast is the number of most recent jobs matching the policy that are kept in
+	// each namespace, regardless of their age.
+	KeepLast int `json:"KeepLast,omitempty"`
+}
+
+// PrunedJob is a terminal job that was removed, or would be removed in a dry run,
+// from the job store because of a retention policy.
+type PrunedJob struct {
+	JobID      string       `json:"JobID"`
+	Name       string       `json:"Name"`
+	Namespace  string       `json:"Namespace"`
+	State      JobStateType `json:"State"`
+	ModifyTime int64        `json:"ModifyTime"`
+	// Reason explains which retention limit the job exceeded
+	Reason string `json:"Reason"`
+}
+
+// Normalize normalizes the policy
+func (p *RetentionPolicy) Normalize() {
+	if p == nil {
+		return
+	}
+	p.Namespace = strings.TrimSpace(p.Namespace)
+}
+
+// Validate returns an error if the policy is invalid
+func (p *RetentionPolicy) Validate() error {
+	if p == nil {
+		return errors.New("missing retention policy")
+	}
+	var mErr error
+	if !validate.IsBlank(p.Namespace) && validate.ContainsSpaces(p.Namespace) {
+		mErr = errors.Join(mErr, errors.New("namespace contains whitespace"))
+	}
+	for _, state := range p.States {
+		if !state.IsTerminal() {
+			mErr = errors.Join(mErr, fmt.Errorf("%s is not a terminal job state", state))
+		}
+	}
+	if p.MaxAge < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid max age: %s", p.MaxAge))
+	}
+	if p.KeepLast < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid keep last value: %d", p.KeepLast))
+	}
+	return mErr
+}
+
+// Matches returns true if the policy applies to the job
+func (p *RetentionPolicy) Matches(job *Job) bool {
+	if !job.IsTerminal() {
+		return false
+	}
+	if p.Namespace != "" && p.Namespace != job.Namespace {
+		return false
+	}
+	if len(p.States) == 0 {
+		return true
+	}
+	for _, state := range p.States {
+		if state == job.State.StateType {
+			return true
+		}
+	}
+	return false
+}
+
+// RetainsForever returns true if the policy has no limit, and never prunes the jobs it matches
+func (p *RetentionPolicy) RetainsForever() bool {
+	return p.MaxAge == 0 && p.KeepLast == 0
+}
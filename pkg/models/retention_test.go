@@ -0,0 +1,81 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *RetentionPolicy
+		wantErr bool
+	}{
+		{
+			name:   "all-terminal-jobs",
+			policy: &RetentionPolicy{MaxAge: time.Hour},
+		},
+		{
+			name:   "namespace-and-states",
+			policy: &RetentionPolicy{Namespace: "ci", States: []JobStateType{JobStateTypeFailed, JobStateTypeStopped}, KeepLast: 10},
+		},
+		{
+			name:    "nil",
+			wantErr: true,
+		},
+		{
+			name:    "non-terminal-state",
+			policy:  &RetentionPolicy{States: []JobStateType{JobStateTypeRunning}},
+			wantErr: true,
+		},
+		{
+			name:    "undefined-state",
+			policy:  &RetentionPolicy{States: []JobStateType{JobStateTypeUndefined}},
+			wantErr: true,
+		},
+		{
+			name:    "negative-max-age",
+			policy:  &RetentionPolicy{MaxAge: -time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "negative-keep-last",
+			policy:  &RetentionPolicy{KeepLast: -1},
+			wantErr: true,
+		},
+		{
+			name:    "namespace-with-spaces",
+			policy:  &RetentionPolicy{Namespace: "my namespace"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRetentionPolicy_Matches(t *testing.T) {
+	job := func(namespace string, state JobStateType) *Job {
+		return &Job{Namespace: namespace, State: NewJobState(state)}
+	}
+
+	policy := &RetentionPolicy{}
+	assert.True(t, policy.Matches(job("default", JobStateTypeCompleted)))
+	assert.False(t, policy.Matches(job("default", JobStateTypeRunning)))
+
+	policy = &RetentionPolicy{Namespace: "ci", States: []JobStateType{JobStateTypeFailed}}
+	assert.True(t, policy.Matches(job("ci", JobStateTypeFailed)))
+	assert.False(t, policy.Matches(job("ci", JobStateTypeCompleted)))
+	assert.False(t, policy.Matches(job("default", JobStateTypeFailed)))
+}
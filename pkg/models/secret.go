@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// maxSecretNameLength is the maximum length of the name of a secret
+const maxSecretNameLength = 128
+
+// secretNamePattern is the pattern the names of secrets must match
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// envNamePattern is the pattern the environment variables secrets are injected as must match
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Secret is a sensitive value, such as an API key or a password, that is stored encrypted
+// by the requester. Tasks of the jobs in the same namespace reference secrets by name, and
+// their values are only delivered to the compute nodes running the executions of the jobs.
+type Secret struct {
+	// Namespace is the namespace of the jobs that can reference the secret
+	Namespace string `json:"Namespace"`
+
+	// Name identifies the secret within its namespace
+	Name string `json:"Name"`
+
+	// Value is the sensitive value of the secret. It is never returned by the APIs.
+	Value string `json:"Value,omitempty"`
+
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// Normalize normalizes the secret
+func (s *Secret) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Namespace = strings.TrimSpace(s.Namespace)
+	s.Name = strings.TrimSpace(s.Name)
+}
+
+// Copy returns a copy of the secret
+func (s *Secret) Copy() *Secret {
+	if s == nil {
+		return nil
+	}
+	ns := new(Secret)
+	*ns = *s
+	return ns
+}
+
+// Redacted returns a copy of the secret without its value
+func (s *Secret) Redacted() *Secret {
+	if s == nil {
+		return nil
+	}
+	ns := s.Copy()
+	ns.Value = ""
+	return ns
+}
+
+// Validate returns an error if the secret is invalid
+func (s *Secret) Validate() error {
+	if s == nil {
+		return errors.New("missing secret")
+	}
+	var mErr error
+	if validate.IsBlank(s.Namespace) {
+		mErr = errors.Join(mErr, errors.New("missing secret namespace"))
+	} else if validate.ContainsSpaces(s.Namespace) {
+		mErr = errors.Join(mErr, errors.New("secret namespace contains whitespace"))
+	}
+	mErr = errors.Join(mErr, validateSecretName(s.Name))
+	if s.Value == "" {
+		mErr = errors.Join(mErr, errors.New("missing secret value"))
+	}
+	return mErr
+}
+
+// validateSecretName returns an error if the name is not a valid secret name
+func validateSecretName(name string) error {
+	if validate.IsBlank(name) {
+		return errors.New("missing secret name")
+	}
+	if len(name) > maxSecretNameLength {
+		return fmt.Errorf("secret name %s is longer than %d characters", name, maxSecretNameLength)
+	}
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %s: must start with a letter or a digit, "+
+			"and only contain letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+// SecretReference injects a secret of the job's namespace into a task, either as an
+// environment variable or as a file. Only the name of the secret is part of
+// the job, so its value is never stored with the job nor returned with it.
+type SecretReference struct {
+	// Name is the name of the secret in the namespace of the job
+	Name string `json:"Name"`
+
+	// Env is the environment variable the value of the secret is set to
+	Env string `json:"Env,omitempty"`
+
+	// Path is the absolute path of the file the value of the secret is written to
+	Path string `json:"Path,omitempty"`
+}
+
+// Normalize normalizes the secret reference
+func (r *SecretReference) Normalize() {
+	if r == nil {
+		return
+	}
+	r.Name = strings.TrimSpace(r.Name)
+	r.Env = strings.TrimSpace(r.Env)
+	r.Path = strings.TrimSpace(r.Path)
+}
+
+// Copy returns a copy of the secret reference
+func (r *SecretReference) Copy() *SecretReference {
+	if r == nil {
+		return nil
+	}
+	nr := new(SecretReference)
+	*nr = *r
+	return nr
+}
+
+// Validate returns an error if the secret reference is invalid
+func (r *SecretReference) Validate() error {
+	if r == nil {
+		return errors.New("missing secret reference")
+	}
+	mErr := validateSecretName(r.Name)
+	switch {
+	case r.Env == "" && r.Path == "":
+		mErr = errors.Join(mErr, fmt.Errorf("secret %s must be injected as either an env var or a file", r.Name))
+	case r.Env != "" && r.Path != "":
+		mErr = errors.Join(mErr, fmt.Errorf("secret %s cannot be injected as both an env var and a file", r.Name))
+	case r.Env != "" && !envNamePattern.MatchString(r.Env):
+		mErr = errors.Join(mErr, fmt.Errorf("invalid env var name %s for secret %s", r.Env, r.Name))
+	case r.Path != "" && (!path.IsAbs(r.Path) || path.Clean(r.Path) != r.Path || r.Path == "/"):
+		mErr = errors.Join(mErr, fmt.Errorf("invalid path %s for secret %s: must be a clean absolute file path", r.Path, r.Name))
+	}
+	return mErr
+}
@@ -0,0 +1,117 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  Secret
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			secret: Secret{Namespace: "default", Name: "api-key.v1_2", Value: "value"},
+		},
+		{
+			name:    "missing-namespace",
+			secret:  Secret{Name: "api-key", Value: "value"},
+			wantErr: true,
+		},
+		{
+			name:    "missing-value",
+			secret:  Secret{Namespace: "default", Name: "api-key"},
+			wantErr: true,
+		},
+		{
+			name:    "name-with-slash",
+			secret:  Secret{Namespace: "default", Name: "api/key", Value: "value"},
+			wantErr: true,
+		},
+		{
+			name:    "name-starting-with-dot",
+			secret:  Secret{Namespace: "default", Name: ".api-key", Value: "value"},
+			wantErr: true,
+		},
+		{
+			name:    "name-too-long",
+			secret:  Secret{Namespace: "default", Name: strings.Repeat("a", maxSecretNameLength+1), Value: "value"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.secret.Validate(); tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecret_Redacted(t *testing.T) {
+	secret := &Secret{Namespace: "default", Name: "api-key", Value: "value"}
+	redacted := secret.Redacted()
+	assert.Empty(t, redacted.Value)
+	assert.Equal(t, "api-key", redacted.Name)
+	assert.Equal(t, "value", secret.Value, "original secret should not be modified")
+}
+
+func TestSecretReference_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     SecretReference
+		wantErr bool
+	}{
+		{name: "env", ref: SecretReference{Name: "api-key", Env: "API_KEY"}},
+		{name: "path", ref: SecretReference{Name: "api-key", Path: "/run/secrets/api-key"}},
+		{name: "neither", ref: SecretReference{Name: "api-key"}, wantErr: true},
+		{name: "both", ref: SecretReference{Name: "api-key", Env: "API_KEY", Path: "/key"}, wantErr: true},
+		{name: "invalid-env", ref: SecretReference{Name: "api-key", Env: "1API-KEY"}, wantErr: true},
+		{name: "relative-path", ref: SecretReference{Name: "api-key", Path: "secrets/key"}, wantErr: true},
+		{name: "unclean-path", ref: SecretReference{Name: "api-key", Path: "/run/../key"}, wantErr: true},
+		{name: "root-path", ref: SecretReference{Name: "api-key", Path: "/"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ref.Validate(); tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTask_ValidateSecrets(t *testing.T) {
+	newTask := func(env map[string]string, refs ...*SecretReference) *Task {
+		task, err := NewTaskBuilder().
+			Name("task").
+			Engine(NewSpecConfig("docker")).
+			Build()
+		require.NoError(t, err)
+		task.Env = env
+		task.Secrets = refs
+		return task
+	}
+
+	task := newTask(nil, &SecretReference{Name: "a", Env: "A"}, &SecretReference{Name: "b", Path: "/b"})
+	require.NoError(t, task.ValidateSubmission())
+
+	task = newTask(map[string]string{"A": "plain"}, &SecretReference{Name: "a", Env: "A"})
+	assert.ErrorContains(t, task.ValidateSubmission(), "A")
+
+	task = newTask(nil, &SecretReference{Name: "a", Env: "A"}, &SecretReference{Name: "b", Env: "A"})
+	assert.Error(t, task.ValidateSubmission())
+
+	task = newTask(nil, &SecretReference{Name: "a", Path: "/key"}, &SecretReference{Name: "b", Path: "/key"})
+	assert.Error(t, task.ValidateSubmission())
+}
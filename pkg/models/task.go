@@ -21,6 +21,9 @@ type Task struct {
 	// Map of environment variables to be used by the driver
 	Env map[string]string `json:"Env,omitempty"`
 
+	// Secrets of the job's namespace injected into the task as environment variables or files
+	Secrets []*SecretReference `json:"Secrets,omitempty"`
+
 	// Meta is used to associate arbitrary metadata with this task.
 	Meta map[string]string `json:"Meta,omitempty"`
 
@@ -37,6 +40,12 @@ type Task struct {
 	Network *NetworkConfig `json:"Network,omitempty"`
 
 	Timeouts *TimeoutConfig `json:"Timeouts,omitempty"`
+
+	// Checkpoint enables the checkpoints of the task, if set
+	Checkpoint *CheckpointConfig `json:"Checkpoint,omitempty"`
+
+	// HealthCheck is run periodically against the task of a long-running job, if set
+	HealthCheck *HealthCheck `json:"HealthCheck,omitempty"`
 }
 
 func (t *Task) MetricAttributes() []attribute.KeyValue {
@@ -61,6 +70,9 @@ func (t *Task) Normalize() {
 	if t.ResultPaths == nil {
 		t.ResultPaths = make([]*ResultPath, 0)
 	}
+	if t.Secrets == nil {
+		t.Secrets = make([]*SecretReference, 0)
+	}
 	if t.ResourcesConfig == nil {
 		t.ResourcesConfig = &ResourcesConfig{}
 	}
@@ -79,8 +91,11 @@ func (t *Task) Normalize() {
 	t.ResourcesConfig.Normalize()
 	NormalizeSlice(t.InputSources)
 	NormalizeSlice(t.ResultPaths)
+	NormalizeSlice(t.Secrets)
 	t.Network.Normalize()
 	t.ResourcesConfig.Normalize()
+	t.Checkpoint.Normalize()
+	t.HealthCheck.Normalize()
 }
 
 func (t *Task) Copy() *Task {
@@ -96,8 +111,11 @@ func (t *Task) Copy() *Task {
 	nt.ResultPaths = CopySlice(t.ResultPaths)
 	nt.Meta = maps.Clone(t.Meta)
 	nt.Env = maps.Clone(t.Env)
+	nt.Secrets = CopySlice(t.Secrets)
 	nt.Network = t.Network.Copy()
 	nt.Timeouts = t.Timeouts.Copy()
+	nt.Checkpoint = t.Checkpoint.Copy()
+	nt.HealthCheck = t.HealthCheck.Copy()
 	return nt
 }
 
@@ -139,23 +157,106 @@ func (t *Task) ValidateSubmission() error {
 	if len(t.ResultPaths) > 0 && t.Publisher.IsEmpty() {
 		mErr = errors.Join(mErr, errors.New("publisher must be set if result paths are set"))
 	}
+	if t.Checkpoint != nil {
+		mErr = errors.Join(mErr, t.validateCheckpoint())
+	}
+	if t.HealthCheck != nil {
+		mErr = errors.Join(mErr, t.validateHealthCheck())
+	}
+	if len(t.Secrets) > 0 {
+		mErr = errors.Join(mErr, t.validateSecrets())
+	}
 
 	seenInputAliases := make(map[string]bool)
 	for _, input := range t.InputSources {
 		if input.Alias != "" && seenInputAliases[input.Alias] {
 			mErr = errors.Join(mErr, fmt.Errorf("input source with alias %s already exist", input.Alias))
 		}
+		if input.Alias == CheckpointInputAlias {
+			mErr = errors.Join(mErr, fmt.Errorf("input source alias %s is reserved for checkpoints", input.Alias))
+		}
 		seenInputAliases[input.Alias] = true
 	}
 
 	return mErr
 }
 
+func (t *Task) validateCheckpoint() error {
+	var mErr error
+	if err := t.Checkpoint.Validate(); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("checkpoint validation failed: %v", err))
+	}
+	if t.Publisher.IsEmpty() {
+		mErr = errors.Join(mErr, errors.New("publisher must be set if checkpoints are enabled"))
+	}
+	for _, result := range t.ResultPaths {
+		if result.Name == CheckpointResultName {
+			mErr = errors.Join(mErr, fmt.Errorf("result path name %s is reserved for checkpoints", CheckpointResultName))
+		}
+		if result.Path == t.Checkpoint.Path {
+			mErr = errors.Join(mErr, fmt.Errorf("result path %s is also the checkpoint path", result.Path))
+		}
+	}
+	return mErr
+}
+
+func (t *Task) validateHealthCheck() error {
+	var mErr error
+	if err := t.HealthCheck.Validate(); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("health check validation failed: %v", err))
+	}
+	if t.HealthCheck.Type != HealthCheckCommand && (t.Network == nil || t.Network.Disabled()) {
+		mErr = errors.Join(mErr, fmt.Errorf("%s health checks require the task to have network access", t.HealthCheck.Type))
+	}
+	return mErr
+}
+
+func (t *Task) validateSecrets() error {
+	if err := ValidateSlice(t.Secrets); err != nil {
+		return fmt.Errorf("secret validation failed: %v", err)
+	}
+	var mErr error
+	seenEnv := make(map[string]bool)
+	seenPaths := make(map[string]bool)
+	for _, secret := range t.Secrets {
+		if secret.Env != "" {
+			if _, ok := t.Env[secret.Env]; ok {
+				mErr = errors.Join(mErr, fmt.Errorf("env var %s of secret %s is also set in the task env", secret.Env, secret.Name))
+			} else if seenEnv[secret.Env] {
+				mErr = errors.Join(mErr, fmt.Errorf("env var %s is set by more than one secret", secret.Env))
+			}
+			seenEnv[secret.Env] = true
+		}
+		if secret.Path != "" {
+			if seenPaths[secret.Path] {
+				mErr = errors.Join(mErr, fmt.Errorf("path %s is set by more than one secret", secret.Path))
+			}
+			seenPaths[secret.Path] = true
+		}
+	}
+	return mErr
+}
+
 // ToBuilder returns a new task builder with the same values as the task
 func (t *Task) ToBuilder() *TaskBuilder {
 	return NewTaskBuilderFromTask(t)
 }
 
+// HasSecretFiles returns true if any secret is injected into the task as a file
+func (t *Task) HasSecretFiles() bool {
+	for _, secret := range t.Secrets {
+		if secret != nil && secret.Path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPorts returns true if the task has ports to publish on the compute node
+func (t *Task) HasPorts() bool {
+	return t.Network != nil && len(t.Network.Ports) > 0
+}
+
 func (t *Task) AllStorageTypes() []string {
 	var types []string
 	for _, a := range t.InputSources {
@@ -56,6 +56,11 @@ func (b *TaskBuilder) ResultPaths(resultPaths ...*ResultPath) *TaskBuilder {
 	return b
 }
 
+func (b *TaskBuilder) Secrets(secrets ...*SecretReference) *TaskBuilder {
+	b.task.Secrets = secrets
+	return b
+}
+
 func (b *TaskBuilder) Network(network *NetworkConfig) *TaskBuilder {
 	b.task.Network = network
 	return b
@@ -66,6 +71,16 @@ func (b *TaskBuilder) Timeouts(timeouts *TimeoutConfig) *TaskBuilder {
 	return b
 }
 
+func (b *TaskBuilder) Checkpoint(checkpoint *CheckpointConfig) *TaskBuilder {
+	b.task.Checkpoint = checkpoint
+	return b
+}
+
+func (b *TaskBuilder) HealthCheck(healthCheck *HealthCheck) *TaskBuilder {
+	b.task.HealthCheck = healthCheck
+	return b
+}
+
 func (b *TaskBuilder) Build() (*Task, error) {
 	b.task.Normalize()
 	return b.task, b.task.Validate()
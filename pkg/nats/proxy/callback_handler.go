@@ -51,25 +51,38 @@ func (h *CallbackHandler) handle(msg *nats.Msg) {
 
 	subjectParts := strings.Split(msg.Subject, ".")
 	method := subjectParts[len(subjectParts)-1]
+	sourceNodeID := subjectParts[len(subjectParts)-2]
 
 	switch method {
 	case OnBidComplete:
-		processCallback(ctx, msg, h.callback.OnBidComplete)
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnBidComplete)
 	case OnRunComplete:
-		processCallback(ctx, msg, h.callback.OnRunComplete)
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnRunComplete)
 	case OnCancelComplete:
-		processCallback(ctx, msg, h.callback.OnCancelComplete)
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnCancelComplete)
 	case OnComputeFailure:
-		processCallback(ctx, msg, h.callback.OnComputeFailure)
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnComputeFailure)
+	case OnCheckpoint:
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnCheckpoint)
+	case OnHealthCheck:
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnHealthCheck)
+	case OnPortsPublished:
+		processCallback(ctx, msg, sourceNodeID, h.callback.OnPortsPublished)
 	default:
 		// Noop, not subscribed to this method
 		return
 	}
 }
 
+// routedCallback is implemented by all callbacks, which identify the compute node that sent them.
+type routedCallback interface {
+	SourceNodeID() string
+}
+
 func processCallback[Request any](
 	ctx context.Context,
 	msg *nats.Msg,
+	sourceNodeID string,
 	f callbackHandler[Request]) {
 	request := new(Request)
 	err := json.Unmarshal(msg.Data, request)
@@ -78,5 +91,12 @@ func processCallback[Request any](
 		return
 	}
 
+	// drop callbacks that a node sent on behalf of another node
+	if routed, ok := any(request).(routedCallback); ok && routed.SourceNodeID() != sourceNodeID {
+		log.Ctx(ctx).Warn().Msgf("dropping %s from node %s published on subject %s",
+			reflect.TypeOf(request), routed.SourceNodeID(), msg.Subject)
+		return
+	}
+
 	go f(ctx, *request)
 }
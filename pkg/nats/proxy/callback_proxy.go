@@ -31,25 +31,37 @@ func NewCallbackProxy(params CallbackProxyParams) *CallbackProxy {
 }
 
 func (p *CallbackProxy) OnBidComplete(ctx context.Context, result compute.BidResult) {
-	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnBidComplete, result)
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnBidComplete, result)
 }
 
 func (p *CallbackProxy) OnRunComplete(ctx context.Context, result compute.RunResult) {
-	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnRunComplete, result)
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnRunComplete, result)
 }
 
 func (p *CallbackProxy) OnCancelComplete(ctx context.Context, result compute.CancelResult) {
-	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnCancelComplete, result)
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnCancelComplete, result)
 }
 
 func (p *CallbackProxy) OnComputeFailure(ctx context.Context, result compute.ComputeError) {
-	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnComputeFailure, result)
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnComputeFailure, result)
+}
+
+func (p *CallbackProxy) OnCheckpoint(ctx context.Context, result compute.CheckpointResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnCheckpoint, result)
+}
+
+func (p *CallbackProxy) OnHealthCheck(ctx context.Context, result compute.HealthCheckResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnHealthCheck, result)
+}
+
+func (p *CallbackProxy) OnPortsPublished(ctx context.Context, result compute.PortsResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata, OnPortsPublished, result)
 }
 
 func proxyCallbackRequest(
 	ctx context.Context,
 	conn *nats.Conn,
-	destNodeID string,
+	routing compute.RoutingMetadata,
 	method string,
 	request interface{}) {
 	// deserialize the request object
@@ -59,7 +71,8 @@ func proxyCallbackRequest(
 		return
 	}
 
-	subject := callbackPublishSubject(destNodeID, method)
+	destNodeID := routing.TargetPeerID
+	subject := callbackPublishSubject(destNodeID, routing.SourcePeerID, method)
 	log.Ctx(ctx).Trace().Msgf("Sending request %+v to subject %s", request, subject)
 
 	// We use Publish instead of Request as Orchestrator callbacks do not return a response, for now.
@@ -9,6 +9,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
@@ -35,7 +36,10 @@ type handlerWithResponse[Request, Response any] func(context.Context, Request) (
 
 // NewComputeHandler creates a new ComputeHandler.
 func NewComputeHandler(params ComputeHandlerParams) (*ComputeHandler, error) {
-	streamingClient, err := stream.NewClient(stream.ClientParams{Conn: params.Conn})
+	streamingClient, err := stream.NewClient(stream.ClientParams{
+		Conn:        params.Conn,
+		InboxPrefix: stream.NodeInboxPrefix(params.Name),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +80,9 @@ func handleRequest(msg *nats.Msg, handler *ComputeHandler) {
 		processAndRespond(ctx, handler.conn, msg, handler.computeEndpoint.CancelExecution)
 	case ExecutionLogs:
 		processAndStream(ctx, handler.streamingClient, msg, handler.computeEndpoint.ExecutionLogs)
+	case Exec:
+		// exec sessions are interactive and long-lived, and must not block other requests
+		go processExec(ctx, handler, msg)
 	default:
 		// Noop, not subscribed to this method
 		return
@@ -156,3 +163,65 @@ func processAndStream[Request, Response any](ctx context.Context, streamingClien
 	}
 	_ = writer.Close()
 }
+
+// processExec runs a command inside an execution, forwarding the input streamed by the requester
+// to the command and streaming its output back. The command is stopped if the requester goes away.
+func processExec(ctx context.Context, handler *ComputeHandler, msg *nats.Msg) {
+	if msg.Reply == "" {
+		log.Ctx(ctx).Error().Msgf("exec request on %s has no reply subject", msg.Subject)
+		return
+	}
+	writer := handler.streamingClient.NewWriter(msg.Reply)
+	request := new(execRequest)
+	err := json.Unmarshal(msg.Data, request)
+	if err != nil {
+		_ = writer.CloseWithCode(stream.CloseBadRequest, fmt.Sprintf("error decoding exec request: %s", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	inputs, err := handler.streamingClient.Subscribe(ctx, request.InputSubject)
+	if err != nil {
+		_ = writer.CloseWithCode(stream.CloseInternalServerErr, fmt.Sprintf("error subscribing to exec input: %s", err))
+		return
+	}
+	input := make(chan models.ExecInput)
+	go func() {
+		defer close(input)
+		// the input stream is only closed when the requester goes away
+		defer cancel()
+		for res := range inputs {
+			if res.Err != nil {
+				return
+			}
+			in := new(models.ExecInput)
+			if err := json.Unmarshal(res.Value, in); err != nil {
+				log.Ctx(ctx).Error().Msgf("error decoding exec input: %s", err)
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case input <- *in:
+			}
+		}
+	}()
+	request.Input = input
+
+	outputs, err := handler.computeEndpoint.Exec(ctx, request.ExecRequest)
+	if err != nil {
+		_ = writer.CloseWithCode(stream.CloseInternalServerErr, fmt.Sprintf("error in exec handler: %s", err))
+		return
+	}
+	// the requester starts streaming the input once it receives this first, empty, output
+	if _, err = writer.WriteObject(concurrency.NewAsyncValue(models.ExecOutput{})); err != nil {
+		log.Ctx(ctx).Error().Msgf("error writing response to stream: %s", err)
+	}
+	for res := range outputs {
+		if _, err = writer.WriteObject(res); err != nil {
+			log.Ctx(ctx).Error().Msgf("error writing response to stream: %s", err)
+		}
+	}
+	_ = writer.Close()
+}
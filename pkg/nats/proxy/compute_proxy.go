@@ -89,6 +89,73 @@ func (p *ComputeProxy) ExecutionLogs(ctx context.Context, request compute.Execut
 		})
 }
 
+// Exec runs a command inside an execution on a remote compute node. The input of the command
+// is streamed to the compute node on its own subject, once the compute node is ready to receive it.
+func (p *ComputeProxy) Exec(ctx context.Context, request compute.ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	inputSubject := newNodeInbox(request.TargetPeerID)
+	outputs, err := proxyStreamingRequest[execRequest, models.ExecOutput](
+		ctx, p.streamingClient, &BaseRequest[execRequest]{
+			TargetNodeID: request.TargetPeerID,
+			Method:       Exec,
+			Body:         execRequest{ExecRequest: request, InputSubject: inputSubject},
+		})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ch := make(chan *concurrency.AsyncResult[models.ExecOutput], asyncRequestChanLen)
+	go func() {
+		defer close(ch)
+		// stops forwarding the input once the command exited
+		defer cancel()
+		ready := false
+		for output := range outputs {
+			if !ready {
+				ready = true
+				go p.forwardExecInput(ctx, request.Input, inputSubject)
+				// the compute node sends an empty output once it is ready to receive the input
+				if output.Err == nil && isEmptyExecOutput(output.Value) {
+					continue
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- output:
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// forwardExecInput publishes the input of a command to the compute node running it. Closing the input
+// only closes the standard input of the command, and the command is stopped when the context is done.
+func (p *ComputeProxy) forwardExecInput(ctx context.Context, input <-chan models.ExecInput, subject string) {
+	writer := p.streamingClient.NewWriter(subject)
+	for {
+		select {
+		case <-ctx.Done():
+			_ = writer.CloseWithCode(stream.CloseGoingAway)
+			return
+		case in, ok := <-input:
+			if !ok {
+				in = models.ExecInput{EOF: true}
+				input = nil
+			}
+			if _, err := writer.WriteObject(in); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("error writing exec input to stream")
+			}
+		}
+	}
+}
+
+func isEmptyExecOutput(output models.ExecOutput) bool {
+	return output.Type == 0 && len(output.Data) == 0 && !output.Exited
+}
+
 func proxyRequest[Request any, Response any](
 	ctx context.Context,
 	conn *nats.Conn,
@@ -1,6 +1,11 @@
 package proxy
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+)
 
 const (
 	ComputeEndpointSubjectPrefix = "node.compute"
@@ -12,11 +17,15 @@ const (
 	BidRejected     = "BidRejected/v1"
 	CancelExecution = "CancelExecution/v1"
 	ExecutionLogs   = "ExecutionLogs/v1"
+	Exec            = "Exec/v1"
 
 	OnBidComplete    = "OnBidComplete/v1"
 	OnRunComplete    = "OnRunComplete/v1"
 	OnCancelComplete = "OnCancelComplete/v1"
 	OnComputeFailure = "OnComputeFailure/v1"
+	OnCheckpoint     = "OnCheckpoint/v1"
+	OnHealthCheck    = "OnHealthCheck/v1"
+	OnPortsPublished = "OnPortsPublished/v1"
 
 	RegisterNode    = "RegisterNode/v1"
 	UpdateNodeInfo  = "UpdateNodeInfo/v1"
@@ -31,8 +40,10 @@ func computeEndpointSubscribeSubject(nodeID string) string {
 	return fmt.Sprintf("%s.%s.>", ComputeEndpointSubjectPrefix, nodeID)
 }
 
-func callbackPublishSubject(nodeID string, method string) string {
-	return fmt.Sprintf("%s.%s.%s", CallbackSubjectPrefix, nodeID, method)
+// callbackPublishSubject returns the subject a compute node sends callbacks to an orchestrator on.
+// The subject includes the ID of the sending node, so that nodes can be restricted to their own subjects.
+func callbackPublishSubject(nodeID string, sourceNodeID string, method string) string {
+	return fmt.Sprintf("%s.%s.%s.%s", CallbackSubjectPrefix, nodeID, sourceNodeID, method)
 }
 
 func callbackSubscribeSubject(nodeID string) string {
@@ -46,3 +57,14 @@ func managementPublishSubject(nodeID string, method string) string {
 func managementSubscribeSubject() string {
 	return fmt.Sprintf("%s.>", ManagementSubjectPrefix)
 }
+
+// NodeInboxPrefix returns the prefix of the inboxes of a node, which its NATS client is configured
+// with so that the node can be restricted to subscribing to its own inboxes.
+func NodeInboxPrefix(nodeID string) string {
+	return nats.InboxPrefix + nodeID
+}
+
+// newNodeInbox returns a new inbox that only the node can subscribe to
+func newNodeInbox(nodeID string) string {
+	return NodeInboxPrefix(nodeID) + "." + nuid.Next()
+}
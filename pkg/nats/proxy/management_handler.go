@@ -3,6 +3,7 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -49,10 +50,11 @@ func (h *ManagementHandler) handle(msg *nats.Msg) {
 
 	subjectParts := strings.Split(msg.Subject, ".")
 	method := subjectParts[len(subjectParts)-1]
+	nodeID := subjectParts[len(subjectParts)-2]
 
 	switch method {
 	case RegisterNode:
-		response, err := h.processRegistration(ctx, msg)
+		response, err := h.processRegistration(ctx, msg, nodeID)
 		asyncResponse := concurrency.NewAsyncResult(response, err)
 
 		if err := sendResponse(h.conn, msg.Reply, asyncResponse); err != nil {
@@ -60,7 +62,7 @@ func (h *ManagementHandler) handle(msg *nats.Msg) {
 		}
 
 	case UpdateNodeInfo:
-		response, err := h.processUpdateInfo(ctx, msg)
+		response, err := h.processUpdateInfo(ctx, msg, nodeID)
 		asyncResponse := concurrency.NewAsyncResult(response, err)
 
 		if err := sendResponse(h.conn, msg.Reply, asyncResponse); err != nil {
@@ -68,7 +70,7 @@ func (h *ManagementHandler) handle(msg *nats.Msg) {
 		}
 
 	case UpdateResources:
-		response, err := h.processUpdateResources(ctx, msg)
+		response, err := h.processUpdateResources(ctx, msg, nodeID)
 		asyncResponse := concurrency.NewAsyncResult(response, err)
 
 		if err := sendResponse(h.conn, msg.Reply, asyncResponse); err != nil {
@@ -79,35 +81,56 @@ func (h *ManagementHandler) handle(msg *nats.Msg) {
 	}
 }
 
-func (h *ManagementHandler) processRegistration(ctx context.Context, msg *nats.Msg) (*requests.RegisterResponse, error) {
+func (h *ManagementHandler) processRegistration(
+	ctx context.Context, msg *nats.Msg, nodeID string) (*requests.RegisterResponse, error) {
 	request := new(requests.RegisterRequest)
 	err := json.Unmarshal(msg.Data, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("error decoding %s: %s", reflect.TypeOf(request), err)
 		return nil, err
 	}
+	if err = checkNodeID(nodeID, request.Info.NodeID); err != nil {
+		return nil, err
+	}
 
 	return h.endpoint.Register(ctx, *request)
 }
 
-func (h *ManagementHandler) processUpdateInfo(ctx context.Context, msg *nats.Msg) (*requests.UpdateInfoResponse, error) {
+func (h *ManagementHandler) processUpdateInfo(
+	ctx context.Context, msg *nats.Msg, nodeID string) (*requests.UpdateInfoResponse, error) {
 	request := new(requests.UpdateInfoRequest)
 	err := json.Unmarshal(msg.Data, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("error decoding %s: %s", reflect.TypeOf(request), err)
 		return nil, err
 	}
+	if err = checkNodeID(nodeID, request.Info.NodeID); err != nil {
+		return nil, err
+	}
 
 	return h.endpoint.UpdateInfo(ctx, *request)
 }
 
-func (h *ManagementHandler) processUpdateResources(ctx context.Context, msg *nats.Msg) (*requests.UpdateResourcesResponse, error) {
+func (h *ManagementHandler) processUpdateResources(
+	ctx context.Context, msg *nats.Msg, nodeID string) (*requests.UpdateResourcesResponse, error) {
 	request := new(requests.UpdateResourcesRequest)
 	err := json.Unmarshal(msg.Data, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Msgf("error decoding %s: %s", reflect.TypeOf(request), err)
 		return nil, err
 	}
+	if err = checkNodeID(nodeID, request.NodeID); err != nil {
+		return nil, err
+	}
 
 	return h.endpoint.UpdateResources(ctx, *request)
 }
+
+// checkNodeID returns an error if a request is about another node than the one that sent it,
+// as nodes are only allowed to publish on subjects with their own node ID.
+func checkNodeID(subjectNodeID string, requestNodeID string) error {
+	if subjectNodeID != requestNodeID {
+		return fmt.Errorf("request of node %s sent by node %s", requestNodeID, subjectNodeID)
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package proxy
 
+import "github.com/bacalhau-project/bacalhau/pkg/compute"
+
 type BaseRequest[T any] struct {
 	TargetNodeID string
 	Method       string
@@ -11,7 +13,9 @@ func (r *BaseRequest[T]) ComputeEndpoint() string {
 	return computeEndpointPublishSubject(r.TargetNodeID, r.Method)
 }
 
-// OrchestratorEndpoint return the orchestrator endpoint for the base request.
-func (r *BaseRequest[T]) OrchestratorEndpoint() string {
-	return callbackPublishSubject(r.TargetNodeID, r.Method)
+// execRequest is the request sent to compute nodes to run a command inside an execution,
+// along with the subject on which the input of the command is streamed by the requester.
+type execRequest struct {
+	compute.ExecRequest
+	InputSubject string
 }
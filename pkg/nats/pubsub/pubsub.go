@@ -23,6 +23,12 @@ type PubSubParams struct {
 	Conn *nats.Conn
 }
 
+// SubjectChecker is implemented by messages that identify their sender, and that are only
+// valid when published on the sender's own subject. Messages published on other subjects are dropped.
+type SubjectChecker interface {
+	CheckSubject(subject string) error
+}
+
 type PubSub[T any] struct {
 	subject             string
 	subscriptionSubject string
@@ -93,6 +99,12 @@ func (p *PubSub[T]) readMessage(ctx context.Context, msg *nats.Msg) {
 		log.Ctx(ctx).Error().Err(err).Msgf("error unmarshalling nats payload from subject %s", msg.Subject)
 		return
 	}
+	if checker, ok := any(payload).(SubjectChecker); ok {
+		if err = checker.CheckSubject(msg.Subject); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("dropping message published on subject %s", msg.Subject)
+			return
+		}
+	}
 
 	err = p.subscriber.Handle(ctx, payload)
 	if err != nil {
@@ -22,13 +22,20 @@ const RequestChanLen = 16
 // similar to https://github.com/nats-io/nats.go/blob/main/nats.go#L4015
 const (
 	inboxPrefix    = "_SINBOX."
-	inboxPrefixLen = len(inboxPrefix)
 	replySuffixLen = 8 // Gives us 62^8
 	rdigits        = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
 	base           = 62
-	nuidSize       = 22
 )
 
+// InboxSubjects matches the inboxes of all streaming clients
+const InboxSubjects = inboxPrefix + ">"
+
+// NodeInboxPrefix returns the prefix of the inboxes of the streaming clients of a node,
+// so that the node can be restricted to subscribing to its own inboxes.
+func NodeInboxPrefix(nodeID string) string {
+	return inboxPrefix + nodeID
+}
+
 // streamingBucket is a structure to hold the response channel and context
 type streamingBucket struct {
 	// ctx is the context for the channel consumer that requested and waiting for messages
@@ -60,12 +67,15 @@ func (sb *streamingBucket) close() {
 
 type ClientParams struct {
 	Conn *nats.Conn
+	// InboxPrefix is optional, and the inboxes of the client are under _SINBOX if it is not set
+	InboxPrefix string
 }
 
 // Client represents a NATS streaming client.
 type Client struct {
-	Conn *nats.Conn
-	mu   sync.RWMutex // Protects access to the response map.
+	Conn        *nats.Conn
+	inboxPrefix string       // The prefix of the inboxes including trailing .
+	mu          sync.RWMutex // Protects access to the response map.
 
 	// response handler
 	respSub       string                      // The wildcard subject
@@ -80,9 +90,13 @@ type Client struct {
 // NewClient creates a new NATS client.
 func NewClient(params ClientParams) (*Client, error) {
 	nc := &Client{
-		Conn:     params.Conn,
-		respMap:  make(map[string]*streamingBucket),
-		respRand: rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // using same inbox naming as nats
+		Conn:        params.Conn,
+		inboxPrefix: inboxPrefix,
+		respMap:     make(map[string]*streamingBucket),
+		respRand:    rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // using same inbox naming as nats
+	}
+	if params.InboxPrefix != "" {
+		nc.inboxPrefix = params.InboxPrefix + "."
 	}
 
 	// Setup response subscription.
@@ -106,12 +120,7 @@ func NewClient(params ClientParams) (*Client, error) {
 
 // newInbox will return a new inbox string for this client.
 func (nc *Client) newInbox() string {
-	var b [inboxPrefixLen + nuidSize]byte
-	pres := b[:inboxPrefixLen]
-	copy(pres, inboxPrefix)
-	ns := b[inboxPrefixLen:]
-	copy(ns, nuid.Next())
-	return string(b[:])
+	return nc.inboxPrefix + nuid.Next()
 }
 
 // respHandler is the global response handler. It will look up
@@ -132,35 +141,19 @@ func (nc *Client) respHandler(m *nats.Msg) {
 		return
 	}
 
-	closeErr := new(CloseError)
-	var asyncResult *concurrency.AsyncResult[[]byte]
-
-	sMsg := new(StreamingMsg)
-	err := json.Unmarshal(m.Data, sMsg)
-	if err != nil {
-		closeErr = &CloseError{Code: CloseUnsupportedData, Text: err.Error()}
-		asyncResult = concurrency.NewAsyncError[[]byte](closeErr)
-	} else {
-		switch sMsg.Type {
-		case streamingMsgTypeClose:
-			closeErr = sMsg.CloseError
-			asyncResult = concurrency.NewAsyncError[[]byte](closeErr)
-		case streamingMsgTypeData:
-			asyncResult = concurrency.NewAsyncValue(sMsg.Data)
-		default:
-			log.Warn().Msgf("Unknown streaming message type: %d", sMsg.Type)
-			return
-		}
+	asyncResult, ok := decodeStreamingMsg(m.Data)
+	if !ok {
+		return
 	}
 
 	// if normal closure, then we close the channel without adding any error message
-	if closeErr != nil && closeErr.Code == CloseNormalClosure {
+	if asyncResult == nil {
 		nc.cleanupBucket(rt)
 		return
 	}
 
 	// Explicitly check if the context is done before attempting to send a message.
-	if err = bucket.ctx.Err(); err != nil {
+	if err := bucket.ctx.Err(); err != nil {
 		// The context is already done. Handle cleanup and exit.
 		nc.cleanupBucket(rt)
 		return
@@ -180,6 +173,29 @@ func (nc *Client) respHandler(m *nats.Msg) {
 	}
 }
 
+// decodeStreamingMsg decodes a streaming message into the result to deliver to the consumer of
+// the stream. The result is nil if the stream was closed normally, and false is returned if the
+// message type is unknown.
+func decodeStreamingMsg(data []byte) (*concurrency.AsyncResult[[]byte], bool) {
+	sMsg := new(StreamingMsg)
+	err := json.Unmarshal(data, sMsg)
+	if err != nil {
+		return concurrency.NewAsyncError[[]byte](&CloseError{Code: CloseUnsupportedData, Text: err.Error()}), true
+	}
+	switch sMsg.Type {
+	case streamingMsgTypeClose:
+		if sMsg.CloseError != nil && sMsg.CloseError.Code == CloseNormalClosure {
+			return nil, true
+		}
+		return concurrency.NewAsyncError[[]byte](sMsg.CloseError), true
+	case streamingMsgTypeData:
+		return concurrency.NewAsyncValue(sMsg.Data), true
+	default:
+		log.Warn().Msgf("Unknown streaming message type: %d", sMsg.Type)
+		return nil, false
+	}
+}
+
 func (nc *Client) cleanupBucket(token string) {
 	nc.mu.Lock()
 	defer nc.mu.Unlock()
@@ -258,6 +274,88 @@ func (nc *Client) createNewRequestAndSend(ctx context.Context, subj string, data
 	return bucket, nil
 }
 
+// Subscribe reads a stream that is written by a Writer to the given subject, instead of the
+// reply subject of a request opened with OpenStream. This allows the producer of a stream
+// to also consume a stream from the requester, e.g. the input of an interactive command.
+// The returned channel is closed when the stream is closed or the context is done.
+func (nc *Client) Subscribe(ctx context.Context, subject string) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	if ctx == nil {
+		return nil, nats.ErrInvalidContext
+	}
+	bucket := &subscriptionBucket{streamingBucket: newStreamingBucket(ctx, subject)}
+	sub, err := nc.Conn.Subscribe(subject, bucket.handle)
+	if err != nil {
+		bucket.close()
+		return nil, err
+	}
+	bucket.setSubscription(sub)
+	go func() {
+		<-bucket.ctx.Done()
+		bucket.unsubscribe()
+	}()
+	return bucket.ch, nil
+}
+
+// subscriptionBucket is a streamingBucket that is fed by its own subscription, which
+// is unsubscribed when the stream is closed.
+type subscriptionBucket struct {
+	*streamingBucket
+	mu     sync.Mutex
+	sub    *nats.Subscription
+	closed bool
+}
+
+func (b *subscriptionBucket) handle(m *nats.Msg) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	asyncResult, ok := decodeStreamingMsg(m.Data)
+	if !ok {
+		return
+	}
+	if asyncResult == nil {
+		b.doUnsubscribe()
+		return
+	}
+	select {
+	case b.ch <- asyncResult:
+		if asyncResult.Err != nil {
+			b.doUnsubscribe()
+		}
+	case <-b.ctx.Done():
+		b.doUnsubscribe()
+	}
+}
+
+func (b *subscriptionBucket) setSubscription(sub *nats.Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sub = sub
+	if b.closed {
+		_ = sub.Unsubscribe()
+	}
+}
+
+func (b *subscriptionBucket) unsubscribe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.doUnsubscribe()
+}
+
+// doUnsubscribe closes the bucket and its subscription. Lock should be held.
+func (b *subscriptionBucket) doUnsubscribe() {
+	if b.closed {
+		return
+	}
+	b.closed = true
+	if b.sub != nil {
+		_ = b.sub.Unsubscribe()
+	}
+	b.close()
+}
+
 // NewWriter creates a new streaming writer.
 func (nc *Client) NewWriter(subject string) *Writer {
 	return &Writer{
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -75,6 +76,67 @@ func (suite *ClientTestSuite) TestRequestWithContextCancellation() {
 	suite.Require().Error(err, "Expected an error due to cancelled context")
 }
 
+func (suite *ClientTestSuite) TestSubscribe() {
+	subject := nats.NewInbox()
+	ch, err := suite.streamingClient.Subscribe(suite.ctx, subject)
+	suite.Require().NoError(err)
+
+	writer := suite.streamingClient.NewWriter(subject)
+	_, err = writer.Write([]byte("input 1"))
+	suite.Require().NoError(err)
+	_, err = writer.Write([]byte("input 2"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(writer.Close())
+
+	for _, expected := range []string{"input 1", "input 2"} {
+		select {
+		case res := <-ch:
+			suite.Require().NotNil(res)
+			suite.Require().NoError(res.Err)
+			suite.Require().Equal(expected, string(res.Value))
+		case <-time.After(1 * time.Second):
+			suite.Fail("Timeout waiting for the stream data")
+		}
+	}
+	select {
+	case _, ok := <-ch:
+		suite.Require().False(ok, "Expected the channel to be closed after a normal closure")
+	case <-time.After(1 * time.Second):
+		suite.Fail("Timeout waiting for the channel to be closed")
+	}
+}
+
+func (suite *ClientTestSuite) TestSubscribeCloseWithCode() {
+	subject := nats.NewInbox()
+	ch, err := suite.streamingClient.Subscribe(suite.ctx, subject)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.streamingClient.NewWriter(subject).CloseWithCode(CloseGoingAway))
+	select {
+	case res := <-ch:
+		suite.Require().NotNil(res)
+		var closeErr *CloseError
+		suite.Require().ErrorAs(res.Err, &closeErr)
+		suite.Require().Equal(CloseGoingAway, closeErr.Code)
+	case <-time.After(1 * time.Second):
+		suite.Fail("Timeout waiting for the close error")
+	}
+}
+
+func (suite *ClientTestSuite) TestSubscribeContextCancel() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	ch, err := suite.streamingClient.Subscribe(ctx, nats.NewInbox())
+	suite.Require().NoError(err)
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		suite.Require().False(ok, "Expected the channel to be closed")
+	case <-time.After(1 * time.Second):
+		suite.Fail("Timeout waiting for the channel to be closed")
+	}
+}
+
 func TestClientTestSuite(t *testing.T) {
 	suite.Run(t, new(ClientTestSuite))
 }
@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nkeys"
+	"github.com/rs/zerolog/log"
+)
+
+// bootstrapConnectionTTL is how long compute nodes without credentials stay connected. The node
+// reconnects afterwards, so that it uses the credentials it was issued in the meantime.
+const bootstrapConnectionTTL = 30 * time.Second
+
+// authenticator authenticates the clients of the NATS server of an orchestrator. Clients that
+// present credentials signed by the issuer get the permissions of their credentials. Other
+// clients must present the auth secret, if one is set, and only get bootstrap permissions when
+// credentials are required.
+//
+// The server discards user JWTs when it does not run in operator mode, so clients send their
+// credentials as their token instead, along with the key they were issued for, and prove they
+// hold the key by signing the nonce of the connection.
+type authenticator struct {
+	authSecret         string
+	issuerPublicKey    string
+	requireCredentials bool
+}
+
+// Check returns true if the client is allowed to connect, and registers its permissions
+func (a *authenticator) Check(c server.ClientAuthentication) bool {
+	opts := c.GetOpts()
+	if claims, err := jwt.DecodeUserClaims(opts.Token); err == nil {
+		return a.checkCredentials(c, opts, claims)
+	}
+
+	if a.authSecret != "" && subtle.ConstantTimeCompare([]byte(opts.Token), []byte(a.authSecret)) != 1 {
+		return false
+	}
+	if !a.requireCredentials {
+		c.RegisterUser(&server.User{Username: opts.Name})
+		return true
+	}
+	if opts.Name == "" || strings.ContainsAny(opts.Name, reservedChars) {
+		log.Debug().Msgf("rejecting connection without credentials from %s: invalid node ID %q", c.RemoteAddress(), opts.Name)
+		return false
+	}
+	c.RegisterUser(&server.User{
+		Username:           opts.Name,
+		Permissions:        bootstrapPermissions(opts.Name),
+		ConnectionDeadline: time.Now().Add(bootstrapConnectionTTL),
+	})
+	return true
+}
+
+// checkCredentials verifies that the credentials were issued by the issuer, have not expired,
+// and that the client holds the key they were issued for.
+func (a *authenticator) checkCredentials(
+	c server.ClientAuthentication, opts *server.ClientOpts, claims *jwt.UserClaims) bool {
+	if claims.Issuer != a.issuerPublicKey {
+		log.Debug().Msgf("rejecting connection from %s: credentials of %s issued by unknown issuer %s",
+			c.RemoteAddress(), claims.Name, claims.Issuer)
+		return false
+	}
+	if claims.Expires > 0 && !time.Now().Before(time.Unix(claims.Expires, 0)) {
+		log.Debug().Msgf("rejecting connection from %s: credentials of %s expired", c.RemoteAddress(), claims.Name)
+		return false
+	}
+	if opts.Name != claims.Name {
+		log.Debug().Msgf("rejecting connection from %s: credentials of %s used by %s", c.RemoteAddress(), claims.Name, opts.Name)
+		return false
+	}
+	if opts.Nkey != claims.Subject {
+		log.Debug().Msgf("rejecting connection from %s: credentials of %s used with another key", c.RemoteAddress(), claims.Name)
+		return false
+	}
+
+	// the client proves it holds the key of the credentials by signing the nonce of the connection
+	signature, err := base64.RawURLEncoding.DecodeString(opts.Sig)
+	if err != nil {
+		signature, err = base64.StdEncoding.DecodeString(opts.Sig)
+		if err != nil {
+			return false
+		}
+	}
+	publicKey, err := nkeys.FromPublicKey(opts.Nkey)
+	if err != nil {
+		return false
+	}
+	if err = publicKey.Verify(c.GetNonce(), signature); err != nil {
+		log.Debug().Msgf("rejecting connection from %s: invalid signature of credentials of %s", c.RemoteAddress(), claims.Name)
+		return false
+	}
+
+	user := &server.User{
+		Username:    claims.Name,
+		Permissions: serverPermissions(claims.Permissions),
+	}
+	// the node has to reconnect with renewed credentials once they expire
+	if claims.Expires > 0 {
+		user.ConnectionDeadline = time.Unix(claims.Expires, 0)
+	}
+	c.RegisterUser(user)
+	return true
+}
+
+// compile-time interface check
+var _ server.Authentication = (*authenticator)(nil)
@@ -0,0 +1,255 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultCredentialsTTL is how long the credentials issued to compute nodes are valid for.
+	// Approved nodes renew their credentials every time they update their info, while nodes that
+	// are rejected or deleted are disconnected once their credentials expire.
+	DefaultCredentialsTTL = time.Hour
+
+	issuerSeedFileName      = "credentials-issuer.nk"
+	nodeSeedFileName        = "node.nk"
+	nodeCredentialsFileName = "node.jwt"
+)
+
+// Issuer issues the credentials that compute nodes use to connect to the orchestrators. The
+// credentials are JWTs signed by the issuer's key, which bind the ID of a node to its own key
+// and restrict the subjects the node can publish and subscribe to.
+type Issuer struct {
+	keyPair        nkeys.KeyPair
+	publicKey      string
+	ttl            time.Duration
+	heartbeatTopic string
+}
+
+// NewIssuer creates an issuer with the key stored in the directory, which is created on first use.
+// Orchestrators of the same cluster must share the key, so that they accept each other's credentials.
+// An ephemeral key is used if the directory is not set.
+func NewIssuer(dir string, ttl time.Duration, heartbeatTopic string) (*Issuer, error) {
+	var keyPair nkeys.KeyPair
+	var err error
+	if dir == "" {
+		keyPair, err = nkeys.CreateAccount()
+	} else {
+		keyPair, err = loadOrCreateKeyPair(filepath.Join(dir, issuerSeedFileName), nkeys.CreateAccount)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials issuer key: %w", err)
+	}
+	publicKey, err := keyPair.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultCredentialsTTL
+	}
+	return &Issuer{
+		keyPair:        keyPair,
+		publicKey:      publicKey,
+		ttl:            ttl,
+		heartbeatTopic: heartbeatTopic,
+	}, nil
+}
+
+// PublicKey returns the public key that the credentials are verified with
+func (i *Issuer) PublicKey() string {
+	return i.publicKey
+}
+
+// IssueCredentials returns credentials of the compute node for its public key, which are
+// restricted to the subjects of the node.
+func (i *Issuer) IssueCredentials(ctx context.Context, nodeID string, publicKey string) (string, error) {
+	if !nkeys.IsValidPublicUserKey(publicKey) {
+		return "", fmt.Errorf("invalid public key %s of node %s", publicKey, nodeID)
+	}
+	claims := jwt.NewUserClaims(publicKey)
+	claims.Name = nodeID
+	claims.Expires = time.Now().Add(i.ttl).Unix()
+	claims.Permissions = computeNodePermissions(nodeID, i.heartbeatTopic)
+	credentials, err := claims.Encode(i.keyPair)
+	if err != nil {
+		return "", fmt.Errorf("failed to issue credentials of node %s: %w", nodeID, err)
+	}
+	log.Ctx(ctx).Debug().Msgf("issued credentials of node %s valid until %s",
+		nodeID, time.Unix(claims.Expires, 0).UTC().Format(time.RFC3339))
+	return credentials, nil
+}
+
+// orchestratorCredentials returns the options of NATS clients to connect with credentials without
+// any restriction nor expiry, which the orchestrator uses to connect to its own server.
+func (i *Issuer) orchestratorCredentials(nodeID string) ([]nats.Option, error) {
+	keyPair, err := nkeys.CreateUser()
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := keyPair.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.NewUserClaims(publicKey)
+	claims.Name = nodeID
+	credentials, err := claims.Encode(i.keyPair)
+	if err != nil {
+		return nil, err
+	}
+	return credentialsOptions(keyPair, publicKey, func() string { return credentials }), nil
+}
+
+// NodeCredentials holds the key of a compute node, and the credentials that the orchestrators
+// issue for the key once the node is approved. Both are kept in a directory, so that the node
+// keeps its identity across restarts.
+type NodeCredentials struct {
+	mu          sync.RWMutex
+	keyPair     nkeys.KeyPair
+	publicKey   string
+	credentials string
+	expires     time.Time
+	path        string
+}
+
+// NewNodeCredentials loads the key and credentials of the compute node from the directory,
+// and creates the key on first use.
+func NewNodeCredentials(dir string) (*NodeCredentials, error) {
+	if dir == "" {
+		return nil, errors.New("a store directory is required to keep the node credentials")
+	}
+	keyPair, err := loadOrCreateKeyPair(filepath.Join(dir, nodeSeedFileName), nkeys.CreateUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load node key: %w", err)
+	}
+	publicKey, err := keyPair.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	c := &NodeCredentials{
+		keyPair:   keyPair,
+		publicKey: publicKey,
+		path:      filepath.Join(dir, nodeCredentialsFileName),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read node credentials: %w", err)
+	}
+	if len(data) > 0 {
+		// credentials that are no longer valid, e.g. issued for a previous key, are replaced
+		// once the node registers again
+		if err = c.set(string(data)); err != nil {
+			log.Warn().Err(err).Msgf("ignoring node credentials stored in %s", c.path)
+		}
+	}
+	return c, nil
+}
+
+// PublicKey returns the public key of the node, which the credentials are issued for
+func (c *NodeCredentials) PublicKey() string {
+	return c.publicKey
+}
+
+// HasCredentials returns true if the node holds credentials that have not expired
+func (c *NodeCredentials) HasCredentials() bool {
+	return c.current() != ""
+}
+
+// SetCredentials stores the credentials issued to the node. They are used the next time
+// the node connects to an orchestrator.
+func (c *NodeCredentials) SetCredentials(credentials string) error {
+	if err := c.set(credentials); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, []byte(credentials), 0600); err != nil {
+		return fmt.Errorf("failed to store node credentials: %w", err)
+	}
+	return nil
+}
+
+func (c *NodeCredentials) set(credentials string) error {
+	claims, err := jwt.DecodeUserClaims(credentials)
+	if err != nil {
+		return fmt.Errorf("invalid node credentials: %w", err)
+	}
+	if claims.Subject != c.publicKey {
+		return errors.New("node credentials were issued for another key")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.credentials = credentials
+	c.expires = time.Time{}
+	if claims.Expires > 0 {
+		c.expires = time.Unix(claims.Expires, 0)
+	}
+	return nil
+}
+
+// current returns the credentials of the node, or an empty string if they expired
+func (c *NodeCredentials) current() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.expires.IsZero() && !time.Now().Before(c.expires) {
+		return ""
+	}
+	return c.credentials
+}
+
+// clientOptions returns the options of NATS clients to connect with the credentials of the node.
+// The credentials are read on every connection, so that renewed credentials are used once the
+// orchestrator closes the connection. Nodes without credentials connect with the auth secret.
+func (c *NodeCredentials) clientOptions(authSecret string) []nats.Option {
+	return credentialsOptions(c.keyPair, c.publicKey, func() string {
+		if credentials := c.current(); credentials != "" {
+			return credentials
+		}
+		return authSecret
+	})
+}
+
+// credentialsOptions returns the options of NATS clients to send the credentials as their token,
+// and to prove they hold the key the credentials were issued for by signing the nonce of the server.
+func credentialsOptions(keyPair nkeys.KeyPair, publicKey string, token func() string) []nats.Option {
+	return []nats.Option{
+		nats.Nkey(publicKey, keyPair.Sign),
+		nats.TokenHandler(token),
+	}
+}
+
+// loadOrCreateKeyPair reads the seed of a key pair from the file, or creates a new key pair
+// and writes its seed to the file if it does not exist.
+func loadOrCreateKeyPair(path string, create func() (nkeys.KeyPair, error)) (nkeys.KeyPair, error) {
+	seed, err := os.ReadFile(path)
+	if err == nil {
+		return nkeys.FromSeed(seed)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	keyPair, err := create()
+	if err != nil {
+		return nil, err
+	}
+	seed, err = keyPair.Seed()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(path, seed, 0600); err != nil {
+		return nil, err
+	}
+	return keyPair, nil
+}
@@ -0,0 +1,297 @@
+//go:build unit || !integration
+
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/network"
+	"github.com/bacalhau-project/bacalhau/pkg/nats/proxy"
+	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
+)
+
+const (
+	testAuthSecret     = "sekret"
+	testHeartbeatTopic = "heartbeat"
+	noMessageTimeout   = 200 * time.Millisecond
+)
+
+type CredentialsSuite struct {
+	suite.Suite
+	ctx          context.Context
+	orchestrator *NATSTransport
+}
+
+func (s *CredentialsSuite) SetupTest() {
+	s.ctx = context.Background()
+	port, err := network.GetFreePort()
+	s.Require().NoError(err)
+
+	s.orchestrator, err = NewNATSTransport(s.ctx, &NATSTransportConfig{
+		NodeID:                 "orchestrator",
+		Port:                   port,
+		IsRequesterNode:        true,
+		StoreDir:               s.T().TempDir(),
+		AuthSecret:             testAuthSecret,
+		RequireNodeCredentials: true,
+		HeartbeatTopic:         testHeartbeatTopic,
+	})
+	s.Require().NoError(err)
+}
+
+func (s *CredentialsSuite) TearDownTest() {
+	s.Require().NoError(s.orchestrator.Close(s.ctx))
+}
+
+// connect connects a compute node to the orchestrator, and returns the asynchronous
+// errors the connection receives, such as permission violations.
+func (s *CredentialsSuite) connect(nodeID string, options ...nats.Option) (*nats.Conn, chan error, error) {
+	errs := make(chan error, 10)
+	options = append(options,
+		nats.Name(nodeID),
+		nats.NoReconnect(),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			errs <- err
+		}),
+	)
+	conn, err := nats.Connect(s.orchestrator.Config.Orchestrators[0], options...)
+	if err == nil {
+		s.T().Cleanup(conn.Close)
+	}
+	return conn, errs, err
+}
+
+// subscribe subscribes the orchestrator to the subject
+func (s *CredentialsSuite) subscribe(subject string) *nats.Subscription {
+	sub, err := s.orchestrator.natsClient.Client.SubscribeSync(subject)
+	s.Require().NoError(err)
+	s.Require().NoError(s.orchestrator.natsClient.Client.Flush())
+	return sub
+}
+
+func (s *CredentialsSuite) assertReceived(sub *nats.Subscription) {
+	_, err := sub.NextMsg(time.Second)
+	s.NoError(err)
+}
+
+func (s *CredentialsSuite) assertNotReceived(sub *nats.Subscription) {
+	_, err := sub.NextMsg(noMessageTimeout)
+	s.ErrorIs(err, nats.ErrTimeout)
+}
+
+func (s *CredentialsSuite) assertPermissionViolation(errs chan error) {
+	select {
+	case err := <-errs:
+		s.ErrorContains(err, "Permissions Violation")
+	case <-time.After(time.Second):
+		s.Fail("expected a permission violation")
+	}
+}
+
+func (s *CredentialsSuite) newNodeCredentials(nodeID string) *NodeCredentials {
+	credentials, err := NewNodeCredentials(s.T().TempDir())
+	s.Require().NoError(err)
+	issued, err := s.orchestrator.Issuer().IssueCredentials(s.ctx, nodeID, credentials.PublicKey())
+	s.Require().NoError(err)
+	s.Require().NoError(credentials.SetCredentials(issued))
+	return credentials
+}
+
+func (s *CredentialsSuite) TestRejectsWrongAuthSecret() {
+	_, _, err := s.connect("node1", nats.Token("wrong"))
+	s.ErrorIs(err, nats.ErrAuthorization)
+
+	_, _, err = s.connect("node1")
+	s.ErrorIs(err, nats.ErrAuthorization)
+}
+
+func (s *CredentialsSuite) TestBootstrapPermissions() {
+	credentials, err := NewNodeCredentials(s.T().TempDir())
+	s.Require().NoError(err)
+	s.False(credentials.HasCredentials())
+
+	conn, errs, err := s.connect("node1", credentials.clientOptions(testAuthSecret)...)
+	s.Require().NoError(err)
+
+	// the node can register
+	registrations := s.subscribe("node.management.node1." + proxy.RegisterNode)
+	s.Require().NoError(conn.Publish("node.management.node1."+proxy.RegisterNode, nil))
+	s.assertReceived(registrations)
+
+	// but cannot use any other subject
+	updates := s.subscribe("node.management.node1." + proxy.UpdateNodeInfo)
+	s.Require().NoError(conn.Publish("node.management.node1."+proxy.UpdateNodeInfo, nil))
+	s.assertNotReceived(updates)
+	s.assertPermissionViolation(errs)
+
+	_, err = conn.SubscribeSync("node.compute.node1.>")
+	s.Require().NoError(err)
+	s.assertPermissionViolation(errs)
+}
+
+func (s *CredentialsSuite) TestBootstrapRejectsInvalidNodeID() {
+	_, _, err := s.connect("node.>", nats.Token(testAuthSecret))
+	s.ErrorIs(err, nats.ErrAuthorization)
+}
+
+func (s *CredentialsSuite) TestCredentialsPermissions() {
+	credentials := s.newNodeCredentials("node1")
+	s.True(credentials.HasCredentials())
+
+	// credentials do not require the auth secret
+	conn, errs, err := s.connect("node1", credentials.clientOptions("")...)
+	s.Require().NoError(err)
+
+	own := s.subscribe(callbackPublishSubjectForTest("node1", proxy.OnRunComplete))
+	s.Require().NoError(conn.Publish(callbackPublishSubjectForTest("node1", proxy.OnRunComplete), nil))
+	s.assertReceived(own)
+
+	heartbeats := s.subscribe(testHeartbeatTopic + ".node1")
+	s.Require().NoError(conn.Publish(testHeartbeatTopic+".node1", nil))
+	s.assertReceived(heartbeats)
+
+	// the node cannot act on behalf of another node
+	other := s.subscribe(callbackPublishSubjectForTest("node2", proxy.OnRunComplete))
+	s.Require().NoError(conn.Publish(callbackPublishSubjectForTest("node2", proxy.OnRunComplete), nil))
+	s.assertNotReceived(other)
+	s.assertPermissionViolation(errs)
+
+	otherHeartbeats := s.subscribe(testHeartbeatTopic + ".node2")
+	s.Require().NoError(conn.Publish(testHeartbeatTopic+".node2", nil))
+	s.assertNotReceived(otherHeartbeats)
+	s.assertPermissionViolation(errs)
+
+	// nor receive the requests sent to another node
+	_, err = conn.SubscribeSync("node.compute.node2.>")
+	s.Require().NoError(err)
+	s.assertPermissionViolation(errs)
+}
+
+func (s *CredentialsSuite) TestRejectsCredentialsOfAnotherNode() {
+	credentials := s.newNodeCredentials("node1")
+	_, _, err := s.connect("node2", credentials.clientOptions("")...)
+	s.ErrorIs(err, nats.ErrAuthorization)
+}
+
+func (s *CredentialsSuite) TestRejectsCredentialsOfAnotherIssuer() {
+	issuer, err := NewIssuer("", time.Hour, testHeartbeatTopic)
+	s.Require().NoError(err)
+	credentials, err := NewNodeCredentials(s.T().TempDir())
+	s.Require().NoError(err)
+	issued, err := issuer.IssueCredentials(s.ctx, "node1", credentials.PublicKey())
+	s.Require().NoError(err)
+	s.Require().NoError(credentials.SetCredentials(issued))
+
+	_, _, err = s.connect("node1", credentials.clientOptions("")...)
+	s.ErrorIs(err, nats.ErrAuthorization)
+}
+
+func (s *CredentialsSuite) TestRejectsExpiredCredentials() {
+	keyPair, err := nkeys.CreateUser()
+	s.Require().NoError(err)
+	publicKey, err := keyPair.PublicKey()
+	s.Require().NoError(err)
+	claims := jwt.NewUserClaims(publicKey)
+	claims.Name = "node1"
+	claims.Expires = time.Now().Add(-time.Minute).Unix()
+	expired, err := claims.Encode(s.orchestrator.Issuer().keyPair)
+	s.Require().NoError(err)
+
+	_, _, err = s.connect("node1", credentialsOptions(keyPair, publicKey, func() string { return expired })...)
+	s.ErrorIs(err, nats.ErrAuthorization)
+}
+
+func (s *CredentialsSuite) TestRejectsCredentialsWithoutKey() {
+	credentials := s.newNodeCredentials("node1")
+	impostor, err := nkeys.CreateUser()
+	s.Require().NoError(err)
+
+	// the impostor claims the key of the credentials, but cannot sign with it
+	_, _, err = s.connect("node1", credentialsOptions(impostor, credentials.PublicKey(), credentials.current)...)
+	s.ErrorIs(err, nats.ErrAuthorization)
+}
+
+func (s *CredentialsSuite) TestNodeCredentialsAreStored() {
+	dir := s.T().TempDir()
+	credentials, err := NewNodeCredentials(dir)
+	s.Require().NoError(err)
+	issued, err := s.orchestrator.Issuer().IssueCredentials(s.ctx, "node1", credentials.PublicKey())
+	s.Require().NoError(err)
+	s.Require().NoError(credentials.SetCredentials(issued))
+
+	reloaded, err := NewNodeCredentials(dir)
+	s.Require().NoError(err)
+	s.Equal(credentials.PublicKey(), reloaded.PublicKey())
+	s.True(reloaded.HasCredentials())
+
+	// credentials issued for another key are not accepted
+	other, err := NewNodeCredentials(s.T().TempDir())
+	s.Require().NoError(err)
+	s.Error(other.SetCredentials(issued))
+	s.False(other.HasCredentials())
+}
+
+func (s *CredentialsSuite) TestIssuerKeyIsStored() {
+	dir := s.T().TempDir()
+	issuer, err := NewIssuer(dir, 0, testHeartbeatTopic)
+	s.Require().NoError(err)
+	reloaded, err := NewIssuer(dir, 0, testHeartbeatTopic)
+	s.Require().NoError(err)
+	s.Equal(issuer.PublicKey(), reloaded.PublicKey())
+	s.Equal(DefaultCredentialsTTL, reloaded.ttl)
+}
+
+func (s *CredentialsSuite) TestSplitAuthSecret() {
+	servers, authSecret := splitAuthSecret([]string{"nats://sekret@orch1:4222", "nats://orch2:4222"}, "")
+	s.Equal([]string{"nats://orch1:4222", "nats://orch2:4222"}, servers)
+	s.Equal("sekret", authSecret)
+
+	// the configured auth secret takes precedence
+	_, authSecret = splitAuthSecret([]string{"nats://other@orch1:4222"}, "sekret")
+	s.Equal("sekret", authSecret)
+}
+
+func callbackPublishSubjectForTest(sourceNodeID string, method string) string {
+	return proxy.CallbackSubjectPrefix + ".orchestrator." + sourceNodeID + "." + method
+}
+
+func TestCredentialsSuite(t *testing.T) {
+	suite.Run(t, new(CredentialsSuite))
+}
+
+func (s *CredentialsSuite) TestInboxPermissions() {
+	credentials := s.newNodeCredentials("node1")
+	options := append(credentials.clientOptions(""), nats.CustomInboxPrefix(proxy.NodeInboxPrefix("node1")))
+	conn, errs, err := s.connect("node1", options...)
+	s.Require().NoError(err)
+
+	// the node receives replies on its own inbox
+	subject := "node.management.node1." + proxy.UpdateNodeInfo
+	_, err = s.orchestrator.natsClient.Client.Subscribe(subject, func(m *nats.Msg) {
+		s.NoError(m.Respond([]byte("ok")))
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(s.orchestrator.natsClient.Client.Flush())
+	reply, err := conn.Request(subject, nil, time.Second)
+	s.Require().NoError(err)
+	s.Equal("ok", string(reply.Data))
+
+	// but cannot subscribe to the inboxes of another node
+	for _, inbox := range []string{
+		proxy.NodeInboxPrefix("node2") + ".>",
+		stream.NodeInboxPrefix("node2") + ".>",
+		nats.InboxPrefix + ">",
+		stream.InboxSubjects,
+	} {
+		_, err = conn.SubscribeSync(inbox)
+		s.Require().NoError(err)
+		s.assertPermissionViolation(errs)
+	}
+}
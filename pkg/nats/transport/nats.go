@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/nats-io/nats-server/v2/server"
 	"github.com/nats-io/nats.go"
@@ -49,6 +51,18 @@ type NATSTransportConfig struct {
 	ClusterPort              int
 	ClusterAdvertisedAddress string
 	ClusterPeers             []string
+
+	// TLS configures TLS for client and cluster connections
+	TLS TLSConfig
+
+	// RequireNodeCredentials requires compute nodes to connect with credentials issued by the
+	// orchestrators once they are approved, which restrict them to their own subjects. Nodes that
+	// are not approved yet can only register. Compute nodes keep their key and credentials in StoreDir.
+	RequireNodeCredentials bool
+	// CredentialsTTL is how long the credentials issued to compute nodes are valid for
+	CredentialsTTL time.Duration
+	// HeartbeatTopic is the topic compute nodes send heartbeats to, which credentials allow them to publish on
+	HeartbeatTopic string
 }
 
 func (c *NATSTransportConfig) Validate() error {
@@ -63,6 +77,8 @@ func (c *NATSTransportConfig) Validate() error {
 		mErr = errors.Join(mErr, fmt.Errorf("node ID '%s' contains one or more reserved characters: %s", c.NodeID, reservedChars))
 	}
 
+	mErr = errors.Join(mErr, c.TLS.Validate(c.IsRequesterNode))
+
 	if c.IsRequesterNode {
 		mErr = errors.Join(mErr, validate.IsGreaterThanZero(c.Port, "port %d must be greater than zero", c.Port))
 
@@ -75,6 +91,9 @@ func (c *NATSTransportConfig) Validate() error {
 		if validate.IsEmpty(c.Orchestrators) {
 			mErr = errors.Join(mErr, errors.New("missing orchestrators"))
 		}
+		if c.RequireNodeCredentials && validate.IsBlank(c.StoreDir) {
+			mErr = errors.Join(mErr, errors.New("node credentials require a store directory"))
+		}
 	}
 	return mErr
 }
@@ -89,6 +108,8 @@ type NATSTransport struct {
 	nodeInfoPubSub    pubsub.PubSub[models.NodeState]
 	nodeInfoDecorator models.NodeInfoDecorator
 	managementProxy   compute.ManagementEndpoint
+	issuer            *Issuer
+	nodeCredentials   *NodeCredentials
 }
 
 //nolint:funlen
@@ -99,21 +120,46 @@ func NewNATSTransport(ctx context.Context,
 		return nil, fmt.Errorf("error validating nats transport config. %w", err)
 	}
 
-	var sm *nats_helper.ServerManager
+	transport := &NATSTransport{
+		nodeID:            config.NodeID,
+		Config:            config,
+		nodeInfoDecorator: models.NoopNodeInfoDecorator{},
+	}
+
 	if config.IsRequesterNode {
 		var err error
 
+		// the issuer is always created, so that orchestrators of a cluster accept the credentials
+		// issued by each other whether or not they require them
+		transport.issuer, err = NewIssuer(config.StoreDir, config.CredentialsTTL, config.HeartbeatTopic)
+		if err != nil {
+			return nil, err
+		}
+
 		// create nats server with servers acting as its cluster peers
 		serverOpts := &server.Options{
-			ServerName:             config.NodeID,
-			Port:                   config.Port,
-			ClientAdvertise:        config.AdvertisedAddress,
-			Authorization:          config.AuthSecret,
+			ServerName:      config.NodeID,
+			Port:            config.Port,
+			ClientAdvertise: config.AdvertisedAddress,
+			CustomClientAuthentication: &authenticator{
+				authSecret:         config.AuthSecret,
+				issuerPublicKey:    transport.issuer.PublicKey(),
+				requireCredentials: config.RequireNodeCredentials,
+			},
+			AlwaysEnableNonce:      true,
 			Debug:                  true, // will only be used if log level is debug
 			JetStream:              true,
 			DisableJetStreamBanner: true,
 			StoreDir:               config.StoreDir,
 		}
+		if config.TLS.Enabled() {
+			serverOpts.TLSConfig, err = config.TLS.serverTLSConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to configure NATS server TLS: %w", err)
+			}
+			serverOpts.TLS = true
+			serverOpts.TLSVerify = config.TLS.VerifyClients
+		}
 
 		// Only set cluster options if cluster peers are provided. Jetstream doesn't
 		// like the setting to be present with no values, or with values that are
@@ -131,23 +177,36 @@ func NewNATSTransport(ctx context.Context,
 				Port:      config.ClusterPort,
 				Advertise: config.ClusterAdvertisedAddress,
 			}
+			if config.TLS.Enabled() {
+				serverOpts.Cluster.TLSConfig, err = config.TLS.clusterTLSConfig()
+				if err != nil {
+					return nil, fmt.Errorf("failed to configure NATS cluster TLS: %w", err)
+				}
+			}
 		}
 
 		log.Debug().Msgf("Creating NATS server with options: %+v", serverOpts)
-		sm, err = nats_helper.NewServerManager(ctx, nats_helper.ServerManagerParams{
+		transport.natsServer, err = nats_helper.NewServerManager(ctx, nats_helper.ServerManagerParams{
 			Options: serverOpts,
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		config.Orchestrators = append(config.Orchestrators, sm.Server.ClientURL())
+		config.Orchestrators = append(config.Orchestrators, transport.natsServer.Server.ClientURL())
+	} else if config.RequireNodeCredentials {
+		var err error
+		transport.nodeCredentials, err = NewNodeCredentials(config.StoreDir)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	nc, err := CreateClient(ctx, config)
+	nc, err := transport.CreateClient(ctx)
 	if err != nil {
 		return nil, err
 	}
+	transport.natsClient = nc
 
 	// PubSub to publish and consume node info messages
 	nodeInfoPubSub, err := nats_pubsub.NewPubSub[models.NodeState](nats_pubsub.PubSubParams{
@@ -177,34 +236,80 @@ func NewNATSTransport(ctx context.Context,
 		Conn: nc.Client,
 	})
 
-	return &NATSTransport{
-		nodeID:            config.NodeID,
-		natsServer:        sm,
-		natsClient:        nc,
-		Config:            config,
-		computeProxy:      computeProxy,
-		callbackProxy:     computeCallback,
-		nodeInfoPubSub:    nodeInfoPubSub,
-		nodeInfoDecorator: models.NoopNodeInfoDecorator{},
-		managementProxy:   managementProxy,
-	}, nil
+	transport.computeProxy = computeProxy
+	transport.callbackProxy = computeCallback
+	transport.nodeInfoPubSub = nodeInfoPubSub
+	transport.managementProxy = managementProxy
+	return transport, nil
 }
 
-func CreateClient(ctx context.Context, config *NATSTransportConfig) (*nats_helper.ClientManager, error) {
+// CreateClient creates a new NATS client connected to the orchestrators, with the TLS settings
+// and credentials of the node.
+func (t *NATSTransport) CreateClient(ctx context.Context) (*nats_helper.ClientManager, error) {
+	config := t.Config
 	// create nats client
 	log.Debug().Msgf("Creating NATS client with servers: %s", strings.Join(config.Orchestrators, ","))
 	clientOptions := []nats.Option{
 		nats.Name(config.NodeID),
 	}
-	if config.AuthSecret != "" {
+	servers := config.Orchestrators
+
+	switch {
+	case t.issuer != nil:
+		// the orchestrator connects to its own server with unrestricted credentials
+		credentials, err := t.issuer.orchestratorCredentials(config.NodeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create orchestrator credentials: %w", err)
+		}
+		clientOptions = append(clientOptions, credentials...)
+	case t.nodeCredentials != nil:
+		// credentials are sent as the token, so an auth secret in the orchestrator URLs is sent
+		// by the credentials options instead until the node is issued credentials
+		authSecret := config.AuthSecret
+		servers, authSecret = splitAuthSecret(servers, authSecret)
+		clientOptions = append(clientOptions, t.nodeCredentials.clientOptions(authSecret)...)
+	case config.AuthSecret != "":
 		clientOptions = append(clientOptions, nats.Token(config.AuthSecret))
 	}
+
+	if config.IsRequesterNode {
+		if config.TLS.Enabled() {
+			tlsOptions, err := config.TLS.localClientOptions()
+			if err != nil {
+				return nil, err
+			}
+			clientOptions = append(clientOptions, tlsOptions...)
+		}
+	} else {
+		clientOptions = append(clientOptions, config.TLS.clientOptions()...)
+		// compute nodes can only subscribe to their own inboxes
+		clientOptions = append(clientOptions, nats.CustomInboxPrefix(proxy.NodeInboxPrefix(config.NodeID)))
+	}
 	return nats_helper.NewClientManager(ctx,
-		strings.Join(config.Orchestrators, ","),
+		strings.Join(servers, ","),
 		clientOptions...,
 	)
 }
 
+// splitAuthSecret removes the auth secret from the user part of the server URLs, and returns it
+// unless an auth secret is already set.
+func splitAuthSecret(servers []string, authSecret string) ([]string, string) {
+	result := make([]string, 0, len(servers))
+	for _, address := range servers {
+		u, err := url.Parse(address)
+		if err != nil || u.User == nil {
+			result = append(result, address)
+			continue
+		}
+		if authSecret == "" {
+			authSecret = u.User.Username()
+		}
+		u.User = nil
+		result = append(result, u.String())
+	}
+	return result, authSecret
+}
+
 func (t *NATSTransport) RegisterNodeInfoConsumer(ctx context.Context, infostore routing.NodeInfoStore) error {
 	// subscribe to nodeInfo subject and add nodeInfo to nodeInfoStore
 	nodeInfoSubscriber := pubsub.NewChainedSubscriber[models.NodeState](true)
@@ -241,6 +346,17 @@ func (t *NATSTransport) RegisterManagementEndpoint(endpoint compute.ManagementEn
 	return err
 }
 
+// Issuer returns the issuer of compute node credentials, which is only set on orchestrators.
+func (t *NATSTransport) Issuer() *Issuer {
+	return t.issuer
+}
+
+// NodeCredentials returns the credentials of the compute node, which are only set on compute
+// nodes that are required to connect with credentials.
+func (t *NATSTransport) NodeCredentials() *NodeCredentials {
+	return t.nodeCredentials
+}
+
 // ComputeProxy returns the compute proxy.
 func (t *NATSTransport) ComputeProxy() compute.Endpoint {
 	return t.computeProxy
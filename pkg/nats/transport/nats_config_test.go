@@ -113,6 +113,44 @@ func (suite *NATSTransportConfigSuite) TestValidate() {
 			},
 			expectedErrors: []string{"cluster port -1 must be greater than zero"},
 		},
+		{
+			name: "Node Credentials Without Store Dir",
+			config: NATSTransportConfig{
+				NodeID:                 "node6",
+				Orchestrators:          []string{"orch1"},
+				RequireNodeCredentials: true,
+			},
+			expectedErrors: []string{"node credentials require a store directory"},
+		},
+		{
+			name: "TLS Certificate Without Key",
+			config: NATSTransportConfig{
+				NodeID:        "node7",
+				Orchestrators: []string{"orch1"},
+				TLS:           TLSConfig{CertFile: "node.crt"},
+			},
+			expectedErrors: []string{"TLS certificate and key must be set together"},
+		},
+		{
+			name: "TLS Without Certificate in Requester Node",
+			config: NATSTransportConfig{
+				NodeID:          "node8",
+				Port:            4222,
+				IsRequesterNode: true,
+				TLS:             TLSConfig{CACertFile: "ca.crt"},
+			},
+			expectedErrors: []string{"orchestrators require a TLS certificate"},
+		},
+		{
+			name: "Verify Clients Without CA",
+			config: NATSTransportConfig{
+				NodeID:          "node9",
+				Port:            4222,
+				IsRequesterNode: true,
+				TLS:             TLSConfig{CertFile: "node.crt", KeyFile: "node.key", VerifyClients: true},
+			},
+			expectedErrors: []string{"verifying client certificates requires a CA certificate"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"fmt"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
+	"github.com/bacalhau-project/bacalhau/pkg/nats/proxy"
+	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
+	"github.com/bacalhau-project/bacalhau/pkg/node/heartbeat"
+)
+
+// inboxSubjects matches the inboxes that replies to requests are sent to
+const inboxSubjects = nats.InboxPrefix + ">"
+
+// nodeInboxSubjects returns the subjects of the inboxes of the node, which only the node can
+// subscribe to so that it cannot receive the replies and streams sent to other nodes.
+func nodeInboxSubjects(nodeID string) []string {
+	return []string{
+		proxy.NodeInboxPrefix(nodeID) + ".>",
+		stream.NodeInboxPrefix(nodeID) + ".>",
+	}
+}
+
+// computeNodePermissions returns the subjects that a compute node can use. The node can only
+// receive requests, replies and streams sent to itself, and only send callbacks, management
+// requests and heartbeats on its own subjects, so that it cannot act on behalf of other nodes.
+func computeNodePermissions(nodeID string, heartbeatTopic string) jwt.Permissions {
+	var permissions jwt.Permissions
+	permissions.Pub.Allow.Add(
+		fmt.Sprintf("%s.*.%s.>", proxy.CallbackSubjectPrefix, nodeID),
+		fmt.Sprintf("%s.%s.>", proxy.ManagementSubjectPrefix, nodeID),
+		heartbeat.Subject(heartbeatTopic, nodeID),
+		inboxSubjects,
+		stream.InboxSubjects,
+	)
+	permissions.Sub.Allow.Add(fmt.Sprintf("%s.%s.>", proxy.ComputeEndpointSubjectPrefix, nodeID))
+	permissions.Sub.Allow.Add(nodeInboxSubjects(nodeID)...)
+	return permissions
+}
+
+// bootstrapPermissions returns the subjects that a compute node without credentials can use,
+// which only allow it to register with the orchestrators and to receive its credentials.
+func bootstrapPermissions(nodeID string) *server.Permissions {
+	return &server.Permissions{
+		Publish: &server.SubjectPermission{
+			Allow: []string{fmt.Sprintf("%s.%s.%s", proxy.ManagementSubjectPrefix, nodeID, proxy.RegisterNode)},
+		},
+		Subscribe: &server.SubjectPermission{
+			Allow: []string{proxy.NodeInboxPrefix(nodeID) + ".>"},
+		},
+	}
+}
+
+// serverPermissions converts the permissions of credentials to the permissions of a NATS server.
+// Credentials without any permission are not restricted.
+func serverPermissions(permissions jwt.Permissions) *server.Permissions {
+	if permissions.Pub.Empty() && permissions.Sub.Empty() {
+		return nil
+	}
+	return &server.Permissions{
+		Publish: &server.SubjectPermission{
+			Allow: permissions.Pub.Allow,
+			Deny:  permissions.Pub.Deny,
+		},
+		Subscribe: &server.SubjectPermission{
+			Allow: permissions.Sub.Allow,
+			Deny:  permissions.Sub.Deny,
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// TLSConfig configures TLS for the connections of the NATS transport. Orchestrators use the
+// certificate to serve client and cluster connections, while compute nodes use it as a client
+// certificate. Connections are verified against the CA certificate if one is set, which enables
+// mutual TLS between the nodes of the cluster.
+type TLSConfig struct {
+	// CertFile is the path of the PEM encoded certificate of the node
+	CertFile string
+	// KeyFile is the path of the PEM encoded private key of the certificate
+	KeyFile string
+	// CACertFile is the path of the PEM encoded certificate of the cluster CA
+	CACertFile string
+	// VerifyClients requires compute nodes to present a client certificate signed by the
+	// CA when connecting to an orchestrator.
+	VerifyClients bool
+}
+
+// Enabled returns true if any TLS setting is set
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.CACertFile != ""
+}
+
+// Validate returns an error if the TLS settings are incomplete
+func (c TLSConfig) Validate(isRequesterNode bool) error {
+	var mErr error
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		mErr = errors.Join(mErr, errors.New("TLS certificate and key must be set together"))
+	}
+	if isRequesterNode && c.Enabled() && c.CertFile == "" {
+		mErr = errors.Join(mErr, errors.New("orchestrators require a TLS certificate to serve TLS connections"))
+	}
+	if c.VerifyClients && c.CACertFile == "" {
+		mErr = errors.Join(mErr, errors.New("verifying client certificates requires a CA certificate"))
+	}
+	return mErr
+}
+
+// serverTLSConfig returns the TLS config of the client connections of a NATS server
+func (c TLSConfig) serverTLSConfig() (*tls.Config, error) {
+	return server.GenTLSConfig(&server.TLSConfigOpts{
+		CertFile: c.CertFile,
+		KeyFile:  c.KeyFile,
+		CaFile:   c.CACertFile,
+		Verify:   c.VerifyClients,
+	})
+}
+
+// clusterTLSConfig returns the TLS config of the connections between the servers of a cluster,
+// which always verify each other's certificates when a CA is set.
+func (c TLSConfig) clusterTLSConfig() (*tls.Config, error) {
+	return server.GenTLSConfig(&server.TLSConfigOpts{
+		CertFile: c.CertFile,
+		KeyFile:  c.KeyFile,
+		CaFile:   c.CACertFile,
+		Verify:   c.CACertFile != "",
+	})
+}
+
+// clientOptions returns the options of NATS clients connecting to orchestrators
+func (c TLSConfig) clientOptions() []nats.Option {
+	var options []nats.Option
+	if c.CACertFile != "" {
+		options = append(options, nats.RootCAs(c.CACertFile))
+	}
+	if c.CertFile != "" {
+		options = append(options, nats.ClientCert(c.CertFile, c.KeyFile))
+	}
+	return options
+}
+
+// localClientOptions returns the options of NATS clients of an orchestrator connecting to its own
+// server. The server is reached on a local address that its certificate is not issued for, so the
+// certificate is verified against the first name it is issued for instead.
+func (c TLSConfig) localClientOptions() ([]nats.Option, error) {
+	certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+	switch {
+	case len(leaf.DNSNames) > 0:
+		config.ServerName = leaf.DNSNames[0]
+	case len(leaf.IPAddresses) > 0:
+		config.ServerName = leaf.IPAddresses[0].String()
+	default:
+		config.ServerName = leaf.Subject.CommonName
+	}
+
+	roots := x509.NewCertPool()
+	if c.CACertFile != "" {
+		pem, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA certificate: %w", err)
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse TLS CA certificate %s", c.CACertFile)
+		}
+	} else {
+		// without a CA, the certificate is trusted as is, e.g. when it is self-signed
+		roots.AddCert(leaf)
+	}
+	config.RootCAs = roots
+	return []nats.Option{nats.Secure(config)}, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity/disk"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/sensors"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	pkgconfig "github.com/bacalhau-project/bacalhau/pkg/config"
@@ -24,6 +25,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi"
 	compute_endpoint "github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/local"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
 	repo_storage "github.com/bacalhau-project/bacalhau/pkg/storage/repo"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
@@ -60,6 +62,7 @@ func NewComputeNode(
 	managementProxy compute.ManagementEndpoint,
 	configuredLabels map[string]string,
 	heartbeatClient *heartbeat.HeartbeatClient,
+	nodeCredentials compute.NodeCredentialsStore,
 ) (*Compute, error) {
 	executionStore := config.ExecutionStore
 
@@ -75,6 +78,8 @@ func NewComputeNode(
 	if err != nil {
 		return nil, err
 	}
+	// values of the secrets delivered with executions, kept in memory until they are done
+	secretsCache := secrets.NewCache()
 	baseExecutor := compute.NewBaseExecutor(compute.BaseExecutorParams{
 		ID:                     nodeID,
 		Callback:               computeCallback,
@@ -85,6 +90,10 @@ func NewComputeNode(
 		Publishers:             publishers,
 		FailureInjectionConfig: config.FailureInjectionConfig,
 		ResultsPath:            *resultsPath,
+		LogStore:               config.LogStore,
+		PublishLogs:            config.PublishLogs,
+		PortsAddress:           local.ResolveAddress(ctx, config.PortsAddress),
+		Secrets:                secretsCache,
 	})
 
 	bufferRunner := compute.NewExecutorBuffer(compute.ExecutorBufferParams{
@@ -94,6 +103,7 @@ func NewComputeNode(
 		RunningCapacityTracker:     runningCapacityTracker,
 		EnqueuedCapacityTracker:    enqueuedCapacityTracker,
 		DefaultJobExecutionTimeout: config.DefaultJobExecutionTimeout,
+		EnablePreemption:           config.EnablePreemption,
 	})
 	runningInfoProvider := sensors.NewRunningExecutionsInfoProvider(sensors.RunningExecutionsInfoProviderParams{
 		Name:          "ActiveJobs",
@@ -128,8 +138,13 @@ func NewComputeNode(
 	logserver := logstream.NewServer(logstream.ServerParams{
 		ExecutionStore: executionStore,
 		Executors:      executors,
+		LogStore:       config.LogStore,
+		Secrets:        secretsCache,
 		Buffer:         config.LogStreamBufferSize,
 	})
+	if config.LogStore != nil {
+		config.LogStore.Start(ctx)
+	}
 
 	// node info
 	nodeInfoDecorator := compute.NewNodeInfoDecorator(compute.NodeInfoDecoratorParams{
@@ -153,6 +168,7 @@ func NewComputeNode(
 		bufferRunner,
 		apiServer,
 		capacityCalculator,
+		secretsCache,
 	)
 	baseEndpoint := compute.NewBaseEndpoint(compute.BaseEndpointParams{
 		ID:              nodeID,
@@ -160,7 +176,9 @@ func NewComputeNode(
 		UsageCalculator: capacityCalculator,
 		Bidder:          bidder,
 		Executor:        bufferRunner,
+		Executors:       executors,
 		LogServer:       logserver,
+		Secrets:         secretsCache,
 	})
 
 	// register debug info providers for the /debug endpoint
@@ -213,6 +231,7 @@ func NewComputeNode(
 			ResourceTracker:      runningCapacityTracker,
 			HeartbeatClient:      heartbeatClient,
 			ControlPlaneSettings: config.ControlPlaneSettings,
+			Credentials:          nodeCredentials,
 		})
 		if err := managementClient.RegisterNode(ctx); err != nil {
 			return nil, fmt.Errorf("failed to register node with requester: %s", err)
@@ -226,6 +245,10 @@ func NewComputeNode(
 			managementClient.Stop()
 		}
 
+		if config.LogStore != nil {
+			config.LogStore.Stop()
+		}
+
 		executionStore.Close(ctx)
 		resultsPath.Close()
 	}
@@ -263,6 +286,7 @@ func NewBidder(
 	bufferRunner *compute.ExecutorBuffer,
 	apiServer *publicapi.Server,
 	calculator capacity.UsageCalculator,
+	secretsCache *secrets.Cache,
 ) compute.Bidder {
 	var semanticBidStrats []bidstrategy.SemanticBidStrategy
 	if config.BidSemanticStrategy == nil {
@@ -324,5 +348,6 @@ func NewBidder(
 			return apiServer.GetURI().JoinPath("/api/v1/compute/approve")
 		},
 		UsageCalculator: calculator,
+		Secrets:         secretsCache,
 	})
 }
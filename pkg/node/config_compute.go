@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"regexp"
 	"time"
 
 	pkgerrors "github.com/pkg/errors"
@@ -13,8 +15,10 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy/semantic"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/network"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -23,6 +27,8 @@ const (
 	localPublishFolderPerm = 0755
 )
 
+var hostnameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
 // JobSelectionPolicy describe the rules for how a compute node selects an incoming job
 type JobSelectionPolicy struct {
 	// this describes if we should run a job based on
@@ -74,9 +80,24 @@ type ComputeConfigParams struct {
 
 	ExecutionStore store.ExecutionStore
 
+	// LogStore persists the logs of executions. Optional.
+	LogStore *logstream.LogStore
+	// PublishLogs publishes the persisted logs of executions along with their results
+	PublishLogs bool
+
 	LocalPublisher types.LocalPublisherConfig
+	// PortsAddress is the address at which the ports published by executions are reachable,
+	// either a host or one of the address types public, private or local
+	PortsAddress string
+
+	// DockerRuntime selects how the jobs of the docker engine are run
+	DockerRuntime types.DockerRuntimeConfig
+	// ProcessExecutor configures running the jobs of the process engine directly on the host
+	ProcessExecutor types.ProcessExecutorConfig
 
 	ControlPlaneSettings types.ComputeControlPlaneConfig
+
+	EnablePreemption bool
 }
 
 type ComputeConfig struct {
@@ -120,9 +141,27 @@ type ComputeConfig struct {
 
 	ExecutionStore store.ExecutionStore
 
+	// LogStore persists the logs of executions, so that they can be retrieved after the executions
+	// complete. Optional, and if not set the logs can only be streamed while executions are running.
+	LogStore *logstream.LogStore
+	// PublishLogs publishes the persisted logs of executions along with their results
+	PublishLogs bool
+
 	LocalPublisher types.LocalPublisherConfig
+	// PortsAddress is the address at which the ports published by executions are reachable,
+	// either a host or one of the address types public, private or local
+	PortsAddress string
+
+	// DockerRuntime selects how the jobs of the docker engine are run
+	DockerRuntime types.DockerRuntimeConfig
+	// ProcessExecutor configures running the jobs of the process engine directly on the host
+	ProcessExecutor types.ProcessExecutorConfig
 
 	ControlPlaneSettings types.ComputeControlPlaneConfig
+
+	// EnablePreemption allows higher priority executions to preempt lower priority
+	// running executions of preemptible jobs when the node is out of capacity.
+	EnablePreemption bool
 }
 
 func NewComputeConfigWithDefaults() (ComputeConfig, error) {
@@ -149,6 +188,9 @@ func NewComputeConfigWith(params ComputeConfigParams) (ComputeConfig, error) {
 	if params.LocalPublisher.Address == "" {
 		params.LocalPublisher.Address = DefaultComputeConfig.LocalPublisher.Address
 	}
+	if params.PortsAddress == "" {
+		params.PortsAddress = DefaultComputeConfig.PortsAddress
+	}
 	if params.LocalPublisher.Directory == "" {
 		params.LocalPublisher.Directory = DefaultComputeConfig.LocalPublisher.Directory
 		if err := os.MkdirAll(params.LocalPublisher.Directory, localPublishFolderPerm); err != nil {
@@ -221,8 +263,14 @@ func NewComputeConfigWith(params ComputeConfigParams) (ComputeConfig, error) {
 		BidSemanticStrategy:          params.BidSemanticStrategy,
 		BidResourceStrategy:          params.BidResourceStrategy,
 		ExecutionStore:               params.ExecutionStore,
+		LogStore:                     params.LogStore,
+		PublishLogs:                  params.PublishLogs,
 		LocalPublisher:               params.LocalPublisher,
+		PortsAddress:                 params.PortsAddress,
+		DockerRuntime:                params.DockerRuntime,
+		ProcessExecutor:              params.ProcessExecutor,
 		ControlPlaneSettings:         params.ControlPlaneSettings,
+		EnablePreemption:             params.EnablePreemption,
 	}
 
 	if err := validateConfig(config, physicalResources); err != nil {
@@ -259,5 +307,22 @@ func validateConfig(config ComputeConfig, physicalResources models.Resources) er
 				config.DefaultJobResourceLimits, config.JobResourceLimits))
 	}
 
+	if portsErr := validatePortsAddress(config.PortsAddress); portsErr != nil {
+		err = errors.Join(err, portsErr)
+	}
+
 	return err
 }
+
+// validatePortsAddress returns an error if the address at which the ports published by executions
+// are reachable is neither an address type, an IP address nor a host name, as it is given as is to
+// the clients of the ports.
+func validatePortsAddress(address string) error {
+	if _, ok := network.AddressTypeFromString(address); ok {
+		return nil
+	}
+	if net.ParseIP(address) != nil || hostnameRegex.MatchString(address) {
+		return nil
+	}
+	return fmt.Errorf("invalid ports address %q: must be a host, an IP address or one of public, private or local", address)
+}
@@ -0,0 +1,18 @@
+//go:build unit || !integration
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePortsAddress(t *testing.T) {
+	for _, address := range []string{"private", "public", "local", "127.0.0.1", "::1", "node-1", "node-1.example.com"} {
+		require.NoError(t, validatePortsAddress(address), address)
+	}
+	for _, address := range []string{"", " ", "http://node-1", "node-1:8080", "-node", "node_1"} {
+		require.ErrorContains(t, validatePortsAddress(address), "invalid ports address", address)
+	}
+}
@@ -33,6 +33,7 @@ var DefaultComputeConfig = ComputeConfigParams{
 	LocalPublisher: types.LocalPublisherConfig{
 		Directory: path.Join(config.GetStoragePath(), "bacalhau-local-publisher"),
 	},
+	PortsAddress: "private",
 	ControlPlaneSettings: types.ComputeControlPlaneConfig{
 		InfoUpdateFrequency:     types.Duration(60 * time.Second), //nolint:gomnd
 		ResourceUpdateFrequency: types.Duration(30 * time.Second), //nolint:gomnd
@@ -76,6 +77,8 @@ var DefaultRequesterConfig = RequesterConfigParams{
 	},
 
 	DefaultApprovalState: models.NodeMembership.APPROVED,
+
+	DrainGracePeriod: 5 * time.Minute,
 }
 
 var TestRequesterConfig = RequesterConfigParams{
@@ -112,6 +115,8 @@ var TestRequesterConfig = RequesterConfigParams{
 	},
 
 	DefaultApprovalState: models.NodeMembership.APPROVED,
+
+	DrainGracePeriod: 5 * time.Minute,
 }
 
 func getRequesterConfigParams() RequesterConfigParams {
@@ -43,6 +43,17 @@ type NetworkConfig struct {
 	// nodes together. This should never reference this current running instance (e.g.
 	// don't use localhost).
 	ClusterPeers []string
+
+	// TLS settings of the connections between nodes
+	TLSCertFile      string
+	TLSKeyFile       string
+	TLSCACertFile    string
+	TLSVerifyClients bool
+
+	// RequireNodeCredentials requires compute nodes to connect with the credentials
+	// issued to them by the orchestrators once they are approved
+	RequireNodeCredentials bool
+	NodeCredentialsTTL     time.Duration
 }
 
 func (c *NetworkConfig) Validate() error {
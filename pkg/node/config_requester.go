@@ -52,6 +52,16 @@ type RequesterConfigParams struct {
 
 	JobStore jobstore.Store
 
+	// SecretsKeyPath is the file holding the key that encrypts the secrets persisted in the
+	// jobstore. It is required with SQL jobstores, and is next to the database of BoltDB
+	// jobstores if not set.
+	SecretsKeyPath string
+
+	// retention policies of terminal jobs, in order of precedence, and how often the
+	// job store is pruned in the background
+	RetentionPolicies []models.RetentionPolicy
+	RetentionInterval time.Duration
+
 	DefaultPublisher string
 
 	// When new nodes join the cluster, what state do they have? By default, APPROVED, and
@@ -59,6 +69,9 @@ type RequesterConfigParams struct {
 	// or for when operators are ready to control node approval.
 	DefaultApprovalState models.NodeMembershipState
 
+	// DrainGracePeriod is the grace period of node drain requests that do not set one
+	DrainGracePeriod time.Duration
+
 	ControlPlaneSettings types.RequesterControlPlaneConfig
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/authn"
 	"github.com/bacalhau-project/bacalhau/pkg/authn/ask"
 	"github.com/bacalhau-project/bacalhau/pkg/authn/challenge"
+	"github.com/bacalhau-project/bacalhau/pkg/authn/oidc"
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	executor_util "github.com/bacalhau-project/bacalhau/pkg/executor/util"
@@ -73,7 +74,9 @@ func NewStandardExecutorsFactory() ExecutorsFactory {
 				ctx,
 				nodeConfig.CleanupManager,
 				executor_util.StandardExecutorOptions{
-					DockerID: fmt.Sprintf("bacalhau-%s", nodeConfig.NodeID),
+					DockerID:        fmt.Sprintf("bacalhau-%s", nodeConfig.NodeID),
+					DockerRuntime:   nodeConfig.ComputeConfig.DockerRuntime,
+					ProcessExecutor: nodeConfig.ComputeConfig.ProcessExecutor,
 				},
 			)
 			if err != nil {
@@ -171,6 +174,30 @@ func NewStandardAuthenticatorsFactory() AuthenticatorsFactory {
 						privKey,
 						nodeConfig.NodeID,
 					)
+				case authn.MethodTypeOIDC:
+					if authnConfig.OIDC.Issuer == "" || authnConfig.OIDC.ClientID == "" {
+						allErr = errors.Join(allErr, fmt.Errorf("authentication method %q requires an OIDC issuer and client ID", name))
+						continue
+					}
+
+					methodPolicy, err := policy.FromPathOrDefault(authnConfig.PolicyPath, oidc.GroupsPolicy)
+					if err != nil {
+						allErr = errors.Join(allErr, err)
+						continue
+					}
+
+					authns[name] = oidc.NewAuthenticator(
+						ctx,
+						methodPolicy,
+						privKey,
+						nodeConfig.NodeID,
+						oidc.Config{
+							Issuer:   authnConfig.OIDC.Issuer,
+							ClientID: authnConfig.OIDC.ClientID,
+							Scopes:   authnConfig.OIDC.Scopes,
+							JWKSURL:  authnConfig.OIDC.JWKSURL,
+						},
+					)
 				default:
 					allErr = errors.Join(allErr, fmt.Errorf("unknown authentication type: %q", authnConfig.Type))
 				}
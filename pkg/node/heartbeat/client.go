@@ -16,7 +16,7 @@ type HeartbeatClient struct {
 
 func NewClient(conn *nats.Conn, nodeID string, topic string) (*HeartbeatClient, error) {
 	subParams := natsPubSub.PubSubParams{
-		Subject: topic,
+		Subject: Subject(topic, nodeID),
 		Conn:    conn,
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	natsPubSub "github.com/bacalhau-project/bacalhau/pkg/nats/pubsub"
 )
 
 const (
@@ -153,3 +154,34 @@ func (s *HeartbeatTestSuite) TestSendHeartbeat() {
 		})
 	}
 }
+
+func (s *HeartbeatTestSuite) TestHeartbeatOnAnotherNodeSubject() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.clock = clock.NewMock()
+	server, err := NewServer(HeartbeatServerParams{
+		Clock:                 s.clock,
+		Client:                s.client,
+		Topic:                 TestTopic,
+		CheckFrequency:        1 * time.Second,
+		NodeDisconnectedAfter: 10 * time.Second,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(server.Start(ctx))
+
+	// node-a publishes a heartbeat claiming to be node-b on its own subject
+	publisher, err := natsPubSub.NewPubSub[Heartbeat](natsPubSub.PubSubParams{
+		Subject: Subject(TestTopic, "node-a"),
+		Conn:    s.client,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(publisher.Publish(ctx, Heartbeat{NodeID: "node-b", Sequence: 1}))
+	s.Require().NoError(s.client.Flush())
+
+	nodeState := models.NodeState{Info: models.NodeInfo{NodeID: "node-b"}}
+	s.Never(func() bool {
+		server.UpdateNodeInfo(&nodeState)
+		return nodeState.Connection == models.NodeStates.HEALTHY
+	}, 200*time.Millisecond, 20*time.Millisecond)
+}
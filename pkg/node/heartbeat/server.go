@@ -39,8 +39,9 @@ type TimestampedHeartbeat struct {
 
 func NewServer(params HeartbeatServerParams) (*HeartbeatServer, error) {
 	subParams := natsPubSub.PubSubParams{
-		Subject: params.Topic,
-		Conn:    params.Client,
+		Subject:             params.Topic,
+		SubscriptionSubject: subscriptionSubject(params.Topic),
+		Conn:                params.Client,
 	}
 
 	subscription, err := natsPubSub.NewPubSub[Heartbeat](subParams)
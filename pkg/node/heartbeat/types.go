@@ -1,5 +1,10 @@
 package heartbeat
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Heartbeat represents a heartbeat message from a specific node.
 // It contains the node ID and the sequence number of the heartbeat
 // which is monotonically increasing (reboots aside). We do not
@@ -9,3 +14,22 @@ type Heartbeat struct {
 	NodeID   string
 	Sequence uint64
 }
+
+// CheckSubject returns an error if the heartbeat was not published on the subject of its node,
+// so that a node cannot send heartbeats on behalf of another node.
+func (h Heartbeat) CheckSubject(subject string) error {
+	if !strings.HasSuffix(subject, "."+h.NodeID) {
+		return fmt.Errorf("heartbeat of node %s published on subject %s", h.NodeID, subject)
+	}
+	return nil
+}
+
+// Subject returns the subject the heartbeats of a node are published on
+func Subject(topic string, nodeID string) string {
+	return topic + "." + nodeID
+}
+
+// subscriptionSubject returns the subject the heartbeats of all nodes are received on
+func subscriptionSubject(topic string) string {
+	return topic + ".*"
+}
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
@@ -13,6 +14,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/models/requests"
 	"github.com/bacalhau-project/bacalhau/pkg/node/heartbeat"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
 	"github.com/bacalhau-project/bacalhau/pkg/routing"
 )
 
@@ -29,12 +31,27 @@ type NodeManager struct {
 	resourceMap          *concurrency.StripedMap[models.Resources]
 	heartbeats           *heartbeat.HeartbeatServer
 	defaultApprovalState models.NodeMembershipState
+	drainGracePeriod     time.Duration
+	credentialsIssuer    CredentialsIssuer
+	auditRecorder        *audit.Recorder
+}
+
+// CredentialsIssuer issues the credentials that approved compute nodes use to connect
+// to the orchestrators.
+type CredentialsIssuer interface {
+	IssueCredentials(ctx context.Context, nodeID string, publicKey string) (string, error)
 }
 
 type NodeManagerParams struct {
 	NodeInfo             routing.NodeInfoStore
 	Heartbeats           *heartbeat.HeartbeatServer
 	DefaultApprovalState models.NodeMembershipState
+	// DrainGracePeriod is the grace period of drain actions that do not set one
+	DrainGracePeriod time.Duration
+	// CredentialsIssuer is optional, and no credentials are issued if it is not set
+	CredentialsIssuer CredentialsIssuer
+	// AuditRecorder is optional, and node actions are not audited if it is not set
+	AuditRecorder *audit.Recorder
 }
 
 // NewNodeManager constructs a new node manager and returns a pointer
@@ -45,6 +62,9 @@ func NewNodeManager(params NodeManagerParams) *NodeManager {
 		store:                params.NodeInfo,
 		heartbeats:           params.Heartbeats,
 		defaultApprovalState: params.DefaultApprovalState,
+		drainGracePeriod:     params.DrainGracePeriod,
+		credentialsIssuer:    params.CredentialsIssuer,
+		auditRecorder:        params.AuditRecorder,
 	}
 }
 
@@ -79,25 +99,62 @@ func (n *NodeManager) Register(ctx context.Context, request requests.RegisterReq
 			}, nil
 		}
 
+		// Nodes are identified by their key once they registered one, so that another node
+		// cannot take over their ID.
+		if existing.PublicKey != "" && request.PublicKey != existing.PublicKey {
+			return &requests.RegisterResponse{
+				Accepted: false,
+				Reason:   "node is registered with a different key",
+			}, nil
+		}
+		// Nodes registered without a key are not trusted with the first key registered for their
+		// ID, as any node knowing the cluster secret could claim it. The key is bound to the node,
+		// but approved nodes are put back to pending until an operator approves them again.
+		if existing.PublicKey == "" && request.PublicKey != "" {
+			existing.PublicKey = request.PublicKey
+			if existing.Membership == models.NodeMembership.APPROVED {
+				log.Ctx(ctx).Warn().Str("NodeID", existing.Info.NodeID).
+					Msg("approved node registered a key for the first time, it must be approved again")
+				existing.Membership = models.NodeMembership.PENDING
+			}
+			if err = n.store.Add(ctx, existing); err != nil {
+				return nil, errors.Wrap(err, "failed to save nodestate during node registration")
+			}
+		}
+
+		credentials, err := n.issueCredentials(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+
 		// Otherwise we'll allow the registration, but let the compute node
 		// that it has already been registered on a previous occasion.
 		return &requests.RegisterResponse{
-			Accepted: true,
-			Reason:   "node already registered",
+			Accepted:    true,
+			Reason:      "node already registered",
+			Credentials: credentials,
 		}, nil
 	}
 
-	if err := n.store.Add(ctx, models.NodeState{
+	state := models.NodeState{
 		Info:       request.Info,
 		Membership: n.defaultApprovalState,
 		// NB(forrest): by virtue of a compute node calling this endpoint we can consider it connected
 		Connection: models.NodeStates.CONNECTED,
-	}); err != nil {
+		PublicKey:  request.PublicKey,
+	}
+	if err := n.store.Add(ctx, state); err != nil {
 		return nil, errors.Wrap(err, "failed to save nodestate during node registration")
 	}
 
+	credentials, err := n.issueCredentials(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
 	return &requests.RegisterResponse{
-		Accepted: true,
+		Accepted:    true,
+		Credentials: credentials,
 	}, nil
 }
 
@@ -131,15 +188,33 @@ func (n *NodeManager) UpdateInfo(ctx context.Context, request requests.UpdateInf
 		Membership: existing.Membership,
 		// TODO can we assume the node is connected here?
 		Connection: models.NodeStates.CONNECTED,
+		Cordon:     existing.Cordon,
+		PublicKey:  existing.PublicKey,
 	}); err != nil {
 		return nil, errors.Wrap(err, "failed to save nodestate during node registration")
 	}
 
+	// renew the credentials of the node, so that they do not expire while it is approved
+	credentials, err := n.issueCredentials(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+
 	return &requests.UpdateInfoResponse{
-		Accepted: true,
+		Accepted:    true,
+		Credentials: credentials,
 	}, nil
 }
 
+// issueCredentials returns credentials for approved nodes that registered a public key,
+// or an empty string if the node is not issued any credentials.
+func (n *NodeManager) issueCredentials(ctx context.Context, state models.NodeState) (string, error) {
+	if n.credentialsIssuer == nil || state.PublicKey == "" || state.Membership != models.NodeMembership.APPROVED {
+		return "", nil
+	}
+	return n.credentialsIssuer.IssueCredentials(ctx, state.Info.NodeID, state.PublicKey)
+}
+
 // UpdateResources updates the available resources in our in-memory store for each node. This data
 // is used to augment information about the available resources for each node.
 func (n *NodeManager) UpdateResources(ctx context.Context,
@@ -168,6 +243,18 @@ func (n *NodeManager) FindPeer(ctx context.Context, peerID peer.ID) (peer.AddrIn
 }
 
 func (n *NodeManager) Add(ctx context.Context, nodeInfo models.NodeState) error {
+	// nodes are only cordoned by the node manager, and only register their key with it,
+	// so keep both when the node publishes its state
+	if nodeInfo.Cordon == nil || nodeInfo.PublicKey == "" {
+		if existing, err := n.store.Get(ctx, nodeInfo.Info.NodeID); err == nil {
+			if nodeInfo.Cordon == nil {
+				nodeInfo.Cordon = existing.Cordon
+			}
+			if nodeInfo.PublicKey == "" {
+				nodeInfo.PublicKey = existing.PublicKey
+			}
+		}
+	}
 	return n.store.Add(ctx, nodeInfo)
 }
 
@@ -223,6 +310,12 @@ func (n *NodeManager) Delete(ctx context.Context, nodeID string) error {
 // reason for the approval (for audit). The return values denote success and any
 // failure of the operation as a human readable string.
 func (n *NodeManager) ApproveAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	success, message := n.approveAction(ctx, nodeID, reason)
+	n.audit(ctx, models.AuditActionNodeApprove, nodeID, reason, success, message)
+	return success, message
+}
+
+func (n *NodeManager) approveAction(ctx context.Context, nodeID string, reason string) (bool, string) {
 	state, err := n.store.GetByPrefix(ctx, nodeID)
 	if err != nil {
 		return false, err.Error()
@@ -246,6 +339,12 @@ func (n *NodeManager) ApproveAction(ctx context.Context, nodeID string, reason s
 // reason for the rejection (for audit). The return values denote success and any
 // failure of the operation as a human readable string.
 func (n *NodeManager) RejectAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	success, message := n.rejectAction(ctx, nodeID, reason)
+	n.audit(ctx, models.AuditActionNodeReject, nodeID, reason, success, message)
+	return success, message
+}
+
+func (n *NodeManager) rejectAction(ctx context.Context, nodeID string, reason string) (bool, string) {
 	state, err := n.store.GetByPrefix(ctx, nodeID)
 	if err != nil {
 		return false, err.Error()
@@ -269,6 +368,12 @@ func (n *NodeManager) RejectAction(ctx context.Context, nodeID string, reason st
 // reason for the rejection (for audit). The return values denote success and any
 // failure of the operation as a human readable string.
 func (n *NodeManager) DeleteAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	success, message := n.deleteAction(ctx, nodeID, reason)
+	n.audit(ctx, models.AuditActionNodeDelete, nodeID, reason, success, message)
+	return success, message
+}
+
+func (n *NodeManager) deleteAction(ctx context.Context, nodeID string, reason string) (bool, string) {
 	state, err := n.store.GetByPrefix(ctx, nodeID)
 	if err != nil {
 		return false, err.Error()
@@ -281,5 +386,130 @@ func (n *NodeManager) DeleteAction(ctx context.Context, nodeID string, reason st
 	return true, ""
 }
 
+// audit records a node action in the audit log along with the reason given for
+// it, or the failure of the action, if node actions are audited
+func (n *NodeManager) audit(ctx context.Context, action, nodeID, reason string, success bool, message string) {
+	if n.auditRecorder == nil {
+		return
+	}
+	event := models.AuditEvent{
+		Action:  action,
+		Target:  nodeID,
+		Outcome: models.AuditOutcomeSuccess,
+		Reason:  reason,
+	}
+	if !success {
+		event.Outcome = models.AuditOutcomeFailure
+		event.Reason = message
+	}
+	n.auditRecorder.Record(ctx, event)
+}
+
+// CordonAction is used to take a node out of rotation, so that no new executions are scheduled
+// on it, along with a specific reason (for audit). The return values denote success and any
+// failure of the operation as a human readable string.
+func (n *NodeManager) CordonAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	success, message := n.cordonAction(ctx, nodeID, reason)
+	n.audit(ctx, models.AuditActionNodeCordon, nodeID, reason, success, message)
+	return success, message
+}
+
+func (n *NodeManager) cordonAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	state, err := n.store.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if state.IsCordoned() {
+		return false, "node already cordoned"
+	}
+
+	state.Cordon = &models.NodeCordon{
+		Message:    reason,
+		CreateTime: time.Now().UTC().UnixNano(),
+	}
+	log.Ctx(ctx).Info().Str("reason", reason).Msgf("node %s cordoned", nodeID)
+
+	if err := n.store.Add(ctx, state); err != nil {
+		return false, "failed to save nodestate during node cordon"
+	}
+
+	return true, ""
+}
+
+// DrainAction is used to cordon a node and move the executions of its long-running jobs
+// to other nodes, along with a specific reason (for audit). The executions are stopped
+// once the grace period is over, while batch executions are allowed to finish. A node that
+// is already cordoned can be drained. The return values denote success and any failure of
+// the operation as a human readable string.
+func (n *NodeManager) DrainAction(
+	ctx context.Context, nodeID string, reason string, gracePeriod *time.Duration) (bool, string) {
+	success, message := n.drainAction(ctx, nodeID, reason, gracePeriod)
+	n.audit(ctx, models.AuditActionNodeDrain, nodeID, reason, success, message)
+	return success, message
+}
+
+func (n *NodeManager) drainAction(
+	ctx context.Context, nodeID string, reason string, gracePeriod *time.Duration) (bool, string) {
+	state, err := n.store.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if state.Cordon != nil && state.Cordon.Drain {
+		return false, "node already draining"
+	}
+	if gracePeriod == nil {
+		gracePeriod = &n.drainGracePeriod
+	}
+	if *gracePeriod < 0 {
+		return false, "drain grace period must not be negative"
+	}
+
+	now := time.Now().UTC()
+	state.Cordon = &models.NodeCordon{
+		Message:       reason,
+		CreateTime:    now.UnixNano(),
+		Drain:         true,
+		DrainDeadline: now.Add(*gracePeriod).UnixNano(),
+	}
+	log.Ctx(ctx).Info().Str("reason", reason).Dur("gracePeriod", *gracePeriod).Msgf("node %s draining", nodeID)
+
+	if err := n.store.Add(ctx, state); err != nil {
+		return false, "failed to save nodestate during node drain"
+	}
+
+	return true, ""
+}
+
+// UncordonAction is used to put a cordoned or draining node back in rotation, along with
+// a specific reason (for audit). The return values denote success and any failure of the
+// operation as a human readable string.
+func (n *NodeManager) UncordonAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	success, message := n.uncordonAction(ctx, nodeID, reason)
+	n.audit(ctx, models.AuditActionNodeUncordon, nodeID, reason, success, message)
+	return success, message
+}
+
+func (n *NodeManager) uncordonAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	state, err := n.store.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if !state.IsCordoned() {
+		return false, "node not cordoned"
+	}
+
+	state.Cordon = nil
+	log.Ctx(ctx).Info().Str("reason", reason).Msgf("node %s uncordoned", nodeID)
+
+	if err := n.store.Add(ctx, state); err != nil {
+		return false, "failed to save nodestate during node uncordon"
+	}
+
+	return true, ""
+}
+
 var _ compute.ManagementEndpoint = (*NodeManager)(nil)
 var _ routing.NodeInfoStore = (*NodeManager)(nil)
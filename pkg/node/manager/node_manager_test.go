@@ -0,0 +1,92 @@
+//go:build unit || !integration
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models/requests"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/inmemory"
+)
+
+// fakeCredentialsIssuer issues credentials made of the node ID and its key
+type fakeCredentialsIssuer struct{}
+
+func (fakeCredentialsIssuer) IssueCredentials(_ context.Context, nodeID string, publicKey string) (string, error) {
+	return nodeID + ":" + publicKey, nil
+}
+
+type NodeManagerRegisterSuite struct {
+	suite.Suite
+	store   *inmemory.NodeStore
+	manager *NodeManager
+}
+
+func TestNodeManagerRegisterSuite(t *testing.T) {
+	suite.Run(t, new(NodeManagerRegisterSuite))
+}
+
+func (s *NodeManagerRegisterSuite) SetupTest() {
+	s.store = inmemory.NewNodeStore(inmemory.NodeStoreParams{TTL: time.Hour})
+	s.manager = NewNodeManager(NodeManagerParams{
+		NodeInfo:             s.store,
+		DefaultApprovalState: models.NodeMembership.APPROVED,
+		CredentialsIssuer:    fakeCredentialsIssuer{},
+	})
+}
+
+func (s *NodeManagerRegisterSuite) register(publicKey string) *requests.RegisterResponse {
+	response, err := s.manager.Register(context.Background(), requests.RegisterRequest{
+		Info:      models.NodeInfo{NodeID: "node-1", NodeType: models.NodeTypeCompute},
+		PublicKey: publicKey,
+	})
+	s.Require().NoError(err)
+	return response
+}
+
+func (s *NodeManagerRegisterSuite) membership() models.NodeMembershipState {
+	state, err := s.store.Get(context.Background(), "node-1")
+	s.Require().NoError(err)
+	return state.Membership
+}
+
+func (s *NodeManagerRegisterSuite) TestRegisterWithKey() {
+	response := s.register("key-1")
+	s.True(response.Accepted)
+	s.Equal("node-1:key-1", response.Credentials)
+
+	// the node ID is bound to its key
+	response = s.register("key-1")
+	s.True(response.Accepted)
+	s.Equal("node-1:key-1", response.Credentials)
+
+	response = s.register("key-2")
+	s.False(response.Accepted)
+	s.Empty(response.Credentials)
+}
+
+func (s *NodeManagerRegisterSuite) TestFirstKeyOfApprovedNodeRequiresApproval() {
+	response := s.register("")
+	s.True(response.Accepted)
+	s.Equal(models.NodeMembership.APPROVED, s.membership())
+
+	// the key is bound to the node, but no credentials are issued until it is approved again
+	response = s.register("key-1")
+	s.True(response.Accepted)
+	s.Empty(response.Credentials)
+	s.Equal(models.NodeMembership.PENDING, s.membership())
+
+	response = s.register("key-2")
+	s.False(response.Accepted)
+
+	approved, reason := s.manager.ApproveAction(context.Background(), "node-1", "checked")
+	s.Require().True(approved, reason)
+	response = s.register("key-1")
+	s.True(response.Accepted)
+	s.Equal("node-1:key-1", response.Credentials)
+}
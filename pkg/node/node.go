@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	pkgconfig "github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
@@ -25,6 +26,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/node/heartbeat"
 	"github.com/bacalhau-project/bacalhau/pkg/node/manager"
 	"github.com/bacalhau-project/bacalhau/pkg/node/metrics"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/apikey"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/agent"
@@ -164,6 +167,39 @@ func NewNode(
 		return nil, err
 	}
 
+	authorizer := authz.NewPolicyAuthorizer(authzPolicy, signingKey, config.NodeID)
+
+	// API keys of service accounts, which are exchanged for access tokens before
+	// requests are authorized
+	var apiKeyManager *apikey.Manager
+	if config.IsRequesterNode {
+		apiKeyStore, err := newAPIKeyStore(ctx, config.RequesterNodeConfig)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := pkgconfig.GetClientPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		apiKeyManager = apikey.NewManager(apikey.ManagerParams{Store: apiKeyStore})
+		authorizer = apikey.NewAuthorizer(apikey.AuthorizerParams{
+			Authorizer: authorizer,
+			Manager:    apiKeyManager,
+			SigningKey: privateKey,
+			NodeID:     config.NodeID,
+		})
+	}
+
+	// audit log of the mutating API requests and node actions on the requester
+	var auditRecorder *audit.Recorder
+	if config.IsRequesterNode {
+		auditStore, err := newAuditStore(ctx, config.RequesterNodeConfig)
+		if err != nil {
+			return nil, err
+		}
+		auditRecorder = audit.NewRecorder(audit.RecorderParams{Store: auditStore})
+	}
+
 	serverVersion := version.Get()
 	// public http api server
 	serverParams := publicapi.ServerParams{
@@ -172,7 +208,7 @@ func NewNode(
 		Port:       config.APIPort,
 		HostID:     config.NodeID,
 		Config:     config.APIServerConfig,
-		Authorizer: authz.NewPolicyAuthorizer(authzPolicy, signingKey, config.NodeID),
+		Authorizer: authorizer,
 		Headers: map[string]string{
 			apimodels.HTTPHeaderBacalhauGitVersion: serverVersion.GitVersion,
 			apimodels.HTTPHeaderBacalhauGitCommit:  serverVersion.GitCommit,
@@ -180,6 +216,7 @@ func NewNode(
 			apimodels.HTTPHeaderBacalhauBuildOS:    serverVersion.GOOS,
 			apimodels.HTTPHeaderBacalhauArch:       serverVersion.GOARCH,
 		},
+		AuditRecorder: auditRecorder,
 	}
 
 	// Only allow autocert for requester nodes
@@ -196,13 +233,13 @@ func NewNode(
 	}
 	// node info store that is used for both discovering compute nodes, as to find addresses of other nodes for routing requests.
 
-	var natsConfig *nats_transport.NATSTransportConfig
+	var natsTransportLayer *nats_transport.NATSTransport
 	var transportLayer transport.TransportLayer
 	var tracingInfoStore routing.NodeInfoStore
 	var heartbeatSvr *heartbeat.HeartbeatServer
 
 	if config.NetworkConfig.Type == models.NetworkTypeNATS {
-		natsConfig = &nats_transport.NATSTransportConfig{
+		natsConfig := &nats_transport.NATSTransportConfig{
 			NodeID:                   config.NodeID,
 			Port:                     config.NetworkConfig.Port,
 			AdvertisedAddress:        config.NetworkConfig.AdvertisedAddress,
@@ -214,9 +251,18 @@ func NewNode(
 			ClusterPeers:             config.NetworkConfig.ClusterPeers,
 			ClusterAdvertisedAddress: config.NetworkConfig.ClusterAdvertisedAddress,
 			IsRequesterNode:          config.IsRequesterNode,
+			TLS: nats_transport.TLSConfig{
+				CertFile:      config.NetworkConfig.TLSCertFile,
+				KeyFile:       config.NetworkConfig.TLSKeyFile,
+				CACertFile:    config.NetworkConfig.TLSCACertFile,
+				VerifyClients: config.NetworkConfig.TLSVerifyClients,
+			},
+			RequireNodeCredentials: config.NetworkConfig.RequireNodeCredentials,
+			CredentialsTTL:         config.NetworkConfig.NodeCredentialsTTL,
+			HeartbeatTopic:         config.RequesterNodeConfig.ControlPlaneSettings.HeartbeatTopic,
 		}
 
-		natsTransportLayer, err := nats_transport.NewNATSTransport(ctx, natsConfig)
+		natsTransportLayer, err = nats_transport.NewNATSTransport(ctx, natsConfig)
 		if err != nil {
 			return nil, pkgerrors.Wrap(err, "failed to create NATS transport layer")
 		}
@@ -225,7 +271,7 @@ func NewNode(
 		if config.IsRequesterNode {
 			// KV Node Store requires connection info from the NATS server so that it is able
 			// to create its own connection and then subscribe to the node info topic.
-			natsClient, err := nats_transport.CreateClient(ctx, natsTransportLayer.Config)
+			natsClient, err := natsTransportLayer.CreateClient(ctx)
 			if err != nil {
 				return nil, pkgerrors.Wrap(err, "failed to create NATS client for node info store")
 			}
@@ -297,10 +343,17 @@ func NewNode(
 		// Create a new node manager to keep track of compute nodes connecting
 		// to the network. Provide it with a mechanism to lookup (and enhance)
 		// node info, and a reference to the heartbeat server if running NATS.
+		var credentialsIssuer manager.CredentialsIssuer
+		if natsTransportLayer != nil {
+			credentialsIssuer = natsTransportLayer.Issuer()
+		}
 		nodeManager := manager.NewNodeManager(manager.NodeManagerParams{
 			NodeInfo:             tracingInfoStore,
 			Heartbeats:           heartbeatSvr,
 			DefaultApprovalState: config.RequesterNodeConfig.DefaultApprovalState,
+			DrainGracePeriod:     config.RequesterNodeConfig.DrainGracePeriod,
+			CredentialsIssuer:    credentialsIssuer,
+			AuditRecorder:        auditRecorder,
 		})
 
 		// Start the nodemanager, ensuring it doesn't block the main thread and
@@ -330,6 +383,8 @@ func NewNode(
 			legacyInfoStore,
 			transportLayer.ComputeProxy(),
 			nodeManager,
+			apiKeyManager,
+			auditRecorder,
 		)
 		if err != nil {
 			return nil, err
@@ -375,13 +430,14 @@ func NewNode(
 		)
 
 		var hbClient *heartbeat.HeartbeatClient
+		var nodeCredentials compute.NodeCredentialsStore
 
 		// We want to provide a heartbeat client to the compute node if we are using NATS.
 		// We can only create a heartbeat client if we have a NATS client, and we can
 		// only do that if the configuration is available. Whilst we support libp2p this
 		// is not always the case.
-		if natsConfig != nil {
-			natsClient, err := nats_transport.CreateClient(ctx, natsConfig)
+		if natsTransportLayer != nil {
+			natsClient, err := natsTransportLayer.CreateClient(ctx)
 			if err != nil {
 				return nil, pkgerrors.Wrap(err, "failed to create NATS client for node info store")
 			}
@@ -394,6 +450,10 @@ func NewNode(
 			if err != nil {
 				return nil, pkgerrors.Wrap(err, "failed to create heartbeat client")
 			}
+
+			if credentials := natsTransportLayer.NodeCredentials(); credentials != nil {
+				nodeCredentials = credentials
+			}
 		}
 
 		// setup compute node
@@ -411,6 +471,7 @@ func NewNode(
 			transportLayer.ManagementProxy(),
 			config.Labels,
 			hbClient,
+			nodeCredentials,
 		)
 		if err != nil {
 			return nil, err
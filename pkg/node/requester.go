@@ -2,6 +2,9 @@ package node
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 
 	"github.com/rs/zerolog/log"
 
@@ -11,10 +14,15 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/node/manager"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/apikey"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/evaluation"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/planner"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retention"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retry"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/scheduler"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/secret"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/selection/selector"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/transformer"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi"
@@ -29,6 +37,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/eventhandler"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/selection/discovery"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/selection/ranking"
@@ -37,6 +47,9 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
 
+// SecretsKeyFileName is the file holding the key that encrypts the secrets persisted by the requester
+const SecretsKeyFileName = "secrets.key"
+
 type Requester struct {
 	// Visible for testing
 	Endpoint   requester.Endpoint
@@ -62,6 +75,8 @@ func NewRequesterNode(
 	nodeInfoStore routing.NodeInfoStore, // for libp2p store only, once removed remove this in favour of nodeManager
 	computeProxy compute.Endpoint,
 	nodeManager *manager.NodeManager,
+	apiKeyManager *apikey.Manager,
+	auditRecorder *audit.Recorder,
 ) (*Requester, error) {
 	// prepare event handlers
 	tracerContextProvider := eventhandler.NewTracerContextProvider(nodeID)
@@ -106,29 +121,51 @@ func NewRequesterNode(
 	})
 
 	// evaluation broker
-	evalBroker, err := evaluation.NewInMemoryBroker(evaluation.InMemoryBrokerParams{
-		VisibilityTimeout:    requesterConfig.EvalBrokerVisibilityTimeout,
-		InitialRetryDelay:    requesterConfig.EvalBrokerInitialRetryDelay,
-		SubsequentRetryDelay: requesterConfig.EvalBrokerSubsequentRetryDelay,
-		MaxReceiveCount:      requesterConfig.EvalBrokerMaxRetryCount,
-	})
+	evalBroker, err := newEvaluationBroker(ctx, requesterConfig)
 	if err != nil {
 		return nil, err
 	}
 	evalBroker.SetEnabled(true)
 
+	// quotas that hold back jobs while their namespace is over quota
+	quotaStore, err := newQuotaStore(ctx, requesterConfig)
+	if err != nil {
+		return nil, err
+	}
+	quotaEnforcer := quota.NewEnforcer(quota.EnforcerParams{
+		Store:    quotaStore,
+		JobStore: jobStore,
+	})
+
+	// secrets that tasks reference, and that are only sent to the nodes running their executions
+	secretStore, err := newSecretStore(ctx, requesterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// workflow that holds back jobs until the jobs they depend on have completed
+	workflow := orchestrator.NewWorkflow(orchestrator.WorkflowParams{
+		Store:            jobStore,
+		EvaluationBroker: evalBroker,
+	})
+
 	// planners that execute the proposed plan by the scheduler
 	// order of the planners is important as they are executed in order
 	planners := planner.NewChain(
 		// planner that persist the desired state as defined by the scheduler
 		planner.NewStateUpdater(jobStore),
 
+		// planner that wires the results of upstream jobs into new executions,
+		// and releases downstream jobs when their dependencies are done
+		workflow,
+
 		// planner that forwards the desired state to the compute nodes,
 		// and updates the observed state if the compute node accepts the desired state
 		planner.NewComputeForwarder(planner.ComputeForwarderParams{
 			ID:             nodeID,
 			ComputeService: computeProxy,
 			JobStore:       jobStore,
+			SecretStore:    secretStore,
 		}),
 
 		// planner that publishes events on job completion or failure
@@ -147,29 +184,45 @@ func NewRequesterNode(
 		retryStrategyChain := retry.NewChain()
 		retryStrategyChain.Add(
 			retry.NewFixedStrategy(retry.FixedStrategyParams{ShouldRetry: true}),
+			// honors the retry policy of jobs, if any
+			retry.NewExponentialStrategy(retry.ExponentialStrategyParams{}),
 		)
 		retryStrategy = retryStrategyChain
 	}
 
 	// scheduler provider
 	batchServiceJobScheduler := scheduler.NewBatchServiceJobScheduler(scheduler.BatchServiceJobSchedulerParams{
-		JobStore:      jobStore,
-		Planner:       planners,
-		NodeSelector:  nodeSelector,
-		RetryStrategy: retryStrategy,
+		JobStore:         jobStore,
+		Planner:          planners,
+		NodeSelector:     nodeSelector,
+		RetryStrategy:    retryStrategy,
+		EvaluationBroker: evalBroker,
+		QuotaEnforcer:    quotaEnforcer,
+		Workflow:         workflow,
 	})
 	schedulerProvider := orchestrator.NewMappedSchedulerProvider(map[string]orchestrator.Scheduler{
 		models.JobTypeBatch:   batchServiceJobScheduler,
 		models.JobTypeService: batchServiceJobScheduler,
 		models.JobTypeOps: scheduler.NewOpsJobScheduler(scheduler.OpsJobSchedulerParams{
-			JobStore:     jobStore,
-			Planner:      planners,
-			NodeSelector: nodeSelector,
+			JobStore:         jobStore,
+			Planner:          planners,
+			NodeSelector:     nodeSelector,
+			EvaluationBroker: evalBroker,
+			QuotaEnforcer:    quotaEnforcer,
+			Workflow:         workflow,
 		}),
 		models.JobTypeDaemon: scheduler.NewDaemonJobScheduler(scheduler.DaemonJobSchedulerParams{
-			JobStore:     jobStore,
-			Planner:      planners,
-			NodeSelector: nodeSelector,
+			JobStore:         jobStore,
+			Planner:          planners,
+			NodeSelector:     nodeSelector,
+			EvaluationBroker: evalBroker,
+			QuotaEnforcer:    quotaEnforcer,
+			Workflow:         workflow,
+		}),
+		models.JobTypeScheduled: scheduler.NewScheduledJobScheduler(scheduler.ScheduledJobSchedulerParams{
+			JobStore:         jobStore,
+			Planner:          planners,
+			EvaluationBroker: evalBroker,
 		}),
 	})
 
@@ -247,6 +300,9 @@ func NewRequesterNode(
 		JobTransformer:    jobTransformers,
 		TaskTranslator:    translationProvider,
 		ResultTransformer: resultTransformers,
+		Workflow:          workflow,
+		QuotaEnforcer:     quotaEnforcer,
+		SecretStore:       secretStore,
 	})
 
 	housekeeping := requester.NewHousekeeping(requester.HousekeepingParams{
@@ -256,6 +312,14 @@ func NewRequesterNode(
 		Interval: requesterConfig.HousekeepingBackgroundTaskInterval,
 	})
 
+	// prunes terminal jobs that are no longer retained from the job store
+	reaper := retention.NewReaper(retention.ReaperParams{
+		JobStore: jobStore,
+		Policies: requesterConfig.RetentionPolicies,
+		Interval: requesterConfig.RetentionInterval,
+	})
+	reaper.Start(ctx)
+
 	// register debug info providers for the /debug endpoint
 	debugInfoProviders := []model.DebugInfoProvider{
 		discovery.NewDebugInfoProvider(nodeInfoStore),
@@ -271,10 +335,15 @@ func NewRequesterNode(
 	})
 
 	orchestrator_endpoint.NewEndpoint(orchestrator_endpoint.EndpointParams{
-		Router:       apiServer.Router,
-		Orchestrator: endpointV2,
-		JobStore:     jobStore,
-		NodeManager:  nodeManager,
+		Router:        apiServer.Router,
+		Orchestrator:  endpointV2,
+		JobStore:      jobStore,
+		NodeManager:   nodeManager,
+		QuotaEnforcer: quotaEnforcer,
+		SecretStore:   secretStore,
+		APIKeyManager: apiKeyManager,
+		AuditRecorder: auditRecorder,
+		Reaper:        reaper,
 	})
 
 	auth_endpoint.BindEndpoint(ctx, apiServer.Router, authnProvider)
@@ -302,6 +371,8 @@ func NewRequesterNode(
 	cleanupFunc := func(ctx context.Context) {
 		// stop the housekeeping background task
 		housekeeping.Stop()
+		// stop pruning the job store
+		reaper.Stop()
 		for _, worker := range workers {
 			worker.Stop()
 		}
@@ -336,6 +407,119 @@ func NewRequesterNode(
 	}, nil
 }
 
+// evaluationBroker is an orchestrator.EvaluationBroker that can be enabled and disabled
+type evaluationBroker interface {
+	orchestrator.EvaluationBroker
+	SetEnabled(enabled bool)
+}
+
+// The requester persists its own state, which is the queue of the evaluation broker, the
+// namespace quotas, the secrets, the API keys and the audit log, in the database of its
+// jobstore so that the state survives restarts along with the jobs. Each of these stores is
+// implemented for every database that backs a jobstore, and other jobstores are rejected
+// rather than keeping the state in memory, where it would be silently lost on restart.
+
+func unsupportedJobStoreError(jobStore jobstore.Store) error {
+	return fmt.Errorf("jobstore %T cannot persist the state of the requester", jobStore)
+}
+
+// newEvaluationBroker creates the evaluation broker of the requester.
+func newEvaluationBroker(ctx context.Context, requesterConfig RequesterConfig) (evaluationBroker, error) {
+	switch jobStore := requesterConfig.JobStore.(type) {
+	case *boltjobstore.BoltJobStore:
+		return evaluation.NewBoltBroker(evaluation.BoltBrokerParams{
+			Database:             jobStore.Database(),
+			VisibilityTimeout:    requesterConfig.EvalBrokerVisibilityTimeout,
+			InitialRetryDelay:    requesterConfig.EvalBrokerInitialRetryDelay,
+			SubsequentRetryDelay: requesterConfig.EvalBrokerSubsequentRetryDelay,
+			MaxReceiveCount:      requesterConfig.EvalBrokerMaxRetryCount,
+		})
+	case *sqljobstore.SQLJobStore:
+		return evaluation.NewSQLBroker(ctx, evaluation.SQLBrokerParams{
+			Database:             jobStore.Database(),
+			VisibilityTimeout:    requesterConfig.EvalBrokerVisibilityTimeout,
+			InitialRetryDelay:    requesterConfig.EvalBrokerInitialRetryDelay,
+			SubsequentRetryDelay: requesterConfig.EvalBrokerSubsequentRetryDelay,
+			MaxReceiveCount:      requesterConfig.EvalBrokerMaxRetryCount,
+		})
+	default:
+		return nil, unsupportedJobStoreError(requesterConfig.JobStore)
+	}
+}
+
+// newQuotaStore creates the store of the namespace quotas.
+func newQuotaStore(ctx context.Context, requesterConfig RequesterConfig) (quota.Store, error) {
+	switch jobStore := requesterConfig.JobStore.(type) {
+	case *boltjobstore.BoltJobStore:
+		return quota.NewBoltStore(jobStore.Database())
+	case *sqljobstore.SQLJobStore:
+		return quota.NewSQLStore(ctx, jobStore.Database())
+	default:
+		return nil, unsupportedJobStoreError(requesterConfig.JobStore)
+	}
+}
+
+// newSecretStore creates the store of the secrets, whose values are encrypted with the key
+// in SecretsKeyPath. The key of BoltDB jobstores defaults to a file next to their database.
+func newSecretStore(ctx context.Context, requesterConfig RequesterConfig) (secret.Store, error) {
+	keyPath := requesterConfig.SecretsKeyPath
+	switch jobStore := requesterConfig.JobStore.(type) {
+	case *boltjobstore.BoltJobStore:
+		database := jobStore.Database()
+		if keyPath == "" {
+			keyPath = filepath.Join(filepath.Dir(database.Path()), SecretsKeyFileName)
+		}
+		cipher, err := loadSecretsCipher(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return secret.NewBoltStore(database, cipher)
+	case *sqljobstore.SQLJobStore:
+		if keyPath == "" {
+			return nil, errors.New("the path of the secrets key is required with a SQL jobstore")
+		}
+		cipher, err := loadSecretsCipher(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return secret.NewSQLStore(ctx, jobStore.Database(), cipher)
+	default:
+		return nil, unsupportedJobStoreError(requesterConfig.JobStore)
+	}
+}
+
+func loadSecretsCipher(keyPath string) (*secret.Cipher, error) {
+	key, err := secret.LoadOrCreateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return secret.NewCipher(key)
+}
+
+// newAPIKeyStore creates the store of the API keys.
+func newAPIKeyStore(ctx context.Context, requesterConfig RequesterConfig) (apikey.Store, error) {
+	switch jobStore := requesterConfig.JobStore.(type) {
+	case *boltjobstore.BoltJobStore:
+		return apikey.NewBoltStore(jobStore.Database())
+	case *sqljobstore.SQLJobStore:
+		return apikey.NewSQLStore(ctx, jobStore.Database())
+	default:
+		return nil, unsupportedJobStoreError(requesterConfig.JobStore)
+	}
+}
+
+// newAuditStore creates the store of the audit log.
+func newAuditStore(ctx context.Context, requesterConfig RequesterConfig) (audit.Store, error) {
+	switch jobStore := requesterConfig.JobStore.(type) {
+	case *boltjobstore.BoltJobStore:
+		return audit.NewBoltStore(jobStore.Database())
+	case *sqljobstore.SQLJobStore:
+		return audit.NewSQLStore(ctx, jobStore.Database())
+	default:
+		return nil, unsupportedJobStoreError(requesterConfig.JobStore)
+	}
+}
+
 func (r *Requester) cleanup(ctx context.Context) {
 	r.cleanupFunc(ctx)
 }
@@ -0,0 +1,129 @@
+package apikey
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	bearerPrefix = "Bearer "
+
+	// accessTokenTTL is how long the access tokens that API keys are exchanged
+	// for are valid. They only live for the duration of a request.
+	accessTokenTTL = time.Minute
+
+	// SubjectPrefix prefixes the name of an API key in the subject of the access
+	// tokens it is exchanged for, to tell service accounts apart from users.
+	SubjectPrefix = "apikey:"
+)
+
+// The namespace permission bits of access tokens, as checked by the authz policies
+const (
+	namespaceRead     = 1
+	namespaceWrite    = 2
+	namespaceDownload = 4
+	namespaceCancel   = 8
+)
+
+// AuthorizerParams holds the dependencies of an API key authorizer
+type AuthorizerParams struct {
+	// Authorizer is the authorizer that requests are passed to
+	Authorizer authz.Authorizer
+	Manager    *Manager
+	// SigningKey signs the access tokens that API keys are exchanged for. Its
+	// public key must be the one the authz policy verifies tokens with.
+	SigningKey *rsa.PrivateKey
+	NodeID     string
+}
+
+type authorizer struct {
+	authorizer authz.Authorizer
+	manager    *Manager
+	signingKey *rsa.PrivateKey
+	nodeID     string
+}
+
+// NewAuthorizer returns an authorizer that accepts API keys as bearer tokens. The
+// API key of a request is replaced with an access token granting the namespace
+// permissions of the key, in the same form as the tokens issued by authenticators,
+// so that the authz policy applies to API keys without knowing about them.
+func NewAuthorizer(params AuthorizerParams) authz.Authorizer {
+	return &authorizer{
+		authorizer: params.Authorizer,
+		manager:    params.Manager,
+		signingKey: params.SigningKey,
+		nodeID:     params.NodeID,
+	}
+}
+
+// Authorize implements authz.Authorizer
+func (a *authorizer) Authorize(req *http.Request) (authz.Authorization, error) {
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), bearerPrefix)
+	if !found || !IsAPIKey(token) {
+		return a.authorizer.Authorize(req)
+	}
+
+	key, err := a.manager.Verify(req.Context(), token)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		return authz.Authorization{Approved: false, TokenValid: false, Reason: err.Error()}, nil
+	} else if err != nil {
+		return authz.Authorization{}, err
+	}
+
+	accessToken, err := a.accessToken(key)
+	if err != nil {
+		return authz.Authorization{}, err
+	}
+	req.Header.Set("Authorization", bearerPrefix+accessToken)
+	return a.authorizer.Authorize(req)
+}
+
+// accessToken returns a short-lived access token granting the permissions of the key
+func (a *authorizer) accessToken(key *models.APIKey) (string, error) {
+	now := time.Now()
+	token := jwt.New()
+	claims := map[string]any{
+		jwt.IssuerKey:     a.nodeID,
+		jwt.SubjectKey:    SubjectPrefix + key.Name,
+		jwt.AudienceKey:   []string{a.nodeID},
+		jwt.IssuedAtKey:   now,
+		jwt.ExpirationKey: now.Add(accessTokenTTL),
+		"ns":              Namespaces(key),
+	}
+	for name, value := range claims {
+		if err := token.Set(name, value); err != nil {
+			return "", err
+		}
+	}
+	signed, err := jwt.Sign(token, jwa.RS256, a.signingKey)
+	return string(signed), err
+}
+
+// Namespaces returns the namespace permissions that the key grants, keyed by
+// namespace as in the "ns" claim of access tokens.
+func Namespaces(key *models.APIKey) map[string]int {
+	var permissions int
+	if key.HasPermission(models.APIKeyPermissionRead) {
+		permissions |= namespaceRead | namespaceDownload
+	}
+	if key.HasPermission(models.APIKeyPermissionWrite) {
+		permissions |= namespaceWrite | namespaceCancel
+	}
+	namespaces := make(map[string]int, len(key.Namespaces))
+	for _, namespace := range key.Namespaces {
+		namespaces[namespace] = permissions
+	}
+	return namespaces
+}
+
+// compile-time check that authorizer implements the authz.Authorizer interface
+var _ authz.Authorizer = (*authorizer)(nil)
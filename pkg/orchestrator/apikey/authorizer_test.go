@@ -0,0 +1,96 @@
+//go:build unit || !integration
+
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestAuthorizerAppliesNamespacePolicy(t *testing.T) {
+	logger.ConfigureTestLogging(t)
+	ctx := context.Background()
+
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	policyPath, err := filepath.Abs("../../authz/policies/policy_ns_anon.rego")
+	require.NoError(t, err)
+	nsPolicy, err := policy.FromPath(policyPath)
+	require.NoError(t, err)
+
+	manager := NewManager(ManagerParams{Store: NewInMemoryStore()})
+	authorizer := NewAuthorizer(AuthorizerParams{
+		Authorizer: authz.NewPolicyAuthorizer(nsPolicy, &signingKey.PublicKey, "test-node"),
+		Manager:    manager,
+		SigningKey: signingKey,
+		NodeID:     "test-node",
+	})
+
+	_, writer, err := manager.Create(ctx, CreateParams{
+		Name:        "ci",
+		Namespaces:  []string{"test"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead, models.APIKeyPermissionWrite},
+		Granted:     fullAccess,
+	})
+	require.NoError(t, err)
+	_, reader, err := manager.Create(ctx, CreateParams{
+		Name:        "dashboard",
+		Namespaces:  []string{models.APIKeyAllNamespaces},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead},
+		Granted:     fullAccess,
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		apiKey     string
+		namespace  string
+		method     string
+		approved   bool
+		tokenValid bool
+	}{
+		{"allow submission to namespace of key", writer, "test", http.MethodPut, true, true},
+		{"deny submission to other namespace", writer, "other", http.MethodPut, false, true},
+		{"allow read of namespace of key", writer, "test", http.MethodGet, true, true},
+		{"allow read of all namespaces", reader, "other", http.MethodGet, true, true},
+		{"deny submission with read-only key", reader, "test", http.MethodPut, false, true},
+		{"deny unknown key", reader + "x", "test", http.MethodGet, false, false},
+	}
+
+	for _, testcase := range cases {
+		t.Run(testcase.name, func(t *testing.T) {
+			body, err := yaml.Marshal(&models.Job{Namespace: testcase.namespace})
+			require.NoError(t, err)
+			request, err := http.NewRequest(testcase.method, "/api/v1/orchestrator/jobs", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer "+testcase.apiKey)
+
+			result, err := authorizer.Authorize(request)
+			require.NoError(t, err)
+			require.Equal(t, testcase.approved, result.Approved)
+			require.Equal(t, testcase.tokenValid, result.TokenValid)
+		})
+	}
+
+	// revoked keys are no longer accepted
+	require.NoError(t, manager.Revoke(ctx, "ci"))
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/orchestrator/jobs", nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer "+writer)
+	result, err := authorizer.Authorize(request)
+	require.NoError(t, err)
+	require.False(t, result.TokenValid)
+}
@@ -0,0 +1,113 @@
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// BucketAPIKeys is the bolt bucket holding the API keys keyed by ID.
+const BucketAPIKeys = "apikeys"
+
+// BoltStore is a Store that persists the API keys in BoltDB, which is expected to be the
+// database of the jobstore so that keys survive restarts of the requester.
+type BoltStore struct {
+	database *bolt.DB
+}
+
+// NewBoltStore creates a new API key store persisted in the provided bolt database.
+func NewBoltStore(database *bolt.DB) (*BoltStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	err := database.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketAPIKeys))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API keys bucket: %w", err)
+	}
+	return &BoltStore{database: database}, nil
+}
+
+func (s *BoltStore) Get(_ context.Context, id string) (key models.APIKey, err error) {
+	err = s.database.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketAPIKeys)).Get([]byte(id))
+		if data == nil {
+			return NewErrAPIKeyNotFound(id)
+		}
+		return json.Unmarshal(data, &key)
+	})
+	return key, err
+}
+
+func (s *BoltStore) List(_ context.Context) ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0)
+	err := s.database.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketAPIKeys)).ForEach(func(_, v []byte) error {
+			var key models.APIKey
+			if err := json.Unmarshal(v, &key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	// keys are iterated in ID order, so sort them by name
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+	return keys, err
+}
+
+func (s *BoltStore) Put(_ context.Context, key models.APIKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return s.database.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketAPIKeys)).Put([]byte(key.ID), data)
+	})
+}
+
+func (s *BoltStore) UpdateLastUsed(_ context.Context, id string, lastUsedTime int64) error {
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketAPIKeys))
+		data := bkt.Get([]byte(id))
+		if data == nil {
+			return NewErrAPIKeyNotFound(id)
+		}
+		var key models.APIKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return err
+		}
+		key.LastUsedTime = lastUsedTime
+		data, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		return bkt.Put([]byte(id), data)
+	})
+}
+
+func (s *BoltStore) Delete(_ context.Context, id string) error {
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketAPIKeys))
+		if bkt.Get([]byte(id)) == nil {
+			return NewErrAPIKeyNotFound(id)
+		}
+		return bkt.Delete([]byte(id))
+	})
+}
+
+// compile-time check that BoltStore implements the Store interface
+var _ Store = (*BoltStore)(nil)
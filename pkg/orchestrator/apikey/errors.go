@@ -0,0 +1,50 @@
+package apikey
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidAPIKey is returned when a key is malformed, unknown, revoked or expired.
+// The reason is deliberately not detailed, so that keys cannot be probed.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// ErrAPIKeyNotFound is returned when there is no API key with the requested ID or name
+type ErrAPIKeyNotFound struct {
+	Key string
+}
+
+func NewErrAPIKeyNotFound(key string) ErrAPIKeyNotFound {
+	return ErrAPIKeyNotFound{Key: key}
+}
+
+func (e ErrAPIKeyNotFound) Error() string {
+	return fmt.Sprintf("API key not found: %s", e.Key)
+}
+
+// ErrAPIKeyAlreadyExists is returned when creating an API key with the name of an existing key
+type ErrAPIKeyAlreadyExists struct {
+	Name string
+}
+
+func NewErrAPIKeyAlreadyExists(name string) ErrAPIKeyAlreadyExists {
+	return ErrAPIKeyAlreadyExists{Name: name}
+}
+
+func (e ErrAPIKeyAlreadyExists) Error() string {
+	return fmt.Sprintf("API key already exists: %s", e.Name)
+}
+
+// ErrAPIKeyScopeExceeded is returned when creating an API key that grants permissions in a
+// namespace that the principal creating it does not have
+type ErrAPIKeyScopeExceeded struct {
+	Namespace string
+}
+
+func NewErrAPIKeyScopeExceeded(namespace string) ErrAPIKeyScopeExceeded {
+	return ErrAPIKeyScopeExceeded{Namespace: namespace}
+}
+
+func (e ErrAPIKeyScopeExceeded) Error() string {
+	return fmt.Sprintf("API key cannot grant permissions that the caller does not have in namespace %s", e.Namespace)
+}
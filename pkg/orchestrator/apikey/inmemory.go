@@ -0,0 +1,80 @@
+package apikey
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// InMemoryStore is a Store that keeps the API keys in memory, and loses them on restart.
+type InMemoryStore struct {
+	// keys is keyed by ID
+	keys map[string]models.APIKey
+	mu   sync.RWMutex
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		keys: make(map[string]models.APIKey),
+	}
+}
+
+func (s *InMemoryStore) Get(_ context.Context, id string) (models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return models.APIKey{}, NewErrAPIKeyNotFound(id)
+	}
+	return *key.Copy(), nil
+}
+
+func (s *InMemoryStore) List(_ context.Context) ([]models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]models.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *key.Copy())
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+	return keys, nil
+}
+
+func (s *InMemoryStore) Put(_ context.Context, key models.APIKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.ID] = *key.Copy()
+	return nil
+}
+
+func (s *InMemoryStore) UpdateLastUsed(_ context.Context, id string, lastUsedTime int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return NewErrAPIKeyNotFound(id)
+	}
+	key.LastUsedTime = lastUsedTime
+	s.keys[id] = key
+	return nil
+}
+
+func (s *InMemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[id]; !ok {
+		return NewErrAPIKeyNotFound(id)
+	}
+	delete(s.keys, id)
+	return nil
+}
+
+// compile-time check that InMemoryStore implements the Store interface
+var _ Store = (*InMemoryStore)(nil)
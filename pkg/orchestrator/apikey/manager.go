@@ -0,0 +1,231 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// Prefix identifies API keys among the bearer tokens of requests. Keys are
+	// made of the prefix, the ID of the key, '_' and the secret of the key.
+	Prefix = "bacalhau_"
+
+	idLength     = 8
+	secretLength = 32
+
+	// lastUsedResolution is how often the last used time of a key is updated at
+	// most, so that requests authenticated with a key do not all write to the store.
+	lastUsedResolution = time.Minute
+)
+
+// ManagerParams holds the dependencies of a Manager
+type ManagerParams struct {
+	Store Store
+	// Clock is the clock used to expire keys. Defaults to the real time clock.
+	Clock clock.Clock
+}
+
+// CreateParams holds the properties of an API key to create
+type CreateParams struct {
+	Name        string
+	Namespaces  []string
+	Permissions []models.APIKeyPermission
+	// ExpiresIn is how long the key is valid for, or zero if it never expires
+	ExpiresIn time.Duration
+	// Granted are the namespace permissions of the principal creating the key, keyed by
+	// namespace as in the "ns" claim of access tokens. The key cannot grant more than them.
+	Granted map[string]int
+}
+
+// Manager issues, verifies and revokes API keys.
+type Manager struct {
+	store Store
+	clock clock.Clock
+}
+
+func NewManager(params ManagerParams) *Manager {
+	clk := params.Clock
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &Manager{
+		store: params.Store,
+		clock: clk,
+	}
+}
+
+// Create issues a new API key, and returns it along with the key itself. The key
+// cannot be retrieved later, as only its hash is stored.
+func (m *Manager) Create(ctx context.Context, params CreateParams) (*models.APIKey, string, error) {
+	if params.ExpiresIn < 0 {
+		return nil, "", errors.New("API key expiry must not be negative")
+	}
+	if _, err := m.getByName(ctx, params.Name); err == nil {
+		return nil, "", NewErrAPIKeyAlreadyExists(params.Name)
+	} else if !errors.As(err, &ErrAPIKeyNotFound{}) {
+		return nil, "", err
+	}
+
+	id, err := randomBytes(idLength)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomBytes(secretLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := m.clock.Now().UTC()
+	key := &models.APIKey{
+		ID:          hex.EncodeToString(id),
+		Name:        params.Name,
+		Namespaces:  params.Namespaces,
+		Permissions: params.Permissions,
+		CreateTime:  now.UnixNano(),
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = hash(encodedSecret)
+	if params.ExpiresIn > 0 {
+		key.ExpireTime = now.Add(params.ExpiresIn).UnixNano()
+	}
+	key.Normalize()
+	if err = checkScope(key, params.Granted); err != nil {
+		return nil, "", err
+	}
+
+	if err = m.store.Put(ctx, *key); err != nil {
+		return nil, "", err
+	}
+	return key.Redacted(), Prefix + key.ID + "_" + encodedSecret, nil
+}
+
+// List returns the API keys without their hashes, sorted by name.
+func (m *Manager) List(ctx context.Context) ([]*models.APIKey, error) {
+	keys, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*models.APIKey, len(keys))
+	for i := range keys {
+		res[i] = keys[i].Redacted()
+	}
+	return res, nil
+}
+
+// Revoke removes the API key with the name, which is no longer accepted.
+func (m *Manager) Revoke(ctx context.Context, name string) error {
+	key, err := m.getByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	return m.store.Delete(ctx, key.ID)
+}
+
+// Verify returns the API key if the key is valid, and records that it was used.
+// ErrInvalidAPIKey is returned if it is not a valid key, or if it was revoked or
+// has expired.
+func (m *Manager) Verify(ctx context.Context, token string) (*models.APIKey, error) {
+	id, secret, ok := parse(token)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := m.store.Get(ctx, id)
+	if errors.As(err, &ErrAPIKeyNotFound{}) {
+		return nil, ErrInvalidAPIKey
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := m.clock.Now()
+	if subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(key.Hash)) != 1 || key.IsExpired(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if now.Sub(time.Unix(0, key.LastUsedTime)) >= lastUsedResolution {
+		key.LastUsedTime = now.UTC().UnixNano()
+		err = m.store.UpdateLastUsed(ctx, key.ID, key.LastUsedTime)
+		if errors.As(err, &ErrAPIKeyNotFound{}) {
+			// the key was revoked since it was read
+			return nil, ErrInvalidAPIKey
+		} else if err != nil {
+			// the key is still valid if its last use could not be recorded
+			log.Ctx(ctx).Warn().Err(err).Str("APIKey", key.Name).Msg("failed to record last use of API key")
+		}
+	}
+	return key.Redacted(), nil
+}
+
+func (m *Manager) getByName(ctx context.Context, name string) (models.APIKey, error) {
+	keys, err := m.store.List(ctx)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	for _, key := range keys {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+	return models.APIKey{}, NewErrAPIKeyNotFound(name)
+}
+
+// checkScope returns an error if the key grants permissions in any of its namespaces beyond
+// the granted permissions. Permissions granted in all namespaces apply to each namespace,
+// but permissions in all namespaces can only be granted by permissions in all namespaces.
+func checkScope(key *models.APIKey, granted map[string]int) error {
+	permissions := Namespaces(key)
+	for _, namespace := range key.Namespaces {
+		allowed := granted[models.APIKeyAllNamespaces]
+		if namespace != models.APIKeyAllNamespaces {
+			allowed |= granted[namespace]
+		}
+		if permissions[namespace]&^allowed != 0 {
+			return NewErrAPIKeyScopeExceeded(namespace)
+		}
+	}
+	return nil
+}
+
+// IsAPIKey returns true if the token looks like an API key, rather than another
+// kind of bearer token such as the access tokens issued by authenticators.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
+
+// parse splits an API key into the ID and the secret of the key
+func parse(token string) (id string, secret string, ok bool) {
+	rest, found := strings.CutPrefix(token, Prefix)
+	if !found {
+		return "", "", false
+	}
+	id, secret, found = strings.Cut(rest, "_")
+	if !found || len(id) != hex.EncodedLen(idLength) || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) ([]byte, error) {
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,219 @@
+//go:build unit || !integration
+
+package apikey
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// fullAccess grants all the permissions of API keys in all namespaces
+var fullAccess = map[string]int{
+	models.APIKeyAllNamespaces: namespaceRead | namespaceWrite | namespaceDownload | namespaceCancel,
+}
+
+type ManagerTestSuite struct {
+	suite.Suite
+	clock   *clock.Mock
+	store   *InMemoryStore
+	manager *Manager
+}
+
+func TestManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(ManagerTestSuite))
+}
+
+func (s *ManagerTestSuite) SetupTest() {
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.store = NewInMemoryStore()
+	s.manager = NewManager(ManagerParams{Store: s.store, Clock: s.clock})
+}
+
+func (s *ManagerTestSuite) create(name string, expiresIn time.Duration) (*models.APIKey, string) {
+	key, token, err := s.manager.Create(context.Background(), CreateParams{
+		Name:        name,
+		Namespaces:  []string{"team-a", "team-a", "team-b"},
+		Permissions: []models.APIKeyPermission{"Write", models.APIKeyPermissionRead},
+		ExpiresIn:   expiresIn,
+		Granted:     fullAccess,
+	})
+	s.Require().NoError(err)
+	return key, token
+}
+
+func (s *ManagerTestSuite) TestCreateStoresHashOnly() {
+	key, token := s.create("ci", 0)
+	s.True(IsAPIKey(token))
+	s.True(strings.HasPrefix(token, Prefix+key.ID+"_"))
+	s.Empty(key.Hash)
+	s.Equal([]string{"team-a", "team-b"}, key.Namespaces)
+	s.Equal([]models.APIKeyPermission{models.APIKeyPermissionRead, models.APIKeyPermissionWrite}, key.Permissions)
+
+	stored, err := s.store.Get(context.Background(), key.ID)
+	s.Require().NoError(err)
+	s.NotEmpty(stored.Hash)
+	s.NotContains(token, stored.Hash)
+
+	keys, err := s.manager.List(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(keys, 1)
+	s.Empty(keys[0].Hash)
+}
+
+func (s *ManagerTestSuite) TestCreateDuplicateName() {
+	s.create("ci", 0)
+	_, _, err := s.manager.Create(context.Background(), CreateParams{
+		Name:        "ci",
+		Namespaces:  []string{"default"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead},
+		Granted:     fullAccess,
+	})
+	s.ErrorAs(err, &ErrAPIKeyAlreadyExists{})
+}
+
+func (s *ManagerTestSuite) TestCreateWithinGrantedScope() {
+	granted := map[string]int{
+		models.APIKeyAllNamespaces: namespaceRead | namespaceDownload,
+		"team-a":                   namespaceWrite | namespaceCancel,
+	}
+	cases := []struct {
+		name        string
+		namespaces  []string
+		permissions []models.APIKeyPermission
+		exceeded    string
+	}{
+		{"read in all namespaces", []string{models.APIKeyAllNamespaces},
+			[]models.APIKeyPermission{models.APIKeyPermissionRead}, ""},
+		{"write in granted namespace", []string{"team-a"},
+			[]models.APIKeyPermission{models.APIKeyPermissionRead, models.APIKeyPermissionWrite}, ""},
+		{"write in other namespace", []string{"team-a", "team-b"},
+			[]models.APIKeyPermission{models.APIKeyPermissionWrite}, "team-b"},
+		{"write in all namespaces", []string{models.APIKeyAllNamespaces},
+			[]models.APIKeyPermission{models.APIKeyPermissionWrite}, models.APIKeyAllNamespaces},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			_, _, err := s.manager.Create(context.Background(), CreateParams{
+				Name:        strings.ReplaceAll(tc.name, " ", "-"),
+				Namespaces:  tc.namespaces,
+				Permissions: tc.permissions,
+				Granted:     granted,
+			})
+			if tc.exceeded == "" {
+				s.NoError(err)
+			} else {
+				s.Equal(NewErrAPIKeyScopeExceeded(tc.exceeded), err)
+			}
+		})
+	}
+
+	// callers without an access token cannot grant anything
+	_, _, err := s.manager.Create(context.Background(), CreateParams{
+		Name:        "anonymous",
+		Namespaces:  []string{"default"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead},
+	})
+	s.ErrorAs(err, &ErrAPIKeyScopeExceeded{})
+}
+
+func (s *ManagerTestSuite) TestVerify() {
+	ctx := context.Background()
+	key, token := s.create("ci", 0)
+
+	verified, err := s.manager.Verify(ctx, token)
+	s.Require().NoError(err)
+	s.Equal(key.ID, verified.ID)
+	s.Equal(s.clock.Now().UnixNano(), verified.LastUsedTime)
+
+	for _, invalid := range []string{
+		"",
+		"not-a-key",
+		Prefix + key.ID,
+		Prefix + key.ID + "_wrong-secret",
+		Prefix + "0000000000000000" + strings.TrimPrefix(token, Prefix+key.ID),
+	} {
+		_, err = s.manager.Verify(ctx, invalid)
+		s.ErrorIs(err, ErrInvalidAPIKey, invalid)
+	}
+}
+
+func (s *ManagerTestSuite) TestLastUsedUpdatedOncePerMinute() {
+	ctx := context.Background()
+	key, token := s.create("ci", 0)
+	firstUse := s.clock.Now().UnixNano()
+
+	_, err := s.manager.Verify(ctx, token)
+	s.Require().NoError(err)
+	s.clock.Add(30 * time.Second)
+	_, err = s.manager.Verify(ctx, token)
+	s.Require().NoError(err)
+	stored, err := s.store.Get(ctx, key.ID)
+	s.Require().NoError(err)
+	s.Equal(firstUse, stored.LastUsedTime)
+
+	s.clock.Add(30 * time.Second)
+	_, err = s.manager.Verify(ctx, token)
+	s.Require().NoError(err)
+	stored, err = s.store.Get(ctx, key.ID)
+	s.Require().NoError(err)
+	s.Equal(s.clock.Now().UnixNano(), stored.LastUsedTime)
+}
+
+func (s *ManagerTestSuite) TestExpiry() {
+	_, token := s.create("ci", time.Hour)
+
+	s.clock.Add(59 * time.Minute)
+	_, err := s.manager.Verify(context.Background(), token)
+	s.Require().NoError(err)
+
+	s.clock.Add(time.Minute)
+	_, err = s.manager.Verify(context.Background(), token)
+	s.ErrorIs(err, ErrInvalidAPIKey)
+}
+
+// revokingStore revokes API keys right after they are read, as if they were revoked
+// while being verified
+type revokingStore struct {
+	*InMemoryStore
+}
+
+func (s revokingStore) Get(ctx context.Context, id string) (models.APIKey, error) {
+	key, err := s.InMemoryStore.Get(ctx, id)
+	if err == nil {
+		err = s.InMemoryStore.Delete(ctx, id)
+	}
+	return key, err
+}
+
+func (s *ManagerTestSuite) TestRevokeWhileVerifying() {
+	ctx := context.Background()
+	key, token := s.create("ci", 0)
+
+	manager := NewManager(ManagerParams{Store: revokingStore{s.store}, Clock: s.clock})
+	_, err := manager.Verify(ctx, token)
+	s.ErrorIs(err, ErrInvalidAPIKey)
+	_, err = s.store.Get(ctx, key.ID)
+	s.ErrorAs(err, &ErrAPIKeyNotFound{}, "recording the last use should not restore a revoked key")
+}
+
+func (s *ManagerTestSuite) TestRevoke() {
+	ctx := context.Background()
+	_, token := s.create("ci", 0)
+	_, otherToken := s.create("nightly", 0)
+
+	s.Require().NoError(s.manager.Revoke(ctx, "ci"))
+	_, err := s.manager.Verify(ctx, token)
+	s.ErrorIs(err, ErrInvalidAPIKey)
+	_, err = s.manager.Verify(ctx, otherToken)
+	s.NoError(err)
+
+	s.ErrorAs(s.manager.Revoke(ctx, "ci"), &ErrAPIKeyNotFound{})
+}
@@ -0,0 +1,121 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// SQLStore is a Store that persists the API keys in the database of the SQL jobstore, so
+// that keys survive restarts of the requester.
+type SQLStore struct {
+	database *sqljobstore.Database
+}
+
+// NewSQLStore creates a new API key store persisted in the provided database.
+func NewSQLStore(ctx context.Context, database *sqljobstore.Database) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	_, err := database.Exec(ctx, `CREATE TABLE IF NOT EXISTS api_keys (
+		id   TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		data `+database.JSONType()+` NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API keys table: %w", err)
+	}
+	return &SQLStore{database: database}, nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (key models.APIKey, err error) {
+	var data string
+	err = s.database.QueryRow(ctx, `SELECT data FROM api_keys WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return key, NewErrAPIKeyNotFound(id)
+	}
+	if err != nil {
+		return key, err
+	}
+	err = json.Unmarshal([]byte(data), &key)
+	return key, err
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := s.database.Query(ctx, `SELECT data FROM api_keys ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]models.APIKey, 0)
+	for rows.Next() {
+		var data string
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var key models.APIKey
+		if err = json.Unmarshal([]byte(data), &key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *SQLStore) Put(ctx context.Context, key models.APIKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.database.Exec(ctx, `INSERT INTO api_keys (id, name, data) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, data = excluded.data`, key.ID, key.Name, string(data))
+	return err
+}
+
+func (s *SQLStore) UpdateLastUsed(ctx context.Context, id string, lastUsedTime int64) error {
+	return s.database.Update(ctx, func(tx *sqljobstore.Tx) error {
+		var data string
+		err := tx.QueryRow(ctx, tx.ForUpdate(`SELECT data FROM api_keys WHERE id = ?`), id).Scan(&data)
+		if errors.Is(err, sql.ErrNoRows) {
+			return NewErrAPIKeyNotFound(id)
+		}
+		if err != nil {
+			return err
+		}
+		var key models.APIKey
+		if err = json.Unmarshal([]byte(data), &key); err != nil {
+			return err
+		}
+		key.LastUsedTime = lastUsedTime
+		updated, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE api_keys SET data = ? WHERE id = ?`, string(updated), id)
+		return err
+	})
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	result, err := s.database.Exec(ctx, `DELETE FROM api_keys WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return NewErrAPIKeyNotFound(id)
+	}
+	return nil
+}
+
+// compile-time check that SQLStore implements the Store interface
+var _ Store = (*SQLStore)(nil)
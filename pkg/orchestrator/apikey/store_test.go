@@ -0,0 +1,126 @@
+//go:build unit || !integration
+
+package apikey
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+	_ "modernc.org/sqlite"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	newStore func() Store
+	store    Store
+}
+
+func TestInMemoryStoreTestSuite(t *testing.T) {
+	suite.Run(t, &StoreTestSuite{newStore: func() Store { return NewInMemoryStore() }})
+}
+
+func TestBoltStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		database, err := bolt.Open(filepath.Join(s.T().TempDir(), "apikeys.db"), 0600, nil)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = database.Close() })
+		store, err := NewBoltStore(database)
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func TestSQLStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		ctx := context.Background()
+		jobStore, err := sqljobstore.NewSQLJobStore(ctx, sqljobstore.DriverSQLite, ":memory:")
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = jobStore.Close(ctx) })
+		store, err := NewSQLStore(ctx, jobStore.Database())
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.store = s.newStore()
+}
+
+func newTestKey(id, name string) models.APIKey {
+	return models.APIKey{
+		ID:          id,
+		Name:        name,
+		Namespaces:  []string{"default"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead},
+		Hash:        "hash-of-" + id,
+		CreateTime:  1,
+	}
+}
+
+func (s *StoreTestSuite) TestPutGetDelete() {
+	ctx := context.Background()
+	key := newTestKey("0001", "ci")
+	s.Require().NoError(s.store.Put(ctx, key))
+
+	stored, err := s.store.Get(ctx, "0001")
+	s.Require().NoError(err)
+	s.Equal(key, stored)
+
+	// put replaces the existing key
+	key.LastUsedTime = 2
+	s.Require().NoError(s.store.Put(ctx, key))
+	stored, err = s.store.Get(ctx, "0001")
+	s.Require().NoError(err)
+	s.Equal(int64(2), stored.LastUsedTime)
+
+	s.Require().NoError(s.store.Delete(ctx, "0001"))
+	_, err = s.store.Get(ctx, "0001")
+	s.ErrorAs(err, &ErrAPIKeyNotFound{})
+	s.ErrorAs(s.store.Delete(ctx, "0001"), &ErrAPIKeyNotFound{})
+}
+
+func (s *StoreTestSuite) TestUpdateLastUsed() {
+	ctx := context.Background()
+	key := newTestKey("0001", "ci")
+	s.Require().NoError(s.store.Put(ctx, key))
+
+	s.Require().NoError(s.store.UpdateLastUsed(ctx, "0001", 2))
+	stored, err := s.store.Get(ctx, "0001")
+	s.Require().NoError(err)
+	key.LastUsedTime = 2
+	s.Equal(key, stored)
+
+	// deleted keys are not created again
+	s.Require().NoError(s.store.Delete(ctx, "0001"))
+	s.ErrorAs(s.store.UpdateLastUsed(ctx, "0001", 3), &ErrAPIKeyNotFound{})
+	_, err = s.store.Get(ctx, "0001")
+	s.ErrorAs(err, &ErrAPIKeyNotFound{})
+}
+
+func (s *StoreTestSuite) TestListSortedByName() {
+	ctx := context.Background()
+	s.Require().NoError(s.store.Put(ctx, newTestKey("0001", "nightly")))
+	s.Require().NoError(s.store.Put(ctx, newTestKey("0002", "ci")))
+
+	keys, err := s.store.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(keys, 2)
+	s.Equal("ci", keys[0].Name)
+	s.Equal("nightly", keys[1].Name)
+}
+
+func (s *StoreTestSuite) TestPutInvalid() {
+	key := newTestKey("0001", "ci")
+	key.Permissions = []models.APIKeyPermission{"admin"}
+	s.Error(s.store.Put(context.Background(), key))
+}
@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Store persists the API keys issued by the requester.
+type Store interface {
+	// Get returns the API key with the ID including its hash, or
+	// ErrAPIKeyNotFound if there is no such key.
+	Get(ctx context.Context, id string) (models.APIKey, error)
+
+	// List returns the API keys including their hashes, sorted by name.
+	List(ctx context.Context) ([]models.APIKey, error)
+
+	// Put creates or replaces an API key.
+	Put(ctx context.Context, key models.APIKey) error
+
+	// UpdateLastUsed sets when the API key was last used, or returns ErrAPIKeyNotFound
+	// if there is no such key. The key is never created, so that a key that is deleted
+	// while it is being used stays deleted.
+	UpdateLastUsed(ctx context.Context, id string, lastUsedTime int64) error
+
+	// Delete removes an API key.
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// BucketAudit is the bolt bucket holding the audit log, keyed by the big-endian
+// ID of the events so that they are iterated in the order they were recorded.
+const BucketAudit = "audit"
+
+// BoltStore is a Store that persists the audit log in BoltDB, which is expected to be
+// the database of the jobstore so that the audit log survives restarts of the requester.
+type BoltStore struct {
+	database *bolt.DB
+}
+
+// NewBoltStore creates a new audit store persisted in the provided bolt database.
+func NewBoltStore(database *bolt.DB) (*BoltStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	err := database.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketAudit))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit bucket: %w", err)
+	}
+	return &BoltStore{database: database}, nil
+}
+
+func (s *BoltStore) Append(_ context.Context, event models.AuditEvent) (models.AuditEvent, error) {
+	if err := event.Validate(); err != nil {
+		return models.AuditEvent{}, err
+	}
+	err := s.database.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketAudit))
+		id, err := bkt.NextSequence()
+		if err != nil {
+			return err
+		}
+		event.ID = id
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(eventKey(id), data)
+	})
+	if err != nil {
+		return models.AuditEvent{}, err
+	}
+	return event, nil
+}
+
+func (s *BoltStore) Query(_ context.Context, query Query) ([]models.AuditEvent, error) {
+	events := make([]models.AuditEvent, 0)
+	err := s.database.View(func(tx *bolt.Tx) error {
+		// walk the log backwards so that the limit keeps the most recent events
+		c := tx.Bucket([]byte(BucketAudit)).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if query.Limit > 0 && len(events) >= query.Limit {
+				return nil
+			}
+			var event models.AuditEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+			if query.Matches(event) {
+				events = append(events, event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(events)
+	return events, nil
+}
+
+func eventKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// compile-time check that BoltStore implements the Store interface
+var _ Store = (*BoltStore)(nil)
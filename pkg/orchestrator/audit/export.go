@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// WriteJSONLines writes the events as JSON lines, one event per line, which is
+// the format the audit log is exported in.
+func WriteJSONLines(w io.Writer, events []models.AuditEvent) error {
+	encoder := json.NewEncoder(w)
+	for i := range events {
+		if err := encoder.Encode(events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// InMemoryStore is a Store that keeps the audit log in memory, and loses it on restart.
+type InMemoryStore struct {
+	events []models.AuditEvent
+	mu     sync.RWMutex
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{}
+}
+
+func (s *InMemoryStore) Append(_ context.Context, event models.AuditEvent) (models.AuditEvent, error) {
+	if err := event.Validate(); err != nil {
+		return models.AuditEvent{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event.ID = uint64(len(s.events)) + 1
+	s.events = append(s.events, event)
+	return event, nil
+}
+
+func (s *InMemoryStore) Query(_ context.Context, query Query) ([]models.AuditEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events := make([]models.AuditEvent, 0)
+	// walk the log backwards so that the limit keeps the most recent events
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(events) >= query.Limit {
+			break
+		}
+		if query.Matches(s.events[i]) {
+			events = append(events, s.events[i])
+		}
+	}
+	slices.Reverse(events)
+	return events, nil
+}
+
+// compile-time check that InMemoryStore implements the Store interface
+var _ Store = (*InMemoryStore)(nil)
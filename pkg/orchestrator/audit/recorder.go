@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// RecorderParams holds the dependencies of a Recorder
+type RecorderParams struct {
+	Store Store
+	// Clock is the clock used to timestamp events. Defaults to the real time clock.
+	Clock clock.Clock
+}
+
+// Recorder records audited actions in the audit log.
+type Recorder struct {
+	store Store
+	clock clock.Clock
+}
+
+func NewRecorder(params RecorderParams) *Recorder {
+	clk := params.Clock
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &Recorder{
+		store: params.Store,
+		clock: clk,
+	}
+}
+
+// Record appends the event to the audit log. The time of the event is set if it
+// is missing, as are its principal and request ID from the API request of the
+// context. Events outside of API requests are recorded for the system principal.
+// Actions have already been carried out when they are recorded, so failures to
+// record them are logged rather than returned.
+func (r *Recorder) Record(ctx context.Context, event models.AuditEvent) {
+	if event.Time == 0 {
+		event.Time = r.clock.Now().UTC().UnixNano()
+	}
+	if request, ok := RequestFromContext(ctx); ok {
+		if event.Principal == "" {
+			event.Principal = request.Principal
+		}
+		if event.RequestID == "" {
+			event.RequestID = request.ID
+		}
+	}
+	if event.Principal == "" {
+		event.Principal = models.AuditPrincipalSystem
+	}
+	if _, err := r.store.Append(ctx, event); err != nil {
+		log.Ctx(ctx).Error().Err(err).
+			Str("Principal", event.Principal).
+			Str("Action", event.Action).
+			Str("Target", event.Target).
+			Msg("failed to record audit event")
+	}
+}
+
+// Query returns the events of the audit log matching the query.
+func (r *Recorder) Query(ctx context.Context, query Query) ([]models.AuditEvent, error) {
+	return r.store.Query(ctx, query)
+}
+
+// Request identifies the API request that actions are carried out for
+type Request struct {
+	ID        string
+	Principal string
+}
+
+type requestContextKey struct{}
+
+// ContextWithRequest returns a context carrying the API request, so that the
+// actions carried out for the request are recorded with its principal and ID.
+func ContextWithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, request)
+}
+
+// RequestFromContext returns the API request carried by the context, if any.
+func RequestFromContext(ctx context.Context) (Request, bool) {
+	request, ok := ctx.Value(requestContextKey{}).(Request)
+	return request, ok
+}
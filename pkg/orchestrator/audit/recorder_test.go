@@ -0,0 +1,69 @@
+//go:build unit || !integration
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestRecorderRecordsRequestOfContext(t *testing.T) {
+	clk := clock.NewMock()
+	clk.Set(time.Unix(100, 0))
+	recorder := NewRecorder(RecorderParams{Store: NewInMemoryStore(), Clock: clk})
+
+	ctx := ContextWithRequest(context.Background(), Request{ID: "req-1", Principal: "alice"})
+	recorder.Record(ctx, models.AuditEvent{
+		Action:  models.AuditActionNodeApprove,
+		Target:  "n-1",
+		Outcome: models.AuditOutcomeSuccess,
+	})
+	// events outside of API requests are carried out by the requester itself
+	recorder.Record(context.Background(), models.AuditEvent{
+		Action:  models.AuditActionNodeReject,
+		Target:  "n-2",
+		Outcome: models.AuditOutcomeSuccess,
+	})
+
+	events, err := recorder.Query(context.Background(), Query{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.AuditEvent{
+		ID:        1,
+		Time:      time.Unix(100, 0).UnixNano(),
+		Principal: "alice",
+		Action:    models.AuditActionNodeApprove,
+		Target:    "n-1",
+		RequestID: "req-1",
+		Outcome:   models.AuditOutcomeSuccess,
+	}, events[0])
+	assert.Equal(t, models.AuditPrincipalSystem, events[1].Principal)
+	assert.Empty(t, events[1].RequestID)
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	events := []models.AuditEvent{
+		{ID: 1, Time: 1, Principal: "alice", Action: "PUT /api/v1/orchestrator/jobs", Target: "j-1", Outcome: models.AuditOutcomeSuccess},
+		{ID: 2, Time: 2, Principal: "bob", Action: models.AuditActionNodeDelete, Target: "n-1", Outcome: models.AuditOutcomeFailure},
+	}
+	var out bytes.Buffer
+	require.NoError(t, WriteJSONLines(&out, events))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, len(events))
+	for i, line := range lines {
+		var event models.AuditEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		assert.Equal(t, events[i], event)
+	}
+}
@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// SQLStore is a Store that persists the audit log in the database of the SQL jobstore, so
+// that the audit log survives restarts of the requester. The fields that events are
+// queried by are duplicated into columns next to the events.
+type SQLStore struct {
+	database *sqljobstore.Database
+}
+
+// NewSQLStore creates a new audit store persisted in the provided database.
+func NewSQLStore(ctx context.Context, database *sqljobstore.Database) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	for _, statement := range []string{
+		`CREATE TABLE IF NOT EXISTS audit_events (
+			id        ` + database.SerialKey() + `,
+			principal TEXT NOT NULL,
+			action    TEXT NOT NULL,
+			target    TEXT NOT NULL,
+			time      BIGINT NOT NULL,
+			data      ` + database.JSONType() + ` NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_events_time ON audit_events (time)`,
+	} {
+		if _, err := database.Exec(ctx, statement); err != nil {
+			return nil, fmt.Errorf("failed to create audit table: %w", err)
+		}
+	}
+	return &SQLStore{database: database}, nil
+}
+
+func (s *SQLStore) Append(ctx context.Context, event models.AuditEvent) (models.AuditEvent, error) {
+	if err := event.Validate(); err != nil {
+		return models.AuditEvent{}, err
+	}
+	// the ID is assigned by the database, and is not part of the stored event
+	event.ID = 0
+	data, err := json.Marshal(event)
+	if err != nil {
+		return models.AuditEvent{}, err
+	}
+	var id int64
+	err = s.database.QueryRow(ctx,
+		`INSERT INTO audit_events (principal, action, target, time, data) VALUES (?, ?, ?, ?, ?) RETURNING id`,
+		event.Principal, event.Action, event.Target, event.Time, string(data)).Scan(&id)
+	if err != nil {
+		return models.AuditEvent{}, err
+	}
+	event.ID = uint64(id)
+	return event, nil
+}
+
+func (s *SQLStore) Query(ctx context.Context, query Query) ([]models.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if query.Principal != "" {
+		addCondition("principal = ?", query.Principal)
+	}
+	if query.Action != "" {
+		addCondition("action = ?", query.Action)
+	}
+	if query.Target != "" {
+		addCondition("target = ?", query.Target)
+	}
+	if !query.After.IsZero() {
+		addCondition("time >= ?", query.After.UnixNano())
+	}
+	if !query.Before.IsZero() {
+		addCondition("time < ?", query.Before.UnixNano())
+	}
+
+	// select the most recent events first so that the limit keeps them
+	statement := `SELECT id, data FROM audit_events`
+	if len(conditions) > 0 {
+		statement += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	statement += ` ORDER BY id DESC`
+	if query.Limit > 0 {
+		statement += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.database.Query(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]models.AuditEvent, 0)
+	for rows.Next() {
+		var id int64
+		var data string
+		if err = rows.Scan(&id, &data); err != nil {
+			return nil, err
+		}
+		var event models.AuditEvent
+		if err = json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, err
+		}
+		event.ID = uint64(id)
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.Reverse(events)
+	return events, nil
+}
+
+// compile-time check that SQLStore implements the Store interface
+var _ Store = (*SQLStore)(nil)
@@ -0,0 +1,129 @@
+//go:build unit || !integration
+
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+	_ "modernc.org/sqlite"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	newStore func() Store
+	store    Store
+}
+
+func TestInMemoryStoreTestSuite(t *testing.T) {
+	suite.Run(t, &StoreTestSuite{newStore: func() Store { return NewInMemoryStore() }})
+}
+
+func TestBoltStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		database, err := bolt.Open(filepath.Join(s.T().TempDir(), "audit.db"), 0600, nil)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = database.Close() })
+		store, err := NewBoltStore(database)
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func TestSQLStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		ctx := context.Background()
+		jobStore, err := sqljobstore.NewSQLJobStore(ctx, sqljobstore.DriverSQLite, ":memory:")
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = jobStore.Close(ctx) })
+		store, err := NewSQLStore(ctx, jobStore.Database())
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.store = s.newStore()
+}
+
+func newTestEvent(seconds int64, principal, action, target string) models.AuditEvent {
+	return models.AuditEvent{
+		Time:      time.Unix(seconds, 0).UnixNano(),
+		Principal: principal,
+		Action:    action,
+		Target:    target,
+		Outcome:   models.AuditOutcomeSuccess,
+	}
+}
+
+func (s *StoreTestSuite) appendEvents(events ...models.AuditEvent) {
+	for _, event := range events {
+		_, err := s.store.Append(context.Background(), event)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *StoreTestSuite) TestAppend() {
+	ctx := context.Background()
+	first, err := s.store.Append(ctx, newTestEvent(1, "alice", "PUT /api/v1/orchestrator/jobs", "j-1"))
+	s.Require().NoError(err)
+	second, err := s.store.Append(ctx, newTestEvent(2, "bob", models.AuditActionNodeApprove, "n-1"))
+	s.Require().NoError(err)
+	s.Equal(uint64(1), first.ID)
+	s.Equal(uint64(2), second.ID)
+
+	events, err := s.store.Query(ctx, Query{})
+	s.Require().NoError(err)
+	s.Equal([]models.AuditEvent{first, second}, events)
+}
+
+func (s *StoreTestSuite) TestAppendInvalid() {
+	ctx := context.Background()
+	event := newTestEvent(1, "alice", "", "j-1")
+	_, err := s.store.Append(ctx, event)
+	s.Error(err)
+
+	events, err := s.store.Query(ctx, Query{})
+	s.Require().NoError(err)
+	s.Empty(events)
+}
+
+func (s *StoreTestSuite) TestQuery() {
+	s.appendEvents(
+		newTestEvent(10, "alice", "PUT /api/v1/orchestrator/jobs", "j-1"),
+		newTestEvent(20, "bob", "DELETE /api/v1/orchestrator/jobs/:id", "j-1"),
+		newTestEvent(30, "alice", models.AuditActionNodeApprove, "n-1"),
+		newTestEvent(40, "alice", "DELETE /api/v1/orchestrator/jobs/:id", "j-2"),
+	)
+
+	targets := func(query Query) []string {
+		events, err := s.store.Query(context.Background(), query)
+		s.Require().NoError(err)
+		res := make([]string, len(events))
+		for i := range events {
+			res[i] = events[i].Principal + "/" + events[i].Target
+		}
+		return res
+	}
+
+	s.Equal([]string{"alice/j-1", "alice/n-1", "alice/j-2"}, targets(Query{Principal: "alice"}))
+	s.Equal([]string{"bob/j-1", "alice/j-2"}, targets(Query{Action: "DELETE /api/v1/orchestrator/jobs/:id"}))
+	s.Equal([]string{"alice/j-1", "bob/j-1"}, targets(Query{Target: "j-1"}))
+	s.Equal([]string{"bob/j-1", "alice/n-1"}, targets(Query{After: time.Unix(20, 0), Before: time.Unix(40, 0)}))
+	s.Empty(targets(Query{Principal: "carol"}))
+
+	// the limit keeps the most recent events, in the order they were recorded
+	s.Equal([]string{"alice/n-1", "alice/j-2"}, targets(Query{Limit: 2}))
+	s.Equal([]string{"alice/j-1", "alice/n-1"}, targets(Query{Principal: "alice", Before: time.Unix(40, 0), Limit: 2}))
+}
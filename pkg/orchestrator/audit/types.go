@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Store persists the audit log of the requester. Events can only be appended,
+// and are never updated or removed.
+type Store interface {
+	// Append records the event at the end of the audit log, and returns it with
+	// its ID assigned.
+	Append(ctx context.Context, event models.AuditEvent) (models.AuditEvent, error)
+
+	// Query returns the events matching the query, in the order they were recorded.
+	Query(ctx context.Context, query Query) ([]models.AuditEvent, error)
+}
+
+// Query selects events of the audit log. Fields that are not set match all events.
+type Query struct {
+	Principal string
+	Action    string
+	Target    string
+	// After and Before select events recorded at or after, and strictly before, the given times
+	After  time.Time
+	Before time.Time
+	// Limit returns only the most recent events matching the query
+	Limit int
+}
+
+// Matches returns true if the event is selected by the query, ignoring its limit
+func (q Query) Matches(event models.AuditEvent) bool {
+	if q.Principal != "" && event.Principal != q.Principal {
+		return false
+	}
+	if q.Action != "" && event.Action != q.Action {
+		return false
+	}
+	if q.Target != "" && event.Target != q.Target {
+		return false
+	}
+	if !q.After.IsZero() && event.Time < q.After.UnixNano() {
+		return false
+	}
+	if !q.Before.IsZero() && event.Time >= q.Before.UnixNano() {
+		return false
+	}
+	return true
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/secret"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/transformer"
 	"github.com/bacalhau-project/bacalhau/pkg/translation"
 	"github.com/google/uuid"
@@ -20,6 +22,10 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// persistedLogsTimeout is how long to wait for the persisted logs of a completed execution,
+// before falling back to the execution's output, e.g. when its compute node is unreachable
+const persistedLogsTimeout = 10 * time.Second
+
 type BaseEndpointParams struct {
 	ID                string
 	EvaluationBroker  EvaluationBroker
@@ -29,6 +35,11 @@ type BaseEndpointParams struct {
 	JobTransformer    transformer.JobTransformer
 	TaskTranslator    translation.TranslatorProvider
 	ResultTransformer transformer.ResultTransformer
+	Workflow          *Workflow
+	QuotaEnforcer     *quota.Enforcer
+	// SecretStore holds the secrets that tasks can reference. Jobs referencing
+	// secrets are rejected if nil.
+	SecretStore secret.Store
 }
 
 type BaseEndpoint struct {
@@ -40,6 +51,9 @@ type BaseEndpoint struct {
 	jobTransformer    transformer.JobTransformer
 	taskTranslator    translation.TranslatorProvider
 	resultTransformer transformer.ResultTransformer
+	workflow          *Workflow
+	quotaEnforcer     *quota.Enforcer
+	secretStore       secret.Store
 }
 
 func NewBaseEndpoint(params *BaseEndpointParams) *BaseEndpoint {
@@ -52,6 +66,9 @@ func NewBaseEndpoint(params *BaseEndpointParams) *BaseEndpoint {
 		jobTransformer:    params.JobTransformer,
 		taskTranslator:    params.TaskTranslator,
 		resultTransformer: params.ResultTransformer,
+		workflow:          params.Workflow,
+		quotaEnforcer:     params.QuotaEnforcer,
+		secretStore:       params.SecretStore,
 	}
 }
 
@@ -95,6 +112,57 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		}
 	}
 
+	if err := e.checkSecrets(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if job.HasDependencies() {
+		if e.workflow == nil {
+			return nil, errors.New("job dependencies are not supported by this orchestrator")
+		}
+		if err := e.workflow.Resolve(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	// Long-running jobs submitted with the name of an active job update that job to a new
+	// version, and the scheduler replaces its executions following the job's update strategy.
+	if job.IsLongRunning() {
+		existing, found, err := e.findActiveJob(ctx, *job)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			job.ID = existing.ID
+			if err = e.store.UpdateJob(ctx, *job, JobUpdatedEvent(existing)); err != nil {
+				return nil, err
+			}
+			eval, err := e.enqueueJobUpdate(ctx, *job)
+			if err != nil {
+				return nil, err
+			}
+			return &SubmitJobResponse{
+				JobID:        job.ID,
+				EvaluationID: eval.ID,
+				Warnings:     warnings,
+			}, nil
+		}
+	}
+
+	// Jobs over the quota of their namespace are accepted but kept pending. Their first evaluation
+	// is delayed, and the scheduler holds them back until the namespace is within quota again.
+	var waitUntil time.Time
+	if e.quotaEnforcer != nil {
+		reasons, err := e.quotaEnforcer.Check(ctx, *job)
+		if err != nil {
+			return nil, err
+		}
+		if len(reasons) > 0 {
+			events = append(events, JobWaitingForQuotaEvent(job.Namespace, reasons))
+			waitUntil = time.Now().Add(e.quotaEnforcer.RetryInterval())
+		}
+	}
+
 	for i, event := range events {
 		if i == 0 {
 			if err := e.store.CreateJob(ctx, *job, events[0]); err != nil {
@@ -114,10 +182,47 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		TriggeredBy: models.EvalTriggerJobRegister,
 		Type:        job.Type,
 		Status:      models.EvalStatusPending,
+		WaitUntil:   waitUntil,
 		CreateTime:  job.CreateTime,
 		ModifyTime:  job.CreateTime,
 	}
 
+	// Jobs waiting for upstream jobs are created with a blocked evaluation, and the workflow
+	// enqueues a new evaluation once all the upstream jobs have completed.
+	// The job is created before checking the dependencies so that it is visible to the workflow
+	// if an upstream job completes in the meantime.
+	if job.HasDependencies() {
+		status, err := e.workflow.Status(ctx, *job)
+		if err != nil {
+			return nil, err
+		}
+		if status.Failed != nil {
+			err = e.store.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
+				JobID:    job.ID,
+				NewState: models.JobStateTypeFailed,
+				Event:    JobDependencyFailedEvent(*status.Failed),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &SubmitJobResponse{
+				JobID:    job.ID,
+				Warnings: warnings,
+			}, nil
+		}
+		if !status.Ready() {
+			eval.Status = models.EvalStatusBlocked
+			err = e.store.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
+				JobID:    job.ID,
+				NewState: models.JobStateTypePending,
+				Event:    JobWaitingForDependenciesEvent(status.Pending),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// TODO(ross): How can we create this evaluation in the same transaction that the CreateJob
 	// call uses.
 	if err := e.store.CreateEvaluation(ctx, *eval); err != nil {
@@ -125,8 +230,10 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		return nil, err
 	}
 
-	if err := e.evaluationBroker.Enqueue(eval); err != nil {
-		return nil, err
+	if eval.ShouldEnqueue() {
+		if err := e.evaluationBroker.Enqueue(eval); err != nil {
+			return nil, err
+		}
 	}
 	e.eventEmitter.EmitJobCreated(ctx, *job)
 	return &SubmitJobResponse{
@@ -204,6 +311,133 @@ func (e *BaseEndpoint) StopJob(ctx context.Context, request *StopJobRequest) (St
 	}, nil
 }
 
+// findActiveJob looks for a job that is not terminal, with the same name, namespace and type
+// as the given job.
+func (e *BaseEndpoint) findActiveJob(ctx context.Context, job models.Job) (models.Job, bool, error) {
+	response, err := e.store.GetJobs(ctx, jobstore.JobQuery{
+		Namespace:  job.Namespace,
+		ReturnAll:  true,
+		States:     []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+		Types:      []string{job.Type},
+		NamePrefix: job.Name,
+	})
+	if err != nil {
+		return models.Job{}, false, err
+	}
+	for _, existing := range response.Jobs {
+		if existing.Name == job.Name {
+			return existing, true, nil
+		}
+	}
+	return models.Job{}, false, nil
+}
+
+// RollbackJob rolls a long-running job back to one of its previous versions. The specification
+// of the previous version becomes a new version of the job, which is rolled out following the
+// job's update strategy.
+func (e *BaseEndpoint) RollbackJob(ctx context.Context, request *RollbackJobRequest) (RollbackJobResponse, error) {
+	job, err := e.store.GetJob(ctx, request.JobID)
+	if err != nil {
+		return RollbackJobResponse{}, err
+	}
+	if !job.IsLongRunning() {
+		return RollbackJobResponse{}, fmt.Errorf("job %s of type %s cannot be rolled back", job.ID, job.Type)
+	}
+	if request.Version >= job.Version {
+		return RollbackJobResponse{}, fmt.Errorf(
+			"cannot roll back job %s to version %d as it is at version %d", job.ID, request.Version, job.Version)
+	}
+	previous, err := e.store.GetJobVersion(ctx, job.ID, request.Version)
+	if err != nil {
+		return RollbackJobResponse{}, err
+	}
+	if err = e.store.UpdateJob(ctx, previous, JobRolledBackEvent(request.Version)); err != nil {
+		return RollbackJobResponse{}, err
+	}
+	eval, err := e.enqueueJobUpdate(ctx, job)
+	if err != nil {
+		return RollbackJobResponse{}, err
+	}
+	return RollbackJobResponse{
+		JobVersion:   job.Version + 1,
+		EvaluationID: eval.ID,
+	}, nil
+}
+
+// enqueueJobUpdate creates and enqueues an evaluation to roll out a new version of a job
+func (e *BaseEndpoint) enqueueJobUpdate(ctx context.Context, job models.Job) (*models.Evaluation, error) {
+	now := time.Now().UTC().UnixNano()
+	eval := &models.Evaluation{
+		ID:          uuid.NewString(),
+		JobID:       job.ID,
+		TriggeredBy: models.EvalTriggerJobUpdate,
+		Type:        job.Type,
+		Status:      models.EvalStatusPending,
+		CreateTime:  now,
+		ModifyTime:  now,
+	}
+	if err := e.store.CreateEvaluation(ctx, *eval); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to save evaluation for update of job %s", job.ID)
+		return nil, err
+	}
+	if err := e.evaluationBroker.Enqueue(eval); err != nil {
+		return nil, err
+	}
+	return eval, nil
+}
+
+// EvaluateNodeJobs enqueues evaluations for the long-running jobs affected by a change to a
+// node, such as the node being drained or uncordoned, so that the schedulers can move or
+// recreate their executions. Service jobs are only evaluated if they have executions running on
+// the node, while daemon jobs are always evaluated as they may need an execution on the node.
+func (e *BaseEndpoint) EvaluateNodeJobs(ctx context.Context, nodeID string) error {
+	response, err := e.store.GetJobs(ctx, jobstore.JobQuery{
+		ReturnAll: true,
+		States:    []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+		Types:     []string{models.JobTypeService, models.JobTypeDaemon},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range response.Jobs {
+		if job.Type == models.JobTypeService {
+			executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+			if err != nil {
+				return err
+			}
+			onNode := false
+			for i := range executions {
+				if executions[i].NodeID == nodeID && !executions[i].IsTerminalState() {
+					onNode = true
+					break
+				}
+			}
+			if !onNode {
+				continue
+			}
+		}
+
+		now := time.Now().UTC().UnixNano()
+		eval := &models.Evaluation{
+			ID:          uuid.NewString(),
+			JobID:       job.ID,
+			TriggeredBy: models.EvalTriggerNodeUpdate,
+			Type:        job.Type,
+			Status:      models.EvalStatusPending,
+			CreateTime:  now,
+			ModifyTime:  now,
+		}
+		if err = e.store.CreateEvaluation(ctx, *eval); err != nil {
+			return fmt.Errorf("failed to save evaluation for job %s after node %s update: %w", job.ID, nodeID, err)
+		}
+		if err = e.evaluationBroker.Enqueue(eval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *BaseEndpoint) ReadLogs(ctx context.Context, request ReadLogsRequest) (
 	<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
@@ -239,12 +473,6 @@ func (e *BaseEndpoint) ReadLogs(ctx context.Context, request ReadLogsRequest) (
 		return nil, fmt.Errorf("unable to find execution %s in job %s", request.ExecutionID, request.JobID)
 	}
 
-	if execution.IsTerminalState() {
-		streamer := logstream.NewCompletedStreamer(logstream.CompletedStreamerParams{
-			Execution: execution,
-		})
-		return streamer.Stream(ctx), nil
-	}
 	req := compute.ExecutionLogsRequest{
 		RoutingMetadata: compute.RoutingMetadata{
 			SourcePeerID: e.id,
@@ -253,9 +481,112 @@ func (e *BaseEndpoint) ReadLogs(ctx context.Context, request ReadLogsRequest) (
 		ExecutionID: execution.ID,
 		Tail:        request.Tail,
 		Follow:      request.Follow,
+		Offset:      request.Offset,
+		TailLines:   request.TailLines,
+		Since:       request.Since,
+	}
+	if !execution.IsTerminalState() {
+		return e.computeProxy.ExecutionLogs(ctx, req)
+	}
+
+	// the logs of completed executions are read from the compute node that persisted them,
+	// falling back to the output truncated in the execution if they are not available.
+	fallback := logstream.NewCompletedStreamer(logstream.CompletedStreamerParams{
+		Execution: execution,
+		Offset:    request.Offset,
+		TailLines: request.TailLines,
+	})
+	persisted, err := e.computeProxy.ExecutionLogs(ctx, req)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msgf("persisted logs of execution %s are not available", execution.ID)
+		return fallback.Stream(ctx), nil
+	}
+	return streamWithFallback(ctx, persisted, fallback), nil
+}
+
+// streamWithFallback forwards the logs, unless the first result is an error or does not arrive
+// within persistedLogsTimeout, in which case the logs of the fallback streamer are forwarded instead.
+func streamWithFallback(ctx context.Context, logs <-chan *concurrency.AsyncResult[models.ExecutionLog],
+	fallback logstream.Streamer) <-chan *concurrency.AsyncResult[models.ExecutionLog] {
+	ch := make(chan *concurrency.AsyncResult[models.ExecutionLog])
+	go func() {
+		defer close(ch)
+		timer := time.NewTimer(persistedLogsTimeout)
+		defer timer.Stop()
+
+		var first *concurrency.AsyncResult[models.ExecutionLog]
+		more := false
+		select {
+		case <-ctx.Done():
+			return
+		case first, more = <-logs:
+		case <-timer.C:
+			log.Ctx(ctx).Debug().Msg("timed out waiting for persisted execution logs")
+			logs = fallback.Stream(ctx)
+		}
+		if more && first.Err != nil {
+			log.Ctx(ctx).Debug().Err(first.Err).Msg("persisted execution logs are not available")
+			logs = fallback.Stream(ctx)
+		} else if more {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- first:
+			}
+		}
+		for result := range logs {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- result:
+			}
+		}
+	}()
+	return ch
+}
+
+// Exec runs a command inside a running execution of a job, on the compute node running it.
+func (e *BaseEndpoint) Exec(ctx context.Context, request ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID: request.JobID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var execution *models.Execution
+	for i, exec := range executions {
+		if exec.ComputeState.StateType != models.ExecutionStateBidAccepted || exec.IsTerminalDesiredState() {
+			continue
+		}
+		if exec.ID == request.ExecutionID {
+			execution = &executions[i]
+			break
+		}
+		if request.ExecutionID == "" && (execution == nil || exec.ModifyTime > execution.ModifyTime) {
+			execution = &executions[i]
+		}
+	}
+	if execution == nil {
+		if request.ExecutionID != "" {
+			return nil, fmt.Errorf("execution %s of job %s is not running", request.ExecutionID, request.JobID)
+		}
+		return nil, fmt.Errorf("no running executions found for job %s", request.JobID)
 	}
 
-	return e.computeProxy.ExecutionLogs(ctx, req)
+	return e.computeProxy.Exec(ctx, compute.ExecRequest{
+		RoutingMetadata: compute.RoutingMetadata{
+			SourcePeerID: e.id,
+			TargetPeerID: execution.NodeID,
+		},
+		ExecutionID: execution.ID,
+		Command:     request.Command,
+		TTY:         request.TTY,
+		Stdin:       request.Stdin,
+		Size:        request.Size,
+		Input:       request.Input,
+	})
 }
 
 // GetResults returns the results of a job
@@ -296,3 +627,22 @@ func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsReques
 		Results: results,
 	}, nil
 }
+
+// checkSecrets returns an error if the tasks of the job reference secrets that do not exist in
+// the namespace of the job, so that the job fails on submission rather than when it is scheduled.
+func (e *BaseEndpoint) checkSecrets(ctx context.Context, job *models.Job) error {
+	for _, task := range job.Tasks {
+		if len(task.Secrets) == 0 {
+			continue
+		}
+		if e.secretStore == nil {
+			return errors.New("secrets are not supported by this orchestrator")
+		}
+		for _, reference := range task.Secrets {
+			if _, err := e.secretStore.Get(ctx, job.Namespace, reference.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
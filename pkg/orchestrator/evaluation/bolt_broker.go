@@ -0,0 +1,122 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// BucketEvaluationBroker is the bolt bucket holding the evaluations known to the broker,
+// keyed by evaluation ID.
+const BucketEvaluationBroker = "evaluation_broker"
+
+// compile-time check to ensure type implements the models.EvaluationBroker interface
+var _ orchestrator.EvaluationBroker = &BoltBroker{}
+
+type BoltBrokerParams struct {
+	// Database is the bolt database to persist the evaluations in, which is
+	// expected to be shared with the jobstore.
+	Database             *bolt.DB
+	VisibilityTimeout    time.Duration
+	InitialRetryDelay    time.Duration
+	SubsequentRetryDelay time.Duration
+	MaxReceiveCount      int
+}
+
+// BoltBroker is an evaluation broker that persists the enqueued and inflight evaluations
+// in BoltDB so that they survive restarts of the orchestrator.
+type BoltBroker struct {
+	*persistentBroker
+}
+
+// NewBoltBroker creates a new evaluation broker persisted in the provided bolt database.
+func NewBoltBroker(params BoltBrokerParams) (*BoltBroker, error) {
+	if params.Database == nil {
+		return nil, errors.New("database is required")
+	}
+	err := params.Database.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketEvaluationBroker))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evaluation broker bucket: %w", err)
+	}
+	broker, err := newPersistentBroker(boltRecords{database: params.Database}, InMemoryBrokerParams{
+		VisibilityTimeout:    params.VisibilityTimeout,
+		InitialRetryDelay:    params.InitialRetryDelay,
+		SubsequentRetryDelay: params.SubsequentRetryDelay,
+		MaxReceiveCount:      params.MaxReceiveCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltBroker{persistentBroker: broker}, nil
+}
+
+// boltRecords persists the records of the broker in a bolt bucket
+type boltRecords struct {
+	database *bolt.DB
+}
+
+func (r boltRecords) update(evalID string, mutate func(record *brokerRecord, exists bool) recordChange) error {
+	return r.database.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketEvaluationBroker))
+		var record brokerRecord
+		data := bkt.Get([]byte(evalID))
+		if data != nil {
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+		}
+		change := mutate(&record, data != nil)
+		return applyBoltChange(bkt, []byte(evalID), record, change)
+	})
+}
+
+func (r boltRecords) updateAll(mutate func(record *brokerRecord) recordChange) error {
+	return r.database.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketEvaluationBroker))
+		// the bucket cannot be modified while iterating over it, so the records are read first
+		var keys [][]byte
+		var records []brokerRecord
+		err := bkt.ForEach(func(k, v []byte) error {
+			var record brokerRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			keys = append(keys, append([]byte(nil), k...))
+			records = append(records, record)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			change := mutate(&records[i])
+			if err = applyBoltChange(bkt, keys[i], records[i], change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func applyBoltChange(bkt *bolt.Bucket, key []byte, record brokerRecord, change recordChange) error {
+	switch change {
+	case recordPut:
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(key, data)
+	case recordDelete:
+		return bkt.Delete(key)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,223 @@
+package evaluation
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// compile-time check to ensure type implements the models.EvaluationBroker interface
+var _ orchestrator.EvaluationBroker = &persistentBroker{}
+
+// brokerRecord is the persisted state of an evaluation in the broker
+type brokerRecord struct {
+	Evaluation *models.Evaluation `json:"Evaluation"`
+	// ReceiptHandle is the receipt handle of the evaluation if it is inflight
+	ReceiptHandle string `json:"ReceiptHandle,omitempty"`
+	// ReceiveCount is the number of times the evaluation was dequeued, across restarts
+	ReceiveCount int `json:"ReceiveCount"`
+	// Requeue is true if the evaluation was re-enqueued while inflight, and
+	// should be enqueued again once Ack'd
+	Requeue bool `json:"Requeue,omitempty"`
+}
+
+// recordChange is how a mutation changed the record of an evaluation
+type recordChange int
+
+const (
+	recordUnchanged recordChange = iota
+	recordPut
+	recordDelete
+)
+
+// brokerRecords persists the records of the evaluations known to a broker
+type brokerRecords interface {
+	// update applies the mutation to the record of the evaluation in a single
+	// transaction, and persists the change it returns.
+	update(evalID string, mutate func(record *brokerRecord, exists bool) recordChange) error
+	// updateAll applies the mutation to all records in a single transaction.
+	updateAll(mutate func(record *brokerRecord) recordChange) error
+}
+
+// persistentBroker is an evaluation broker that persists the enqueued and inflight
+// evaluations so that they survive restarts of the orchestrator. The queueing itself is
+// delegated to an InMemoryBroker, which provides the at-least-once delivery and the
+// single inflight evaluation per job guarantees. When the broker is enabled, the
+// persisted evaluations are replayed into the in-memory broker, preserving their
+// WaitUntil times. Receipt handles of inflight evaluations are invalidated on replay.
+type persistentBroker struct {
+	records         brokerRecords
+	broker          *InMemoryBroker
+	maxReceiveCount int
+}
+
+func newPersistentBroker(records brokerRecords, params InMemoryBrokerParams) (*persistentBroker, error) {
+	broker, err := NewInMemoryBroker(params)
+	if err != nil {
+		return nil, err
+	}
+	return &persistentBroker{
+		records:         records,
+		broker:          broker,
+		maxReceiveCount: params.MaxReceiveCount,
+	}, nil
+}
+
+// Enabled is used to check if the broker is enabled.
+func (b *persistentBroker) Enabled() bool {
+	return b.broker.Enabled()
+}
+
+// SetEnabled is used to control if the broker is enabled. Enabling the broker
+// replays the persisted evaluations, while disabling it keeps them persisted.
+func (b *persistentBroker) SetEnabled(enabled bool) {
+	prevEnabled := b.broker.Enabled()
+	b.broker.SetEnabled(enabled)
+	if !prevEnabled && enabled {
+		if err := b.restore(); err != nil {
+			log.Error().Err(err).Msg("failed to restore persisted evaluations")
+		}
+	}
+}
+
+// restore re-enqueues the persisted evaluations into the in-memory broker.
+// Evaluations that reached the delivery limit are dropped.
+func (b *persistentBroker) restore() error {
+	var evals []*models.Evaluation
+	err := b.records.updateAll(func(record *brokerRecord) recordChange {
+		if record.ReceiveCount >= b.maxReceiveCount {
+			log.Warn().Msgf("dropping evaluation %s for job %s as it has been dequeued %d times",
+				record.Evaluation.ID, record.Evaluation.JobID, record.ReceiveCount)
+			return recordDelete
+		}
+		// the receipt handle of an evaluation that was inflight is no longer valid
+		record.ReceiptHandle = ""
+		record.Requeue = false
+		evals = append(evals, record.Evaluation)
+		return recordPut
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, eval := range evals {
+		log.Debug().Msgf("restoring evaluation %s for job %s", eval.ID, eval.JobID)
+		if err = b.broker.Enqueue(eval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *persistentBroker) Enqueue(evaluation *models.Evaluation) error {
+	if !b.broker.Enabled() {
+		return b.broker.Enqueue(evaluation)
+	}
+	if err := b.persistEnqueued(evaluation, ""); err != nil {
+		return err
+	}
+	return b.broker.Enqueue(evaluation)
+}
+
+func (b *persistentBroker) EnqueueAll(evals map[*models.Evaluation]string) error {
+	if !b.broker.Enabled() {
+		return b.broker.EnqueueAll(evals)
+	}
+	for eval, receiptHandle := range evals {
+		if err := b.persistEnqueued(eval, receiptHandle); err != nil {
+			return err
+		}
+	}
+	return b.broker.EnqueueAll(evals)
+}
+
+// persistEnqueued persists an enqueued evaluation. If the evaluation is inflight with
+// a matching receipt handle, it is marked to be requeued once Ack'd, which is the
+// same behaviour as the in-memory broker.
+func (b *persistentBroker) persistEnqueued(eval *models.Evaluation, receiptHandle string) error {
+	return b.records.update(eval.ID, func(record *brokerRecord, exists bool) recordChange {
+		if !exists {
+			record.Evaluation = eval.Copy()
+			return recordPut
+		}
+		if receiptHandle != "" && record.ReceiptHandle == receiptHandle {
+			record.Requeue = true
+			return recordPut
+		}
+		return recordUnchanged
+	})
+}
+
+func (b *persistentBroker) Dequeue(types []string, timeout time.Duration) (*models.Evaluation, string, error) {
+	eval, receiptHandle, err := b.broker.Dequeue(types, timeout)
+	if err != nil || eval == nil {
+		return eval, receiptHandle, err
+	}
+	err = b.records.update(eval.ID, func(record *brokerRecord, exists bool) recordChange {
+		if !exists {
+			record.Evaluation = eval.Copy()
+		}
+		record.ReceiptHandle = receiptHandle
+		record.ReceiveCount++
+		return recordPut
+	})
+	if err != nil {
+		// release the evaluation so that it is delivered again
+		if nackErr := b.broker.Nack(eval.ID, receiptHandle); nackErr != nil {
+			log.Error().Err(nackErr).Msgf("failed to nack evaluation %s", eval.ID)
+		}
+		return nil, "", err
+	}
+	return eval, receiptHandle, nil
+}
+
+func (b *persistentBroker) Inflight(evaluationID string) (string, bool) {
+	return b.broker.Inflight(evaluationID)
+}
+
+func (b *persistentBroker) InflightExtend(evaluationID, receiptHandle string) error {
+	return b.broker.InflightExtend(evaluationID, receiptHandle)
+}
+
+func (b *persistentBroker) Ack(evalID string, receiptHandle string) error {
+	if err := b.broker.Ack(evalID, receiptHandle); err != nil {
+		return err
+	}
+	return b.records.update(evalID, func(record *brokerRecord, exists bool) recordChange {
+		if !exists {
+			return recordUnchanged
+		}
+		if !record.Requeue {
+			return recordDelete
+		}
+		// the evaluation was re-enqueued by the in-memory broker after the Ack
+		record.ReceiptHandle = ""
+		record.Requeue = false
+		record.ReceiveCount = 0
+		return recordPut
+	})
+}
+
+func (b *persistentBroker) Nack(evalID string, receiptHandle string) error {
+	if err := b.broker.Nack(evalID, receiptHandle); err != nil {
+		return err
+	}
+	return b.records.update(evalID, func(record *brokerRecord, exists bool) recordChange {
+		if !exists {
+			return recordUnchanged
+		}
+		record.Evaluation.WaitUntil = time.Now().Add(
+			b.broker.nackReenqueueDelay(record.Evaluation, record.ReceiveCount)).UTC()
+		record.ReceiptHandle = ""
+		record.Requeue = false
+		return recordPut
+	})
+}
+
+// Stats is used to query the state of the broker
+func (b *persistentBroker) Stats() *BrokerStats {
+	return b.broker.Stats()
+}
@@ -0,0 +1,195 @@
+//go:build unit || !integration
+
+package evaluation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+	_ "modernc.org/sqlite"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// PersistentBrokerTestSuite runs the same tests against the brokers persisting
+// evaluations in the different databases of the jobstore
+type PersistentBrokerTestSuite struct {
+	suite.Suite
+	// openDatabase opens the database of a test, and returns a function creating
+	// brokers on top of it
+	openDatabase func() func() *persistentBroker
+	newBroker    func() *persistentBroker
+	broker       *persistentBroker
+}
+
+func TestBoltBrokerTestSuite(t *testing.T) {
+	s := &PersistentBrokerTestSuite{}
+	s.openDatabase = func() func() *persistentBroker {
+		database, err := bolt.Open(filepath.Join(s.T().TempDir(), "broker.db"), 0600, nil)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { s.NoError(database.Close()) })
+		return func() *persistentBroker {
+			broker, err := NewBoltBroker(BoltBrokerParams{
+				Database:             database,
+				VisibilityTimeout:    defaultBrokerParams.VisibilityTimeout,
+				InitialRetryDelay:    defaultBrokerParams.InitialRetryDelay,
+				SubsequentRetryDelay: defaultBrokerParams.SubsequentRetryDelay,
+				MaxReceiveCount:      defaultBrokerParams.MaxReceiveCount,
+			})
+			s.Require().NoError(err)
+			return broker.persistentBroker
+		}
+	}
+	suite.Run(t, s)
+}
+
+func TestSQLBrokerTestSuite(t *testing.T) {
+	s := &PersistentBrokerTestSuite{}
+	s.openDatabase = func() func() *persistentBroker {
+		ctx := context.Background()
+		jobStore, err := sqljobstore.NewSQLJobStore(ctx, sqljobstore.DriverSQLite, ":memory:")
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { s.NoError(jobStore.Close(ctx)) })
+		return func() *persistentBroker {
+			broker, err := NewSQLBroker(ctx, SQLBrokerParams{
+				Database:             jobStore.Database(),
+				VisibilityTimeout:    defaultBrokerParams.VisibilityTimeout,
+				InitialRetryDelay:    defaultBrokerParams.InitialRetryDelay,
+				SubsequentRetryDelay: defaultBrokerParams.SubsequentRetryDelay,
+				MaxReceiveCount:      defaultBrokerParams.MaxReceiveCount,
+			})
+			s.Require().NoError(err)
+			return broker.persistentBroker
+		}
+	}
+	suite.Run(t, s)
+}
+
+func (s *PersistentBrokerTestSuite) SetupTest() {
+	s.newBroker = s.openDatabase()
+	s.broker = s.newBroker()
+	s.broker.SetEnabled(true)
+}
+
+func (s *PersistentBrokerTestSuite) TearDownTest() {
+	s.broker.SetEnabled(false)
+}
+
+// restart simulates a restart of the orchestrator by disabling the current broker
+// and creating a new one on top of the same database
+func (s *PersistentBrokerTestSuite) restart() {
+	s.broker.SetEnabled(false)
+	s.broker = s.newBroker()
+	s.broker.SetEnabled(true)
+}
+
+func (s *PersistentBrokerTestSuite) TestEnqueue_Restart_Dequeue() {
+	eval := mock.Eval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+
+	s.restart()
+	s.Equal(1, s.broker.Stats().TotalReady)
+
+	out, _, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(out)
+	s.Equal(eval.ID, out.ID)
+}
+
+func (s *PersistentBrokerTestSuite) TestAck_NotRestored() {
+	eval := mock.Eval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+	out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().NoError(s.broker.Ack(out.ID, receiptHandle))
+
+	s.restart()
+	s.True(s.broker.Stats().IsEmpty())
+}
+
+func (s *PersistentBrokerTestSuite) TestInflight_Restart_NewReceiptHandle() {
+	eval := mock.Eval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+	_, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+
+	s.restart()
+	_, ok := s.broker.Inflight(eval.ID)
+	s.False(ok, "inflight evaluations should be enqueued again after a restart")
+
+	out, newReceiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(out)
+	s.Equal(eval.ID, out.ID)
+	s.NotEqual(receiptHandle, newReceiptHandle)
+	s.Error(s.broker.Ack(eval.ID, receiptHandle), "previous receipt handle should be invalid")
+	s.NoError(s.broker.Ack(eval.ID, newReceiptHandle))
+}
+
+func (s *PersistentBrokerTestSuite) TestWaitUntil_Restored() {
+	eval := mock.Eval()
+	eval.WaitUntil = time.Now().Add(time.Hour).UTC()
+	s.Require().NoError(s.broker.Enqueue(eval))
+
+	s.restart()
+	stats := s.broker.Stats()
+	s.Equal(1, stats.TotalWaiting)
+	s.Equal(0, stats.TotalReady)
+}
+
+func (s *PersistentBrokerTestSuite) TestSerialize_DuplicateJobID_Restart() {
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	s.Require().NoError(s.broker.Enqueue(eval1))
+	s.Require().NoError(s.broker.Enqueue(eval2))
+
+	s.restart()
+	out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(out)
+
+	// only a single evaluation per job should be inflight
+	next, _, err := s.broker.Dequeue(defaultSched, 10*time.Millisecond)
+	s.Require().NoError(err)
+	s.Nil(next)
+
+	s.Require().NoError(s.broker.Ack(out.ID, receiptHandle))
+	next, _, err = s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(next)
+	s.NotEqual(out.ID, next.ID)
+}
+
+func (s *PersistentBrokerTestSuite) TestRequeue_Ack_Persisted() {
+	eval := mock.Eval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+	out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.broker.EnqueueAll(map[*models.Evaluation]string{out: receiptHandle}))
+	s.Require().NoError(s.broker.Ack(out.ID, receiptHandle))
+
+	s.restart()
+	s.Equal(1, s.broker.Stats().TotalReady)
+}
+
+func (s *PersistentBrokerTestSuite) TestDeliveryLimit_Restart() {
+	eval := mock.Eval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+	for i := 0; i < defaultBrokerParams.MaxReceiveCount; i++ {
+		out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+		s.Require().NoError(err)
+		s.Require().NotNil(out)
+		s.Require().NoError(s.broker.Nack(out.ID, receiptHandle))
+	}
+
+	s.restart()
+	s.True(s.broker.Stats().IsEmpty(), "evaluations exceeding the delivery limit should be dropped")
+}
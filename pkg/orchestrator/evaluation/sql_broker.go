@@ -0,0 +1,138 @@
+package evaluation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// compile-time check to ensure type implements the models.EvaluationBroker interface
+var _ orchestrator.EvaluationBroker = &SQLBroker{}
+
+type SQLBrokerParams struct {
+	// Database is the database of the SQL jobstore to persist the evaluations in
+	Database             *sqljobstore.Database
+	VisibilityTimeout    time.Duration
+	InitialRetryDelay    time.Duration
+	SubsequentRetryDelay time.Duration
+	MaxReceiveCount      int
+}
+
+// SQLBroker is an evaluation broker that persists the enqueued and inflight evaluations
+// in the database of the SQL jobstore so that they survive restarts of the orchestrator.
+type SQLBroker struct {
+	*persistentBroker
+}
+
+// NewSQLBroker creates a new evaluation broker persisted in the provided database.
+func NewSQLBroker(ctx context.Context, params SQLBrokerParams) (*SQLBroker, error) {
+	if params.Database == nil {
+		return nil, errors.New("database is required")
+	}
+	_, err := params.Database.Exec(ctx, `CREATE TABLE IF NOT EXISTS evaluation_broker (
+		id   TEXT PRIMARY KEY,
+		data `+params.Database.JSONType()+` NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evaluation broker table: %w", err)
+	}
+	broker, err := newPersistentBroker(sqlRecords{database: params.Database}, InMemoryBrokerParams{
+		VisibilityTimeout:    params.VisibilityTimeout,
+		InitialRetryDelay:    params.InitialRetryDelay,
+		SubsequentRetryDelay: params.SubsequentRetryDelay,
+		MaxReceiveCount:      params.MaxReceiveCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SQLBroker{persistentBroker: broker}, nil
+}
+
+// sqlRecords persists the records of the broker in a table, one row per evaluation
+type sqlRecords struct {
+	database *sqljobstore.Database
+}
+
+func (r sqlRecords) update(evalID string, mutate func(record *brokerRecord, exists bool) recordChange) error {
+	ctx := context.Background()
+	return r.database.Update(ctx, func(tx *sqljobstore.Tx) error {
+		var record brokerRecord
+		var data string
+		err := tx.QueryRow(ctx, tx.ForUpdate(`SELECT data FROM evaluation_broker WHERE id = ?`), evalID).Scan(&data)
+		exists := err == nil
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if exists {
+			if err = json.Unmarshal([]byte(data), &record); err != nil {
+				return err
+			}
+		}
+		change := mutate(&record, exists)
+		return applySQLChange(ctx, tx, evalID, record, change)
+	})
+}
+
+func (r sqlRecords) updateAll(mutate func(record *brokerRecord) recordChange) error {
+	ctx := context.Background()
+	return r.database.Update(ctx, func(tx *sqljobstore.Tx) error {
+		// all rows are read before they are changed, as a connection cannot run a
+		// statement while the rows of another one are being read
+		rows, err := tx.Query(ctx, tx.ForUpdate(`SELECT id, data FROM evaluation_broker ORDER BY id`))
+		if err != nil {
+			return err
+		}
+		var ids []string
+		var records []brokerRecord
+		for rows.Next() {
+			var id, data string
+			var record brokerRecord
+			if err = rows.Scan(&id, &data); err == nil {
+				err = json.Unmarshal([]byte(data), &record)
+			}
+			if err != nil {
+				_ = rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			records = append(records, record)
+		}
+		if err = rows.Close(); err != nil {
+			return err
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		for i := range records {
+			change := mutate(&records[i])
+			if err = applySQLChange(ctx, tx, ids[i], records[i], change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func applySQLChange(ctx context.Context, tx *sqljobstore.Tx, evalID string, record brokerRecord, change recordChange) error {
+	switch change {
+	case recordPut:
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO evaluation_broker (id, data) VALUES (?, ?)
+			ON CONFLICT (id) DO UPDATE SET data = excluded.data`, evalID, string(data))
+		return err
+	case recordDelete:
+		_, err := tx.Exec(ctx, `DELETE FROM evaluation_broker WHERE id = ?`, evalID)
+		return err
+	default:
+		return nil
+	}
+}
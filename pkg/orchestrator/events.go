@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -9,20 +11,39 @@ import (
 const (
 	EventTopicJobSubmission models.EventTopic = "Submission"
 	EventTopicJobScheduling models.EventTopic = "Scheduling"
+	EventTopicCheckpoint    models.EventTopic = "Checkpoint"
+	EventTopicHealthCheck   models.EventTopic = "HealthCheck"
+	EventTopicNetworking    models.EventTopic = "Networking"
 )
 
 const (
-	jobSubmittedMessage        = "Job submitted"
-	jobTranslatedMessage       = "Job tasks translated to new type"
-	jobStopRequestedMessage    = "Job requested to stop before completion"
-	jobExhaustedRetriesMessage = "Job failed because it has been retried too many times"
+	jobSubmittedMessage         = "Job submitted"
+	jobTranslatedMessage        = "Job tasks translated to new type"
+	jobStopRequestedMessage     = "Job requested to stop before completion"
+	jobExhaustedRetriesMessage  = "Job failed because it has been retried too many times"
+	jobWaitingMessage           = "Job is waiting for its dependencies to complete"
+	jobDependencyFailedMessage  = "Job failed because one of its dependencies did not complete"
+	jobWaitingForQuotaMessage   = "Job is waiting for its namespace to be within quota"
+	jobScheduledRunMessage      = "Scheduled run created"
+	jobScheduledSkippedMessage  = "Scheduled run skipped because previous runs are still active"
+	jobCreatedByScheduleMessage = "Job created by scheduled job"
+	jobUpdatedMessage           = "Job updated to a new version"
+	jobRolledBackMessage        = "Job rolled back to a previous version"
+	jobRevertedMessage          = "Job reverted because an execution of its new version failed"
 
 	execStoppedByJobStopMessage          = "Execution stop requested because job has been stopped"
 	execStoppedByNodeUnhealthyMessage    = "Execution stop requested because node has disappeared"
 	execStoppedByNodeRejectedMessage     = "Execution stop requested because node has been rejected"
+	execStoppedByNodeDrainMessage        = "Execution stop requested because node is being drained"
 	execStoppedByOversubscriptionMessage = "Execution stop requested because there are more executions than needed"
+	execStoppedByJobUpdateMessage        = "Execution stop requested because job has been updated"
+	execStoppedByHealthCheckMessage      = "Execution stop requested because it failed its health check"
 	execRejectedByNodeMessage            = "Node responded to execution run request"
 	execFailedMessage                    = "Execution did not complete successfully"
+	execPreemptedMessage                 = "Execution stopped because it was preempted by a higher priority execution"
+	execCheckpointedMessage              = "Execution published a checkpoint"
+	execHealthChangedMessage             = "Execution health changed"
+	execPortsPublishedMessage            = "Execution published its ports"
 )
 
 func event(topic models.EventTopic, msg string, details map[string]string) models.Event {
@@ -55,18 +76,124 @@ func JobExhaustedRetriesEvent() models.Event {
 	return event(EventTopicJobScheduling, jobExhaustedRetriesMessage, map[string]string{})
 }
 
+func JobWaitingForDependenciesEvent(pending []string) models.Event {
+	return event(EventTopicJobScheduling, jobWaitingMessage, map[string]string{
+		"PendingDependencies": strings.Join(pending, ","),
+	})
+}
+
+func JobWaitingForQuotaEvent(namespace string, reasons []string) models.Event {
+	return event(EventTopicJobScheduling, jobWaitingForQuotaMessage, map[string]string{
+		"Namespace":     namespace,
+		"QuotaExceeded": strings.Join(reasons, ","),
+	})
+}
+
+func JobDependencyFailedEvent(upstream models.Job) models.Event {
+	return event(EventTopicJobScheduling, jobDependencyFailedMessage, map[string]string{
+		"DependencyID":    upstream.ID,
+		"DependencyState": upstream.State.StateType.String(),
+	})
+}
+
+func JobScheduledRunEvent(run models.Job, scheduledTime time.Time) models.Event {
+	return event(EventTopicJobScheduling, jobScheduledRunMessage, map[string]string{
+		models.DetailsKeyScheduledRunID: run.ID,
+		models.DetailsKeyScheduledTime:  scheduledTime.Format(time.RFC3339),
+	})
+}
+
+func JobScheduledRunSkippedEvent(scheduledTime time.Time, activeRuns []string) models.Event {
+	return event(EventTopicJobScheduling, jobScheduledSkippedMessage, map[string]string{
+		models.DetailsKeyScheduledTime: scheduledTime.Format(time.RFC3339),
+		"ActiveRuns":                   strings.Join(activeRuns, ","),
+	})
+}
+
+func JobCreatedByScheduleEvent(scheduledJob models.Job, scheduledTime time.Time) models.Event {
+	return event(EventTopicJobSubmission, jobCreatedByScheduleMessage, map[string]string{
+		"ScheduledJobID":               scheduledJob.ID,
+		models.DetailsKeyScheduledTime: scheduledTime.Format(time.RFC3339),
+	})
+}
+
+func JobUpdatedEvent(previous models.Job) models.Event {
+	return event(EventTopicJobSubmission, jobUpdatedMessage, map[string]string{
+		"PreviousVersion": strconv.FormatUint(previous.Version, 10),
+	})
+}
+
+func JobRolledBackEvent(version uint64) models.Event {
+	return event(EventTopicJobSubmission, jobRolledBackMessage, map[string]string{
+		"RolledBackTo": strconv.FormatUint(version, 10),
+	})
+}
+
+func JobRevertedEvent(failedVersion uint64, version uint64) models.Event {
+	return event(EventTopicJobScheduling, jobRevertedMessage, map[string]string{
+		"FailedVersion": strconv.FormatUint(failedVersion, 10),
+		"RevertedTo":    strconv.FormatUint(version, 10),
+	})
+}
+
 func ExecStoppedByJobStopEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByJobStopMessage, map[string]string{})
 }
 
 func ExecStoppedByNodeUnhealthyEvent() models.Event {
-	return event(EventTopicJobScheduling, execStoppedByNodeUnhealthyMessage, map[string]string{})
+	return event(EventTopicJobScheduling, execStoppedByNodeUnhealthyMessage, map[string]string{
+		models.DetailsKeyStopReason: string(models.ExecutionStopReasonNodeLost),
+	})
 }
 
 func ExecStoppedByNodeRejectedEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByNodeRejectedMessage, map[string]string{})
 }
 
+func ExecStoppedByNodeDrainEvent() models.Event {
+	return event(EventTopicJobScheduling, execStoppedByNodeDrainMessage, map[string]string{
+		models.DetailsKeyStopReason: string(models.ExecutionStopReasonNodeDrained),
+	})
+}
+
+func ExecStoppedByJobUpdateEvent() models.Event {
+	return event(EventTopicJobScheduling, execStoppedByJobUpdateMessage, map[string]string{})
+}
+
+func ExecStoppedByHealthCheckEvent() models.Event {
+	return event(EventTopicJobScheduling, execStoppedByHealthCheckMessage, map[string]string{
+		models.DetailsKeyStopReason: string(models.ExecutionStopReasonHealthCheck),
+	})
+}
+
 func ExecStoppedByOversubscriptionEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByOversubscriptionMessage, map[string]string{})
 }
+
+func ExecPreemptedEvent() models.Event {
+	return event(EventTopicJobScheduling, execPreemptedMessage, map[string]string{
+		models.DetailsKeyStopReason: string(models.ExecutionStopReasonPreempted),
+	})
+}
+
+func ExecCheckpointedEvent(checkpoint *models.ExecutionCheckpoint) models.Event {
+	return event(EventTopicCheckpoint, execCheckpointedMessage, map[string]string{
+		"CheckpointTime": checkpoint.GetCreateTime().Format(time.RFC3339),
+	})
+}
+
+func ExecHealthChangedEvent(health *models.ExecutionHealth) models.Event {
+	return event(EventTopicHealthCheck, execHealthChangedMessage, map[string]string{
+		"HealthStatus":  string(health.Status),
+		"HealthMessage": health.Message,
+		"CheckTime":     health.GetCheckTime().Format(time.RFC3339),
+	})
+}
+
+func ExecPortsPublishedEvent(ports []*models.ExecutionPort) models.Event {
+	details := make(map[string]string, len(ports))
+	for _, port := range ports {
+		details[port.Name] = port.Address()
+	}
+	return event(EventTopicNetworking, execPortsPublishedMessage, details)
+}
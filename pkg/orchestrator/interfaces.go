@@ -102,8 +102,8 @@ type NodeRanker interface {
 
 // NodeSelector selects nodes based on their suitability to execute a job.
 type NodeSelector interface {
-	// AllNodes returns all nodes in the network.
-	AllNodes(ctx context.Context) ([]models.NodeInfo, error)
+	// AllNodes returns the state of all nodes in the network.
+	AllNodes(ctx context.Context) ([]models.NodeState, error)
 
 	// AllMatchingNodes returns all nodes that match the job constrains and selection criteria.
 	AllMatchingNodes(ctx context.Context, job *models.Job, constraints *NodeSelectionConstraints) ([]models.NodeInfo, error)
@@ -116,4 +116,7 @@ type NodeSelector interface {
 type RetryStrategy interface {
 	// ShouldRetry returns true if the job can be retried.
 	ShouldRetry(ctx context.Context, request RetryRequest) bool
+
+	// RetryDelay returns how long to wait after the last failure before retrying the job.
+	RetryDelay(ctx context.Context, request RetryRequest) time.Duration
 }
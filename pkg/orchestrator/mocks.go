@@ -383,10 +383,10 @@ func (mr *MockNodeSelectorMockRecorder) AllMatchingNodes(ctx, job, constraints a
 }
 
 // AllNodes mocks base method.
-func (m *MockNodeSelector) AllNodes(ctx context.Context) ([]models.NodeInfo, error) {
+func (m *MockNodeSelector) AllNodes(ctx context.Context) ([]models.NodeState, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AllNodes", ctx)
-	ret0, _ := ret[0].([]models.NodeInfo)
+	ret0, _ := ret[0].([]models.NodeState)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -435,6 +435,20 @@ func (m *MockRetryStrategy) EXPECT() *MockRetryStrategyMockRecorder {
 	return m.recorder
 }
 
+// RetryDelay mocks base method.
+func (m *MockRetryStrategy) RetryDelay(ctx context.Context, request RetryRequest) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDelay", ctx, request)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// RetryDelay indicates an expected call of RetryDelay.
+func (mr *MockRetryStrategyMockRecorder) RetryDelay(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDelay", reflect.TypeOf((*MockRetryStrategy)(nil).RetryDelay), ctx, request)
+}
+
 // ShouldRetry mocks base method.
 func (m *MockRetryStrategy) ShouldRetry(ctx context.Context, request RetryRequest) bool {
 	m.ctrl.T.Helper()
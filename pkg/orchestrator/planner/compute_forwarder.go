@@ -2,10 +2,14 @@ package planner
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/secret"
+	"github.com/benbjohnson/clock"
 	"github.com/rs/zerolog/log"
 )
 
@@ -13,19 +17,31 @@ type ComputeForwarder struct {
 	id             string
 	computeService compute.Endpoint
 	jobStore       jobstore.Store
+	secretStore    secret.Store
+	clock          clock.Clock
 }
 
 type ComputeForwarderParams struct {
 	ID             string
 	ComputeService compute.Endpoint
 	JobStore       jobstore.Store
+	// SecretStore holds the secrets referenced by tasks, which are sent to the compute
+	// node when the execution is started. Optional if jobs do not reference secrets.
+	SecretStore secret.Store
+	// Clock is the clock used to record when executions start. Defaults to the system clock.
+	Clock clock.Clock
 }
 
 func NewComputeForwarder(params ComputeForwarderParams) *ComputeForwarder {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
 	return &ComputeForwarder{
 		id:             params.ID,
 		computeService: params.ComputeService,
 		jobStore:       params.JobStore,
+		secretStore:    params.SecretStore,
+		clock:          params.Clock,
 	}
 }
 
@@ -46,7 +62,7 @@ func (s *ComputeForwarder) doProcess(ctx context.Context, plan *models.Plan) {
 	//  the total number of concurrent notifications across plans to avoid overloading the network.
 	for _, exec := range plan.NewExecutions {
 		waitForApproval := exec.DesiredState.StateType == models.ExecutionDesiredStatePending
-		s.doNotifyAskForBid(ctx, exec, waitForApproval)
+		s.doNotifyAskForBid(ctx, plan.Job, exec, waitForApproval)
 	}
 	for _, u := range plan.UpdatedExecutions {
 		observedState := u.Execution.ComputeState.StateType
@@ -54,14 +70,14 @@ func (s *ComputeForwarder) doProcess(ctx context.Context, plan *models.Plan) {
 		switch u.DesiredState {
 		case models.ExecutionDesiredStatePending:
 			if observedState == models.ExecutionStateNew {
-				s.doNotifyAskForBid(ctx, u.Execution, true)
+				s.doNotifyAskForBid(ctx, plan.Job, u.Execution, true)
 			}
 		case models.ExecutionDesiredStateRunning:
 			if observedState == models.ExecutionStateAskForBidAccepted {
-				s.doNotifyBidAccepted(ctx, u.Execution)
+				s.doNotifyBidAccepted(ctx, plan.Job, u.Execution)
 			}
 			if observedState == models.ExecutionStateNew {
-				s.doNotifyAskForBid(ctx, u.Execution, false)
+				s.doNotifyAskForBid(ctx, plan.Job, u.Execution, false)
 			}
 		case models.ExecutionDesiredStateStopped:
 			if observedState == models.ExecutionStateAskForBidAccepted {
@@ -74,7 +90,8 @@ func (s *ComputeForwarder) doProcess(ctx context.Context, plan *models.Plan) {
 }
 
 // doNotifyAskForBid notifies the target node to bid for the given execution.
-func (s *ComputeForwarder) doNotifyAskForBid(ctx context.Context, execution *models.Execution, waitForApproval bool) {
+func (s *ComputeForwarder) doNotifyAskForBid(
+	ctx context.Context, job *models.Job, execution *models.Execution, waitForApproval bool) {
 	log.Ctx(ctx).Debug().Msgf("Requester node %s asking node %s to bid with execution %s",
 		s.id, execution.NodeID, execution.ID)
 
@@ -87,6 +104,15 @@ func (s *ComputeForwarder) doNotifyAskForBid(ctx context.Context, execution *mod
 			TargetPeerID: execution.NodeID,
 		},
 	}
+	if !waitForApproval {
+		// the execution starts right away, so it needs its secrets
+		secrets, err := s.resolveSecrets(ctx, job)
+		if err != nil {
+			s.failExecution(ctx, execution, err, models.ExecutionStateNew)
+			return
+		}
+		request.Secrets = secrets
+	}
 	_, err := s.computeService.AskForBid(ctx, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msgf("Failed to notify node %s to bid for execution %s",
@@ -103,16 +129,22 @@ func (s *ComputeForwarder) doNotifyAskForBid(ctx context.Context, execution *mod
 }
 
 // doNotifyBidAccepted notifies the target node that the bid was accepted.
-func (s *ComputeForwarder) doNotifyBidAccepted(ctx context.Context, execution *models.Execution) {
+func (s *ComputeForwarder) doNotifyBidAccepted(ctx context.Context, job *models.Job, execution *models.Execution) {
 	log.Ctx(ctx).Debug().Msgf("Requester node %s responding with BidAccepted for bid: %s", s.id, execution.ID)
+	secrets, err := s.resolveSecrets(ctx, job)
+	if err != nil {
+		s.failExecution(ctx, execution, err, models.ExecutionStateAskForBidAccepted)
+		return
+	}
 	request := compute.BidAcceptedRequest{
 		ExecutionID: execution.ID,
+		Secrets:     secrets,
 		RoutingMetadata: compute.RoutingMetadata{
 			SourcePeerID: s.id,
 			TargetPeerID: execution.NodeID,
 		},
 	}
-	_, err := s.computeService.BidAccepted(ctx, request)
+	_, err = s.computeService.BidAccepted(ctx, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msgf("Failed to notify node %s that bid %s was accepted",
 			execution.NodeID, execution.ID)
@@ -167,16 +199,60 @@ func (s *ComputeForwarder) notifyCancel(ctx context.Context, message string, exe
 	s.updateExecutionState(ctx, execution, models.ExecutionStateCancelled)
 }
 
-// updateExecutionState updates the execution state in the job store.
+// resolveSecrets returns the values of the secrets referenced by the task of the job,
+// which are only sent to the node that runs the execution.
+func (s *ComputeForwarder) resolveSecrets(ctx context.Context, job *models.Job) (compute.SecretValues, error) {
+	if job == nil {
+		return nil, nil
+	}
+	references := job.Task().Secrets
+	if len(references) == 0 {
+		return nil, nil
+	}
+	if s.secretStore == nil {
+		return nil, errors.New("secrets are not supported by this requester")
+	}
+	values, err := secret.Resolve(ctx, s.secretStore, job.Namespace, references)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets of execution: %w", err)
+	}
+	return values, nil
+}
+
+// failExecution marks the execution as failed without notifying the compute node.
+func (s *ComputeForwarder) failExecution(ctx context.Context, execution *models.Execution, err error,
+	expectedStates ...models.ExecutionStateType) {
+	log.Ctx(ctx).Error().Err(err).Msgf("Failed to start execution %s on node %s", execution.ID, execution.NodeID)
+	updateErr := s.jobStore.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStateFailed).WithMessage(err.Error()),
+		},
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: expectedStates,
+		},
+	})
+	if updateErr != nil {
+		log.Ctx(ctx).Error().Err(updateErr).Msgf("Failed to update execution %s to state %s",
+			execution.ID, models.ExecutionStateFailed)
+	}
+}
+
+// updateExecutionState updates the execution state in the job store, and records
+// when executions whose bid is accepted start running.
 func (s *ComputeForwarder) updateExecutionState(ctx context.Context, execution *models.Execution,
 	newState models.ExecutionStateType, expectedStates ...models.ExecutionStateType) {
+	newValues := models.Execution{
+		ComputeState: models.State[models.ExecutionStateType]{
+			StateType: newState,
+		},
+	}
+	if newState == models.ExecutionStateBidAccepted {
+		newValues.StartTime = s.clock.Now().UTC().UnixNano()
+	}
 	err := s.jobStore.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
 		ExecutionID: execution.ID,
-		NewValues: models.Execution{
-			ComputeState: models.State[models.ExecutionStateType]{
-				StateType: newState,
-			},
-		},
+		NewValues:   newValues,
 		Condition: jobstore.UpdateExecutionCondition{
 			ExpectedStates: expectedStates,
 		},
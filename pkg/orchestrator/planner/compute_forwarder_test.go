@@ -12,6 +12,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 )
@@ -22,6 +23,7 @@ type ComputeForwarderSuite struct {
 	ctrl             *gomock.Controller
 	computeService   *compute.MockEndpoint
 	jobStore         *jobstore.MockStore
+	clock            *clock.Mock
 	nodeID           string
 	plannerErr       error
 	computeForwarder *ComputeForwarder
@@ -36,6 +38,8 @@ func (suite *ComputeForwarderSuite) SetupTest() {
 	suite.ctrl = gomock.NewController(suite.T())
 	suite.computeService = compute.NewMockEndpoint(suite.ctrl)
 	suite.jobStore = jobstore.NewMockStore(suite.ctrl)
+	suite.clock = clock.NewMock()
+	suite.clock.Set(time.Now())
 	suite.nodeID = "test-node"
 	suite.plannerErr = errors.New("planner error")
 
@@ -43,6 +47,7 @@ func (suite *ComputeForwarderSuite) SetupTest() {
 		ID:             suite.nodeID,
 		ComputeService: suite.computeService,
 		JobStore:       suite.jobStore,
+		Clock:          suite.clock,
 	}
 
 	suite.computeForwarder = NewComputeForwarder(params)
@@ -147,10 +152,15 @@ func (suite *ComputeForwarderSuite) mockUpdateExecution(plan *models.Plan, id st
 
 }
 func (suite *ComputeForwarderSuite) assertStateUpdated(execution *models.Execution, newState models.ExecutionStateType, expectedState models.ExecutionStateType) {
-	matcher := NewUpdateExecutionMatcher(suite.T(), execution, UpdateExecutionMatcherParams{
+	params := UpdateExecutionMatcherParams{
 		NewState:      newState,
 		ExpectedState: expectedState,
-	})
+	}
+	// executions whose bid is accepted start running
+	if newState == models.ExecutionStateBidAccepted {
+		params.NewStartTime = suite.clock.Now().UTC().UnixNano()
+	}
+	matcher := NewUpdateExecutionMatcher(suite.T(), execution, params)
 	suite.jobStore.EXPECT().UpdateExecution(suite.ctx, matcher).Times(1)
 }
 
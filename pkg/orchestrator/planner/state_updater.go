@@ -45,6 +45,7 @@ func (s *StateUpdater) Process(ctx context.Context, plan *models.Plan) error {
 					StateType: u.DesiredState,
 					Message:   u.Event.Message,
 				},
+				StopReason: u.Event.StopReason(),
 			},
 			Condition: jobstore.UpdateExecutionCondition{
 				ExpectedRevision: u.Execution.Revision,
@@ -58,6 +58,24 @@ func (suite *StateUpdaterSuite) TestStateUpdater_Process_UpdateExecutions_Succes
 	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
 }
 
+func (suite *StateUpdaterSuite) TestStateUpdater_Process_UpdateExecutions_StopReason() {
+	plan := mock.Plan()
+	execution := mock.ExecutionForJob(plan.Job)
+	plan.AppendStoppedExecution(execution, models.Event{
+		Message: "node lost",
+		Details: map[string]string{models.DetailsKeyStopReason: string(models.ExecutionStopReasonNodeLost)},
+	})
+
+	// the reason the execution is stopped for is recorded on the execution
+	suite.mockStore.EXPECT().UpdateExecution(suite.ctx, NewUpdateExecutionMatcher(suite.T(), execution, UpdateExecutionMatcherParams{
+		NewDesiredState:     models.ExecutionDesiredStateStopped,
+		DesiredStateComment: "node lost",
+		ExpectedRevision:    execution.Revision,
+		NewStopReason:       models.ExecutionStopReasonNodeLost,
+	})).Times(1)
+	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
+}
+
 func (suite *StateUpdaterSuite) TestStateUpdater_Process_UpdateJobState_Success() {
 	plan := mock.Plan()
 	plan.DesiredJobState = models.JobStateTypeCompleted
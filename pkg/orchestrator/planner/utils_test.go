@@ -51,6 +51,8 @@ type UpdateExecutionMatcher struct {
 	desiredStateComment string
 	expectedState       models.ExecutionStateType
 	expectedRevision    uint64
+	newStartTime        int64
+	newStopReason       models.ExecutionStopReason
 }
 
 type UpdateExecutionMatcherParams struct {
@@ -60,6 +62,8 @@ type UpdateExecutionMatcherParams struct {
 	DesiredStateComment string
 	ExpectedState       models.ExecutionStateType
 	ExpectedRevision    uint64
+	NewStartTime        int64
+	NewStopReason       models.ExecutionStopReason
 }
 
 func NewUpdateExecutionMatcher(t *testing.T, execution *models.Execution, params UpdateExecutionMatcherParams) *UpdateExecutionMatcher {
@@ -72,6 +76,8 @@ func NewUpdateExecutionMatcher(t *testing.T, execution *models.Execution, params
 		desiredStateComment: params.DesiredStateComment,
 		expectedState:       params.ExpectedState,
 		expectedRevision:    params.ExpectedRevision,
+		newStartTime:        params.NewStartTime,
+		newStopReason:       params.NewStopReason,
 	}
 }
 
@@ -80,6 +86,7 @@ func NewUpdateExecutionMatcherFromPlanUpdate(t *testing.T, update *models.PlanEx
 		NewDesiredState:     update.DesiredState,
 		DesiredStateComment: update.Event.Message,
 		ExpectedRevision:    update.Execution.Revision,
+		NewStopReason:       update.Event.StopReason(),
 	})
 }
 
@@ -95,6 +102,8 @@ func (m *UpdateExecutionMatcher) Matches(x interface{}) bool {
 		NewValues: models.Execution{
 			ComputeState: models.NewExecutionState(m.newState).WithMessage(m.newStateComment),
 			DesiredState: models.NewExecutionDesiredState(m.newDesiredState).WithMessage(m.desiredStateComment),
+			StartTime:    m.newStartTime,
+			StopReason:   m.newStopReason,
 		},
 		Condition: jobstore.UpdateExecutionCondition{
 			ExpectedRevision: m.expectedRevision,
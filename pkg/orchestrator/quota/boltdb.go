@@ -0,0 +1,89 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// BucketNamespaceQuotas is the bolt bucket holding the quotas, keyed by namespace.
+const BucketNamespaceQuotas = "namespace_quotas"
+
+// BoltStore is a Store that persists the quotas in BoltDB, which is expected to be
+// the database of the jobstore so that quotas survive restarts of the requester.
+type BoltStore struct {
+	database *bolt.DB
+}
+
+// NewBoltStore creates a new quota store persisted in the provided bolt database.
+func NewBoltStore(database *bolt.DB) (*BoltStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	err := database.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketNamespaceQuotas))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create namespace quotas bucket: %w", err)
+	}
+	return &BoltStore{database: database}, nil
+}
+
+func (s *BoltStore) Get(_ context.Context, namespace string) (quota models.NamespaceQuota, err error) {
+	err = s.database.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketNamespaceQuotas)).Get([]byte(namespace))
+		if data == nil {
+			return NewErrQuotaNotFound(namespace)
+		}
+		return json.Unmarshal(data, &quota)
+	})
+	return quota, err
+}
+
+func (s *BoltStore) List(_ context.Context) ([]models.NamespaceQuota, error) {
+	quotas := make([]models.NamespaceQuota, 0)
+	err := s.database.View(func(tx *bolt.Tx) error {
+		// keys are iterated in byte order, so the quotas are sorted by namespace
+		return tx.Bucket([]byte(BucketNamespaceQuotas)).ForEach(func(_, v []byte) error {
+			var quota models.NamespaceQuota
+			if err := json.Unmarshal(v, &quota); err != nil {
+				return err
+			}
+			quotas = append(quotas, quota)
+			return nil
+		})
+	})
+	return quotas, err
+}
+
+func (s *BoltStore) Put(_ context.Context, quota models.NamespaceQuota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+	return s.database.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketNamespaceQuotas)).Put([]byte(quota.Namespace), data)
+	})
+}
+
+func (s *BoltStore) Delete(_ context.Context, namespace string) error {
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketNamespaceQuotas))
+		if bkt.Get([]byte(namespace)) == nil {
+			return NewErrQuotaNotFound(namespace)
+		}
+		return bkt.Delete([]byte(namespace))
+	})
+}
+
+// compile-time check that BoltStore implements the Store interface
+var _ Store = (*BoltStore)(nil)
@@ -0,0 +1,176 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// DefaultRetryInterval is how long jobs over quota wait before being evaluated again.
+const DefaultRetryInterval = 30 * time.Second
+
+type EnforcerParams struct {
+	Store    Store
+	JobStore jobstore.Store
+	// RetryInterval is how long jobs over quota wait before being evaluated again.
+	// Defaults to DefaultRetryInterval.
+	RetryInterval time.Duration
+}
+
+// Enforcer checks whether jobs fit within the quota of their namespace. A job is counted
+// against the quota of its namespace from the moment the scheduler admits it, until it
+// reaches a terminal state. Pending jobs that were not admitted yet, including the ones
+// held back because of their quota, are not counted.
+//
+// Admissions are serialized per namespace, so that concurrent evaluations cannot admit
+// jobs that only fit the quota one at a time. Admitted jobs are tracked in memory until
+// the job store reports them running, so requesters sharing a job store each enforce
+// the quota on the jobs they admit.
+type Enforcer struct {
+	store         Store
+	jobStore      jobstore.Store
+	retryInterval time.Duration
+
+	mu sync.Mutex
+	// namespaceLocks serialize the admission of jobs in each namespace
+	namespaceLocks map[string]*sync.Mutex
+	// admitted holds the usage of the admitted jobs of each namespace that are not running yet
+	admitted map[string]map[string]models.NamespaceUsage
+}
+
+func NewEnforcer(params EnforcerParams) *Enforcer {
+	retryInterval := params.RetryInterval
+	if retryInterval == 0 {
+		retryInterval = DefaultRetryInterval
+	}
+	return &Enforcer{
+		store:          params.Store,
+		jobStore:       params.JobStore,
+		retryInterval:  retryInterval,
+		namespaceLocks: make(map[string]*sync.Mutex),
+		admitted:       make(map[string]map[string]models.NamespaceUsage),
+	}
+}
+
+// Store returns the store of the quotas being enforced.
+func (e *Enforcer) Store() Store {
+	return e.store
+}
+
+// RetryInterval returns how long jobs over quota wait before being evaluated again.
+func (e *Enforcer) RetryInterval() time.Duration {
+	return e.retryInterval
+}
+
+// Usage returns the number of active jobs in the namespace and the resources they requested.
+func (e *Enforcer) Usage(ctx context.Context, namespace string) (models.NamespaceUsage, error) {
+	return e.usage(ctx, namespace, "")
+}
+
+// Check returns the reasons why the job would exceed the quota of its namespace if it
+// was scheduled now, or nil if it fits or the namespace has no quota.
+func (e *Enforcer) Check(ctx context.Context, job models.Job) ([]string, error) {
+	reasons, _, err := e.check(ctx, job)
+	return reasons, err
+}
+
+// Admit checks whether the job fits within the quota of its namespace, and if it does,
+// counts the job against the quota until it reaches a terminal state. It returns the
+// reasons why the job exceeds the quota, or nil if the job is admitted.
+func (e *Enforcer) Admit(ctx context.Context, job models.Job) ([]string, error) {
+	unlock := e.lockNamespace(job.Namespace)
+	defer unlock()
+
+	reasons, demand, err := e.check(ctx, job)
+	if err != nil || len(reasons) > 0 || demand == nil {
+		return reasons, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.admitted[job.Namespace] == nil {
+		e.admitted[job.Namespace] = make(map[string]models.NamespaceUsage)
+	}
+	e.admitted[job.Namespace][job.ID] = *demand
+	return nil, nil
+}
+
+// check returns the reasons why the job exceeds the quota of its namespace, and the usage
+// of the job, which is nil if the namespace has no quota.
+func (e *Enforcer) check(ctx context.Context, job models.Job) ([]string, *models.NamespaceUsage, error) {
+	quota, err := e.store.Get(ctx, job.Namespace)
+	if err != nil {
+		if errors.As(err, &ErrQuotaNotFound{}) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	demand, err := job.ResourceUsage()
+	if err != nil {
+		return nil, nil, err
+	}
+	usage, err := e.usage(ctx, job.Namespace, job.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quota.Exceeded(usage.Add(demand)), &demand, nil
+}
+
+// lockNamespace locks the admission of jobs in the namespace, and returns the function that unlocks it
+func (e *Enforcer) lockNamespace(namespace string) func() {
+	e.mu.Lock()
+	lock, ok := e.namespaceLocks[namespace]
+	if !ok {
+		lock = new(sync.Mutex)
+		e.namespaceLocks[namespace] = lock
+	}
+	e.mu.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// usage sums the requested resources of the running and admitted jobs in the namespace,
+// except for the excluded job.
+func (e *Enforcer) usage(ctx context.Context, namespace string, excludedJobID string) (models.NamespaceUsage, error) {
+	jobs, err := e.jobStore.GetInProgressJobs(ctx, "")
+	if err != nil {
+		return models.NamespaceUsage{}, err
+	}
+	usage := models.NamespaceUsage{}
+	pending := make(map[string]bool)
+	for i := range jobs {
+		job := &jobs[i]
+		if job.Namespace != namespace || job.Type == models.JobTypeScheduled {
+			continue
+		}
+		if job.State.StateType != models.JobStateTypeRunning {
+			pending[job.ID] = true
+			continue
+		}
+		if job.ID == excludedJobID {
+			continue
+		}
+		jobUsage, err := job.ResourceUsage()
+		if err != nil {
+			return models.NamespaceUsage{}, err
+		}
+		usage = usage.Add(jobUsage)
+	}
+
+	// admitted jobs are counted until they are running, and forgotten once they are running or terminal
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for jobID, jobUsage := range e.admitted[namespace] {
+		switch {
+		case !pending[jobID]:
+			delete(e.admitted[namespace], jobID)
+		case jobID != excludedJobID:
+			usage = usage.Add(jobUsage)
+		}
+	}
+	return usage, nil
+}
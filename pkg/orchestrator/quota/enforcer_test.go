@@ -0,0 +1,165 @@
+//go:build unit || !integration
+
+package quota
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type EnforcerTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	jobStore *jobstore.MockStore
+	store    *InMemoryStore
+	enforcer *Enforcer
+}
+
+func TestEnforcerTestSuite(t *testing.T) {
+	suite.Run(t, new(EnforcerTestSuite))
+}
+
+func (s *EnforcerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.jobStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+	s.store = NewInMemoryStore()
+	s.enforcer = NewEnforcer(EnforcerParams{
+		Store:    s.store,
+		JobStore: s.jobStore,
+	})
+}
+
+// mockJob returns a job of the namespace with the given count, each execution requesting 1 CPU and 1 GPU
+func mockJob(namespace string, count int, state models.JobStateType) models.Job {
+	job := mock.Job()
+	job.Namespace = namespace
+	job.Count = count
+	job.State = models.NewJobState(state)
+	job.Task().ResourcesConfig = &models.ResourcesConfig{CPU: "1", GPU: "1"}
+	return *job
+}
+
+func (s *EnforcerTestSuite) TestCheck_NoQuota() {
+	reasons, err := s.enforcer.Check(s.ctx, mockJob("team-a", 100, models.JobStateTypePending))
+	s.Require().NoError(err)
+	s.Empty(reasons)
+}
+
+func (s *EnforcerTestSuite) TestCheck() {
+	s.Require().NoError(s.store.Put(s.ctx, models.NamespaceQuota{
+		Namespace: "team-a",
+		MaxJobs:   2,
+		Resources: models.Resources{GPU: 4},
+	}))
+	active := []models.Job{
+		mockJob("team-a", 2, models.JobStateTypeRunning),
+		// pending jobs and jobs of other namespaces are not counted
+		mockJob("team-a", 4, models.JobStateTypePending),
+		mockJob("team-b", 4, models.JobStateTypeRunning),
+	}
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any(), "").Return(active, nil).AnyTimes()
+
+	testCases := []struct {
+		name     string
+		count    int
+		exceeded int
+	}{
+		{name: "within quota", count: 2},
+		{name: "over gpu quota", count: 3, exceeded: 1},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			reasons, err := s.enforcer.Check(s.ctx, mockJob("team-a", tc.count, models.JobStateTypePending))
+			s.Require().NoError(err)
+			s.Len(reasons, tc.exceeded)
+		})
+	}
+
+	usage, err := s.enforcer.Usage(s.ctx, "team-a")
+	s.Require().NoError(err)
+	s.Equal(1, usage.Jobs)
+	s.Equal(uint64(2), usage.Resources.GPU)
+}
+
+func (s *EnforcerTestSuite) TestCheck_MaxJobs() {
+	s.Require().NoError(s.store.Put(s.ctx, models.NamespaceQuota{Namespace: "team-a", MaxJobs: 1}))
+	running := mockJob("team-a", 1, models.JobStateTypeRunning)
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any(), "").Return([]models.Job{running}, nil).Times(2)
+
+	reasons, err := s.enforcer.Check(s.ctx, mockJob("team-a", 1, models.JobStateTypePending))
+	s.Require().NoError(err)
+	s.Len(reasons, 1)
+
+	// the job being checked is not counted against its own quota
+	reasons, err = s.enforcer.Check(s.ctx, running)
+	s.Require().NoError(err)
+	s.Empty(reasons)
+}
+
+func (s *EnforcerTestSuite) TestAdmit_CountsAdmittedJobs() {
+	s.Require().NoError(s.store.Put(s.ctx, models.NamespaceQuota{Namespace: "team-a", MaxJobs: 1}))
+	first := mockJob("team-a", 1, models.JobStateTypePending)
+	second := mockJob("team-a", 1, models.JobStateTypePending)
+	active := []models.Job{first, second}
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any(), "").DoAndReturn(
+		func(context.Context, string) ([]models.Job, error) { return active, nil }).AnyTimes()
+
+	reasons, err := s.enforcer.Admit(s.ctx, first)
+	s.Require().NoError(err)
+	s.Empty(reasons)
+
+	// the first job is counted before it is running
+	reasons, err = s.enforcer.Admit(s.ctx, second)
+	s.Require().NoError(err)
+	s.Len(reasons, 1)
+	usage, err := s.enforcer.Usage(s.ctx, "team-a")
+	s.Require().NoError(err)
+	s.Equal(1, usage.Jobs)
+
+	// admitting a job again does not count it twice
+	reasons, err = s.enforcer.Admit(s.ctx, first)
+	s.Require().NoError(err)
+	s.Empty(reasons)
+
+	// the first job is no longer counted once it is terminal
+	active = []models.Job{second}
+	reasons, err = s.enforcer.Admit(s.ctx, second)
+	s.Require().NoError(err)
+	s.Empty(reasons)
+}
+
+func (s *EnforcerTestSuite) TestAdmit_Concurrent() {
+	s.Require().NoError(s.store.Put(s.ctx, models.NamespaceQuota{Namespace: "team-a", MaxJobs: 1}))
+	jobs := make([]models.Job, 10)
+	for i := range jobs {
+		jobs[i] = mockJob("team-a", 1, models.JobStateTypePending)
+	}
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any(), "").Return(jobs, nil).AnyTimes()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	admitted := 0
+	for i := range jobs {
+		wg.Add(1)
+		go func(job models.Job) {
+			defer wg.Done()
+			reasons, err := s.enforcer.Admit(s.ctx, job)
+			s.NoError(err)
+			if len(reasons) == 0 {
+				mu.Lock()
+				admitted++
+				mu.Unlock()
+			}
+		}(jobs[i])
+	}
+	wg.Wait()
+	s.Equal(1, admitted)
+}
@@ -0,0 +1,14 @@
+package quota
+
+// ErrQuotaNotFound is returned when a namespace has no quota
+type ErrQuotaNotFound struct {
+	Namespace string
+}
+
+func NewErrQuotaNotFound(namespace string) ErrQuotaNotFound {
+	return ErrQuotaNotFound{Namespace: namespace}
+}
+
+func (e ErrQuotaNotFound) Error() string {
+	return "quota not found for namespace: " + e.Namespace
+}
@@ -0,0 +1,67 @@
+package quota
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// InMemoryStore is a Store that keeps the quotas in memory, and loses them on restart.
+type InMemoryStore struct {
+	quotas map[string]models.NamespaceQuota
+	mu     sync.RWMutex
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		quotas: make(map[string]models.NamespaceQuota),
+	}
+}
+
+func (s *InMemoryStore) Get(_ context.Context, namespace string) (models.NamespaceQuota, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	quota, ok := s.quotas[namespace]
+	if !ok {
+		return models.NamespaceQuota{}, NewErrQuotaNotFound(namespace)
+	}
+	return *quota.Copy(), nil
+}
+
+func (s *InMemoryStore) List(_ context.Context) ([]models.NamespaceQuota, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	quotas := make([]models.NamespaceQuota, 0, len(s.quotas))
+	for _, quota := range s.quotas {
+		quotas = append(quotas, *quota.Copy())
+	}
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotas[i].Namespace < quotas[j].Namespace
+	})
+	return quotas, nil
+}
+
+func (s *InMemoryStore) Put(_ context.Context, quota models.NamespaceQuota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quotas[quota.Namespace] = *quota.Copy()
+	return nil
+}
+
+func (s *InMemoryStore) Delete(_ context.Context, namespace string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.quotas[namespace]; !ok {
+		return NewErrQuotaNotFound(namespace)
+	}
+	delete(s.quotas, namespace)
+	return nil
+}
+
+// compile-time check that InMemoryStore implements the Store interface
+var _ Store = (*InMemoryStore)(nil)
@@ -0,0 +1,96 @@
+package quota
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// SQLStore is a Store that persists the quotas in the database of the SQL jobstore, so
+// that quotas survive restarts of the requester.
+type SQLStore struct {
+	database *sqljobstore.Database
+}
+
+// NewSQLStore creates a new quota store persisted in the provided database.
+func NewSQLStore(ctx context.Context, database *sqljobstore.Database) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	_, err := database.Exec(ctx, `CREATE TABLE IF NOT EXISTS namespace_quotas (
+		namespace TEXT PRIMARY KEY,
+		data      `+database.JSONType()+` NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create namespace quotas table: %w", err)
+	}
+	return &SQLStore{database: database}, nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, namespace string) (quota models.NamespaceQuota, err error) {
+	var data string
+	err = s.database.QueryRow(ctx, `SELECT data FROM namespace_quotas WHERE namespace = ?`, namespace).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return quota, NewErrQuotaNotFound(namespace)
+	}
+	if err != nil {
+		return quota, err
+	}
+	err = json.Unmarshal([]byte(data), &quota)
+	return quota, err
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]models.NamespaceQuota, error) {
+	rows, err := s.database.Query(ctx, `SELECT data FROM namespace_quotas ORDER BY namespace`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	quotas := make([]models.NamespaceQuota, 0)
+	for rows.Next() {
+		var data string
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var quota models.NamespaceQuota
+		if err = json.Unmarshal([]byte(data), &quota); err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, rows.Err()
+}
+
+func (s *SQLStore) Put(ctx context.Context, quota models.NamespaceQuota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+	_, err = s.database.Exec(ctx, `INSERT INTO namespace_quotas (namespace, data) VALUES (?, ?)
+		ON CONFLICT (namespace) DO UPDATE SET data = excluded.data`, quota.Namespace, string(data))
+	return err
+}
+
+func (s *SQLStore) Delete(ctx context.Context, namespace string) error {
+	result, err := s.database.Exec(ctx, `DELETE FROM namespace_quotas WHERE namespace = ?`, namespace)
+	if err != nil {
+		return err
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return NewErrQuotaNotFound(namespace)
+	}
+	return nil
+}
+
+// compile-time check that SQLStore implements the Store interface
+var _ Store = (*SQLStore)(nil)
@@ -0,0 +1,96 @@
+//go:build unit || !integration
+
+package quota
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+	_ "modernc.org/sqlite"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	newStore func() Store
+	store    Store
+}
+
+func TestInMemoryStoreTestSuite(t *testing.T) {
+	suite.Run(t, &StoreTestSuite{newStore: func() Store { return NewInMemoryStore() }})
+}
+
+func TestBoltStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		database, err := bolt.Open(filepath.Join(s.T().TempDir(), "quotas.db"), 0600, nil)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = database.Close() })
+		store, err := NewBoltStore(database)
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func TestSQLStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		ctx := context.Background()
+		jobStore, err := sqljobstore.NewSQLJobStore(ctx, sqljobstore.DriverSQLite, ":memory:")
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = jobStore.Close(ctx) })
+		store, err := NewSQLStore(ctx, jobStore.Database())
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.store = s.newStore()
+}
+
+func (s *StoreTestSuite) TestPutGetDelete() {
+	ctx := context.Background()
+	quota := models.NamespaceQuota{Namespace: "team-a", MaxJobs: 3, Resources: models.Resources{GPU: 2}}
+	s.Require().NoError(s.store.Put(ctx, quota))
+
+	stored, err := s.store.Get(ctx, "team-a")
+	s.Require().NoError(err)
+	s.Equal(quota, stored)
+
+	// put replaces the existing quota
+	quota.MaxJobs = 5
+	s.Require().NoError(s.store.Put(ctx, quota))
+	stored, err = s.store.Get(ctx, "team-a")
+	s.Require().NoError(err)
+	s.Equal(5, stored.MaxJobs)
+
+	s.Require().NoError(s.store.Delete(ctx, "team-a"))
+	_, err = s.store.Get(ctx, "team-a")
+	s.ErrorAs(err, &ErrQuotaNotFound{})
+	s.ErrorAs(s.store.Delete(ctx, "team-a"), &ErrQuotaNotFound{})
+}
+
+func (s *StoreTestSuite) TestList() {
+	ctx := context.Background()
+	for _, namespace := range []string{"team-c", "team-a", "team-b"} {
+		s.Require().NoError(s.store.Put(ctx, models.NamespaceQuota{Namespace: namespace, MaxJobs: 1}))
+	}
+	quotas, err := s.store.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(quotas, 3)
+	for i, namespace := range []string{"team-a", "team-b", "team-c"} {
+		s.Equal(namespace, quotas[i].Namespace)
+	}
+}
+
+func (s *StoreTestSuite) TestPut_Invalid() {
+	s.Error(s.store.Put(context.Background(), models.NamespaceQuota{MaxJobs: 1}))
+}
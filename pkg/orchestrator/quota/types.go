@@ -0,0 +1,23 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Store persists the quotas of namespaces on the requester.
+type Store interface {
+	// Get returns the quota of the namespace, or ErrQuotaNotFound if the
+	// namespace has no quota.
+	Get(ctx context.Context, namespace string) (models.NamespaceQuota, error)
+
+	// List returns the quotas of all namespaces, sorted by namespace.
+	List(ctx context.Context) ([]models.NamespaceQuota, error)
+
+	// Put creates or replaces the quota of a namespace.
+	Put(ctx context.Context, quota models.NamespaceQuota) error
+
+	// Delete removes the quota of a namespace, lifting all its limits.
+	Delete(ctx context.Context, namespace string) error
+}
@@ -0,0 +1,226 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// defaultBatchSize is the number of terminal jobs read from the job store at once
+const defaultBatchSize = 100
+
+// terminalStates are the states of the jobs that can be pruned
+var terminalStates = []models.JobStateType{
+	models.JobStateTypeCompleted,
+	models.JobStateTypeFailed,
+	models.JobStateTypeStopped,
+}
+
+type ReaperParams struct {
+	JobStore jobstore.Store
+	// Policies are the retention policies, in order of precedence.
+	Policies []models.RetentionPolicy
+	// Interval is how often the job store is pruned in the background.
+	// Zero disables the background pruning.
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+// Reaper removes terminal jobs from the job store once they are no longer retained by
+// the retention policies. Jobs that are not matched by any policy are kept forever, and
+// jobs that pending jobs depend on are kept until their dependents are scheduled.
+type Reaper struct {
+	jobStore  jobstore.Store
+	policies  []models.RetentionPolicy
+	interval  time.Duration
+	clock     clock.Clock
+	batchSize uint32
+
+	stopChannel chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewReaper(params ReaperParams) *Reaper {
+	c := params.Clock
+	if c == nil {
+		c = clock.New()
+	}
+	return &Reaper{
+		jobStore:    params.JobStore,
+		policies:    params.Policies,
+		interval:    params.Interval,
+		clock:       c,
+		batchSize:   defaultBatchSize,
+		stopChannel: make(chan struct{}),
+	}
+}
+
+// Policies returns the configured retention policies
+func (r *Reaper) Policies() []models.RetentionPolicy {
+	return r.policies
+}
+
+// Start prunes the job store in the background every interval, until Stop is called.
+// It does nothing if the interval or the policies are not set.
+func (r *Reaper) Start(ctx context.Context) {
+	if r.interval <= 0 || len(r.policies) == 0 {
+		return
+	}
+	go func() {
+		ticker := r.clock.Ticker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				pruned, err := r.Prune(ctx, r.policies, false)
+				if err != nil {
+					log.Ctx(ctx).Err(err).Msg("failed to prune job store")
+				} else if len(pruned) > 0 {
+					log.Ctx(ctx).Info().Msgf("pruned %d terminal jobs from the job store", len(pruned))
+				}
+			case <-r.stopChannel:
+				log.Ctx(ctx).Debug().Msg("stopped job store reaper")
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (r *Reaper) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChannel)
+	})
+}
+
+// Prune removes the terminal jobs that are not retained by the policies, and returns them.
+// With dryRun, the jobs are only returned and the job store is left untouched.
+func (r *Reaper) Prune(ctx context.Context, policies []models.RetentionPolicy, dryRun bool) ([]models.PrunedJob, error) {
+	candidates, err := r.candidates(ctx, policies)
+	if err != nil || dryRun {
+		return candidates, err
+	}
+
+	pruned := make([]models.PrunedJob, 0, len(candidates))
+	for _, candidate := range candidates {
+		// the job might have been removed in the meantime, e.g. by another requester sharing the job store
+		err = r.jobStore.DeleteJob(ctx, candidate.JobID)
+		if _, notFound := err.(*bacerrors.JobNotFound); err != nil && !notFound {
+			return pruned, fmt.Errorf("failed to prune job %s: %w", candidate.JobID, err)
+		}
+		pruned = append(pruned, candidate)
+	}
+	return pruned, nil
+}
+
+// retentionGroup identifies the jobs of a namespace that are retained by the same policy
+type retentionGroup struct {
+	policy    int
+	namespace string
+}
+
+// candidates returns the terminal jobs that the policies do not retain, most recent first
+func (r *Reaper) candidates(ctx context.Context, policies []models.RetentionPolicy) ([]models.PrunedJob, error) {
+	if len(policies) == 0 {
+		return []models.PrunedJob{}, nil
+	}
+
+	// most recently modified jobs first, so that the last N jobs of each group are seen first.
+	// Terminal jobs are read in batches, as the job store can hold a large number of them.
+	query := jobstore.JobQuery{
+		ReturnAll:   true,
+		States:      terminalStates,
+		SortBy:      "modified_at",
+		SortReverse: true,
+		Limit:       r.batchSize,
+	}
+
+	now := r.clock.Now()
+	kept := make(map[retentionGroup]int)
+	candidates := make([]models.PrunedJob, 0)
+	for {
+		response, err := r.jobStore.GetJobs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for i := range response.Jobs {
+			job := &response.Jobs[i]
+			index, policy := matchingPolicy(policies, job)
+			if policy == nil || policy.RetainsForever() {
+				continue
+			}
+
+			group := retentionGroup{policy: index, namespace: job.Namespace}
+			if kept[group] < policy.KeepLast {
+				kept[group]++
+				continue
+			}
+
+			age := now.Sub(time.Unix(0, job.ModifyTime))
+			var reason string
+			if policy.MaxAge > 0 {
+				if age <= policy.MaxAge {
+					continue
+				}
+				reason = fmt.Sprintf("older than %s", policy.MaxAge)
+			} else {
+				reason = fmt.Sprintf("not in the last %d jobs", policy.KeepLast)
+			}
+
+			// the results of the job are still needed by the jobs waiting for it
+			needed, err := r.hasPendingDependents(ctx, job.ID)
+			if err != nil {
+				return nil, err
+			}
+			if needed {
+				continue
+			}
+
+			candidates = append(candidates, models.PrunedJob{
+				JobID:      job.ID,
+				Name:       job.Name,
+				Namespace:  job.Namespace,
+				State:      job.State.StateType,
+				ModifyTime: job.ModifyTime,
+				Reason:     reason,
+			})
+		}
+		if response.NextCursor == nil {
+			return candidates, nil
+		}
+		query.Cursor = response.NextCursor
+	}
+}
+
+// hasPendingDependents returns true if pending jobs depend on the job
+func (r *Reaper) hasPendingDependents(ctx context.Context, jobID string) (bool, error) {
+	response, err := r.jobStore.GetJobs(ctx, jobstore.JobQuery{
+		ReturnAll: true,
+		DependsOn: jobID,
+		States:    []models.JobStateType{models.JobStateTypePending},
+		Limit:     1,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve the dependents of job %s: %w", jobID, err)
+	}
+	return len(response.Jobs) > 0, nil
+}
+
+// matchingPolicy returns the first policy that applies to the job, and its index
+func matchingPolicy(policies []models.RetentionPolicy, job *models.Job) (int, *models.RetentionPolicy) {
+	for i := range policies {
+		if policies[i].Matches(job) {
+			return i, &policies[i]
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,201 @@
+//go:build unit || !integration
+
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ReaperTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	clock    *clock.Mock
+	jobStore *jobstore.MockStore
+	jobs     []models.Job
+	// dependents are the pending jobs that depend on each job
+	dependents map[string][]models.Job
+}
+
+func TestReaperTestSuite(t *testing.T) {
+	suite.Run(t, new(ReaperTestSuite))
+}
+
+func (s *ReaperTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	s.jobStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+
+	// jobs are listed most recently modified first, as returned by the job store
+	s.jobs = []models.Job{
+		s.mockJob("running", "team-a", models.JobStateTypeRunning, 30*24*time.Hour),
+		s.mockJob("a-completed-1d", "team-a", models.JobStateTypeCompleted, 24*time.Hour),
+		s.mockJob("b-failed-2d", "team-b", models.JobStateTypeFailed, 2*24*time.Hour),
+		s.mockJob("a-failed-3d", "team-a", models.JobStateTypeFailed, 3*24*time.Hour),
+		s.mockJob("a-completed-8d", "team-a", models.JobStateTypeCompleted, 8*24*time.Hour),
+		s.mockJob("b-stopped-9d", "team-b", models.JobStateTypeStopped, 9*24*time.Hour),
+		s.mockJob("a-completed-10d", "team-a", models.JobStateTypeCompleted, 10*24*time.Hour),
+	}
+	s.dependents = make(map[string][]models.Job)
+	s.jobStore.EXPECT().GetJobs(gomock.Any(), gomock.Any()).DoAndReturn(s.getJobs).AnyTimes()
+}
+
+// getJobs returns a page of the jobs that match the query, in the order they are listed
+func (s *ReaperTestSuite) getJobs(_ context.Context, query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
+	if query.DependsOn != "" {
+		return &jobstore.JobQueryResponse{Jobs: s.dependents[query.DependsOn]}, nil
+	}
+	s.Require().Equal("modified_at", query.SortBy)
+	s.Require().True(query.SortReverse)
+	jobs := lo.Filter(s.jobs, func(job models.Job, _ int) bool { return query.Matches(&job) })
+	start := 0
+	if query.Cursor != nil {
+		start = lo.IndexOf(lo.Map(jobs, func(job models.Job, _ int) string { return job.ID }), query.Cursor.ID) + 1
+	}
+	end := len(jobs)
+	if query.Limit > 0 && start+int(query.Limit) < end {
+		end = start + int(query.Limit)
+	}
+	response := &jobstore.JobQueryResponse{Jobs: jobs[start:end]}
+	if end < len(jobs) {
+		response.NextCursor = query.NewJobCursor(&jobs[end-1])
+	}
+	return response, nil
+}
+
+func (s *ReaperTestSuite) mockJob(id, namespace string, state models.JobStateType, age time.Duration) models.Job {
+	job := mock.Job()
+	job.ID = id
+	job.Namespace = namespace
+	job.State = models.NewJobState(state)
+	job.ModifyTime = s.clock.Now().Add(-age).UnixNano()
+	return *job
+}
+
+func (s *ReaperTestSuite) prunedIDs(policies []models.RetentionPolicy) []string {
+	reaper := NewReaper(ReaperParams{JobStore: s.jobStore, Clock: s.clock})
+	// read the jobs in several batches
+	reaper.batchSize = 2
+	pruned, err := reaper.Prune(s.ctx, policies, true)
+	s.Require().NoError(err)
+	return lo.Map(pruned, func(job models.PrunedJob, _ int) string { return job.JobID })
+}
+
+func (s *ReaperTestSuite) TestPolicies() {
+	week := 7 * 24 * time.Hour
+	testCases := []struct {
+		name     string
+		policies []models.RetentionPolicy
+		expected []string
+	}{
+		{
+			name:     "no policies",
+			expected: []string{},
+		},
+		{
+			name:     "by age",
+			policies: []models.RetentionPolicy{{MaxAge: week}},
+			expected: []string{"a-completed-8d", "b-stopped-9d", "a-completed-10d"},
+		},
+		{
+			name:     "keep last per namespace",
+			policies: []models.RetentionPolicy{{KeepLast: 2}},
+			expected: []string{"a-completed-8d", "a-completed-10d"},
+		},
+		{
+			name:     "keep last older than max age",
+			policies: []models.RetentionPolicy{{MaxAge: 2 * 24 * time.Hour, KeepLast: 3}},
+			expected: []string{"a-completed-10d"},
+		},
+		{
+			name:     "per state",
+			policies: []models.RetentionPolicy{{States: []models.JobStateType{models.JobStateTypeCompleted}, MaxAge: week}},
+			expected: []string{"a-completed-8d", "a-completed-10d"},
+		},
+		{
+			name:     "per namespace",
+			policies: []models.RetentionPolicy{{Namespace: "team-b", MaxAge: time.Hour}},
+			expected: []string{"b-failed-2d", "b-stopped-9d"},
+		},
+		{
+			name: "first matching policy wins",
+			policies: []models.RetentionPolicy{
+				// team-b jobs are retained forever
+				{Namespace: "team-b"},
+				{MaxAge: time.Hour},
+			},
+			expected: []string{"a-completed-1d", "a-failed-3d", "a-completed-8d", "a-completed-10d"},
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.Equal(tc.expected, s.prunedIDs(tc.policies))
+		})
+	}
+}
+
+func (s *ReaperTestSuite) TestPolicies_KeepsJobsWithPendingDependents() {
+	dependent := s.mockJob("dependent", "team-a", models.JobStateTypePending, 0)
+	s.dependents["a-completed-10d"] = []models.Job{dependent}
+	policies := []models.RetentionPolicy{{MaxAge: 7 * 24 * time.Hour}}
+	s.Equal([]string{"a-completed-8d", "b-stopped-9d"}, s.prunedIDs(policies))
+}
+
+func (s *ReaperTestSuite) TestPrune() {
+	policies := []models.RetentionPolicy{{MaxAge: 9 * 24 * time.Hour}}
+	s.jobStore.EXPECT().DeleteJob(gomock.Any(), "a-completed-10d").Return(nil)
+
+	reaper := NewReaper(ReaperParams{JobStore: s.jobStore, Clock: s.clock})
+	pruned, err := reaper.Prune(s.ctx, policies, false)
+	s.Require().NoError(err)
+	s.Require().Len(pruned, 1)
+	s.Equal("a-completed-10d", pruned[0].JobID)
+	s.Equal("team-a", pruned[0].Namespace)
+	s.Equal(models.JobStateTypeCompleted, pruned[0].State)
+	s.Equal("older than 216h0m0s", pruned[0].Reason)
+}
+
+func (s *ReaperTestSuite) TestPrune_AlreadyDeleted() {
+	policies := []models.RetentionPolicy{{MaxAge: 9 * 24 * time.Hour}}
+	s.jobStore.EXPECT().DeleteJob(gomock.Any(), "a-completed-10d").Return(bacerrors.NewJobNotFound("a-completed-10d"))
+
+	reaper := NewReaper(ReaperParams{JobStore: s.jobStore, Clock: s.clock})
+	pruned, err := reaper.Prune(s.ctx, policies, false)
+	s.Require().NoError(err)
+	s.Len(pruned, 1)
+}
+
+func (s *ReaperTestSuite) TestStart() {
+	policies := []models.RetentionPolicy{{MaxAge: 9*24*time.Hour + time.Hour}}
+	deleted := make(chan string, 1)
+	s.jobStore.EXPECT().DeleteJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id string) error {
+		deleted <- id
+		return nil
+	})
+
+	reaper := NewReaper(ReaperParams{JobStore: s.jobStore, Policies: policies, Interval: time.Minute, Clock: s.clock})
+	reaper.Start(s.ctx)
+	defer reaper.Stop()
+
+	// let the reaper start its ticker before moving the clock forward
+	time.Sleep(10 * time.Millisecond)
+	s.clock.Add(time.Minute)
+	select {
+	case id := <-deleted:
+		s.Equal("a-completed-10d", id)
+	case <-time.After(time.Second):
+		s.Fail("job store was not pruned")
+	}
+}
@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/rs/zerolog/log"
@@ -35,3 +36,12 @@ func (c *Chain) ShouldRetry(ctx context.Context, request orchestrator.RetryReque
 	}
 	return doRetry
 }
+
+// RetryDelay returns the longest delay of the strategies in the chain
+func (c *Chain) RetryDelay(ctx context.Context, request orchestrator.RetryRequest) time.Duration {
+	var delay time.Duration
+	for _, strategy := range c.strategies {
+		delay = max(delay, strategy.RetryDelay(ctx, request))
+	}
+	return delay
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+type ExponentialStrategyParams struct {
+	// DefaultPolicy is the retry policy of jobs that do not define their own.
+	// Jobs without a policy are retried immediately and without limit if not set.
+	DefaultPolicy *models.RetryPolicy
+}
+
+// ExponentialStrategy retries jobs according to their retry policy, waiting
+// exponentially longer between attempts.
+type ExponentialStrategy struct {
+	defaultPolicy *models.RetryPolicy
+}
+
+func NewExponentialStrategy(params ExponentialStrategyParams) *ExponentialStrategy {
+	return &ExponentialStrategy{defaultPolicy: params.DefaultPolicy}
+}
+
+// ShouldRetry returns true if the job has attempts left and all the failures are of retryable classes
+func (s *ExponentialStrategy) ShouldRetry(ctx context.Context, request orchestrator.RetryRequest) bool {
+	policy := s.policy(request)
+	if policy == nil {
+		return true
+	}
+	if policy.MaxAttempts > 0 && request.Attempts >= policy.MaxAttempts {
+		return false
+	}
+	for _, class := range request.FailureClasses {
+		if !policy.RetriesOn(class) {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryDelay returns the exponential backoff of the job's retry policy for the number of failed attempts
+func (s *ExponentialStrategy) RetryDelay(ctx context.Context, request orchestrator.RetryRequest) time.Duration {
+	policy := s.policy(request)
+	if policy == nil {
+		return 0
+	}
+	return backoff.NewExponential(policy.GetBackoffBase(), policy.GetBackoffMax()).Duration(request.Attempts)
+}
+
+func (s *ExponentialStrategy) policy(request orchestrator.RetryRequest) *models.RetryPolicy {
+	if request.Job != nil && request.Job.RetryPolicy != nil {
+		return request.Job.RetryPolicy
+	}
+	return s.defaultPolicy
+}
+
+// compile-time interface checks
+var _ orchestrator.RetryStrategy = (*ExponentialStrategy)(nil)
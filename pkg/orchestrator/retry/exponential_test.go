@@ -0,0 +1,73 @@
+//go:build unit || !integration
+
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+func TestExponentialStrategy_WithoutPolicy(t *testing.T) {
+	ctx := context.Background()
+	strategy := NewExponentialStrategy(ExponentialStrategyParams{})
+	request := orchestrator.RetryRequest{Job: &models.Job{}, Attempts: 100}
+	assert.True(t, strategy.ShouldRetry(ctx, request))
+	assert.Equal(t, time.Duration(0), strategy.RetryDelay(ctx, request))
+}
+
+func TestExponentialStrategy_WithPolicy(t *testing.T) {
+	ctx := context.Background()
+	strategy := NewExponentialStrategy(ExponentialStrategyParams{})
+	policy := &models.RetryPolicy{MaxAttempts: 3, BackoffBase: 10, BackoffMax: 30, RetryOn: []string{models.RetryOnNodeLost}}
+	job := &models.Job{RetryPolicy: policy}
+
+	request := orchestrator.RetryRequest{Job: job, Attempts: 1, FailureClasses: []string{models.RetryOnNodeLost}}
+	assert.True(t, strategy.ShouldRetry(ctx, request))
+	assert.Equal(t, 10*time.Second, strategy.RetryDelay(ctx, request))
+
+	request.Attempts = 2
+	assert.True(t, strategy.ShouldRetry(ctx, request))
+	assert.Equal(t, 20*time.Second, strategy.RetryDelay(ctx, request))
+
+	request.Attempts = 3
+	assert.False(t, strategy.ShouldRetry(ctx, request))
+	assert.Equal(t, 30*time.Second, strategy.RetryDelay(ctx, request))
+
+	// failures of classes that are not retryable are not retried
+	request = orchestrator.RetryRequest{Job: job, Attempts: 1, FailureClasses: []string{models.RetryOnFailed}}
+	assert.False(t, strategy.ShouldRetry(ctx, request))
+}
+
+func TestExponentialStrategy_DefaultPolicy(t *testing.T) {
+	ctx := context.Background()
+	strategy := NewExponentialStrategy(ExponentialStrategyParams{
+		DefaultPolicy: &models.RetryPolicy{MaxAttempts: 1},
+	})
+	request := orchestrator.RetryRequest{Job: &models.Job{}, Attempts: 1}
+	assert.False(t, strategy.ShouldRetry(ctx, request))
+
+	// the job's own policy takes precedence
+	request.Job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 2}
+	assert.True(t, strategy.ShouldRetry(ctx, request))
+}
+
+func TestChain_RetryDelay(t *testing.T) {
+	ctx := context.Background()
+	chain := NewChain()
+	chain.Add(
+		NewFixedStrategy(FixedStrategyParams{ShouldRetry: true}),
+		NewExponentialStrategy(ExponentialStrategyParams{}),
+	)
+	request := orchestrator.RetryRequest{
+		Job:      &models.Job{RetryPolicy: &models.RetryPolicy{BackoffBase: 5, BackoffMax: 60}},
+		Attempts: 2,
+	}
+	assert.True(t, chain.ShouldRetry(ctx, request))
+	assert.Equal(t, 10*time.Second, chain.RetryDelay(ctx, request))
+}
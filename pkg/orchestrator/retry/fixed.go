@@ -2,6 +2,7 @@ package retry
 
 import (
 	"context"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 )
@@ -21,5 +22,9 @@ func (s *FixedStrategy) ShouldRetry(ctx context.Context, request orchestrator.Re
 	return s.shouldRetry
 }
 
+func (s *FixedStrategy) RetryDelay(ctx context.Context, request orchestrator.RetryRequest) time.Duration {
+	return 0
+}
+
 // compile-time interface checks
 var _ orchestrator.RetryStrategy = (*FixedStrategy)(nil)
@@ -0,0 +1,167 @@
+//go:build unit || !integration
+
+package scheduler
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retry"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+func (s *BatchJobSchedulerTestSuite) TestProcessArray_ShouldCreateExecutionPerIndex() {
+	ctx := context.Background()
+	job, evaluation := mockArrayJob()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(nil, nil)
+
+	// fewer nodes than indices, so the executions are spread across the nodes
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), nodeIDs[0]),
+		*fakeNodeInfo(s.T(), nodeIDs[1]),
+	}
+	s.mockArrayNodeSelection(job, nodeInfos)
+
+	plan := s.capturePlan()
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+
+	s.Require().Len(plan.NewExecutions, 6)
+	for i, exec := range plan.NewExecutions {
+		s.Equal(i, exec.ArrayIndex)
+		s.Equal(nodeInfos[i%2].ID(), exec.NodeID)
+		env := exec.Job.Task().Env
+		s.Equal(strconv.Itoa(i), env[models.EnvArrayIndex])
+		s.Equal("6", env[models.EnvArraySize])
+		s.Equal([]string{"a", "b", "c"}[i/2], env["LETTER"])
+		s.Equal([]string{"1", "3"}[i%2], env["NUMBER"])
+	}
+	// the stored job is left untouched
+	s.Empty(plan.Job.Task().Env)
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcessArray_ShouldRetryFailedIndicesOnly() {
+	ctx := context.Background()
+	job, evaluation := mockArrayJob()
+	executions := mockArrayExecutions(job,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateFailed,
+		models.ExecutionStateBidAccepted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+	)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[2].NodeID),
+	}), nil)
+	s.mockArrayNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[3])})
+
+	plan := s.capturePlan()
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+
+	s.Require().Len(plan.NewExecutions, 1)
+	s.Equal(1, plan.NewExecutions[0].ArrayIndex)
+	s.Equal("1", plan.NewExecutions[0].Job.Task().Env[models.EnvArrayIndex])
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcessArray_ShouldFailWhenRetriesExhausted() {
+	ctx := context.Background()
+	job, evaluation := mockArrayJob()
+	executions := mockArrayExecutions(job,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateFailed,
+		models.ExecutionStateBidAccepted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+	)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[2].NodeID),
+	}), nil)
+	s.scheduler.retryStrategy = retry.NewFixedStrategy(retry.FixedStrategyParams{ShouldRetry: false})
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:        evaluation,
+		JobState:          models.JobStateTypeFailed,
+		StoppedExecutions: []string{executions[2].ID},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcessArray_ShouldMarkJobAsCompleted() {
+	ctx := context.Background()
+	job, evaluation := mockArrayJob()
+	executions := mockArrayExecutions(job,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+		models.ExecutionStateCompleted,
+	)
+	// a failed attempt of an index that later completed does not matter
+	failed := mock.ExecutionForJob(job)
+	failed.ArrayIndex = 4
+	failed.ComputeState = models.NewExecutionState(models.ExecutionStateFailed)
+	executions = append(executions, *failed)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeCompleted,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) mockArrayNodeSelection(job *models.Job, nodeInfos []models.NodeInfo) {
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job, &orchestrator.NodeSelectionConstraints{
+		RequireApproval:  false,
+		RequireConnected: false,
+	}).Return(nodeInfos, nil)
+}
+
+// capturePlan returns the plan that will be passed to the planner once processed
+func (s *BatchJobSchedulerTestSuite) capturePlan() *models.Plan {
+	plan := &models.Plan{}
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, p *models.Plan) error {
+		*plan = *p
+		return nil
+	})
+	return plan
+}
+
+// mockArrayJob returns an array job of 6 indices, with LETTER varying the slowest
+func mockArrayJob() (*models.Job, *models.Evaluation) {
+	job, _, evaluation := mockJob()
+	job.Array = &models.JobArray{Parameters: []*models.ArrayParameter{
+		{Name: "LETTER", Values: []string{"a", "b", "c"}},
+		{Name: "NUMBER", Range: &models.ArrayRange{Start: 1, End: 3, Step: 2}},
+	}}
+	job.Normalize()
+	return job, evaluation
+}
+
+// mockArrayExecutions returns an execution per array index in the given states
+func mockArrayExecutions(job *models.Job, states ...models.ExecutionStateType) []models.Execution {
+	executions := make([]models.Execution, len(states))
+	for i, state := range states {
+		e := mock.ExecutionForJob(job)
+		e.NodeID = nodeIDs[i%len(nodeIDs)]
+		e.ArrayIndex = i
+		e.ComputeState = models.NewExecutionState(state)
+		executions[i] = *e
+	}
+	return executions
+}
@@ -5,7 +5,9 @@ package scheduler
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -13,6 +15,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retry"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 )
@@ -101,7 +104,7 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_AlreadyEnoughExecutions() {
 		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
 		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	// empty plan
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -130,7 +133,7 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_RejectExtraExecutions() {
 		*fakeNodeInfo(s.T(), executions[1].NodeID),
 		*fakeNodeInfo(s.T(), executions[2].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation:         evaluation,
@@ -155,7 +158,7 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_TooManyExecutions() {
 		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
 		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation:        evaluation,
 		StoppedExecutions: []string{executions[execAskForBid].ID},
@@ -225,7 +228,7 @@ func (s *BatchJobSchedulerTestSuite) TestFailUnhealthyExecs_ShouldMarkExecutions
 		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
 		*fakeNodeInfo(s.T(), executions[execCanceled].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	s.mockNodeSelection(job, nodeInfos, 1)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -262,7 +265,7 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_NoMoreNod
 	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
 
 	// mark all nodes as unhealthy so that we don't retry on other nodes
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{}, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{}), nil)
 	s.mockNodeSelection(job, []models.NodeInfo{}, job.Count-1) // exclude completed exec.
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -289,7 +292,7 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_NoRetry()
 		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
 		*fakeNodeInfo(s.T(), executions[execCompleted].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation: evaluation,
@@ -344,3 +347,263 @@ func mockJob() (*models.Job, []models.Execution, *models.Evaluation) {
 	}
 	return job, executions, evaluation
 }
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldDelayRetryByBackoff() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	// one more execution is needed besides the active ones
+	job.Count = 4
+	job.RetryPolicy = &models.RetryPolicy{BackoffBase: 60, BackoffMax: 600}
+	failedAt := executions[execFailed].GetModifyTime()
+
+	broker := orchestrator.NewMockEvaluationBroker(gomock.NewController(s.T()))
+	clk := clock.NewMock()
+	clk.Set(failedAt.Add(10 * time.Second))
+	s.scheduler = NewBatchServiceJobScheduler(BatchServiceJobSchedulerParams{
+		JobStore:         s.jobStore,
+		Planner:          s.planner,
+		NodeSelector:     s.nodeSelector,
+		RetryStrategy:    retry.NewExponentialStrategy(retry.ExponentialStrategyParams{}),
+		EvaluationBroker: broker,
+		Clock:            clk,
+	})
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil).Times(2)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil).Times(2)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}), nil).Times(2)
+
+	// the failed execution is only retried once the backoff is over
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	broker.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(eval *models.Evaluation) error {
+		s.Equal(job.ID, eval.JobID)
+		s.Equal(models.EvalTriggerRetryFailedExec, eval.TriggeredBy)
+		s.Equal(failedAt.Add(60*time.Second), eval.WaitUntil)
+		return nil
+	}).Times(1)
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(2)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+
+	// evaluating the job again during the backoff does not enqueue another evaluation
+	clk.Add(10 * time.Second)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldRetryAfterBackoff() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	// one more execution is needed besides the active ones
+	job.Count = 4
+	job.RetryPolicy = &models.RetryPolicy{BackoffBase: 60, BackoffMax: 600}
+
+	clk := clock.NewMock()
+	clk.Set(executions[execFailed].GetModifyTime().Add(time.Minute))
+	s.scheduler.retryStrategy = retry.NewExponentialStrategy(retry.ExponentialStrategyParams{})
+	s.scheduler.clock = clk
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[3])}, 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeIDs[3]},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldResumeFromLatestCheckpoint() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	// one more execution is needed besides the active ones
+	job.Count = 4
+	job.Task().Checkpoint = &models.CheckpointConfig{Path: "/checkpoint", Interval: 60}
+	older := &models.SpecConfig{Type: models.StorageSourceS3, Params: map[string]interface{}{"Key": "older"}}
+	latest := &models.SpecConfig{Type: models.StorageSourceS3, Params: map[string]interface{}{"Key": "latest"}}
+	executions[execCanceled].Checkpoint = &models.ExecutionCheckpoint{Source: older, CreateTime: 1}
+	executions[execFailed].Checkpoint = &models.ExecutionCheckpoint{Source: latest, CreateTime: 2}
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}), nil)
+	s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), gomock.Any(), 1, gomock.Any()).
+		Return([]models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[3])}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		s.Require().Len(plan.NewExecutions, 1)
+		execution := plan.NewExecutions[0]
+		s.Equal(executions[execFailed].ID, execution.PreviousExecution)
+		inputs := execution.Job.Task().InputSources
+		s.Require().NotEmpty(inputs)
+		checkpoint := inputs[len(inputs)-1]
+		s.Equal(models.CheckpointInputAlias, checkpoint.Alias)
+		s.Equal("/checkpoint", checkpoint.Target)
+		s.Equal(latest, checkpoint.Source)
+		// the checkpoint is only added to the job of the new execution
+		s.Len(plan.Job.Task().InputSources, len(inputs)-1)
+		return nil
+	})
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldRescheduleExecution_Preempted() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	// one more execution is needed besides the active ones
+	job.Count = 4
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 1, BackoffBase: 60, BackoffMax: 600}
+	executions[execFailed].DesiredState = models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped)
+	executions[execFailed].StopReason = models.ExecutionStopReasonPreempted
+
+	// preempted executions are neither delayed by the backoff nor counted as attempts
+	clk := clock.NewMock()
+	clk.Set(executions[execFailed].GetModifyTime().Add(10 * time.Second))
+	s.scheduler.retryStrategy = retry.NewExponentialStrategy(retry.ExponentialStrategyParams{})
+	s.scheduler.clock = clk
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[3])}, 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeIDs[3]},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldHoldJobOverQuota() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	running := mock.Job()
+	running.Namespace = job.Namespace
+	running.State = models.NewJobState(models.JobStateTypeRunning)
+
+	quotaStore := quota.NewInMemoryStore()
+	s.Require().NoError(quotaStore.Put(ctx, models.NamespaceQuota{Namespace: job.Namespace, MaxJobs: 1}))
+	broker := orchestrator.NewMockEvaluationBroker(gomock.NewController(s.T()))
+	s.scheduler.evaluationBroker = broker
+	s.scheduler.quotaEnforcer = quota.NewEnforcer(quota.EnforcerParams{
+		Store:    quotaStore,
+		JobStore: s.jobStore,
+	})
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(nil, nil)
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any(), "").Return([]models.Job{*running, *job}, nil)
+
+	// the job is kept pending without a plan, and evaluated again later
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).Return(nil)
+	broker.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(eval *models.Evaluation) error {
+		s.Equal(job.ID, eval.JobID)
+		s.Equal(models.EvalTriggerJobQuota, eval.TriggeredBy)
+		s.True(eval.WaitUntil.After(time.Now()))
+		return nil
+	})
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Times(0)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+// withDependency makes the job depend on a new upstream job in the given state
+func (s *BatchJobSchedulerTestSuite) withDependency(job *models.Job, state models.JobStateType) {
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(state)
+	job.Dependencies = []*models.JobDependency{{Name: upstream.Name, JobID: upstream.ID}}
+	s.scheduler.workflow = orchestrator.NewWorkflow(orchestrator.WorkflowParams{Store: s.jobStore})
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldHoldJobWithPendingDependencies() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	s.withDependency(job, models.JobStateTypeRunning)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(nil, nil)
+
+	// the job is kept pending without a plan until the workflow evaluates it again
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Times(0)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_FailedDependency() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	s.withDependency(job, models.JobStateTypeFailed)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(nil, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeFailed,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldCreateExecutionsWhenDependenciesCompleted() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	s.withDependency(job, models.JobStateTypeCompleted)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(nil, nil)
+
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), nodeIDs[0]),
+		*fakeNodeInfo(s.T(), nodeIDs[1]),
+		*fakeNodeInfo(s.T(), nodeIDs[2]),
+	}
+	s.mockNodeSelection(job, nodeInfos, job.Count)
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: nodeIDs[:3],
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_MaxAttempts() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	// one more execution is needed besides the active ones
+	job.Count = 4
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 1}
+	s.scheduler.retryStrategy = retry.NewExponentialStrategy(retry.ExponentialStrategyParams{})
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}), nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeFailed,
+		StoppedExecutions: []string{
+			executions[execAskForBid].ID,
+			executions[execBidAccepted].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
@@ -3,7 +3,9 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 )
 
@@ -18,10 +21,15 @@ import (
 // - batch jobs that run until completion on N number of nodes
 // - service jobs than run until stopped on N number of nodes
 type BatchServiceJobScheduler struct {
-	jobStore      jobstore.Store
-	planner       orchestrator.Planner
-	nodeSelector  orchestrator.NodeSelector
-	retryStrategy orchestrator.RetryStrategy
+	jobStore         jobstore.Store
+	planner          orchestrator.Planner
+	nodeSelector     orchestrator.NodeSelector
+	retryStrategy    orchestrator.RetryStrategy
+	evaluationBroker orchestrator.EvaluationBroker
+	quotaEnforcer    *quota.Enforcer
+	workflow         *orchestrator.Workflow
+	clock            clock.Clock
+	delayed          *delayedEvaluations
 }
 
 type BatchServiceJobSchedulerParams struct {
@@ -29,14 +37,30 @@ type BatchServiceJobSchedulerParams struct {
 	Planner       orchestrator.Planner
 	NodeSelector  orchestrator.NodeSelector
 	RetryStrategy orchestrator.RetryStrategy
+	// EvaluationBroker is used to re-evaluate jobs once their retry backoff or the drain deadline of their nodes is over.
+	EvaluationBroker orchestrator.EvaluationBroker
+	// QuotaEnforcer holds back pending jobs whose namespace is over quota. Quotas are not enforced if nil.
+	QuotaEnforcer *quota.Enforcer
+	// Workflow holds back pending jobs until the jobs they depend on have completed. Dependencies are not checked if nil.
+	Workflow *orchestrator.Workflow
+	// Clock is the clock used to decide when retries are due. Defaults to the system clock.
+	Clock clock.Clock
 }
 
 func NewBatchServiceJobScheduler(params BatchServiceJobSchedulerParams) *BatchServiceJobScheduler {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
 	return &BatchServiceJobScheduler{
-		jobStore:      params.JobStore,
-		planner:       params.Planner,
-		nodeSelector:  params.NodeSelector,
-		retryStrategy: params.RetryStrategy,
+		jobStore:         params.JobStore,
+		planner:          params.Planner,
+		nodeSelector:     params.NodeSelector,
+		retryStrategy:    params.RetryStrategy,
+		evaluationBroker: params.EvaluationBroker,
+		quotaEnforcer:    params.QuotaEnforcer,
+		workflow:         params.Workflow,
+		clock:            params.Clock,
+		delayed:          newDelayedEvaluations(),
 	}
 }
 
@@ -68,21 +92,62 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 		return b.planner.Process(ctx, plan)
 	}
 
+	// keep the job pending until its dependencies have completed
+	if held, err := holdForDependencies(ctx, b.workflow, b.planner, plan, job); held || err != nil {
+		return err
+	}
+
+	// keep the job pending while its namespace is over quota
+	if held, err := holdOverQuota(
+		ctx, b.quotaEnforcer, b.delayed, b.jobStore, b.evaluationBroker, job, b.clock.Now().UTC()); held || err != nil {
+		return err
+	}
+
 	// Retrieve the info for all the nodes that have executions for this job
-	nodeInfos, err := existingNodeInfos(ctx, b.nodeSelector, nonTerminalExecs)
+	nodeStates, err := existingNodeStates(ctx, b.nodeSelector, nonTerminalExecs)
 	if err != nil {
 		return err
 	}
 
 	// Mark executions that are running on nodes that are not healthy as failed
-	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
+	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeStates)
 	lost.markStopped(orchestrator.ExecStoppedByNodeUnhealthyEvent(), plan)
 
+	// Move service executions off draining nodes, whereas batch executions are allowed to finish
+	if job.Type == models.JobTypeService {
+		nonTerminalExecs, err = drainExecs(ctx, b.jobStore, b.evaluationBroker, b.delayed, job, plan,
+			nonTerminalExecs, nodeStates, b.clock.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		// Replace executions that failed their health checks
+		var unhealthy execSet
+		nonTerminalExecs, unhealthy = nonTerminalExecs.filterByExecutionHealth()
+		unhealthy.markStopped(orchestrator.ExecStoppedByHealthCheckEvent(), plan)
+	}
+
+	// Only batch jobs can be array jobs, as validation rejects service array jobs whose updates
+	// would otherwise not be rolled out
+	if job.IsArray() {
+		return b.processArray(ctx, &job, plan, existingExecs, nonTerminalExecs, lost)
+	}
+
+	// Replace the executions of previous versions of service jobs that were updated
+	surge := 0
+	if job.Type == models.JobTypeService {
+		var reverted bool
+		nonTerminalExecs, surge, reverted, err = b.rolloutUpdate(ctx, &job, plan, existingExecs, nonTerminalExecs)
+		if err != nil || reverted {
+			return err
+		}
+	}
+
 	// Calculate remaining job count
 	// Service jobs run until the user stops the job, and would be a bug if an execution is marked completed. So the desired
 	// remaining count equals the count specified in the job spec.
 	// Batch jobs on the other hand run until completion and the desired remaining count excludes the completed executions
-	desiredRemainingCount := job.Count
+	desiredRemainingCount := job.Count + surge
 	if job.Type == models.JobTypeBatch {
 		desiredRemainingCount = math.Max(0, job.Count-existingExecs.countCompleted())
 	}
@@ -96,12 +161,15 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	remainingExecutionCount := desiredRemainingCount - execsByApprovalStatus.activeCount()
 	if remainingExecutionCount > 0 {
 		allFailed := existingExecs.filterFailed().union(lost)
-		var placementErr error
-		if len(allFailed) > 0 && !b.retryStrategy.ShouldRetry(ctx, orchestrator.RetryRequest{JobID: job.ID}) {
-			placementErr = fmt.Errorf("exceeded max retries for job %s", job.ID)
+		retryAt, placementErr := b.retryTime(ctx, &job, existingExecs, lost)
+		if placementErr != nil {
 			plan.Event = orchestrator.JobExhaustedRetriesEvent()
+		} else if !retryAt.IsZero() {
+			if err = b.delayRetry(ctx, &job, retryAt); err != nil {
+				return err
+			}
 		} else {
-			_, placementErr = b.createMissingExecs(ctx, remainingExecutionCount, &job, plan)
+			_, placementErr = b.createMissingExecs(ctx, remainingExecutionCount, &job, plan, existingExecs)
 		}
 		if placementErr != nil {
 			b.handleFailure(nonTerminalExecs, allFailed, plan, placementErr)
@@ -123,19 +191,63 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	return b.planner.Process(ctx, plan)
 }
 
-func (b *BatchServiceJobScheduler) createMissingExecs(
-	ctx context.Context, remainingExecutionCount int, job *models.Job, plan *models.Plan) (execSet, error) {
+// rolloutUpdate replaces the executions of previous versions of an updated service job with
+// executions of its current version, following the job's update strategy. It returns the
+// non-terminal executions that count towards the job's count, the number of canary executions
+// to run on top of the count, and whether the update failed and was reverted instead.
+func (b *BatchServiceJobScheduler) rolloutUpdate(ctx context.Context, job *models.Job, plan *models.Plan,
+	existingExecs execSet, nonTerminalExecs execSet) (execSet, int, bool, error) {
+	current, outdated, err := outdatedExecs(ctx, b.jobStore, *job, nonTerminalExecs)
+	if err != nil || len(outdated) == 0 {
+		return nonTerminalExecs, 0, false, err
+	}
+
+	// without an update strategy, all executions are replaced at once
+	if job.Update == nil {
+		outdated.markStopped(orchestrator.ExecStoppedByJobUpdateEvent(), plan)
+		return current, 0, false, nil
+	}
+
+	now := b.clock.Now().UTC()
+	if failedUpdate(*job, existingExecs) {
+		return nil, 0, true, revertUpdate(ctx, b.jobStore, b.evaluationBroker, *job, outdated.latestJobVersion(), now)
+	}
+
+	healthy, unhealthy, err := awaitHealthy(ctx, b.jobStore, b.evaluationBroker, b.delayed, *job, current, now)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	// canaries run alongside the executions of the previous versions until they are healthy
+	if len(healthy) < job.Update.Canary {
+		return current.union(outdated), job.Update.Canary, false, nil
+	}
+
+	// stop the executions exceeding the job's count, such as the canaries' counterparts, and
+	// replace up to max parallel executions at a time, as long as the new ones are unhealthy
+	excess := math.Max(0, len(current)+len(outdated)-job.Count)
+	toStop, kept := outdated.filterByOverSubscriptions(excess + math.Max(0, job.Update.MaxParallel-len(unhealthy)))
+	toStop.markStopped(orchestrator.ExecStoppedByJobUpdateEvent(), plan)
+	return current.union(kept), 0, false, nil
+}
+
+// createMissingExecs creates and places the given number of executions. The new executions
+// resume from the latest checkpoint published by the existing executions, if any.
+func (b *BatchServiceJobScheduler) createMissingExecs(ctx context.Context,
+	remainingExecutionCount int, job *models.Job, plan *models.Plan, existingExecs execSet) (execSet, error) {
 	newExecs := execSet{}
 	for i := 0; i < remainingExecutionCount; i++ {
 		execution := &models.Execution{
 			JobID:        job.ID,
 			Job:          job,
+			JobVersion:   job.Version,
 			ID:           idgen.ExecutionIDPrefix + uuid.NewString(),
 			EvalID:       plan.EvalID,
 			Namespace:    job.Namespace,
 			ComputeState: models.NewExecutionState(models.ExecutionStateNew),
 			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStatePending),
 		}
+		resumeFromCheckpoint(execution, existingExecs)
 		execution.Normalize()
 		newExecs[execution.ID] = execution
 	}
@@ -152,6 +264,193 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 	return newExecs, nil
 }
 
+// processArray reconciles the executions of an array job, where each index of the array
+// must complete exactly once. Indices without an active execution are scheduled again,
+// so that only the failed indices are retried.
+func (b *BatchServiceJobScheduler) processArray(ctx context.Context, job *models.Job, plan *models.Plan,
+	existingExecs execSet, nonTerminalExecs execSet, lost execSet) error {
+	existingByIndex := existingExecs.groupByArrayIndex()
+	nonTerminalByIndex := nonTerminalExecs.groupByArrayIndex()
+	lostByIndex := lost.groupByArrayIndex()
+
+	var missingIndices []int
+	var retryAt time.Time
+	var retryErr error
+	completed := 0
+	for index := 0; index < job.ArraySize(); index++ {
+		active := nonTerminalByIndex[index]
+		if existingByIndex[index].countCompleted() > 0 {
+			completed++
+			active.markStopped(orchestrator.ExecStoppedByOversubscriptionEvent(), plan)
+			continue
+		}
+
+		// each index runs a single execution
+		execsByApprovalStatus := active.filterByApprovalStatus(1)
+		execsByApprovalStatus.toApprove.markApproved(plan)
+		execsByApprovalStatus.toReject.markStopped(orchestrator.ExecStoppedByNodeRejectedEvent(), plan)
+		_, overSubscriptions := execsByApprovalStatus.running.filterByOverSubscriptions(1)
+		overSubscriptions.markStopped(orchestrator.ExecStoppedByOversubscriptionEvent(), plan)
+
+		if execsByApprovalStatus.activeCount() == 0 {
+			// failed indices are retried on their own, once their backoff is over
+			indexRetryAt, err := b.retryTime(ctx, job, existingByIndex[index], lostByIndex[index])
+			if err != nil {
+				retryErr = err
+			} else if indexRetryAt.IsZero() {
+				missingIndices = append(missingIndices, index)
+			} else if retryAt.IsZero() || indexRetryAt.Before(retryAt) {
+				retryAt = indexRetryAt
+			}
+		}
+	}
+
+	if retryErr != nil || len(missingIndices) > 0 {
+		placementErr := retryErr
+		if placementErr != nil {
+			plan.Event = orchestrator.JobExhaustedRetriesEvent()
+		} else {
+			placementErr = b.createArrayExecs(ctx, missingIndices, job, plan, existingByIndex)
+		}
+		if placementErr != nil {
+			b.handleFailure(nonTerminalExecs, existingExecs.filterFailed().union(lost), plan, placementErr)
+			return b.planner.Process(ctx, plan)
+		}
+	}
+
+	if !retryAt.IsZero() {
+		if err := b.delayRetry(ctx, job, retryAt); err != nil {
+			return err
+		}
+	}
+
+	if completed == job.ArraySize() {
+		plan.MarkJobCompleted()
+	}
+	plan.MarkJobRunningIfEligible()
+	return b.planner.Process(ctx, plan)
+}
+
+// retryTime decides whether the failed executions among the given ones can be retried.
+// It returns an error if the job exhausted its retries, or otherwise the time the retry is
+// delayed until by the job's backoff, which is zero if the executions can be retried now.
+// lost holds the executions whose node was found to be lost by the current evaluation.
+func (b *BatchServiceJobScheduler) retryTime(
+	ctx context.Context, job *models.Job, execs execSet, lost execSet) (time.Time, error) {
+	// preempted executions are rescheduled right away and don't count as failed attempts
+	failed := execs.filterFailed()
+	for id := range failed.filterPreempted() {
+		delete(failed, id)
+	}
+	lost = execs.filterLost().union(lost)
+	if len(failed) == 0 && len(lost) == 0 {
+		return time.Time{}, nil
+	}
+
+	now := b.clock.Now().UTC()
+	request := orchestrator.RetryRequest{
+		JobID:    job.ID,
+		Job:      job,
+		Attempts: len(failed) + len(lost),
+	}
+	var lastFailure time.Time
+	for _, exec := range failed.union(lost) {
+		lastFailure = maxTime(lastFailure, exec.GetModifyTime())
+	}
+	if len(failed) > 0 {
+		request.FailureClasses = append(request.FailureClasses, models.RetryOnFailed)
+	}
+	if len(lost) > 0 {
+		request.FailureClasses = append(request.FailureClasses, models.RetryOnNodeLost)
+		if len(lost.filterNonTerminal()) > 0 {
+			// the executions lost by this evaluation are only now known to have failed
+			lastFailure = now
+		}
+	}
+
+	if !b.retryStrategy.ShouldRetry(ctx, request) {
+		return time.Time{}, fmt.Errorf("exceeded max retries for job %s", job.ID)
+	}
+	retryAt := lastFailure.Add(b.retryStrategy.RetryDelay(ctx, request))
+	if !retryAt.After(now) {
+		return time.Time{}, nil
+	}
+	return retryAt, nil
+}
+
+// delayRetry enqueues an evaluation of the job for when its retry backoff is over
+func (b *BatchServiceJobScheduler) delayRetry(ctx context.Context, job *models.Job, retryAt time.Time) error {
+	log.Ctx(ctx).Debug().Msgf("delaying retry of job %s until %s", job.ID, retryAt)
+	return b.delayed.enqueue(ctx, b.jobStore, b.evaluationBroker, *job,
+		models.EvalTriggerRetryFailedExec, retryAt, b.clock.Now().UTC())
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// createArrayExecs creates an execution for each of the given array indices. The executions
+// are spread across all matching nodes, as an array job can have more indices than there are nodes.
+// Each index resumes from the latest checkpoint published by its existing executions, if any.
+func (b *BatchServiceJobScheduler) createArrayExecs(ctx context.Context,
+	indices []int, job *models.Job, plan *models.Plan, existingByIndex map[int]execSet) error {
+	nodes, err := b.nodeSelector.AllMatchingNodes(
+		ctx,
+		job,
+		&orchestrator.NodeSelectionConstraints{
+			RequireApproval:  false,
+			RequireConnected: false,
+		},
+	)
+	if err == nil && len(nodes) == 0 {
+		err = orchestrator.NewErrNotEnoughNodes(1, nil)
+	}
+	if err != nil {
+		plan.Event = models.EventFromError(orchestrator.EventTopicJobScheduling, err)
+		return err
+	}
+
+	for i, index := range indices {
+		execution := &models.Execution{
+			JobID:        job.ID,
+			Job:          job.ForArrayIndex(index),
+			JobVersion:   job.Version,
+			ArrayIndex:   index,
+			ID:           idgen.ExecutionIDPrefix + uuid.NewString(),
+			EvalID:       plan.EvalID,
+			Namespace:    job.Namespace,
+			NodeID:       nodes[i%len(nodes)].ID(),
+			ComputeState: models.NewExecutionState(models.ExecutionStateNew),
+			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStatePending),
+		}
+		resumeFromCheckpoint(execution, existingByIndex[index])
+		execution.Normalize()
+		plan.AppendExecution(execution)
+	}
+	return nil
+}
+
+// resumeFromCheckpoint makes a new execution resume from the latest checkpoint published by
+// the existing executions, by adding the checkpoint to the input sources of its copy of the job.
+func resumeFromCheckpoint(execution *models.Execution, existingExecs execSet) {
+	config := execution.Job.Task().Checkpoint
+	if config == nil {
+		return
+	}
+	previous := existingExecs.latestCheckpoint()
+	if previous == nil {
+		return
+	}
+	job := execution.Job.Copy()
+	task := job.Task()
+	task.InputSources = append(task.InputSources, previous.Checkpoint.InputSource(config))
+	execution.Job = job
+	execution.PreviousExecution = previous.ID
+}
+
 // placeExecs places the executions
 func (b *BatchServiceJobScheduler) placeExecs(ctx context.Context, execs execSet, job *models.Job) error {
 	if len(execs) > 0 {
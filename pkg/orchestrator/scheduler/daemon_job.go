@@ -4,33 +4,57 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 )
 
 // DaemonJobScheduler is a scheduler for batch jobs that run until completion
 type DaemonJobScheduler struct {
-	jobStore     jobstore.Store
-	planner      orchestrator.Planner
-	nodeSelector orchestrator.NodeSelector
+	jobStore         jobstore.Store
+	planner          orchestrator.Planner
+	nodeSelector     orchestrator.NodeSelector
+	evaluationBroker orchestrator.EvaluationBroker
+	quotaEnforcer    *quota.Enforcer
+	workflow         *orchestrator.Workflow
+	clock            clock.Clock
+	delayed          *delayedEvaluations
 }
 
 type DaemonJobSchedulerParams struct {
 	JobStore     jobstore.Store
 	Planner      orchestrator.Planner
 	NodeSelector orchestrator.NodeSelector
+	// EvaluationBroker is used to re-evaluate jobs held back by their quota, or running on draining nodes.
+	EvaluationBroker orchestrator.EvaluationBroker
+	// QuotaEnforcer holds back pending jobs whose namespace is over quota. Quotas are not enforced if nil.
+	QuotaEnforcer *quota.Enforcer
+	// Workflow holds back pending jobs until the jobs they depend on have completed. Dependencies are not checked if nil.
+	Workflow *orchestrator.Workflow
+	// Clock is the clock used to decide when drain deadlines and rollouts are due. Defaults to the system clock.
+	Clock clock.Clock
 }
 
 func NewDaemonJobScheduler(params DaemonJobSchedulerParams) *DaemonJobScheduler {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
 	return &DaemonJobScheduler{
-		jobStore:     params.JobStore,
-		planner:      params.Planner,
-		nodeSelector: params.NodeSelector,
+		jobStore:         params.JobStore,
+		planner:          params.Planner,
+		nodeSelector:     params.NodeSelector,
+		evaluationBroker: params.EvaluationBroker,
+		quotaEnforcer:    params.QuotaEnforcer,
+		workflow:         params.Workflow,
+		clock:            params.Clock,
+		delayed:          newDelayedEvaluations(),
 	}
 }
 
@@ -62,18 +86,47 @@ func (b *DaemonJobScheduler) Process(ctx context.Context, evaluation *models.Eva
 		return b.planner.Process(ctx, plan)
 	}
 
+	// keep the job pending until its dependencies have completed
+	if held, err := holdForDependencies(ctx, b.workflow, b.planner, plan, job); held || err != nil {
+		return err
+	}
+
+	// keep the job pending while its namespace is over quota
+	if held, err := holdOverQuota(
+		ctx, b.quotaEnforcer, b.delayed, b.jobStore, b.evaluationBroker, job, b.clock.Now().UTC()); held || err != nil {
+		return err
+	}
+
 	// Retrieve the info for all the nodes that have executions for this job
-	nodeInfos, err := existingNodeInfos(ctx, b.nodeSelector, nonTerminalExecs)
+	nodeStates, err := existingNodeStates(ctx, b.nodeSelector, nonTerminalExecs)
 	if err != nil {
 		return err
 	}
 
 	// Mark executions that are running on nodes that are not healthy as failed
-	_, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
+	healthy, lost := nonTerminalExecs.filterByNodeHealth(nodeStates)
 	lost.markStopped(orchestrator.ExecStoppedByNodeUnhealthyEvent(), plan)
 
+	// Stop executions on draining nodes, as no new executions are created on cordoned nodes
+	healthy, err = drainExecs(ctx, b.jobStore, b.evaluationBroker, b.delayed, job, plan,
+		healthy, nodeStates, b.clock.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	// Restart executions that failed their health checks on the same node
+	healthy, unhealthy := healthy.filterByExecutionHealth()
+	unhealthy.markStopped(orchestrator.ExecStoppedByHealthCheckEvent(), plan)
+
+	// Replace the executions of previous versions of the job if it was updated
+	replaced, reverted, err := b.rolloutUpdate(ctx, &job, plan, existingExecs, healthy)
+	if err != nil || reverted {
+		return err
+	}
+	replaced = replaced.union(unhealthy)
+
 	// Look for new matching nodes and create new executions every time we evaluate the job
-	_, err = b.createMissingExecs(ctx, &job, plan, existingExecs)
+	_, err = b.createMissingExecs(ctx, &job, plan, existingExecs, replaced)
 	if err != nil {
 		return fmt.Errorf("failed to find/create missing executions: %w", err)
 	}
@@ -82,8 +135,47 @@ func (b *DaemonJobScheduler) Process(ctx context.Context, evaluation *models.Eva
 	return b.planner.Process(ctx, plan)
 }
 
-func (b *DaemonJobScheduler) createMissingExecs(
-	ctx context.Context, job *models.Job, plan *models.Plan, existingExecs execSet) (execSet, error) {
+// rolloutUpdate replaces the executions of previous versions of an updated daemon job, node by
+// node, following the job's update strategy. It returns the executions stopped to be replaced
+// on their node, and whether the update failed and was reverted instead.
+func (b *DaemonJobScheduler) rolloutUpdate(ctx context.Context, job *models.Job, plan *models.Plan,
+	existingExecs execSet, nonTerminalExecs execSet) (execSet, bool, error) {
+	current, outdated, err := outdatedExecs(ctx, b.jobStore, *job, nonTerminalExecs)
+	if err != nil || len(outdated) == 0 {
+		return outdated, false, err
+	}
+
+	// without an update strategy, all executions are replaced at once
+	if job.Update == nil {
+		outdated.markStopped(orchestrator.ExecStoppedByJobUpdateEvent(), plan)
+		return outdated, false, nil
+	}
+
+	now := b.clock.Now().UTC()
+	if failedUpdate(*job, existingExecs) {
+		return nil, true, revertUpdate(ctx, b.jobStore, b.evaluationBroker, *job, outdated.latestJobVersion(), now)
+	}
+
+	healthy, unhealthy, err := awaitHealthy(ctx, b.jobStore, b.evaluationBroker, b.delayed, *job, current, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// replace up to max parallel executions at a time, and only the canaries until they are healthy
+	slots := job.Update.MaxParallel - len(unhealthy)
+	if len(healthy) < job.Update.Canary {
+		slots = math.Min(slots, job.Update.Canary-len(current))
+	}
+	replaced, _ := outdated.filterByOverSubscriptions(math.Max(0, slots))
+	replaced.markStopped(orchestrator.ExecStoppedByJobUpdateEvent(), plan)
+	return replaced, false, nil
+}
+
+// createMissingExecs creates an execution on each matching node that is not running the job.
+// Executions of previous versions of the job no longer occupy their node once they are replaced,
+// and neither do executions replaced for failing their health checks.
+func (b *DaemonJobScheduler) createMissingExecs(ctx context.Context,
+	job *models.Job, plan *models.Plan, existingExecs execSet, replaced execSet) (execSet, error) {
 	newExecs := execSet{}
 
 	// Require approval when selecting nodes, but do not require them to be connected.
@@ -96,9 +188,23 @@ func (b *DaemonJobScheduler) createMissingExecs(
 		return newExecs, err
 	}
 
-	// map for existing NodeIDs for faster lookup and filtering of existing executions
+	// map for existing NodeIDs for faster lookup and filtering of existing executions.
+	// Executions stopped by draining their node are ignored, so that the job runs on the node
+	// again once it is uncordoned.
+	drained := existingExecs.filterDrained()
+	failedHealthChecks := existingExecs.filterStoppedFor(models.ExecutionStopReasonHealthCheck)
 	existingNodes := make(map[string]struct{})
 	for _, exec := range existingExecs {
+		if drained.has(exec.ID) || failedHealthChecks.has(exec.ID) {
+			continue
+		}
+		if replaced.has(exec.ID) && exec.IsUnhealthy() {
+			continue
+		}
+		if execJobVersion(exec) < job.Version && (replaced.has(exec.ID) || exec.IsTerminalState() ||
+			exec.DesiredState.StateType == models.ExecutionDesiredStateStopped) {
+			continue
+		}
 		existingNodes[exec.NodeID] = struct{}{}
 	}
 
@@ -110,6 +216,7 @@ func (b *DaemonJobScheduler) createMissingExecs(
 		execution := &models.Execution{
 			JobID:        job.ID,
 			Job:          job,
+			JobVersion:   job.Version,
 			EvalID:       plan.EvalID,
 			ID:           idgen.ExecutionIDPrefix + uuid.NewString(),
 			Namespace:    job.Namespace,
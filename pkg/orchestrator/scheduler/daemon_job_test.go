@@ -5,7 +5,9 @@ package scheduler
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -21,6 +23,7 @@ type DaemonJobSchedulerTestSuite struct {
 	jobStore     *jobstore.MockStore
 	planner      *orchestrator.MockPlanner
 	nodeSelector *orchestrator.MockNodeSelector
+	clock        *clock.Mock
 	scheduler    *DaemonJobScheduler
 }
 
@@ -29,11 +32,14 @@ func (s *DaemonJobSchedulerTestSuite) SetupTest() {
 	s.jobStore = jobstore.NewMockStore(ctrl)
 	s.planner = orchestrator.NewMockPlanner(ctrl)
 	s.nodeSelector = orchestrator.NewMockNodeSelector(ctrl)
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Now())
 
 	s.scheduler = NewDaemonJobScheduler(DaemonJobSchedulerParams{
 		JobStore:     s.jobStore,
 		Planner:      s.planner,
 		NodeSelector: s.nodeSelector,
+		Clock:        s.clock,
 	})
 }
 
@@ -100,7 +106,7 @@ func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldMarkLostExecutionsOnUnhe
 	nodeInfos := []models.NodeInfo{
 		*fakeNodeInfo(s.T(), executions[1].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job, gomock.Any()).Return(nodeInfos, nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -114,6 +120,87 @@ func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldMarkLostExecutionsOnUnhe
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldStopExecutionsOnDrainedNodes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	executions[1].ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	// the grace period of the first node is over, and it is no longer matched as it is cordoned
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[0].NodeID),
+		*fakeNodeInfo(s.T(), executions[1].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(
+		fakeDrainingNodeStates(nodeInfos, executions[0].NodeID, s.clock.Now().Add(-time.Minute)), nil)
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job, gomock.Any()).Return(nodeInfos[1:], nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{},
+		StoppedExecutions: []string{
+			executions[0].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldRestartUnhealthyExecutionsOnTheSameNode() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	executions[0].ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+	executions[0].Health = &models.ExecutionHealth{Status: models.ExecutionUnhealthy}
+	executions[1].ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[0].NodeID),
+		*fakeNodeInfo(s.T(), executions[1].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job, gomock.Any()).Return(nodeInfos, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		JobState:                 models.JobStateTypeRunning,
+		Evaluation:               evaluation,
+		NewExecutionDesiredState: models.ExecutionDesiredStateRunning,
+		NewExecutionsNodes:       []string{nodeInfos[0].ID()},
+		StoppedExecutions:        []string{executions[0].ID},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldReplaceOutdatedExecutionsGradually() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	previous := mockJobUpdate(job, &models.UpdateStrategy{MaxParallel: 1, MinHealthyTime: 10})
+	executions[0].ModifyTime = executions[1].ModifyTime - 1 // oldest execution
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetJobVersion(gomock.Any(), job.ID, uint64(1)).Return(previous, nil).Times(1)
+
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[0].NodeID),
+		*fakeNodeInfo(s.T(), executions[1].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job, gomock.Any()).Return(nodeInfos, nil)
+
+	// only the execution on the first node is replaced, as the update replaces one node at a time
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:               evaluation,
+		NewExecutionDesiredState: models.ExecutionDesiredStateRunning,
+		NewExecutionsNodes:       []string{nodeInfos[0].ID()},
+		StoppedExecutions:        []string{executions[0].ID},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 // Even when an execution has failed, we don't mark the job as failed and continue waiting
 // for more nodes that match the job selection to join.
 // This requires a revisit in the future if all or a high percentage of nodes keep failing
@@ -129,7 +216,7 @@ func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldNOTMarkJobAsFailed() {
 	nodeInfos := []models.NodeInfo{
 		*fakeNodeInfo(s.T(), executions[1].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job, gomock.Any()).Return(nodeInfos, nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
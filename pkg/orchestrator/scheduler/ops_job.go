@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -11,27 +12,42 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 )
 
 // OpsJobScheduler is a scheduler for batch jobs that run until completion
 type OpsJobScheduler struct {
-	jobStore     jobstore.Store
-	planner      orchestrator.Planner
-	nodeSelector orchestrator.NodeSelector
+	jobStore         jobstore.Store
+	planner          orchestrator.Planner
+	nodeSelector     orchestrator.NodeSelector
+	evaluationBroker orchestrator.EvaluationBroker
+	quotaEnforcer    *quota.Enforcer
+	workflow         *orchestrator.Workflow
+	delayed          *delayedEvaluations
 }
 
 type OpsJobSchedulerParams struct {
 	JobStore     jobstore.Store
 	Planner      orchestrator.Planner
 	NodeSelector orchestrator.NodeSelector
+	// EvaluationBroker is used to re-evaluate jobs held back by their quota.
+	EvaluationBroker orchestrator.EvaluationBroker
+	// QuotaEnforcer holds back pending jobs whose namespace is over quota. Quotas are not enforced if nil.
+	QuotaEnforcer *quota.Enforcer
+	// Workflow holds back pending jobs until the jobs they depend on have completed. Dependencies are not checked if nil.
+	Workflow *orchestrator.Workflow
 }
 
 func NewOpsJobScheduler(params OpsJobSchedulerParams) *OpsJobScheduler {
 	return &OpsJobScheduler{
-		jobStore:     params.JobStore,
-		planner:      params.Planner,
-		nodeSelector: params.NodeSelector,
+		jobStore:         params.JobStore,
+		planner:          params.Planner,
+		nodeSelector:     params.NodeSelector,
+		evaluationBroker: params.EvaluationBroker,
+		quotaEnforcer:    params.QuotaEnforcer,
+		workflow:         params.Workflow,
+		delayed:          newDelayedEvaluations(),
 	}
 }
 
@@ -63,14 +79,25 @@ func (b *OpsJobScheduler) Process(ctx context.Context, evaluation *models.Evalua
 		return b.planner.Process(ctx, plan)
 	}
 
+	// keep the job pending until its dependencies have completed
+	if held, err := holdForDependencies(ctx, b.workflow, b.planner, plan, job); held || err != nil {
+		return err
+	}
+
+	// keep the job pending while its namespace is over quota
+	if held, err := holdOverQuota(
+		ctx, b.quotaEnforcer, b.delayed, b.jobStore, b.evaluationBroker, job, time.Now().UTC()); held || err != nil {
+		return err
+	}
+
 	// Retrieve the info for all the nodes that have executions for this job
-	nodeInfos, err := existingNodeInfos(ctx, b.nodeSelector, nonTerminalExecs)
+	nodeStates, err := existingNodeStates(ctx, b.nodeSelector, nonTerminalExecs)
 	if err != nil {
 		return err
 	}
 
 	// Mark executions that are running on nodes that are not healthy as failed
-	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
+	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeStates)
 	lost.markStopped(orchestrator.ExecStoppedByNodeUnhealthyEvent(), plan)
 
 	allFailed := existingExecs.filterFailed().union(lost)
@@ -119,6 +146,7 @@ func (b *OpsJobScheduler) createMissingExecs(
 		execution := &models.Execution{
 			JobID:        job.ID,
 			Job:          job,
+			JobVersion:   job.Version,
 			EvalID:       plan.EvalID,
 			ID:           idgen.ExecutionIDPrefix + uuid.NewString(),
 			Namespace:    job.Namespace,
@@ -96,7 +96,7 @@ func (s *OpsJobSchedulerTestSuite) TestProcess_ShouldMarkLostExecutionsOnUnhealt
 	nodeInfos := []models.NodeInfo{
 		*fakeNodeInfo(s.T(), executions[1].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation:         evaluation,
@@ -121,7 +121,7 @@ func (s *OpsJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed() {
 	nodeInfos := []models.NodeInfo{
 		*fakeNodeInfo(s.T(), executions[1].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation: evaluation,
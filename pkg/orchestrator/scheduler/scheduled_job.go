@@ -0,0 +1,259 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+// ScheduledJobScheduler is a scheduler for jobs that create batch job runs on a cron schedule.
+// A scheduled job never has executions of its own. Each time its schedule is due, a new batch
+// job is created from its specification and the run is recorded in the scheduled job's history.
+// The scheduler keeps itself ticking by enqueuing an evaluation that waits until the next due time.
+type ScheduledJobScheduler struct {
+	jobStore         jobstore.Store
+	planner          orchestrator.Planner
+	evaluationBroker orchestrator.EvaluationBroker
+	clock            clock.Clock
+}
+
+type ScheduledJobSchedulerParams struct {
+	JobStore         jobstore.Store
+	Planner          orchestrator.Planner
+	EvaluationBroker orchestrator.EvaluationBroker
+	// Clock is the clock used to decide when runs are due. Defaults to the system clock.
+	Clock clock.Clock
+}
+
+func NewScheduledJobScheduler(params ScheduledJobSchedulerParams) *ScheduledJobScheduler {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
+	return &ScheduledJobScheduler{
+		jobStore:         params.JobStore,
+		planner:          params.Planner,
+		evaluationBroker: params.EvaluationBroker,
+		clock:            params.Clock,
+	}
+}
+
+func (b *ScheduledJobScheduler) Process(ctx context.Context, evaluation *models.Evaluation) error {
+	ctx = log.Ctx(ctx).With().Str("JobID", evaluation.JobID).Str("EvalID", evaluation.ID).Logger().WithContext(ctx)
+
+	job, err := b.jobStore.GetJob(ctx, evaluation.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve job %s: %w", evaluation.JobID, err)
+	}
+	if job.Schedule == nil {
+		return fmt.Errorf("job %s of type %s has no schedule", job.ID, job.Type)
+	}
+
+	activeRuns, err := b.activeRuns(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	// stop the active runs if the scheduled job is stopped
+	if job.IsTerminal() {
+		return b.stopRuns(ctx, activeRuns, fmt.Sprintf("scheduled job %s was stopped", job.ID))
+	}
+
+	if job.State.StateType == models.JobStateTypePending {
+		plan := models.NewPlan(evaluation, &job)
+		plan.DesiredJobState = models.JobStateTypeRunning
+		if err = b.planner.Process(ctx, plan); err != nil {
+			return err
+		}
+		if job, err = b.jobStore.GetJob(ctx, job.ID); err != nil {
+			return fmt.Errorf("failed to retrieve job %s: %w", evaluation.JobID, err)
+		}
+	}
+
+	now := b.clock.Now().UTC()
+	lastScheduled, err := b.lastScheduledTime(ctx, job)
+	if err != nil {
+		return err
+	}
+	due, err := latestDueTime(job.Schedule, lastScheduled, now)
+	if err != nil {
+		return err
+	}
+	if !due.IsZero() {
+		if err = b.run(ctx, job, due, activeRuns); err != nil {
+			return err
+		}
+	}
+
+	// only the evaluations created on submission and by the schedule itself keep the
+	// schedule ticking, so that other evaluations do not multiply the pending ticks.
+	if evaluation.TriggeredBy != models.EvalTriggerJobRegister && evaluation.TriggeredBy != models.EvalTriggerJobSchedule {
+		return nil
+	}
+	next, err := job.Schedule.Next(now)
+	if err != nil {
+		return err
+	}
+	if next.IsZero() {
+		log.Ctx(ctx).Warn().Msgf("schedule %s of job %s will not be due again", job.Schedule, job.ID)
+		return nil
+	}
+	return b.enqueue(ctx, job, models.EvalTriggerJobSchedule, next)
+}
+
+// run creates a new run of the scheduled job that was due at the given time,
+// according to the job's concurrency policy.
+func (b *ScheduledJobScheduler) run(ctx context.Context, job models.Job, due time.Time, activeRuns []models.Job) error {
+	if len(activeRuns) > 0 {
+		switch job.Schedule.ConcurrencyPolicy {
+		case models.ConcurrencyPolicyForbid:
+			log.Ctx(ctx).Debug().Msgf("skipping run of job %s due at %s as %d runs are still active",
+				job.ID, due, len(activeRuns))
+			return b.record(ctx, job, orchestrator.JobScheduledRunSkippedEvent(due, runIDs(activeRuns)))
+		case models.ConcurrencyPolicyReplace:
+			if err := b.stopRuns(ctx, activeRuns, fmt.Sprintf("replaced by run of scheduled job %s due at %s",
+				job.ID, due.Format(time.RFC3339))); err != nil {
+				return err
+			}
+		}
+	}
+
+	run := newRun(job, due)
+	if err := b.jobStore.CreateJob(ctx, *run, orchestrator.JobCreatedByScheduleEvent(job, due)); err != nil {
+		return fmt.Errorf("failed to create run of scheduled job %s: %w", job.ID, err)
+	}
+	if err := b.enqueue(ctx, *run, models.EvalTriggerJobRegister, time.Time{}); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Debug().Msgf("created run %s of scheduled job %s due at %s", run.ID, job.ID, due)
+	return b.record(ctx, job, orchestrator.JobScheduledRunEvent(*run, due))
+}
+
+// newRun returns a batch job created from the scheduled job's specification
+func newRun(job models.Job, due time.Time) *models.Job {
+	run := job.Copy()
+	run.ID = idgen.NewJobID()
+	run.Name = fmt.Sprintf("%s-%d", job.Name, due.Unix())
+	run.Type = models.JobTypeBatch
+	run.Schedule = nil
+	run.State = models.State[models.JobStateType]{}
+	run.Version = 0
+	run.Revision = 0
+	run.Meta[models.MetaScheduledBy] = job.ID
+	run.Meta[models.MetaScheduledTime] = due.Format(time.RFC3339)
+	return run
+}
+
+// record appends an event to the history of the scheduled job, without changing its state
+func (b *ScheduledJobScheduler) record(ctx context.Context, job models.Job, event models.Event) error {
+	return b.jobStore.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
+		JobID:     job.ID,
+		Condition: jobstore.UpdateJobCondition{ExpectedState: models.JobStateTypeRunning},
+		NewState:  models.JobStateTypeRunning,
+		Event:     event,
+	})
+}
+
+// lastScheduledTime returns the due time of the most recent run recorded in the job's history,
+// whether it was created or skipped, or the creation time of the job if there was no run yet.
+func (b *ScheduledJobScheduler) lastScheduledTime(ctx context.Context, job models.Job) (time.Time, error) {
+	history, err := b.jobStore.GetJobHistory(ctx, job.ID, jobstore.JobHistoryFilterOptions{
+		ExcludeExecutionLevel: true,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to retrieve history of job %s: %w", job.ID, err)
+	}
+	last := job.GetCreateTime()
+	for _, entry := range history {
+		scheduledTime, ok := entry.Event.Details[models.DetailsKeyScheduledTime]
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, scheduledTime)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("ignoring invalid scheduled time in history of job %s", job.ID)
+			continue
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last, nil
+}
+
+// latestDueTime returns the most recent time the schedule was due after the last scheduled
+// time and up to now, or the zero time if no run is due. Runs missed while the orchestrator
+// was down are not caught up, only the latest one is.
+func latestDueTime(schedule *models.JobSchedule, lastScheduled, now time.Time) (time.Time, error) {
+	due := time.Time{}
+	for t := lastScheduled; ; {
+		next, err := schedule.Next(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.IsZero() || next.After(now) {
+			return due, nil
+		}
+		due, t = next, next
+	}
+}
+
+// activeRuns returns the runs of the scheduled job that have not reached a terminal state
+func (b *ScheduledJobScheduler) activeRuns(ctx context.Context, job models.Job) ([]models.Job, error) {
+	jobs, err := b.jobStore.GetInProgressJobs(ctx, models.JobTypeBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve active runs of job %s: %w", job.ID, err)
+	}
+	var runs []models.Job
+	for _, run := range jobs {
+		if run.Meta[models.MetaScheduledBy] == job.ID {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// stopRuns stops the given runs and enqueues an evaluation for each of them
+// so that their executions are stopped.
+func (b *ScheduledJobScheduler) stopRuns(ctx context.Context, runs []models.Job, reason string) error {
+	var mErr error
+	for _, run := range runs {
+		err := b.jobStore.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
+			JobID:     run.ID,
+			Condition: jobstore.UpdateJobCondition{ExpectedRevision: run.Revision},
+			NewState:  models.JobStateTypeStopped,
+			Event:     orchestrator.JobStoppedEvent(reason),
+		})
+		if err == nil {
+			err = b.enqueue(ctx, run, models.EvalTriggerJobCancel, time.Time{})
+		}
+		if err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("failed to stop run %s: %w", run.ID, err))
+		}
+	}
+	return mErr
+}
+
+// enqueue creates and enqueues an evaluation of the job that is ignored until the given time
+func (b *ScheduledJobScheduler) enqueue(ctx context.Context, job models.Job, triggeredBy string, waitUntil time.Time) error {
+	return enqueueEvaluation(ctx, b.jobStore, b.evaluationBroker, job, triggeredBy, waitUntil, b.clock.Now().UTC())
+}
+
+func runIDs(runs []models.Job) []string {
+	ids := make([]string, len(runs))
+	for i, run := range runs {
+		ids[i] = run.ID
+	}
+	return ids
+}
+
+// compile-time assertion that ScheduledJobScheduler satisfies the Scheduler interface
+var _ orchestrator.Scheduler = &ScheduledJobScheduler{}
@@ -0,0 +1,219 @@
+//go:build unit || !integration
+
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/planner"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ScheduledJobSchedulerTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	clock     *clock.Mock
+	jobStore  *boltjobstore.BoltJobStore
+	broker    *orchestrator.MockEvaluationBroker
+	scheduler *ScheduledJobScheduler
+	enqueued  []*models.Evaluation
+}
+
+func TestScheduledJobSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledJobSchedulerTestSuite))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC))
+
+	jobStore, err := boltjobstore.NewBoltJobStore(
+		filepath.Join(s.T().TempDir(), "test.db"), boltjobstore.WithClock(s.clock))
+	s.Require().NoError(err)
+	s.jobStore = jobStore
+
+	s.enqueued = nil
+	s.broker = orchestrator.NewMockEvaluationBroker(gomock.NewController(s.T()))
+	s.broker.EXPECT().Enqueue(gomock.Any()).AnyTimes().DoAndReturn(func(eval *models.Evaluation) error {
+		s.enqueued = append(s.enqueued, eval)
+		return nil
+	})
+
+	s.scheduler = NewScheduledJobScheduler(ScheduledJobSchedulerParams{
+		JobStore:         s.jobStore,
+		Planner:          planner.NewStateUpdater(s.jobStore),
+		EvaluationBroker: s.broker,
+		Clock:            s.clock,
+	})
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TearDownTest() {
+	s.NoError(s.jobStore.Close(s.ctx))
+}
+
+// createScheduledJob creates an hourly scheduled job and processes its registration evaluation
+func (s *ScheduledJobSchedulerTestSuite) createScheduledJob(policy string) *models.Job {
+	job := mock.Job()
+	job.Type = models.JobTypeScheduled
+	job.Schedule = &models.JobSchedule{Cron: "@hourly", ConcurrencyPolicy: policy}
+	s.Require().NoError(s.jobStore.CreateJob(s.ctx, *job, models.Event{}))
+
+	eval := s.evalFor(job, models.EvalTriggerJobRegister)
+	s.Require().NoError(s.scheduler.Process(s.ctx, eval))
+	return job
+}
+
+func (s *ScheduledJobSchedulerTestSuite) evalFor(job *models.Job, triggeredBy string) *models.Evaluation {
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.Type = job.Type
+	eval.TriggeredBy = triggeredBy
+	return eval
+}
+
+// tick advances the clock to the next hour and processes the evaluation enqueued by the schedule
+func (s *ScheduledJobSchedulerTestSuite) tick() {
+	s.Require().NotEmpty(s.enqueued)
+	eval := s.enqueued[len(s.enqueued)-1]
+	s.Require().Equal(models.EvalTriggerJobSchedule, eval.TriggeredBy)
+	s.clock.Set(eval.WaitUntil)
+	s.enqueued = nil
+	s.Require().NoError(s.scheduler.Process(s.ctx, eval))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) runs(job *models.Job) []models.Job {
+	runs, err := s.scheduler.activeRuns(s.ctx, *job)
+	s.Require().NoError(err)
+	return runs
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_RegisterSchedulesNextRun() {
+	job := s.createScheduledJob(models.ConcurrencyPolicyAllow)
+
+	stored, err := s.jobStore.GetJob(s.ctx, job.ID)
+	s.Require().NoError(err)
+	s.Equal(models.JobStateTypeRunning, stored.State.StateType)
+	s.Empty(s.runs(job))
+
+	s.Require().Len(s.enqueued, 1)
+	s.Equal(job.ID, s.enqueued[0].JobID)
+	s.Equal(models.JobTypeScheduled, s.enqueued[0].Type)
+	s.Equal(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), s.enqueued[0].WaitUntil)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_CreatesRunWhenDue() {
+	job := s.createScheduledJob(models.ConcurrencyPolicyAllow)
+	s.tick()
+
+	runs := s.runs(job)
+	s.Require().Len(runs, 1)
+	run := runs[0]
+	s.Equal(models.JobTypeBatch, run.Type)
+	s.Nil(run.Schedule)
+	s.Equal(job.ID, run.Meta[models.MetaScheduledBy])
+	s.Equal("2024-01-01T01:00:00Z", run.Meta[models.MetaScheduledTime])
+
+	// the run is enqueued along with the next tick of the schedule
+	s.Require().Len(s.enqueued, 2)
+	s.Equal(run.ID, s.enqueued[0].JobID)
+	s.Equal(models.EvalTriggerJobRegister, s.enqueued[0].TriggeredBy)
+	s.Equal(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC), s.enqueued[1].WaitUntil)
+
+	history, err := s.jobStore.GetJobHistory(s.ctx, job.ID, jobstore.JobHistoryFilterOptions{})
+	s.Require().NoError(err)
+	last := history[len(history)-1]
+	s.Equal(run.ID, last.Event.Details[models.DetailsKeyScheduledRunID])
+
+	// evaluating the job again does not create another run for the same due time
+	s.enqueued = nil
+	eval := s.evalFor(job, models.EvalTriggerExecUpdate)
+	s.Require().NoError(s.scheduler.Process(s.ctx, eval))
+	s.Len(s.runs(job), 1)
+	s.Empty(s.enqueued)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_AllowOverlappingRuns() {
+	job := s.createScheduledJob(models.ConcurrencyPolicyAllow)
+	s.tick()
+	s.tick()
+	s.Len(s.runs(job), 2)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_ForbidSkipsRun() {
+	job := s.createScheduledJob(models.ConcurrencyPolicyForbid)
+	s.tick()
+	first := s.runs(job)
+	s.Require().Len(first, 1)
+
+	s.tick()
+	runs := s.runs(job)
+	s.Require().Len(runs, 1)
+	s.Equal(first[0].ID, runs[0].ID)
+
+	history, err := s.jobStore.GetJobHistory(s.ctx, job.ID, jobstore.JobHistoryFilterOptions{})
+	s.Require().NoError(err)
+	last := history[len(history)-1]
+	s.Equal("2024-01-01T02:00:00Z", last.Event.Details[models.DetailsKeyScheduledTime])
+	s.NotContains(last.Event.Details, models.DetailsKeyScheduledRunID)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_ReplaceStopsActiveRuns() {
+	job := s.createScheduledJob(models.ConcurrencyPolicyReplace)
+	s.tick()
+	first := s.runs(job)
+	s.Require().Len(first, 1)
+
+	s.tick()
+	runs := s.runs(job)
+	s.Require().Len(runs, 1)
+	s.NotEqual(first[0].ID, runs[0].ID)
+
+	stopped, err := s.jobStore.GetJob(s.ctx, first[0].ID)
+	s.Require().NoError(err)
+	s.Equal(models.JobStateTypeStopped, stopped.State.StateType)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_StoppedJobStopsActiveRuns() {
+	job := s.createScheduledJob(models.ConcurrencyPolicyAllow)
+	s.tick()
+	s.Require().Len(s.runs(job), 1)
+
+	s.Require().NoError(s.jobStore.UpdateJobState(s.ctx, jobstore.UpdateJobStateRequest{
+		JobID:    job.ID,
+		NewState: models.JobStateTypeStopped,
+	}))
+	s.enqueued = nil
+	eval := s.evalFor(job, models.EvalTriggerJobCancel)
+	s.Require().NoError(s.scheduler.Process(s.ctx, eval))
+
+	s.Empty(s.runs(job))
+	s.Require().Len(s.enqueued, 1)
+	s.Equal(models.EvalTriggerJobCancel, s.enqueued[0].TriggeredBy)
+	s.Equal(models.JobTypeBatch, s.enqueued[0].Type)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestLatestDueTime() {
+	schedule := &models.JobSchedule{Cron: "0 * * * *"}
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	due, err := latestDueTime(schedule, last, last.Add(30*time.Minute))
+	s.Require().NoError(err)
+	s.True(due.IsZero())
+
+	// only the latest missed run is due
+	due, err = latestDueTime(schedule, last, last.Add(3*time.Hour+30*time.Minute))
+	s.Require().NoError(err)
+	s.Equal(last.Add(3*time.Hour), due)
+}
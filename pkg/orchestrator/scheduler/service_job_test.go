@@ -5,6 +5,9 @@ package scheduler
 import (
 	"context"
 	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -94,7 +97,7 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_AlreadyEnoughExecutions() {
 		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
 		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	// empty plan
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -123,7 +126,7 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_RejectExtraExecutions() {
 		*fakeNodeInfo(s.T(), executions[1].NodeID),
 		*fakeNodeInfo(s.T(), executions[2].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation:         evaluation,
@@ -150,7 +153,7 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_TooManyExecutions() {
 		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
 		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation:        evaluation,
 		StoppedExecutions: []string{executions[execServiceAskForBid].ID},
@@ -221,7 +224,7 @@ func (s *ServiceJobSchedulerTestSuite) TestFailUnhealthyExecs_ShouldMarkExecutio
 		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
 		*fakeNodeInfo(s.T(), executions[execServiceCanceled].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	s.mockNodeSelection(job, nodeInfos, 2)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -239,6 +242,265 @@ func (s *ServiceJobSchedulerTestSuite) TestFailUnhealthyExecs_ShouldMarkExecutio
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldReplaceExecutionsOnDrainingNodes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	broker := orchestrator.NewMockEvaluationBroker(gomock.NewController(s.T()))
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	deadline := clk.Now().Add(time.Minute)
+	s.scheduler = NewBatchServiceJobScheduler(BatchServiceJobSchedulerParams{
+		JobStore:         s.jobStore,
+		Planner:          s.planner,
+		NodeSelector:     s.nodeSelector,
+		RetryStrategy:    s.retryStrategy,
+		EvaluationBroker: broker,
+		Clock:            clk,
+	})
+
+	// a replacement is created straight away, while the execution is only stopped at the deadline.
+	// Evaluating the job again before the deadline does not enqueue another evaluation.
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	broker.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(eval *models.Evaluation) error {
+		s.Equal(models.EvalTriggerNodeUpdate, eval.TriggeredBy)
+		s.Equal(deadline.UTC().UnixNano(), eval.WaitUntil.UnixNano())
+		return nil
+	}).Times(1)
+	for i := 0; i < 2; i++ {
+		s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+		s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+		// the node of the first running execution is draining
+		drainingNodeID := executions[execServiceBidAccepted1].NodeID
+		s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeDrainingNodeStates([]models.NodeInfo{
+			*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+			*fakeNodeInfo(s.T(), drainingNodeID),
+			*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+		}, drainingNodeID, deadline), nil)
+		nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[4])}
+		s.mockNodeSelection(job, nodeInfos, 1)
+
+		matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+			Evaluation:         evaluation,
+			NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		})
+		s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+		s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+		clk.Add(10 * time.Second)
+	}
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldStopExecutionsOnDrainedNodes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	// the grace period of the draining node is over
+	drainingNodeID := executions[execServiceBidAccepted1].NodeID
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeDrainingNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), drainingNodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}, drainingNodeID, time.Now().Add(-time.Minute)), nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[4])}
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions:  []string{executions[execServiceBidAccepted1].ID},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldReplaceUnhealthyExecutions() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	executions[execServiceBidAccepted1].Health = &models.ExecutionHealth{Status: models.ExecutionUnhealthy}
+	executions[execServiceBidAccepted2].Health = &models.ExecutionHealth{Status: models.ExecutionHealthy}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}), nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[4])}
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	// only the execution failing its health checks is replaced
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions:  []string{executions[execServiceBidAccepted1].ID},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldReplaceOutdatedExecutionsGradually() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	previous := mockJobUpdate(job, &models.UpdateStrategy{MaxParallel: 1, MinHealthyTime: 10})
+	executions[execServiceBidAccepted1].ModifyTime = executions[execServiceAskForBid].ModifyTime - 1 // oldest execution
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetJobVersion(gomock.Any(), job.ID, uint64(1)).Return(previous, nil).Times(1)
+
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}), nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[4])}
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	// only the oldest execution is replaced, as the update replaces one execution at a time
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions:  []string{executions[execServiceBidAccepted1].ID},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldWaitForNewExecutionsToBeHealthy() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	previous := mockJobUpdate(job, &models.UpdateStrategy{MaxParallel: 1, MinHealthyTime: 10})
+	broker := orchestrator.NewMockEvaluationBroker(gomock.NewController(s.T()))
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	s.scheduler = NewBatchServiceJobScheduler(BatchServiceJobSchedulerParams{
+		JobStore:         s.jobStore,
+		Planner:          s.planner,
+		NodeSelector:     s.nodeSelector,
+		RetryStrategy:    s.retryStrategy,
+		EvaluationBroker: broker,
+		Clock:            clk,
+	})
+
+	// the execution of the new version started running a few seconds ago, and has been
+	// updated since, such as when its health was last checked
+	startTime := clk.Now().Add(-4 * time.Second)
+	executions[execServiceBidAccepted1].JobVersion = job.Version
+	executions[execServiceBidAccepted1].StartTime = startTime.UnixNano()
+	executions[execServiceBidAccepted1].ModifyTime = clk.Now().UnixNano()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil).Times(2)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil).Times(2)
+	s.jobStore.EXPECT().GetJobVersion(gomock.Any(), job.ID, uint64(1)).Return(previous, nil).Times(2)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}), nil).Times(2)
+
+	// no execution is replaced until the new one has been running for the min healthy time,
+	// when the job is evaluated again
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	broker.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(eval *models.Evaluation) error {
+		s.Equal(models.EvalTriggerJobUpdate, eval.TriggeredBy)
+		s.Equal(startTime.Add(10*time.Second).UnixNano(), eval.WaitUntil.UnixNano())
+		return nil
+	}).Times(1)
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(2)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+
+	// evaluating the job again before then does not enqueue another evaluation
+	clk.Add(time.Second)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldRunCanariesAlongsideOutdatedExecutions() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	previous := mockJobUpdate(job, &models.UpdateStrategy{MaxParallel: 1, MinHealthyTime: 10, Canary: 1})
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetJobVersion(gomock.Any(), job.ID, uint64(1)).Return(previous, nil).Times(1)
+
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}), nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[4])}
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	// the canary is created without stopping any execution of the previous version
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldRevertFailedUpdate() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	previous := mockJobUpdate(job, &models.UpdateStrategy{MaxParallel: 1, MinHealthyTime: 10, AutoRevert: true})
+	broker := orchestrator.NewMockEvaluationBroker(gomock.NewController(s.T()))
+	s.scheduler = NewBatchServiceJobScheduler(BatchServiceJobSchedulerParams{
+		JobStore:         s.jobStore,
+		Planner:          s.planner,
+		NodeSelector:     s.nodeSelector,
+		RetryStrategy:    s.retryStrategy,
+		EvaluationBroker: broker,
+	})
+
+	// the execution of the new version failed
+	executions[execServiceFailed].JobVersion = job.Version
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetJobVersion(gomock.Any(), job.ID, uint64(1)).Return(previous, nil).Times(2)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}), nil)
+
+	// the job is reverted to its previous version, which is rolled out by a new evaluation
+	s.jobStore.EXPECT().UpdateJob(gomock.Any(), previous, gomock.Any()).Return(nil)
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).Return(nil)
+	broker.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(eval *models.Evaluation) error {
+		s.Equal(models.EvalTriggerJobUpdate, eval.TriggeredBy)
+		return nil
+	})
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Times(0)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldKeepExecutionsOfUnchangedTasks() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	previous := mockJobUpdate(job, &models.UpdateStrategy{MaxParallel: 1})
+	previous.Tasks = job.Copy().Tasks
+	previous.Count = 2
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetJobVersion(gomock.Any(), job.ID, uint64(1)).Return(previous, nil).Times(1)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}), nil)
+
+	// empty plan
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 // It is a bug if a long running execution is completed. The scheduler treat those as failed executions,
 // try to reschedule, or fail the job if can no longer reschedule
 func (s *ServiceJobSchedulerTestSuite) TestProcess_TreatCompletedExecutionsAsFailed() {
@@ -257,7 +519,7 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_TreatCompletedExecutionsAsFai
 		*fakeNodeInfo(s.T(), nodeIDs[3]),
 		*fakeNodeInfo(s.T(), nodeIDs[4]),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 	s.mockNodeSelection(job, nodeInfos, 2)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -278,7 +540,7 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_NoMoreN
 	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
 
 	// mark all nodes as unhealthy so that we don't retry on other nodes
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{}, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates([]models.NodeInfo{}), nil)
 	s.mockNodeSelection(job, []models.NodeInfo{}, job.Count)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
@@ -306,7 +568,7 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_NoRetry
 		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
 		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
 	}
-	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(fakeNodeStates(nodeInfos), nil)
 
 	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
 		Evaluation: evaluation,
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/rs/zerolog/log"
 )
@@ -122,11 +124,11 @@ func (set execSet) filterByOverSubscriptions(desiredCount int) (remaining execSe
 }
 
 // filterByNodeHealth partitions executions based on their node's health status.
-func (set execSet) filterByNodeHealth(nodeInfos map[string]*models.NodeInfo) (healthy execSet, lost execSet) {
+func (set execSet) filterByNodeHealth(nodeStates map[string]*models.NodeState) (healthy execSet, lost execSet) {
 	healthy = make(execSet)
 	lost = make(execSet)
 	for _, exec := range set {
-		if _, ok := nodeInfos[exec.NodeID]; !ok {
+		if _, ok := nodeStates[exec.NodeID]; !ok {
 			lost[exec.ID] = exec
 			log.Debug().Msgf("Execution %s is running on node %s which is not healthy", exec.ID, exec.NodeID)
 		} else {
@@ -136,6 +138,68 @@ func (set execSet) filterByNodeHealth(nodeInfos map[string]*models.NodeInfo) (he
 	return healthy, lost
 }
 
+// filterByNodeDrain partitions executions based on whether their node is being drained.
+func (set execSet) filterByNodeDrain(nodeStates map[string]*models.NodeState) (remaining execSet, draining execSet) {
+	remaining = make(execSet)
+	draining = make(execSet)
+	for _, exec := range set {
+		if state, ok := nodeStates[exec.NodeID]; ok && state.Cordon != nil && state.Cordon.Drain {
+			draining[exec.ID] = exec
+		} else {
+			remaining[exec.ID] = exec
+		}
+	}
+	return remaining, draining
+}
+
+// filterByDrainDeadline returns the executions on draining nodes whose drain deadline has passed,
+// along with the earliest deadline that has not passed yet, which is zero if there is none.
+func (set execSet) filterByDrainDeadline(
+	nodeStates map[string]*models.NodeState, now time.Time) (drained execSet, nextDeadline time.Time) {
+	drained = make(execSet)
+	for _, exec := range set {
+		deadline := nodeStates[exec.NodeID].Cordon.GetDrainDeadline()
+		if !deadline.After(now) {
+			drained[exec.ID] = exec
+		} else if nextDeadline.IsZero() || deadline.Before(nextDeadline) {
+			nextDeadline = deadline
+		}
+	}
+	return drained, nextDeadline
+}
+
+// filterDrained filters executions that were stopped as the node running them was drained
+func (set execSet) filterDrained() execSet {
+	return set.filterStoppedFor(models.ExecutionStopReasonNodeDrained)
+}
+
+// filterStoppedFor filters executions that were stopped for the given reason
+func (set execSet) filterStoppedFor(reason models.ExecutionStopReason) execSet {
+	filtered := execSet{}
+	for _, exec := range set {
+		if exec.DesiredState.StateType == models.ExecutionDesiredStateStopped &&
+			exec.StopReason == reason {
+			filtered[exec.ID] = exec
+		}
+	}
+	return filtered
+}
+
+// filterByExecutionHealth partitions executions based on whether their health checks report
+// them as unhealthy. Executions without health checks are always healthy.
+func (set execSet) filterByExecutionHealth() (healthy execSet, unhealthy execSet) {
+	healthy = make(execSet)
+	unhealthy = make(execSet)
+	for _, exec := range set {
+		if exec.IsUnhealthy() {
+			unhealthy[exec.ID] = exec
+		} else {
+			healthy[exec.ID] = exec
+		}
+	}
+	return healthy, unhealthy
+}
+
 // executionsByApprovalStatus represents the different sets of executions based on their approval status.
 type executionsByApprovalStatus struct {
 	running   execSet
@@ -227,7 +291,120 @@ func (set execSet) countByState() map[models.ExecutionStateType]int {
 	return counts
 }
 
+// filterLost filters executions that were stopped as the node running them was lost
+func (set execSet) filterLost() execSet {
+	filtered := execSet{}
+	for _, exec := range set {
+		if exec.DesiredState.StateType == models.ExecutionDesiredStateStopped &&
+			exec.StopReason == models.ExecutionStopReasonNodeLost &&
+			exec.ComputeState.StateType != models.ExecutionStateFailed {
+			filtered[exec.ID] = exec
+		}
+	}
+	return filtered
+}
+
+// filterPreempted filters executions that were stopped by the compute node to make room for higher priority ones
+func (set execSet) filterPreempted() execSet {
+	filtered := execSet{}
+	for _, exec := range set {
+		if exec.DesiredState.StateType == models.ExecutionDesiredStateStopped &&
+			exec.StopReason == models.ExecutionStopReasonPreempted {
+			filtered[exec.ID] = exec
+		}
+	}
+	return filtered
+}
+
+// groupByArrayIndex groups the executions of an array job by their array index.
+func (set execSet) groupByArrayIndex() map[int]execSet {
+	groups := make(map[int]execSet)
+	for _, exec := range set {
+		if _, ok := groups[exec.ArrayIndex]; !ok {
+			groups[exec.ArrayIndex] = execSet{}
+		}
+		groups[exec.ArrayIndex][exec.ID] = exec
+	}
+	return groups
+}
+
 // countCompleted counts the number of completed executions.
 func (set execSet) countCompleted() int {
 	return set.countByState()[models.ExecutionStateCompleted]
 }
+
+// latestCheckpoint returns the execution that published the latest checkpoint, or nil
+// if none of the executions published a checkpoint.
+func (set execSet) latestCheckpoint() *models.Execution {
+	var latest *models.Execution
+	for _, exec := range set {
+		if exec.Checkpoint == nil {
+			continue
+		}
+		if latest == nil || exec.Checkpoint.CreateTime > latest.Checkpoint.CreateTime {
+			latest = exec
+		}
+	}
+	return latest
+}
+
+// filterByJobVersion partitions executions based on whether they run a version of the job
+// that is older than the given one.
+func (set execSet) filterByJobVersion(version uint64) (current execSet, previous execSet) {
+	current = make(execSet)
+	previous = make(execSet)
+	for _, exec := range set {
+		if execJobVersion(exec) < version {
+			previous[exec.ID] = exec
+		} else {
+			current[exec.ID] = exec
+		}
+	}
+	return current, previous
+}
+
+// filterByMinHealthyTime partitions executions based on whether they have been running for the
+// given time since they started, along with the earliest time one of the unhealthy executions can
+// become healthy. Executions that are not running yet can become healthy at the earliest after the
+// given time from now.
+func (set execSet) filterByMinHealthyTime(
+	minHealthyTime time.Duration, now time.Time) (healthy execSet, unhealthy execSet, nextHealthy time.Time) {
+	healthy = make(execSet)
+	unhealthy = make(execSet)
+	for _, exec := range set {
+		running := exec.ComputeState.StateType == models.ExecutionStateBidAccepted
+		healthyAt := now.Add(minHealthyTime)
+		if running {
+			// executions that started before their start time was recorded fall back to their last update
+			startTime := exec.GetStartTime()
+			if startTime.IsZero() {
+				startTime = exec.GetModifyTime()
+			}
+			healthyAt = startTime.Add(minHealthyTime)
+		}
+		if running && !healthyAt.After(now) {
+			healthy[exec.ID] = exec
+			continue
+		}
+		unhealthy[exec.ID] = exec
+		if nextHealthy.IsZero() || healthyAt.Before(nextHealthy) {
+			nextHealthy = healthyAt
+		}
+	}
+	return healthy, unhealthy, nextHealthy
+}
+
+// latestJobVersion returns the latest version of the job run by the executions
+func (set execSet) latestJobVersion() uint64 {
+	var latest uint64
+	for _, exec := range set {
+		latest = math.Max(latest, execJobVersion(exec))
+	}
+	return latest
+}
+
+// execJobVersion returns the version of the job run by an execution. Executions created
+// before job versions were tracked run the first version of their job.
+func execJobVersion(exec *models.Execution) uint64 {
+	return math.Max(exec.JobVersion, 1)
+}
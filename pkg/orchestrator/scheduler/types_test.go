@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -153,7 +154,7 @@ func TestExecSet_has(t *testing.T) {
 }
 
 func TestExecSet_FilterByNodeHealth(t *testing.T) {
-	nodeInfos := map[string]*models.NodeInfo{
+	nodeStates := map[string]*models.NodeState{
 		"node1": {},
 		"node2": {},
 	}
@@ -165,7 +166,7 @@ func TestExecSet_FilterByNodeHealth(t *testing.T) {
 	}
 
 	set := execSetFromSlice(executions)
-	healthy, lost := set.filterByNodeHealth(nodeInfos)
+	healthy, lost := set.filterByNodeHealth(nodeStates)
 
 	assert.Len(t, healthy, 2)
 	assert.Len(t, lost, 1)
@@ -213,3 +214,63 @@ func TestExecSet_FilterByApprovalStatus(t *testing.T) {
 	assert.ElementsMatch(t, approvalStatus.pending.keys(), []string{})
 	assert.Equal(t, 3, approvalStatus.activeCount())
 }
+
+func TestExecSet_FilterByMinHealthyTime(t *testing.T) {
+	now := time.Now()
+	minHealthyTime := 10 * time.Second
+
+	executions := []*models.Execution{
+		// started long ago, but updated recently
+		{ID: "exec1", ComputeState: models.NewExecutionState(models.ExecutionStateBidAccepted),
+			StartTime: now.Add(-time.Minute).UnixNano(), ModifyTime: now.UnixNano()},
+		// started recently
+		{ID: "exec2", ComputeState: models.NewExecutionState(models.ExecutionStateBidAccepted),
+			StartTime: now.Add(-4 * time.Second).UnixNano(), ModifyTime: now.Add(-time.Minute).UnixNano()},
+		// started before start times were recorded
+		{ID: "exec3", ComputeState: models.NewExecutionState(models.ExecutionStateBidAccepted),
+			ModifyTime: now.Add(-time.Minute).UnixNano()},
+		// not started yet
+		{ID: "exec4", ComputeState: models.NewExecutionState(models.ExecutionStateAskForBid),
+			ModifyTime: now.Add(-time.Minute).UnixNano()},
+	}
+
+	healthy, unhealthy, nextHealthy := execSetFromSlice(executions).filterByMinHealthyTime(minHealthyTime, now)
+	assert.ElementsMatch(t, []string{"exec1", "exec3"}, healthy.keys())
+	assert.ElementsMatch(t, []string{"exec2", "exec4"}, unhealthy.keys())
+	assert.True(t, now.Add(6*time.Second).Equal(nextHealthy))
+}
+
+func TestExecSet_FilterLostAndPreempted(t *testing.T) {
+	stopped := models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped)
+	executions := []*models.Execution{
+		{ID: "lost", DesiredState: stopped, StopReason: models.ExecutionStopReasonNodeLost,
+			ComputeState: models.NewExecutionState(models.ExecutionStateCancelled)},
+		{ID: "lost-failed", DesiredState: stopped, StopReason: models.ExecutionStopReasonNodeLost,
+			ComputeState: models.NewExecutionState(models.ExecutionStateFailed)},
+		{ID: "preempted", DesiredState: stopped, StopReason: models.ExecutionStopReasonPreempted,
+			ComputeState: models.NewExecutionState(models.ExecutionStateFailed)},
+		// executions are matched by their stop reason rather than the message of their desired state
+		{ID: "message-only", DesiredState: stopped.WithMessage(orchestrator.ExecPreemptedEvent().Message),
+			ComputeState: models.NewExecutionState(models.ExecutionStateFailed)},
+	}
+
+	set := execSetFromSlice(executions)
+	assert.ElementsMatch(t, []string{"lost"}, set.filterLost().keys())
+	assert.ElementsMatch(t, []string{"preempted"}, set.filterPreempted().keys())
+}
+
+func TestExecSet_FilterDrained(t *testing.T) {
+	stopped := models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped)
+	executions := []*models.Execution{
+		{ID: "drained", DesiredState: stopped, StopReason: models.ExecutionStopReasonNodeDrained},
+		{ID: "health-check", DesiredState: stopped, StopReason: models.ExecutionStopReasonHealthCheck},
+		{ID: "running", DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStateRunning),
+			StopReason: models.ExecutionStopReasonNodeDrained},
+		// executions are matched by their stop reason rather than the message of their desired state
+		{ID: "message-only", DesiredState: stopped.WithMessage(orchestrator.ExecStoppedByNodeDrainEvent().Message)},
+	}
+
+	set := execSetFromSlice(executions)
+	assert.ElementsMatch(t, []string{"drained"}, set.filterDrained().keys())
+	assert.ElementsMatch(t, []string{"health-check"}, set.filterStoppedFor(models.ExecutionStopReasonHealthCheck).keys())
+}
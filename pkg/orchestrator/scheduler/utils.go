@@ -2,17 +2,148 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
 )
 
-// existingNodeInfos returns a map of nodeID to NodeInfo for all the nodes that have executions for this job
-func existingNodeInfos(ctx context.Context,
+// enqueueEvaluation creates and enqueues an evaluation of the job that is ignored until the given time
+func enqueueEvaluation(ctx context.Context,
+	jobStore jobstore.Store,
+	evaluationBroker orchestrator.EvaluationBroker,
+	job models.Job, triggeredBy string, waitUntil time.Time, now time.Time) error {
+	eval := &models.Evaluation{
+		ID:          uuid.NewString(),
+		Namespace:   job.Namespace,
+		JobID:       job.ID,
+		TriggeredBy: triggeredBy,
+		Priority:    job.Priority,
+		Type:        job.Type,
+		Status:      models.EvalStatusPending,
+		WaitUntil:   waitUntil,
+		CreateTime:  now.UnixNano(),
+		ModifyTime:  now.UnixNano(),
+	}
+	if err := jobStore.CreateEvaluation(ctx, *eval); err != nil {
+		return fmt.Errorf("failed to create evaluation for job %s: %w", job.ID, err)
+	}
+	return evaluationBroker.Enqueue(eval)
+}
+
+// delayedEvaluations keeps track of the delayed evaluations enqueued for jobs, so that jobs
+// evaluated again before their delayed evaluation is due do not enqueue another one.
+type delayedEvaluations struct {
+	mu sync.Mutex
+	// due is when the pending delayed evaluation of each job and trigger is due
+	due map[delayedEvaluationKey]time.Time
+}
+
+type delayedEvaluationKey struct {
+	jobID       string
+	triggeredBy string
+}
+
+func newDelayedEvaluations() *delayedEvaluations {
+	return &delayedEvaluations{due: make(map[delayedEvaluationKey]time.Time)}
+}
+
+// enqueue creates and enqueues an evaluation of the job that is ignored until the given time,
+// unless an evaluation of the job with the same trigger is already pending and due by then.
+func (d *delayedEvaluations) enqueue(ctx context.Context,
+	jobStore jobstore.Store,
+	evaluationBroker orchestrator.EvaluationBroker,
+	job models.Job, triggeredBy string, waitUntil time.Time, now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// forget the evaluations that are already due
+	for key, due := range d.due {
+		if !due.After(now) {
+			delete(d.due, key)
+		}
+	}
+
+	key := delayedEvaluationKey{jobID: job.ID, triggeredBy: triggeredBy}
+	if due, pending := d.due[key]; pending && !due.After(waitUntil) {
+		log.Ctx(ctx).Trace().Msgf("evaluation of job %s is already delayed until %s", job.ID, due)
+		return nil
+	}
+	if err := enqueueEvaluation(ctx, jobStore, evaluationBroker, job, triggeredBy, waitUntil, now); err != nil {
+		return err
+	}
+	d.due[key] = waitUntil
+	return nil
+}
+
+// holdOverQuota keeps a pending job from being scheduled while it does not fit within the quota
+// of its namespace, and enqueues an evaluation to check the job again later. It returns true if
+// the job is held back, and otherwise admits the job so that it is counted against the quota
+// before it is running. Jobs that are already running are never held back.
+func holdOverQuota(ctx context.Context,
+	enforcer *quota.Enforcer,
+	delayed *delayedEvaluations,
+	jobStore jobstore.Store,
+	evaluationBroker orchestrator.EvaluationBroker,
+	job models.Job, now time.Time) (bool, error) {
+	if enforcer == nil || job.State.StateType != models.JobStateTypePending {
+		return false, nil
+	}
+	reasons, err := enforcer.Admit(ctx, job)
+	if err != nil {
+		return false, fmt.Errorf("failed to check quota of namespace %s: %w", job.Namespace, err)
+	}
+	if len(reasons) == 0 {
+		return false, nil
+	}
+	log.Ctx(ctx).Debug().Strs("Reasons", reasons).
+		Msgf("holding job %s pending as namespace %s is over quota", job.ID, job.Namespace)
+	return true, delayed.enqueue(ctx, jobStore, evaluationBroker, job,
+		models.EvalTriggerJobQuota, now.Add(enforcer.RetryInterval()), now)
+}
+
+// holdForDependencies keeps a pending job from being scheduled until all the jobs it depends on
+// have completed, and fails the job if one of them did not complete. It returns true if the job
+// is held back or failed. Held jobs are evaluated again by the workflow once their dependencies
+// are done, and jobs that are already running are never held back.
+func holdForDependencies(ctx context.Context,
+	workflow *orchestrator.Workflow,
+	planner orchestrator.Planner,
+	plan *models.Plan,
+	job models.Job) (bool, error) {
+	if workflow == nil || !job.HasDependencies() || job.State.StateType != models.JobStateTypePending {
+		return false, nil
+	}
+	status, err := workflow.Status(ctx, job)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case status.Failed != nil:
+		plan.MarkJobFailed(orchestrator.JobDependencyFailedEvent(*status.Failed))
+		return true, planner.Process(ctx, plan)
+	case !status.Ready():
+		log.Ctx(ctx).Debug().Strs("PendingDependencies", status.Pending).
+			Msgf("holding job %s pending until its dependencies have completed", job.ID)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// existingNodeStates returns a map of nodeID to NodeState for all the nodes that have executions for this job
+func existingNodeStates(ctx context.Context,
 	nodeSelector orchestrator.NodeSelector,
-	existingExecutions execSet) (map[string]*models.NodeInfo, error) {
-	out := make(map[string]*models.NodeInfo)
+	existingExecutions execSet) (map[string]*models.NodeState, error) {
+	out := make(map[string]*models.NodeState)
 	if len(existingExecutions) == 0 {
 		return out, nil
 	}
@@ -20,13 +151,13 @@ func existingNodeInfos(ctx context.Context,
 
 	// TODO: implement a better way to retrieve node info instead of listing all nodes
 	//  Also we should detect if a node is still available, but does not support the job constraints any longer.
-	nodesMap := make(map[string]*models.NodeInfo)
+	nodesMap := make(map[string]*models.NodeState)
 	discoveredNodes, err := nodeSelector.AllNodes(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 	for i, node := range discoveredNodes {
-		nodesMap[node.ID()] = &discoveredNodes[i]
+		nodesMap[node.Info.ID()] = &discoveredNodes[i]
 	}
 
 	for _, execution := range existingExecutions {
@@ -35,11 +166,118 @@ func existingNodeInfos(ctx context.Context,
 		if _, ok := checked[execution.NodeID]; ok {
 			continue
 		}
-		nodeInfo, ok := nodesMap[execution.NodeID]
+		nodeState, ok := nodesMap[execution.NodeID]
 		if ok {
-			out[execution.NodeID] = nodeInfo
+			out[execution.NodeID] = nodeState
 		}
 		checked[execution.NodeID] = struct{}{}
 	}
 	return out, nil
 }
+
+// drainExecs stops the executions of a long-running job that run on draining nodes once the
+// drain deadline of their node passes, and enqueues an evaluation of the job for the next
+// deadline. It returns the executions that do not run on draining nodes, so that the ones
+// that do are replaced on other nodes right away.
+func drainExecs(ctx context.Context,
+	jobStore jobstore.Store,
+	evaluationBroker orchestrator.EvaluationBroker,
+	delayed *delayedEvaluations,
+	job models.Job, plan *models.Plan,
+	execs execSet, nodeStates map[string]*models.NodeState, now time.Time) (execSet, error) {
+	remaining, draining := execs.filterByNodeDrain(nodeStates)
+	drained, nextDeadline := draining.filterByDrainDeadline(nodeStates, now)
+	drained.markStopped(orchestrator.ExecStoppedByNodeDrainEvent(), plan)
+	if !nextDeadline.IsZero() {
+		log.Ctx(ctx).Debug().Msgf("delaying stop of job %s executions on draining nodes until %s", job.ID, nextDeadline)
+		if err := delayed.enqueue(ctx, jobStore, evaluationBroker, job,
+			models.EvalTriggerNodeUpdate, nextDeadline, now); err != nil {
+			return nil, err
+		}
+	}
+	return remaining, nil
+}
+
+// outdatedExecs partitions the executions of a long-running job between the ones that run its
+// current version, and the outdated ones that run a previous version and must be replaced.
+// Executions of previous versions whose tasks do not differ from the current version keep
+// running, and executions already requested to stop are ignored as they are being replaced.
+func outdatedExecs(ctx context.Context,
+	jobStore jobstore.Store,
+	job models.Job, execs execSet) (current execSet, outdated execSet, err error) {
+	current, previous := execs.filterByJobVersion(job.Version)
+	outdated = make(execSet)
+	replace := make(map[uint64]bool)
+	for _, exec := range previous {
+		if exec.DesiredState.StateType == models.ExecutionDesiredStateStopped {
+			continue
+		}
+		version := execJobVersion(exec)
+		if _, ok := replace[version]; !ok {
+			previousJob, err := jobStore.GetJobVersion(ctx, job.ID, version)
+			if errors.As(err, &jobstore.ErrJobVersionNotFound{}) {
+				replace[version] = true
+			} else if err != nil {
+				return nil, nil, fmt.Errorf("failed to retrieve version %d of job %s: %w", version, job.ID, err)
+			} else {
+				replace[version] = job.RequiresReplacement(&previousJob)
+			}
+		}
+		if replace[version] {
+			outdated[exec.ID] = exec
+		} else {
+			current[exec.ID] = exec
+		}
+	}
+	return current, outdated, nil
+}
+
+// awaitHealthy partitions the executions of the current version of a job being updated based on
+// whether they are healthy, and enqueues an evaluation of the job for when the next one can
+// become healthy, so that the update carries on.
+func awaitHealthy(ctx context.Context,
+	jobStore jobstore.Store,
+	evaluationBroker orchestrator.EvaluationBroker,
+	delayed *delayedEvaluations,
+	job models.Job, current execSet, now time.Time) (healthy execSet, unhealthy execSet, err error) {
+	healthy, unhealthy, nextHealthy := current.filterByMinHealthyTime(job.Update.GetMinHealthyTime(), now)
+	if nextHealthy.After(now) {
+		log.Ctx(ctx).Debug().Msgf("delaying update of job %s until %s", job.ID, nextHealthy)
+		if err = delayed.enqueue(ctx, jobStore, evaluationBroker, job,
+			models.EvalTriggerJobUpdate, nextHealthy, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	return healthy, unhealthy, nil
+}
+
+// failedUpdate returns true if the update of a job should be reverted, which is the case if its
+// update strategy reverts failed updates and an execution of its current version failed.
+func failedUpdate(job models.Job, existingExecs execSet) bool {
+	if job.Update == nil || !job.Update.AutoRevert {
+		return false
+	}
+	for _, exec := range existingExecs.filterFailed() {
+		if execJobVersion(exec) == job.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// revertUpdate reverts a job whose update failed to the given previous version, which becomes
+// a new version of the job, and enqueues an evaluation of the job to roll out the reverted version.
+func revertUpdate(ctx context.Context,
+	jobStore jobstore.Store,
+	evaluationBroker orchestrator.EvaluationBroker,
+	job models.Job, version uint64, now time.Time) error {
+	previous, err := jobStore.GetJobVersion(ctx, job.ID, version)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve version %d of job %s: %w", version, job.ID, err)
+	}
+	log.Ctx(ctx).Info().Msgf("reverting job %s from version %d to version %d", job.ID, job.Version, version)
+	if err = jobStore.UpdateJob(ctx, previous, orchestrator.JobRevertedEvent(job.Version, version)); err != nil {
+		return fmt.Errorf("failed to revert job %s to version %d: %w", job.ID, version, err)
+	}
+	return enqueueEvaluation(ctx, jobStore, evaluationBroker, job, models.EvalTriggerJobUpdate, time.Time{}, now)
+}
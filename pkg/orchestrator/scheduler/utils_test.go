@@ -5,6 +5,7 @@ package scheduler
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -125,3 +126,39 @@ func fakeNodeInfo(t *testing.T, nodeID string) *models.NodeInfo {
 		NodeID: nodeID,
 	}
 }
+
+// fakeNodeStates returns the states of approved and connected nodes with the given infos
+// fakeDrainingNodeStates returns healthy node states, with the node of the given id draining
+// until the given deadline
+func fakeDrainingNodeStates(nodeInfos []models.NodeInfo, nodeID string, deadline time.Time) []models.NodeState {
+	nodeStates := fakeNodeStates(nodeInfos)
+	for i := range nodeStates {
+		if nodeStates[i].Info.ID() == nodeID {
+			nodeStates[i].Cordon = &models.NodeCordon{Drain: true, DrainDeadline: deadline.UnixNano()}
+		}
+	}
+	return nodeStates
+}
+
+func fakeNodeStates(nodeInfos []models.NodeInfo) []models.NodeState {
+	nodeStates := make([]models.NodeState, 0, len(nodeInfos))
+	for _, info := range nodeInfos {
+		nodeStates = append(nodeStates, models.NodeState{
+			Info:       info,
+			Membership: models.NodeMembership.APPROVED,
+			Connection: models.NodeStates.CONNECTED,
+		})
+	}
+	return nodeStates
+}
+
+// mockJobUpdate updates the job to its second version with the given update strategy, and
+// returns its first version, whose task differs so that its executions must be replaced.
+func mockJobUpdate(job *models.Job, update *models.UpdateStrategy) models.Job {
+	previous := job.Copy()
+	previous.Task().Name = "previous"
+	job.Version = 2
+	job.State = models.NewJobState(models.JobStateTypeRunning)
+	job.Update = update
+	return *previous
+}
@@ -0,0 +1,148 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// BucketSecrets is the bolt bucket holding the secrets, with a nested bucket per namespace
+// holding its secrets keyed by name.
+const BucketSecrets = "secrets"
+
+// storedSecret is a secret as persisted in BoltDB, with its value encrypted
+type storedSecret struct {
+	Secret         models.Secret `json:"Secret"`
+	EncryptedValue []byte        `json:"EncryptedValue"`
+}
+
+// BoltStore is a Store that persists the secrets in BoltDB, which is expected to be the
+// database of the jobstore so that secrets survive restarts of the requester. The values
+// of the secrets are encrypted before they are written to the database.
+type BoltStore struct {
+	database *bolt.DB
+	cipher   *Cipher
+}
+
+// NewBoltStore creates a new secret store persisted in the provided bolt database, and
+// encrypting the values of the secrets with the provided cipher.
+func NewBoltStore(database *bolt.DB, cipher *Cipher) (*BoltStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	if cipher == nil {
+		return nil, errors.New("cipher is required")
+	}
+	err := database.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketSecrets))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets bucket: %w", err)
+	}
+	return &BoltStore{database: database, cipher: cipher}, nil
+}
+
+func (s *BoltStore) Get(_ context.Context, namespace string, name string) (secret models.Secret, err error) {
+	var stored storedSecret
+	err = s.database.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(BucketSecrets)).Bucket([]byte(namespace))
+		if bkt == nil {
+			return NewErrSecretNotFound(namespace, name)
+		}
+		data := bkt.Get([]byte(name))
+		if data == nil {
+			return NewErrSecretNotFound(namespace, name)
+		}
+		return json.Unmarshal(data, &stored)
+	})
+	if err != nil {
+		return secret, err
+	}
+	value, err := s.cipher.Decrypt(stored.EncryptedValue)
+	if err != nil {
+		return secret, err
+	}
+	secret = stored.Secret
+	secret.Value = string(value)
+	return secret, nil
+}
+
+func (s *BoltStore) List(_ context.Context, namespace string) ([]models.Secret, error) {
+	secrets := make([]models.Secret, 0)
+	listNamespace := func(bkt *bolt.Bucket) error {
+		// keys are iterated in byte order, so the secrets are sorted by name
+		return bkt.ForEach(func(_, v []byte) error {
+			var stored storedSecret
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return err
+			}
+			secrets = append(secrets, stored.Secret)
+			return nil
+		})
+	}
+	err := s.database.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(BucketSecrets))
+		if namespace != "" {
+			bkt := root.Bucket([]byte(namespace))
+			if bkt == nil {
+				return nil
+			}
+			return listNamespace(bkt)
+		}
+		return root.ForEachBucket(func(k []byte) error {
+			return listNamespace(root.Bucket(k))
+		})
+	})
+	return secrets, err
+}
+
+func (s *BoltStore) Put(_ context.Context, secret models.Secret) error {
+	if err := secret.Validate(); err != nil {
+		return err
+	}
+	encrypted, err := s.cipher.Encrypt([]byte(secret.Value))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(storedSecret{
+		Secret:         *secret.Redacted(),
+		EncryptedValue: encrypted,
+	})
+	if err != nil {
+		return err
+	}
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.Bucket([]byte(BucketSecrets)).CreateBucketIfNotExists([]byte(secret.Namespace))
+		if err != nil {
+			return err
+		}
+		return bkt.Put([]byte(secret.Name), data)
+	})
+}
+
+func (s *BoltStore) Delete(_ context.Context, namespace string, name string) error {
+	return s.database.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket([]byte(BucketSecrets))
+		bkt := root.Bucket([]byte(namespace))
+		if bkt == nil || bkt.Get([]byte(name)) == nil {
+			return NewErrSecretNotFound(namespace, name)
+		}
+		if err := bkt.Delete([]byte(name)); err != nil {
+			return err
+		}
+		// drop the bucket of the namespace with its last secret
+		if k, _ := bkt.Cursor().First(); k == nil {
+			return root.DeleteBucket([]byte(namespace))
+		}
+		return nil
+	})
+}
+
+// compile-time check that BoltStore implements the Store interface
+var _ Store = (*BoltStore)(nil)
@@ -0,0 +1,90 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// KeySize is the size in bytes of the keys used to encrypt secrets, selecting AES-256
+	KeySize = 32
+
+	keyFilePerm = 0600
+	keyDirPerm  = 0700
+)
+
+// Cipher encrypts and decrypts the values of secrets with AES-GCM.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a key of KeySize bytes.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid secrets key size %d: must be %d bytes", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt returns the encrypted value prefixed with the random nonce used to encrypt it.
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt returns the value that was encrypted by Encrypt.
+func (c *Cipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("encrypted secret is too short")
+	}
+	plaintext, err := c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return plaintext, nil
+}
+
+// LoadOrCreateKey reads the key used to encrypt secrets from the file, and generates a new
+// random key readable only by the current user if the file does not exist.
+func LoadOrCreateKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("invalid secrets key in %s: must be %d bytes", path, KeySize)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read secrets key: %w", err)
+	}
+
+	key = make([]byte, KeySize)
+	if _, err = io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate secrets key: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), keyDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create secrets key directory: %w", err)
+	}
+	if err = os.WriteFile(path, key, keyFilePerm); err != nil {
+		return nil, fmt.Errorf("failed to write secrets key: %w", err)
+	}
+	return key, nil
+}
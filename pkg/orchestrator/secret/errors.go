@@ -0,0 +1,17 @@
+package secret
+
+import "fmt"
+
+// ErrSecretNotFound is returned when a namespace has no secret with the requested name
+type ErrSecretNotFound struct {
+	Namespace string
+	Name      string
+}
+
+func NewErrSecretNotFound(namespace string, name string) ErrSecretNotFound {
+	return ErrSecretNotFound{Namespace: namespace, Name: name}
+}
+
+func (e ErrSecretNotFound) Error() string {
+	return fmt.Sprintf("secret %s not found in namespace: %s", e.Name, e.Namespace)
+}
@@ -0,0 +1,82 @@
+package secret
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// InMemoryStore is a Store that keeps the secrets in memory, and loses them on restart.
+type InMemoryStore struct {
+	// secrets is keyed by namespace and then by name
+	secrets map[string]map[string]models.Secret
+	mu      sync.RWMutex
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		secrets: make(map[string]map[string]models.Secret),
+	}
+}
+
+func (s *InMemoryStore) Get(_ context.Context, namespace string, name string) (models.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	secret, ok := s.secrets[namespace][name]
+	if !ok {
+		return models.Secret{}, NewErrSecretNotFound(namespace, name)
+	}
+	return secret, nil
+}
+
+func (s *InMemoryStore) List(_ context.Context, namespace string) ([]models.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	secrets := make([]models.Secret, 0)
+	for ns, namespaceSecrets := range s.secrets {
+		if namespace != "" && ns != namespace {
+			continue
+		}
+		for _, secret := range namespaceSecrets {
+			secrets = append(secrets, *secret.Redacted())
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].Namespace != secrets[j].Namespace {
+			return secrets[i].Namespace < secrets[j].Namespace
+		}
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets, nil
+}
+
+func (s *InMemoryStore) Put(_ context.Context, secret models.Secret) error {
+	if err := secret.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.secrets[secret.Namespace]; !ok {
+		s.secrets[secret.Namespace] = make(map[string]models.Secret)
+	}
+	s.secrets[secret.Namespace][secret.Name] = secret
+	return nil
+}
+
+func (s *InMemoryStore) Delete(_ context.Context, namespace string, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.secrets[namespace][name]; !ok {
+		return NewErrSecretNotFound(namespace, name)
+	}
+	delete(s.secrets[namespace], name)
+	if len(s.secrets[namespace]) == 0 {
+		delete(s.secrets, namespace)
+	}
+	return nil
+}
+
+// compile-time check that InMemoryStore implements the Store interface
+var _ Store = (*InMemoryStore)(nil)
@@ -0,0 +1,28 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Resolve returns the values of the secrets referenced by a task, keyed by secret name.
+// It fails if any of the secrets does not exist in the namespace.
+func Resolve(ctx context.Context, store Store, namespace string, references []*models.SecretReference) (
+	map[string]string, error) {
+	if len(references) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(references))
+	for _, reference := range references {
+		if _, ok := values[reference.Name]; ok {
+			continue
+		}
+		secret, err := store.Get(ctx, namespace, reference.Name)
+		if err != nil {
+			return nil, err
+		}
+		values[reference.Name] = secret.Value
+	}
+	return values, nil
+}
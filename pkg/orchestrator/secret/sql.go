@@ -0,0 +1,127 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// SQLStore is a Store that persists the secrets in the database of the SQL jobstore, so
+// that secrets survive restarts of the requester. The values of the secrets are encrypted
+// before they are written to the database.
+type SQLStore struct {
+	database *sqljobstore.Database
+	cipher   *Cipher
+}
+
+// NewSQLStore creates a new secret store persisted in the provided database, and
+// encrypting the values of the secrets with the provided cipher.
+func NewSQLStore(ctx context.Context, database *sqljobstore.Database, cipher *Cipher) (*SQLStore, error) {
+	if database == nil {
+		return nil, errors.New("database is required")
+	}
+	if cipher == nil {
+		return nil, errors.New("cipher is required")
+	}
+	_, err := database.Exec(ctx, `CREATE TABLE IF NOT EXISTS secrets (
+		namespace TEXT NOT NULL,
+		name      TEXT NOT NULL,
+		data      `+database.JSONType()+` NOT NULL,
+		PRIMARY KEY (namespace, name)
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets table: %w", err)
+	}
+	return &SQLStore{database: database, cipher: cipher}, nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, namespace string, name string) (secret models.Secret, err error) {
+	var data string
+	err = s.database.QueryRow(ctx,
+		`SELECT data FROM secrets WHERE namespace = ? AND name = ?`, namespace, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return secret, NewErrSecretNotFound(namespace, name)
+	}
+	if err != nil {
+		return secret, err
+	}
+	var stored storedSecret
+	if err = json.Unmarshal([]byte(data), &stored); err != nil {
+		return secret, err
+	}
+	value, err := s.cipher.Decrypt(stored.EncryptedValue)
+	if err != nil {
+		return secret, err
+	}
+	secret = stored.Secret
+	secret.Value = string(value)
+	return secret, nil
+}
+
+func (s *SQLStore) List(ctx context.Context, namespace string) ([]models.Secret, error) {
+	query := `SELECT data FROM secrets ORDER BY namespace, name`
+	var args []interface{}
+	if namespace != "" {
+		query = `SELECT data FROM secrets WHERE namespace = ? ORDER BY name`
+		args = append(args, namespace)
+	}
+	rows, err := s.database.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	secrets := make([]models.Secret, 0)
+	for rows.Next() {
+		var data string
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var stored storedSecret
+		if err = json.Unmarshal([]byte(data), &stored); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, stored.Secret)
+	}
+	return secrets, rows.Err()
+}
+
+func (s *SQLStore) Put(ctx context.Context, secret models.Secret) error {
+	if err := secret.Validate(); err != nil {
+		return err
+	}
+	encrypted, err := s.cipher.Encrypt([]byte(secret.Value))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(storedSecret{
+		Secret:         *secret.Redacted(),
+		EncryptedValue: encrypted,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.database.Exec(ctx, `INSERT INTO secrets (namespace, name, data) VALUES (?, ?, ?)
+		ON CONFLICT (namespace, name) DO UPDATE SET data = excluded.data`, secret.Namespace, secret.Name, string(data))
+	return err
+}
+
+func (s *SQLStore) Delete(ctx context.Context, namespace string, name string) error {
+	result, err := s.database.Exec(ctx, `DELETE FROM secrets WHERE namespace = ? AND name = ?`, namespace, name)
+	if err != nil {
+		return err
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return NewErrSecretNotFound(namespace, name)
+	}
+	return nil
+}
+
+// compile-time check that SQLStore implements the Store interface
+var _ Store = (*SQLStore)(nil)
@@ -0,0 +1,173 @@
+//go:build unit || !integration
+
+package secret
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+	_ "modernc.org/sqlite"
+
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type StoreTestSuite struct {
+	suite.Suite
+	newStore func() Store
+	store    Store
+}
+
+func TestInMemoryStoreTestSuite(t *testing.T) {
+	suite.Run(t, &StoreTestSuite{newStore: func() Store { return NewInMemoryStore() }})
+}
+
+func TestBoltStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		database, err := bolt.Open(filepath.Join(s.T().TempDir(), "secrets.db"), 0600, nil)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = database.Close() })
+		key, err := LoadOrCreateKey(filepath.Join(s.T().TempDir(), "secrets.key"))
+		s.Require().NoError(err)
+		cipher, err := NewCipher(key)
+		s.Require().NoError(err)
+		store, err := NewBoltStore(database, cipher)
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func TestSQLStoreTestSuite(t *testing.T) {
+	s := &StoreTestSuite{}
+	s.newStore = func() Store {
+		ctx := context.Background()
+		jobStore, err := sqljobstore.NewSQLJobStore(ctx, sqljobstore.DriverSQLite, ":memory:")
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = jobStore.Close(ctx) })
+		key, err := LoadOrCreateKey(filepath.Join(s.T().TempDir(), "secrets.key"))
+		s.Require().NoError(err)
+		cipher, err := NewCipher(key)
+		s.Require().NoError(err)
+		store, err := NewSQLStore(ctx, jobStore.Database(), cipher)
+		s.Require().NoError(err)
+		return store
+	}
+	suite.Run(t, s)
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.store = s.newStore()
+}
+
+func (s *StoreTestSuite) TestPutGetDelete() {
+	ctx := context.Background()
+	secret := models.Secret{Namespace: "team-a", Name: "db-password", Value: "hunter2"}
+	s.Require().NoError(s.store.Put(ctx, secret))
+
+	stored, err := s.store.Get(ctx, "team-a", "db-password")
+	s.Require().NoError(err)
+	s.Equal(secret, stored)
+
+	// put replaces the existing secret
+	secret.Value = "correct-horse"
+	s.Require().NoError(s.store.Put(ctx, secret))
+	stored, err = s.store.Get(ctx, "team-a", "db-password")
+	s.Require().NoError(err)
+	s.Equal("correct-horse", stored.Value)
+
+	// secrets are scoped to their namespace
+	_, err = s.store.Get(ctx, "team-b", "db-password")
+	s.ErrorAs(err, &ErrSecretNotFound{})
+
+	s.Require().NoError(s.store.Delete(ctx, "team-a", "db-password"))
+	_, err = s.store.Get(ctx, "team-a", "db-password")
+	s.ErrorAs(err, &ErrSecretNotFound{})
+	s.ErrorAs(s.store.Delete(ctx, "team-a", "db-password"), &ErrSecretNotFound{})
+}
+
+func (s *StoreTestSuite) TestList() {
+	ctx := context.Background()
+	for _, secret := range []models.Secret{
+		{Namespace: "team-b", Name: "token", Value: "b"},
+		{Namespace: "team-a", Name: "token", Value: "a"},
+		{Namespace: "team-a", Name: "api-key", Value: "a"},
+	} {
+		s.Require().NoError(s.store.Put(ctx, secret))
+	}
+
+	secrets, err := s.store.List(ctx, "team-a")
+	s.Require().NoError(err)
+	s.Require().Len(secrets, 2)
+	s.Equal("api-key", secrets[0].Name)
+	s.Equal("token", secrets[1].Name)
+
+	secrets, err = s.store.List(ctx, "")
+	s.Require().NoError(err)
+	s.Require().Len(secrets, 3)
+	s.Equal("team-b", secrets[2].Namespace)
+	for _, secret := range secrets {
+		s.Empty(secret.Value, "values must not be listed")
+	}
+
+	secrets, err = s.store.List(ctx, "team-c")
+	s.Require().NoError(err)
+	s.Empty(secrets)
+}
+
+func (s *StoreTestSuite) TestPut_Invalid() {
+	s.Error(s.store.Put(context.Background(), models.Secret{Namespace: "team-a", Name: "token"}))
+	s.Error(s.store.Put(context.Background(), models.Secret{Namespace: "team-a", Name: "a b", Value: "v"}))
+}
+
+func (s *StoreTestSuite) TestResolve() {
+	ctx := context.Background()
+	s.Require().NoError(s.store.Put(ctx, models.Secret{Namespace: "team-a", Name: "token", Value: "v"}))
+
+	values, err := Resolve(ctx, s.store, "team-a", []*models.SecretReference{
+		{Name: "token", Env: "TOKEN"},
+		{Name: "token", Path: "/run/token"},
+	})
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"token": "v"}, values)
+
+	_, err = Resolve(ctx, s.store, "team-b", []*models.SecretReference{{Name: "token", Env: "TOKEN"}})
+	s.ErrorAs(err, &ErrSecretNotFound{})
+}
+
+func TestBoltStore_EncryptsValues(t *testing.T) {
+	database, err := bolt.Open(filepath.Join(t.TempDir(), "secrets.db"), 0600, nil)
+	require.NoError(t, err)
+	defer database.Close() //nolint:errcheck
+	cipher, err := NewCipher(make([]byte, KeySize))
+	require.NoError(t, err)
+	store, err := NewBoltStore(database, cipher)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(context.Background(),
+		models.Secret{Namespace: "team-a", Name: "token", Value: "plaintext-value"}))
+	require.NoError(t, database.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketSecrets)).Bucket([]byte("team-a")).Get([]byte("token"))
+		require.NotNil(t, data)
+		assert.False(t, strings.Contains(string(data), "plaintext-value"))
+		return nil
+	}))
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "secrets.key")
+	key, err := LoadOrCreateKey(path)
+	require.NoError(t, err)
+	require.Len(t, key, KeySize)
+
+	loaded, err := LoadOrCreateKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+}
@@ -0,0 +1,24 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Store persists the secrets of namespaces on the requester.
+type Store interface {
+	// Get returns the secret of the namespace including its value, or
+	// ErrSecretNotFound if the namespace has no secret with this name.
+	Get(ctx context.Context, namespace string, name string) (models.Secret, error)
+
+	// List returns the secrets of a namespace without their values, sorted by name.
+	// The secrets of all namespaces are returned if the namespace is empty.
+	List(ctx context.Context, namespace string) ([]models.Secret, error)
+
+	// Put creates or replaces a secret.
+	Put(ctx context.Context, secret models.Secret) error
+
+	// Delete removes a secret.
+	Delete(ctx context.Context, namespace string, name string) error
+}
@@ -27,11 +27,14 @@ func NewPreviousExecutionsNodeRanker(params PreviousExecutionsNodeRankerParams)
 // - Rank 30: Node has never executed the job.
 // - Rank 0: Node has already executed the job.
 // - Rank -1: Node has executed the job more than once or has rejected a bid
+// Array jobs run many executions of the same job on each node, so only a rejected bid
+// makes a node unsuitable for them.
 func (s *PreviousExecutionsNodeRanker) RankNodes(ctx context.Context,
 	job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
 	ranks := make([]orchestrator.NodeRank, len(nodes))
 	previousExecutors := make(map[string]int)
 	toFilterOut := make(map[string]bool)
+	rejectedBids := make(map[string]bool)
 	executions, err := s.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{
 		JobID: job.ID,
 	})
@@ -46,13 +49,22 @@ func (s *PreviousExecutionsNodeRanker) RankNodes(ctx context.Context,
 			}
 			if execution.ComputeState.StateType == models.ExecutionStateAskForBidRejected {
 				toFilterOut[execution.NodeID] = true
+				rejectedBids[execution.NodeID] = true
 			}
 		}
 	}
 	for i, node := range nodes {
 		rank := 3 * orchestrator.RankPreferred
 		reason := "job not executed yet"
-		if previousExecutions, ok := previousExecutors[node.ID()]; ok {
+		if job.IsArray() {
+			if rejectedBids[node.ID()] {
+				rank = orchestrator.RankUnsuitable
+				reason = "job rejected"
+			} else if _, ok := previousExecutors[node.ID()]; ok {
+				rank = orchestrator.RankPossible
+				reason = "job already executed on this node"
+			}
+		} else if previousExecutions, ok := previousExecutors[node.ID()]; ok {
 			if previousExecutions > 1 {
 				rank = orchestrator.RankUnsuitable
 				reason = "job already executed on this node more than once"
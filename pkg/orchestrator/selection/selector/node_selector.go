@@ -32,17 +32,12 @@ func NewNodeSelector(params NodeSelectorParams) *NodeSelector {
 	}
 }
 
-func (n NodeSelector) AllNodes(ctx context.Context) ([]models.NodeInfo, error) {
+func (n NodeSelector) AllNodes(ctx context.Context) ([]models.NodeState, error) {
 	nodeStates, err := n.nodeDiscoverer.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list discovered nodes: %w", err)
 	}
-	// extract slice of models.NodeInfo from slice of routing.NodeConnectionState
-	nodeInfos := make([]models.NodeInfo, 0, len(nodeStates))
-	for _, ns := range nodeStates {
-		nodeInfos = append(nodeInfos, ns.Info)
-	}
-	return nodeInfos, nil
+	return nodeStates, nil
 }
 
 func (n NodeSelector) AllMatchingNodes(ctx context.Context,
@@ -92,11 +87,16 @@ func (n NodeSelector) rankAndFilterNodes(ctx context.Context,
 	// - compute nodes
 	// - approved to executor jobs
 	// - connected (alive)
+	// - not cordoned
 	nodeStates := lo.Filter(listed, func(nodeState models.NodeState, index int) bool {
 		if nodeState.Info.NodeType != models.NodeTypeCompute {
 			return false
 		}
 
+		if nodeState.IsCordoned() {
+			return false
+		}
+
 		if constraints.RequireApproval && nodeState.Membership != models.NodeMembership.APPROVED {
 			return false
 		}
@@ -1,6 +1,8 @@
 package orchestrator
 
 import (
+	"time"
+
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/rs/zerolog"
 )
@@ -25,11 +27,37 @@ type StopJobResponse struct {
 	EvaluationID string
 }
 
+type RollbackJobRequest struct {
+	JobID   string
+	Version uint64
+}
+
+type RollbackJobResponse struct {
+	// JobVersion is the new version of the job, created from the version it was rolled back to
+	JobVersion   uint64
+	EvaluationID string
+}
+
 type ReadLogsRequest struct {
 	JobID       string
 	ExecutionID string
 	Tail        bool
 	Follow      bool
+	Offset      int
+	TailLines   int
+	Since       time.Time
+}
+
+type ExecRequest struct {
+	JobID string
+	// ExecutionID is the execution to run the command in. Defaults to the latest running execution of the job.
+	ExecutionID string
+	Command     []string
+	TTY         bool
+	Stdin       bool
+	Size        *models.TerminalSize
+	// Input receives the input of the command until it is closed
+	Input <-chan models.ExecInput
 }
 
 type ReadLogsResponse struct {
@@ -88,6 +116,12 @@ func (r NodeRank) MarshalZerologObject(e *zerolog.Event) {
 
 type RetryRequest struct {
 	JobID string
+	// Job is the job whose failed executions are to be retried.
+	Job *models.Job
+	// Attempts is the number of failed attempts so far, of the job or of the array index being retried.
+	Attempts int
+	// FailureClasses are the classes of the failures being retried, such as models.RetryOnFailed.
+	FailureClasses []string
 }
 
 type NodeSelectionConstraints struct {
@@ -0,0 +1,281 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Workflow coordinates jobs that depend on other jobs. It holds back the evaluation
+// of a job until all of its upstream jobs have completed, and wires the published
+// results of the upstream jobs into the input sources of the downstream job's task.
+//
+// Workflow implements the Planner interface so that it can be part of the planner chain,
+// where it observes jobs reaching a terminal state and releases or fails the jobs depending
+// on them. It must be placed after the planner that persists the desired state of the plan,
+// and before the planner that forwards new executions to compute nodes.
+type Workflow struct {
+	store            jobstore.Store
+	evaluationBroker EvaluationBroker
+}
+
+type WorkflowParams struct {
+	Store            jobstore.Store
+	EvaluationBroker EvaluationBroker
+}
+
+func NewWorkflow(params WorkflowParams) *Workflow {
+	return &Workflow{
+		store:            params.Store,
+		evaluationBroker: params.EvaluationBroker,
+	}
+}
+
+// DependencyStatus is the status of the upstream jobs of a job.
+type DependencyStatus struct {
+	// Pending holds the IDs of upstream jobs that have not completed yet.
+	Pending []string
+	// Failed is the first upstream job found in a terminal state other than completed.
+	Failed *models.Job
+}
+
+// Ready returns true if all upstream jobs have completed.
+func (s DependencyStatus) Ready() bool {
+	return len(s.Pending) == 0 && s.Failed == nil
+}
+
+// Resolve validates the dependencies of a job that is being submitted, and pins each
+// dependency to the ID of its upstream job so that later submissions of jobs with the
+// same name do not change the graph.
+func (w *Workflow) Resolve(ctx context.Context, job *models.Job) error {
+	for _, dep := range job.Dependencies {
+		upstream, err := w.findUpstream(ctx, job.Namespace, dep)
+		if err != nil {
+			return err
+		}
+		if upstream.ID == job.ID {
+			return fmt.Errorf("job %s cannot depend on itself", job.ID)
+		}
+		if upstream.Namespace != job.Namespace {
+			return fmt.Errorf("job dependency %s is in namespace %s, expected %s",
+				dep.Reference(), upstream.Namespace, job.Namespace)
+		}
+		if upstream.Type != models.JobTypeBatch && upstream.Type != models.JobTypeOps {
+			return fmt.Errorf("job dependency %s is of type %s, only batch and ops jobs can be depended on",
+				dep.Reference(), upstream.Type)
+		}
+		dep.JobID = upstream.ID
+		if dep.Name == "" {
+			dep.Name = upstream.Name
+		}
+	}
+	return nil
+}
+
+// findUpstream returns the upstream job of a dependency, looking it up by ID if set,
+// or by picking the most recently created job with the dependency's name otherwise.
+func (w *Workflow) findUpstream(ctx context.Context, namespace string, dep *models.JobDependency) (models.Job, error) {
+	if dep.JobID != "" {
+		return w.store.GetJob(ctx, dep.JobID)
+	}
+	response, err := w.store.GetJobs(ctx, jobstore.JobQuery{
+		Namespace:   namespace,
+		SortBy:      "created_at",
+		SortReverse: true,
+	})
+	if err != nil {
+		return models.Job{}, err
+	}
+	for _, job := range response.Jobs {
+		if job.Name == dep.Name {
+			return job, nil
+		}
+	}
+	return models.Job{}, fmt.Errorf("job dependency %s not found in namespace %s", dep.Name, namespace)
+}
+
+// Status returns the status of the upstream jobs of the given job.
+func (w *Workflow) Status(ctx context.Context, job models.Job) (DependencyStatus, error) {
+	status := DependencyStatus{}
+	for _, dep := range job.Dependencies {
+		upstream, err := w.store.GetJob(ctx, dep.JobID)
+		if err != nil {
+			return status, fmt.Errorf("failed to retrieve dependency %s of job %s: %w", dep.JobID, job.ID, err)
+		}
+		switch upstream.State.StateType {
+		case models.JobStateTypeCompleted:
+		case models.JobStateTypeFailed, models.JobStateTypeStopped:
+			if status.Failed == nil {
+				status.Failed = &upstream
+			}
+		default:
+			status.Pending = append(status.Pending, upstream.ID)
+		}
+	}
+	return status, nil
+}
+
+// Process wires the results of upstream jobs into new executions of the plan's job,
+// and releases or fails the jobs depending on the plan's job when it reaches a terminal state.
+func (w *Workflow) Process(ctx context.Context, plan *models.Plan) error {
+	if plan.Job.HasDependencies() && len(plan.NewExecutions) > 0 {
+		if err := w.wireInputs(ctx, plan); err != nil {
+			return err
+		}
+	}
+
+	if becomesTerminal(plan) {
+		return w.releaseDependents(ctx, plan.Job.ID)
+	}
+	return nil
+}
+
+// becomesTerminal returns true if the plan is the first to see its job in a terminal state,
+// either because the plan moves the job to a terminal state, or because the plan is the one
+// of the evaluation that follows the job being stopped by the user.
+func becomesTerminal(plan *models.Plan) bool {
+	if plan.Job.IsTerminal() {
+		return plan.Eval != nil && plan.Eval.TriggeredBy == models.EvalTriggerJobCancel
+	}
+	return plan.DesiredJobState.IsTerminal()
+}
+
+// wireInputs adds the published results of the upstream jobs as input sources to
+// the job of the new executions in the plan. The job of each execution is copied so
+// that the stored specification remains as submitted by the user.
+func (w *Workflow) wireInputs(ctx context.Context, plan *models.Plan) error {
+	var sources []*models.InputSource
+	for _, dep := range plan.Job.Dependencies {
+		depSources, err := w.upstreamResults(ctx, dep)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, depSources...)
+	}
+	for _, exec := range plan.NewExecutions {
+		job := exec.Job
+		if job == nil {
+			job = plan.Job
+		}
+		job = job.Copy()
+		task := job.Task()
+		task.InputSources = append(task.InputSources, sources...)
+		exec.Job = job
+	}
+	return nil
+}
+
+// upstreamResults returns the published results of the completed executions of the
+// upstream job as input sources. If the upstream job has more than one result, each
+// result is mounted in its own numbered directory under the dependency's target.
+func (w *Workflow) upstreamResults(ctx context.Context, dep *models.JobDependency) ([]*models.InputSource, error) {
+	executions, err := w.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID: dep.JobID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve executions of dependency %s: %w", dep.JobID, err)
+	}
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].CreateTime < executions[j].CreateTime
+	})
+
+	results := make([]*models.SpecConfig, 0, len(executions))
+	for _, execution := range executions {
+		if execution.ComputeState.StateType != models.ExecutionStateCompleted {
+			continue
+		}
+		if execution.PublishedResult == nil || execution.PublishedResult.Type == "" {
+			continue
+		}
+		results = append(results, execution.PublishedResult.Copy())
+	}
+
+	sources := make([]*models.InputSource, len(results))
+	for i, result := range results {
+		target := dep.TargetOrDefault()
+		if len(results) > 1 {
+			target = path.Join(target, strconv.Itoa(i))
+		}
+		sources[i] = &models.InputSource{
+			Source: result,
+			Alias:  dep.Name,
+			Target: target,
+		}
+	}
+	return sources, nil
+}
+
+// releaseDependents evaluates the pending jobs that depend on the given job. Jobs whose
+// dependencies have all completed are enqueued for scheduling, and jobs with a failed
+// dependency are marked as failed, which in turn fails their own dependents.
+func (w *Workflow) releaseDependents(ctx context.Context, upstreamID string) error {
+	response, err := w.store.GetJobs(ctx, jobstore.JobQuery{
+		ReturnAll: true,
+		DependsOn: upstreamID,
+		States:    []models.JobStateType{models.JobStateTypePending},
+	})
+	if err != nil {
+		return err
+	}
+
+	var mErr error
+	for _, job := range response.Jobs {
+		status, err := w.Status(ctx, job)
+		if err != nil {
+			mErr = errors.Join(mErr, err)
+			continue
+		}
+		switch {
+		case status.Failed != nil:
+			err = w.store.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
+				JobID:     job.ID,
+				Condition: jobstore.UpdateJobCondition{ExpectedState: models.JobStateTypePending},
+				NewState:  models.JobStateTypeFailed,
+				Event:     JobDependencyFailedEvent(*status.Failed),
+			})
+			if err == nil {
+				err = w.releaseDependents(ctx, job.ID)
+			}
+		case status.Ready():
+			err = w.enqueue(ctx, job)
+		}
+		if err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
+	return mErr
+}
+
+// enqueue creates and enqueues an evaluation for a job whose dependencies have completed.
+func (w *Workflow) enqueue(ctx context.Context, job models.Job) error {
+	now := time.Now().UTC().UnixNano()
+	eval := &models.Evaluation{
+		ID:          uuid.NewString(),
+		Namespace:   job.Namespace,
+		JobID:       job.ID,
+		TriggeredBy: models.EvalTriggerJobDependency,
+		Priority:    job.Priority,
+		Type:        job.Type,
+		Status:      models.EvalStatusPending,
+		CreateTime:  now,
+		ModifyTime:  now,
+	}
+	if err := w.store.CreateEvaluation(ctx, *eval); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Debug().Msgf("Dependencies of job %s completed, enqueuing evaluation %s", job.ID, eval.ID)
+	return w.evaluationBroker.Enqueue(eval)
+}
+
+// compile-time check whether the Workflow implements the Planner interface.
+var _ Planner = (*Workflow)(nil)
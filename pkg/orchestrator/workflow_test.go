@@ -0,0 +1,206 @@
+//go:build unit || !integration
+
+package orchestrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type WorkflowSuite struct {
+	suite.Suite
+	ctx      context.Context
+	ctrl     *gomock.Controller
+	store    *boltjobstore.BoltJobStore
+	broker   *orchestrator.MockEvaluationBroker
+	workflow *orchestrator.Workflow
+}
+
+func TestWorkflowSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowSuite))
+}
+
+func (s *WorkflowSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.ctrl = gomock.NewController(s.T())
+
+	store, err := boltjobstore.NewBoltJobStore(filepath.Join(s.T().TempDir(), "test.db"))
+	s.Require().NoError(err)
+	s.store = store
+	s.broker = orchestrator.NewMockEvaluationBroker(s.ctrl)
+	s.workflow = orchestrator.NewWorkflow(orchestrator.WorkflowParams{
+		Store:            s.store,
+		EvaluationBroker: s.broker,
+	})
+}
+
+func (s *WorkflowSuite) TearDownTest() {
+	s.ctrl.Finish()
+	s.NoError(s.store.Close(s.ctx))
+}
+
+func (s *WorkflowSuite) createJob(name string, deps ...*models.JobDependency) *models.Job {
+	job := mock.Job()
+	job.Name = name
+	job.Dependencies = deps
+	s.Require().NoError(s.workflow.Resolve(s.ctx, job))
+	s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	return job
+}
+
+func (s *WorkflowSuite) updateJobState(job *models.Job, state models.JobStateType) {
+	s.Require().NoError(s.store.UpdateJobState(s.ctx, jobstore.UpdateJobStateRequest{
+		JobID:    job.ID,
+		NewState: state,
+	}))
+}
+
+func (s *WorkflowSuite) completeExecution(job *models.Job, result *models.SpecConfig) {
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.store.CreateExecution(s.ctx, *execution, models.Event{}))
+	s.Require().NoError(s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
+			PublishedResult: result,
+		},
+	}))
+}
+
+func (s *WorkflowSuite) TestResolveByName() {
+	upstream := s.createJob("upstream")
+	downstream := s.createJob("downstream", &models.JobDependency{Name: "upstream"})
+	s.Equal(upstream.ID, downstream.Dependencies[0].JobID)
+	s.Equal("/inputs/upstream", downstream.Dependencies[0].TargetOrDefault())
+}
+
+func (s *WorkflowSuite) TestResolveMissing() {
+	job := mock.Job()
+	job.Dependencies = []*models.JobDependency{{Name: "missing"}}
+	s.Error(s.workflow.Resolve(s.ctx, job))
+}
+
+func (s *WorkflowSuite) TestResolveRejectsServiceJobs() {
+	upstream := mock.Job()
+	upstream.Name = "service"
+	upstream.Type = models.JobTypeService
+	s.Require().NoError(s.store.CreateJob(s.ctx, *upstream, models.Event{}))
+
+	job := mock.Job()
+	job.Dependencies = []*models.JobDependency{{Name: "service"}}
+	s.Error(s.workflow.Resolve(s.ctx, job))
+}
+
+func (s *WorkflowSuite) TestStatus() {
+	first := s.createJob("first")
+	second := s.createJob("second")
+	downstream := s.createJob("downstream", &models.JobDependency{Name: "first"}, &models.JobDependency{Name: "second"})
+
+	status, err := s.workflow.Status(s.ctx, *downstream)
+	s.Require().NoError(err)
+	s.False(status.Ready())
+	s.ElementsMatch([]string{first.ID, second.ID}, status.Pending)
+
+	s.updateJobState(first, models.JobStateTypeCompleted)
+	s.updateJobState(second, models.JobStateTypeFailed)
+	status, err = s.workflow.Status(s.ctx, *downstream)
+	s.Require().NoError(err)
+	s.False(status.Ready())
+	s.Empty(status.Pending)
+	s.Require().NotNil(status.Failed)
+	s.Equal(second.ID, status.Failed.ID)
+}
+
+func (s *WorkflowSuite) TestProcessReleasesDependents() {
+	upstream := s.createJob("upstream")
+	downstream := s.createJob("downstream", &models.JobDependency{Name: "upstream"})
+	s.updateJobState(upstream, models.JobStateTypeCompleted)
+
+	s.broker.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(eval *models.Evaluation) error {
+		s.Equal(downstream.ID, eval.JobID)
+		s.Equal(models.EvalTriggerJobDependency, eval.TriggeredBy)
+		return nil
+	})
+
+	plan := models.NewPlan(mock.Eval(), upstream)
+	plan.DesiredJobState = models.JobStateTypeCompleted
+	s.NoError(s.workflow.Process(s.ctx, plan))
+}
+
+func (s *WorkflowSuite) TestProcessReleasesDependentsOnce() {
+	upstream := s.createJob("upstream")
+	s.createJob("downstream", &models.JobDependency{Name: "upstream"})
+	s.updateJobState(upstream, models.JobStateTypeCompleted)
+	stored, err := s.store.GetJob(s.ctx, upstream.ID)
+	s.Require().NoError(err)
+
+	// later plans of the terminal job do not release its dependents again
+	s.broker.EXPECT().Enqueue(gomock.Any()).Times(0)
+	s.NoError(s.workflow.Process(s.ctx, models.NewPlan(mock.Eval(), &stored)))
+}
+
+func (s *WorkflowSuite) TestProcessReleasesDependentsOfStoppedJob() {
+	upstream := s.createJob("upstream")
+	downstream := s.createJob("downstream", &models.JobDependency{Name: "upstream"})
+	s.updateJobState(upstream, models.JobStateTypeStopped)
+	stored, err := s.store.GetJob(s.ctx, upstream.ID)
+	s.Require().NoError(err)
+
+	// the job is stopped by the user before the scheduler evaluates it
+	eval := mock.Eval()
+	eval.TriggeredBy = models.EvalTriggerJobCancel
+	s.NoError(s.workflow.Process(s.ctx, models.NewPlan(eval, &stored)))
+
+	stored, err = s.store.GetJob(s.ctx, downstream.ID)
+	s.Require().NoError(err)
+	s.Equal(models.JobStateTypeFailed, stored.State.StateType)
+}
+
+func (s *WorkflowSuite) TestProcessFailsDependentsTransitively() {
+	upstream := s.createJob("upstream")
+	middle := s.createJob("middle", &models.JobDependency{Name: "upstream"})
+	downstream := s.createJob("downstream", &models.JobDependency{Name: "middle"})
+	s.updateJobState(upstream, models.JobStateTypeFailed)
+
+	plan := models.NewPlan(mock.Eval(), upstream)
+	plan.DesiredJobState = models.JobStateTypeFailed
+	s.NoError(s.workflow.Process(s.ctx, plan))
+
+	for _, job := range []*models.Job{middle, downstream} {
+		stored, err := s.store.GetJob(s.ctx, job.ID)
+		s.Require().NoError(err)
+		s.Equal(models.JobStateTypeFailed, stored.State.StateType)
+	}
+}
+
+func (s *WorkflowSuite) TestProcessWiresInputs() {
+	upstream := s.createJob("upstream")
+	result := &models.SpecConfig{Type: models.StorageSourceIPFS, Params: map[string]interface{}{"CID": "QmTest"}}
+	s.completeExecution(upstream, result)
+	s.updateJobState(upstream, models.JobStateTypeCompleted)
+
+	downstream := s.createJob("downstream", &models.JobDependency{Name: "upstream", Target: "/data"})
+	plan := models.NewPlan(mock.Eval(), downstream)
+	execution := mock.ExecutionForJob(downstream)
+	plan.AppendExecution(execution)
+	s.Require().NoError(s.workflow.Process(s.ctx, plan))
+
+	inputs := execution.Job.Task().InputSources
+	s.Require().Len(inputs, 1)
+	s.Equal("/data", inputs[0].Target)
+	s.Equal("upstream", inputs[0].Alias)
+	s.Equal(result, inputs[0].Source)
+
+	// the job of the plan must not be modified
+	s.Empty(downstream.Task().InputSources)
+}
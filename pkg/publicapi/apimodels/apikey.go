@@ -0,0 +1,63 @@
+package apimodels
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ListAPIKeysRequest lists the API keys issued by the requester. The keys
+// themselves and their hashes are never returned.
+type ListAPIKeysRequest struct {
+	BaseListRequest
+}
+
+type ListAPIKeysResponse struct {
+	BaseListResponse
+	APIKeys []*models.APIKey `json:"APIKeys"`
+}
+
+type CreateAPIKeyRequest struct {
+	BasePutRequest
+	// Name is the unique name the key is managed by
+	Name string `json:"Name"`
+	// Namespaces are the namespaces the key grants access to, or "*" for all namespaces
+	Namespaces []string `json:"Namespaces"`
+	// Permissions are the accesses the key grants in its namespaces
+	Permissions []models.APIKeyPermission `json:"Permissions"`
+	// ExpiresIn is how long the key is valid for, or zero if it never expires
+	ExpiresIn time.Duration `json:"ExpiresIn,omitempty"`
+}
+
+// Validate is used to validate fields in the CreateAPIKeyRequest.
+func (r *CreateAPIKeyRequest) Validate() error {
+	mErr := models.ValidateAPIKeyName(r.Name)
+	if len(r.Namespaces) == 0 {
+		mErr = errors.Join(mErr, errors.New("API key must grant access to at least one namespace"))
+	}
+	if len(r.Permissions) == 0 {
+		mErr = errors.Join(mErr, errors.New("API key must grant at least one permission"))
+	}
+	if r.ExpiresIn < 0 {
+		mErr = errors.Join(mErr, errors.New("API key expiry must not be negative"))
+	}
+	return mErr
+}
+
+type CreateAPIKeyResponse struct {
+	BasePutResponse
+	// APIKey is the issued key, without its hash
+	APIKey *models.APIKey `json:"APIKey"`
+	// Key is the key to authenticate with. It is only returned once.
+	Key string `json:"Key"`
+}
+
+type RevokeAPIKeyRequest struct {
+	BasePutRequest
+	Name string `json:"-"`
+}
+
+type RevokeAPIKeyResponse struct {
+	BasePutResponse
+}
@@ -0,0 +1,48 @@
+package apimodels
+
+import (
+	"strconv"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ListAuditEventsRequest lists the events of the audit log of the requester,
+// in the order they were recorded. The limit of the request keeps the most
+// recent events.
+type ListAuditEventsRequest struct {
+	BaseListRequest
+	Principal string `query:"principal"`
+	Action    string `query:"action"`
+	Target    string `query:"target"`
+	// After and Before list events recorded at or after, and strictly before,
+	// the given unix times in seconds
+	After  int64 `query:"after" validate:"min=0"`
+	Before int64 `query:"before" validate:"min=0"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *ListAuditEventsRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseListRequest.ToHTTPRequest()
+
+	if o.Principal != "" {
+		r.Params.Set("principal", o.Principal)
+	}
+	if o.Action != "" {
+		r.Params.Set("action", o.Action)
+	}
+	if o.Target != "" {
+		r.Params.Set("target", o.Target)
+	}
+	if o.After != 0 {
+		r.Params.Set("after", strconv.FormatInt(o.After, 10))
+	}
+	if o.Before != 0 {
+		r.Params.Set("before", strconv.FormatInt(o.Before, 10))
+	}
+	return r
+}
+
+type ListAuditEventsResponse struct {
+	BaseListResponse
+	Events []models.AuditEvent `json:"Events"`
+}
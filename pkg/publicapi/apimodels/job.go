@@ -1,6 +1,8 @@
 package apimodels
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -75,6 +77,16 @@ func (r *GetJobResponse) Normalize() {
 type ListJobsRequest struct {
 	BaseListRequest
 	Labels []labels.Requirement `query:"-"` // don't auto bind as it requires special handling
+	// States, Types and EngineTypes list jobs that have ANY of the given values
+	States      []string `query:"state"`
+	Types       []string `query:"type"`
+	EngineTypes []string `query:"engine"`
+	// NamePrefix lists jobs whose name starts with the prefix
+	NamePrefix string `query:"name_prefix"`
+	// CreatedAfter and CreatedBefore list jobs created at or after, and strictly before,
+	// the given unix times in seconds
+	CreatedAfter  int64 `query:"created_after" validate:"min=0"`
+	CreatedBefore int64 `query:"created_before" validate:"min=0"`
 }
 
 // ToHTTPRequest is used to convert the request to an HTTP request
@@ -84,9 +96,52 @@ func (o *ListJobsRequest) ToHTTPRequest() *HTTPRequest {
 	for _, v := range o.Labels {
 		r.Params.Add("labels", v.String())
 	}
+	for _, v := range o.States {
+		r.Params.Add("state", v)
+	}
+	for _, v := range o.Types {
+		r.Params.Add("type", v)
+	}
+	for _, v := range o.EngineTypes {
+		r.Params.Add("engine", v)
+	}
+	if o.NamePrefix != "" {
+		r.Params.Set("name_prefix", o.NamePrefix)
+	}
+	if o.CreatedAfter != 0 {
+		r.Params.Set("created_after", strconv.FormatInt(o.CreatedAfter, 10))
+	}
+	if o.CreatedBefore != 0 {
+		r.Params.Set("created_before", strconv.FormatInt(o.CreatedBefore, 10))
+	}
 	return r
 }
 
+// JobStates returns the job states to list jobs in, or an error naming the
+// states that are not known.
+func (o *ListJobsRequest) JobStates() ([]models.JobStateType, error) {
+	var states []models.JobStateType
+	var mErr error
+	for _, state := range o.States {
+		var stateType models.JobStateType
+		if err := stateType.UnmarshalText([]byte(state)); err != nil || stateType.IsUndefined() {
+			mErr = errors.Join(mErr, fmt.Errorf("unknown job state: %q", state))
+			continue
+		}
+		states = append(states, stateType)
+	}
+	return states, mErr
+}
+
+// Validate is used to validate fields in the ListJobsRequest.
+func (o *ListJobsRequest) Validate() error {
+	_, mErr := o.JobStates()
+	if o.CreatedAfter != 0 && o.CreatedBefore != 0 && o.CreatedAfter >= o.CreatedBefore {
+		mErr = errors.Join(mErr, errors.New("created_after must be before created_before"))
+	}
+	return mErr
+}
+
 type ListJobsResponse struct {
 	BaseListResponse
 	Jobs []*models.Job `json:"Jobs"`
@@ -164,12 +219,32 @@ type StopJobResponse struct {
 	EvaluationID string `json:"EvaluationID"`
 }
 
+type RollbackJobRequest struct {
+	BasePutRequest
+	JobID string `json:"-"`
+	// Version is the previous version of the job to roll back to
+	Version uint64 `json:"Version"`
+}
+
+type RollbackJobResponse struct {
+	BasePutResponse
+	// JobVersion is the new version of the job, created from the version it was rolled back to
+	JobVersion   uint64 `json:"JobVersion"`
+	EvaluationID string `json:"EvaluationID"`
+}
+
 type GetLogsRequest struct {
 	BaseGetRequest
 	JobID       string `query:"-"`
 	ExecutionID string `query:"execution_id" validate:"omitempty"`
 	Tail        bool   `query:"tail"`
 	Follow      bool   `query:"follow"`
+	// Offset is the number of lines to skip from the start of the logs
+	Offset int `query:"offset"`
+	// TailLines limits the existing logs to the last N lines
+	TailLines int `query:"tail_lines"`
+	// Since only returns the logs written after this time, in unix seconds
+	Since int64 `query:"since"`
 }
 
 // ToHTTPRequest is used to convert the request to an HTTP request
@@ -185,5 +260,102 @@ func (o *GetLogsRequest) ToHTTPRequest() *HTTPRequest {
 	if o.Follow {
 		r.Params.Set("follow", "true")
 	}
+	if o.Offset != 0 {
+		r.Params.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.TailLines != 0 {
+		r.Params.Set("tail_lines", strconv.Itoa(o.TailLines))
+	}
+	if o.Since != 0 {
+		r.Params.Set("since", strconv.FormatInt(o.Since, 10))
+	}
+	return r
+}
+
+// Validate is used to validate fields in the GetLogsRequest.
+func (o *GetLogsRequest) Validate() error {
+	var mErr error
+	if o.Offset < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid offset: %d", o.Offset))
+	}
+	if o.TailLines < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid tail lines: %d", o.TailLines))
+	}
+	return mErr
+}
+
+type ExecRequest struct {
+	BaseGetRequest
+	JobID string `query:"-"`
+	// ExecutionID is the execution to run the command in. Defaults to the latest running execution of the job.
+	ExecutionID string   `query:"execution_id" validate:"omitempty"`
+	Command     []string `query:"command"`
+	// TTY allocates a terminal for the command
+	TTY bool `query:"tty"`
+	// Stdin attaches the standard input of the command to the input sent by the client
+	Stdin bool `query:"stdin"`
+	// Height and Width are the initial size of the terminal
+	Height uint `query:"height"`
+	Width  uint `query:"width"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *ExecRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+
+	if o.ExecutionID != "" {
+		r.Params.Set("execution_id", o.ExecutionID)
+	}
+	for _, arg := range o.Command {
+		r.Params.Add("command", arg)
+	}
+	if o.TTY {
+		r.Params.Set("tty", "true")
+	}
+	if o.Stdin {
+		r.Params.Set("stdin", "true")
+	}
+	if o.Height != 0 && o.Width != 0 {
+		r.Params.Set("height", strconv.FormatUint(uint64(o.Height), 10))
+		r.Params.Set("width", strconv.FormatUint(uint64(o.Width), 10))
+	}
 	return r
 }
+
+// Validate is used to validate fields in the ExecRequest.
+func (o *ExecRequest) Validate() error {
+	if len(o.Command) == 0 {
+		return errors.New("missing command to execute")
+	}
+	return nil
+}
+
+type PruneJobsRequest struct {
+	BasePutRequest
+	// DryRun returns the jobs that would be pruned without removing them
+	DryRun bool `json:"DryRun"`
+	// Policies override the retention policies configured on the orchestrator
+	Policies []*models.RetentionPolicy `json:"Policies,omitempty"`
+}
+
+// Normalize is used to canonicalize fields in the PruneJobsRequest.
+func (r *PruneJobsRequest) Normalize() {
+	for _, policy := range r.Policies {
+		policy.Normalize()
+	}
+}
+
+// Validate is used to validate fields in the PruneJobsRequest.
+func (r *PruneJobsRequest) Validate() error {
+	var mErr error
+	for _, policy := range r.Policies {
+		mErr = errors.Join(mErr, policy.Validate())
+	}
+	return mErr
+}
+
+type PruneJobsResponse struct {
+	BasePutResponse
+	DryRun bool                `json:"DryRun"`
+	Jobs   []*models.PrunedJob `json:"Jobs"`
+}
@@ -52,6 +52,10 @@ type PutNodeRequest struct {
 	Action  string
 	Message string
 	NodeID  string
+	// GracePeriod is how long, in seconds, running executions of long-running jobs are given
+	// to be replaced on other nodes when draining a node, before they are stopped. The grace
+	// period configured on the requester is used if it is not set.
+	GracePeriod *int64 `json:",omitempty"`
 }
 
 type PutNodeResponse struct {
@@ -63,9 +67,12 @@ type PutNodeResponse struct {
 type NodeAction string
 
 const (
-	NodeActionApprove NodeAction = "approve"
-	NodeActionReject  NodeAction = "reject"
-	NodeActionDelete  NodeAction = "delete"
+	NodeActionApprove  NodeAction = "approve"
+	NodeActionReject   NodeAction = "reject"
+	NodeActionDelete   NodeAction = "delete"
+	NodeActionCordon   NodeAction = "cordon"
+	NodeActionUncordon NodeAction = "uncordon"
+	NodeActionDrain    NodeAction = "drain"
 )
 
 func (n NodeAction) Description() string {
@@ -76,10 +83,21 @@ func (n NodeAction) Description() string {
 		return "Reject a node whose membership is pending"
 	case NodeActionDelete:
 		return "Delete a node from the cluster."
+	case NodeActionCordon:
+		return "Stop scheduling new executions on a node"
+	case NodeActionUncordon:
+		return "Resume scheduling new executions on a cordoned or draining node"
+	case NodeActionDrain:
+		return "Cordon a node and move the executions of its service and daemon jobs to other nodes"
 	}
 	return ""
 }
 
 func (n NodeAction) IsValid() bool {
-	return n == NodeActionApprove || n == NodeActionReject || n == NodeActionDelete
+	switch n {
+	case NodeActionApprove, NodeActionReject, NodeActionDelete,
+		NodeActionCordon, NodeActionUncordon, NodeActionDrain:
+		return true
+	}
+	return false
 }
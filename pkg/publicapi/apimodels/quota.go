@@ -0,0 +1,62 @@
+package apimodels
+
+import (
+	"errors"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type GetQuotaRequest struct {
+	BaseGetRequest
+	QuotaNamespace string `query:"-"`
+}
+
+type GetQuotaResponse struct {
+	BaseGetResponse
+	Quota *models.NamespaceQuota `json:"Quota"`
+	// Usage is the number of active jobs in the namespace and their requested resources
+	Usage *models.NamespaceUsage `json:"Usage"`
+}
+
+type ListQuotasRequest struct {
+	BaseListRequest
+}
+
+type ListQuotasResponse struct {
+	BaseListResponse
+	Quotas []*models.NamespaceQuota `json:"Quotas"`
+}
+
+type PutQuotaRequest struct {
+	BasePutRequest
+	Quota *models.NamespaceQuota `json:"Quota"`
+}
+
+// Normalize is used to canonicalize fields in the PutQuotaRequest.
+func (r *PutQuotaRequest) Normalize() {
+	if r.Quota != nil {
+		r.Quota.Normalize()
+	}
+}
+
+// Validate is used to validate fields in the PutQuotaRequest.
+func (r *PutQuotaRequest) Validate() error {
+	if r.Quota == nil {
+		return errors.New("missing quota")
+	}
+	return r.Quota.Validate()
+}
+
+type PutQuotaResponse struct {
+	BasePutResponse
+	Quota *models.NamespaceQuota `json:"Quota"`
+}
+
+type DeleteQuotaRequest struct {
+	BasePutRequest
+	QuotaNamespace string `json:"-"`
+}
+
+type DeleteQuotaResponse struct {
+	BasePutResponse
+}
@@ -0,0 +1,54 @@
+package apimodels
+
+import (
+	"errors"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ListSecretsRequest lists the secrets of the namespace of the request, or of all
+// namespaces if it is not set. The values of the secrets are never returned.
+type ListSecretsRequest struct {
+	BaseListRequest
+}
+
+type ListSecretsResponse struct {
+	BaseListResponse
+	Secrets []*models.Secret `json:"Secrets"`
+}
+
+type PutSecretRequest struct {
+	BasePutRequest
+	Secret *models.Secret `json:"Secret"`
+}
+
+// Normalize is used to canonicalize fields in the PutSecretRequest.
+func (r *PutSecretRequest) Normalize() {
+	if r.Secret != nil {
+		r.Secret.Normalize()
+	}
+}
+
+// Validate is used to validate fields in the PutSecretRequest.
+func (r *PutSecretRequest) Validate() error {
+	if r.Secret == nil {
+		return errors.New("missing secret")
+	}
+	return r.Secret.Validate()
+}
+
+type PutSecretResponse struct {
+	BasePutResponse
+	// Secret is the stored secret, without its value
+	Secret *models.Secret `json:"Secret"`
+}
+
+type DeleteSecretRequest struct {
+	BasePutRequest
+	SecretNamespace string `json:"-"`
+	SecretName      string `json:"-"`
+}
+
+type DeleteSecretResponse struct {
+	BasePutResponse
+}
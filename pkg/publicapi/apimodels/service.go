@@ -0,0 +1,34 @@
+package apimodels
+
+type DiscoverServiceRequest struct {
+	BaseGetRequest
+	// Name is the name of the service or daemon job
+	Name string `query:"-"`
+	// Port only returns the endpoints of the port with this name
+	Port string `query:"port"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *DiscoverServiceRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+	if o.Port != "" {
+		r.Params.Set("port", o.Port)
+	}
+	return r
+}
+
+// ServiceEndpoint is a port published by a running execution of a service
+type ServiceEndpoint struct {
+	JobID       string `json:"JobID"`
+	ExecutionID string `json:"ExecutionID"`
+	NodeID      string `json:"NodeID"`
+	// Port is the name of the port
+	Port string `json:"Port"`
+	// Address is the host:port at which the port is reachable
+	Address string `json:"Address"`
+}
+
+type DiscoverServiceResponse struct {
+	BaseGetResponse
+	Endpoints []*ServiceEndpoint `json:"Endpoints"`
+}
@@ -4,10 +4,15 @@ package client
 // structure of the API: each method returns an object that can submit requests
 // to control a single part of the system.
 type API interface {
+	APIKeys() *APIKeys
 	Agent() *Agent
+	Audit() *Audit
 	Auth() *Auth
 	Jobs() *Jobs
 	Nodes() *Nodes
+	Quotas() *Quotas
+	Secrets() *Secrets
+	Services() *Services
 }
 
 type api struct {
@@ -18,6 +23,10 @@ func (c *api) Agent() *Agent {
 	return &Agent{client: c.Client}
 }
 
+func (c *api) Audit() *Audit {
+	return &Audit{client: c.Client}
+}
+
 func (c *api) Auth() *Auth {
 	return &Auth{client: c.Client}
 }
@@ -30,6 +39,22 @@ func (c *api) Nodes() *Nodes {
 	return &Nodes{client: c.Client}
 }
 
+func (c *api) Quotas() *Quotas {
+	return &Quotas{client: c.Client}
+}
+
+func (c *api) APIKeys() *APIKeys {
+	return &APIKeys{client: c.Client}
+}
+
+func (c *api) Secrets() *Secrets {
+	return &Secrets{client: c.Client}
+}
+
+func (c *api) Services() *Services {
+	return &Services{client: c.Client}
+}
+
 func NewAPI(transport Client) API {
 	return &api{Client: transport}
 }
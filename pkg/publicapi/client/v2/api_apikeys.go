@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const apiKeysPath = "/api/v1/orchestrator/apikeys"
+
+type APIKeys struct {
+	client Client
+}
+
+// List is used to list the API keys issued by the requester.
+func (c *APIKeys) List(ctx context.Context, r *apimodels.ListAPIKeysRequest) (*apimodels.ListAPIKeysResponse, error) {
+	var resp apimodels.ListAPIKeysResponse
+	if err := c.client.List(ctx, apiKeysPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Create is used to issue a new API key.
+func (c *APIKeys) Create(ctx context.Context, r *apimodels.CreateAPIKeyRequest) (*apimodels.CreateAPIKeyResponse, error) {
+	var resp apimodels.CreateAPIKeyResponse
+	if err := c.client.Post(ctx, apiKeysPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Revoke is used to revoke an API key, which is no longer accepted.
+func (c *APIKeys) Revoke(ctx context.Context, r *apimodels.RevokeAPIKeyRequest) (*apimodels.RevokeAPIKeyResponse, error) {
+	var resp apimodels.RevokeAPIKeyResponse
+	if err := c.client.Delete(ctx, apiKeysPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const auditPath = "/api/v1/orchestrator/audit"
+
+type Audit struct {
+	client Client
+}
+
+// List is used to list the events of the audit log of the requester.
+func (c *Audit) List(ctx context.Context, r *apimodels.ListAuditEventsRequest) (*apimodels.ListAuditEventsResponse, error) {
+	var resp apimodels.ListAuditEventsResponse
+	if err := c.client.List(ctx, auditPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
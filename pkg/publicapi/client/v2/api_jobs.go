@@ -78,7 +78,34 @@ func (j *Jobs) Stop(ctx context.Context, r *apimodels.StopJobRequest) (*apimodel
 	return &resp, nil
 }
 
+// Rollback is used to roll a long-running job back to one of its previous versions.
+func (j *Jobs) Rollback(ctx context.Context, r *apimodels.RollbackJobRequest) (*apimodels.RollbackJobResponse, error) {
+	var resp apimodels.RollbackJobResponse
+	if err := j.client.Put(ctx, jobsPath+"/"+r.JobID+"/rollback", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // Logs returns a stream of logs for a given job/execution.
 func (j *Jobs) Logs(ctx context.Context, r *apimodels.GetLogsRequest) (<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	return DialAsyncResult[*apimodels.GetLogsRequest, models.ExecutionLog](ctx, j.client, jobsPath+"/"+r.JobID+"/logs", r)
 }
+
+// Exec runs a command inside a running execution of a job, and returns a stream of its output
+// until it exits. The input is sent to the command until the input channel is closed.
+func (j *Jobs) Exec(ctx context.Context, r *apimodels.ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	return DialAsyncResultWithInput[*apimodels.ExecRequest, models.ExecInput, models.ExecOutput](
+		ctx, j.client, jobsPath+"/"+r.JobID+"/exec", r, input)
+}
+
+// Prune removes the terminal jobs that are not retained by the retention policies,
+// or only lists them if the request is a dry run.
+func (j *Jobs) Prune(ctx context.Context, r *apimodels.PruneJobsRequest) (*apimodels.PruneJobsResponse, error) {
+	var resp apimodels.PruneJobsResponse
+	if err := j.client.Post(ctx, jobsPath+"/prune", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const quotasPath = "/api/v1/orchestrator/quotas"
+
+type Quotas struct {
+	client Client
+}
+
+// Get is used to get the quota of a namespace and its current usage.
+func (c *Quotas) Get(ctx context.Context, r *apimodels.GetQuotaRequest) (*apimodels.GetQuotaResponse, error) {
+	var resp apimodels.GetQuotaResponse
+	if err := c.client.Get(ctx, quotasPath+"/"+r.QuotaNamespace, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// List is used to list the quotas of all namespaces.
+func (c *Quotas) List(ctx context.Context, r *apimodels.ListQuotasRequest) (*apimodels.ListQuotasResponse, error) {
+	var resp apimodels.ListQuotasResponse
+	if err := c.client.List(ctx, quotasPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Put is used to create or replace the quota of a namespace.
+func (c *Quotas) Put(ctx context.Context, r *apimodels.PutQuotaRequest) (*apimodels.PutQuotaResponse, error) {
+	var resp apimodels.PutQuotaResponse
+	if err := c.client.Put(ctx, quotasPath+"/"+r.Quota.Namespace, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Delete is used to remove the quota of a namespace.
+func (c *Quotas) Delete(ctx context.Context, r *apimodels.DeleteQuotaRequest) (*apimodels.DeleteQuotaResponse, error) {
+	var resp apimodels.DeleteQuotaResponse
+	if err := c.client.Delete(ctx, quotasPath+"/"+r.QuotaNamespace, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const servicesPath = "/api/v1/orchestrator/services"
+
+type Services struct {
+	client Client
+}
+
+// Discover is used to get the endpoints of the running executions of a service.
+func (c *Services) Discover(ctx context.Context, r *apimodels.DiscoverServiceRequest) (*apimodels.DiscoverServiceResponse, error) {
+	var resp apimodels.DiscoverServiceResponse
+	if err := c.client.Get(ctx, servicesPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -25,6 +26,7 @@ type Client interface {
 	Post(context.Context, string, apimodels.PutRequest, apimodels.PutResponse) error
 	Delete(context.Context, string, apimodels.PutRequest, apimodels.Response) error
 	Dial(context.Context, string, apimodels.Request) (<-chan *concurrency.AsyncResult[[]byte], error)
+	DialWithInput(context.Context, string, apimodels.Request, <-chan []byte) (<-chan *concurrency.AsyncResult[[]byte], error)
 }
 
 // New creates a new transport.
@@ -123,6 +125,17 @@ func (c *httpClient) Delete(ctx context.Context, endpoint string, in apimodels.P
 // successfully dialed, from which point on the returned channel will contain
 // every received message.
 func (c *httpClient) Dial(ctx context.Context, endpoint string, in apimodels.Request) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	return c.DialWithInput(ctx, endpoint, in, nil)
+}
+
+// DialWithInput is like Dial, but also writes the messages received from the input
+// channel to the websocket connection, until the input channel is closed.
+func (c *httpClient) DialWithInput(
+	ctx context.Context,
+	endpoint string,
+	in apimodels.Request,
+	input <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
 	r := in.ToHTTPRequest()
 	httpR, err := c.toHTTP(ctx, http.MethodGet, endpoint, r)
 	if err != nil {
@@ -145,13 +158,43 @@ func (c *httpClient) Dial(ctx context.Context, endpoint string, in apimodels.Req
 	}
 	defer resp.Body.Close()
 
+	// The connection supports one concurrent writer, which is either the input
+	// writer or the close message once the connection is done.
+	var writeMu sync.Mutex
+	done := make(chan struct{})
+	if input != nil {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-done:
+					return
+				case msg, ok := <-input:
+					if !ok {
+						return
+					}
+					writeMu.Lock()
+					err := conn.WriteMessage(websocket.TextMessage, msg)
+					writeMu.Unlock()
+					if err != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
 	// Read messages from the server, and send them until the conn is closed or
 	// the context is cancelled. We have to read them here because the reader
 	// will be discarded upon the next call to NextReader.
 	output := make(chan *concurrency.AsyncResult[[]byte], c.config.WebsocketChannelBuffer)
 	go func() {
 		defer func() {
+			close(done)
+			writeMu.Lock()
 			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			writeMu.Unlock()
 			conn.Close()
 			close(output)
 		}()
@@ -356,6 +399,20 @@ func (t *AuthenticatingClient) Dial(
 	return output, err
 }
 
+func (t *AuthenticatingClient) DialWithInput(
+	ctx context.Context,
+	path string,
+	in apimodels.Request,
+	input <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	var output <-chan *concurrency.AsyncResult[[]byte]
+	err := doRequest(t, in, func(req apimodels.Request) (err error) {
+		output, err = t.Client.DialWithInput(ctx, path, req, input)
+		return
+	})
+	return output, err
+}
+
 func doRequest[R apimodels.Request](t *AuthenticatingClient, request R, runRequest func(R) error) (err error) {
 	if t.Credential != nil {
 		request.SetCredential(t.Credential)
@@ -17,6 +17,8 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 )
@@ -109,12 +111,59 @@ func DialAsyncResult[In apimodels.Request, Out any](
 	endpoint string,
 	r In,
 ) (<-chan *concurrency.AsyncResult[Out], error) {
-	output := make(chan *concurrency.AsyncResult[Out])
-
 	input, err := client.Dial(ctx, endpoint, r)
 	if err != nil {
 		return nil, err
 	}
+	return decodeAsyncResults[Out](input), nil
+}
+
+// DialAsyncResultWithInput is like DialAsyncResult, but also encodes the objects
+// received from the input channel and sends them to the server.
+func DialAsyncResultWithInput[In apimodels.Request, Msg any, Out any](
+	ctx context.Context,
+	client Client,
+	endpoint string,
+	r In,
+	messages <-chan Msg,
+) (<-chan *concurrency.AsyncResult[Out], error) {
+	encoded := make(chan []byte)
+	go func() {
+		defer close(encoded)
+		for {
+			var msg Msg
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok = <-messages:
+				if !ok {
+					return
+				}
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				log.Ctx(ctx).Error().Err(err).Msgf("failed to encode %T", msg)
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case encoded <- data:
+			}
+		}
+	}()
+
+	input, err := client.DialWithInput(ctx, endpoint, r, encoded)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAsyncResults[Out](input), nil
+}
+
+// decodeAsyncResults decodes the received messages as AsyncResult objects.
+func decodeAsyncResults[Out any](input <-chan *concurrency.AsyncResult[[]byte]) <-chan *concurrency.AsyncResult[Out] {
+	output := make(chan *concurrency.AsyncResult[Out])
 	go func() {
 		for result := range input {
 			outResult := new(concurrency.AsyncResult[Out])
@@ -128,6 +177,5 @@ func DialAsyncResult[In apimodels.Request, Out any](
 		}
 		close(output)
 	}()
-
-	return output, nil
+	return output
 }
@@ -0,0 +1,115 @@
+package orchestrator
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/apikey"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator ListAPIKeys
+//
+// @ID			orchestrator/listAPIKeys
+// @Summary		Returns the API keys issued by the requester.
+// @Description	Returns the API keys issued by the requester, without the keys themselves.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Success		200	{object}	apimodels.ListAPIKeysResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/apikeys [get]
+func (e *Endpoint) listAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.ListAPIKeysRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	keys, err := e.apiKeyManager.List(ctx)
+	if err != nil {
+		return err
+	}
+	if args.Limit > 0 && len(keys) > int(args.Limit) {
+		keys = keys[:args.Limit]
+	}
+	return c.JSON(http.StatusOK, &apimodels.ListAPIKeysResponse{
+		APIKeys: keys,
+	})
+}
+
+// godoc for Orchestrator CreateAPIKey
+//
+// @ID			orchestrator/createAPIKey
+// @Summary		Issues a new API key.
+// @Description	Issues a new API key scoped to namespaces and permissions, which must be granted by the access token of the caller. The key is only returned in this response, as only its hash is stored.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			apikey	body	apimodels.CreateAPIKeyRequest	true	"API key to create"
+// @Success		200	{object}	apimodels.CreateAPIKeyResponse
+// @Failure		400	{object}	string
+// @Failure		403	{object}	string
+// @Failure		409	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/apikeys [post]
+func (e *Endpoint) createAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.CreateAPIKeyRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	// the key can only grant what the access token of the caller grants
+	authorization, _ := authz.AuthorizationFromContext(ctx)
+	key, token, err := e.apiKeyManager.Create(ctx, apikey.CreateParams{
+		Name:        args.Name,
+		Namespaces:  args.Namespaces,
+		Permissions: args.Permissions,
+		ExpiresIn:   args.ExpiresIn,
+		Granted:     authorization.Namespaces,
+	})
+	if err != nil {
+		if errors.As(err, &apikey.ErrAPIKeyAlreadyExists{}) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		if errors.As(err, &apikey.ErrAPIKeyScopeExceeded{}) {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, &apimodels.CreateAPIKeyResponse{
+		APIKey: key,
+		Key:    token,
+	})
+}
+
+// godoc for Orchestrator RevokeAPIKey
+//
+// @ID			orchestrator/revokeAPIKey
+// @Summary		Revokes an API key.
+// @Description	Revokes an API key, which is no longer accepted.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			name	path	string	true	"Name of the API key"
+// @Success		200	{object}	apimodels.RevokeAPIKeyResponse
+// @Failure		404	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/apikeys/{name} [delete]
+func (e *Endpoint) revokeAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := e.apiKeyManager.Revoke(ctx, c.Param("name")); err != nil {
+		if errors.As(err, &apikey.ErrAPIKeyNotFound{}) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.RevokeAPIKeyResponse{})
+}
@@ -0,0 +1,60 @@
+package orchestrator
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator ListAuditEvents
+//
+// @ID			orchestrator/listAuditEvents
+// @Summary		Returns the audit log of the requester.
+// @Description	Returns the mutating API requests and node actions recorded in the audit log, in the order they were recorded.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			principal	query	string	false	"Only return events of this principal"
+// @Param			action		query	string	false	"Only return events of this action"
+// @Param			target		query	string	false	"Only return events on this target"
+// @Param			after		query	int		false	"Only return events recorded at or after this unix time in seconds"
+// @Param			before		query	int		false	"Only return events recorded before this unix time in seconds"
+// @Param			limit		query	int		false	"Only return the most recent events"
+// @Success		200	{object}	apimodels.ListAuditEventsResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/audit [get]
+func (e *Endpoint) listAuditEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.ListAuditEventsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	query := audit.Query{
+		Principal: args.Principal,
+		Action:    args.Action,
+		Target:    args.Target,
+		Limit:     int(args.Limit),
+	}
+	if args.After != 0 {
+		query.After = time.Unix(args.After, 0)
+	}
+	if args.Before != 0 {
+		query.Before = time.Unix(args.Before, 0)
+	}
+	events, err := e.auditRecorder.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.ListAuditEventsResponse{
+		Events: events,
+	})
+}
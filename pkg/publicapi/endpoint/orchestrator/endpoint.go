@@ -4,6 +4,11 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/node/manager"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/apikey"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retention"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/secret"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/middleware"
 	"github.com/labstack/echo/v4"
 )
@@ -13,21 +18,41 @@ type EndpointParams struct {
 	Orchestrator *orchestrator.BaseEndpoint
 	JobStore     jobstore.Store
 	NodeManager  *manager.NodeManager
+	// QuotaEnforcer serves the namespace quotas. The quota APIs are not registered if nil.
+	QuotaEnforcer *quota.Enforcer
+	// SecretStore holds the secrets of namespaces. The secret APIs are not registered if nil.
+	SecretStore secret.Store
+	// APIKeyManager issues the API keys of service accounts. The API key APIs are not registered if nil.
+	APIKeyManager *apikey.Manager
+	// AuditRecorder records the audit log of the requester. The audit APIs are not registered if nil.
+	AuditRecorder *audit.Recorder
+	// Reaper prunes terminal jobs from the job store. The prune API is not registered if nil.
+	Reaper *retention.Reaper
 }
 
 type Endpoint struct {
-	router       *echo.Echo
-	orchestrator *orchestrator.BaseEndpoint
-	store        jobstore.Store
-	nodeManager  *manager.NodeManager
+	router        *echo.Echo
+	orchestrator  *orchestrator.BaseEndpoint
+	store         jobstore.Store
+	nodeManager   *manager.NodeManager
+	quotaEnforcer *quota.Enforcer
+	secretStore   secret.Store
+	apiKeyManager *apikey.Manager
+	auditRecorder *audit.Recorder
+	reaper        *retention.Reaper
 }
 
 func NewEndpoint(params EndpointParams) *Endpoint {
 	e := &Endpoint{
-		router:       params.Router,
-		orchestrator: params.Orchestrator,
-		store:        params.JobStore,
-		nodeManager:  params.NodeManager,
+		router:        params.Router,
+		orchestrator:  params.Orchestrator,
+		store:         params.JobStore,
+		nodeManager:   params.NodeManager,
+		quotaEnforcer: params.QuotaEnforcer,
+		secretStore:   params.SecretStore,
+		apiKeyManager: params.APIKeyManager,
+		auditRecorder: params.AuditRecorder,
+		reaper:        params.Reaper,
 	}
 
 	// JSON group
@@ -36,14 +61,39 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 	g.PUT("/jobs", e.putJob)
 	g.POST("/jobs", e.putJob)
 	g.GET("/jobs", e.listJobs)
+	if e.reaper != nil {
+		g.POST("/jobs/prune", e.pruneJobs)
+	}
 	g.GET("/jobs/:id", e.getJob)
 	g.DELETE("/jobs/:id", e.stopJob)
+	g.PUT("/jobs/:id/rollback", e.rollbackJob)
 	g.GET("/jobs/:id/history", e.jobHistory)
 	g.GET("/jobs/:id/executions", e.jobExecutions)
 	g.GET("/jobs/:id/results", e.jobResults)
 	g.GET("/jobs/:id/logs", e.logs)
+	g.GET("/jobs/:id/exec", e.exec)
+	g.GET("/services/:name", e.discoverService)
 	g.GET("/nodes", e.listNodes)
 	g.GET("/nodes/:id", e.getNode)
 	g.PUT("/nodes/:id", e.updateNode)
+	if e.quotaEnforcer != nil {
+		g.GET("/quotas", e.listQuotas)
+		g.GET("/quotas/:namespace", e.getQuota)
+		g.PUT("/quotas/:namespace", e.putQuota)
+		g.DELETE("/quotas/:namespace", e.deleteQuota)
+	}
+	if e.secretStore != nil {
+		g.GET("/secrets", e.listSecrets)
+		g.PUT("/secrets/:namespace/:name", e.putSecret)
+		g.DELETE("/secrets/:namespace/:name", e.deleteSecret)
+	}
+	if e.apiKeyManager != nil {
+		g.GET("/apikeys", e.listAPIKeys)
+		g.POST("/apikeys", e.createAPIKey)
+		g.DELETE("/apikeys/:name", e.revokeAPIKey)
+	}
+	if e.auditRecorder != nil {
+		g.GET("/audit", e.listAuditEvents)
+	}
 	return e
 }
@@ -1,9 +1,11 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/gorilla/websocket"
@@ -49,6 +51,7 @@ func (e *Endpoint) putJob(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	c.Response().Header().Set(apimodels.HTTPHeaderJobID, resp.JobID)
 	return c.JSON(http.StatusOK, apimodels.PutJobResponse{
 		JobID:        resp.JobID,
 		EvaluationID: resp.EvaluationID,
@@ -137,6 +140,12 @@ func (e *Endpoint) getJob(c echo.Context) error {
 // @Param			next_token	query	string	false	"Token to get the next page of jobs"
 // @Param			reverse	query	bool	false		"Reverse the order of the jobs"
 // @Param			order_by	query	string	false	"Order the jobs by the given field"
+// @Param			state	query	[]string	false	"Only return jobs in any of the given states"
+// @Param			type	query	[]string	false	"Only return jobs of any of the given types"
+// @Param			engine	query	[]string	false	"Only return jobs using any of the given engines"
+// @Param			name_prefix	query	string	false	"Only return jobs whose name starts with the prefix"
+// @Param			created_after	query	int	false	"Only return jobs created at or after the given unix time"
+// @Param			created_before	query	int	false	"Only return jobs created before the given unix time"
 // @Success		200	{object}	apimodels.ListJobsResponse
 // @Failure		400	{object}	string
 // @Failure		500	{object}	string
@@ -152,11 +161,13 @@ func (e *Endpoint) listJobs(c echo.Context) error {
 	}
 
 	var offset uint32
+	var cursor *jobstore.JobCursor
 	var err error
 
 	// If the request contains a paging token then it is decoded and used to replace
 	// any other values provided in the request. This allows for stable sorting to
-	// allow the pagination to work correctly.
+	// allow the pagination to work correctly. The filters are not part of the token,
+	// and are expected to be the same for all pages.
 	if args.NextToken != "" {
 		token, err := models.NewPagingTokenFromString(args.NextToken)
 		if err != nil {
@@ -168,6 +179,9 @@ func (e *Endpoint) listJobs(c echo.Context) error {
 		args.Reverse = token.SortReverse
 		args.Limit = token.Limit
 		offset = token.Offset
+		if token.HasCursor() {
+			cursor = &jobstore.JobCursor{ID: token.CursorID, SortValue: token.CursorSortValue}
+		}
 	}
 
 	selector, err := parseLabels(c)
@@ -175,13 +189,29 @@ func (e *Endpoint) listJobs(c echo.Context) error {
 		return err
 	}
 
+	states, err := args.JobStates()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	query := jobstore.JobQuery{
 		Namespace:   args.Namespace,
 		Limit:       args.Limit,
 		Offset:      offset,
+		Cursor:      cursor,
 		SortBy:      args.OrderBy,
 		SortReverse: args.Reverse,
 		Selector:    selector,
+		States:      states,
+		Types:       args.Types,
+		EngineTypes: args.EngineTypes,
+		NamePrefix:  args.NamePrefix,
+	}
+	if args.CreatedAfter != 0 {
+		query.CreatedAfter = time.Unix(args.CreatedAfter, 0)
+	}
+	if args.CreatedBefore != 0 {
+		query.CreatedBefore = time.Unix(args.CreatedBefore, 0)
 	}
 
 	if args.Namespace == apimodels.AllNamespacesNamespace {
@@ -195,16 +225,18 @@ func (e *Endpoint) listJobs(c echo.Context) error {
 	}
 
 	var nextToken string
-	// If the next offset > 0 then it means there are more records to be returned, so
+	// If there is a next cursor then it means there are more records to be returned, so
 	// we should give the user a token to use that will return the next page of results.
 	// We encode the current settings into the token to maintain a stable sort across
-	// pages.
-	if response.NextOffset != 0 {
+	// pages, and the position of the last job so that the next page starts right after
+	// it even if jobs are created or removed in the meantime.
+	if response.NextCursor != nil {
 		nextToken = models.NewPagingToken(&models.PagingTokenParams{
-			SortBy:      args.OrderBy,
-			SortReverse: args.Reverse,
-			Limit:       args.Limit,
-			Offset:      response.NextOffset,
+			SortBy:          args.OrderBy,
+			SortReverse:     args.Reverse,
+			Limit:           args.Limit,
+			CursorID:        response.NextCursor.ID,
+			CursorSortValue: response.NextCursor.SortValue,
 		}).String()
 	}
 
@@ -237,6 +269,7 @@ func (e *Endpoint) listJobs(c echo.Context) error {
 func (e *Endpoint) stopJob(c echo.Context) error {
 	ctx := c.Request().Context()
 	jobID := c.Param("id")
+	c.Response().Header().Set(apimodels.HTTPHeaderJobID, jobID)
 
 	var args apimodels.StopJobRequest
 	if err := c.Bind(&args); err != nil {
@@ -258,6 +291,45 @@ func (e *Endpoint) stopJob(c echo.Context) error {
 	})
 }
 
+// godoc for Orchestrator RollbackJob
+//
+// @ID			orchestrator/rollbackJob
+// @Summary		Rolls a job back to a previous version.
+// @Description	Rolls a long-running job back to one of its previous versions, which becomes a new version of the job.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			id	path	string	true	"ID of the job to roll back"
+// @Param			rollbackJobRequest	body	apimodels.RollbackJobRequest	true	"Version to roll back to"
+// @Success		200	{object}	apimodels.RollbackJobResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/jobs/{id}/rollback [put]
+func (e *Endpoint) rollbackJob(c echo.Context) error {
+	ctx := c.Request().Context()
+	jobID := c.Param("id")
+	c.Response().Header().Set(apimodels.HTTPHeaderJobID, jobID)
+
+	var args apimodels.RollbackJobRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+	resp, err := e.orchestrator.RollbackJob(ctx, &orchestrator.RollbackJobRequest{
+		JobID:   jobID,
+		Version: args.Version,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.RollbackJobResponse{
+		JobVersion:   resp.JobVersion,
+		EvaluationID: resp.EvaluationID,
+	})
+}
+
 // godoc for Orchestrator JobHistory
 //
 // @ID			orchestrator/jobHistory
@@ -439,6 +511,7 @@ func (e *Endpoint) jobResults(c echo.Context) error {
 // @Summary			Displays the logs for a current job/execution
 // @Description		Shows the output from the job specified by `id`
 // @Description		The output will be continuous until either, the client disconnects or the execution completes.
+// @Description		The logs of completed executions are served from the logs persisted by compute nodes when available.
 // @Tags			Orchestrator
 // @Accept			json
 // @Produce			json
@@ -446,6 +519,9 @@ func (e *Endpoint) jobResults(c echo.Context) error {
 // @Param			execution_id	query 	string	false	"Fetch logs for a specific execution"
 // @Param			tail	query	bool	false	"Fetch historical logs"
 // @Param			follow			query	bool	false	"Follow the logs"
+// @Param			offset			query	int		false	"Number of lines to skip from the start of the logs"
+// @Param			tail_lines		query	int		false	"Only return the last N lines of the logs"
+// @Param			since			query	int		false	"Only return the logs written after this time, in unix seconds"
 // @Success		200			{object}	string
 // @Failure		400			{object}	string
 // @Failure		500			{object}	string
@@ -481,11 +557,18 @@ func (e *Endpoint) logsWS(c echo.Context, ws *websocket.Conn) error {
 		return err
 	}
 
+	var since time.Time
+	if args.Since != 0 {
+		since = time.Unix(args.Since, 0)
+	}
 	logstreamCh, err := e.orchestrator.ReadLogs(c.Request().Context(), orchestrator.ReadLogsRequest{
 		JobID:       jobID,
 		ExecutionID: args.ExecutionID,
 		Tail:        args.Tail,
 		Follow:      args.Follow,
+		Offset:      args.Offset,
+		TailLines:   args.TailLines,
+		Since:       since,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to open log stream for job %s: %w", jobID, err)
@@ -498,3 +581,120 @@ func (e *Endpoint) logsWS(c echo.Context, ws *websocket.Conn) error {
 	}
 	return nil
 }
+
+// godoc for Orchestrator JobExec
+//
+// @ID				orchestrator/exec
+// @Summary			Executes a command inside a running execution of a job
+// @Description		Runs the command inside the execution, similarly to `docker exec`, and streams its output until it exits.
+// @Description		The client sends models.ExecInput messages to write to the standard input of the command or to resize its terminal,
+// @Description		and receives models.ExecOutput messages, the last of which holds the exit code of the command.
+// @Description		Executing commands requires the exec permission on the namespace of the job.
+// @Tags			Orchestrator
+// @Accept			json
+// @Produce			json
+// @Param			id				path	string		true	"ID of the job to execute the command in"
+// @Param			namespace		query	string		false	"Namespace of the job"
+// @Param			execution_id	query	string		false	"Execution to execute the command in. Defaults to the latest running execution"
+// @Param			command			query	[]string	true	"Command to execute and its arguments"	collectionFormat(multi)
+// @Param			tty				query	bool		false	"Allocate a terminal for the command"
+// @Param			stdin			query	bool		false	"Attach the standard input of the command"
+// @Param			height			query	int			false	"Initial height of the terminal"
+// @Param			width			query	int			false	"Initial width of the terminal"
+// @Success		200			{object}	string
+// @Failure		400			{object}	string
+// @Failure		500			{object}	string
+// @Router			/api/v1/orchestrator/jobs/{id}/exec [get]
+func (e *Endpoint) exec(c echo.Context) error {
+	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade websocket connection: %w", err)
+	}
+	defer ws.Close()
+
+	err = e.execWS(c, ws)
+	if err != nil {
+		log.Ctx(c.Request().Context()).Error().Err(err).Msg("websocket failure")
+		err = ws.WriteJSON(concurrency.AsyncResult[models.ExecOutput]{
+			Err: err,
+		})
+		if err != nil {
+			log.Ctx(c.Request().Context()).Error().Err(err).Msg("failed to write error to websocket")
+		}
+	}
+	_ = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return nil
+}
+
+func (e *Endpoint) execWS(c echo.Context, ws *websocket.Conn) error {
+	jobID := c.Param("id")
+	var args apimodels.ExecRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	// the command is stopped if the client goes away
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	// the authorization policy grants the exec permission on the requested namespace,
+	// so commands can only be executed in the jobs of that namespace
+	job, err := e.store.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	namespace := args.Namespace
+	if namespace == "" {
+		namespace = models.DefaultNamespace
+	}
+	if job.Namespace != namespace {
+		return fmt.Errorf("job %s not found in namespace %s", jobID, namespace)
+	}
+
+	var size *models.TerminalSize
+	if args.Height != 0 && args.Width != 0 {
+		size = &models.TerminalSize{Height: args.Height, Width: args.Width}
+	}
+	input := make(chan models.ExecInput)
+	go readExecInput(ctx, cancel, ws, input)
+
+	outputs, err := e.orchestrator.Exec(ctx, orchestrator.ExecRequest{
+		JobID:       job.ID,
+		ExecutionID: args.ExecutionID,
+		Command:     args.Command,
+		TTY:         args.TTY,
+		Stdin:       args.Stdin,
+		Size:        size,
+		Input:       input,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exec in job %s: %w", jobID, err)
+	}
+
+	for output := range outputs {
+		if err = ws.WriteJSON(output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readExecInput forwards the input sent by the client until the websocket is closed,
+// in which case the context is cancelled.
+func readExecInput(ctx context.Context, cancel context.CancelFunc, ws *websocket.Conn, input chan<- models.ExecInput) {
+	defer cancel()
+	for {
+		var in models.ExecInput
+		if err := ws.ReadJSON(&in); err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case input <- in:
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/exp/slices"
@@ -163,6 +164,11 @@ func (e *Endpoint) updateNode(c echo.Context) error {
 		return err
 	}
 
+	// resolve the full node id, as evaluations are matched against it
+	if nodeState, err := e.nodeManager.GetByPrefix(ctx, nodeID); err == nil {
+		nodeID = nodeState.Info.ID()
+	}
+
 	var action func(context.Context, string, string) (bool, string)
 	if args.Action == string(apimodels.NodeActionApprove) {
 		action = e.nodeManager.ApproveAction
@@ -170,6 +176,19 @@ func (e *Endpoint) updateNode(c echo.Context) error {
 		action = e.nodeManager.RejectAction
 	} else if args.Action == string(apimodels.NodeActionDelete) {
 		action = e.nodeManager.DeleteAction
+	} else if args.Action == string(apimodels.NodeActionCordon) {
+		action = e.nodeManager.CordonAction
+	} else if args.Action == string(apimodels.NodeActionUncordon) {
+		action = e.nodeManager.UncordonAction
+	} else if args.Action == string(apimodels.NodeActionDrain) {
+		var gracePeriod *time.Duration
+		if args.GracePeriod != nil {
+			seconds := time.Duration(*args.GracePeriod) * time.Second
+			gracePeriod = &seconds
+		}
+		action = func(ctx context.Context, nodeID string, reason string) (bool, string) {
+			return e.nodeManager.DrainAction(ctx, nodeID, reason, gracePeriod)
+		}
 	} else {
 		action = func(context.Context, string, string) (bool, string) {
 			return false, "unsupported action"
@@ -177,6 +196,13 @@ func (e *Endpoint) updateNode(c echo.Context) error {
 	}
 
 	success, msg := action(ctx, nodeID, args.Message)
+
+	// move or recreate the executions of long-running jobs affected by the node update
+	if success && (args.Action == string(apimodels.NodeActionDrain) || args.Action == string(apimodels.NodeActionUncordon)) {
+		if err := e.orchestrator.EvaluateNodeJobs(ctx, nodeID); err != nil {
+			return err
+		}
+	}
 	return c.JSON(http.StatusOK, apimodels.PutNodeResponse{
 		Success: success,
 		Error:   msg,
@@ -0,0 +1,154 @@
+package orchestrator
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/quota"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator ListQuotas
+//
+// @ID			orchestrator/listQuotas
+// @Summary		Returns the quotas of all namespaces.
+// @Description	Returns the quotas of all namespaces.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Success		200	{object}	apimodels.ListQuotasResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/quotas [get]
+func (e *Endpoint) listQuotas(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.ListQuotasRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	quotas, err := e.quotaEnforcer.Store().List(ctx)
+	if err != nil {
+		return err
+	}
+	res := make([]*models.NamespaceQuota, len(quotas))
+	for i := range quotas {
+		res[i] = &quotas[i]
+	}
+	if args.Limit > 0 && len(res) > int(args.Limit) {
+		res = res[:args.Limit]
+	}
+	return c.JSON(http.StatusOK, &apimodels.ListQuotasResponse{
+		Quotas: res,
+	})
+}
+
+// godoc for Orchestrator GetQuota
+//
+// @ID			orchestrator/getQuota
+// @Summary		Returns the quota of a namespace and its current usage.
+// @Description	Returns the quota of a namespace and its current usage.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string	true	"Namespace to get the quota for"
+// @Success		200	{object}	apimodels.GetQuotaResponse
+// @Failure		400	{object}	string
+// @Failure		404	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/quotas/{namespace} [get]
+func (e *Endpoint) getQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	namespace := c.Param("namespace")
+	q, err := e.quotaEnforcer.Store().Get(ctx, namespace)
+	if err != nil {
+		return quotaError(err)
+	}
+	usage, err := e.quotaEnforcer.Usage(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.GetQuotaResponse{
+		Quota: &q,
+		Usage: &usage,
+	})
+}
+
+// godoc for Orchestrator PutQuota
+//
+// @ID			orchestrator/putQuota
+// @Summary		Creates or replaces the quota of a namespace.
+// @Description	Creates or replaces the quota of a namespace.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string					true	"Namespace to set the quota for"
+// @Param			quota		body	apimodels.PutQuotaRequest	true	"Quota to set"
+// @Success		200	{object}	apimodels.PutQuotaResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/quotas/{namespace} [put]
+func (e *Endpoint) putQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.PutQuotaRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if args.Quota == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing quota")
+	}
+	args.Quota.Namespace = c.Param("namespace")
+	args.Normalize()
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().UnixNano()
+	args.Quota.CreateTime = now
+	args.Quota.ModifyTime = now
+	existing, err := e.quotaEnforcer.Store().Get(ctx, args.Quota.Namespace)
+	if err == nil {
+		args.Quota.CreateTime = existing.CreateTime
+	} else if !errors.As(err, &quota.ErrQuotaNotFound{}) {
+		return err
+	}
+
+	if err = e.quotaEnforcer.Store().Put(ctx, *args.Quota); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.PutQuotaResponse{
+		Quota: args.Quota,
+	})
+}
+
+// godoc for Orchestrator DeleteQuota
+//
+// @ID			orchestrator/deleteQuota
+// @Summary		Removes the quota of a namespace.
+// @Description	Removes the quota of a namespace, lifting all its limits.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string	true	"Namespace to remove the quota of"
+// @Success		200	{object}	apimodels.DeleteQuotaResponse
+// @Failure		404	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/quotas/{namespace} [delete]
+func (e *Endpoint) deleteQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := e.quotaEnforcer.Store().Delete(ctx, c.Param("namespace")); err != nil {
+		return quotaError(err)
+	}
+	return c.JSON(http.StatusOK, &apimodels.DeleteQuotaResponse{})
+}
+
+// quotaError maps a missing quota to a not found response
+func quotaError(err error) error {
+	if errors.As(err, &quota.ErrQuotaNotFound{}) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return err
+}
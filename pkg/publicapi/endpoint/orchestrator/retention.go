@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator PruneJobs
+//
+// @ID			orchestrator/pruneJobs
+// @Summary		Removes terminal jobs that are no longer retained by the retention policies.
+// @Description	Removes terminal jobs, along with their executions, evaluations and history,
+// @Description	that are no longer retained by the retention policies. With DryRun, the jobs are only listed.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			request	body	apimodels.PruneJobsRequest	true	"Prune request"
+// @Success		200	{object}	apimodels.PruneJobsResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/jobs/prune [post]
+func (e *Endpoint) pruneJobs(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.PruneJobsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	args.Normalize()
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	policies := e.reaper.Policies()
+	if len(args.Policies) > 0 {
+		policies = lo.Map(args.Policies, func(p *models.RetentionPolicy, _ int) models.RetentionPolicy { return *p })
+	}
+	pruned, err := e.reaper.Prune(ctx, policies, args.DryRun)
+	if err != nil {
+		return err
+	}
+	res := make([]*models.PrunedJob, len(pruned))
+	for i := range pruned {
+		res[i] = &pruned[i]
+	}
+	return c.JSON(http.StatusOK, &apimodels.PruneJobsResponse{
+		DryRun: args.DryRun,
+		Jobs:   res,
+	})
+}
@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/secret"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator ListSecrets
+//
+// @ID			orchestrator/listSecrets
+// @Summary		Returns the secrets of a namespace, without their values.
+// @Description	Returns the secrets of a namespace, or of all namespaces if none is set, without their values.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	query	string	false	"Namespace to list the secrets of"
+// @Success		200	{object}	apimodels.ListSecretsResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/secrets [get]
+func (e *Endpoint) listSecrets(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.ListSecretsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	secrets, err := e.secretStore.List(ctx, args.Namespace)
+	if err != nil {
+		return err
+	}
+	res := make([]*models.Secret, len(secrets))
+	for i := range secrets {
+		res[i] = secrets[i].Redacted()
+	}
+	if args.Limit > 0 && len(res) > int(args.Limit) {
+		res = res[:args.Limit]
+	}
+	return c.JSON(http.StatusOK, &apimodels.ListSecretsResponse{
+		Secrets: res,
+	})
+}
+
+// godoc for Orchestrator PutSecret
+//
+// @ID			orchestrator/putSecret
+// @Summary		Creates or replaces a secret of a namespace.
+// @Description	Creates or replaces a secret of a namespace. The value of the secret is stored encrypted, and is never returned.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string						true	"Namespace of the secret"
+// @Param			name		path	string						true	"Name of the secret"
+// @Param			secret		body	apimodels.PutSecretRequest	true	"Secret to set"
+// @Success		200	{object}	apimodels.PutSecretResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/secrets/{namespace}/{name} [put]
+func (e *Endpoint) putSecret(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.PutSecretRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if args.Secret == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing secret")
+	}
+	args.Secret.Namespace = c.Param("namespace")
+	args.Secret.Name = c.Param("name")
+	args.Normalize()
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().UnixNano()
+	args.Secret.CreateTime = now
+	args.Secret.ModifyTime = now
+	existing, err := e.secretStore.Get(ctx, args.Secret.Namespace, args.Secret.Name)
+	if err == nil {
+		args.Secret.CreateTime = existing.CreateTime
+	} else if !errors.As(err, &secret.ErrSecretNotFound{}) {
+		return err
+	}
+
+	if err = e.secretStore.Put(ctx, *args.Secret); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.PutSecretResponse{
+		Secret: args.Secret.Redacted(),
+	})
+}
+
+// godoc for Orchestrator DeleteSecret
+//
+// @ID			orchestrator/deleteSecret
+// @Summary		Removes a secret of a namespace.
+// @Description	Removes a secret of a namespace. Jobs referencing the secret fail to start new executions.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string	true	"Namespace of the secret"
+// @Param			name		path	string	true	"Name of the secret"
+// @Success		200	{object}	apimodels.DeleteSecretResponse
+// @Failure		404	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/secrets/{namespace}/{name} [delete]
+func (e *Endpoint) deleteSecret(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := e.secretStore.Delete(ctx, c.Param("namespace"), c.Param("name")); err != nil {
+		if errors.As(err, &secret.ErrSecretNotFound{}) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.DeleteSecretResponse{})
+}
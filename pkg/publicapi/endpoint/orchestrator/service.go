@@ -0,0 +1,80 @@
+package orchestrator
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator DiscoverService
+//
+// @ID			orchestrator/discoverService
+// @Summary		Returns the endpoints of a running service.
+// @Description	Returns the addresses of the ports published by the running and healthy executions of the service or daemon jobs with the given name.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			name	path	string	true	"Name of the service or daemon job"
+// @Param			namespace	query	string	false	"Namespace of the job"
+// @Param			port	query	string	false	"Only return the endpoints of the port with this name"
+// @Success		200	{object}	apimodels.DiscoverServiceResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/services/{name} [get]
+func (e *Endpoint) discoverService(c echo.Context) error {
+	ctx := c.Request().Context()
+	name := c.Param("name")
+	var args apimodels.DiscoverServiceRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	response, err := e.store.GetJobs(ctx, jobstore.JobQuery{
+		Namespace:  args.Namespace,
+		ReturnAll:  true,
+		States:     []models.JobStateType{models.JobStateTypeRunning},
+		Types:      []string{models.JobTypeService, models.JobTypeDaemon},
+		NamePrefix: name,
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoints := make([]*apimodels.ServiceEndpoint, 0)
+	for _, job := range response.Jobs {
+		if job.Name != name {
+			continue
+		}
+		executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+		if err != nil {
+			return err
+		}
+		for _, execution := range executions {
+			// only running executions that are expected to keep running serve requests
+			if execution.ComputeState.StateType != models.ExecutionStateBidAccepted ||
+				execution.DesiredState.StateType != models.ExecutionDesiredStateRunning ||
+				execution.IsUnhealthy() {
+				continue
+			}
+			for _, port := range execution.Ports {
+				if args.Port != "" && port.Name != args.Port {
+					continue
+				}
+				endpoints = append(endpoints, &apimodels.ServiceEndpoint{
+					JobID:       job.ID,
+					ExecutionID: execution.ID,
+					NodeID:      execution.NodeID,
+					Port:        port.Name,
+					Address:     port.Address(),
+				})
+			}
+		}
+	}
+	return c.JSON(http.StatusOK, &apimodels.DiscoverServiceResponse{
+		Endpoints: endpoints,
+	})
+}
@@ -1,26 +1,129 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/labstack/echo/v4"
 )
 
 // Authorize only allows the HTTP request to continue if the passed authorizer
-// permits the request.
-func Authorize(authorizer authz.Authorizer) echo.MiddlewareFunc {
+// permits the request. If a recorder is passed, requests that may modify state are
+// recorded in the audit log along with their outcome, including those that are
+// denied, and the principal and authorization of the request are made available
+// to the handlers.
+func Authorize(authorizer authz.Authorizer, recorder *audit.Recorder) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			if result, err := authorizer.Authorize(c.Request()); err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			result, err := authorizer.Authorize(c.Request())
+
+			req := c.Request()
+			request := audit.Request{
+				ID:        requestID(c),
+				Principal: principal(req, result),
+			}
+			ctx := audit.ContextWithRequest(req.Context(), request)
+			c.SetRequest(req.WithContext(authz.ContextWithAuthorization(ctx, result)))
+
+			if err != nil {
+				err = echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 			} else if !result.Approved && result.TokenValid {
-				return echo.NewHTTPError(http.StatusForbidden, "Access denied. "+result.Reason)
+				err = echo.NewHTTPError(http.StatusForbidden, "Access denied. "+result.Reason)
 			} else if !result.Approved && !result.TokenValid {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token. "+result.Reason)
+				err = echo.NewHTTPError(http.StatusUnauthorized, "Invalid token. "+result.Reason)
+			} else if execRoutes[c.Path()] && !result.Exec {
+				err = echo.NewHTTPError(http.StatusForbidden, "Access denied. Executing commands in jobs requires the exec permission")
 			} else {
-				return next(c)
+				err = next(c)
 			}
+
+			if recorder != nil && isMutating(c) {
+				recorder.Record(c.Request().Context(), auditEvent(c, err))
+			}
+			return err
 		}
 	}
 }
+
+// requestID returns the ID of the request set by the request ID middleware
+func requestID(c echo.Context) string {
+	if id := c.Request().Header.Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Response().Header().Get(echo.HeaderXRequestID)
+}
+
+// principal returns who the request is made by, for the audit log
+func principal(req *http.Request, result authz.Authorization) string {
+	switch {
+	case result.Principal != "":
+		return result.Principal
+	case req.Header.Get("Authorization") == "":
+		return models.AuditPrincipalAnonymous
+	default:
+		return models.AuditPrincipalUnknown
+	}
+}
+
+// auditEvent returns the audit event of a request that was handled with the error
+func auditEvent(c echo.Context, err error) models.AuditEvent {
+	req := c.Request()
+	event := models.AuditEvent{
+		Action:     req.Method + " " + c.Path(),
+		Target:     req.URL.Path,
+		StatusCode: c.Response().Status,
+		Outcome:    models.AuditOutcomeSuccess,
+	}
+	// requests that did not match a route have no path
+	if c.Path() == "" {
+		event.Action = req.Method + " " + req.URL.Path
+	}
+	// requests that acted on a job are audited against the job
+	if jobID := c.Response().Header().Get(apimodels.HTTPHeaderJobID); jobID != "" {
+		event.Target = jobID
+	}
+
+	if err != nil {
+		event.StatusCode = http.StatusInternalServerError
+		event.Reason = err.Error()
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			event.StatusCode = httpErr.Code
+			event.Reason = fmt.Sprint(httpErr.Message)
+		}
+	}
+	switch {
+	case event.StatusCode == http.StatusUnauthorized || event.StatusCode == http.StatusForbidden:
+		event.Outcome = models.AuditOutcomeDenied
+	case event.StatusCode >= http.StatusBadRequest:
+		event.Outcome = models.AuditOutcomeFailure
+	}
+	return event
+}
+
+// execRoutes are the routes that execute commands in running jobs over a
+// websocket. They are requested with GET, so they require the exec permission
+// of the authorization on top of its approval, and are audited.
+var execRoutes = map[string]bool{
+	"/api/v1/orchestrator/jobs/:id/exec": true,
+}
+
+// isMutating returns true if the request may modify state
+func isMutating(c echo.Context) bool {
+	return !isSafeMethod(c.Request().Method) || execRoutes[c.Path()]
+}
+
+// isSafeMethod returns true if the HTTP method does not modify any state
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
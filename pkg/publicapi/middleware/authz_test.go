@@ -0,0 +1,166 @@
+//go:build unit || !integration
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	echomiddelware "github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// fakeAuthorizer approves requests with a bearer token, whose principal is the token.
+// Only alice is permitted to execute commands in jobs.
+type fakeAuthorizer struct{}
+
+func (fakeAuthorizer) Authorize(req *http.Request) (authz.Authorization, error) {
+	token := req.Header.Get("Authorization")
+	if token == "" {
+		return authz.Authorization{Approved: false, TokenValid: true, Reason: "no token"}, nil
+	}
+	return authz.Authorization{Approved: true, TokenValid: true, Principal: token, Exec: token == "alice"}, nil
+}
+
+type AuthorizeAuditTestSuite struct {
+	suite.Suite
+	router   *echo.Echo
+	recorder *audit.Recorder
+}
+
+func TestAuthorizeAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizeAuditTestSuite))
+}
+
+func (s *AuthorizeAuditTestSuite) SetupTest() {
+	s.recorder = audit.NewRecorder(audit.RecorderParams{Store: audit.NewInMemoryStore()})
+	s.router = echo.New()
+	s.router.Use(echomiddelware.RequestID(), Authorize(fakeAuthorizer{}, s.recorder))
+	s.router.GET("/jobs/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	s.router.GET("/api/v1/orchestrator/jobs/:id/exec", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	s.router.PUT("/jobs", func(c echo.Context) error {
+		c.Response().Header().Set(apimodels.HTTPHeaderJobID, "j-1")
+		return c.NoContent(http.StatusOK)
+	})
+	s.router.DELETE("/jobs/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "job not found")
+	})
+	s.router.PUT("/nodes/:id", func(c echo.Context) error {
+		// actions of the handler are recorded for the request
+		request, ok := audit.RequestFromContext(c.Request().Context())
+		s.Require().True(ok)
+		s.Equal("alice", request.Principal)
+		s.NotEmpty(request.ID)
+		s.recorder.Record(c.Request().Context(), models.AuditEvent{
+			Action:  models.AuditActionNodeApprove,
+			Target:  c.Param("id"),
+			Outcome: models.AuditOutcomeSuccess,
+		})
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+func (s *AuthorizeAuditTestSuite) serve(method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *AuthorizeAuditTestSuite) events() []models.AuditEvent {
+	events, err := s.recorder.Query(context.Background(), audit.Query{})
+	s.Require().NoError(err)
+	return events
+}
+
+func (s *AuthorizeAuditTestSuite) TestSafeMethodsAreNotAudited() {
+	s.Equal(http.StatusOK, s.serve(http.MethodGet, "/jobs/j-1", "alice").Code)
+	s.Empty(s.events())
+}
+
+func (s *AuthorizeAuditTestSuite) TestExecIsAudited() {
+	s.Equal(http.StatusOK, s.serve(http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec", "alice").Code)
+
+	events := s.events()
+	s.Require().Len(events, 1)
+	s.Equal("alice", events[0].Principal)
+	s.Equal("GET /api/v1/orchestrator/jobs/:id/exec", events[0].Action)
+	s.Equal("/api/v1/orchestrator/jobs/j-1/exec", events[0].Target)
+	s.Equal(models.AuditOutcomeSuccess, events[0].Outcome)
+}
+
+func (s *AuthorizeAuditTestSuite) TestExecRequiresExecPermission() {
+	// the request is approved, as it is made with a safe method, but not permitted to exec
+	s.Equal(http.StatusForbidden, s.serve(http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec", "bob").Code)
+
+	events := s.events()
+	s.Require().Len(events, 1)
+	s.Equal("bob", events[0].Principal)
+	s.Equal(models.AuditOutcomeDenied, events[0].Outcome)
+	s.Contains(events[0].Reason, "exec permission")
+}
+
+func (s *AuthorizeAuditTestSuite) TestSuccess() {
+	rec := s.serve(http.MethodPut, "/jobs", "alice")
+	s.Equal(http.StatusOK, rec.Code)
+
+	events := s.events()
+	s.Require().Len(events, 1)
+	s.Equal("alice", events[0].Principal)
+	s.Equal("PUT /jobs", events[0].Action)
+	s.Equal("j-1", events[0].Target)
+	s.Equal(rec.Header().Get(echo.HeaderXRequestID), events[0].RequestID)
+	s.Equal(models.AuditOutcomeSuccess, events[0].Outcome)
+	s.Equal(http.StatusOK, events[0].StatusCode)
+	s.NotZero(events[0].Time)
+}
+
+func (s *AuthorizeAuditTestSuite) TestDenied() {
+	s.Equal(http.StatusForbidden, s.serve(http.MethodDelete, "/jobs/j-1", "").Code)
+
+	events := s.events()
+	s.Require().Len(events, 1)
+	s.Equal(models.AuditPrincipalAnonymous, events[0].Principal)
+	s.Equal("DELETE /jobs/:id", events[0].Action)
+	s.Equal("/jobs/j-1", events[0].Target)
+	s.Equal(models.AuditOutcomeDenied, events[0].Outcome)
+	s.Equal(http.StatusForbidden, events[0].StatusCode)
+	s.Contains(events[0].Reason, "no token")
+}
+
+func (s *AuthorizeAuditTestSuite) TestFailure() {
+	s.Equal(http.StatusNotFound, s.serve(http.MethodDelete, "/jobs/j-1", "alice").Code)
+
+	events := s.events()
+	s.Require().Len(events, 1)
+	s.Equal(models.AuditOutcomeFailure, events[0].Outcome)
+	s.Equal(http.StatusNotFound, events[0].StatusCode)
+	s.Equal("job not found", events[0].Reason)
+}
+
+func (s *AuthorizeAuditTestSuite) TestActionsOfRequestShareItsID() {
+	s.Equal(http.StatusOK, s.serve(http.MethodPut, "/nodes/n-1", "alice").Code)
+
+	events := s.events()
+	s.Require().Len(events, 2)
+	s.Equal(models.AuditActionNodeApprove, events[0].Action)
+	s.Equal("n-1", events[0].Target)
+	s.Equal("PUT /nodes/:id", events[1].Action)
+	s.Equal(events[1].RequestID, events[0].RequestID)
+	s.Equal("alice", events[0].Principal)
+}
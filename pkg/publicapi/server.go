@@ -12,6 +12,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/audit"
 
 	"github.com/bacalhau-project/bacalhau/pkg/logger"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/middleware"
@@ -39,6 +40,8 @@ type ServerParams struct {
 	Config             Config
 	Authorizer         authz.Authorizer
 	Headers            map[string]string
+	// AuditRecorder records the requests with unsafe methods in the audit log. They are not audited if nil.
+	AuditRecorder *audit.Recorder
 }
 
 // Server configures a node's public REST API.
@@ -112,7 +115,13 @@ func NewAPIServer(params ServerParams) (*Server, error) {
 	server.Router.Use(
 		echomiddelware.CORS(),
 		echomiddelware.Recover(),
-		echomiddelware.RequestID(),
+		// the request ID is also set on the request, as the response headers set before
+		// the timeout middleware are not visible to the handlers and middlewares after it
+		echomiddelware.RequestIDWithConfig(echomiddelware.RequestIDConfig{
+			RequestIDHandler: func(c echo.Context, id string) {
+				c.Request().Header.Set(echo.HeaderXRequestID, id)
+			},
+		}),
 		echomiddelware.BodyLimit(server.config.MaxBytesToReadInBody),
 		echomiddelware.RateLimiter(
 			echomiddelware.NewRateLimiterMemoryStore(rate.Limit(
@@ -126,7 +135,7 @@ func NewAPIServer(params ServerParams) (*Server, error) {
 			}),
 
 		middleware.Otel(),
-		middleware.Authorize(params.Authorizer),
+		middleware.Authorize(params.Authorizer, params.AuditRecorder),
 		// sets headers on the server based on provided config
 		middleware.ServerHeader(params.Headers),
 		// logs request at appropriate error level based on status code
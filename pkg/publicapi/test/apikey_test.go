@@ -0,0 +1,139 @@
+//go:build unit || !integration
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/devstack"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/node"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+	"github.com/bacalhau-project/bacalhau/pkg/setup"
+	"github.com/bacalhau-project/bacalhau/pkg/test/teststack"
+)
+
+func (s *ServerSuite) TestAPIKeys() {
+	ctx := context.Background()
+	adminClient := tokenClient(s.T(), s.requesterNode, "admin", adminAccess)
+	createResponse, err := adminClient.APIKeys().Create(ctx, &apimodels.CreateAPIKeyRequest{
+		Name:        "ci",
+		Namespaces:  []string{"ci"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionWrite},
+	})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(createResponse.Key)
+	s.Empty(createResponse.APIKey.Hash)
+
+	// the name of a key is unique
+	_, err = adminClient.APIKeys().Create(ctx, &apimodels.CreateAPIKeyRequest{
+		Name:        "ci",
+		Namespaces:  []string{"ci"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead},
+	})
+	s.Require().Error(err)
+
+	// requests authenticated with the key are accepted, and its use is recorded
+	keyClient := client.NewAPI(&client.AuthenticatingClient{
+		Client:     client.NewHTTPClient(s.requesterNode.APIServer.GetURI().String()),
+		Credential: &apimodels.HTTPCredential{Scheme: "Bearer", Value: createResponse.Key},
+	})
+	_, err = keyClient.Jobs().List(ctx, &apimodels.ListJobsRequest{})
+	s.Require().NoError(err)
+
+	listResponse, err := s.client.APIKeys().List(ctx, &apimodels.ListAPIKeysRequest{})
+	s.Require().NoError(err)
+	s.Require().Len(listResponse.APIKeys, 1)
+	s.Equal("ci", listResponse.APIKeys[0].Name)
+	s.Empty(listResponse.APIKeys[0].Hash)
+	s.NotZero(listResponse.APIKeys[0].LastUsedTime)
+
+	// revoked keys are rejected
+	_, err = s.client.APIKeys().Revoke(ctx, &apimodels.RevokeAPIKeyRequest{Name: "ci"})
+	s.Require().NoError(err)
+	_, err = keyClient.Jobs().List(ctx, &apimodels.ListJobsRequest{})
+	s.Require().Error(err)
+
+	_, err = s.client.APIKeys().Revoke(ctx, &apimodels.RevokeAPIKeyRequest{Name: "ci"})
+	s.Require().Error(err)
+}
+
+func (s *ServerSuite) TestAPIKeyScopeLimitedToCaller() {
+	ctx := context.Background()
+	request := &apimodels.CreateAPIKeyRequest{
+		Name:        "scoped",
+		Namespaces:  []string{"team-a", "team-b"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionWrite},
+	}
+
+	// anonymous callers have no permissions to grant
+	_, err := s.client.APIKeys().Create(ctx, request)
+	s.requireStatus(http.StatusForbidden, err)
+
+	// nor can callers grant permissions in namespaces they have no access to
+	teamClient := tokenClient(s.T(), s.requesterNode, "team-a-owner", map[string]int{"team-a": 15})
+	_, err = teamClient.APIKeys().Create(ctx, request)
+	s.requireStatus(http.StatusForbidden, err)
+	s.ErrorContains(err, "team-b")
+
+	request.Namespaces = []string{"team-a"}
+	_, err = teamClient.APIKeys().Create(ctx, request)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_, _ = s.client.APIKeys().Revoke(ctx, &apimodels.RevokeAPIKeyRequest{Name: "scoped"})
+	})
+}
+
+func (s *ServerSuite) requireStatus(status int, err error) {
+	var responseErr client.UnexpectedResponseError
+	s.Require().ErrorAs(err, &responseErr)
+	s.Require().Equal(status, responseErr.StatusCode())
+}
+
+func TestAPIKeysRequireAdministrator(t *testing.T) {
+	logger.ConfigureTestLogging(t)
+	setup.SetupBacalhauRepoForTesting(t)
+	ctx := context.Background()
+
+	policyPath, err := filepath.Abs("../../authz/policies/policy_ns_anon.rego")
+	require.NoError(t, err)
+	stack := teststack.Setup(ctx, t,
+		devstack.WithNumberOfHybridNodes(1),
+		devstack.WithDependencyInjector(devstack.NewNoopNodeDependencyInjector()),
+		devstack.WithNodeOverrides(node.NodeConfig{
+			AuthConfig: types.AuthConfig{AccessPolicyPath: policyPath},
+		}),
+	)
+	n := stack.Nodes[0]
+	anonymousClient := client.New(n.APIServer.GetURI().String())
+	require.NoError(t, WaitForAlive(ctx, anonymousClient))
+
+	request := &apimodels.CreateAPIKeyRequest{
+		Name:        "ci",
+		Namespaces:  []string{"team-a"},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionRead},
+	}
+	for name, c := range map[string]client.API{
+		"anonymous":                   anonymousClient,
+		"owner of a single namespace": tokenClient(t, n, "team-a-owner", map[string]int{"team-a": 15}),
+	} {
+		_, err = c.APIKeys().Create(ctx, request)
+		var responseErr client.UnexpectedResponseError
+		require.ErrorAs(t, err, &responseErr, name)
+		require.Equal(t, http.StatusForbidden, responseErr.StatusCode(), name)
+
+		_, err = c.APIKeys().List(ctx, &apimodels.ListAPIKeysRequest{})
+		require.Error(t, err, name)
+	}
+
+	_, err = tokenClient(t, n, "admin", adminAccess).APIKeys().Create(ctx, request)
+	require.NoError(t, err)
+}
@@ -0,0 +1,100 @@
+//go:build unit || !integration
+
+package test
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
+)
+
+func (s *ServerSuite) TestAuditLog() {
+	ctx := context.Background()
+	createResponse, err := tokenClient(s.T(), s.requesterNode, "admin", adminAccess).APIKeys().Create(ctx, &apimodels.CreateAPIKeyRequest{
+		Name:        "auditor",
+		Namespaces:  []string{models.APIKeyAllNamespaces},
+		Permissions: []models.APIKeyPermission{models.APIKeyPermissionWrite},
+	})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_, _ = s.client.APIKeys().Revoke(ctx, &apimodels.RevokeAPIKeyRequest{Name: "auditor"})
+	})
+	keyClient := client.NewAPI(&client.AuthenticatingClient{
+		Client:     client.NewHTTPClient(s.requesterNode.APIServer.GetURI().String()),
+		Credential: &apimodels.HTTPCredential{Scheme: "Bearer", Value: createResponse.Key},
+	})
+
+	// the compute node is already approved, so approving it again fails
+	putResponse, err := keyClient.Nodes().Put(ctx, &apimodels.PutNodeRequest{
+		NodeID:  s.computeNode.ID,
+		Action:  string(apimodels.NodeActionApprove),
+		Message: "audit test",
+	})
+	s.Require().NoError(err)
+	s.Require().False(putResponse.Success)
+
+	// read-only requests are not audited
+	_, err = keyClient.Jobs().List(ctx, &apimodels.ListJobsRequest{})
+	s.Require().NoError(err)
+
+	listResponse, err := s.client.Audit().List(ctx, &apimodels.ListAuditEventsRequest{
+		Principal: "apikey:auditor",
+	})
+	s.Require().NoError(err)
+	s.Require().Len(listResponse.Events, 2)
+
+	nodeEvent, requestEvent := listResponse.Events[0], listResponse.Events[1]
+	s.Equal(models.AuditActionNodeApprove, nodeEvent.Action)
+	s.Equal(s.computeNode.ID, nodeEvent.Target)
+	s.Equal(models.AuditOutcomeFailure, nodeEvent.Outcome)
+	s.Equal("node already approved", nodeEvent.Reason)
+
+	s.Equal("PUT /api/v1/orchestrator/nodes/:id", requestEvent.Action)
+	s.Equal("/api/v1/orchestrator/nodes/"+s.computeNode.ID, requestEvent.Target)
+	s.Equal(models.AuditOutcomeSuccess, requestEvent.Outcome)
+	s.NotEmpty(requestEvent.RequestID)
+	s.Equal(requestEvent.RequestID, nodeEvent.RequestID)
+
+	// the key was created by the administrator
+	listResponse, err = s.client.Audit().List(ctx, &apimodels.ListAuditEventsRequest{
+		Action: "POST /api/v1/orchestrator/apikeys",
+		BaseListRequest: apimodels.BaseListRequest{
+			Limit: 1,
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(listResponse.Events, 1)
+	s.Equal("admin", listResponse.Events[0].Principal)
+}
+
+func (s *ServerSuite) TestAuditNodeCordon() {
+	ctx := context.Background()
+	operatorClient := tokenClient(s.T(), s.requesterNode, "operator", adminAccess)
+	for _, action := range []apimodels.NodeAction{
+		apimodels.NodeActionCordon, apimodels.NodeActionDrain, apimodels.NodeActionUncordon,
+	} {
+		putResponse, err := operatorClient.Nodes().Put(ctx, &apimodels.PutNodeRequest{
+			NodeID:  s.computeNode.ID,
+			Action:  string(action),
+			Message: "maintenance",
+		})
+		s.Require().NoError(err)
+		s.Require().True(putResponse.Success, putResponse.Error)
+	}
+
+	for _, action := range []string{
+		models.AuditActionNodeCordon, models.AuditActionNodeDrain, models.AuditActionNodeUncordon,
+	} {
+		listResponse, err := s.client.Audit().List(ctx, &apimodels.ListAuditEventsRequest{
+			Principal: "operator",
+			Action:    action,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(listResponse.Events, 1, action)
+		s.Equal(s.computeNode.ID, listResponse.Events[0].Target)
+		s.Equal(models.AuditOutcomeSuccess, listResponse.Events[0].Outcome)
+		s.Equal("maintenance", listResponse.Events[0].Reason)
+	}
+}
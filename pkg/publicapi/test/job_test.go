@@ -4,8 +4,13 @@ package test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/samber/lo"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 )
@@ -81,3 +86,95 @@ func (s *ServerSuite) TestJobOperations() {
 	_, err = s.client.Jobs().Stop(ctx, &apimodels.StopJobRequest{JobID: putResponse.JobID})
 	s.Require().Error(err)
 }
+
+func (s *ServerSuite) TestListJobsFilters() {
+	ctx := context.Background()
+	jobIDs := make([]string, 3)
+	for i := range jobIDs {
+		job := mock.Job()
+		job.Name = fmt.Sprintf("list-filters-%d", i)
+		putResponse, err := s.client.Jobs().Put(ctx, &apimodels.PutJobRequest{Job: job})
+		s.Require().NoError(err)
+		jobIDs[i] = putResponse.JobID
+	}
+
+	// page through the jobs using the next token
+	var listed []string
+	request := &apimodels.ListJobsRequest{
+		BaseListRequest: apimodels.BaseListRequest{Limit: 2},
+		NamePrefix:      "list-filters-",
+		EngineTypes:     []string{models.EngineNoop},
+		States:          lo.Map(models.JobStateTypes(), func(s models.JobStateType, _ int) string { return s.String() }),
+		Types:           []string{models.JobTypeBatch},
+		CreatedAfter:    time.Now().Add(-time.Hour).Unix(),
+	}
+	for {
+		listResponse, err := s.client.Jobs().List(ctx, request)
+		s.Require().NoError(err)
+		for _, j := range listResponse.Jobs {
+			listed = append(listed, j.ID)
+		}
+		if listResponse.NextToken == "" {
+			break
+		}
+		request.NextToken = listResponse.NextToken
+	}
+	s.Equal(jobIDs, listed)
+
+	// filters that match none of the jobs
+	listResponse, err := s.client.Jobs().List(ctx, &apimodels.ListJobsRequest{
+		NamePrefix: "list-filters-",
+		Types:      []string{models.JobTypeService},
+	})
+	s.Require().NoError(err)
+	s.Empty(listResponse.Jobs)
+
+	// unknown states are rejected, naming the state
+	_, err = s.client.Jobs().List(ctx, &apimodels.ListJobsRequest{States: []string{"running", "finished"}})
+	s.requireStatus(http.StatusBadRequest, err)
+	s.ErrorContains(err, "unknown job state")
+	s.ErrorContains(err, "finished")
+	s.NotContains(err.Error(), "running")
+}
+
+func (s *ServerSuite) TestDiscoverUnknownService() {
+	ctx := context.Background()
+	response, err := s.client.Services().Discover(ctx, &apimodels.DiscoverServiceRequest{Name: "unknown-service"})
+	s.Require().NoError(err)
+	s.Require().NotNil(response)
+	s.Empty(response.Endpoints)
+}
+
+func (s *ServerSuite) TestSecrets() {
+	ctx := context.Background()
+	secret := &models.Secret{Namespace: "secrets-test", Name: "api-key", Value: "s3cr3t"}
+	putResponse, err := s.client.Secrets().Put(ctx, &apimodels.PutSecretRequest{Secret: secret})
+	s.Require().NoError(err)
+	s.Equal("api-key", putResponse.Secret.Name)
+	s.Empty(putResponse.Secret.Value)
+	s.NotZero(putResponse.Secret.CreateTime)
+
+	listResponse, err := s.client.Secrets().List(ctx, &apimodels.ListSecretsRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			BaseGetRequest: apimodels.BaseGetRequest{
+				BaseRequest: apimodels.BaseRequest{Namespace: "secrets-test"},
+			},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(listResponse.Secrets, 1)
+	s.Equal("api-key", listResponse.Secrets[0].Name)
+	s.Empty(listResponse.Secrets[0].Value)
+
+	_, err = s.client.Secrets().Delete(ctx, &apimodels.DeleteSecretRequest{
+		SecretNamespace: "secrets-test",
+		SecretName:      "api-key",
+	})
+	s.Require().NoError(err)
+
+	_, err = s.client.Secrets().Delete(ctx, &apimodels.DeleteSecretRequest{
+		SecretNamespace: "secrets-test",
+		SecretName:      "api-key",
+	})
+	s.Require().Error(err)
+}
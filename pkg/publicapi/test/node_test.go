@@ -6,6 +6,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/labels"
@@ -36,3 +37,43 @@ func (s *ServerSuite) TestNodeListLabels() {
 	require.NotEmpty(s.T(), resp.Nodes)
 	require.Equal(s.T(), 1, len(resp.Nodes))
 }
+
+func (s *ServerSuite) TestNodeDrainGracePeriod() {
+	ctx := context.Background()
+	drain := func(gracePeriod *int64) time.Duration {
+		s.T().Cleanup(func() {
+			_, _ = s.client.Nodes().Put(ctx, &apimodels.PutNodeRequest{
+				NodeID: s.computeNode.ID,
+				Action: string(apimodels.NodeActionUncordon),
+			})
+		})
+		before := time.Now()
+		putResponse, err := s.client.Nodes().Put(ctx, &apimodels.PutNodeRequest{
+			NodeID:      s.computeNode.ID,
+			Action:      string(apimodels.NodeActionDrain),
+			GracePeriod: gracePeriod,
+		})
+		s.Require().NoError(err)
+		s.Require().True(putResponse.Success, putResponse.Error)
+
+		getResponse, err := s.client.Nodes().Get(ctx, &apimodels.GetNodeRequest{NodeID: s.computeNode.ID})
+		s.Require().NoError(err)
+		s.Require().NotNil(getResponse.Node.Cordon)
+		deadline := getResponse.Node.Cordon.GetDrainDeadline().Sub(before)
+
+		putResponse, err = s.client.Nodes().Put(ctx, &apimodels.PutNodeRequest{
+			NodeID: s.computeNode.ID,
+			Action: string(apimodels.NodeActionUncordon),
+		})
+		s.Require().NoError(err)
+		s.Require().True(putResponse.Success, putResponse.Error)
+		return deadline
+	}
+
+	// requests without a grace period use the one configured on the requester
+	s.InDelta(5*time.Minute, drain(nil), float64(10*time.Second))
+
+	// an explicit grace period of zero stops executions immediately
+	zero := int64(0)
+	s.InDelta(0, drain(&zero), float64(10*time.Second))
+}
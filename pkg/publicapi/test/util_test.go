@@ -7,6 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
 	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
 	"github.com/libp2p/go-libp2p/core/host"
@@ -21,6 +24,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/libp2p"
 	"github.com/bacalhau-project/bacalhau/pkg/node"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
 	"github.com/bacalhau-project/bacalhau/pkg/setup"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
@@ -114,3 +118,27 @@ func setupNodeForTestWithConfig(t *testing.T, apiCfg publicapi.Config) (*node.No
 	require.NoError(t, WaitForNodes(ctx, apiClient))
 	return n, apiClient
 }
+
+// adminAccess is the access to all namespaces of administrators
+var adminAccess = map[string]int{"*": 15}
+
+// tokenClient returns a client authenticated with an access token of the subject,
+// signed by the node and granting the namespace permissions.
+func tokenClient(t *testing.T, n *node.Node, subject string, namespaces map[string]int) client.API {
+	signingKey, err := config.GetClientPrivateKey()
+	require.NoError(t, err)
+
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.SubjectKey, subject))
+	require.NoError(t, token.Set(jwt.IssuerKey, n.ID))
+	require.NoError(t, token.Set(jwt.AudienceKey, []string{n.ID}))
+	require.NoError(t, token.Set(jwt.ExpirationKey, time.Now().Add(time.Hour)))
+	require.NoError(t, token.Set("ns", namespaces))
+	signed, err := jwt.Sign(token, jwa.RS256, signingKey)
+	require.NoError(t, err)
+
+	return client.NewAPI(&client.AuthenticatingClient{
+		Client:     client.NewHTTPClient(n.APIServer.GetURI().String()),
+		Credential: &apimodels.HTTPCredential{Scheme: "Bearer", Value: string(signed)},
+	})
+}
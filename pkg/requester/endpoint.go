@@ -323,10 +323,99 @@ func (e *BaseEndpoint) OnCancelComplete(ctx context.Context, result compute.Canc
 		e.id, result.ExecutionID, result.SourcePeerID)
 }
 
+func (e *BaseEndpoint) OnCheckpoint(ctx context.Context, result compute.CheckpointResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received Checkpoint for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+	if result.Checkpoint == nil || result.Checkpoint.Source == nil {
+		log.Ctx(ctx).Error().Msgf("[OnCheckpoint] execution %s sent an empty checkpoint", result.ExecutionID)
+		return
+	}
+
+	// record the checkpoint so that the execution can be resumed from it if it is rescheduled
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{
+				models.ExecutionStateBidAccepted,
+			},
+		},
+		NewValues: models.Execution{
+			Checkpoint: result.Checkpoint,
+		},
+		Event: orchestrator.ExecCheckpointedEvent(result.Checkpoint),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnCheckpoint] failed to update execution")
+	}
+}
+
+func (e *BaseEndpoint) OnHealthCheck(ctx context.Context, result compute.HealthCheckResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received HealthCheck for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+	if result.Health == nil {
+		log.Ctx(ctx).Error().Msgf("[OnHealthCheck] execution %s sent an empty health", result.ExecutionID)
+		return
+	}
+
+	// record the health of the execution so that the scheduler replaces it if it is unhealthy
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{
+				models.ExecutionStateBidAccepted,
+			},
+		},
+		NewValues: models.Execution{
+			Health: result.Health,
+		},
+		Event: orchestrator.ExecHealthChangedEvent(result.Health),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnHealthCheck] failed to update execution")
+		return
+	}
+
+	if result.Health.IsUnhealthy() {
+		e.enqueueEvaluation(ctx, result.JobID, "OnHealthCheck")
+	}
+}
+
+func (e *BaseEndpoint) OnPortsPublished(ctx context.Context, result compute.PortsResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received PortsPublished for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+
+	// record the ports of the execution so that they can be discovered
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{
+				models.ExecutionStateBidAccepted,
+			},
+		},
+		NewValues: models.Execution{
+			Ports: result.Ports,
+		},
+		Event: orchestrator.ExecPortsPublishedEvent(result.Ports),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnPortsPublished] failed to update execution")
+	}
+}
+
 func (e *BaseEndpoint) OnComputeFailure(ctx context.Context, result compute.ComputeError) {
 	log.Ctx(ctx).Debug().Err(result).Msgf("Requester node %s received ComputeFailure for execution: %s from %s",
 		e.id, result.ExecutionID, result.SourcePeerID)
 
+	// executions preempted by higher priority ones are flagged so that the scheduler
+	// reschedules them without counting them as failed attempts
+	desiredState := models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution failed")
+	var stopReason models.ExecutionStopReason
+	if result.Event.Details[models.DetailsKeyPreempted] == "true" {
+		preempted := orchestrator.ExecPreemptedEvent()
+		desiredState = desiredState.WithMessage(preempted.Message)
+		stopReason = preempted.StopReason()
+	}
+
 	// update execution state
 	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
 		ExecutionID: result.ExecutionID,
@@ -338,7 +427,8 @@ func (e *BaseEndpoint) OnComputeFailure(ctx context.Context, result compute.Comp
 		},
 		NewValues: models.Execution{
 			ComputeState: models.NewExecutionState(models.ExecutionStateFailed).WithMessage(result.Error()),
-			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution failed"),
+			DesiredState: desiredState,
+			StopReason:   stopReason,
 		},
 		Event: result.Event,
 	})
@@ -0,0 +1,81 @@
+//go:build integration || !unit
+
+package compute
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/secrets"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store/resolver"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	noop_publisher "github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+const testSecretValue = "s3cr3t-t0k3n"
+
+type SecretsSuite struct {
+	ComputeSuite
+	published chan string
+}
+
+func TestSecretsSuite(t *testing.T) {
+	suite.Run(t, new(SecretsSuite))
+}
+
+func (s *SecretsSuite) SetupTest() {
+	s.setupConfig()
+	s.setupNode()
+
+	// the job echoes the secret, and the published stdout is captured
+	s.published = make(chan string, 1)
+	s.executor.Config.ExternalHooks.JobHandler = func(
+		ctx context.Context, jobID string, resultsDir string) (*models.RunCommandResult, error) {
+		output := "token is " + testSecretValue + "\n"
+		return executor.WriteJobResults(resultsDir,
+			strings.NewReader(output), strings.NewReader(output), 0, nil, executor.OutputLimits{
+				MaxStdoutFileLength:   1024,
+				MaxStdoutReturnLength: 1024,
+				MaxStderrFileLength:   1024,
+				MaxStderrReturnLength: 1024,
+			}), nil
+	}
+	*s.publisher = *noop_publisher.NewNoopPublisherWithConfig(noop_publisher.PublisherConfig{
+		ExternalHooks: noop_publisher.PublisherExternalHooks{
+			PublishResult: func(ctx context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+				stdout, err := os.ReadFile(filepath.Join(resultPath, models.DownloadFilenameStdout))
+				s.published <- string(stdout)
+				return models.SpecConfig{}, err
+			},
+		},
+	})
+}
+
+func (s *SecretsSuite) TestPublishedStdoutIsRedacted() {
+	ctx := context.Background()
+	executionID := s.prepareAndAskForBid(ctx, mock.Execution())
+
+	_, err := s.node.LocalEndpoint.BidAccepted(ctx, compute.BidAcceptedRequest{
+		ExecutionID: executionID,
+		Secrets:     compute.SecretValues{"TOKEN": testSecretValue},
+	})
+	s.Require().NoError(err)
+
+	result := <-s.completedChannel
+	stdout := <-s.published
+	s.NotContains(stdout, testSecretValue)
+	s.Contains(stdout, "token is "+secrets.Redacted)
+	s.NotContains(result.RunCommandResult.STDOUT, testSecretValue)
+
+	err = s.stateResolver.Wait(ctx, executionID, resolver.CheckForState(store.ExecutionStateCompleted))
+	s.NoError(err)
+}
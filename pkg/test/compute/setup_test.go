@@ -120,6 +120,7 @@ func (s *ComputeSuite) setupNode() {
 		nil,                 // until we switch to testing with NATS
 		map[string]string{}, // empty configured labels
 		nil,                 // no heartbeat client
+		nil,                 // no node credentials
 	)
 	s.NoError(err)
 	s.stateResolver = *resolver.NewStateResolver(resolver.StateResolverParams{
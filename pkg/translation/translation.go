@@ -107,7 +107,7 @@ func ShouldTranslate(ctx context.Context, provider TranslatorProvider, tasks []*
 		kind := tasks[i].Engine.Type
 		if provider.Has(ctx, kind) {
 			needTranslationCount += 1
-		} else if kind == models.EngineDocker || kind == models.EngineWasm || kind == models.EngineNoop {
+		} else if models.IsDefaultEngineType(kind) {
 			continue
 		} else {
 			errs = errors.Join(errs, fmt.Errorf("unknown task type identified in translation: '%s'", kind))
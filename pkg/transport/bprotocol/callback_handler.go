@@ -37,6 +37,9 @@ func NewCallbackHandler(params CallbackHandlerParams) *CallbackHandler {
 	host.SetStreamHandler(OnRunComplete, handleCallback(host, handler.callback.OnRunComplete))
 	host.SetStreamHandler(OnCancelComplete, handleCallback(host, handler.callback.OnCancelComplete))
 	host.SetStreamHandler(OnComputeFailure, handleCallback(host, handler.callback.OnComputeFailure))
+	host.SetStreamHandler(OnCheckpoint, handleCallback(host, handler.callback.OnCheckpoint))
+	host.SetStreamHandler(OnHealthCheck, handleCallback(host, handler.callback.OnHealthCheck))
+	host.SetStreamHandler(OnPortsPublished, handleCallback(host, handler.callback.OnPortsPublished))
 	return handler
 }
 
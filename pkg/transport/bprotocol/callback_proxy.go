@@ -64,6 +64,24 @@ func (p *CallbackProxy) OnComputeFailure(ctx context.Context, result compute.Com
 	})
 }
 
+func (p *CallbackProxy) OnCheckpoint(ctx context.Context, result compute.CheckpointResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnCheckpoint, result, func(ctx2 context.Context) {
+		p.localCallback.OnCheckpoint(ctx2, result)
+	})
+}
+
+func (p *CallbackProxy) OnHealthCheck(ctx context.Context, result compute.HealthCheckResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnHealthCheck, result, func(ctx2 context.Context) {
+		p.localCallback.OnHealthCheck(ctx2, result)
+	})
+}
+
+func (p *CallbackProxy) OnPortsPublished(ctx context.Context, result compute.PortsResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnPortsPublished, result, func(ctx2 context.Context) {
+		p.localCallback.OnPortsPublished(ctx2, result)
+	})
+}
+
 func proxyCallbackRequest(
 	ctx context.Context,
 	p *CallbackProxy,
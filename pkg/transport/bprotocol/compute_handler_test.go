@@ -63,6 +63,10 @@ func (t *TestEndpoint) ExecutionLogs(ctx context.Context, request compute.Execut
 	<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	return nil, errors.New("No test implementation")
 }
+func (t *TestEndpoint) Exec(ctx context.Context, request compute.ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	return nil, errors.New("No test implementation")
+}
 
 func (s *ComputeProxyTestSuite) TeardownSuite() {
 	s.proxy.host.Close()
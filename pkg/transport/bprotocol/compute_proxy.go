@@ -96,6 +96,16 @@ func (p *ComputeProxy) ExecutionLogs(ctx context.Context, request compute.Execut
 		ctx, p.host, request.TargetPeerID, ExecutionLogsID, request)
 }
 
+// Exec is only supported with local compute endpoints, as the libp2p transport does
+// not support streaming the input of the command to remote compute nodes.
+func (p *ComputeProxy) Exec(ctx context.Context, request compute.ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if request.TargetPeerID == p.host.ID().String() && p.localEndpoint != nil {
+		return p.localEndpoint.Exec(ctx, request)
+	}
+	return nil, fmt.Errorf("exec is not supported by the libp2p transport, use the NATS transport instead")
+}
+
 func proxyRequest[Request any, Response any](
 	ctx context.Context,
 	h host.Host,
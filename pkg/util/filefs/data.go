@@ -0,0 +1,55 @@
+package filefs
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+)
+
+// dataFileMode is the mode of files held in memory, which are read-only
+const dataFileMode = 0444
+
+type dataFs struct {
+	name string
+	data []byte
+}
+
+// NewFromData returns a filesystem made of a single read-only file held in memory, like
+// New does for a file on disk.
+func NewFromData(name string, data []byte) fs.FS {
+	return dataFs{name: path.Base(name), data: data}
+}
+
+// Open implements fs.FS
+func (f dataFs) Open(name string) (fs.File, error) {
+	if name != "." {
+		return nil, os.ErrNotExist
+	}
+	return &dataFile{
+		Reader: bytes.NewReader(f.data),
+		info:   dataFileInfo{name: f.name, size: int64(len(f.data))},
+	}, nil
+}
+
+type dataFile struct {
+	*bytes.Reader
+	info dataFileInfo
+}
+
+func (f *dataFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *dataFile) Close() error { return nil }
+
+type dataFileInfo struct {
+	name string
+	size int64
+}
+
+func (i dataFileInfo) Name() string       { return i.name }
+func (i dataFileInfo) Size() int64        { return i.size }
+func (i dataFileInfo) Mode() fs.FileMode  { return dataFileMode }
+func (i dataFileInfo) ModTime() time.Time { return time.Time{} }
+func (i dataFileInfo) IsDir() bool        { return false }
+func (i dataFileInfo) Sys() any           { return nil }
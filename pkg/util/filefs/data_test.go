@@ -0,0 +1,35 @@
+//go:build unit || !integration
+
+package filefs
+
+import (
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/util/mountfs"
+)
+
+func TestNewFromData(t *testing.T) {
+	root := mountfs.New()
+	require.NoError(t, root.Mount("/run/secrets/token", NewFromData("/run/secrets/token", []byte("s3cr3t"))))
+
+	file, err := root.Open("/run/secrets/token")
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck
+
+	info, err := file.Stat()
+	require.NoError(t, err)
+	require.Equal(t, "token", info.Name())
+	require.Equal(t, int64(6), info.Size())
+	require.Equal(t, fs.FileMode(dataFileMode), info.Mode())
+
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", string(content))
+
+	_, err = root.Open("/run/secrets/other")
+	require.Error(t, err)
+}
@@ -133,6 +133,12 @@ func (b *Broadcaster[T]) safeChannelSend(ch chan T, value T) (isClosed bool) {
 }
 
 func (b *Broadcaster[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
 	b.closed = true
 	for ch := range b.clients {
 		close(ch)
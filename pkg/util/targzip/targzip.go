@@ -33,6 +33,12 @@ func Decompress(src io.Reader, dst string) error {
 	return decompress(src, dst, MaximumContextSize)
 }
 
+// DecompressWithMaxBytes decompresses the archive like Decompress, but with the given
+// maximum size for each file instead of MaximumContextSize.
+func DecompressWithMaxBytes(src io.Reader, dst string, max datasize.ByteSize) error {
+	return decompress(src, dst, max)
+}
+
 func UncompressedSize(src io.Reader) (datasize.ByteSize, error) {
 	var size datasize.ByteSize
 	zr, err := gzip.NewReader(src)